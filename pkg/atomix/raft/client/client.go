@@ -17,7 +17,6 @@ package client
 import (
 	"container/list"
 	"context"
-	"errors"
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
 	"github.com/atomix/go-framework/pkg/atomix/node"
 	streams "github.com/atomix/go-framework/pkg/atomix/stream"
@@ -25,9 +24,45 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"math/rand"
 	"sync"
+	"time"
 )
 
+const (
+	// minRetryDelay is the delay before the first retry of a request
+	minRetryDelay = 10 * time.Millisecond
+	// maxRetryDelay is the maximum delay between retries of a request
+	maxRetryDelay = time.Second
+)
+
+// retryDelay returns the delay before the given retry of a request
+// The delay doubles with each retry up to maxRetryDelay so a request doesn't spin while the cluster is unavailable,
+// and is jittered so requests failing at the same time are not retried in lockstep.
+func retryDelay(retry int) time.Duration {
+	delay := minRetryDelay
+	for i := 0; i < retry && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// awaitRetry waits for the delay before the given retry of a request
+// It returns a bool indicating whether the request should be retried, which is false if the context is done first.
+func awaitRetry(ctx context.Context, retry int) bool {
+	timer := time.NewTimer(retryDelay(retry))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // NewClient returns a new Raft client
 func NewClient(config cluster.Cluster, consistency raft.ReadConsistency) *Client {
 	cluster := raft.NewCluster(config)
@@ -48,7 +83,34 @@ func newClient(cluster raft.Cluster, client raft.Client, consistency raft.ReadCo
 	}
 }
 
+// Zones maps members of the cluster to the zones in which they're located
+type Zones map[raft.MemberID]string
+
+// PreferZone configures the client to prefer members in the given zone when selecting a member to read from
+// Members located in the zone are tried first, and if no member in the zone can be reached, reads fall back to
+// members in other zones. Members that are not in the zones map are treated as being in another zone.
+func (c *Client) PreferZone(zone string, zones Zones) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	local := list.New()
+	remote := list.New()
+	for element := c.members.Front(); element != nil; element = element.Next() {
+		member := element.Value.(raft.MemberID)
+		if zones[member] == zone {
+			local.PushBack(member)
+		} else {
+			remote.PushBack(member)
+		}
+	}
+	local.PushBackList(remote)
+	c.members = local
+	c.memberNode = nil
+	c.member = nil
+}
+
 // Client is a service Client implementation for the Raft consensus protocol
+// Requests that fail because the target member is unreachable are retried on other members of the cluster
+// until the request's context is done.
 type Client struct {
 	node.Client
 	members     *list.List
@@ -57,10 +119,16 @@ type Client struct {
 	leader      *raft.MemberID
 	client      raft.Client
 	consistency raft.ReadConsistency
+	lastIndex   raft.Index
 	mu          sync.RWMutex
 	log         util.Logger
 }
 
+// ReadConsistency returns the consistency of reads sent by the client
+func (c *Client) ReadConsistency() raft.ReadConsistency {
+	return c.consistency
+}
+
 // MustLeader returns whether requests must be handled by a leader
 func (c *Client) MustLeader() bool {
 	return false
@@ -92,11 +160,37 @@ func (c *Client) Write(ctx context.Context, in []byte, stream streams.WriteStrea
 	return <-errCh
 }
 
+// LastIndex returns the highest index at which a write sent by the client was committed
+// Reading at the last index with ReadAtIndex observes the client's own writes from any member.
+func (c *Client) LastIndex() raft.Index {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastIndex
+}
+
+// updateLastIndex records the index at which a write sent by the client was committed
+func (c *Client) updateLastIndex(index raft.Index) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if index > c.lastIndex {
+		c.lastIndex = index
+	}
+}
+
 // Read sends a read operation to the cluster
 func (c *Client) Read(ctx context.Context, in []byte, stream streams.WriteStream) error {
+	return c.ReadAtIndex(ctx, in, 0, 0, stream)
+}
+
+// ReadAtIndex sends a read operation to the cluster that must be served by a member that has applied the given index
+// A member that has not applied the index waits up to the given timeout for it before rejecting the read, and
+// rejected reads are retried on other members until the context is done. A minimum index of 0 reads from any member.
+func (c *Client) ReadAtIndex(ctx context.Context, in []byte, minIndex raft.Index, timeout time.Duration, stream streams.WriteStream) error {
 	request := &raft.QueryRequest{
 		Value:           in,
 		ReadConsistency: c.consistency,
+		MinIndex:        minIndex,
+		MinIndexTimeout: timeout,
 	}
 
 	errCh := make(chan error)
@@ -125,6 +219,8 @@ func (c *Client) getLeader() raft.MemberID {
 }
 
 // resetLeader resets the leader
+// If the leader is reset because the expected member failed, the member is also reset to ensure the next
+// request fails over to another member.
 func (c *Client) resetLeader(expected raft.MemberID, leader *raft.MemberID) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -133,6 +229,9 @@ func (c *Client) resetLeader(expected raft.MemberID, leader *raft.MemberID) bool
 		return true
 	} else if c.leader != nil && leader == nil {
 		c.leader = nil
+		if c.member != nil && *c.member == expected {
+			c.member = nil
+		}
 		return true
 	} else if c.leader != nil && leader != nil && *c.leader != *leader {
 		c.leader = leader
@@ -148,18 +247,23 @@ func (c *Client) resetLeader(expected raft.MemberID, leader *raft.MemberID) bool
 
 // write sends the given write request to the cluster
 func (c *Client) write(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream) error {
-	go c.sendWrite(ctx, request, stream)
+	go c.sendWrite(ctx, request, stream, 0)
 	return nil
 }
 
-// retryWrite retries a write request
-func (c *Client) retryWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream, leader raft.MemberID) {
+// retryWrite retries a write request after backing off for the given number of retries
+func (c *Client) retryWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream, leader raft.MemberID, retries int) {
 	c.resetLeader(leader, nil)
-	go c.sendWrite(ctx, request, stream)
+	if !awaitRetry(ctx, retries) {
+		stream.Error(raft.NewRPCError(ctx.Err()))
+		stream.Close()
+		return
+	}
+	go c.sendWrite(ctx, request, stream, retries+1)
 }
 
-// sendWrite sends a write request
-func (c *Client) sendWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream) {
+// sendWrite sends a write request that has been retried the given number of times
+func (c *Client) sendWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream, retries int) {
 	leader := c.getLeader()
 	c.log.Trace("Sending CommandRequest %+v to %s", request, leader)
 	ch, err := c.client.Command(ctx, request, leader)
@@ -167,31 +271,31 @@ func (c *Client) sendWrite(ctx context.Context, request *raft.CommandRequest, st
 		c.log.Trace("Received CommandRequest error %s from %s", err, leader)
 		if e, ok := status.FromError(err); ok {
 			if e.Code() == codes.Unavailable {
-				c.retryWrite(ctx, request, stream, leader)
+				c.retryWrite(ctx, request, stream, leader, retries)
 				return
 			}
 		}
-		stream.Error(err)
+		stream.Error(raft.NewRPCError(err))
 		stream.Close()
 	} else {
-		c.receiveWrite(ctx, request, stream, leader, ch)
+		c.receiveWrite(ctx, request, stream, leader, ch, retries)
 	}
 }
 
 // receiveWrite process write responses
-func (c *Client) receiveWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream, leader raft.MemberID, ch <-chan *raft.CommandStreamResponse) {
+func (c *Client) receiveWrite(ctx context.Context, request *raft.CommandRequest, stream streams.WriteStream, leader raft.MemberID, ch <-chan *raft.CommandStreamResponse, retries int) {
 	for streamResponse := range ch {
 		if streamResponse.Failed() {
 			c.log.Trace("Received CommandResponse error %s from %s", streamResponse.Error, leader)
 			c.resetLeader(leader, nil)
 			if e, ok := status.FromError(streamResponse.Error); ok {
 				if e.Code() == codes.Unavailable {
-					c.retryWrite(ctx, request, stream, leader)
+					c.retryWrite(ctx, request, stream, leader, retries)
 					return
 				}
 			}
 
-			stream.Error(streamResponse.Error)
+			stream.Error(raft.NewRPCError(streamResponse.Error))
 			stream.Close()
 			return
 		}
@@ -199,32 +303,35 @@ func (c *Client) receiveWrite(ctx context.Context, request *raft.CommandRequest,
 		response := streamResponse.Response
 		c.log.Trace("Received CommandResponse %+v from %s", response, leader)
 		if response.Status == raft.ResponseStatus_OK {
+			c.updateLastIndex(response.Index)
 			stream.Value(response.Output)
 		} else if response.Error == raft.ResponseError_ILLEGAL_MEMBER_STATE {
 			// If possible, update the current leader
 			if leader == response.Leader {
-				c.retryWrite(ctx, request, stream, leader)
+				c.retryWrite(ctx, request, stream, leader, retries)
 			} else if response.Leader != "" && c.resetLeader(leader, &response.Leader) {
-				c.sendWrite(ctx, request, stream)
+				c.sendWrite(ctx, request, stream, retries)
 			} else if response.Leader == "" && c.resetLeader(leader, nil) {
-				c.sendWrite(ctx, request, stream)
+				c.sendWrite(ctx, request, stream, retries)
 			} else {
-				stream.Error(errors.New(response.Message))
+				stream.Error(raft.NewCommandError(response))
 				stream.Close()
 			}
 			return
 		} else {
-			stream.Error(errors.New(response.Message))
+			stream.Error(raft.NewCommandError(response))
 		}
 	}
 	stream.Close()
 }
 
-// resetMember resets the member connection
-func (c *Client) resetMember() {
+// resetMember resets the member connection if the current member is the expected member
+func (c *Client) resetMember(expected raft.MemberID) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.member = nil
+	if c.member != nil && *c.member == expected {
+		c.member = nil
+	}
 }
 
 // getMember gets the current member
@@ -256,18 +363,23 @@ func (c *Client) getMemberSafe() raft.MemberID {
 
 // read sends the given read request to the cluster
 func (c *Client) read(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream) error {
-	go c.sendRead(ctx, request, stream)
+	go c.sendRead(ctx, request, stream, 0)
 	return nil
 }
 
-// retryRead retries a read request
-func (c *Client) retryRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream) {
-	c.resetMember()
-	go c.sendRead(ctx, request, stream)
+// retryRead retries a read request on another member after backing off for the given number of retries
+func (c *Client) retryRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream, member raft.MemberID, retries int) {
+	c.resetMember(member)
+	if !awaitRetry(ctx, retries) {
+		stream.Error(raft.NewRPCError(ctx.Err()))
+		stream.Close()
+		return
+	}
+	go c.sendRead(ctx, request, stream, retries+1)
 }
 
-// sendRead sends a read request
-func (c *Client) sendRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream) {
+// sendRead sends a read request that has been retried the given number of times
+func (c *Client) sendRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream, retries int) {
 	member := c.getMember()
 	c.log.Trace("Sending QueryRequest %+v to %s", request, member)
 	ch, err := c.client.Query(ctx, request, member)
@@ -275,29 +387,29 @@ func (c *Client) sendRead(ctx context.Context, request *raft.QueryRequest, strea
 		c.log.Trace("Received QueryRequest error %s from %s", err, member)
 		if e, ok := status.FromError(err); ok {
 			if e.Code() == codes.Unavailable {
-				c.retryRead(ctx, request, stream)
+				c.retryRead(ctx, request, stream, member, retries)
 				return
 			}
 		}
-		stream.Error(err)
+		stream.Error(raft.NewRPCError(err))
 		stream.Close()
 	} else {
-		c.receiveRead(ctx, request, stream, member, ch)
+		c.receiveRead(ctx, request, stream, member, ch, retries)
 	}
 }
 
-func (c *Client) receiveRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream, member raft.MemberID, ch <-chan *raft.QueryStreamResponse) {
+func (c *Client) receiveRead(ctx context.Context, request *raft.QueryRequest, stream streams.WriteStream, member raft.MemberID, ch <-chan *raft.QueryStreamResponse, retries int) {
 	for streamResponse := range ch {
 		if streamResponse.Failed() {
 			c.log.Trace("Received QueryResponse error %s from %s", streamResponse.Error, member)
 			if e, ok := status.FromError(streamResponse.Error); ok {
 				if e.Code() == codes.Unavailable {
-					c.retryRead(ctx, request, stream)
+					c.retryRead(ctx, request, stream, member, retries)
 					return
 				}
 			}
 
-			stream.Error(streamResponse.Error)
+			stream.Error(raft.NewRPCError(streamResponse.Error))
 			stream.Close()
 			return
 		}
@@ -307,11 +419,15 @@ func (c *Client) receiveRead(ctx context.Context, request *raft.QueryRequest, st
 		if response.Status == raft.ResponseStatus_OK {
 			stream.Value(response.Output)
 		} else if response.Error == raft.ResponseError_ILLEGAL_MEMBER_STATE {
-			c.resetMember()
-			c.sendRead(ctx, request, stream)
+			c.resetMember(member)
+			c.sendRead(ctx, request, stream, retries)
+			return
+		} else if response.Error == raft.ResponseError_UNAVAILABLE && request.MinIndex > 0 {
+			// The member has not reached the read's minimum index, so try another member.
+			c.retryRead(ctx, request, stream, member, retries)
 			return
 		} else {
-			stream.Error(errors.New(response.Message))
+			stream.Error(raft.NewQueryError(response))
 		}
 	}
 	stream.Close()
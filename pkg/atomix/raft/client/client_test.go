@@ -17,12 +17,17 @@ package client
 import (
 	"context"
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
-	"github.com/atomix/go-framework/pkg/atomix/node"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func newTestClient(client raft.Client) *Client {
@@ -30,19 +35,19 @@ func newTestClient(client raft.Client) *Client {
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5000,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5000,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "bar",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 			"baz": {
-				ID:   "baz",
-				Host: "localhost",
-				Port: 5002,
+				ID:           "baz",
+				Host:         "localhost",
+				ProtocolPort: 5002,
 			},
 		},
 	}
@@ -122,17 +127,257 @@ func TestClient(t *testing.T) {
 
 	client := newTestClient(protocol)
 
-	ch := make(chan node.Output)
-	assert.NoError(t, client.Write(context.Background(), []byte("Hello world!"), ch))
-	assert.Equal(t, "foo", string((<-ch).Value))
-	assert.Equal(t, "bar", string((<-ch).Value))
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	assert.Equal(t, "foo", string((<-ch).Value.([]byte)))
+	assert.Equal(t, "bar", string((<-ch).Value.([]byte)))
 	_, ok := <-ch
 	assert.False(t, ok)
 
-	ch = make(chan node.Output)
-	assert.NoError(t, client.Read(context.Background(), []byte("Hello world again!"), ch))
-	assert.Equal(t, "bar", string((<-ch).Value))
-	assert.Equal(t, "baz", string((<-ch).Value))
+	ch = make(chan streams.Result)
+	assert.NoError(t, client.Read(context.Background(), []byte("Hello world again!"), streams.NewChannelStream(ch)))
+	assert.Equal(t, "bar", string((<-ch).Value.([]byte)))
+	assert.Equal(t, "baz", string((<-ch).Value.([]byte)))
 	_, ok = <-ch
 	assert.False(t, ok)
 }
+
+// testCluster is a set of members to which requests can be sent by a mock client
+type testCluster struct {
+	leader raft.MemberID
+	killed map[raft.MemberID]bool
+	mu     sync.Mutex
+}
+
+func (c *testCluster) kill(member raft.MemberID, leader raft.MemberID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.killed[member] = true
+	c.leader = leader
+}
+
+func (c *testCluster) command(ctx context.Context, request *raft.CommandRequest, member raft.MemberID) (<-chan *raft.CommandStreamResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.killed[member] {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	if member != c.leader {
+		ch <- raft.NewCommandStreamResponse(&raft.CommandResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+			Leader: c.leader,
+		}, nil)
+	} else {
+		ch <- raft.NewCommandStreamResponse(&raft.CommandResponse{
+			Status: raft.ResponseStatus_OK,
+			Leader: c.leader,
+			Output: []byte(member),
+		}, nil)
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (c *testCluster) query(ctx context.Context, request *raft.QueryRequest, member raft.MemberID) (<-chan *raft.QueryStreamResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.killed[member] {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	ch := make(chan *raft.QueryStreamResponse, 1)
+	ch <- raft.NewQueryStreamResponse(&raft.QueryResponse{
+		Status: raft.ResponseStatus_OK,
+		Output: []byte(member),
+	}, nil)
+	close(ch)
+	return ch, nil
+}
+
+func awaitResult(t *testing.T, ch <-chan streams.Result) string {
+	result, ok := <-ch
+	assert.True(t, ok)
+	assert.NoError(t, result.Error)
+	_, ok = <-ch
+	assert.False(t, ok)
+	if result.Value == nil {
+		return ""
+	}
+	return string(result.Value.([]byte))
+}
+
+func TestClientFailover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol := mock.NewMockClient(ctrl)
+	cluster := &testCluster{
+		leader: raft.MemberID("bar"),
+		killed: make(map[raft.MemberID]bool),
+	}
+	protocol.EXPECT().Command(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(cluster.command).AnyTimes()
+	protocol.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(cluster.query).AnyTimes()
+
+	client := newTestClient(protocol)
+
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	assert.Equal(t, "bar", awaitResult(t, ch))
+
+	// Kill the leader and verify writes fail over to the new leader
+	cluster.kill(raft.MemberID("bar"), raft.MemberID("baz"))
+	ch = make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	assert.Equal(t, "baz", awaitResult(t, ch))
+
+	ch = make(chan streams.Result)
+	assert.NoError(t, client.Read(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	member := awaitResult(t, ch)
+	assert.NotEqual(t, "bar", member)
+
+	// Kill the member serving reads and verify reads fail over to another member
+	cluster.kill(raft.MemberID(member), raft.MemberID("baz"))
+	ch = make(chan streams.Result)
+	assert.NoError(t, client.Read(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	next := awaitResult(t, ch)
+	assert.NotEqual(t, "bar", next)
+	assert.NotEqual(t, member, next)
+}
+
+func TestClientRetryBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol := mock.NewMockClient(ctrl)
+
+	// Fail every request as if the whole cluster were unavailable
+	var commands, queries int32
+	protocol.EXPECT().
+		Command(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.CommandRequest, member raft.MemberID) (<-chan *raft.CommandStreamResponse, error) {
+			atomic.AddInt32(&commands, 1)
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		}).AnyTimes()
+	protocol.EXPECT().
+		Query(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.QueryRequest, member raft.MemberID) (<-chan *raft.QueryStreamResponse, error) {
+			atomic.AddInt32(&queries, 1)
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		}).AnyTimes()
+
+	client := newTestClient(protocol)
+
+	// Verify retries are backed off rather than spinning until the request's context expires
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(ctx, []byte("Hello world!"), streams.NewChannelStream(ch)))
+	result := <-ch
+	assert.Error(t, result.Error)
+	assert.True(t, atomic.LoadInt32(&commands) < 20)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	ch = make(chan streams.Result)
+	assert.NoError(t, client.Read(ctx, []byte("Hello world!"), streams.NewChannelStream(ch)))
+	result = <-ch
+	assert.Error(t, result.Error)
+	assert.True(t, atomic.LoadInt32(&queries) < 20)
+
+	// Verify the delay grows with each retry up to the maximum and is jittered
+	for retry := 0; retry < 20; retry++ {
+		delay := retryDelay(retry)
+		max := minRetryDelay << uint(retry)
+		if retry > 10 || max > maxRetryDelay {
+			max = maxRetryDelay
+		}
+		assert.True(t, delay >= max/2)
+		assert.True(t, delay < max)
+	}
+}
+
+func TestClientPreferZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol := mock.NewMockClient(ctrl)
+	cluster := &testCluster{
+		leader: raft.MemberID("foo"),
+		killed: make(map[raft.MemberID]bool),
+	}
+	protocol.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(cluster.query).AnyTimes()
+
+	client := newTestClient(protocol)
+	client.PreferZone("us-east-1b", Zones{
+		raft.MemberID("foo"): "us-east-1a",
+		raft.MemberID("bar"): "us-east-1c",
+		raft.MemberID("baz"): "us-east-1b",
+	})
+
+	// Verify sequential reads are routed to the member in the same zone
+	for i := 0; i < 3; i++ {
+		ch := make(chan streams.Result)
+		assert.NoError(t, client.Read(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+		assert.Equal(t, "baz", awaitResult(t, ch))
+	}
+
+	// Kill the same-zone member and verify reads fall back to another zone
+	cluster.kill(raft.MemberID("baz"), raft.MemberID("foo"))
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Read(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	assert.NotEqual(t, "baz", awaitResult(t, ch))
+}
+
+func TestClientReadAtIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol := mock.NewMockClient(ctrl)
+	protocol.EXPECT().
+		Command(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.CommandRequest, member raft.MemberID) (<-chan *raft.CommandStreamResponse, error) {
+			ch := make(chan *raft.CommandStreamResponse, 1)
+			ch <- raft.NewCommandStreamResponse(&raft.CommandResponse{
+				Status: raft.ResponseStatus_OK,
+				Leader: member,
+				Output: []byte(member),
+				Index:  raft.Index(5),
+			}, nil)
+			close(ch)
+			return ch, nil
+		}).AnyTimes()
+
+	// Only baz has applied the write; the other members reject reads requiring its index
+	applied := map[raft.MemberID]raft.Index{
+		raft.MemberID("foo"): raft.Index(4),
+		raft.MemberID("bar"): raft.Index(3),
+		raft.MemberID("baz"): raft.Index(5),
+	}
+	protocol.EXPECT().
+		Query(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.QueryRequest, member raft.MemberID) (<-chan *raft.QueryStreamResponse, error) {
+			ch := make(chan *raft.QueryStreamResponse, 1)
+			if request.MinIndex > applied[member] {
+				ch <- raft.NewQueryStreamResponse(&raft.QueryResponse{
+					Status: raft.ResponseStatus_ERROR,
+					Error:  raft.ResponseError_UNAVAILABLE,
+				}, nil)
+			} else {
+				ch <- raft.NewQueryStreamResponse(&raft.QueryResponse{
+					Status: raft.ResponseStatus_OK,
+					Output: []byte(member),
+				}, nil)
+			}
+			close(ch)
+			return ch, nil
+		}).AnyTimes()
+
+	client := newTestClient(protocol)
+	assert.Equal(t, raft.Index(0), client.LastIndex())
+
+	// Verify the client records the index at which its write was committed
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), []byte("Hello world!"), streams.NewChannelStream(ch)))
+	awaitResult(t, ch)
+	assert.Equal(t, raft.Index(5), client.LastIndex())
+
+	// Verify a read at the write's index is retried until a member that has applied the index serves it
+	for i := 0; i < 3; i++ {
+		ch = make(chan streams.Result)
+		assert.NoError(t, client.ReadAtIndex(context.Background(), []byte("Hello world!"), client.LastIndex(), 0, streams.NewChannelStream(ch)))
+		assert.Equal(t, "baz", awaitResult(t, ch))
+	}
+}
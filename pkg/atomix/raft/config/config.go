@@ -17,8 +17,36 @@ package config
 import "time"
 
 const (
-	defaultElectionTimeout   = 5 * time.Second
-	defaultHeartbeatInterval = 500 * time.Millisecond
+	defaultElectionTimeout         = 5 * time.Second
+	defaultHeartbeatInterval       = 500 * time.Millisecond
+	defaultSnapshotThreshold       = 0
+	defaultQuorumHealthInterval    = 10 * time.Second
+	defaultMinLeadershipDuration   = 0
+	defaultIdleNoopInterval        = 0
+	defaultFailureLogInterval      = 10 * time.Second
+	defaultLeaderWarmup            = 0
+	defaultStatusInterval          = time.Second
+	defaultMaxEntrySize            = 1024 * 1024
+	defaultInstallTimeoutFactor    = 10
+	defaultApplyParallelism        = 1
+	defaultMetadataSyncWindow      = 0
+	defaultMaxCommitBatchSize      = 1000
+	defaultMaxAppendQueueBytes     = 16 * 1024 * 1024
+	defaultMaxElectionWorkers      = 16
+	defaultSlowAppendThreshold     = 0
+	defaultRestoreBufferSize       = 1024 * 1024
+	defaultLastAppliedSyncInterval = time.Second
+	defaultCommitStallThreshold    = 0
+	defaultStartupTimeout          = 0
+	defaultFlapThreshold           = 0
+	defaultFlapWindow              = time.Minute
+	defaultFlapBackoff             = 30 * time.Second
+	defaultMaintenanceWindow       = 10 * time.Minute
+	defaultRateWindow              = 10 * time.Second
+	defaultSnapshotOnStopTimeout   = 10 * time.Second
+	defaultDiskCheckInterval       = time.Second
+	maxMetadataSyncWindow          = 10 * time.Millisecond
+	minAppendWorkers               = 2
 )
 
 // GetElectionTimeoutOrDefault returns the configured election timeout if set, otherwise the default election timeout
@@ -38,3 +66,292 @@ func (c *ProtocolConfig) GetHeartbeatIntervalOrDefault() time.Duration {
 	}
 	return defaultHeartbeatInterval
 }
+
+// GetSnapshotThresholdOrDefault returns the configured number of entries to apply between snapshots if set,
+// otherwise the default snapshot threshold. A threshold of 0 disables periodic snapshots.
+func (c *ProtocolConfig) GetSnapshotThresholdOrDefault() uint64 {
+	threshold := c.GetCompaction().GetSnapshotThreshold()
+	if threshold > 0 {
+		return threshold
+	}
+	return defaultSnapshotThreshold
+}
+
+// GetRestoreBufferSizeOrDefault returns the configured size of the buffer through which snapshots are read when
+// restoring the state machine if set, otherwise the default restore buffer size of 1MB
+func (c *ProtocolConfig) GetRestoreBufferSizeOrDefault() int {
+	size := c.GetCompaction().GetRestoreBufferSize()
+	if size > 0 {
+		return int(size)
+	}
+	return defaultRestoreBufferSize
+}
+
+// GetQuorumHealthIntervalOrDefault returns the configured interval at which the leader checks the health of the
+// quorum if set, otherwise the default quorum health interval
+func (c *ProtocolConfig) GetQuorumHealthIntervalOrDefault() time.Duration {
+	interval := c.GetQuorumHealthInterval()
+	if interval != nil {
+		return *interval
+	}
+	return defaultQuorumHealthInterval
+}
+
+// GetMinLeadershipDurationOrDefault returns the configured minimum duration of a leader's term if set, otherwise
+// the default minimum leadership duration. A duration of 0 disables the minimum leadership duration.
+func (c *ProtocolConfig) GetMinLeadershipDurationOrDefault() time.Duration {
+	duration := c.GetMinLeadershipDuration()
+	if duration != nil {
+		return *duration
+	}
+	return defaultMinLeadershipDuration
+}
+
+// GetIdleNoopIntervalOrDefault returns the configured interval at which an idle leader commits a no-op entry if set,
+// otherwise the default idle no-op interval. An interval of 0 disables idle no-op entries.
+func (c *ProtocolConfig) GetIdleNoopIntervalOrDefault() time.Duration {
+	interval := c.GetIdleNoopInterval()
+	if interval != nil {
+		return *interval
+	}
+	return defaultIdleNoopInterval
+}
+
+// GetFailureLogIntervalOrDefault returns the configured minimum interval between logged replication failures for
+// each member if set, otherwise the default failure log interval. An interval of 0 logs every failure.
+func (c *ProtocolConfig) GetFailureLogIntervalOrDefault() time.Duration {
+	interval := c.GetFailureLogInterval()
+	if interval != nil {
+		return *interval
+	}
+	return defaultFailureLogInterval
+}
+
+// GetLeaderWarmupOrDefault returns the configured maximum time for which a new leader waits to establish contact
+// with a quorum before accepting commands if set, otherwise the default leader warm-up. A warm-up of 0 disables
+// the warm-up.
+func (c *ProtocolConfig) GetLeaderWarmupOrDefault() time.Duration {
+	warmup := c.GetLeaderWarmup()
+	if warmup != nil {
+		return *warmup
+	}
+	return defaultLeaderWarmup
+}
+
+// GetSlowAppendThresholdOrDefault returns the configured duration after which a successful append marks the
+// receiving member degraded if set, otherwise the default slow append threshold. A threshold of 0 disables the check.
+func (c *ProtocolConfig) GetSlowAppendThresholdOrDefault() time.Duration {
+	threshold := c.GetSlowAppendThreshold()
+	if threshold != nil {
+		return *threshold
+	}
+	return defaultSlowAppendThreshold
+}
+
+// GetLastAppliedSyncIntervalOrDefault returns the configured minimum interval at which the index of the last entry
+// applied to a persistent state machine is persisted if set, otherwise the default of 1 second. An interval of 0
+// persists the index after every change applied to the state machine.
+func (c *ProtocolConfig) GetLastAppliedSyncIntervalOrDefault() time.Duration {
+	interval := c.GetLastAppliedSyncInterval()
+	if interval != nil {
+		return *interval
+	}
+	return defaultLastAppliedSyncInterval
+}
+
+// GetCommitStallThresholdOrDefault returns the configured duration for which the leader's commit index may not advance
+// while proposals are pending before the commit index is reported stalled if set, otherwise the default commit stall
+// threshold. A threshold of 0 disables stall detection.
+func (c *ProtocolConfig) GetCommitStallThresholdOrDefault() time.Duration {
+	threshold := c.GetCommitStallThreshold()
+	if threshold != nil {
+		return *threshold
+	}
+	return defaultCommitStallThreshold
+}
+
+// GetStartupTimeoutOrDefault returns the configured maximum time to wait for the protocol to become ready on startup
+// if set, otherwise the default startup timeout. A timeout of 0 waits indefinitely.
+func (c *ProtocolConfig) GetStartupTimeoutOrDefault() time.Duration {
+	timeout := c.GetStartupTimeout()
+	if timeout != nil {
+		return *timeout
+	}
+	return defaultStartupTimeout
+}
+
+// GetFlapThresholdOrDefault returns the configured number of times a member may fail after recovering within the flap
+// window before entries are withheld from it if set, otherwise the default of 0, which disables flap detection
+func (c *ProtocolConfig) GetFlapThresholdOrDefault() int {
+	threshold := c.GetFlapThreshold()
+	if threshold > 0 {
+		return int(threshold)
+	}
+	return defaultFlapThreshold
+}
+
+// GetFlapWindowOrDefault returns the configured window within which a member's failures after recovering are
+// counted to detect flapping if set, otherwise the default flap window of 1 minute
+func (c *ProtocolConfig) GetFlapWindowOrDefault() time.Duration {
+	window := c.GetFlapWindow()
+	if window != nil {
+		return *window
+	}
+	return defaultFlapWindow
+}
+
+// GetFlapBackoffOrDefault returns the configured time for which replication of entries to a flapping member is
+// suspended to let the member stabilize if set, otherwise the default flap backoff of 30 seconds
+func (c *ProtocolConfig) GetFlapBackoffOrDefault() time.Duration {
+	backoff := c.GetFlapBackoff()
+	if backoff != nil {
+		return *backoff
+	}
+	return defaultFlapBackoff
+}
+
+// GetMaintenanceWindowOrDefault returns the configured time for which a member in maintenance mode does not stand
+// for election if set, otherwise the default maintenance window of 10 minutes
+func (c *ProtocolConfig) GetMaintenanceWindowOrDefault() time.Duration {
+	window := c.GetMaintenanceWindow()
+	if window != nil {
+		return *window
+	}
+	return defaultMaintenanceWindow
+}
+
+// GetRateWindowOrDefault returns the configured sliding window over which the apply and commit rates are measured
+// if set, otherwise the default rate window of 10 seconds
+func (c *ProtocolConfig) GetRateWindowOrDefault() time.Duration {
+	window := c.GetRateWindow()
+	if window != nil {
+		return *window
+	}
+	return defaultRateWindow
+}
+
+// GetSnapshotOnStopTimeoutOrDefault returns the configured maximum time to wait for a snapshot to be taken when the
+// server is stopped if set, otherwise the default of 10 seconds
+func (c *ProtocolConfig) GetSnapshotOnStopTimeoutOrDefault() time.Duration {
+	timeout := c.GetCompaction().GetSnapshotOnStopTimeout()
+	if timeout != nil {
+		return *timeout
+	}
+	return defaultSnapshotOnStopTimeout
+}
+
+// GetReadTransactionTimeoutOrDefault returns the configured maximum time a read transaction may pin the state
+// machine if set, otherwise the election timeout. Committed changes are deferred while the state machine is pinned,
+// so by default a read transaction stalls the state machine no longer than a leader election would.
+func (c *ProtocolConfig) GetReadTransactionTimeoutOrDefault() time.Duration {
+	timeout := c.GetReadTransactionTimeout()
+	if timeout != nil {
+		return *timeout
+	}
+	return c.GetElectionTimeoutOrDefault()
+}
+
+// GetStatusIntervalOrDefault returns the configured interval at which status updates are sent to watchers if set,
+// otherwise the default status interval
+func (c *ProtocolConfig) GetStatusIntervalOrDefault() time.Duration {
+	interval := c.GetStatusInterval()
+	if interval != nil {
+		return *interval
+	}
+	return defaultStatusInterval
+}
+
+// GetMaxEntrySizeOrDefault returns the configured maximum size of a log entry in bytes if set, otherwise the
+// default maximum entry size
+func (c *ProtocolConfig) GetMaxEntrySizeOrDefault() int {
+	size := c.GetStorage().GetMaxEntrySize()
+	if size > 0 {
+		return int(size)
+	}
+	return defaultMaxEntrySize
+}
+
+// GetInstallTimeoutOrDefault returns the configured timeout for replicating a snapshot to a member if set,
+// otherwise a multiple of the election timeout. Snapshots can take much longer to replicate than log entries,
+// so the timeout applies to the install stream as a whole rather than to individual chunks.
+func (c *ProtocolConfig) GetInstallTimeoutOrDefault() time.Duration {
+	timeout := c.GetInstallTimeout()
+	if timeout != nil {
+		return *timeout
+	}
+	return c.GetElectionTimeoutOrDefault() * defaultInstallTimeoutFactor
+}
+
+// GetApplyParallelismOrDefault returns the configured maximum number of commands to apply to the state machine
+// concurrently if set, otherwise the default of 1, which applies all entries serially
+func (c *ProtocolConfig) GetApplyParallelismOrDefault() int {
+	parallelism := c.GetApplyParallelism()
+	if parallelism > 0 {
+		return int(parallelism)
+	}
+	return defaultApplyParallelism
+}
+
+// GetDiskCheckIntervalOrDefault returns the configured interval for which a reading of the free space in the storage
+// directory is reused if set, otherwise the default of 1 second. An interval of 0 checks the free space on every write.
+func (c *ProtocolConfig) GetDiskCheckIntervalOrDefault() time.Duration {
+	interval := c.GetStorage().GetDiskCheckInterval()
+	if interval == nil {
+		return defaultDiskCheckInterval
+	}
+	return *interval
+}
+
+// GetMetadataSyncWindowOrDefault returns the configured window within which changes to the term and vote share
+// a single sync if set, otherwise the default of 0, which syncs each change. The window is limited to 10ms to bound
+// the latency of elections.
+func (c *ProtocolConfig) GetMetadataSyncWindowOrDefault() time.Duration {
+	window := c.GetStorage().GetMetadataSyncWindow()
+	if window == nil {
+		return defaultMetadataSyncWindow
+	}
+	if *window > maxMetadataSyncWindow {
+		return maxMetadataSyncWindow
+	}
+	return *window
+}
+
+// GetMaxAppendWorkersOrDefault returns the configured maximum number of goroutines replicating to each member if set,
+// otherwise the default of 2. At least 2 workers are used so an abandoned append can be replaced.
+func (c *ProtocolConfig) GetMaxAppendWorkersOrDefault() int {
+	workers := int(c.GetMaxAppendWorkers())
+	if workers < minAppendWorkers {
+		return minAppendWorkers
+	}
+	return workers
+}
+
+// GetMaxAppendQueueBytesOrDefault returns the configured maximum size in bytes of the entries cached for replication
+// to each member if set, otherwise the default of 16MiB
+func (c *ProtocolConfig) GetMaxAppendQueueBytesOrDefault() int {
+	size := c.GetMaxAppendQueueBytes()
+	if size > 0 {
+		return int(size)
+	}
+	return defaultMaxAppendQueueBytes
+}
+
+// GetMaxElectionWorkersOrDefault returns the configured maximum number of poll or vote requests a member sends
+// concurrently when standing for election if set, otherwise the default of 16
+func (c *ProtocolConfig) GetMaxElectionWorkersOrDefault() int {
+	workers := c.GetMaxElectionWorkers()
+	if workers > 0 {
+		return int(workers)
+	}
+	return defaultMaxElectionWorkers
+}
+
+// GetMaxCommitBatchSizeOrDefault returns the configured maximum number of entries the leader commits per acquisition
+// of the write lock if set, otherwise the default of 1000. Larger commits release the lock between batches.
+func (c *ProtocolConfig) GetMaxCommitBatchSizeOrDefault() int {
+	size := c.GetMaxCommitBatchSize()
+	if size > 0 {
+		return int(size)
+	}
+	return defaultMaxCommitBatchSize
+}
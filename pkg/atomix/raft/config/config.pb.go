@@ -53,11 +53,80 @@ func (StorageLevel) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_e09be49defe43eb0, []int{0}
 }
 
+// ReadConsistency is the default consistency of reads sent by the protocol's client
+// The values match the read consistency levels of the Raft protocol.
+type ReadConsistency int32
+
+const (
+	ReadConsistency_SEQUENTIAL         ReadConsistency = 0
+	ReadConsistency_LINEARIZABLE_LEASE ReadConsistency = 1
+	ReadConsistency_LINEARIZABLE       ReadConsistency = 2
+	ReadConsistency_STALE              ReadConsistency = 3
+)
+
+var ReadConsistency_name = map[int32]string{
+	0: "SEQUENTIAL",
+	1: "LINEARIZABLE_LEASE",
+	2: "LINEARIZABLE",
+	3: "STALE",
+}
+
+var ReadConsistency_value = map[string]int32{
+	"SEQUENTIAL":         0,
+	"LINEARIZABLE_LEASE": 1,
+	"LINEARIZABLE":       2,
+	"STALE":              3,
+}
+
+func (x ReadConsistency) String() string {
+	return proto.EnumName(ReadConsistency_name, int32(x))
+}
+
+func (ReadConsistency) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e09be49defe43eb0, []int{1}
+}
+
 type ProtocolConfig struct {
-	ElectionTimeout   *time.Duration    `protobuf:"bytes,1,opt,name=election_timeout,json=electionTimeout,proto3,stdduration" json:"election_timeout,omitempty"`
-	HeartbeatInterval *time.Duration    `protobuf:"bytes,2,opt,name=heartbeat_interval,json=heartbeatInterval,proto3,stdduration" json:"heartbeat_interval,omitempty"`
-	Storage           *StorageConfig    `protobuf:"bytes,3,opt,name=storage,proto3" json:"storage,omitempty"`
-	Compaction        *CompactionConfig `protobuf:"bytes,4,opt,name=compaction,proto3" json:"compaction,omitempty"`
+	ElectionTimeout            *time.Duration    `protobuf:"bytes,1,opt,name=election_timeout,json=electionTimeout,proto3,stdduration" json:"election_timeout,omitempty"`
+	HeartbeatInterval          *time.Duration    `protobuf:"bytes,2,opt,name=heartbeat_interval,json=heartbeatInterval,proto3,stdduration" json:"heartbeat_interval,omitempty"`
+	Storage                    *StorageConfig    `protobuf:"bytes,3,opt,name=storage,proto3" json:"storage,omitempty"`
+	Compaction                 *CompactionConfig `protobuf:"bytes,4,opt,name=compaction,proto3" json:"compaction,omitempty"`
+	QuorumHealthInterval       *time.Duration    `protobuf:"bytes,5,opt,name=quorum_health_interval,json=quorumHealthInterval,proto3,stdduration" json:"quorum_health_interval,omitempty"`
+	MinLeadershipDuration      *time.Duration    `protobuf:"bytes,6,opt,name=min_leadership_duration,json=minLeadershipDuration,proto3,stdduration" json:"min_leadership_duration,omitempty"`
+	PersistReplicationProgress bool              `protobuf:"varint,7,opt,name=persist_replication_progress,json=persistReplicationProgress,proto3" json:"persist_replication_progress,omitempty"`
+	MaxUncommittedEntries      uint64            `protobuf:"varint,8,opt,name=max_uncommitted_entries,json=maxUncommittedEntries,proto3" json:"max_uncommitted_entries,omitempty"`
+	InstallTimeout             *time.Duration    `protobuf:"bytes,9,opt,name=install_timeout,json=installTimeout,proto3,stdduration" json:"install_timeout,omitempty"`
+	ApplyParallelism           uint32            `protobuf:"varint,10,opt,name=apply_parallelism,json=applyParallelism,proto3" json:"apply_parallelism,omitempty"`
+	ClusterId                  string            `protobuf:"bytes,11,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	MaxAppendQueueBytes        uint64            `protobuf:"varint,12,opt,name=max_append_queue_bytes,json=maxAppendQueueBytes,proto3" json:"max_append_queue_bytes,omitempty"`
+	MaxAppendWorkers           uint32            `protobuf:"varint,13,opt,name=max_append_workers,json=maxAppendWorkers,proto3" json:"max_append_workers,omitempty"`
+	ApplyQueueSize             uint32            `protobuf:"varint,14,opt,name=apply_queue_size,json=applyQueueSize,proto3" json:"apply_queue_size,omitempty"`
+	IdleNoopInterval           *time.Duration    `protobuf:"bytes,16,opt,name=idle_noop_interval,json=idleNoopInterval,proto3,stdduration" json:"idle_noop_interval,omitempty"`
+	FailureLogInterval         *time.Duration    `protobuf:"bytes,17,opt,name=failure_log_interval,json=failureLogInterval,proto3,stdduration" json:"failure_log_interval,omitempty"`
+	LeaderWarmup               *time.Duration    `protobuf:"bytes,18,opt,name=leader_warmup,json=leaderWarmup,proto3,stdduration" json:"leader_warmup,omitempty"`
+	StatusInterval             *time.Duration    `protobuf:"bytes,19,opt,name=status_interval,json=statusInterval,proto3,stdduration" json:"status_interval,omitempty"`
+	MaxCommitBatchSize         uint32            `protobuf:"varint,20,opt,name=max_commit_batch_size,json=maxCommitBatchSize,proto3" json:"max_commit_batch_size,omitempty"`
+	StepDownOnFault            bool              `protobuf:"varint,21,opt,name=step_down_on_fault,json=stepDownOnFault,proto3" json:"step_down_on_fault,omitempty"`
+	RelayFanout                uint32            `protobuf:"varint,22,opt,name=relay_fanout,json=relayFanout,proto3" json:"relay_fanout,omitempty"`
+	Observers                  []string          `protobuf:"bytes,23,rep,name=observers,proto3" json:"observers,omitempty"`
+	MaxElectionWorkers         uint32            `protobuf:"varint,24,opt,name=max_election_workers,json=maxElectionWorkers,proto3" json:"max_election_workers,omitempty"`
+	MaxTermGap                 uint64            `protobuf:"varint,25,opt,name=max_term_gap,json=maxTermGap,proto3" json:"max_term_gap,omitempty"`
+	FaultOnTermGap             bool              `protobuf:"varint,26,opt,name=fault_on_term_gap,json=faultOnTermGap,proto3" json:"fault_on_term_gap,omitempty"`
+	SlowAppendThreshold        *time.Duration    `protobuf:"bytes,27,opt,name=slow_append_threshold,json=slowAppendThreshold,proto3,stdduration" json:"slow_append_threshold,omitempty"`
+	ProbeOnRecovery            bool              `protobuf:"varint,28,opt,name=probe_on_recovery,json=probeOnRecovery,proto3" json:"probe_on_recovery,omitempty"`
+	LastAppliedSyncInterval    *time.Duration    `protobuf:"bytes,29,opt,name=last_applied_sync_interval,json=lastAppliedSyncInterval,proto3,stdduration" json:"last_applied_sync_interval,omitempty"`
+	CommitStallThreshold       *time.Duration    `protobuf:"bytes,30,opt,name=commit_stall_threshold,json=commitStallThreshold,proto3,stdduration" json:"commit_stall_threshold,omitempty"`
+	ReadConsistency            ReadConsistency   `protobuf:"varint,31,opt,name=read_consistency,json=readConsistency,proto3,enum=atomix.raft.config.ReadConsistency" json:"read_consistency,omitempty"`
+	StartupTimeout             *time.Duration    `protobuf:"bytes,32,opt,name=startup_timeout,json=startupTimeout,proto3,stdduration" json:"startup_timeout,omitempty"`
+	Witnesses                  []string          `protobuf:"bytes,33,rep,name=witnesses,proto3" json:"witnesses,omitempty"`
+	FlapThreshold              uint32            `protobuf:"varint,34,opt,name=flap_threshold,json=flapThreshold,proto3" json:"flap_threshold,omitempty"`
+	FlapWindow                 *time.Duration    `protobuf:"bytes,35,opt,name=flap_window,json=flapWindow,proto3,stdduration" json:"flap_window,omitempty"`
+	FlapBackoff                *time.Duration    `protobuf:"bytes,36,opt,name=flap_backoff,json=flapBackoff,proto3,stdduration" json:"flap_backoff,omitempty"`
+	MaintenanceWindow          *time.Duration    `protobuf:"bytes,37,opt,name=maintenance_window,json=maintenanceWindow,proto3,stdduration" json:"maintenance_window,omitempty"`
+	RateWindow                 *time.Duration    `protobuf:"bytes,38,opt,name=rate_window,json=rateWindow,proto3,stdduration" json:"rate_window,omitempty"`
+	MaxReplicationWorkers      uint32            `protobuf:"varint,39,opt,name=max_replication_workers,json=maxReplicationWorkers,proto3" json:"max_replication_workers,omitempty"`
+	MaxPendingHeartbeats       uint32            `protobuf:"varint,40,opt,name=max_pending_heartbeats,json=maxPendingHeartbeats,proto3" json:"max_pending_heartbeats,omitempty"`
+	ReadTransactionTimeout     *time.Duration    `protobuf:"bytes,41,opt,name=read_transaction_timeout,json=readTransactionTimeout,proto3,stdduration" json:"read_transaction_timeout,omitempty"`
 }
 
 func (m *ProtocolConfig) Reset()         { *m = ProtocolConfig{} }
@@ -121,12 +190,268 @@ func (m *ProtocolConfig) GetCompaction() *CompactionConfig {
 	return nil
 }
 
+func (m *ProtocolConfig) GetQuorumHealthInterval() *time.Duration {
+	if m != nil {
+		return m.QuorumHealthInterval
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetMinLeadershipDuration() *time.Duration {
+	if m != nil {
+		return m.MinLeadershipDuration
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetPersistReplicationProgress() bool {
+	if m != nil {
+		return m.PersistReplicationProgress
+	}
+	return false
+}
+
+func (m *ProtocolConfig) GetMaxUncommittedEntries() uint64 {
+	if m != nil {
+		return m.MaxUncommittedEntries
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetInstallTimeout() *time.Duration {
+	if m != nil {
+		return m.InstallTimeout
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetApplyParallelism() uint32 {
+	if m != nil {
+		return m.ApplyParallelism
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetClusterId() string {
+	if m != nil {
+		return m.ClusterId
+	}
+	return ""
+}
+
+func (m *ProtocolConfig) GetMaxAppendQueueBytes() uint64 {
+	if m != nil {
+		return m.MaxAppendQueueBytes
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetMaxAppendWorkers() uint32 {
+	if m != nil {
+		return m.MaxAppendWorkers
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetApplyQueueSize() uint32 {
+	if m != nil {
+		return m.ApplyQueueSize
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetIdleNoopInterval() *time.Duration {
+	if m != nil {
+		return m.IdleNoopInterval
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetFailureLogInterval() *time.Duration {
+	if m != nil {
+		return m.FailureLogInterval
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetLeaderWarmup() *time.Duration {
+	if m != nil {
+		return m.LeaderWarmup
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetStatusInterval() *time.Duration {
+	if m != nil {
+		return m.StatusInterval
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetMaxCommitBatchSize() uint32 {
+	if m != nil {
+		return m.MaxCommitBatchSize
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetStepDownOnFault() bool {
+	if m != nil {
+		return m.StepDownOnFault
+	}
+	return false
+}
+
+func (m *ProtocolConfig) GetRelayFanout() uint32 {
+	if m != nil {
+		return m.RelayFanout
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetObservers() []string {
+	if m != nil {
+		return m.Observers
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetMaxElectionWorkers() uint32 {
+	if m != nil {
+		return m.MaxElectionWorkers
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetMaxTermGap() uint64 {
+	if m != nil {
+		return m.MaxTermGap
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetFaultOnTermGap() bool {
+	if m != nil {
+		return m.FaultOnTermGap
+	}
+	return false
+}
+
+func (m *ProtocolConfig) GetSlowAppendThreshold() *time.Duration {
+	if m != nil {
+		return m.SlowAppendThreshold
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetProbeOnRecovery() bool {
+	if m != nil {
+		return m.ProbeOnRecovery
+	}
+	return false
+}
+
+func (m *ProtocolConfig) GetLastAppliedSyncInterval() *time.Duration {
+	if m != nil {
+		return m.LastAppliedSyncInterval
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetCommitStallThreshold() *time.Duration {
+	if m != nil {
+		return m.CommitStallThreshold
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetReadConsistency() ReadConsistency {
+	if m != nil {
+		return m.ReadConsistency
+	}
+	return ReadConsistency_SEQUENTIAL
+}
+
+func (m *ProtocolConfig) GetStartupTimeout() *time.Duration {
+	if m != nil {
+		return m.StartupTimeout
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetWitnesses() []string {
+	if m != nil {
+		return m.Witnesses
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetFlapThreshold() uint32 {
+	if m != nil {
+		return m.FlapThreshold
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetFlapWindow() *time.Duration {
+	if m != nil {
+		return m.FlapWindow
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetFlapBackoff() *time.Duration {
+	if m != nil {
+		return m.FlapBackoff
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetMaintenanceWindow() *time.Duration {
+	if m != nil {
+		return m.MaintenanceWindow
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetRateWindow() *time.Duration {
+	if m != nil {
+		return m.RateWindow
+	}
+	return nil
+}
+
+func (m *ProtocolConfig) GetMaxReplicationWorkers() uint32 {
+	if m != nil {
+		return m.MaxReplicationWorkers
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetMaxPendingHeartbeats() uint32 {
+	if m != nil {
+		return m.MaxPendingHeartbeats
+	}
+	return 0
+}
+
+func (m *ProtocolConfig) GetReadTransactionTimeout() *time.Duration {
+	if m != nil {
+		return m.ReadTransactionTimeout
+	}
+	return nil
+}
+
 type StorageConfig struct {
-	Directory     string       `protobuf:"bytes,1,opt,name=directory,proto3" json:"directory,omitempty"`
-	Level         StorageLevel `protobuf:"varint,2,opt,name=level,proto3,enum=atomix.raft.config.StorageLevel" json:"level,omitempty"`
-	MaxEntrySize  uint32       `protobuf:"varint,3,opt,name=max_entry_size,json=maxEntrySize,proto3" json:"max_entry_size,omitempty"`
-	SegmentSize   uint32       `protobuf:"varint,4,opt,name=segment_size,json=segmentSize,proto3" json:"segment_size,omitempty"`
-	FlushOnCommit bool         `protobuf:"varint,5,opt,name=flush_on_commit,json=flushOnCommit,proto3" json:"flush_on_commit,omitempty"`
+	Directory          string         `protobuf:"bytes,1,opt,name=directory,proto3" json:"directory,omitempty"`
+	Level              StorageLevel   `protobuf:"varint,2,opt,name=level,proto3,enum=atomix.raft.config.StorageLevel" json:"level,omitempty"`
+	MaxEntrySize       uint32         `protobuf:"varint,3,opt,name=max_entry_size,json=maxEntrySize,proto3" json:"max_entry_size,omitempty"`
+	SegmentSize        uint32         `protobuf:"varint,4,opt,name=segment_size,json=segmentSize,proto3" json:"segment_size,omitempty"`
+	FlushOnCommit      bool           `protobuf:"varint,5,opt,name=flush_on_commit,json=flushOnCommit,proto3" json:"flush_on_commit,omitempty"`
+	MinFreeDiskSpace   uint64         `protobuf:"varint,6,opt,name=min_free_disk_space,json=minFreeDiskSpace,proto3" json:"min_free_disk_space,omitempty"`
+	DataDir            string         `protobuf:"bytes,7,opt,name=data_dir,json=dataDir,proto3" json:"data_dir,omitempty"`
+	MetadataSyncWindow *time.Duration `protobuf:"bytes,8,opt,name=metadata_sync_window,json=metadataSyncWindow,proto3,stdduration" json:"metadata_sync_window,omitempty"`
+	DiskCheckInterval  *time.Duration `protobuf:"bytes,9,opt,name=disk_check_interval,json=diskCheckInterval,proto3,stdduration" json:"disk_check_interval,omitempty"`
 }
 
 func (m *StorageConfig) Reset()         { *m = StorageConfig{} }
@@ -197,10 +522,46 @@ func (m *StorageConfig) GetFlushOnCommit() bool {
 	return false
 }
 
+func (m *StorageConfig) GetMinFreeDiskSpace() uint64 {
+	if m != nil {
+		return m.MinFreeDiskSpace
+	}
+	return 0
+}
+
+func (m *StorageConfig) GetDataDir() string {
+	if m != nil {
+		return m.DataDir
+	}
+	return ""
+}
+
+func (m *StorageConfig) GetMetadataSyncWindow() *time.Duration {
+	if m != nil {
+		return m.MetadataSyncWindow
+	}
+	return nil
+}
+
+func (m *StorageConfig) GetDiskCheckInterval() *time.Duration {
+	if m != nil {
+		return m.DiskCheckInterval
+	}
+	return nil
+}
+
 type CompactionConfig struct {
-	Dynamic          bool    `protobuf:"varint,1,opt,name=dynamic,proto3" json:"dynamic,omitempty"`
-	FreeDiskBuffer   float32 `protobuf:"fixed32,2,opt,name=free_disk_buffer,json=freeDiskBuffer,proto3" json:"free_disk_buffer,omitempty"`
-	FreeMemoryBuffer float32 `protobuf:"fixed32,3,opt,name=free_memory_buffer,json=freeMemoryBuffer,proto3" json:"free_memory_buffer,omitempty"`
+	Dynamic               bool           `protobuf:"varint,1,opt,name=dynamic,proto3" json:"dynamic,omitempty"`
+	FreeDiskBuffer        float32        `protobuf:"fixed32,2,opt,name=free_disk_buffer,json=freeDiskBuffer,proto3" json:"free_disk_buffer,omitempty"`
+	FreeMemoryBuffer      float32        `protobuf:"fixed32,3,opt,name=free_memory_buffer,json=freeMemoryBuffer,proto3" json:"free_memory_buffer,omitempty"`
+	SnapshotThreshold     uint64         `protobuf:"varint,4,opt,name=snapshot_threshold,json=snapshotThreshold,proto3" json:"snapshot_threshold,omitempty"`
+	AsyncSnapshots        bool           `protobuf:"varint,5,opt,name=async_snapshots,json=asyncSnapshots,proto3" json:"async_snapshots,omitempty"`
+	RetainedEntries       uint64         `protobuf:"varint,6,opt,name=retained_entries,json=retainedEntries,proto3" json:"retained_entries,omitempty"`
+	RetainedBytes         uint64         `protobuf:"varint,7,opt,name=retained_bytes,json=retainedBytes,proto3" json:"retained_bytes,omitempty"`
+	RestoreBufferSize     uint32         `protobuf:"varint,8,opt,name=restore_buffer_size,json=restoreBufferSize,proto3" json:"restore_buffer_size,omitempty"`
+	MaxSnapshotSize       uint64         `protobuf:"varint,9,opt,name=max_snapshot_size,json=maxSnapshotSize,proto3" json:"max_snapshot_size,omitempty"`
+	SnapshotOnStop        bool           `protobuf:"varint,10,opt,name=snapshot_on_stop,json=snapshotOnStop,proto3" json:"snapshot_on_stop,omitempty"`
+	SnapshotOnStopTimeout *time.Duration `protobuf:"bytes,11,opt,name=snapshot_on_stop_timeout,json=snapshotOnStopTimeout,proto3,stdduration" json:"snapshot_on_stop_timeout,omitempty"`
 }
 
 func (m *CompactionConfig) Reset()         { *m = CompactionConfig{} }
@@ -257,8 +618,65 @@ func (m *CompactionConfig) GetFreeMemoryBuffer() float32 {
 	return 0
 }
 
+func (m *CompactionConfig) GetSnapshotThreshold() uint64 {
+	if m != nil {
+		return m.SnapshotThreshold
+	}
+	return 0
+}
+
+func (m *CompactionConfig) GetAsyncSnapshots() bool {
+	if m != nil {
+		return m.AsyncSnapshots
+	}
+	return false
+}
+
+func (m *CompactionConfig) GetRetainedEntries() uint64 {
+	if m != nil {
+		return m.RetainedEntries
+	}
+	return 0
+}
+
+func (m *CompactionConfig) GetRetainedBytes() uint64 {
+	if m != nil {
+		return m.RetainedBytes
+	}
+	return 0
+}
+
+func (m *CompactionConfig) GetRestoreBufferSize() uint32 {
+	if m != nil {
+		return m.RestoreBufferSize
+	}
+	return 0
+}
+
+func (m *CompactionConfig) GetMaxSnapshotSize() uint64 {
+	if m != nil {
+		return m.MaxSnapshotSize
+	}
+	return 0
+}
+
+func (m *CompactionConfig) GetSnapshotOnStop() bool {
+	if m != nil {
+		return m.SnapshotOnStop
+	}
+	return false
+}
+
+func (m *CompactionConfig) GetSnapshotOnStopTimeout() *time.Duration {
+	if m != nil {
+		return m.SnapshotOnStopTimeout
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("atomix.raft.config.StorageLevel", StorageLevel_name, StorageLevel_value)
+	proto.RegisterEnum("atomix.raft.config.ReadConsistency", ReadConsistency_name, ReadConsistency_value)
 	proto.RegisterType((*ProtocolConfig)(nil), "atomix.raft.config.ProtocolConfig")
 	proto.RegisterType((*StorageConfig)(nil), "atomix.raft.config.StorageConfig")
 	proto.RegisterType((*CompactionConfig)(nil), "atomix.raft.config.CompactionConfig")
@@ -267,39 +685,107 @@ func init() {
 func init() { proto.RegisterFile("atomix/raft/config/config.proto", fileDescriptor_e09be49defe43eb0) }
 
 var fileDescriptor_e09be49defe43eb0 = []byte{
-	// 511 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x6f, 0xd3, 0x30,
-	0x18, 0xc6, 0xeb, 0xad, 0xdb, 0xba, 0x77, 0x6d, 0x57, 0x2c, 0x0e, 0x61, 0x42, 0x5e, 0x57, 0x55,
-	0xa8, 0x42, 0x28, 0x95, 0x86, 0xc4, 0x85, 0x13, 0x6d, 0x77, 0x18, 0x30, 0xa8, 0x52, 0xee, 0x91,
-	0x9b, 0x3a, 0x99, 0xb5, 0x38, 0x9e, 0x1c, 0x77, 0x6a, 0x77, 0xe6, 0x03, 0x70, 0xe4, 0x23, 0x70,
-	0xe3, 0xca, 0x47, 0xe0, 0xb8, 0x13, 0xe2, 0x06, 0xa4, 0x5f, 0x82, 0x23, 0x8a, 0x9d, 0x8c, 0xf1,
-	0x47, 0x88, 0x53, 0xde, 0x3c, 0xfe, 0x3d, 0xef, 0xeb, 0xe7, 0x95, 0x61, 0x9f, 0x6a, 0x29, 0xf8,
-	0xa2, 0xaf, 0x68, 0xa8, 0xfb, 0x81, 0x4c, 0x42, 0x1e, 0x15, 0x1f, 0xf7, 0x5c, 0x49, 0x2d, 0x31,
-	0xb6, 0x80, 0x9b, 0x03, 0xae, 0x3d, 0xd9, 0x23, 0x91, 0x94, 0x51, 0xcc, 0xfa, 0x86, 0x98, 0xce,
-	0xc3, 0xfe, 0x6c, 0xae, 0xa8, 0xe6, 0x32, 0xb1, 0x9e, 0xbd, 0xdb, 0x91, 0x8c, 0xa4, 0x29, 0xfb,
-	0x79, 0x65, 0xd5, 0xce, 0xfb, 0x35, 0x68, 0x8e, 0xf3, 0x2a, 0x90, 0xf1, 0xd0, 0x34, 0xc2, 0x4f,
-	0xa1, 0xc5, 0x62, 0x16, 0xe4, 0x56, 0x5f, 0x73, 0xc1, 0xe4, 0x5c, 0x3b, 0xa8, 0x8d, 0x7a, 0x3b,
-	0x87, 0x77, 0x5c, 0x3b, 0xc3, 0x2d, 0x67, 0xb8, 0xa3, 0x62, 0xc6, 0xa0, 0xfa, 0xf6, 0xcb, 0x3e,
-	0xf2, 0x76, 0x4b, 0xe3, 0x2b, 0xeb, 0xc3, 0x2f, 0x00, 0x9f, 0x32, 0xaa, 0xf4, 0x94, 0x51, 0xed,
-	0xf3, 0x44, 0x33, 0x75, 0x41, 0x63, 0x67, 0xed, 0xff, 0xba, 0xdd, 0xba, 0xb6, 0x1e, 0x17, 0x4e,
-	0xfc, 0x18, 0xb6, 0x52, 0x2d, 0x15, 0x8d, 0x98, 0xb3, 0x6e, 0x9a, 0x1c, 0xb8, 0x7f, 0xae, 0xc2,
-	0x9d, 0x58, 0xc4, 0xe6, 0xf1, 0x4a, 0x07, 0x1e, 0x01, 0x04, 0x52, 0x9c, 0x53, 0x73, 0x43, 0xa7,
-	0x6a, 0xfc, 0xdd, 0xbf, 0xf9, 0x87, 0xd7, 0x54, 0xd1, 0xe2, 0x86, 0xaf, 0xf3, 0x09, 0x41, 0xe3,
-	0x97, 0x01, 0xf8, 0x2e, 0x6c, 0xcf, 0xb8, 0x62, 0x81, 0x96, 0x6a, 0x69, 0x36, 0xb5, 0xed, 0xfd,
-	0x14, 0xf0, 0x23, 0xd8, 0x88, 0xd9, 0x05, 0xb3, 0xa9, 0x9b, 0x87, 0xed, 0x7f, 0x5c, 0xf8, 0x79,
-	0xce, 0x79, 0x16, 0xc7, 0x5d, 0x68, 0x0a, 0xba, 0xf0, 0x59, 0xa2, 0xd5, 0xd2, 0x4f, 0xf9, 0xa5,
-	0x4d, 0xdc, 0xf0, 0xea, 0x82, 0x2e, 0x8e, 0x72, 0x71, 0xc2, 0x2f, 0x19, 0x3e, 0x80, 0x7a, 0xca,
-	0x22, 0xc1, 0x12, 0x6d, 0x99, 0xaa, 0x61, 0x76, 0x0a, 0xcd, 0x20, 0xf7, 0x60, 0x37, 0x8c, 0xe7,
-	0xe9, 0xa9, 0x2f, 0x13, 0x3f, 0x90, 0x42, 0x70, 0xed, 0x6c, 0xb4, 0x51, 0xaf, 0xe6, 0x35, 0x8c,
-	0xfc, 0x32, 0x19, 0x1a, 0xb1, 0xf3, 0x1a, 0x41, 0xeb, 0xf7, 0xe4, 0xd8, 0x81, 0xad, 0xd9, 0x32,
-	0xa1, 0x82, 0x07, 0x26, 0x59, 0xcd, 0x2b, 0x7f, 0x71, 0x0f, 0x5a, 0xa1, 0x62, 0xcc, 0x9f, 0xf1,
-	0xf4, 0xcc, 0x9f, 0xce, 0xc3, 0x90, 0x29, 0x13, 0x71, 0xcd, 0x6b, 0xe6, 0xfa, 0x88, 0xa7, 0x67,
-	0x03, 0xa3, 0xe2, 0x07, 0x80, 0x0d, 0x29, 0x98, 0x90, 0x6a, 0x59, 0xb2, 0xeb, 0x86, 0x35, 0x3d,
-	0x4e, 0xcc, 0x81, 0xa5, 0xef, 0x77, 0xa1, 0x7e, 0x73, 0x1d, 0xb8, 0x06, 0xd5, 0xd1, 0xf1, 0xe4,
-	0x59, 0xab, 0x82, 0x01, 0x36, 0x4f, 0x9e, 0x8c, 0xc7, 0x47, 0xa3, 0x16, 0x1a, 0x74, 0xbf, 0x7f,
-	0x23, 0xe8, 0x5d, 0x46, 0xd0, 0x87, 0x8c, 0xa0, 0x8f, 0x19, 0x41, 0x57, 0x19, 0x41, 0x5f, 0x33,
-	0x82, 0xde, 0xac, 0x48, 0xe5, 0x6a, 0x45, 0x2a, 0x9f, 0x57, 0xa4, 0x32, 0xdd, 0x34, 0x4f, 0xeb,
-	0xe1, 0x8f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xdc, 0x48, 0x2b, 0x0a, 0x51, 0x03, 0x00, 0x00,
+	// 1598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xcd, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0x2c, 0xd9, 0x22, 0x5b, 0x12, 0x45, 0x8e, 0x64, 0x19, 0x56, 0xbc, 0x34, 0xed, 0x95,
+	0x77, 0x69, 0x6f, 0x96, 0x4a, 0xbc, 0xa9, 0xbd, 0xe4, 0x62, 0xfd, 0xd0, 0xb1, 0x12, 0x59, 0x92,
+	0x41, 0x39, 0x4e, 0x52, 0xa9, 0x42, 0x0d, 0x81, 0x21, 0x89, 0x12, 0x30, 0x83, 0x9d, 0x19, 0x58,
+	0xe2, 0x3e, 0x44, 0x2a, 0xc7, 0x3c, 0x42, 0x1e, 0x21, 0x0f, 0xb0, 0x87, 0x1c, 0xf7, 0x98, 0x5b,
+	0x12, 0xf9, 0x25, 0x72, 0x4c, 0x4d, 0x0f, 0x00, 0x52, 0x8e, 0x2b, 0x85, 0x93, 0xa8, 0xee, 0xef,
+	0x6b, 0x74, 0xf7, 0x7c, 0xd3, 0xd3, 0xf0, 0x90, 0x6a, 0x91, 0x44, 0x57, 0xbb, 0x92, 0x8e, 0xf4,
+	0x6e, 0x20, 0xf8, 0x28, 0x1a, 0xe7, 0x7f, 0x7a, 0xa9, 0x14, 0x5a, 0x10, 0x62, 0x01, 0x3d, 0x03,
+	0xe8, 0x59, 0xcf, 0x76, 0x7b, 0x2c, 0xc4, 0x38, 0x66, 0xbb, 0x88, 0x18, 0x66, 0xa3, 0xdd, 0x30,
+	0x93, 0x54, 0x47, 0x82, 0x5b, 0xce, 0xf6, 0xe6, 0x58, 0x8c, 0x05, 0xfe, 0xdc, 0x35, 0xbf, 0xac,
+	0xf5, 0xf1, 0x0f, 0x1b, 0xd0, 0x38, 0x33, 0xbf, 0x02, 0x11, 0x1f, 0x60, 0x20, 0xf2, 0x6b, 0x68,
+	0xb2, 0x98, 0x05, 0x86, 0xea, 0xeb, 0x28, 0x61, 0x22, 0xd3, 0xae, 0xd3, 0x71, 0xba, 0x2b, 0xcf,
+	0xef, 0xf7, 0xec, 0x37, 0x7a, 0xc5, 0x37, 0x7a, 0x87, 0xf9, 0x37, 0xf6, 0x97, 0xfe, 0xf2, 0xcf,
+	0x87, 0x8e, 0xb7, 0x5e, 0x10, 0xcf, 0x2d, 0x8f, 0x9c, 0x00, 0x99, 0x30, 0x2a, 0xf5, 0x90, 0x51,
+	0xed, 0x47, 0x5c, 0x33, 0xf9, 0x9e, 0xc6, 0xee, 0xad, 0x6a, 0xd1, 0x5a, 0x25, 0xf5, 0x28, 0x67,
+	0x92, 0x5f, 0xc2, 0xb2, 0xd2, 0x42, 0xd2, 0x31, 0x73, 0x17, 0x31, 0xc8, 0xa3, 0xde, 0xff, 0xb6,
+	0xa2, 0x37, 0xb0, 0x10, 0x5b, 0x8f, 0x57, 0x30, 0xc8, 0x21, 0x40, 0x20, 0x92, 0x94, 0x62, 0x86,
+	0xee, 0x12, 0xf2, 0x77, 0x3e, 0xc5, 0x3f, 0x28, 0x51, 0x79, 0x88, 0x39, 0x1e, 0x79, 0x0b, 0x5b,
+	0xdf, 0x65, 0x42, 0x66, 0x89, 0x3f, 0x61, 0x34, 0xd6, 0x93, 0x59, 0x59, 0xb7, 0xab, 0x95, 0xb5,
+	0x69, 0xe9, 0xaf, 0x90, 0x5d, 0x56, 0xf6, 0x0e, 0xee, 0x25, 0x11, 0xf7, 0x63, 0x46, 0x43, 0x26,
+	0xd5, 0x24, 0x4a, 0xfd, 0xe2, 0xfc, 0xdc, 0x3b, 0xd5, 0xe2, 0xde, 0x4d, 0x22, 0x7e, 0x5c, 0xd2,
+	0x0b, 0x27, 0x79, 0x01, 0x0f, 0x52, 0x26, 0x55, 0xa4, 0xb4, 0x2f, 0x59, 0x1a, 0x47, 0x01, 0x9a,
+	0xfd, 0x54, 0x8a, 0xb1, 0x64, 0x4a, 0xb9, 0xcb, 0x1d, 0xa7, 0x5b, 0xf3, 0xb6, 0x73, 0x8c, 0x37,
+	0x83, 0x9c, 0xe5, 0x08, 0xf2, 0x2d, 0xdc, 0x4b, 0xe8, 0x95, 0x9f, 0xf1, 0x40, 0x24, 0x49, 0xa4,
+	0x35, 0x0b, 0x7d, 0xc6, 0xb5, 0x8c, 0x98, 0x72, 0x6b, 0x1d, 0xa7, 0xbb, 0xe4, 0xdd, 0x4d, 0xe8,
+	0xd5, 0xdb, 0x99, 0xb7, 0x6f, 0x9d, 0xe4, 0x15, 0xac, 0x47, 0x5c, 0x69, 0x1a, 0xc7, 0xa5, 0x8e,
+	0xea, 0xd5, 0x4a, 0x69, 0xe4, 0xbc, 0x42, 0x46, 0x5f, 0x41, 0x8b, 0xa6, 0x69, 0x3c, 0xf5, 0x53,
+	0x2a, 0x69, 0x1c, 0xb3, 0x38, 0x52, 0x89, 0x0b, 0x1d, 0xa7, 0xbb, 0xe6, 0x35, 0xd1, 0x71, 0x36,
+	0xb3, 0x93, 0xcf, 0x00, 0x82, 0x38, 0x53, 0x9a, 0x49, 0x3f, 0x0a, 0xdd, 0x95, 0x8e, 0xd3, 0xad,
+	0x7b, 0xf5, 0xdc, 0x72, 0x14, 0x92, 0x6f, 0x60, 0xcb, 0x54, 0x43, 0xd3, 0x94, 0xf1, 0xd0, 0xff,
+	0x2e, 0x63, 0x19, 0xf3, 0x87, 0x53, 0xcd, 0x94, 0xbb, 0x8a, 0xc5, 0x6c, 0x24, 0xf4, 0x6a, 0x0f,
+	0x9d, 0x6f, 0x8c, 0x6f, 0xdf, 0xb8, 0xc8, 0x4f, 0x81, 0xcc, 0x91, 0x2e, 0x85, 0xbc, 0x60, 0x52,
+	0xb9, 0x6b, 0x36, 0x83, 0x92, 0xf0, 0xce, 0xda, 0x49, 0x17, 0x6c, 0x56, 0x79, 0x74, 0x15, 0x7d,
+	0xcf, 0xdc, 0x06, 0x62, 0x1b, 0x68, 0xc7, 0xc0, 0x83, 0xe8, 0x7b, 0x46, 0x5e, 0x03, 0x89, 0xc2,
+	0x98, 0xf9, 0x5c, 0x88, 0x74, 0x26, 0xa4, 0x66, 0xb5, 0x2e, 0x35, 0x0d, 0xf5, 0x44, 0x88, 0xb4,
+	0x14, 0xd1, 0x1b, 0xd8, 0x1c, 0xd1, 0x28, 0xce, 0x24, 0xf3, 0x63, 0x31, 0x9e, 0x05, 0x6c, 0x55,
+	0x0b, 0x48, 0x72, 0xf2, 0xb1, 0x18, 0x97, 0x21, 0x0f, 0x61, 0xcd, 0x6a, 0xd2, 0xbf, 0xa4, 0x32,
+	0xc9, 0x52, 0x97, 0x54, 0x8b, 0xb5, 0x6a, 0x59, 0xef, 0x90, 0x64, 0xa4, 0xa0, 0x34, 0xd5, 0x99,
+	0x9a, 0xe5, 0xb4, 0x51, 0x51, 0x0a, 0x96, 0x57, 0xe6, 0xf3, 0x73, 0x30, 0x6a, 0xf3, 0xad, 0xd8,
+	0xfc, 0x21, 0xd5, 0xc1, 0xc4, 0x36, 0x78, 0x13, 0x1b, 0x6c, 0x8e, 0xe9, 0x00, 0x7d, 0xfb, 0xc6,
+	0x85, 0x4d, 0xfe, 0x0a, 0x88, 0xd2, 0x2c, 0xf5, 0x43, 0x71, 0xc9, 0x7d, 0xc1, 0xfd, 0x11, 0xcd,
+	0x62, 0xed, 0xde, 0x45, 0xdd, 0xaf, 0x1b, 0xcf, 0xa1, 0xb8, 0xe4, 0xa7, 0xfc, 0xa5, 0x31, 0x93,
+	0x47, 0xb0, 0x2a, 0x59, 0x4c, 0xa7, 0xfe, 0x88, 0x72, 0xa3, 0xd8, 0x2d, 0x0c, 0xbb, 0x82, 0xb6,
+	0x97, 0x68, 0x22, 0x0f, 0xa0, 0x2e, 0x86, 0x8a, 0xc9, 0xf7, 0x46, 0x03, 0xf7, 0x3a, 0x8b, 0x46,
+	0x5f, 0xa5, 0x81, 0xfc, 0x0c, 0x36, 0x4d, 0x82, 0xe5, 0x08, 0x2d, 0xc4, 0xe2, 0x96, 0xf9, 0xf5,
+	0x73, 0x57, 0x21, 0x97, 0x0e, 0xac, 0x1a, 0x86, 0x66, 0x32, 0xf1, 0xc7, 0x34, 0x75, 0xef, 0xa3,
+	0x0e, 0x21, 0xa1, 0x57, 0xe7, 0x4c, 0x26, 0xbf, 0xa2, 0x29, 0x79, 0x0a, 0x2d, 0x4c, 0xda, 0x64,
+	0x5f, 0xc2, 0xb6, 0xb1, 0x80, 0x06, 0x3a, 0x4e, 0x79, 0x01, 0x1d, 0xc0, 0x5d, 0x15, 0x8b, 0xcb,
+	0x42, 0xaa, 0x7a, 0x22, 0x99, 0x9a, 0x88, 0x38, 0x74, 0x7f, 0x52, 0xad, 0xdf, 0x1b, 0x86, 0x6d,
+	0xe5, 0x7c, 0x5e, 0x70, 0xc9, 0x33, 0x68, 0xa5, 0x52, 0x0c, 0x99, 0xf9, 0xbe, 0x64, 0x81, 0x78,
+	0xcf, 0xe4, 0xd4, 0x7d, 0x60, 0x1b, 0x88, 0x8e, 0x53, 0xee, 0xe5, 0x66, 0xf2, 0x47, 0xd8, 0x8e,
+	0xa9, 0xd2, 0x26, 0x81, 0x38, 0x62, 0xa1, 0xaf, 0xa6, 0x3c, 0x98, 0x9d, 0xfa, 0x67, 0xd5, 0xb2,
+	0xb8, 0x67, 0x42, 0xec, 0xd9, 0x08, 0x83, 0x29, 0x0f, 0xca, 0xe3, 0x7f, 0x0b, 0x5b, 0xf9, 0xd1,
+	0xe7, 0x83, 0xa5, 0xac, 0xaf, 0x5d, 0x71, 0xfa, 0x5a, 0xfa, 0x00, 0xc7, 0x4b, 0x59, 0xe0, 0x09,
+	0x34, 0x25, 0xa3, 0xa1, 0x1f, 0x08, 0x6e, 0xa6, 0x20, 0xe3, 0xc1, 0xd4, 0x7d, 0xd8, 0x71, 0xba,
+	0x8d, 0xe7, 0x9f, 0x7f, 0xea, 0x81, 0xf0, 0x18, 0x0d, 0x0f, 0x66, 0x50, 0x6f, 0x5d, 0xde, 0x34,
+	0xe4, 0x7a, 0x97, 0x3a, 0x4b, 0xcb, 0xd1, 0xd7, 0xa9, 0xae, 0x77, 0xc3, 0x2b, 0x46, 0xdf, 0x03,
+	0xa8, 0x5f, 0x46, 0x9a, 0x33, 0xa5, 0x98, 0x72, 0x1f, 0x59, 0xb1, 0x95, 0x06, 0xf2, 0x04, 0x1a,
+	0xa3, 0x98, 0xa6, 0x73, 0x6d, 0x78, 0x8c, 0x32, 0x5b, 0x33, 0xd6, 0x59, 0x79, 0x2f, 0x60, 0x05,
+	0x61, 0x97, 0x11, 0x0f, 0xc5, 0xa5, 0xfb, 0x79, 0xb5, 0x54, 0xc0, 0x70, 0xde, 0x21, 0x85, 0xec,
+	0xc3, 0x2a, 0x46, 0x18, 0xd2, 0xe0, 0x42, 0x8c, 0x46, 0xee, 0x4e, 0xb5, 0x10, 0xf8, 0xd9, 0x7d,
+	0xcb, 0x31, 0xcb, 0x40, 0x42, 0x8d, 0x12, 0x38, 0xe5, 0x01, 0x2b, 0x92, 0x79, 0x52, 0x71, 0x19,
+	0x98, 0xa3, 0xe6, 0x39, 0xbd, 0x80, 0x15, 0x49, 0x75, 0x19, 0xe8, 0x8b, 0x8a, 0x55, 0x19, 0x4e,
+	0x1e, 0x21, 0x7f, 0xd9, 0xe6, 0xdf, 0xc5, 0xe2, 0xba, 0x7e, 0x89, 0x7d, 0x34, 0xb3, 0x66, 0xee,
+	0x49, 0x2c, 0x6e, 0xec, 0x2f, 0xec, 0x1b, 0x62, 0x2e, 0x49, 0xc4, 0xc7, 0x7e, 0xb9, 0xa7, 0x28,
+	0xb7, 0x8b, 0x34, 0x33, 0x01, 0xce, 0xac, 0xf3, 0x55, 0xe9, 0x23, 0xbf, 0x07, 0x17, 0x45, 0xa6,
+	0x25, 0xe5, 0x8a, 0xde, 0x5c, 0xb0, 0x9e, 0x56, 0x4b, 0x7e, 0xcb, 0x04, 0x38, 0x9f, 0xf1, 0x73,
+	0x95, 0x3c, 0xfe, 0x61, 0x11, 0xd6, 0x6e, 0x6c, 0x3d, 0x46, 0x37, 0x61, 0x24, 0x59, 0xa0, 0x85,
+	0x9c, 0xe2, 0xfa, 0x56, 0xf7, 0x66, 0x06, 0xf2, 0x2d, 0xdc, 0x8e, 0xd9, 0x7b, 0x66, 0x57, 0xb1,
+	0xc6, 0xf3, 0xce, 0xff, 0xd9, 0xa2, 0x8e, 0x0d, 0xce, 0xb3, 0x70, 0xb2, 0x03, 0x0d, 0x1c, 0x6e,
+	0x5c, 0xcb, 0xa9, 0x1d, 0xbb, 0x8b, 0x58, 0xb0, 0x19, 0x60, 0xe6, 0xd9, 0x9f, 0xe2, 0xc0, 0x7d,
+	0x04, 0xab, 0x8a, 0x8d, 0x13, 0xc6, 0xb5, 0xc5, 0x2c, 0xd9, 0x19, 0x9a, 0xdb, 0x10, 0xf2, 0x05,
+	0xac, 0x8f, 0xe2, 0x4c, 0x4d, 0xcc, 0x44, 0xb1, 0x37, 0x12, 0xd7, 0xa7, 0x9a, 0x51, 0x6e, 0xa6,
+	0x26, 0xa7, 0xdc, 0x0e, 0x71, 0xf2, 0x35, 0x6c, 0x98, 0xb5, 0x68, 0x24, 0x19, 0xf3, 0xc3, 0x48,
+	0x5d, 0xf8, 0x2a, 0xa5, 0x01, 0xc3, 0x95, 0x68, 0xc9, 0x6b, 0x26, 0x11, 0x7f, 0x29, 0x19, 0x3b,
+	0x8c, 0xd4, 0xc5, 0xc0, 0xd8, 0xc9, 0x7d, 0xa8, 0x85, 0x54, 0x53, 0x3f, 0x8c, 0x24, 0x2e, 0x36,
+	0x75, 0x6f, 0xd9, 0xfc, 0x7f, 0x18, 0x49, 0xf3, 0x36, 0x26, 0x4c, 0x53, 0x74, 0xe3, 0x4c, 0xca,
+	0x65, 0x53, 0xab, 0xf8, 0x36, 0x16, 0x64, 0x33, 0x8e, 0x72, 0xf9, 0x9c, 0xc2, 0x06, 0xe6, 0x14,
+	0x4c, 0x58, 0x70, 0x31, 0x9b, 0x71, 0x15, 0x97, 0x9c, 0x96, 0xe1, 0x1e, 0x18, 0x6a, 0x31, 0xdd,
+	0x1e, 0xff, 0x69, 0x09, 0x9a, 0x1f, 0x2f, 0x9f, 0xc4, 0x85, 0xe5, 0x70, 0xca, 0x69, 0x12, 0x05,
+	0x78, 0x8e, 0x35, 0xaf, 0xf8, 0xd7, 0xec, 0x19, 0xb3, 0xc6, 0x0c, 0xb3, 0xd1, 0x88, 0x49, 0x3c,
+	0xd0, 0x5b, 0x5e, 0x63, 0x94, 0xb7, 0x65, 0x1f, 0xad, 0x66, 0x7f, 0x41, 0x64, 0xc2, 0x12, 0x21,
+	0xa7, 0x05, 0x76, 0x11, 0xb1, 0x18, 0xe3, 0x35, 0x3a, 0x72, 0xf4, 0xd7, 0x40, 0x14, 0xa7, 0xa9,
+	0x9a, 0x08, 0x3d, 0x37, 0x59, 0x96, 0xb0, 0xe7, 0xad, 0xc2, 0x33, 0x9b, 0x2e, 0x5f, 0xc2, 0x3a,
+	0xc5, 0x8e, 0x16, 0x2e, 0x95, 0x9f, 0x65, 0x03, 0xcd, 0x83, 0xc2, 0x4a, 0x9e, 0x9a, 0x29, 0xab,
+	0x69, 0xc4, 0xe7, 0x36, 0x48, 0x7b, 0x92, 0xeb, 0x85, 0xbd, 0xd8, 0x1d, 0x9f, 0x40, 0xa3, 0x84,
+	0xda, 0xed, 0x6c, 0x19, 0x81, 0x6b, 0x85, 0xd5, 0xee, 0x65, 0x3d, 0xd8, 0x90, 0x4c, 0x69, 0x21,
+	0x59, 0x5e, 0x93, 0x15, 0x5c, 0x0d, 0x05, 0xd7, 0xca, 0x5d, 0xb6, 0x2a, 0x94, 0xdd, 0x33, 0x68,
+	0x19, 0xfd, 0x96, 0xd5, 0x21, 0xba, 0x6e, 0x53, 0x48, 0xe8, 0x55, 0x91, 0x2a, 0x62, 0xbb, 0xd0,
+	0x2c, 0x71, 0x82, 0xfb, 0x4a, 0x8b, 0x14, 0x77, 0xce, 0x9a, 0xd7, 0x28, 0xec, 0xa7, 0x7c, 0xa0,
+	0x45, 0x4a, 0x7e, 0x07, 0xee, 0xc7, 0xc8, 0xf2, 0x62, 0xaf, 0x54, 0x5c, 0xde, 0x6f, 0x86, 0xcc,
+	0xef, 0xf5, 0xb3, 0x1d, 0x58, 0x9d, 0xbf, 0x86, 0xa4, 0x06, 0x4b, 0x87, 0x47, 0x83, 0xdf, 0x34,
+	0x17, 0x08, 0xc0, 0x9d, 0xd7, 0x7b, 0x67, 0x67, 0xfd, 0xc3, 0xa6, 0xf3, 0xec, 0xb7, 0xb0, 0xfe,
+	0xd1, 0x8b, 0x44, 0x1a, 0x00, 0x83, 0xfe, 0x9b, 0xb7, 0xfd, 0x93, 0xf3, 0xa3, 0xbd, 0xe3, 0xe6,
+	0x02, 0xd9, 0x02, 0x72, 0x7c, 0x74, 0xd2, 0xdf, 0xf3, 0x8e, 0xfe, 0xb0, 0xb7, 0x7f, 0xdc, 0xf7,
+	0x8f, 0xfb, 0x7b, 0x83, 0x7e, 0xd3, 0x21, 0x4d, 0x58, 0x9d, 0xb7, 0x37, 0x6f, 0x91, 0x3a, 0xdc,
+	0x1e, 0x9c, 0xef, 0x1d, 0xf7, 0x9b, 0x8b, 0xfb, 0x3b, 0xff, 0xf9, 0x77, 0xdb, 0xf9, 0xeb, 0x75,
+	0xdb, 0xf9, 0xdb, 0x75, 0xdb, 0xf9, 0xfb, 0x75, 0xdb, 0xf9, 0xf1, 0xba, 0xed, 0xfc, 0xeb, 0xba,
+	0xed, 0xfc, 0xf9, 0x43, 0x7b, 0xe1, 0xc7, 0x0f, 0xed, 0x85, 0x7f, 0x7c, 0x68, 0x2f, 0x0c, 0xef,
+	0x60, 0x4d, 0xdf, 0xfc, 0x77, 0x00, 0xd5, 0x2d, 0xca, 0x25, 0xb6, 0x0e, 0x00, 0x00,
 }
 
 func (this *ProtocolConfig) Equal(that interface{}) bool {
@@ -345,10 +831,224 @@ func (this *ProtocolConfig) Equal(that interface{}) bool {
 	if !this.Compaction.Equal(that1.Compaction) {
 		return false
 	}
-	return true
-}
-func (this *StorageConfig) Equal(that interface{}) bool {
-	if that == nil {
+	if this.QuorumHealthInterval != nil && that1.QuorumHealthInterval != nil {
+		if *this.QuorumHealthInterval != *that1.QuorumHealthInterval {
+			return false
+		}
+	} else if this.QuorumHealthInterval != nil {
+		return false
+	} else if that1.QuorumHealthInterval != nil {
+		return false
+	}
+	if this.MinLeadershipDuration != nil && that1.MinLeadershipDuration != nil {
+		if *this.MinLeadershipDuration != *that1.MinLeadershipDuration {
+			return false
+		}
+	} else if this.MinLeadershipDuration != nil {
+		return false
+	} else if that1.MinLeadershipDuration != nil {
+		return false
+	}
+	if this.PersistReplicationProgress != that1.PersistReplicationProgress {
+		return false
+	}
+	if this.MaxUncommittedEntries != that1.MaxUncommittedEntries {
+		return false
+	}
+	if this.InstallTimeout != nil && that1.InstallTimeout != nil {
+		if *this.InstallTimeout != *that1.InstallTimeout {
+			return false
+		}
+	} else if this.InstallTimeout != nil {
+		return false
+	} else if that1.InstallTimeout != nil {
+		return false
+	}
+	if this.ApplyParallelism != that1.ApplyParallelism {
+		return false
+	}
+	if this.ClusterId != that1.ClusterId {
+		return false
+	}
+	if this.MaxAppendQueueBytes != that1.MaxAppendQueueBytes {
+		return false
+	}
+	if this.MaxAppendWorkers != that1.MaxAppendWorkers {
+		return false
+	}
+	if this.ApplyQueueSize != that1.ApplyQueueSize {
+		return false
+	}
+	if this.IdleNoopInterval != nil && that1.IdleNoopInterval != nil {
+		if *this.IdleNoopInterval != *that1.IdleNoopInterval {
+			return false
+		}
+	} else if this.IdleNoopInterval != nil {
+		return false
+	} else if that1.IdleNoopInterval != nil {
+		return false
+	}
+	if this.FailureLogInterval != nil && that1.FailureLogInterval != nil {
+		if *this.FailureLogInterval != *that1.FailureLogInterval {
+			return false
+		}
+	} else if this.FailureLogInterval != nil {
+		return false
+	} else if that1.FailureLogInterval != nil {
+		return false
+	}
+	if this.LeaderWarmup != nil && that1.LeaderWarmup != nil {
+		if *this.LeaderWarmup != *that1.LeaderWarmup {
+			return false
+		}
+	} else if this.LeaderWarmup != nil {
+		return false
+	} else if that1.LeaderWarmup != nil {
+		return false
+	}
+	if this.StatusInterval != nil && that1.StatusInterval != nil {
+		if *this.StatusInterval != *that1.StatusInterval {
+			return false
+		}
+	} else if this.StatusInterval != nil {
+		return false
+	} else if that1.StatusInterval != nil {
+		return false
+	}
+	if this.MaxCommitBatchSize != that1.MaxCommitBatchSize {
+		return false
+	}
+	if this.StepDownOnFault != that1.StepDownOnFault {
+		return false
+	}
+	if this.RelayFanout != that1.RelayFanout {
+		return false
+	}
+	if len(this.Observers) != len(that1.Observers) {
+		return false
+	}
+	for i := range this.Observers {
+		if this.Observers[i] != that1.Observers[i] {
+			return false
+		}
+	}
+	if this.MaxElectionWorkers != that1.MaxElectionWorkers {
+		return false
+	}
+	if this.MaxTermGap != that1.MaxTermGap {
+		return false
+	}
+	if this.FaultOnTermGap != that1.FaultOnTermGap {
+		return false
+	}
+	if this.SlowAppendThreshold != nil && that1.SlowAppendThreshold != nil {
+		if *this.SlowAppendThreshold != *that1.SlowAppendThreshold {
+			return false
+		}
+	} else if this.SlowAppendThreshold != nil {
+		return false
+	} else if that1.SlowAppendThreshold != nil {
+		return false
+	}
+	if this.ProbeOnRecovery != that1.ProbeOnRecovery {
+		return false
+	}
+	if this.LastAppliedSyncInterval != nil && that1.LastAppliedSyncInterval != nil {
+		if *this.LastAppliedSyncInterval != *that1.LastAppliedSyncInterval {
+			return false
+		}
+	} else if this.LastAppliedSyncInterval != nil {
+		return false
+	} else if that1.LastAppliedSyncInterval != nil {
+		return false
+	}
+	if this.CommitStallThreshold != nil && that1.CommitStallThreshold != nil {
+		if *this.CommitStallThreshold != *that1.CommitStallThreshold {
+			return false
+		}
+	} else if this.CommitStallThreshold != nil {
+		return false
+	} else if that1.CommitStallThreshold != nil {
+		return false
+	}
+	if this.ReadConsistency != that1.ReadConsistency {
+		return false
+	}
+	if this.StartupTimeout != nil && that1.StartupTimeout != nil {
+		if *this.StartupTimeout != *that1.StartupTimeout {
+			return false
+		}
+	} else if this.StartupTimeout != nil {
+		return false
+	} else if that1.StartupTimeout != nil {
+		return false
+	}
+	if len(this.Witnesses) != len(that1.Witnesses) {
+		return false
+	}
+	for i := range this.Witnesses {
+		if this.Witnesses[i] != that1.Witnesses[i] {
+			return false
+		}
+	}
+	if this.FlapThreshold != that1.FlapThreshold {
+		return false
+	}
+	if this.FlapWindow != nil && that1.FlapWindow != nil {
+		if *this.FlapWindow != *that1.FlapWindow {
+			return false
+		}
+	} else if this.FlapWindow != nil {
+		return false
+	} else if that1.FlapWindow != nil {
+		return false
+	}
+	if this.FlapBackoff != nil && that1.FlapBackoff != nil {
+		if *this.FlapBackoff != *that1.FlapBackoff {
+			return false
+		}
+	} else if this.FlapBackoff != nil {
+		return false
+	} else if that1.FlapBackoff != nil {
+		return false
+	}
+	if this.MaintenanceWindow != nil && that1.MaintenanceWindow != nil {
+		if *this.MaintenanceWindow != *that1.MaintenanceWindow {
+			return false
+		}
+	} else if this.MaintenanceWindow != nil {
+		return false
+	} else if that1.MaintenanceWindow != nil {
+		return false
+	}
+	if this.RateWindow != nil && that1.RateWindow != nil {
+		if *this.RateWindow != *that1.RateWindow {
+			return false
+		}
+	} else if this.RateWindow != nil {
+		return false
+	} else if that1.RateWindow != nil {
+		return false
+	}
+	if this.MaxReplicationWorkers != that1.MaxReplicationWorkers {
+		return false
+	}
+	if this.MaxPendingHeartbeats != that1.MaxPendingHeartbeats {
+		return false
+	}
+	if this.ReadTransactionTimeout != nil && that1.ReadTransactionTimeout != nil {
+		if *this.ReadTransactionTimeout != *that1.ReadTransactionTimeout {
+			return false
+		}
+	} else if this.ReadTransactionTimeout != nil {
+		return false
+	} else if that1.ReadTransactionTimeout != nil {
+		return false
+	}
+	return true
+}
+func (this *StorageConfig) Equal(that interface{}) bool {
+	if that == nil {
 		return this == nil
 	}
 
@@ -381,6 +1081,30 @@ func (this *StorageConfig) Equal(that interface{}) bool {
 	if this.FlushOnCommit != that1.FlushOnCommit {
 		return false
 	}
+	if this.MinFreeDiskSpace != that1.MinFreeDiskSpace {
+		return false
+	}
+	if this.DataDir != that1.DataDir {
+		return false
+	}
+	if this.MetadataSyncWindow != nil && that1.MetadataSyncWindow != nil {
+		if *this.MetadataSyncWindow != *that1.MetadataSyncWindow {
+			return false
+		}
+	} else if this.MetadataSyncWindow != nil {
+		return false
+	} else if that1.MetadataSyncWindow != nil {
+		return false
+	}
+	if this.DiskCheckInterval != nil && that1.DiskCheckInterval != nil {
+		if *this.DiskCheckInterval != *that1.DiskCheckInterval {
+			return false
+		}
+	} else if this.DiskCheckInterval != nil {
+		return false
+	} else if that1.DiskCheckInterval != nil {
+		return false
+	}
 	return true
 }
 func (this *CompactionConfig) Equal(that interface{}) bool {
@@ -411,6 +1135,36 @@ func (this *CompactionConfig) Equal(that interface{}) bool {
 	if this.FreeMemoryBuffer != that1.FreeMemoryBuffer {
 		return false
 	}
+	if this.SnapshotThreshold != that1.SnapshotThreshold {
+		return false
+	}
+	if this.AsyncSnapshots != that1.AsyncSnapshots {
+		return false
+	}
+	if this.RetainedEntries != that1.RetainedEntries {
+		return false
+	}
+	if this.RetainedBytes != that1.RetainedBytes {
+		return false
+	}
+	if this.RestoreBufferSize != that1.RestoreBufferSize {
+		return false
+	}
+	if this.MaxSnapshotSize != that1.MaxSnapshotSize {
+		return false
+	}
+	if this.SnapshotOnStop != that1.SnapshotOnStop {
+		return false
+	}
+	if this.SnapshotOnStopTimeout != nil && that1.SnapshotOnStopTimeout != nil {
+		if *this.SnapshotOnStopTimeout != *that1.SnapshotOnStopTimeout {
+			return false
+		}
+	} else if this.SnapshotOnStopTimeout != nil {
+		return false
+	} else if that1.SnapshotOnStopTimeout != nil {
+		return false
+	}
 	return true
 }
 func (m *ProtocolConfig) Marshal() (dAtA []byte, err error) {
@@ -433,106 +1187,480 @@ func (m *ProtocolConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	if m.Compaction != nil {
-		{
-			size, err := m.Compaction.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintConfig(dAtA, i, uint64(size))
+	if m.ReadTransactionTimeout != nil {
+		n1, err1 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.ReadTransactionTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.ReadTransactionTimeout):])
+		if err1 != nil {
+			return 0, err1
 		}
+		i -= n1
+		i = encodeVarintConfig(dAtA, i, uint64(n1))
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xca
 	}
-	if m.Storage != nil {
-		{
-			size, err := m.Storage.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintConfig(dAtA, i, uint64(size))
+	if m.MaxPendingHeartbeats != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxPendingHeartbeats))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xc0
+	}
+	if m.MaxReplicationWorkers != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxReplicationWorkers))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xb8
+	}
+	if m.RateWindow != nil {
+		n2, err2 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.RateWindow, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.RateWindow):])
+		if err2 != nil {
+			return 0, err2
 		}
+		i -= n2
+		i = encodeVarintConfig(dAtA, i, uint64(n2))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xb2
 	}
-	if m.HeartbeatInterval != nil {
-		n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.HeartbeatInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.HeartbeatInterval):])
+	if m.MaintenanceWindow != nil {
+		n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.MaintenanceWindow, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MaintenanceWindow):])
 		if err3 != nil {
 			return 0, err3
 		}
 		i -= n3
 		i = encodeVarintConfig(dAtA, i, uint64(n3))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xaa
 	}
-	if m.ElectionTimeout != nil {
-		n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.ElectionTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.ElectionTimeout):])
+	if m.FlapBackoff != nil {
+		n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.FlapBackoff, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FlapBackoff):])
 		if err4 != nil {
 			return 0, err4
 		}
 		i -= n4
 		i = encodeVarintConfig(dAtA, i, uint64(n4))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xa2
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *StorageConfig) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.FlapWindow != nil {
+		n5, err5 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.FlapWindow, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FlapWindow):])
+		if err5 != nil {
+			return 0, err5
+		}
+		i -= n5
+		i = encodeVarintConfig(dAtA, i, uint64(n5))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x9a
 	}
-	return dAtA[:n], nil
-}
-
-func (m *StorageConfig) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *StorageConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.FlushOnCommit {
+	if m.FlapThreshold != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.FlapThreshold))
 		i--
-		if m.FlushOnCommit {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x90
+	}
+	if len(m.Witnesses) > 0 {
+		for iNdEx := len(m.Witnesses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Witnesses[iNdEx])
+			copy(dAtA[i:], m.Witnesses[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.Witnesses[iNdEx])))
+			i--
+			dAtA[i] = 0x2
+			i--
+			dAtA[i] = 0x8a
+		}
+	}
+	if m.StartupTimeout != nil {
+		n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.StartupTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.StartupTimeout):])
+		if err6 != nil {
+			return 0, err6
 		}
+		i -= n6
+		i = encodeVarintConfig(dAtA, i, uint64(n6))
 		i--
-		dAtA[i] = 0x28
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x82
 	}
-	if m.SegmentSize != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.SegmentSize))
+	if m.ReadConsistency != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ReadConsistency))
 		i--
-		dAtA[i] = 0x20
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xf8
 	}
-	if m.MaxEntrySize != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.MaxEntrySize))
+	if m.CommitStallThreshold != nil {
+		n7, err7 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.CommitStallThreshold, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.CommitStallThreshold):])
+		if err7 != nil {
+			return 0, err7
+		}
+		i -= n7
+		i = encodeVarintConfig(dAtA, i, uint64(n7))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xf2
 	}
-	if m.Level != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.Level))
+	if m.LastAppliedSyncInterval != nil {
+		n8, err8 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.LastAppliedSyncInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.LastAppliedSyncInterval):])
+		if err8 != nil {
+			return 0, err8
+		}
+		i -= n8
+		i = encodeVarintConfig(dAtA, i, uint64(n8))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xea
 	}
-	if len(m.Directory) > 0 {
-		i -= len(m.Directory)
-		copy(dAtA[i:], m.Directory)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Directory)))
+	if m.ProbeOnRecovery {
 		i--
-		dAtA[i] = 0xa
+		if m.ProbeOnRecovery {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe0
 	}
-	return len(dAtA) - i, nil
+	if m.SlowAppendThreshold != nil {
+		n9, err9 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.SlowAppendThreshold, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SlowAppendThreshold):])
+		if err9 != nil {
+			return 0, err9
+		}
+		i -= n9
+		i = encodeVarintConfig(dAtA, i, uint64(n9))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xda
+	}
+	if m.FaultOnTermGap {
+		i--
+		if m.FaultOnTermGap {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd0
+	}
+	if m.MaxTermGap != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxTermGap))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc8
+	}
+	if m.MaxElectionWorkers != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxElectionWorkers))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc0
+	}
+	if len(m.Observers) > 0 {
+		for iNdEx := len(m.Observers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Observers[iNdEx])
+			copy(dAtA[i:], m.Observers[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.Observers[iNdEx])))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xba
+		}
+	}
+	if m.RelayFanout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RelayFanout))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
+	if m.StepDownOnFault {
+		i--
+		if m.StepDownOnFault {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa8
+	}
+	if m.MaxCommitBatchSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxCommitBatchSize))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa0
+	}
+	if m.StatusInterval != nil {
+		n10, err10 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.StatusInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.StatusInterval):])
+		if err10 != nil {
+			return 0, err10
+		}
+		i -= n10
+		i = encodeVarintConfig(dAtA, i, uint64(n10))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if m.LeaderWarmup != nil {
+		n11, err11 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.LeaderWarmup, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.LeaderWarmup):])
+		if err11 != nil {
+			return 0, err11
+		}
+		i -= n11
+		i = encodeVarintConfig(dAtA, i, uint64(n11))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if m.FailureLogInterval != nil {
+		n12, err12 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.FailureLogInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FailureLogInterval):])
+		if err12 != nil {
+			return 0, err12
+		}
+		i -= n12
+		i = encodeVarintConfig(dAtA, i, uint64(n12))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if m.IdleNoopInterval != nil {
+		n13, err13 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.IdleNoopInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.IdleNoopInterval):])
+		if err13 != nil {
+			return 0, err13
+		}
+		i -= n13
+		i = encodeVarintConfig(dAtA, i, uint64(n13))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if m.ApplyQueueSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ApplyQueueSize))
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.MaxAppendWorkers != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxAppendWorkers))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.MaxAppendQueueBytes != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxAppendQueueBytes))
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.ClusterId) > 0 {
+		i -= len(m.ClusterId)
+		copy(dAtA[i:], m.ClusterId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ClusterId)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.ApplyParallelism != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ApplyParallelism))
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.InstallTimeout != nil {
+		n14, err14 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.InstallTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.InstallTimeout):])
+		if err14 != nil {
+			return 0, err14
+		}
+		i -= n14
+		i = encodeVarintConfig(dAtA, i, uint64(n14))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.MaxUncommittedEntries != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxUncommittedEntries))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.PersistReplicationProgress {
+		i--
+		if m.PersistReplicationProgress {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.MinLeadershipDuration != nil {
+		n15, err15 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.MinLeadershipDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MinLeadershipDuration):])
+		if err15 != nil {
+			return 0, err15
+		}
+		i -= n15
+		i = encodeVarintConfig(dAtA, i, uint64(n15))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.QuorumHealthInterval != nil {
+		n16, err16 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.QuorumHealthInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.QuorumHealthInterval):])
+		if err16 != nil {
+			return 0, err16
+		}
+		i -= n16
+		i = encodeVarintConfig(dAtA, i, uint64(n16))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Compaction != nil {
+		{
+			size, err := m.Compaction.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Storage != nil {
+		{
+			size, err := m.Storage.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.HeartbeatInterval != nil {
+		n19, err19 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.HeartbeatInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.HeartbeatInterval):])
+		if err19 != nil {
+			return 0, err19
+		}
+		i -= n19
+		i = encodeVarintConfig(dAtA, i, uint64(n19))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ElectionTimeout != nil {
+		n20, err20 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.ElectionTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.ElectionTimeout):])
+		if err20 != nil {
+			return 0, err20
+		}
+		i -= n20
+		i = encodeVarintConfig(dAtA, i, uint64(n20))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StorageConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StorageConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StorageConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DiskCheckInterval != nil {
+		n21, err21 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.DiskCheckInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.DiskCheckInterval):])
+		if err21 != nil {
+			return 0, err21
+		}
+		i -= n21
+		i = encodeVarintConfig(dAtA, i, uint64(n21))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.MetadataSyncWindow != nil {
+		n22, err22 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.MetadataSyncWindow, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MetadataSyncWindow):])
+		if err22 != nil {
+			return 0, err22
+		}
+		i -= n22
+		i = encodeVarintConfig(dAtA, i, uint64(n22))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.DataDir) > 0 {
+		i -= len(m.DataDir)
+		copy(dAtA[i:], m.DataDir)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.DataDir)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.MinFreeDiskSpace != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MinFreeDiskSpace))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.FlushOnCommit {
+		i--
+		if m.FlushOnCommit {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.SegmentSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.SegmentSize))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MaxEntrySize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxEntrySize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Level != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Level))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Directory) > 0 {
+		i -= len(m.Directory)
+		copy(dAtA[i:], m.Directory)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Directory)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *CompactionConfig) Marshal() (dAtA []byte, err error) {
@@ -555,6 +1683,61 @@ func (m *CompactionConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SnapshotOnStopTimeout != nil {
+		n23, err23 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.SnapshotOnStopTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SnapshotOnStopTimeout):])
+		if err23 != nil {
+			return 0, err23
+		}
+		i -= n23
+		i = encodeVarintConfig(dAtA, i, uint64(n23))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.SnapshotOnStop {
+		i--
+		if m.SnapshotOnStop {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.MaxSnapshotSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxSnapshotSize))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.RestoreBufferSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RestoreBufferSize))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.RetainedBytes != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RetainedBytes))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.RetainedEntries != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RetainedEntries))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.AsyncSnapshots {
+		i--
+		if m.AsyncSnapshots {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.SnapshotThreshold != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.SnapshotThreshold))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.FreeMemoryBuffer != 0 {
 		i -= 4
 		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.FreeMemoryBuffer))))
@@ -605,6 +1788,82 @@ func NewPopulatedProtocolConfig(r randyConfig, easy bool) *ProtocolConfig {
 	if r.Intn(5) != 0 {
 		this.Compaction = NewPopulatedCompactionConfig(r, easy)
 	}
+	if r.Intn(5) != 0 {
+		this.QuorumHealthInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.MinLeadershipDuration = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.PersistReplicationProgress = bool(bool(r.Intn(2) == 0))
+	this.MaxUncommittedEntries = uint64(uint64(r.Uint32()))
+	if r.Intn(5) != 0 {
+		this.InstallTimeout = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.ApplyParallelism = uint32(r.Uint32())
+	this.ClusterId = string(randStringConfig(r))
+	this.MaxAppendQueueBytes = uint64(uint64(r.Uint32()))
+	this.MaxAppendWorkers = uint32(r.Uint32())
+	this.ApplyQueueSize = uint32(r.Uint32())
+	if r.Intn(5) != 0 {
+		this.IdleNoopInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.FailureLogInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.LeaderWarmup = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.StatusInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.MaxCommitBatchSize = uint32(r.Uint32())
+	this.StepDownOnFault = bool(bool(r.Intn(2) == 0))
+	this.RelayFanout = uint32(r.Uint32())
+	v1 := r.Intn(10)
+	this.Observers = make([]string, v1)
+	for i := 0; i < v1; i++ {
+		this.Observers[i] = string(randStringConfig(r))
+	}
+	this.MaxElectionWorkers = uint32(r.Uint32())
+	this.MaxTermGap = uint64(uint64(r.Uint32()))
+	this.FaultOnTermGap = bool(bool(r.Intn(2) == 0))
+	if r.Intn(5) != 0 {
+		this.SlowAppendThreshold = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.ProbeOnRecovery = bool(bool(r.Intn(2) == 0))
+	if r.Intn(5) != 0 {
+		this.LastAppliedSyncInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.CommitStallThreshold = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.ReadConsistency = ReadConsistency([]int32{0, 1, 2, 3}[r.Intn(4)])
+	if r.Intn(5) != 0 {
+		this.StartupTimeout = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	v2 := r.Intn(10)
+	this.Witnesses = make([]string, v2)
+	for i := 0; i < v2; i++ {
+		this.Witnesses[i] = string(randStringConfig(r))
+	}
+	this.FlapThreshold = uint32(r.Uint32())
+	if r.Intn(5) != 0 {
+		this.FlapWindow = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.FlapBackoff = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.MaintenanceWindow = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.RateWindow = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	this.MaxReplicationWorkers = uint32(r.Uint32())
+	this.MaxPendingHeartbeats = uint32(r.Uint32())
+	if r.Intn(5) != 0 {
+		this.ReadTransactionTimeout = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -617,6 +1876,14 @@ func NewPopulatedStorageConfig(r randyConfig, easy bool) *StorageConfig {
 	this.MaxEntrySize = uint32(r.Uint32())
 	this.SegmentSize = uint32(r.Uint32())
 	this.FlushOnCommit = bool(bool(r.Intn(2) == 0))
+	this.MinFreeDiskSpace = uint64(uint64(r.Uint32()))
+	this.DataDir = string(randStringConfig(r))
+	if r.Intn(5) != 0 {
+		this.MetadataSyncWindow = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.DiskCheckInterval = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -633,6 +1900,16 @@ func NewPopulatedCompactionConfig(r randyConfig, easy bool) *CompactionConfig {
 	if r.Intn(2) == 0 {
 		this.FreeMemoryBuffer *= -1
 	}
+	this.SnapshotThreshold = uint64(uint64(r.Uint32()))
+	this.AsyncSnapshots = bool(bool(r.Intn(2) == 0))
+	this.RetainedEntries = uint64(uint64(r.Uint32()))
+	this.RetainedBytes = uint64(uint64(r.Uint32()))
+	this.RestoreBufferSize = uint32(r.Uint32())
+	this.MaxSnapshotSize = uint64(uint64(r.Uint32()))
+	this.SnapshotOnStop = bool(bool(r.Intn(2) == 0))
+	if r.Intn(5) != 0 {
+		this.SnapshotOnStopTimeout = github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -657,9 +1934,9 @@ func randUTF8RuneConfig(r randyConfig) rune {
 	return rune(ru + 61)
 }
 func randStringConfig(r randyConfig) string {
-	v1 := r.Intn(100)
-	tmps := make([]rune, v1)
-	for i := 0; i < v1; i++ {
+	v3 := r.Intn(100)
+	tmps := make([]rune, v3)
+	for i := 0; i < v3; i++ {
 		tmps[i] = randUTF8RuneConfig(r)
 	}
 	return string(tmps)
@@ -681,11 +1958,11 @@ func randFieldConfig(dAtA []byte, r randyConfig, fieldNumber int, wire int) []by
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateConfig(dAtA, uint64(key))
-		v2 := r.Int63()
+		v4 := r.Int63()
 		if r.Intn(2) == 0 {
-			v2 *= -1
+			v4 *= -1
 		}
-		dAtA = encodeVarintPopulateConfig(dAtA, uint64(v2))
+		dAtA = encodeVarintPopulateConfig(dAtA, uint64(v4))
 	case 1:
 		dAtA = encodeVarintPopulateConfig(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -732,14 +2009,145 @@ func (m *ProtocolConfig) Size() (n int) {
 		l = m.Compaction.Size()
 		n += 1 + l + sovConfig(uint64(l))
 	}
-	return n
-}
-
-func (m *StorageConfig) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.QuorumHealthInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.QuorumHealthInterval)
+		n += 1 + l + sovConfig(uint64(l))
 	}
-	var l int
+	if m.MinLeadershipDuration != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MinLeadershipDuration)
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.PersistReplicationProgress {
+		n += 2
+	}
+	if m.MaxUncommittedEntries != 0 {
+		n += 1 + sovConfig(uint64(m.MaxUncommittedEntries))
+	}
+	if m.InstallTimeout != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.InstallTimeout)
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ApplyParallelism != 0 {
+		n += 1 + sovConfig(uint64(m.ApplyParallelism))
+	}
+	l = len(m.ClusterId)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MaxAppendQueueBytes != 0 {
+		n += 1 + sovConfig(uint64(m.MaxAppendQueueBytes))
+	}
+	if m.MaxAppendWorkers != 0 {
+		n += 1 + sovConfig(uint64(m.MaxAppendWorkers))
+	}
+	if m.ApplyQueueSize != 0 {
+		n += 1 + sovConfig(uint64(m.ApplyQueueSize))
+	}
+	if m.IdleNoopInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.IdleNoopInterval)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.FailureLogInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FailureLogInterval)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.LeaderWarmup != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.LeaderWarmup)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.StatusInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.StatusInterval)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaxCommitBatchSize != 0 {
+		n += 2 + sovConfig(uint64(m.MaxCommitBatchSize))
+	}
+	if m.StepDownOnFault {
+		n += 3
+	}
+	if m.RelayFanout != 0 {
+		n += 2 + sovConfig(uint64(m.RelayFanout))
+	}
+	if len(m.Observers) > 0 {
+		for _, s := range m.Observers {
+			l = len(s)
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.MaxElectionWorkers != 0 {
+		n += 2 + sovConfig(uint64(m.MaxElectionWorkers))
+	}
+	if m.MaxTermGap != 0 {
+		n += 2 + sovConfig(uint64(m.MaxTermGap))
+	}
+	if m.FaultOnTermGap {
+		n += 3
+	}
+	if m.SlowAppendThreshold != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SlowAppendThreshold)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ProbeOnRecovery {
+		n += 3
+	}
+	if m.LastAppliedSyncInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.LastAppliedSyncInterval)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.CommitStallThreshold != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.CommitStallThreshold)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ReadConsistency != 0 {
+		n += 2 + sovConfig(uint64(m.ReadConsistency))
+	}
+	if m.StartupTimeout != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.StartupTimeout)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if len(m.Witnesses) > 0 {
+		for _, s := range m.Witnesses {
+			l = len(s)
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.FlapThreshold != 0 {
+		n += 2 + sovConfig(uint64(m.FlapThreshold))
+	}
+	if m.FlapWindow != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FlapWindow)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.FlapBackoff != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.FlapBackoff)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaintenanceWindow != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MaintenanceWindow)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.RateWindow != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.RateWindow)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MaxReplicationWorkers != 0 {
+		n += 2 + sovConfig(uint64(m.MaxReplicationWorkers))
+	}
+	if m.MaxPendingHeartbeats != 0 {
+		n += 2 + sovConfig(uint64(m.MaxPendingHeartbeats))
+	}
+	if m.ReadTransactionTimeout != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.ReadTransactionTimeout)
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *StorageConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
 	_ = l
 	l = len(m.Directory)
 	if l > 0 {
@@ -757,6 +2165,21 @@ func (m *StorageConfig) Size() (n int) {
 	if m.FlushOnCommit {
 		n += 2
 	}
+	if m.MinFreeDiskSpace != 0 {
+		n += 1 + sovConfig(uint64(m.MinFreeDiskSpace))
+	}
+	l = len(m.DataDir)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MetadataSyncWindow != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.MetadataSyncWindow)
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.DiskCheckInterval != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.DiskCheckInterval)
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -775,6 +2198,31 @@ func (m *CompactionConfig) Size() (n int) {
 	if m.FreeMemoryBuffer != 0 {
 		n += 5
 	}
+	if m.SnapshotThreshold != 0 {
+		n += 1 + sovConfig(uint64(m.SnapshotThreshold))
+	}
+	if m.AsyncSnapshots {
+		n += 2
+	}
+	if m.RetainedEntries != 0 {
+		n += 1 + sovConfig(uint64(m.RetainedEntries))
+	}
+	if m.RetainedBytes != 0 {
+		n += 1 + sovConfig(uint64(m.RetainedBytes))
+	}
+	if m.RestoreBufferSize != 0 {
+		n += 1 + sovConfig(uint64(m.RestoreBufferSize))
+	}
+	if m.MaxSnapshotSize != 0 {
+		n += 1 + sovConfig(uint64(m.MaxSnapshotSize))
+	}
+	if m.SnapshotOnStop {
+		n += 2
+	}
+	if m.SnapshotOnStopTimeout != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SnapshotOnStopTimeout)
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -920,10 +2368,1009 @@ func (m *ProtocolConfig) Unmarshal(dAtA []byte) error {
 			if err := m.Storage.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			iNdEx = postIndex
-		case 4:
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compaction", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Compaction == nil {
+				m.Compaction = &CompactionConfig{}
+			}
+			if err := m.Compaction.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuorumHealthInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QuorumHealthInterval == nil {
+				m.QuorumHealthInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.QuorumHealthInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinLeadershipDuration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MinLeadershipDuration == nil {
+				m.MinLeadershipDuration = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.MinLeadershipDuration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PersistReplicationProgress", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.PersistReplicationProgress = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxUncommittedEntries", wireType)
+			}
+			m.MaxUncommittedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxUncommittedEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstallTimeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.InstallTimeout == nil {
+				m.InstallTimeout = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.InstallTimeout, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApplyParallelism", wireType)
+			}
+			m.ApplyParallelism = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ApplyParallelism |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClusterId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClusterId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAppendQueueBytes", wireType)
+			}
+			m.MaxAppendQueueBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxAppendQueueBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAppendWorkers", wireType)
+			}
+			m.MaxAppendWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxAppendWorkers |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApplyQueueSize", wireType)
+			}
+			m.ApplyQueueSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ApplyQueueSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdleNoopInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.IdleNoopInterval == nil {
+				m.IdleNoopInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.IdleNoopInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailureLogInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FailureLogInterval == nil {
+				m.FailureLogInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.FailureLogInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaderWarmup", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LeaderWarmup == nil {
+				m.LeaderWarmup = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.LeaderWarmup, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StatusInterval == nil {
+				m.StatusInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.StatusInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCommitBatchSize", wireType)
+			}
+			m.MaxCommitBatchSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxCommitBatchSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StepDownOnFault", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.StepDownOnFault = bool(v != 0)
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RelayFanout", wireType)
+			}
+			m.RelayFanout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RelayFanout |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Observers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Observers = append(m.Observers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxElectionWorkers", wireType)
+			}
+			m.MaxElectionWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxElectionWorkers |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 25:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxTermGap", wireType)
+			}
+			m.MaxTermGap = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxTermGap |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 26:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FaultOnTermGap", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FaultOnTermGap = bool(v != 0)
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlowAppendThreshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SlowAppendThreshold == nil {
+				m.SlowAppendThreshold = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.SlowAppendThreshold, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProbeOnRecovery", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ProbeOnRecovery = bool(v != 0)
+		case 29:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastAppliedSyncInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LastAppliedSyncInterval == nil {
+				m.LastAppliedSyncInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.LastAppliedSyncInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 30:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitStallThreshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CommitStallThreshold == nil {
+				m.CommitStallThreshold = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.CommitStallThreshold, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 31:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadConsistency", wireType)
+			}
+			m.ReadConsistency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ReadConsistency |= ReadConsistency(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 32:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartupTimeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StartupTimeout == nil {
+				m.StartupTimeout = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.StartupTimeout, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 33:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Witnesses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Witnesses = append(m.Witnesses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 34:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlapThreshold", wireType)
+			}
+			m.FlapThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FlapThreshold |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 35:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlapWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FlapWindow == nil {
+				m.FlapWindow = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.FlapWindow, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 36:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlapBackoff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FlapBackoff == nil {
+				m.FlapBackoff = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.FlapBackoff, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 37:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaintenanceWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaintenanceWindow == nil {
+				m.MaintenanceWindow = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.MaintenanceWindow, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 38:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RateWindow == nil {
+				m.RateWindow = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.RateWindow, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 39:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxReplicationWorkers", wireType)
+			}
+			m.MaxReplicationWorkers = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxReplicationWorkers |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 40:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxPendingHeartbeats", wireType)
+			}
+			m.MaxPendingHeartbeats = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxPendingHeartbeats |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 41:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Compaction", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadTransactionTimeout", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -950,10 +3397,10 @@ func (m *ProtocolConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Compaction == nil {
-				m.Compaction = &CompactionConfig{}
+			if m.ReadTransactionTimeout == nil {
+				m.ReadTransactionTimeout = new(time.Duration)
 			}
-			if err := m.Compaction.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.ReadTransactionTimeout, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1119,6 +3566,129 @@ func (m *StorageConfig) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.FlushOnCommit = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinFreeDiskSpace", wireType)
+			}
+			m.MinFreeDiskSpace = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinFreeDiskSpace |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DataDir", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DataDir = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetadataSyncWindow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MetadataSyncWindow == nil {
+				m.MetadataSyncWindow = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.MetadataSyncWindow, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiskCheckInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DiskCheckInterval == nil {
+				m.DiskCheckInterval = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.DiskCheckInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -1214,6 +3784,177 @@ func (m *CompactionConfig) Unmarshal(dAtA []byte) error {
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
 			m.FreeMemoryBuffer = float32(math.Float32frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotThreshold", wireType)
+			}
+			m.SnapshotThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SnapshotThreshold |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AsyncSnapshots", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AsyncSnapshots = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetainedEntries", wireType)
+			}
+			m.RetainedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RetainedEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetainedBytes", wireType)
+			}
+			m.RetainedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RetainedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RestoreBufferSize", wireType)
+			}
+			m.RestoreBufferSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RestoreBufferSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSnapshotSize", wireType)
+			}
+			m.MaxSnapshotSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSnapshotSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotOnStop", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SnapshotOnStop = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotOnStopTimeout", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SnapshotOnStopTimeout == nil {
+				m.SnapshotOnStopTimeout = new(time.Duration)
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(m.SnapshotOnStopTimeout, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
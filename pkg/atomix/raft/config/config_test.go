@@ -24,13 +24,127 @@ func TestAdditionalConfigFunctions(t *testing.T) {
 	config := &ProtocolConfig{}
 	assert.Equal(t, defaultElectionTimeout, config.GetElectionTimeoutOrDefault())
 	assert.Equal(t, defaultHeartbeatInterval, config.GetHeartbeatIntervalOrDefault())
+	assert.Equal(t, uint64(defaultSnapshotThreshold), config.GetSnapshotThresholdOrDefault())
+	assert.Equal(t, defaultQuorumHealthInterval, config.GetQuorumHealthIntervalOrDefault())
+	assert.Equal(t, time.Duration(defaultMinLeadershipDuration), config.GetMinLeadershipDurationOrDefault())
+	assert.Equal(t, time.Duration(defaultIdleNoopInterval), config.GetIdleNoopIntervalOrDefault())
+	assert.Equal(t, defaultFailureLogInterval, config.GetFailureLogIntervalOrDefault())
+	assert.Equal(t, time.Duration(defaultLeaderWarmup), config.GetLeaderWarmupOrDefault())
+	assert.Equal(t, defaultStatusInterval, config.GetStatusIntervalOrDefault())
+	assert.Equal(t, defaultMaxEntrySize, config.GetMaxEntrySizeOrDefault())
+	assert.Equal(t, defaultElectionTimeout*defaultInstallTimeoutFactor, config.GetInstallTimeoutOrDefault())
+	assert.Equal(t, defaultElectionTimeout, config.GetReadTransactionTimeoutOrDefault())
+	assert.Equal(t, defaultApplyParallelism, config.GetApplyParallelismOrDefault())
+	assert.Equal(t, minAppendWorkers, config.GetMaxAppendWorkersOrDefault())
+	assert.Equal(t, defaultMaxCommitBatchSize, config.GetMaxCommitBatchSizeOrDefault())
+	assert.Equal(t, defaultMaxAppendQueueBytes, config.GetMaxAppendQueueBytesOrDefault())
+	assert.Equal(t, defaultMaxElectionWorkers, config.GetMaxElectionWorkersOrDefault())
+	assert.Equal(t, time.Duration(defaultSlowAppendThreshold), config.GetSlowAppendThresholdOrDefault())
+	assert.Equal(t, defaultRestoreBufferSize, config.GetRestoreBufferSizeOrDefault())
+	assert.Equal(t, defaultLastAppliedSyncInterval, config.GetLastAppliedSyncIntervalOrDefault())
+	assert.Equal(t, time.Duration(defaultCommitStallThreshold), config.GetCommitStallThresholdOrDefault())
+	assert.Equal(t, time.Duration(defaultStartupTimeout), config.GetStartupTimeoutOrDefault())
+	assert.Equal(t, defaultFlapThreshold, config.GetFlapThresholdOrDefault())
+	assert.Equal(t, defaultFlapWindow, config.GetFlapWindowOrDefault())
+	assert.Equal(t, defaultFlapBackoff, config.GetFlapBackoffOrDefault())
+	assert.Equal(t, defaultMaintenanceWindow, config.GetMaintenanceWindowOrDefault())
+	assert.Equal(t, defaultRateWindow, config.GetRateWindowOrDefault())
 
 	electionTimeout := 30 * time.Second
 	heartbeatInterval := 1 * time.Second
 	config = &ProtocolConfig{
-		ElectionTimeout:   &electionTimeout,
-		HeartbeatInterval: &heartbeatInterval,
+		ElectionTimeout:     &electionTimeout,
+		HeartbeatInterval:   &heartbeatInterval,
+		ApplyParallelism:    4,
+		MaxAppendWorkers:    8,
+		MaxCommitBatchSize:  100,
+		MaxElectionWorkers:  4,
+		MaxAppendQueueBytes: 4096,
+		Storage: &StorageConfig{
+			MaxEntrySize: 1024,
+		},
+		Compaction: &CompactionConfig{
+			SnapshotThreshold: 100,
+			RestoreBufferSize: 4096,
+		},
 	}
 	assert.Equal(t, electionTimeout, config.GetElectionTimeoutOrDefault())
 	assert.Equal(t, heartbeatInterval, config.GetHeartbeatIntervalOrDefault())
+	assert.Equal(t, uint64(100), config.GetSnapshotThresholdOrDefault())
+	assert.Equal(t, 4096, config.GetRestoreBufferSizeOrDefault())
+	assert.Equal(t, 1024, config.GetMaxEntrySizeOrDefault())
+	assert.Equal(t, 4, config.GetApplyParallelismOrDefault())
+	assert.Equal(t, 8, config.GetMaxAppendWorkersOrDefault())
+	assert.Equal(t, 100, config.GetMaxCommitBatchSizeOrDefault())
+	assert.Equal(t, 4096, config.GetMaxAppendQueueBytesOrDefault())
+	assert.Equal(t, 4, config.GetMaxElectionWorkersOrDefault())
+	assert.Equal(t, electionTimeout*defaultInstallTimeoutFactor, config.GetInstallTimeoutOrDefault())
+	assert.Equal(t, electionTimeout, config.GetReadTransactionTimeoutOrDefault())
+
+	idleNoopInterval := 5 * time.Second
+	config.IdleNoopInterval = &idleNoopInterval
+	assert.Equal(t, idleNoopInterval, config.GetIdleNoopIntervalOrDefault())
+
+	failureLogInterval := time.Duration(0)
+	config.FailureLogInterval = &failureLogInterval
+	assert.Equal(t, failureLogInterval, config.GetFailureLogIntervalOrDefault())
+
+	leaderWarmup := 500 * time.Millisecond
+	config.LeaderWarmup = &leaderWarmup
+	assert.Equal(t, leaderWarmup, config.GetLeaderWarmupOrDefault())
+
+	slowAppendThreshold := 200 * time.Millisecond
+	config.SlowAppendThreshold = &slowAppendThreshold
+	assert.Equal(t, slowAppendThreshold, config.GetSlowAppendThresholdOrDefault())
+
+	lastAppliedSyncInterval := time.Duration(0)
+	config.LastAppliedSyncInterval = &lastAppliedSyncInterval
+	assert.Equal(t, lastAppliedSyncInterval, config.GetLastAppliedSyncIntervalOrDefault())
+
+	commitStallThreshold := 30 * time.Second
+	config.CommitStallThreshold = &commitStallThreshold
+	assert.Equal(t, commitStallThreshold, config.GetCommitStallThresholdOrDefault())
+
+	startupTimeout := time.Minute
+	config.StartupTimeout = &startupTimeout
+	assert.Equal(t, startupTimeout, config.GetStartupTimeoutOrDefault())
+
+	config.FlapThreshold = 3
+	assert.Equal(t, 3, config.GetFlapThresholdOrDefault())
+
+	flapWindow := 10 * time.Second
+	config.FlapWindow = &flapWindow
+	assert.Equal(t, flapWindow, config.GetFlapWindowOrDefault())
+
+	flapBackoff := 5 * time.Minute
+	config.FlapBackoff = &flapBackoff
+	assert.Equal(t, flapBackoff, config.GetFlapBackoffOrDefault())
+
+	maintenanceWindow := time.Hour
+	config.MaintenanceWindow = &maintenanceWindow
+	assert.Equal(t, maintenanceWindow, config.GetMaintenanceWindowOrDefault())
+
+	rateWindow := time.Minute
+	config.RateWindow = &rateWindow
+	assert.Equal(t, rateWindow, config.GetRateWindowOrDefault())
+
+	statusInterval := 100 * time.Millisecond
+	config.StatusInterval = &statusInterval
+	assert.Equal(t, statusInterval, config.GetStatusIntervalOrDefault())
+
+	installTimeout := 10 * time.Minute
+	config.InstallTimeout = &installTimeout
+	assert.Equal(t, installTimeout, config.GetInstallTimeoutOrDefault())
+
+	assert.Equal(t, time.Duration(0), config.GetMetadataSyncWindowOrDefault())
+	syncWindow := 2 * time.Millisecond
+	config.Storage.MetadataSyncWindow = &syncWindow
+	assert.Equal(t, syncWindow, config.GetMetadataSyncWindowOrDefault())
+	syncWindow = time.Second
+	assert.Equal(t, maxMetadataSyncWindow, config.GetMetadataSyncWindowOrDefault())
+
+	assert.Equal(t, defaultDiskCheckInterval, config.GetDiskCheckIntervalOrDefault())
+	diskCheckInterval := time.Duration(0)
+	config.Storage.DiskCheckInterval = &diskCheckInterval
+	assert.Equal(t, time.Duration(0), config.GetDiskCheckIntervalOrDefault())
 }
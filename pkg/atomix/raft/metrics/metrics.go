@@ -0,0 +1,321 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// registry is the global metrics registry
+var registry = &metricsRegistry{
+	counters:   make(map[string]*Counter),
+	gauges:     make(map[string]*Gauge),
+	histograms: make(map[string]*Histogram),
+	rates:      make(map[string]*Rate),
+}
+
+// NewCounter returns the counter with the given name for the given member, creating it if necessary
+func NewCounter(name string, member string) *Counter {
+	return registry.counter(key(name, member))
+}
+
+// NewGauge returns the gauge with the given name for the given member, creating it if necessary
+func NewGauge(name string, member string) *Gauge {
+	return registry.gauge(key(name, member))
+}
+
+// NewHistogram returns the histogram with the given name for the given member, creating it if necessary
+// The bounds are the sorted inclusive upper bounds of the histogram's buckets. Observations greater than the last
+// bound are counted only in the histogram's total count.
+func NewHistogram(name string, member string, bounds []int64) *Histogram {
+	return registry.histogram(name, member, bounds)
+}
+
+// NewRate returns the rate with the given name for the given member and window, creating it if necessary
+// The rate is measured over the given sliding window. Rates with the same name and member but different windows are
+// distinct metrics, labeled by their windows.
+func NewRate(name string, member string, window time.Duration) *Rate {
+	return registry.rate(fmt.Sprintf("%s{member=%q,window=%q}", name, member, window), window)
+}
+
+// ExponentialBounds returns count bucket bounds starting at start and growing by factor
+func ExponentialBounds(start int64, factor int64, count int) []int64 {
+	bounds := make([]int64, count)
+	for i := range bounds {
+		bounds[i] = start
+		start *= factor
+	}
+	return bounds
+}
+
+// Values returns a snapshot of the values of all registered metrics, keyed by metric name
+func Values() map[string]int64 {
+	return registry.values()
+}
+
+// Names returns the sorted names of all registered metrics
+func Names() []string {
+	values := registry.values()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func key(name string, member string) string {
+	return fmt.Sprintf("%s{member=%q}", name, member)
+}
+
+// metricsRegistry is a registry of named metrics
+type metricsRegistry struct {
+	counters   map[string]*Counter
+	gauges     map[string]*Gauge
+	histograms map[string]*Histogram
+	rates      map[string]*Rate
+	mu         sync.RWMutex
+}
+
+func (r *metricsRegistry) counter(name string) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counter, ok := r.counters[name]
+	if !ok {
+		counter = &Counter{}
+		r.counters[name] = counter
+	}
+	return counter
+}
+
+func (r *metricsRegistry) gauge(name string) *Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gauge, ok := r.gauges[name]
+	if !ok {
+		gauge = &Gauge{}
+		r.gauges[name] = gauge
+	}
+	return gauge
+}
+
+func (r *metricsRegistry) histogram(name string, member string, bounds []int64) *Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	histogram, ok := r.histograms[key(name, member)]
+	if !ok {
+		histogram = &Histogram{
+			name:   name,
+			member: member,
+			bounds: bounds,
+			counts: make([]uint64, len(bounds)),
+		}
+		r.histograms[key(name, member)] = histogram
+	}
+	return histogram
+}
+
+func (r *metricsRegistry) rate(name string, window time.Duration) *Rate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rate, ok := r.rates[name]
+	if !ok {
+		rate = &Rate{}
+		rate.reset(window)
+		r.rates[name] = rate
+	}
+	return rate
+}
+
+func (r *metricsRegistry) values() map[string]int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	values := make(map[string]int64)
+	for name, counter := range r.counters {
+		values[name] = int64(counter.Value())
+	}
+	for name, gauge := range r.gauges {
+		values[name] = gauge.Value()
+	}
+	for _, histogram := range r.histograms {
+		histogram.values(values)
+	}
+	for name, rate := range r.rates {
+		values[name] = int64(rate.Value())
+	}
+	return values
+}
+
+// Counter is a monotonically increasing metric
+type Counter struct {
+	value uint64
+}
+
+// Inc increments the counter by 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by the given delta
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.value, delta)
+}
+
+// Value returns the current value of the counter
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+// Gauge is a metric that can be arbitrarily set
+type Gauge struct {
+	value int64
+}
+
+// Set sets the value of the gauge
+func (g *Gauge) Set(value int64) {
+	atomic.StoreInt64(&g.value, value)
+}
+
+// Add adds the given delta to the gauge
+func (g *Gauge) Add(delta int64) {
+	atomic.AddInt64(&g.value, delta)
+}
+
+// Value returns the current value of the gauge
+func (g *Gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+// Histogram is a metric that counts observations in cumulative buckets
+type Histogram struct {
+	name   string
+	member string
+	bounds []int64
+	counts []uint64
+	count  uint64
+	sum    int64
+}
+
+// Observe records the given value in the histogram
+func (h *Histogram) Observe(value int64) {
+	for i, bound := range h.bounds {
+		if value <= bound {
+			atomic.AddUint64(&h.counts[i], 1)
+		}
+	}
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddInt64(&h.sum, value)
+}
+
+// Count returns the total number of observations recorded in the histogram
+func (h *Histogram) Count() uint64 {
+	return atomic.LoadUint64(&h.count)
+}
+
+// Sum returns the sum of all observations recorded in the histogram
+func (h *Histogram) Sum() int64 {
+	return atomic.LoadInt64(&h.sum)
+}
+
+// Bucket returns the number of observations less than or equal to the given bucket bound
+func (h *Histogram) Bucket(bound int64) uint64 {
+	for i, b := range h.bounds {
+		if b == bound {
+			return atomic.LoadUint64(&h.counts[i])
+		}
+	}
+	return 0
+}
+
+// values adds the histogram's bucket counts, total count and sum to the given values
+func (h *Histogram) values(values map[string]int64) {
+	for i, bound := range h.bounds {
+		values[fmt.Sprintf("%s_bucket{member=%q,le=\"%d\"}", h.name, h.member, bound)] = int64(atomic.LoadUint64(&h.counts[i]))
+	}
+	values[key(h.name+"_count", h.member)] = int64(h.Count())
+	values[key(h.name+"_sum", h.member)] = h.Sum()
+}
+
+// rateBuckets is the number of buckets into which the window of a rate is divided
+const rateBuckets = 10
+
+// Rate is a metric that measures the number of events per second over a sliding window
+// The window is divided into buckets, so events expire from the rate one bucket at a time.
+type Rate struct {
+	window  time.Duration
+	width   time.Duration
+	start   time.Time
+	buckets [rateBuckets]int64
+	counts  [rateBuckets]uint64
+	mu      sync.Mutex
+}
+
+// reset clears the rate and sets its window
+func (r *Rate) reset(window time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.window = window
+	r.width = window / rateBuckets
+	if r.width <= 0 {
+		r.width = 1
+	}
+	r.start = time.Now()
+	for i := range r.buckets {
+		r.buckets[i] = -1
+		r.counts[i] = 0
+	}
+}
+
+// Mark records the given number of events
+func (r *Rate) Mark(n uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bucket := int64(time.Since(r.start) / r.width)
+	slot := bucket % rateBuckets
+	if r.buckets[slot] != bucket {
+		r.buckets[slot] = bucket
+		r.counts[slot] = 0
+	}
+	r.counts[slot] += n
+}
+
+// Value returns the number of events per second over the window
+// Until a full window has elapsed since the rate was created, the rate is measured over the elapsed time.
+func (r *Rate) Value() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.start)
+	bucket := int64(elapsed / r.width)
+	var count uint64
+	for slot, b := range r.buckets {
+		if b >= 0 && b > bucket-rateBuckets {
+			count += r.counts[slot]
+		}
+	}
+
+	// The window spans the current partial bucket and the complete buckets preceding it.
+	span := time.Duration(rateBuckets-1)*r.width + elapsed%r.width
+	if span > elapsed {
+		span = elapsed
+	}
+	if span <= 0 {
+		return 0
+	}
+	return float64(count) / span.Seconds()
+}
@@ -0,0 +1,87 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	counter := NewCounter("test_counter", "foo")
+	assert.Equal(t, uint64(0), counter.Value())
+	counter.Inc()
+	counter.Add(2)
+	assert.Equal(t, uint64(3), counter.Value())
+	assert.True(t, counter == NewCounter("test_counter", "foo"))
+	assert.False(t, counter == NewCounter("test_counter", "bar"))
+
+	gauge := NewGauge("test_gauge", "foo")
+	gauge.Set(10)
+	gauge.Add(-3)
+	assert.Equal(t, int64(7), gauge.Value())
+
+	values := Values()
+	assert.Equal(t, int64(3), values[`test_counter{member="foo"}`])
+	assert.Equal(t, int64(7), values[`test_gauge{member="foo"}`])
+	assert.Contains(t, Names(), `test_gauge{member="foo"}`)
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram("test_histogram", "foo", ExponentialBounds(1, 4, 3))
+	assert.True(t, histogram == NewHistogram("test_histogram", "foo", nil))
+	histogram.Observe(1)
+	histogram.Observe(3)
+	histogram.Observe(16)
+	histogram.Observe(100)
+	assert.Equal(t, uint64(4), histogram.Count())
+	assert.Equal(t, int64(120), histogram.Sum())
+	assert.Equal(t, uint64(1), histogram.Bucket(1))
+	assert.Equal(t, uint64(2), histogram.Bucket(4))
+	assert.Equal(t, uint64(3), histogram.Bucket(16))
+
+	values := Values()
+	assert.Equal(t, int64(1), values[`test_histogram_bucket{member="foo",le="1"}`])
+	assert.Equal(t, int64(2), values[`test_histogram_bucket{member="foo",le="4"}`])
+	assert.Equal(t, int64(3), values[`test_histogram_bucket{member="foo",le="16"}`])
+	assert.Equal(t, int64(4), values[`test_histogram_count{member="foo"}`])
+	assert.Equal(t, int64(120), values[`test_histogram_sum{member="foo"}`])
+}
+
+func TestRate(t *testing.T) {
+	window := 100 * time.Millisecond
+	rate := NewRate("test_rate", "foo", window)
+	assert.True(t, rate == NewRate("test_rate", "foo", window))
+	assert.Equal(t, float64(0), rate.Value())
+	rate.Mark(10)
+	assert.True(t, rate.Value() > 0)
+	assert.True(t, Values()[`test_rate{member="foo",window="100ms"}`] > 0)
+
+	// Verify events expire from the rate once the window has elapsed
+	time.Sleep(window + window/rateBuckets)
+	assert.Equal(t, float64(0), rate.Value())
+
+	// Verify a rate with a different window is a distinct metric and doesn't reset the existing rate
+	rate.Mark(10)
+	other := NewRate("test_rate", "foo", time.Second)
+	assert.True(t, rate != other)
+	assert.Equal(t, float64(0), other.Value())
+	assert.True(t, rate.Value() > 0)
+	assert.True(t, rate == NewRate("test_rate", "foo", window))
+	values := Values()
+	assert.Contains(t, values, `test_rate{member="foo",window="100ms"}`)
+	assert.Contains(t, values, `test_rate{member="foo",window="1s"}`)
+}
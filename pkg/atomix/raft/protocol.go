@@ -15,11 +15,13 @@
 package raft
 
 import (
+	"context"
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
 	"github.com/atomix/go-framework/pkg/atomix/node"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/client"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
 )
 
 // NewProtocol returns a new Raft Protocol instance
@@ -29,20 +31,52 @@ func NewProtocol(config *config.ProtocolConfig) *Protocol {
 	}
 }
 
+// NewProtocolWithLog returns a new Raft Protocol instance that stores its log in the given backend
+func NewProtocolWithLog(config *config.ProtocolConfig, log log.Log) *Protocol {
+	return &Protocol{
+		config: config,
+		log:    log,
+	}
+}
+
 // Protocol is an implementation of the Client interface providing the Raft consensus protocol
 type Protocol struct {
 	node.Protocol
 	config *config.ProtocolConfig
+	log    log.Log
 	client *client.Client
 	server *Server
 }
 
 // Start starts the Raft protocol
 func (p *Protocol) Start(cluster cluster.Cluster, registry *node.Registry) error {
-	p.client = client.NewClient(cluster, raft.ReadConsistency_SEQUENTIAL)
-	p.server = NewServer(cluster, registry, p.config)
+	var server *Server
+	var err error
+	if p.log != nil {
+		server, err = NewServerWithLog(cluster, registry, p.config, p.log)
+	} else {
+		server, err = NewServer(cluster, registry, p.config)
+	}
+	if err != nil {
+		return err
+	}
+	p.client = client.NewClient(cluster, raft.ReadConsistency(p.config.GetReadConsistency()))
+	p.server = server
 	go p.server.Start()
-	return p.server.WaitForReady()
+
+	// If a startup timeout is configured, fail to start if the server can't become ready within the timeout.
+	// The server is stopped before returning the error so it doesn't keep running behind a failed start.
+	ctx := context.Background()
+	if timeout := p.config.GetStartupTimeoutOrDefault(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := p.server.WaitForReadyContext(ctx); err != nil {
+		_ = p.Stop()
+		return err
+	}
+	return nil
 }
 
 // Client returns the Raft protocol client
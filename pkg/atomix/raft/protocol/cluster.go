@@ -18,6 +18,7 @@ import (
 	"fmt"
 	node "github.com/atomix/go-framework/pkg/atomix/cluster"
 	"google.golang.org/grpc"
+	"sort"
 	"sync"
 	"time"
 )
@@ -33,19 +34,129 @@ type Cluster interface {
 	// GetMember returns a Member by ID
 	GetMember(memberID MemberID) *Member
 
+	// Configuration returns the committed cluster configuration
+	// Membership is fixed by the cluster configuration with which the node was started, so there is never a pending
+	// configuration change to return.
+	Configuration() *Configuration
+
 	// GetClient gets a RaftServiceClient connection for the given member
 	GetClient(memberID MemberID) (RaftServiceClient, error)
+
+	// UpdateMemberAddress updates the network address of the given member
+	// The member's existing connection is closed, and subsequent RPCs to the member use the new address.
+	// Cluster membership is unchanged.
+	UpdateMemberAddress(memberID MemberID, host string, port int) error
+
+	// RemoveMember removes the given member from the local view of the cluster
+	// The removal is not agreed with the other members. The member's connection is closed.
+	RemoveMember(memberID MemberID) error
+
+	// SetMemberType sets the type of the given member in the local view of the cluster
+	// The change is not agreed with the other members.
+	SetMemberType(memberID MemberID, memberType Member_Type) error
+}
+
+// Voters returns the IDs of the voting members in the configuration
+// Witnesses vote and count toward the commit quorum, so they're included among the voters.
+func (c *Configuration) Voters() []MemberID {
+	return c.membersOfType(Member_ACTIVE, Member_WITNESS)
+}
+
+// Witnesses returns the IDs of the voting members that store no entries in the configuration
+func (c *Configuration) Witnesses() []MemberID {
+	return c.membersOfType(Member_WITNESS)
+}
+
+// Learners returns the IDs of the non-voting members that can be promoted to voters in the configuration
+func (c *Configuration) Learners() []MemberID {
+	return c.membersOfType(Member_PROMOTABLE)
+}
+
+// Observers returns the IDs of the passive members in the configuration
+func (c *Configuration) Observers() []MemberID {
+	return c.membersOfType(Member_PASSIVE)
+}
+
+// ReplicationTargets returns the IDs of the members to which the given member replicates entries
+// The leader always replicates directly to the other voters. If relayFanout is positive, non-voting members are
+// arranged in a tree rooted at the voters other than the leader, in which each member relays committed entries to
+// at most relayFanout non-voting members. Otherwise, or if the leader is the only voter, the leader replicates
+// directly to all non-voting members. Witnesses store no entries, so they never relay entries.
+func (c *Configuration) ReplicationTargets(leader MemberID, member MemberID, relayFanout int) []MemberID {
+	relays := make([]MemberID, 0, len(c.Members))
+	for _, voter := range c.membersOfType(Member_ACTIVE) {
+		if voter != leader {
+			relays = append(relays, voter)
+		}
+	}
+	witnesses := c.Witnesses()
+	nonVoters := append(c.Learners(), c.Observers()...)
+
+	if relayFanout <= 0 || len(relays) == 0 {
+		if member != leader {
+			return nil
+		}
+		return append(append(relays, witnesses...), nonVoters...)
+	}
+	if member == leader {
+		return append(relays, witnesses...)
+	}
+
+	// Relays and non-voting members are numbered in order, and the non-voting member i is relayed by
+	// the member numbered i / relayFanout. Each member is thus relayed by a member numbered before it.
+	nodes := append(relays, nonVoters...)
+	for i, node := range nodes {
+		if node == member {
+			start := i * relayFanout
+			if start >= len(nonVoters) {
+				return nil
+			}
+			end := start + relayFanout
+			if end > len(nonVoters) {
+				end = len(nonVoters)
+			}
+			return nonVoters[start:end]
+		}
+	}
+	return nil
+}
+
+func (c *Configuration) membersOfType(memberTypes ...Member_Type) []MemberID {
+	members := make([]MemberID, 0, len(c.Members))
+	for _, member := range c.Members {
+		for _, memberType := range memberTypes {
+			if member.Type == memberType {
+				members = append(members, member.MemberID)
+			}
+		}
+	}
+	return members
 }
 
 // NewCluster returns a new Cluster with the given configuration
-func NewCluster(config node.Cluster) Cluster {
+// Members are voters unless they're listed among the given observers, which receive entries but don't vote.
+func NewCluster(config node.Cluster, observers ...MemberID) Cluster {
+	memberTypes := make(map[MemberID]Member_Type)
+	for _, observer := range observers {
+		memberTypes[observer] = Member_PASSIVE
+	}
+	return NewClusterWithMemberTypes(config, memberTypes)
+}
+
+// NewClusterWithMemberTypes returns a new Cluster with the given configuration and member types
+// Members not listed among the given member types are voters.
+func NewClusterWithMemberTypes(config node.Cluster, memberTypes map[MemberID]Member_Type) Cluster {
 	members := make(map[MemberID]*Member)
 	locations := make(map[MemberID]node.Member)
 	memberIDs := make([]MemberID, 0, len(config.Members))
 	for id, member := range config.Members {
+		memberType, ok := memberTypes[MemberID(id)]
+		if !ok {
+			memberType = Member_ACTIVE
+		}
 		members[MemberID(id)] = &Member{
 			MemberID: MemberID(member.ID),
-			Type:     Member_ACTIVE,
+			Type:     memberType,
 			Updated:  time.Now(),
 		}
 		locations[MemberID(id)] = member
@@ -77,13 +188,36 @@ func (c *cluster) Member() MemberID {
 }
 
 func (c *cluster) Members() []MemberID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.memberIDs
 }
 
 func (c *cluster) GetMember(memberID MemberID) *Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.members[memberID]
 }
 
+// Configuration returns the cluster configuration
+// Membership is fixed by the cluster configuration with which the node was started, so the configuration is
+// committed at index 0.
+func (c *cluster) Configuration() *Configuration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	members := make([]*Member, 0, len(c.members))
+	for _, member := range c.members {
+		m := *member
+		members = append(members, &m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].MemberID < members[j].MemberID
+	})
+	return &Configuration{
+		Members: members,
+	}
+}
+
 // getConn returns a connection for the given member
 func (c *cluster) getConn(member MemberID) (*grpc.ClientConn, error) {
 	_, ok := c.members[member]
@@ -108,6 +242,70 @@ func (c *cluster) getConn(member MemberID) (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+func (c *cluster) UpdateMemberAddress(member MemberID, host string, port int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	location, ok := c.locations[member]
+	if !ok {
+		return fmt.Errorf("unknown member %s", member)
+	}
+	location.Host = host
+	location.ProtocolPort = port
+	c.locations[member] = location
+
+	// Close the connection to the old address to force clients to reconnect.
+	if conn, ok := c.conns[member]; ok {
+		delete(c.conns, member)
+		delete(c.clients, member)
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func (c *cluster) RemoveMember(member MemberID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.members[member]; !ok {
+		return fmt.Errorf("unknown member %s", member)
+	}
+	if member == c.member {
+		return fmt.Errorf("cannot remove the local member %s", member)
+	}
+
+	// Replace the list of member IDs rather than modifying it, since it's shared with callers of Members.
+	memberIDs := make([]MemberID, 0, len(c.memberIDs)-1)
+	for _, memberID := range c.memberIDs {
+		if memberID != member {
+			memberIDs = append(memberIDs, memberID)
+		}
+	}
+	c.memberIDs = memberIDs
+	delete(c.members, member)
+	delete(c.locations, member)
+	if conn, ok := c.conns[member]; ok {
+		delete(c.conns, member)
+		delete(c.clients, member)
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func (c *cluster) SetMemberType(member MemberID, memberType Member_Type) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current, ok := c.members[member]
+	if !ok {
+		return fmt.Errorf("unknown member %s", member)
+	}
+
+	// Replace the member rather than modifying it, since it's shared with callers of GetMember.
+	updated := *current
+	updated.Type = memberType
+	updated.Updated = time.Now()
+	c.members[member] = &updated
+	return nil
+}
+
 // getClient gets the RaftServiceClient for the given member
 func (c *cluster) GetClient(member MemberID) (RaftServiceClient, error) {
 	c.mu.RLock()
@@ -0,0 +1,219 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	atomix "github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"net"
+	"testing"
+)
+
+func TestUpdateMemberAddress(t *testing.T) {
+	server1, port1 := startTestServer(t, Index(1))
+	server2, port2 := startTestServer(t, Index(2))
+	defer server2.Stop()
+
+	cluster := NewCluster(atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "127.0.0.1",
+				ProtocolPort: 5000,
+			},
+			"bar": {
+				ID:           "bar",
+				Host:         "127.0.0.1",
+				ProtocolPort: port1,
+			},
+		},
+	})
+	client := NewClient(cluster)
+
+	response, err := client.Append(context.Background(), &AppendRequest{Term: 1, Leader: "foo"}, MemberID("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, Index(1), response.LastLogIndex)
+
+	// Move the member to a new address and verify requests are sent to the new endpoint
+	server1.Stop()
+	assert.NoError(t, cluster.UpdateMemberAddress(MemberID("bar"), "127.0.0.1", port2))
+	response, err = client.Append(context.Background(), &AppendRequest{Term: 1, Leader: "foo"}, MemberID("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, Index(2), response.LastLogIndex)
+
+	// Verify membership is unchanged
+	assert.Len(t, cluster.Members(), 2)
+	assert.NotNil(t, cluster.GetMember(MemberID("bar")))
+	assert.Error(t, cluster.UpdateMemberAddress(MemberID("baz"), "127.0.0.1", port2))
+}
+
+func TestClusterConfiguration(t *testing.T) {
+	config := atomix.Cluster{
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "127.0.0.1",
+				ProtocolPort: 5000,
+			},
+			"bar": {
+				ID:           "bar",
+				Host:         "127.0.0.1",
+				ProtocolPort: 5001,
+			},
+			"baz": {
+				ID:           "baz",
+				Host:         "127.0.0.1",
+				ProtocolPort: 5002,
+			},
+		},
+	}
+
+	// Verify all nodes report the same committed configuration
+	for _, member := range []string{"foo", "bar", "baz"} {
+		config.MemberID = member
+		committed := NewCluster(config).Configuration()
+		assert.Equal(t, Index(0), committed.Index)
+		assert.Equal(t, []MemberID{"bar", "baz", "foo"}, committed.Voters())
+		assert.Len(t, committed.Learners(), 0)
+		assert.Len(t, committed.Observers(), 0)
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	config := atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID: "foo",
+			},
+			"bar": {
+				ID: "bar",
+			},
+			"baz": {
+				ID: "baz",
+			},
+		},
+	}
+	cluster := NewCluster(config)
+	members := cluster.Members()
+	assert.NoError(t, cluster.RemoveMember(MemberID("baz")))
+	assert.Len(t, members, 3)
+	assert.Len(t, cluster.Members(), 2)
+	assert.Nil(t, cluster.GetMember(MemberID("baz")))
+	committed := cluster.Configuration()
+	assert.Equal(t, []MemberID{"bar", "foo"}, committed.Voters())
+	_, err := cluster.GetClient(MemberID("baz"))
+	assert.Error(t, err)
+
+	// Verify unknown and local members cannot be removed
+	assert.Error(t, cluster.RemoveMember(MemberID("baz")))
+	assert.Error(t, cluster.RemoveMember(MemberID("foo")))
+	assert.Len(t, cluster.Members(), 2)
+}
+
+func TestSetMemberType(t *testing.T) {
+	config := atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID: "foo",
+			},
+			"bar": {
+				ID: "bar",
+			},
+		},
+	}
+	cluster := NewCluster(config)
+	member := cluster.GetMember(MemberID("bar"))
+	assert.NoError(t, cluster.SetMemberType(MemberID("bar"), Member_WITNESS))
+	assert.Equal(t, Member_ACTIVE, member.Type)
+	assert.Equal(t, Member_WITNESS, cluster.GetMember(MemberID("bar")).Type)
+	committed := cluster.Configuration()
+	assert.Equal(t, []MemberID{"bar"}, committed.Witnesses())
+
+	// Verify the type of an unknown member cannot be set
+	assert.Error(t, cluster.SetMemberType(MemberID("baz"), Member_PASSIVE))
+}
+
+func TestReplicationTargets(t *testing.T) {
+	config := atomix.Cluster{
+		MemberID: "a",
+		Members:  map[string]atomix.Member{},
+	}
+	for _, member := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		config.Members[member] = atomix.Member{ID: member}
+	}
+	committed := NewCluster(config, "d", "e", "f", "g", "h").Configuration()
+	assert.Equal(t, []MemberID{"a", "b", "c"}, committed.Voters())
+	assert.Equal(t, []MemberID{"d", "e", "f", "g", "h"}, committed.Observers())
+
+	// Verify the leader replicates directly to all members if relaying is disabled
+	assert.Equal(t, []MemberID{"b", "c", "d", "e", "f", "g", "h"}, committed.ReplicationTargets("a", "a", 0))
+	assert.Len(t, committed.ReplicationTargets("a", "b", 0), 0)
+
+	// Verify observers are arranged in a tree rooted at the voters other than the leader
+	assert.Equal(t, []MemberID{"b", "c"}, committed.ReplicationTargets("a", "a", 2))
+	assert.Equal(t, []MemberID{"d", "e"}, committed.ReplicationTargets("a", "b", 2))
+	assert.Equal(t, []MemberID{"f", "g"}, committed.ReplicationTargets("a", "c", 2))
+	assert.Equal(t, []MemberID{"h"}, committed.ReplicationTargets("a", "d", 2))
+	assert.Len(t, committed.ReplicationTargets("a", "e", 2), 0)
+	assert.Equal(t, []MemberID{"a", "c"}, committed.ReplicationTargets("b", "b", 2))
+	assert.Equal(t, []MemberID{"d", "e"}, committed.ReplicationTargets("b", "a", 2))
+
+	// Verify witnesses vote but are replicated to directly by the leader and never relay entries
+	committed = NewClusterWithMemberTypes(config, map[MemberID]Member_Type{
+		"c": Member_WITNESS,
+		"d": Member_PASSIVE,
+		"e": Member_PASSIVE,
+		"f": Member_PASSIVE,
+		"g": Member_PASSIVE,
+		"h": Member_PASSIVE,
+	}).Configuration()
+	assert.Equal(t, []MemberID{"a", "b", "c"}, committed.Voters())
+	assert.Equal(t, []MemberID{"c"}, committed.Witnesses())
+	assert.Equal(t, []MemberID{"b", "c", "d", "e", "f", "g", "h"}, committed.ReplicationTargets("a", "a", 0))
+	assert.Equal(t, []MemberID{"b", "c"}, committed.ReplicationTargets("a", "a", 2))
+	assert.Equal(t, []MemberID{"d", "e"}, committed.ReplicationTargets("a", "b", 2))
+	assert.Len(t, committed.ReplicationTargets("a", "c", 2), 0)
+}
+
+func startTestServer(t *testing.T, lastIndex Index) (*grpc.Server, int) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	RegisterRaftServiceServer(server, NewServer(&testServer{lastIndex: lastIndex}))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	return server, lis.Addr().(*net.TCPAddr).Port
+}
+
+// testServer is a Server that responds to append requests with a fixed last index
+type testServer struct {
+	Server
+	lastIndex Index
+}
+
+func (s *testServer) Append(ctx context.Context, request *AppendRequest) (*AppendResponse, error) {
+	return &AppendResponse{
+		Status:       ResponseStatus_OK,
+		Term:         request.Term,
+		Succeeded:    true,
+		LastLogIndex: s.lastIndex,
+	}, nil
+}
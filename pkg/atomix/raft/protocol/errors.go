@@ -0,0 +1,151 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// ErrTimeout indicates a request did not complete before its deadline
+var ErrTimeout = errors.New("request timed out")
+
+// ErrQuorumLost indicates the leader could not reach a quorum of the cluster to complete a request
+var ErrQuorumLost = errors.New("quorum lost")
+
+// ErrOverloaded indicates the server rejected a request because it is overloaded
+var ErrOverloaded = errors.New("server overloaded")
+
+// ErrDiskFull indicates the server rejected a request because it is low on disk space
+var ErrDiskFull = errors.New("insufficient disk space")
+
+// ErrEntryTooLarge indicates the server rejected a request because its log entry exceeds the maximum entry size
+var ErrEntryTooLarge = errors.New("entry too large")
+
+// ErrShuttingDown indicates a request could not be completed because the server is shutting down
+var ErrShuttingDown = errors.New("server shutting down")
+
+// ErrLeaderInitializing indicates a command was rejected because a new leader has not yet established contact
+// with a quorum
+var ErrLeaderInitializing = errors.New("leader initializing")
+
+// ErrFaulted indicates a request was rejected because the member's state machine failed to apply an entry
+var ErrFaulted = errors.New("state machine faulted")
+
+// ErrEntriesUnavailable indicates a request was rejected because the requested entries are not committed or have been
+// compacted from the member's log
+var ErrEntriesUnavailable = errors.New("entries unavailable")
+
+// ErrNotLeader indicates a request was sent to a member that is not the leader
+type ErrNotLeader struct {
+	// Leader is the current leader if known
+	Leader MemberID
+}
+
+func (e *ErrNotLeader) Error() string {
+	if e.Leader == "" {
+		return "not the leader: no known leader"
+	}
+	return fmt.Sprintf("not the leader: current leader is %s", e.Leader)
+}
+
+// NewError returns a typed error for the given response error
+func NewError(err ResponseError, message string, leader MemberID) error {
+	switch err {
+	case ResponseError_NO_LEADER, ResponseError_ILLEGAL_MEMBER_STATE:
+		return &ErrNotLeader{Leader: leader}
+	case ResponseError_UNAVAILABLE:
+		return ErrQuorumLost
+	case ResponseError_OVERLOADED:
+		return ErrOverloaded
+	case ResponseError_TIMEOUT:
+		return ErrTimeout
+	case ResponseError_DISK_FULL:
+		return ErrDiskFull
+	case ResponseError_ENTRY_TOO_LARGE:
+		return ErrEntryTooLarge
+	case ResponseError_SHUTTING_DOWN:
+		return ErrShuttingDown
+	case ResponseError_LEADER_INITIALIZING:
+		return ErrLeaderInitializing
+	case ResponseError_FAULTED:
+		return ErrFaulted
+	case ResponseError_ENTRIES_UNAVAILABLE:
+		return ErrEntriesUnavailable
+	}
+	if message == "" {
+		message = strings.ToLower(err.String())
+	}
+	return errors.New(message)
+}
+
+// GetResponseError returns the response error with which to report the given typed error
+func GetResponseError(err error) ResponseError {
+	switch err.(type) {
+	case *ErrNotLeader:
+		return ResponseError_ILLEGAL_MEMBER_STATE
+	}
+	switch err {
+	case ErrQuorumLost:
+		return ResponseError_UNAVAILABLE
+	case ErrOverloaded:
+		return ResponseError_OVERLOADED
+	case ErrTimeout:
+		return ResponseError_TIMEOUT
+	case ErrDiskFull:
+		return ResponseError_DISK_FULL
+	case ErrEntryTooLarge:
+		return ResponseError_ENTRY_TOO_LARGE
+	case ErrShuttingDown:
+		return ResponseError_SHUTTING_DOWN
+	case ErrLeaderInitializing:
+		return ResponseError_LEADER_INITIALIZING
+	case ErrFaulted:
+		return ResponseError_FAULTED
+	case ErrEntriesUnavailable:
+		return ResponseError_ENTRIES_UNAVAILABLE
+	}
+	return ResponseError_PROTOCOL_ERROR
+}
+
+// NewCommandError returns a typed error for the given failed command response
+func NewCommandError(response *CommandResponse) error {
+	return NewError(response.Error, response.Message, response.Leader)
+}
+
+// NewQueryError returns a typed error for the given failed query response
+func NewQueryError(response *QueryResponse) error {
+	return NewError(response.Error, response.Message, "")
+}
+
+// NewRPCError translates an error returned by a Raft RPC into a typed error where possible
+func NewRPCError(err error) error {
+	if err == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	if e, ok := status.FromError(err); ok {
+		switch e.Code() {
+		case codes.DeadlineExceeded:
+			return ErrTimeout
+		case codes.ResourceExhausted:
+			return ErrOverloaded
+		}
+	}
+	return err
+}
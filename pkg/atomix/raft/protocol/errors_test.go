@@ -0,0 +1,148 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestCommandErrors(t *testing.T) {
+	err := NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_ILLEGAL_MEMBER_STATE,
+		Leader: MemberID("foo"),
+	})
+	notLeader, ok := err.(*ErrNotLeader)
+	assert.True(t, ok)
+	assert.Equal(t, MemberID("foo"), notLeader.Leader)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_NO_LEADER,
+	})
+	notLeader, ok = err.(*ErrNotLeader)
+	assert.True(t, ok)
+	assert.Equal(t, MemberID(""), notLeader.Leader)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_UNAVAILABLE,
+	})
+	assert.Equal(t, ErrQuorumLost, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_OVERLOADED,
+	})
+	assert.Equal(t, ErrOverloaded, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_TIMEOUT,
+	})
+	assert.Equal(t, ErrTimeout, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_DISK_FULL,
+	})
+	assert.Equal(t, ErrDiskFull, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_ENTRY_TOO_LARGE,
+	})
+	assert.Equal(t, ErrEntryTooLarge, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_SHUTTING_DOWN,
+	})
+	assert.Equal(t, ErrShuttingDown, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_LEADER_INITIALIZING,
+	})
+	assert.Equal(t, ErrLeaderInitializing, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_FAULTED,
+	})
+	assert.Equal(t, ErrFaulted, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_ENTRIES_UNAVAILABLE,
+	})
+	assert.Equal(t, ErrEntriesUnavailable, err)
+
+	err = NewCommandError(&CommandResponse{
+		Status:  ResponseStatus_ERROR,
+		Error:   ResponseError_APPLICATION_ERROR,
+		Message: "foo",
+	})
+	assert.EqualError(t, err, "foo")
+}
+
+func TestQueryErrors(t *testing.T) {
+	err := NewQueryError(&QueryResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_ILLEGAL_MEMBER_STATE,
+	})
+	_, ok := err.(*ErrNotLeader)
+	assert.True(t, ok)
+
+	err = NewQueryError(&QueryResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_UNAVAILABLE,
+	})
+	assert.Equal(t, ErrQuorumLost, err)
+
+	err = NewQueryError(&QueryResponse{
+		Status: ResponseStatus_ERROR,
+		Error:  ResponseError_QUERY_FAILURE,
+	})
+	assert.EqualError(t, err, "query_failure")
+}
+
+func TestRPCErrors(t *testing.T) {
+	assert.Equal(t, ErrTimeout, NewRPCError(context.DeadlineExceeded))
+	assert.Equal(t, ErrTimeout, NewRPCError(status.Error(codes.DeadlineExceeded, "deadline exceeded")))
+	assert.Equal(t, ErrOverloaded, NewRPCError(status.Error(codes.ResourceExhausted, "resource exhausted")))
+
+	err := errors.New("foo")
+	assert.Equal(t, err, NewRPCError(err))
+}
+
+func TestGetResponseError(t *testing.T) {
+	assert.Equal(t, ResponseError_ILLEGAL_MEMBER_STATE, GetResponseError(&ErrNotLeader{Leader: MemberID("foo")}))
+	assert.Equal(t, ResponseError_UNAVAILABLE, GetResponseError(ErrQuorumLost))
+	assert.Equal(t, ResponseError_OVERLOADED, GetResponseError(ErrOverloaded))
+	assert.Equal(t, ResponseError_TIMEOUT, GetResponseError(ErrTimeout))
+	assert.Equal(t, ResponseError_DISK_FULL, GetResponseError(ErrDiskFull))
+	assert.Equal(t, ResponseError_ENTRY_TOO_LARGE, GetResponseError(ErrEntryTooLarge))
+	assert.Equal(t, ResponseError_SHUTTING_DOWN, GetResponseError(ErrShuttingDown))
+	assert.Equal(t, ResponseError_LEADER_INITIALIZING, GetResponseError(ErrLeaderInitializing))
+	assert.Equal(t, ResponseError_FAULTED, GetResponseError(ErrFaulted))
+	assert.Equal(t, ResponseError_ENTRIES_UNAVAILABLE, GetResponseError(ErrEntriesUnavailable))
+	assert.Equal(t, ResponseError_PROTOCOL_ERROR, GetResponseError(errors.New("foo")))
+}
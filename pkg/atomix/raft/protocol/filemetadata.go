@@ -0,0 +1,400 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// metadataFile is the name of the file in which the term, vote, last applied index, configuration, witness record,
+// and match indexes are stored
+const metadataFile = "metadata"
+
+// The metadata file consists of a header followed by the Metadata message encoded as protobuf:
+//
+//	magic (8 bytes) | format version (uint32) | message length (uint32) | message
+//
+// Fields may be added to the Metadata message without changing the format version, provided they're assigned new
+// field numbers and field numbers are never reused. Readers ignore unknown fields, so files written by a newer release
+// remain readable by an older release of the same format version, and fields missing from files written by an older
+// release take their zero values. The format version is bumped only when the interpretation of existing data
+// changes. Files with a newer format version than the reader supports are rejected rather than misread, and files
+// with an older format version are decoded by the migration for that version and rewritten in the current format.
+const (
+	// metadataMagic identifies a versioned metadata file
+	metadataMagic = "RAFTMETA"
+	// metadataVersion is the current version of the metadata file format
+	metadataVersion uint32 = 1
+	// metadataHeaderSize is the size of the metadata file header
+	metadataHeaderSize = len(metadataMagic) + 8
+)
+
+// metadataMigrations decodes the message of each prior version of the metadata file format
+// Version 0 files were written before the format was versioned and contain only the message, with no header. The
+// message never begins with the magic, since the magic would be decoded as a field the message doesn't define.
+var metadataMigrations = map[uint32]func([]byte) (*Metadata, error){
+	0: unmarshalMetadata,
+}
+
+// encodeMetadata encodes the given metadata in the current metadata file format
+func encodeMetadata(metadata *Metadata) ([]byte, error) {
+	message, err := metadata.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, metadataHeaderSize+len(message))
+	copy(data, metadataMagic)
+	binary.BigEndian.PutUint32(data[len(metadataMagic):], metadataVersion)
+	binary.BigEndian.PutUint32(data[len(metadataMagic)+4:], uint32(len(message)))
+	copy(data[metadataHeaderSize:], message)
+	return data, nil
+}
+
+// decodeMetadata decodes metadata in any supported version of the metadata file format
+// The format version of the decoded file is returned along with the metadata.
+func decodeMetadata(data []byte) (*Metadata, uint32, error) {
+	if !bytes.HasPrefix(data, []byte(metadataMagic)) {
+		metadata, err := metadataMigrations[0](data)
+		return metadata, 0, err
+	}
+	if len(data) < metadataHeaderSize {
+		return nil, 0, errors.New("metadata file header is truncated")
+	}
+	version := binary.BigEndian.Uint32(data[len(metadataMagic):])
+	length := binary.BigEndian.Uint32(data[len(metadataMagic)+4:])
+	if version > metadataVersion {
+		return nil, version, fmt.Errorf("metadata file version %d is newer than the supported version %d", version, metadataVersion)
+	}
+	message := data[metadataHeaderSize:]
+	if uint32(len(message)) != length {
+		return nil, version, fmt.Errorf("metadata file is %d bytes but its header records %d bytes", len(message), length)
+	}
+	if version == metadataVersion {
+		metadata, err := unmarshalMetadata(message)
+		return metadata, version, err
+	}
+	migrate, ok := metadataMigrations[version]
+	if !ok {
+		return nil, version, fmt.Errorf("unsupported metadata file version %d", version)
+	}
+	metadata, err := migrate(message)
+	return metadata, version, err
+}
+
+// unmarshalMetadata decodes the given protobuf encoded metadata message
+func unmarshalMetadata(message []byte) (*Metadata, error) {
+	metadata := &Metadata{}
+	if err := metadata.Unmarshal(message); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// fsyncLatencyBounds are the bucket bounds in microseconds of the histogram of metadata fsync latencies
+var fsyncLatencyBounds = metrics.ExponentialBounds(100, 2, 14)
+
+// NewFileMetadataStore returns a new metadata store that persists the term, vote, last applied index, cluster
+// configuration, witness record, and match indexes to a file
+// The store must be opened in a directory before it's used. If the sync window is 0, each change to the metadata
+// is synced to disk before it's stored. Otherwise, changes are synced when Sync is called, and changes made within
+// the sync window of each other share a single sync. Match indexes are only hints, so storing a match index never
+// triggers a sync; match indexes are written with the next sync of other metadata or when the store is closed.
+// The latency of each fsync is recorded in the given member's raft_metadata_fsync_microseconds histogram.
+func NewFileMetadataStore(member MemberID, syncWindow time.Duration) *FileMetadataStore {
+	store := &FileMetadataStore{
+		matchIndexes: make(map[MemberID]Index),
+		syncWindow:   syncWindow,
+		syncFile: func(file *os.File) error {
+			return file.Sync()
+		},
+		syncLatency: metrics.NewHistogram("raft_metadata_fsync_microseconds", string(member), fsyncLatencyBounds),
+	}
+	store.cond = sync.NewCond(&store.mu)
+	return store
+}
+
+// FileMetadataStore is a MetadataStore that persists the term, vote, last applied index, cluster configuration,
+// witness record, and match indexes to a file
+type FileMetadataStore struct {
+	path         string
+	syncWindow   time.Duration
+	syncFile     func(*os.File) error
+	syncLatency  *metrics.Histogram
+	term         *Term
+	vote         *MemberID
+	lastApplied  *Index
+	config       *Configuration
+	witness      *WitnessRecord
+	matchIndexes map[MemberID]Index
+	version      uint64
+	synced       uint64
+	syncing      bool
+	mu           sync.Mutex
+	cond         *sync.Cond
+}
+
+// Open opens the store in the given directory, loading the metadata stored in the directory if any
+func (s *FileMetadataStore) Open(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.path = filepath.Join(dir, metadataFile)
+	bytes, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	metadata, version, err := decodeMetadata(bytes)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata file %s: %v", s.path, err)
+	}
+
+	// Metadata stored in an older format is rewritten in the current format
+	if version < metadataVersion {
+		if err := s.write(metadata); err != nil {
+			return err
+		}
+	}
+	term := metadata.Term
+	s.term = &term
+	if metadata.Vote != "" {
+		vote := metadata.Vote
+		s.vote = &vote
+	}
+	if metadata.LastApplied > 0 {
+		lastApplied := metadata.LastApplied
+		s.lastApplied = &lastApplied
+	}
+	s.config = metadata.Configuration
+	s.witness = metadata.WitnessRecord
+	for _, matchIndex := range metadata.MatchIndexes {
+		s.matchIndexes[matchIndex.MemberID] = matchIndex.Index
+	}
+	return nil
+}
+
+func (s *FileMetadataStore) StoreTerm(term Term) {
+	s.mu.Lock()
+	s.term = &term
+	s.version++
+	s.mu.Unlock()
+	if s.syncWindow == 0 {
+		_ = s.Sync()
+	}
+}
+
+func (s *FileMetadataStore) LoadTerm() *Term {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.term
+}
+
+func (s *FileMetadataStore) StoreVote(vote *MemberID) {
+	s.mu.Lock()
+	s.vote = vote
+	s.version++
+	s.mu.Unlock()
+	if s.syncWindow == 0 {
+		_ = s.Sync()
+	}
+}
+
+func (s *FileMetadataStore) LoadVote() *MemberID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.vote
+}
+
+func (s *FileMetadataStore) StoreLastApplied(index Index) {
+	s.mu.Lock()
+	s.lastApplied = &index
+	s.version++
+	s.mu.Unlock()
+	if s.syncWindow == 0 {
+		_ = s.Sync()
+	}
+}
+
+func (s *FileMetadataStore) LoadLastApplied() *Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastApplied
+}
+
+func (s *FileMetadataStore) StoreConfiguration(configuration *Configuration) {
+	s.mu.Lock()
+	s.config = configuration
+	s.version++
+	s.mu.Unlock()
+	if s.syncWindow == 0 {
+		_ = s.Sync()
+	}
+}
+
+func (s *FileMetadataStore) LoadConfiguration() *Configuration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+func (s *FileMetadataStore) StoreWitnessRecord(record *WitnessRecord) {
+	s.mu.Lock()
+	s.witness = record
+	s.version++
+	s.mu.Unlock()
+	if s.syncWindow == 0 {
+		_ = s.Sync()
+	}
+}
+
+func (s *FileMetadataStore) LoadWitnessRecord() *WitnessRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.witness
+}
+
+func (s *FileMetadataStore) StoreMatchIndex(member MemberID, index Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.matchIndexes[member]; !ok || current != index {
+		s.matchIndexes[member] = index
+		s.version++
+	}
+}
+
+func (s *FileMetadataStore) LoadMatchIndex(member MemberID) *Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, ok := s.matchIndexes[member]
+	if !ok {
+		return nil
+	}
+	return &index
+}
+
+// Sync blocks until all changes stored before the call are durable
+// If no sync is in progress, the caller waits for the sync window and then writes the latest metadata, allowing
+// changes made by concurrent callers within the window to share the sync. Otherwise, the caller waits for the
+// in-progress sync and starts another sync if its changes were made after the in-progress sync began.
+// A failed write is retried by the next call to Sync.
+func (s *FileMetadataStore) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version := s.version
+	for s.synced < version {
+		if s.syncing {
+			s.cond.Wait()
+			continue
+		}
+
+		s.syncing = true
+		s.mu.Unlock()
+		if s.syncWindow > 0 {
+			time.Sleep(s.syncWindow)
+		}
+		s.mu.Lock()
+		metadata := &Metadata{}
+		if s.term != nil {
+			metadata.Term = *s.term
+		}
+		if s.vote != nil {
+			metadata.Vote = *s.vote
+		}
+		if s.lastApplied != nil {
+			metadata.LastApplied = *s.lastApplied
+		}
+		metadata.Configuration = s.config
+		metadata.WitnessRecord = s.witness
+		for member, index := range s.matchIndexes {
+			metadata.MatchIndexes = append(metadata.MatchIndexes, &MatchIndex{
+				MemberID: member,
+				Index:    index,
+			})
+		}
+		target := s.version
+		s.mu.Unlock()
+		err := s.write(metadata)
+		s.mu.Lock()
+		s.syncing = false
+		if err == nil && target > s.synced {
+			s.synced = target
+		}
+		s.cond.Broadcast()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write durably replaces the metadata file with the given metadata
+func (s *FileMetadataStore) write(metadata *Metadata) error {
+	bytes, err := encodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(bytes); err != nil {
+		file.Close()
+		return err
+	}
+	if err := s.fsync(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	// Sync the directory to ensure the rename is durable
+	dir, err := os.Open(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return s.fsync(dir)
+}
+
+// fsync syncs the given file to disk, recording the latency of the sync
+// The latency is recorded whether or not the sync succeeds, since a failing disk is often also a slow one.
+func (s *FileMetadataStore) fsync(file *os.File) error {
+	startTime := time.Now()
+	err := s.syncFile(file)
+	s.syncLatency.Observe(int64(time.Since(startTime) / time.Microsecond))
+	return err
+}
+
+func (s *FileMetadataStore) Close() error {
+	return s.Sync()
+}
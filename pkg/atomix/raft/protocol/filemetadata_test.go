@@ -0,0 +1,275 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"encoding/binary"
+	atomix "github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileMetadataStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileMetadataStore("foo", 0)
+	assert.NoError(t, store.Open(dir))
+	assert.Nil(t, store.LoadTerm())
+	assert.Nil(t, store.LoadVote())
+	assert.Nil(t, store.LoadLastApplied())
+	assert.Nil(t, store.LoadConfiguration())
+	assert.Nil(t, store.LoadWitnessRecord())
+	store.StoreTerm(Term(3))
+	vote := MemberID("foo")
+	store.StoreVote(&vote)
+	store.StoreMatchIndex(vote, Index(10))
+	store.StoreLastApplied(Index(7))
+	store.StoreConfiguration(&Configuration{
+		Index: Index(5),
+		Term:  Term(2),
+		Members: []*Member{
+			{
+				MemberID: "bar",
+				Type:     Member_ACTIVE,
+			},
+			{
+				MemberID: "foo",
+				Type:     Member_PASSIVE,
+			},
+		},
+	})
+	store.StoreWitnessRecord(&WitnessRecord{Index: Index(9), Term: Term(3)})
+	assert.NoError(t, store.Close())
+
+	// Verify the term, vote, last applied index, configuration, witness record, and match indexes are reloaded when the
+	// store is reopened
+	store = NewFileMetadataStore("foo", 0)
+	assert.NoError(t, store.Open(dir))
+	assert.Equal(t, Term(3), *store.LoadTerm())
+	assert.Equal(t, vote, *store.LoadVote())
+	assert.Equal(t, Index(7), *store.LoadLastApplied())
+	configuration := store.LoadConfiguration()
+	assert.Equal(t, Index(5), configuration.Index)
+	assert.Equal(t, Term(2), configuration.Term)
+	assert.Equal(t, []MemberID{"bar"}, configuration.Voters())
+	assert.Equal(t, []MemberID{"foo"}, configuration.Observers())
+	assert.Equal(t, &WitnessRecord{Index: Index(9), Term: Term(3)}, store.LoadWitnessRecord())
+	assert.Equal(t, Index(10), *store.LoadMatchIndex(vote))
+	assert.Nil(t, store.LoadMatchIndex("bar"))
+	store.StoreVote(nil)
+	assert.NoError(t, store.Close())
+
+	store = NewFileMetadataStore("foo", 0)
+	assert.NoError(t, store.Open(dir))
+	assert.Equal(t, Term(3), *store.LoadTerm())
+	assert.Nil(t, store.LoadVote())
+	assert.Equal(t, Index(7), *store.LoadLastApplied())
+}
+
+// voteRole is a role that increments the term and votes for the candidate on each vote request
+type voteRole struct {
+	*testRole
+	raft Raft
+}
+
+func (r *voteRole) Type() RoleType {
+	return RoleFollower
+}
+
+func (r *voteRole) Vote(ctx context.Context, request *VoteRequest) (*VoteResponse, error) {
+	r.raft.WriteLock()
+	defer r.raft.WriteUnlock()
+	if err := r.raft.SetTerm(r.raft.Term() + 1); err != nil {
+		return nil, err
+	}
+	if err := r.raft.SetLastVotedFor(request.Candidate); err != nil {
+		return nil, err
+	}
+	return &VoteResponse{
+		Status: ResponseStatus_OK,
+		Term:   r.raft.Term(),
+		Voted:  true,
+	}, nil
+}
+
+func TestFileMetadataStoreMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, metadataFile)
+
+	// Write metadata in the unversioned format used before the format was versioned
+	legacy := &Metadata{
+		Term:        Term(3),
+		Vote:        MemberID("foo"),
+		LastApplied: Index(7),
+		Configuration: &Configuration{
+			Index: Index(5),
+			Term:  Term(2),
+			Members: []*Member{
+				{
+					MemberID: "foo",
+					Type:     Member_ACTIVE,
+				},
+			},
+		},
+	}
+	bytes, err := legacy.Marshal()
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path, bytes, 0644))
+
+	// Verify the legacy metadata is loaded and the file is rewritten in the current format
+	store := NewFileMetadataStore("foo", 0)
+	assert.NoError(t, store.Open(dir))
+	assert.Equal(t, Term(3), *store.LoadTerm())
+	assert.Equal(t, MemberID("foo"), *store.LoadVote())
+	assert.Equal(t, Index(7), *store.LoadLastApplied())
+	assert.Equal(t, []MemberID{"foo"}, store.LoadConfiguration().Voters())
+	assert.NoError(t, store.Close())
+	bytes, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(bytes), metadataMagic))
+	metadata, version, err := decodeMetadata(bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, metadataVersion, version)
+	assert.Equal(t, legacy, metadata)
+
+	// Verify the migrated metadata is loaded when the store is reopened
+	store = NewFileMetadataStore("foo", 0)
+	assert.NoError(t, store.Open(dir))
+	assert.Equal(t, Term(3), *store.LoadTerm())
+	assert.Equal(t, Index(7), *store.LoadLastApplied())
+	assert.NoError(t, store.Close())
+
+	// Verify metadata written in a newer format version or truncated is rejected rather than misread
+	newer := append([]byte{}, bytes...)
+	binary.BigEndian.PutUint32(newer[len(metadataMagic):], metadataVersion+1)
+	assert.NoError(t, ioutil.WriteFile(path, newer, 0644))
+	assert.Error(t, NewFileMetadataStore("foo", 0).Open(dir))
+	assert.NoError(t, ioutil.WriteFile(path, bytes[:len(bytes)-1], 0644))
+	assert.Error(t, NewFileMetadataStore("foo", 0).Open(dir))
+}
+
+func TestFileMetadataStoreSyncWindow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Record the metadata that is durable each time the metadata file is synced
+	store := NewFileMetadataStore("foo", 5*time.Millisecond)
+	assert.NoError(t, store.Open(dir))
+	durable := &Metadata{}
+	syncs := 0
+	mu := sync.Mutex{}
+	store.syncFile = func(file *os.File) error {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(file.Name(), ".tmp") {
+			return nil
+		}
+		bytes, err := ioutil.ReadFile(file.Name())
+		if err != nil {
+			return err
+		}
+		metadata, _, err := decodeMetadata(bytes)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		syncs++
+		*durable = *metadata
+		return nil
+	}
+
+	cluster := atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID: "foo",
+			},
+			"bar": {
+				ID: "bar",
+			},
+		},
+	}
+	roles := map[RoleType]func(Raft) Role{
+		RoleFollower: func(r Raft) Role {
+			return &voteRole{testRole: &testRole{}, raft: r}
+		},
+	}
+	raft := newRaft(NewCluster(cluster), &config.ProtocolConfig{}, &unimplementedClient{}, roles, store)
+	raft.WriteLock()
+	raft.Init()
+	raft.WriteUnlock()
+
+	// Verify that no response is returned before the term and vote it reflects are durable
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := raft.Vote(context.TODO(), &VoteRequest{Candidate: "bar"})
+			assert.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			assert.True(t, durable.Term >= response.Term)
+			assert.Equal(t, MemberID("bar"), durable.Vote)
+		}()
+	}
+	wg.Wait()
+
+	// Verify that concurrent changes shared syncs
+	mu.Lock()
+	assert.Equal(t, Term(10), durable.Term)
+	assert.True(t, syncs < 10)
+	mu.Unlock()
+	assert.NoError(t, raft.Close())
+}
+
+func TestFileMetadataStoreSyncLatency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Simulate a slow disk by delaying each sync
+	store := NewFileMetadataStore("sync-latency", 0)
+	assert.NoError(t, store.Open(dir))
+	delay := 20 * time.Millisecond
+	store.syncFile = func(file *os.File) error {
+		time.Sleep(delay)
+		return file.Sync()
+	}
+
+	// Verify the file and directory syncs of a metadata write are each recorded with at least the injected delay
+	latency := metrics.NewHistogram("raft_metadata_fsync_microseconds", "sync-latency", fsyncLatencyBounds)
+	count, sum := latency.Count(), latency.Sum()
+	store.StoreTerm(Term(1))
+	assert.Equal(t, count+2, latency.Count())
+	assert.True(t, latency.Sum()-sum >= 2*int64(delay/time.Microsecond))
+	assert.Equal(t, uint64(0), latency.Bucket(fsyncLatencyBounds[0]))
+}
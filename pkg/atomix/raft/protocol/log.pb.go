@@ -37,6 +37,7 @@ type LogEntry struct {
 	//	*LogEntry_Configuration
 	//	*LogEntry_Command
 	//	*LogEntry_Query
+	//	*LogEntry_Barrier
 	Entry isLogEntry_Entry `protobuf_oneof:"entry"`
 }
 
@@ -81,22 +82,26 @@ type isLogEntry_Entry interface {
 }
 
 type LogEntry_Initialize struct {
-	Initialize *InitializeEntry `protobuf:"bytes,3,opt,name=initialize,proto3,oneof"`
+	Initialize *InitializeEntry `protobuf:"bytes,3,opt,name=initialize,proto3,oneof" json:"initialize,omitempty"`
 }
 type LogEntry_Configuration struct {
-	Configuration *ConfigurationEntry `protobuf:"bytes,4,opt,name=configuration,proto3,oneof"`
+	Configuration *ConfigurationEntry `protobuf:"bytes,4,opt,name=configuration,proto3,oneof" json:"configuration,omitempty"`
 }
 type LogEntry_Command struct {
-	Command *CommandEntry `protobuf:"bytes,5,opt,name=command,proto3,oneof"`
+	Command *CommandEntry `protobuf:"bytes,5,opt,name=command,proto3,oneof" json:"command,omitempty"`
 }
 type LogEntry_Query struct {
-	Query *QueryEntry `protobuf:"bytes,6,opt,name=query,proto3,oneof"`
+	Query *QueryEntry `protobuf:"bytes,6,opt,name=query,proto3,oneof" json:"query,omitempty"`
+}
+type LogEntry_Barrier struct {
+	Barrier *BarrierEntry `protobuf:"bytes,7,opt,name=barrier,proto3,oneof" json:"barrier,omitempty"`
 }
 
 func (*LogEntry_Initialize) isLogEntry_Entry()    {}
 func (*LogEntry_Configuration) isLogEntry_Entry() {}
 func (*LogEntry_Command) isLogEntry_Entry()       {}
 func (*LogEntry_Query) isLogEntry_Entry()         {}
+func (*LogEntry_Barrier) isLogEntry_Entry()       {}
 
 func (m *LogEntry) GetEntry() isLogEntry_Entry {
 	if m != nil {
@@ -147,116 +152,22 @@ func (m *LogEntry) GetQuery() *QueryEntry {
 	return nil
 }
 
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*LogEntry) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _LogEntry_OneofMarshaler, _LogEntry_OneofUnmarshaler, _LogEntry_OneofSizer, []interface{}{
-		(*LogEntry_Initialize)(nil),
-		(*LogEntry_Configuration)(nil),
-		(*LogEntry_Command)(nil),
-		(*LogEntry_Query)(nil),
-	}
-}
-
-func _LogEntry_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*LogEntry)
-	// entry
-	switch x := m.Entry.(type) {
-	case *LogEntry_Initialize:
-		_ = b.EncodeVarint(3<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Initialize); err != nil {
-			return err
-		}
-	case *LogEntry_Configuration:
-		_ = b.EncodeVarint(4<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Configuration); err != nil {
-			return err
-		}
-	case *LogEntry_Command:
-		_ = b.EncodeVarint(5<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Command); err != nil {
-			return err
-		}
-	case *LogEntry_Query:
-		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Query); err != nil {
-			return err
-		}
-	case nil:
-	default:
-		return fmt.Errorf("LogEntry.Entry has unexpected type %T", x)
+func (m *LogEntry) GetBarrier() *BarrierEntry {
+	if x, ok := m.GetEntry().(*LogEntry_Barrier); ok {
+		return x.Barrier
 	}
 	return nil
 }
 
-func _LogEntry_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*LogEntry)
-	switch tag {
-	case 3: // entry.initialize
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(InitializeEntry)
-		err := b.DecodeMessage(msg)
-		m.Entry = &LogEntry_Initialize{msg}
-		return true, err
-	case 4: // entry.configuration
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(ConfigurationEntry)
-		err := b.DecodeMessage(msg)
-		m.Entry = &LogEntry_Configuration{msg}
-		return true, err
-	case 5: // entry.command
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(CommandEntry)
-		err := b.DecodeMessage(msg)
-		m.Entry = &LogEntry_Command{msg}
-		return true, err
-	case 6: // entry.query
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(QueryEntry)
-		err := b.DecodeMessage(msg)
-		m.Entry = &LogEntry_Query{msg}
-		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _LogEntry_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*LogEntry)
-	// entry
-	switch x := m.Entry.(type) {
-	case *LogEntry_Initialize:
-		s := proto.Size(x.Initialize)
-		n += 1 // tag and wire
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *LogEntry_Configuration:
-		s := proto.Size(x.Configuration)
-		n += 1 // tag and wire
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *LogEntry_Command:
-		s := proto.Size(x.Command)
-		n += 1 // tag and wire
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *LogEntry_Query:
-		s := proto.Size(x.Query)
-		n += 1 // tag and wire
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*LogEntry) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*LogEntry_Initialize)(nil),
+		(*LogEntry_Configuration)(nil),
+		(*LogEntry_Command)(nil),
+		(*LogEntry_Query)(nil),
+		(*LogEntry_Barrier)(nil),
 	}
-	return n
 }
 
 type InitializeEntry struct {
@@ -295,6 +206,43 @@ func (m *InitializeEntry) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_InitializeEntry proto.InternalMessageInfo
 
+// BarrierEntry is a no-op entry that completes once all preceding entries have been applied
+type BarrierEntry struct {
+}
+
+func (m *BarrierEntry) Reset()         { *m = BarrierEntry{} }
+func (m *BarrierEntry) String() string { return proto.CompactTextString(m) }
+func (*BarrierEntry) ProtoMessage()    {}
+func (*BarrierEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_169d8cb0b7cb7546, []int{2}
+}
+func (m *BarrierEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BarrierEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BarrierEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BarrierEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BarrierEntry.Merge(m, src)
+}
+func (m *BarrierEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *BarrierEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_BarrierEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BarrierEntry proto.InternalMessageInfo
+
 type ConfigurationEntry struct {
 	Members []*Member `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
 }
@@ -303,7 +251,7 @@ func (m *ConfigurationEntry) Reset()         { *m = ConfigurationEntry{} }
 func (m *ConfigurationEntry) String() string { return proto.CompactTextString(m) }
 func (*ConfigurationEntry) ProtoMessage()    {}
 func (*ConfigurationEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_169d8cb0b7cb7546, []int{2}
+	return fileDescriptor_169d8cb0b7cb7546, []int{3}
 }
 func (m *ConfigurationEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -347,7 +295,7 @@ func (m *CommandEntry) Reset()         { *m = CommandEntry{} }
 func (m *CommandEntry) String() string { return proto.CompactTextString(m) }
 func (*CommandEntry) ProtoMessage()    {}
 func (*CommandEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_169d8cb0b7cb7546, []int{3}
+	return fileDescriptor_169d8cb0b7cb7546, []int{4}
 }
 func (m *CommandEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -391,7 +339,7 @@ func (m *QueryEntry) Reset()         { *m = QueryEntry{} }
 func (m *QueryEntry) String() string { return proto.CompactTextString(m) }
 func (*QueryEntry) ProtoMessage()    {}
 func (*QueryEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_169d8cb0b7cb7546, []int{4}
+	return fileDescriptor_169d8cb0b7cb7546, []int{5}
 }
 func (m *QueryEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -430,6 +378,7 @@ func (m *QueryEntry) GetValue() []byte {
 func init() {
 	proto.RegisterType((*LogEntry)(nil), "atomix.raft.protocol.LogEntry")
 	proto.RegisterType((*InitializeEntry)(nil), "atomix.raft.protocol.InitializeEntry")
+	proto.RegisterType((*BarrierEntry)(nil), "atomix.raft.protocol.BarrierEntry")
 	proto.RegisterType((*ConfigurationEntry)(nil), "atomix.raft.protocol.ConfigurationEntry")
 	proto.RegisterType((*CommandEntry)(nil), "atomix.raft.protocol.CommandEntry")
 	proto.RegisterType((*QueryEntry)(nil), "atomix.raft.protocol.QueryEntry")
@@ -438,33 +387,35 @@ func init() {
 func init() { proto.RegisterFile("atomix/raft/protocol/log.proto", fileDescriptor_169d8cb0b7cb7546) }
 
 var fileDescriptor_169d8cb0b7cb7546 = []byte{
-	// 413 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xc1, 0x8a, 0xd4, 0x30,
-	0x18, 0xc7, 0x1b, 0xa7, 0xdd, 0x19, 0xbf, 0x5d, 0x11, 0xc3, 0x1c, 0xca, 0xb0, 0xa4, 0x25, 0xac,
-	0xd0, 0x53, 0x0a, 0x2b, 0x88, 0x27, 0x0f, 0x15, 0x51, 0x61, 0x05, 0x2d, 0xfb, 0x02, 0x9d, 0x9a,
-	0x29, 0x81, 0xa6, 0x59, 0x33, 0xa9, 0xb8, 0x3e, 0x82, 0xa7, 0x7d, 0x0c, 0x1f, 0xc1, 0x47, 0xd8,
-	0xe3, 0x1e, 0x3d, 0xad, 0xda, 0x79, 0x09, 0xf1, 0x24, 0x4d, 0xcd, 0xce, 0xa8, 0xf5, 0x96, 0x7c,
-	0xdf, 0xef, 0xff, 0xfb, 0xbe, 0x10, 0x20, 0x85, 0x51, 0x52, 0xbc, 0x4f, 0x75, 0xb1, 0x32, 0xe9,
-	0x99, 0x56, 0x46, 0x95, 0xaa, 0x4e, 0x6b, 0x55, 0x31, 0x7b, 0xc1, 0xf3, 0xa1, 0xcf, 0xfa, 0x3e,
-	0x73, 0xfd, 0x05, 0x1d, 0x4d, 0x95, 0x75, 0xbb, 0x36, 0x5c, 0x0f, 0xd8, 0x22, 0xaa, 0x94, 0xaa,
-	0x6a, 0x3e, 0xb4, 0x97, 0xed, 0x2a, 0x35, 0x42, 0xf2, 0xb5, 0x29, 0xe4, 0xd9, 0x6f, 0x60, 0x5e,
-	0xa9, 0x4a, 0xd9, 0x63, 0xda, 0x9f, 0x86, 0x2a, 0xfd, 0x38, 0x81, 0xd9, 0x89, 0xaa, 0x9e, 0x36,
-	0x46, 0x9f, 0xe3, 0x43, 0xf0, 0x0d, 0xd7, 0x32, 0x44, 0x31, 0x4a, 0xfc, 0x6c, 0xf6, 0xf3, 0x3a,
-	0xf2, 0x4f, 0xb9, 0x96, 0xb9, 0xad, 0xe2, 0x0c, 0x6e, 0xdf, 0x38, 0xc3, 0x5b, 0x31, 0x4a, 0xf6,
-	0x8f, 0x17, 0x6c, 0x98, 0xca, 0xdc, 0x54, 0x76, 0xea, 0x88, 0x6c, 0x76, 0x79, 0x1d, 0x79, 0x17,
-	0x5f, 0x23, 0x94, 0x6f, 0x63, 0xf8, 0x19, 0x80, 0x68, 0x84, 0x11, 0x45, 0x2d, 0x3e, 0xf0, 0x70,
-	0x62, 0x25, 0xf7, 0xd9, 0xd8, 0xa3, 0xd9, 0x8b, 0x1b, 0xce, 0x2e, 0xf7, 0xdc, 0xcb, 0x77, 0xa2,
-	0xf8, 0x15, 0xdc, 0x29, 0x55, 0xb3, 0x12, 0x55, 0xab, 0x0b, 0x23, 0x54, 0x13, 0xfa, 0xd6, 0x95,
-	0x8c, 0xbb, 0x9e, 0xec, 0xa2, 0x4e, 0xf7, 0xa7, 0x00, 0x3f, 0x86, 0x69, 0xa9, 0xa4, 0x2c, 0x9a,
-	0x37, 0x61, 0x60, 0x5d, 0xf4, 0x7f, 0x2e, 0x0b, 0x39, 0x8b, 0x0b, 0xe1, 0x47, 0x10, 0xbc, 0x6d,
-	0xb9, 0x3e, 0x0f, 0xf7, 0x6c, 0x3a, 0x1e, 0x4f, 0xbf, 0xee, 0x11, 0x97, 0x1d, 0x02, 0xd9, 0x14,
-	0x02, 0xde, 0x57, 0xe8, 0x3d, 0xb8, 0xfb, 0xd7, 0xab, 0xe9, 0x09, 0xe0, 0x7f, 0x97, 0xc7, 0x0f,
-	0x61, 0x2a, 0xb9, 0x5c, 0x72, 0xbd, 0x0e, 0x51, 0x3c, 0x49, 0xf6, 0x8f, 0x0f, 0xc7, 0xa7, 0xbd,
-	0xb4, 0x50, 0xee, 0x60, 0x7a, 0x04, 0x07, 0xbb, 0xeb, 0xe3, 0x39, 0x04, 0xef, 0x8a, 0xba, 0xe5,
-	0xf6, 0xc7, 0x0f, 0xf2, 0xe1, 0x42, 0x29, 0xc0, 0x76, 0xcd, 0x71, 0x26, 0x3b, 0xfa, 0xf1, 0x9d,
-	0xa0, 0x4f, 0x1d, 0x41, 0x9f, 0x3b, 0x82, 0x2e, 0x3b, 0x82, 0xae, 0x3a, 0x82, 0xbe, 0x75, 0x04,
-	0x5d, 0x6c, 0x88, 0x77, 0xb5, 0x21, 0xde, 0x97, 0x0d, 0xf1, 0x96, 0x7b, 0x76, 0x93, 0x07, 0xbf,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xd8, 0x9a, 0xdf, 0x97, 0xf7, 0x02, 0x00, 0x00,
+	// 434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0x41, 0x6e, 0x13, 0x31,
+	0x14, 0x86, 0xc7, 0x24, 0x69, 0xc2, 0x6b, 0x00, 0x61, 0x65, 0x31, 0x8a, 0x2a, 0x27, 0xb2, 0x8a,
+	0x94, 0x95, 0x47, 0x2a, 0x12, 0x62, 0xc5, 0x62, 0x10, 0x02, 0xa4, 0x22, 0xc1, 0xa8, 0x17, 0x70,
+	0x82, 0x33, 0xb2, 0x34, 0x1e, 0x17, 0xc7, 0x83, 0x28, 0xa7, 0xe8, 0x31, 0x38, 0x02, 0x47, 0xa8,
+	0x58, 0x75, 0xc9, 0xaa, 0xc0, 0xe4, 0x12, 0x88, 0x15, 0x1a, 0xbb, 0x4e, 0x02, 0x0c, 0xdd, 0xd9,
+	0xef, 0x7d, 0xff, 0xff, 0x3f, 0x3f, 0x19, 0x08, 0xb7, 0x5a, 0xc9, 0x0f, 0x89, 0xe1, 0x4b, 0x9b,
+	0x9c, 0x1a, 0x6d, 0xf5, 0x42, 0x17, 0x49, 0xa1, 0x73, 0xe6, 0x2e, 0x78, 0xe4, 0xfb, 0xac, 0xe9,
+	0xb3, 0xd0, 0x1f, 0xd3, 0x56, 0xd5, 0xa2, 0xa8, 0x56, 0x56, 0x18, 0x8f, 0x8d, 0x27, 0xb9, 0xd6,
+	0x79, 0x21, 0x7c, 0x7b, 0x5e, 0x2d, 0x13, 0x2b, 0x95, 0x58, 0x59, 0xae, 0x4e, 0xaf, 0x81, 0x51,
+	0xae, 0x73, 0xed, 0x8e, 0x49, 0x73, 0xf2, 0x55, 0xfa, 0xa5, 0x03, 0x83, 0x63, 0x9d, 0x3f, 0x2b,
+	0xad, 0x39, 0xc3, 0x07, 0xd0, 0xb5, 0xc2, 0xa8, 0x18, 0x4d, 0xd1, 0xac, 0x9b, 0x0e, 0x7e, 0x5d,
+	0x4d, 0xba, 0x27, 0xc2, 0xa8, 0xcc, 0x55, 0x71, 0x0a, 0xb7, 0x37, 0x9e, 0xf1, 0xad, 0x29, 0x9a,
+	0xed, 0x1f, 0x8d, 0x99, 0x4f, 0x65, 0x21, 0x95, 0x9d, 0x04, 0x22, 0x1d, 0x5c, 0x5c, 0x4d, 0xa2,
+	0xf3, 0x6f, 0x13, 0x94, 0x6d, 0x65, 0xf8, 0x39, 0x80, 0x2c, 0xa5, 0x95, 0xbc, 0x90, 0x1f, 0x45,
+	0xdc, 0x71, 0x26, 0x0f, 0x58, 0xdb, 0xa3, 0xd9, 0xcb, 0x0d, 0xe7, 0x86, 0x7b, 0x11, 0x65, 0x3b,
+	0x52, 0xfc, 0x1a, 0xee, 0x2c, 0x74, 0xb9, 0x94, 0x79, 0x65, 0xb8, 0x95, 0xba, 0x8c, 0xbb, 0xce,
+	0x6b, 0xd6, 0xee, 0xf5, 0x74, 0x17, 0x0d, 0x76, 0x7f, 0x1a, 0xe0, 0x27, 0xd0, 0x5f, 0x68, 0xa5,
+	0x78, 0xf9, 0x36, 0xee, 0x39, 0x2f, 0xfa, 0x3f, 0x2f, 0x07, 0x05, 0x97, 0x20, 0xc2, 0x8f, 0xa1,
+	0xf7, 0xae, 0x12, 0xe6, 0x2c, 0xde, 0x73, 0xea, 0x69, 0xbb, 0xfa, 0x4d, 0x83, 0x04, 0xad, 0x17,
+	0x34, 0xc9, 0x73, 0x6e, 0x8c, 0x14, 0x26, 0xee, 0xdf, 0x94, 0x9c, 0x7a, 0x68, 0x93, 0x7c, 0x2d,
+	0x4a, 0xfb, 0xd0, 0x13, 0x4d, 0x8d, 0xde, 0x87, 0x7b, 0x7f, 0x6d, 0x8d, 0xde, 0x85, 0xe1, 0xae,
+	0x8c, 0x1e, 0x03, 0xfe, 0x77, 0x19, 0xf8, 0x11, 0xf4, 0x95, 0x50, 0x73, 0x61, 0x56, 0x31, 0x9a,
+	0x76, 0x66, 0xfb, 0x47, 0x07, 0xed, 0x13, 0xbc, 0x72, 0x50, 0x16, 0x60, 0x7a, 0x08, 0xc3, 0xdd,
+	0x75, 0xe0, 0x11, 0xf4, 0xde, 0xf3, 0xa2, 0x12, 0xee, 0x07, 0x0d, 0x33, 0x7f, 0xa1, 0x14, 0x60,
+	0xfb, 0xec, 0x76, 0x26, 0x3d, 0xfc, 0xf9, 0x83, 0xa0, 0x4f, 0x35, 0x41, 0x9f, 0x6b, 0x82, 0x2e,
+	0x6a, 0x82, 0x2e, 0x6b, 0x82, 0xbe, 0xd7, 0x04, 0x9d, 0xaf, 0x49, 0x74, 0xb9, 0x26, 0xd1, 0xd7,
+	0x35, 0x89, 0xe6, 0x7b, 0x6e, 0x92, 0x87, 0xbf, 0x07, 0x00, 0xce, 0x74, 0xd5, 0x2a, 0x47, 0x03,
+	0x00, 0x00,
 }
 
 func (this *LogEntry) Equal(that interface{}) bool {
@@ -599,6 +550,30 @@ func (this *LogEntry_Query) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *LogEntry_Barrier) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LogEntry_Barrier)
+	if !ok {
+		that2, ok := that.(LogEntry_Barrier)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Barrier.Equal(that1.Barrier) {
+		return false
+	}
+	return true
+}
 func (this *InitializeEntry) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -620,6 +595,27 @@ func (this *InitializeEntry) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *BarrierEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*BarrierEntry)
+	if !ok {
+		that2, ok := that.(BarrierEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
 func (this *ConfigurationEntry) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -743,7 +739,8 @@ func (m *LogEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 }
 
 func (m *LogEntry_Initialize) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *LogEntry_Initialize) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -763,7 +760,8 @@ func (m *LogEntry_Initialize) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *LogEntry_Configuration) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *LogEntry_Configuration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -783,7 +781,8 @@ func (m *LogEntry_Configuration) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 func (m *LogEntry_Command) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *LogEntry_Command) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -803,7 +802,8 @@ func (m *LogEntry_Command) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *LogEntry_Query) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *LogEntry_Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -822,6 +822,27 @@ func (m *LogEntry_Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *LogEntry_Barrier) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LogEntry_Barrier) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Barrier != nil {
+		{
+			size, err := m.Barrier.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintLog(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	return len(dAtA) - i, nil
+}
 func (m *InitializeEntry) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -845,6 +866,29 @@ func (m *InitializeEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *BarrierEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BarrierEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BarrierEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
 func (m *ConfigurationEntry) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -958,7 +1002,7 @@ func NewPopulatedLogEntry(r randyLog, easy bool) *LogEntry {
 	this.Term = Term(uint64(r.Uint32()))
 	v1 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
 	this.Timestamp = *v1
-	oneofNumber_Entry := []int32{3, 4, 5, 6}[r.Intn(4)]
+	oneofNumber_Entry := []int32{3, 4, 5, 6, 7}[r.Intn(5)]
 	switch oneofNumber_Entry {
 	case 3:
 		this.Entry = NewPopulatedLogEntry_Initialize(r, easy)
@@ -968,6 +1012,8 @@ func NewPopulatedLogEntry(r randyLog, easy bool) *LogEntry {
 		this.Entry = NewPopulatedLogEntry_Command(r, easy)
 	case 6:
 		this.Entry = NewPopulatedLogEntry_Query(r, easy)
+	case 7:
+		this.Entry = NewPopulatedLogEntry_Barrier(r, easy)
 	}
 	if !easy && r.Intn(10) != 0 {
 	}
@@ -994,6 +1040,11 @@ func NewPopulatedLogEntry_Query(r randyLog, easy bool) *LogEntry_Query {
 	this.Query = NewPopulatedQueryEntry(r, easy)
 	return this
 }
+func NewPopulatedLogEntry_Barrier(r randyLog, easy bool) *LogEntry_Barrier {
+	this := &LogEntry_Barrier{}
+	this.Barrier = NewPopulatedBarrierEntry(r, easy)
+	return this
+}
 func NewPopulatedInitializeEntry(r randyLog, easy bool) *InitializeEntry {
 	this := &InitializeEntry{}
 	if !easy && r.Intn(10) != 0 {
@@ -1001,6 +1052,13 @@ func NewPopulatedInitializeEntry(r randyLog, easy bool) *InitializeEntry {
 	return this
 }
 
+func NewPopulatedBarrierEntry(r randyLog, easy bool) *BarrierEntry {
+	this := &BarrierEntry{}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
 func NewPopulatedConfigurationEntry(r randyLog, easy bool) *ConfigurationEntry {
 	this := &ConfigurationEntry{}
 	if r.Intn(5) != 0 {
@@ -1176,6 +1234,18 @@ func (m *LogEntry_Query) Size() (n int) {
 	}
 	return n
 }
+func (m *LogEntry_Barrier) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Barrier != nil {
+		l = m.Barrier.Size()
+		n += 1 + l + sovLog(uint64(l))
+	}
+	return n
+}
 func (m *InitializeEntry) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1185,6 +1255,15 @@ func (m *InitializeEntry) Size() (n int) {
 	return n
 }
 
+func (m *BarrierEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
 func (m *ConfigurationEntry) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1453,6 +1532,41 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 			}
 			m.Entry = &LogEntry_Query{v}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Barrier", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLog
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLog
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLog
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BarrierEntry{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Entry = &LogEntry_Barrier{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLog(dAtA[iNdEx:])
@@ -1530,6 +1644,59 @@ func (m *InitializeEntry) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *BarrierEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLog
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BarrierEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BarrierEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLog(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLog
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLog
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ConfigurationEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -14,9 +14,13 @@
 
 package protocol
 
+import "sync"
+
 // newMemoryMetadataStore creates a new in-memory metadata store
 func newMemoryMetadataStore() MetadataStore {
-	return &memoryMetadataStore{}
+	return &memoryMetadataStore{
+		matchIndexes: make(map[MemberID]Index),
+	}
 }
 
 // MetadataStore stores metadata for a Raft server
@@ -33,14 +37,48 @@ type MetadataStore interface {
 	// LoadVote loads the Raft vote
 	LoadVote() *MemberID
 
+	// StoreMatchIndex stores the last known match index for the given member
+	// Match indexes may be stored concurrently with other metadata.
+	StoreMatchIndex(member MemberID, index Index)
+
+	// LoadMatchIndex loads the last known match index for the given member
+	LoadMatchIndex(member MemberID) *Index
+
+	// StoreLastApplied stores the index of the last entry durably reflected by a persistent state machine
+	StoreLastApplied(index Index)
+
+	// LoadLastApplied loads the index of the last entry durably reflected by a persistent state machine
+	LoadLastApplied() *Index
+
+	// StoreConfiguration stores the committed cluster configuration
+	StoreConfiguration(configuration *Configuration)
+
+	// LoadConfiguration loads the committed cluster configuration
+	LoadConfiguration() *Configuration
+
+	// StoreWitnessRecord stores the last entry in the leader's log recorded by a witness
+	StoreWitnessRecord(record *WitnessRecord)
+
+	// LoadWitnessRecord loads the last entry in the leader's log recorded by a witness
+	LoadWitnessRecord() *WitnessRecord
+
+	// Sync blocks until all stored terms and votes are durable
+	// Sync must be called before the term or vote is exposed to other members.
+	Sync() error
+
 	// Close closes the store
 	Close() error
 }
 
 // memoryMetadataStore implements MetadataStore in memory
 type memoryMetadataStore struct {
-	term *Term
-	vote *MemberID
+	term         *Term
+	vote         *MemberID
+	lastApplied  *Index
+	config       *Configuration
+	witness      *WitnessRecord
+	matchIndexes map[MemberID]Index
+	mu           sync.RWMutex
 }
 
 func (s *memoryMetadataStore) StoreTerm(term Term) {
@@ -59,6 +97,62 @@ func (s *memoryMetadataStore) LoadVote() *MemberID {
 	return s.vote
 }
 
+func (s *memoryMetadataStore) StoreMatchIndex(member MemberID, index Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matchIndexes[member] = index
+}
+
+func (s *memoryMetadataStore) LoadMatchIndex(member MemberID) *Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	index, ok := s.matchIndexes[member]
+	if !ok {
+		return nil
+	}
+	return &index
+}
+
+func (s *memoryMetadataStore) StoreLastApplied(index Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastApplied = &index
+}
+
+func (s *memoryMetadataStore) LoadLastApplied() *Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastApplied
+}
+
+func (s *memoryMetadataStore) StoreConfiguration(configuration *Configuration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = configuration
+}
+
+func (s *memoryMetadataStore) LoadConfiguration() *Configuration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+func (s *memoryMetadataStore) StoreWitnessRecord(record *WitnessRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.witness = record
+}
+
+func (s *memoryMetadataStore) LoadWitnessRecord() *WitnessRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.witness
+}
+
+func (s *memoryMetadataStore) Sync() error {
+	return nil
+}
+
 func (s *memoryMetadataStore) Close() error {
 	return nil
 }
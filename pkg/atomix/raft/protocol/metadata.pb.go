@@ -29,8 +29,12 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 // Raft system metadata
 type Metadata struct {
-	Term Term     `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
-	Vote MemberID `protobuf:"bytes,2,opt,name=vote,proto3,casttype=MemberID" json:"vote,omitempty"`
+	Term          Term           `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Vote          MemberID       `protobuf:"bytes,2,opt,name=vote,proto3,casttype=MemberID" json:"vote,omitempty"`
+	LastApplied   Index          `protobuf:"varint,3,opt,name=last_applied,json=lastApplied,proto3,casttype=Index" json:"last_applied,omitempty"`
+	Configuration *Configuration `protobuf:"bytes,4,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	WitnessRecord *WitnessRecord `protobuf:"bytes,5,opt,name=witness_record,json=witnessRecord,proto3" json:"witness_record,omitempty"`
+	MatchIndexes  []*MatchIndex  `protobuf:"bytes,6,rep,name=match_indexes,json=matchIndexes,proto3" json:"match_indexes,omitempty"`
 }
 
 func (m *Metadata) Reset()         { *m = Metadata{} }
@@ -80,6 +84,140 @@ func (m *Metadata) GetVote() MemberID {
 	return ""
 }
 
+func (m *Metadata) GetLastApplied() Index {
+	if m != nil {
+		return m.LastApplied
+	}
+	return 0
+}
+
+func (m *Metadata) GetConfiguration() *Configuration {
+	if m != nil {
+		return m.Configuration
+	}
+	return nil
+}
+
+func (m *Metadata) GetWitnessRecord() *WitnessRecord {
+	if m != nil {
+		return m.WitnessRecord
+	}
+	return nil
+}
+
+func (m *Metadata) GetMatchIndexes() []*MatchIndex {
+	if m != nil {
+		return m.MatchIndexes
+	}
+	return nil
+}
+
+// MatchIndex is the last known match index of a member
+type MatchIndex struct {
+	MemberID MemberID `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3,casttype=MemberID" json:"member_id,omitempty"`
+	Index    Index    `protobuf:"varint,2,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
+}
+
+func (m *MatchIndex) Reset()         { *m = MatchIndex{} }
+func (m *MatchIndex) String() string { return proto.CompactTextString(m) }
+func (*MatchIndex) ProtoMessage()    {}
+func (*MatchIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1c93df0fbe03b7c, []int{1}
+}
+func (m *MatchIndex) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MatchIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MatchIndex.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MatchIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatchIndex.Merge(m, src)
+}
+func (m *MatchIndex) XXX_Size() int {
+	return m.Size()
+}
+func (m *MatchIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatchIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatchIndex proto.InternalMessageInfo
+
+func (m *MatchIndex) GetMemberID() MemberID {
+	if m != nil {
+		return m.MemberID
+	}
+	return ""
+}
+
+func (m *MatchIndex) GetIndex() Index {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+// WitnessRecord is the index and term of the last entry in the leader's log recorded by a witness
+type WitnessRecord struct {
+	Index Index `protobuf:"varint,1,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
+	Term  Term  `protobuf:"varint,2,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+}
+
+func (m *WitnessRecord) Reset()         { *m = WitnessRecord{} }
+func (m *WitnessRecord) String() string { return proto.CompactTextString(m) }
+func (*WitnessRecord) ProtoMessage()    {}
+func (*WitnessRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b1c93df0fbe03b7c, []int{2}
+}
+func (m *WitnessRecord) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WitnessRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WitnessRecord.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WitnessRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WitnessRecord.Merge(m, src)
+}
+func (m *WitnessRecord) XXX_Size() int {
+	return m.Size()
+}
+func (m *WitnessRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_WitnessRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WitnessRecord proto.InternalMessageInfo
+
+func (m *WitnessRecord) GetIndex() Index {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *WitnessRecord) GetTerm() Term {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
 // Raft system configuration
 type Configuration struct {
 	Index     Index      `protobuf:"varint,1,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
@@ -92,7 +230,7 @@ func (m *Configuration) Reset()         { *m = Configuration{} }
 func (m *Configuration) String() string { return proto.CompactTextString(m) }
 func (*Configuration) ProtoMessage()    {}
 func (*Configuration) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b1c93df0fbe03b7c, []int{1}
+	return fileDescriptor_b1c93df0fbe03b7c, []int{3}
 }
 func (m *Configuration) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -151,6 +289,8 @@ func (m *Configuration) GetMembers() []*Member {
 
 func init() {
 	proto.RegisterType((*Metadata)(nil), "atomix.raft.protocol.Metadata")
+	proto.RegisterType((*MatchIndex)(nil), "atomix.raft.protocol.MatchIndex")
+	proto.RegisterType((*WitnessRecord)(nil), "atomix.raft.protocol.WitnessRecord")
 	proto.RegisterType((*Configuration)(nil), "atomix.raft.protocol.Configuration")
 }
 
@@ -159,28 +299,37 @@ func init() {
 }
 
 var fileDescriptor_b1c93df0fbe03b7c = []byte{
-	// 326 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0x31, 0x6e, 0xc2, 0x30,
-	0x14, 0x86, 0x79, 0x60, 0x5a, 0x30, 0xed, 0x12, 0x31, 0x44, 0x08, 0xd9, 0x11, 0xed, 0x90, 0xc9,
-	0x91, 0xa8, 0xd4, 0xb1, 0x43, 0xda, 0x85, 0x4a, 0x2c, 0x11, 0x17, 0x30, 0x60, 0xa2, 0x48, 0x18,
-	0x23, 0x63, 0x2a, 0x8e, 0xc1, 0x31, 0x7a, 0x84, 0x9e, 0xa0, 0xea, 0xc8, 0xd8, 0x89, 0xb6, 0xe1,
-	0x12, 0x15, 0x53, 0x85, 0x4d, 0xe8, 0xc2, 0xf6, 0xf4, 0xbd, 0xef, 0xf9, 0xbd, 0xdf, 0xf8, 0x86,
-	0x1b, 0x25, 0xb3, 0x55, 0xa4, 0xf9, 0xc4, 0x44, 0x73, 0xad, 0x8c, 0x1a, 0xa9, 0x69, 0x24, 0x85,
-	0xe1, 0x63, 0x6e, 0x38, 0xb3, 0xc4, 0x6b, 0x3a, 0x89, 0x1d, 0x24, 0x56, 0x48, 0xad, 0xce, 0xd9,
-	0xd1, 0xd1, 0x74, 0xb9, 0x30, 0x42, 0x3b, 0xad, 0x45, 0x53, 0xa5, 0xd2, 0xa9, 0x70, 0xed, 0xe1,
-	0x72, 0x12, 0x99, 0x4c, 0x8a, 0x85, 0xe1, 0x72, 0x7e, 0x14, 0x9a, 0xa9, 0x4a, 0x95, 0x2d, 0xa3,
-	0x43, 0xe5, 0x68, 0xe7, 0x19, 0xd7, 0xfa, 0xc7, 0x13, 0xbc, 0x36, 0x46, 0x46, 0x68, 0xe9, 0x43,
-	0x00, 0x21, 0x8a, 0x6b, 0xfb, 0x2d, 0x45, 0x03, 0xa1, 0x65, 0x62, 0xa9, 0x17, 0x60, 0xf4, 0xa2,
-	0x8c, 0xf0, 0xcb, 0x01, 0x84, 0xf5, 0xf8, 0x6a, 0xbf, 0xa5, 0xb5, 0xbe, 0x90, 0x43, 0xa1, 0x7b,
-	0x4f, 0x89, 0xed, 0x74, 0xde, 0x01, 0x5f, 0x3f, 0xaa, 0xd9, 0x24, 0x4b, 0x97, 0x9a, 0x9b, 0x4c,
-	0xcd, 0x3c, 0x8a, 0xab, 0xd9, 0x6c, 0x2c, 0x56, 0xc7, 0x27, 0xeb, 0xfb, 0x2d, 0xad, 0xf6, 0x0e,
-	0x20, 0x71, 0xfc, 0xb4, 0xb2, 0x7c, 0x76, 0xe5, 0x03, 0xae, 0x9f, 0x52, 0xf8, 0x95, 0x00, 0xc2,
-	0x46, 0xb7, 0xc5, 0x5c, 0x4e, 0x56, 0xe4, 0x64, 0x83, 0xc2, 0x88, 0xd1, 0xfa, 0x8b, 0x42, 0xf2,
-	0x3f, 0xe2, 0xdd, 0xe3, 0x4b, 0x69, 0x4f, 0x5c, 0xf8, 0x28, 0xa8, 0x84, 0x8d, 0x6e, 0x9b, 0x9d,
-	0xfb, 0x5f, 0xe6, 0x72, 0x24, 0x85, 0x1c, 0xdf, 0xfe, 0xfe, 0x10, 0x78, 0xcd, 0x09, 0xbc, 0xe5,
-	0x04, 0x3e, 0x72, 0x02, 0x9b, 0x9c, 0xc0, 0x77, 0x4e, 0x60, 0xbd, 0x23, 0xa5, 0xcd, 0x8e, 0x94,
-	0x3e, 0x77, 0xa4, 0x34, 0xbc, 0xb0, 0xf3, 0x77, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x45, 0xc7,
-	0x59, 0xe1, 0xd9, 0x01, 0x00, 0x00,
+	// 473 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xcf, 0x8e, 0xd3, 0x30,
+	0x10, 0xc6, 0xd7, 0xdd, 0x74, 0x69, 0xdc, 0x86, 0x83, 0xb5, 0x07, 0xab, 0x5a, 0x39, 0x51, 0x96,
+	0x43, 0x0f, 0x28, 0x91, 0x8a, 0xe0, 0x88, 0x44, 0x80, 0x43, 0x91, 0xca, 0x21, 0x5a, 0x89, 0x63,
+	0xe5, 0x26, 0x6e, 0x88, 0x14, 0xd7, 0x95, 0xe3, 0xb2, 0xfb, 0x18, 0xfb, 0x18, 0x3c, 0x02, 0x4f,
+	0x80, 0x38, 0xee, 0x09, 0x71, 0x2a, 0x90, 0xbe, 0x04, 0xea, 0x09, 0xc5, 0xde, 0xfe, 0x43, 0x81,
+	0x0b, 0xb7, 0xf1, 0xcc, 0xef, 0x1b, 0x8d, 0xbf, 0x19, 0x78, 0x49, 0x95, 0xe0, 0xf9, 0x4d, 0x28,
+	0xe9, 0x4c, 0x85, 0x0b, 0x29, 0x94, 0x48, 0x44, 0x11, 0x72, 0xa6, 0x68, 0x4a, 0x15, 0x0d, 0x74,
+	0x06, 0x9d, 0x1b, 0x28, 0xa8, 0xa1, 0x60, 0x0b, 0xf5, 0xfd, 0x46, 0x69, 0x52, 0x2c, 0x4b, 0xc5,
+	0xa4, 0xc1, 0xfa, 0x6e, 0x26, 0x44, 0x56, 0x30, 0x53, 0x9e, 0x2e, 0x67, 0xa1, 0xca, 0x39, 0x2b,
+	0x15, 0xe5, 0x8b, 0x7b, 0xe0, 0x3c, 0x13, 0x99, 0xd0, 0x61, 0x58, 0x47, 0x26, 0xeb, 0x7f, 0x6d,
+	0xc1, 0xce, 0xf8, 0x7e, 0x06, 0x74, 0x01, 0x2d, 0xc5, 0x24, 0xc7, 0xc0, 0x03, 0x03, 0x2b, 0xea,
+	0x6c, 0x56, 0xae, 0x75, 0xc5, 0x24, 0x8f, 0x75, 0x16, 0x79, 0xd0, 0xfa, 0x20, 0x14, 0xc3, 0x2d,
+	0x0f, 0x0c, 0xec, 0xa8, 0xb7, 0x59, 0xb9, 0x9d, 0x31, 0xe3, 0x53, 0x26, 0x47, 0xaf, 0x62, 0x5d,
+	0x41, 0x8f, 0x61, 0xaf, 0xa0, 0xa5, 0x9a, 0xd0, 0xc5, 0xa2, 0xc8, 0x59, 0x8a, 0x4f, 0x75, 0x1f,
+	0x7b, 0xb3, 0x72, 0xdb, 0xa3, 0x79, 0xca, 0x6e, 0xe2, 0x6e, 0x5d, 0x7e, 0x61, 0xaa, 0x68, 0x04,
+	0x9d, 0x44, 0xcc, 0x67, 0x79, 0xb6, 0x94, 0x54, 0xe5, 0x62, 0x8e, 0x2d, 0x0f, 0x0c, 0xba, 0xc3,
+	0xcb, 0xa0, 0xc9, 0x83, 0xe0, 0xe5, 0x21, 0x1a, 0x1f, 0x2b, 0xd1, 0x1b, 0xf8, 0xf0, 0x3a, 0x57,
+	0x73, 0x56, 0x96, 0x13, 0xc9, 0x12, 0x21, 0x53, 0xdc, 0xfe, 0x57, 0xaf, 0x77, 0x86, 0x8d, 0x35,
+	0x1a, 0x3b, 0xd7, 0x87, 0x4f, 0xf4, 0x1a, 0x3a, 0x9c, 0xaa, 0xe4, 0xfd, 0x24, 0xaf, 0x47, 0x66,
+	0x25, 0x3e, 0xf3, 0x4e, 0x07, 0xdd, 0xa1, 0xd7, 0xdc, 0x6a, 0x5c, 0xa3, 0xe6, 0x73, 0x3d, 0xbe,
+	0x8b, 0x59, 0xe9, 0xa7, 0x10, 0xee, 0x6b, 0xe8, 0x29, 0xb4, 0xb9, 0xf6, 0x6a, 0x92, 0xa7, 0xda,
+	0x5e, 0x3b, 0xc2, 0xd5, 0x81, 0x81, 0x47, 0x66, 0x76, 0x0c, 0x3a, 0x4a, 0x91, 0x0b, 0xdb, 0x7a,
+	0x0a, 0xdc, 0xfa, 0xd3, 0x49, 0x93, 0xf7, 0xdf, 0x42, 0xe7, 0xe8, 0x33, 0x7b, 0x05, 0x68, 0x56,
+	0xec, 0x76, 0xdc, 0x6a, 0xda, 0xb1, 0xff, 0x19, 0x40, 0xe7, 0xc8, 0xe9, 0xff, 0x6c, 0x88, 0x9e,
+	0x43, 0x7b, 0x77, 0x88, 0xfa, 0x1e, 0xba, 0xc3, 0x7e, 0x60, 0x4e, 0x35, 0xd8, 0x9e, 0x6a, 0x70,
+	0xb5, 0x25, 0x22, 0xeb, 0xf6, 0xbb, 0x0b, 0xe2, 0xbd, 0x04, 0x3d, 0x83, 0x0f, 0x8c, 0x1b, 0x25,
+	0xb6, 0xf4, 0x1e, 0x2e, 0xfe, 0xb2, 0x07, 0x0d, 0xc5, 0x5b, 0x38, 0x7a, 0xf4, 0xeb, 0x27, 0x01,
+	0x1f, 0x2b, 0x02, 0x3e, 0x55, 0x04, 0x7c, 0xa9, 0x08, 0xb8, 0xab, 0x08, 0xf8, 0x51, 0x11, 0x70,
+	0xbb, 0x26, 0x27, 0x77, 0x6b, 0x72, 0xf2, 0x6d, 0x4d, 0x4e, 0xa6, 0x67, 0x5a, 0xff, 0xe4, 0xf7,
+	0x00, 0x27, 0x61, 0x09, 0x43, 0x9c, 0x03, 0x00, 0x00,
 }
 
 func (this *Metadata) Equal(that interface{}) bool {
@@ -208,6 +357,77 @@ func (this *Metadata) Equal(that interface{}) bool {
 	if this.Vote != that1.Vote {
 		return false
 	}
+	if this.LastApplied != that1.LastApplied {
+		return false
+	}
+	if !this.Configuration.Equal(that1.Configuration) {
+		return false
+	}
+	if !this.WitnessRecord.Equal(that1.WitnessRecord) {
+		return false
+	}
+	if len(this.MatchIndexes) != len(that1.MatchIndexes) {
+		return false
+	}
+	for i := range this.MatchIndexes {
+		if !this.MatchIndexes[i].Equal(that1.MatchIndexes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *MatchIndex) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MatchIndex)
+	if !ok {
+		that2, ok := that.(MatchIndex)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MemberID != that1.MemberID {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	return true
+}
+func (this *WitnessRecord) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*WitnessRecord)
+	if !ok {
+		that2, ok := that.(WitnessRecord)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	if this.Term != that1.Term {
+		return false
+	}
 	return true
 }
 func (this *Configuration) Equal(that interface{}) bool {
@@ -272,6 +492,49 @@ func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.MatchIndexes) > 0 {
+		for iNdEx := len(m.MatchIndexes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.MatchIndexes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMetadata(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.WitnessRecord != nil {
+		{
+			size, err := m.WitnessRecord.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMetadata(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Configuration != nil {
+		{
+			size, err := m.Configuration.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMetadata(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.LastApplied != 0 {
+		i = encodeVarintMetadata(dAtA, i, uint64(m.LastApplied))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.Vote) > 0 {
 		i -= len(m.Vote)
 		copy(dAtA[i:], m.Vote)
@@ -287,6 +550,74 @@ func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *MatchIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MatchIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MatchIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Index != 0 {
+		i = encodeVarintMetadata(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.MemberID) > 0 {
+		i -= len(m.MemberID)
+		copy(dAtA[i:], m.MemberID)
+		i = encodeVarintMetadata(dAtA, i, uint64(len(m.MemberID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WitnessRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WitnessRecord) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WitnessRecord) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Term != 0 {
+		i = encodeVarintMetadata(dAtA, i, uint64(m.Term))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Index != 0 {
+		i = encodeVarintMetadata(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Configuration) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -322,12 +653,12 @@ func (m *Configuration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		}
 	}
 	if m.Timestamp != nil {
-		n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Timestamp):])
-		if err1 != nil {
-			return 0, err1
+		n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Timestamp):])
+		if err3 != nil {
+			return 0, err3
 		}
-		i -= n1
-		i = encodeVarintMetadata(dAtA, i, uint64(n1))
+		i -= n3
+		i = encodeVarintMetadata(dAtA, i, uint64(n3))
 		i--
 		dAtA[i] = 0x1a
 	}
@@ -359,6 +690,38 @@ func NewPopulatedMetadata(r randyMetadata, easy bool) *Metadata {
 	this := &Metadata{}
 	this.Term = Term(uint64(r.Uint32()))
 	this.Vote = MemberID(randStringMetadata(r))
+	this.LastApplied = Index(uint64(r.Uint32()))
+	if r.Intn(5) != 0 {
+		this.Configuration = NewPopulatedConfiguration(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		this.WitnessRecord = NewPopulatedWitnessRecord(r, easy)
+	}
+	if r.Intn(5) != 0 {
+		v1 := r.Intn(5)
+		this.MatchIndexes = make([]*MatchIndex, v1)
+		for i := 0; i < v1; i++ {
+			this.MatchIndexes[i] = NewPopulatedMatchIndex(r, easy)
+		}
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedMatchIndex(r randyMetadata, easy bool) *MatchIndex {
+	this := &MatchIndex{}
+	this.MemberID = MemberID(randStringMetadata(r))
+	this.Index = Index(uint64(r.Uint32()))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedWitnessRecord(r randyMetadata, easy bool) *WitnessRecord {
+	this := &WitnessRecord{}
+	this.Index = Index(uint64(r.Uint32()))
+	this.Term = Term(uint64(r.Uint32()))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -372,9 +735,9 @@ func NewPopulatedConfiguration(r randyMetadata, easy bool) *Configuration {
 		this.Timestamp = github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
 	}
 	if r.Intn(5) != 0 {
-		v1 := r.Intn(5)
-		this.Members = make([]*Member, v1)
-		for i := 0; i < v1; i++ {
+		v2 := r.Intn(5)
+		this.Members = make([]*Member, v2)
+		for i := 0; i < v2; i++ {
 			this.Members[i] = NewPopulatedMember(r, easy)
 		}
 	}
@@ -402,9 +765,9 @@ func randUTF8RuneMetadata(r randyMetadata) rune {
 	return rune(ru + 61)
 }
 func randStringMetadata(r randyMetadata) string {
-	v2 := r.Intn(100)
-	tmps := make([]rune, v2)
-	for i := 0; i < v2; i++ {
+	v3 := r.Intn(100)
+	tmps := make([]rune, v3)
+	for i := 0; i < v3; i++ {
 		tmps[i] = randUTF8RuneMetadata(r)
 	}
 	return string(tmps)
@@ -426,11 +789,11 @@ func randFieldMetadata(dAtA []byte, r randyMetadata, fieldNumber int, wire int)
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateMetadata(dAtA, uint64(key))
-		v3 := r.Int63()
+		v4 := r.Int63()
 		if r.Intn(2) == 0 {
-			v3 *= -1
+			v4 *= -1
 		}
-		dAtA = encodeVarintPopulateMetadata(dAtA, uint64(v3))
+		dAtA = encodeVarintPopulateMetadata(dAtA, uint64(v4))
 	case 1:
 		dAtA = encodeVarintPopulateMetadata(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -468,15 +831,63 @@ func (m *Metadata) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMetadata(uint64(l))
 	}
-	return n
-}
-
-func (m *Configuration) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.LastApplied != 0 {
+		n += 1 + sovMetadata(uint64(m.LastApplied))
 	}
-	var l int
-	_ = l
+	if m.Configuration != nil {
+		l = m.Configuration.Size()
+		n += 1 + l + sovMetadata(uint64(l))
+	}
+	if m.WitnessRecord != nil {
+		l = m.WitnessRecord.Size()
+		n += 1 + l + sovMetadata(uint64(l))
+	}
+	if len(m.MatchIndexes) > 0 {
+		for _, e := range m.MatchIndexes {
+			l = e.Size()
+			n += 1 + l + sovMetadata(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MatchIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MemberID)
+	if l > 0 {
+		n += 1 + l + sovMetadata(uint64(l))
+	}
+	if m.Index != 0 {
+		n += 1 + sovMetadata(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *WitnessRecord) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovMetadata(uint64(m.Index))
+	}
+	if m.Term != 0 {
+		n += 1 + sovMetadata(uint64(m.Term))
+	}
+	return n
+}
+
+func (m *Configuration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
 	if m.Index != 0 {
 		n += 1 + sovMetadata(uint64(m.Index))
 	}
@@ -582,6 +993,326 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 			}
 			m.Vote = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastApplied", wireType)
+			}
+			m.LastApplied = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastApplied |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Configuration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Configuration == nil {
+				m.Configuration = &Configuration{}
+			}
+			if err := m.Configuration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WitnessRecord", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.WitnessRecord == nil {
+				m.WitnessRecord = &WitnessRecord{}
+			}
+			if err := m.WitnessRecord.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MatchIndexes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MatchIndexes = append(m.MatchIndexes, &MatchIndex{})
+			if err := m.MatchIndexes[len(m.MatchIndexes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetadata(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MatchIndex) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetadata
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MatchIndex: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MatchIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemberID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemberID = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMetadata(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthMetadata
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WitnessRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMetadata
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WitnessRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WitnessRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMetadata
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMetadata(dAtA[iNdEx:])
@@ -75,6 +75,20 @@ func (mr *MockClusterMockRecorder) GetMember(memberID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockCluster)(nil).GetMember), memberID)
 }
 
+// Configuration mocks base method
+func (m *MockCluster) Configuration() *protocol.Configuration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configuration")
+	ret0, _ := ret[0].(*protocol.Configuration)
+	return ret0
+}
+
+// Configuration indicates an expected call of Configuration
+func (mr *MockClusterMockRecorder) Configuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configuration", reflect.TypeOf((*MockCluster)(nil).Configuration))
+}
+
 // GetClient mocks base method
 func (m *MockCluster) GetClient(memberID protocol.MemberID) (protocol.RaftServiceClient, error) {
 	m.ctrl.T.Helper()
@@ -89,3 +103,45 @@ func (mr *MockClusterMockRecorder) GetClient(memberID interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClient", reflect.TypeOf((*MockCluster)(nil).GetClient), memberID)
 }
+
+// UpdateMemberAddress mocks base method
+func (m *MockCluster) UpdateMemberAddress(memberID protocol.MemberID, host string, port int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberAddress", memberID, host, port)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberAddress indicates an expected call of UpdateMemberAddress
+func (mr *MockClusterMockRecorder) UpdateMemberAddress(memberID, host, port interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberAddress", reflect.TypeOf((*MockCluster)(nil).UpdateMemberAddress), memberID, host, port)
+}
+
+// RemoveMember mocks base method
+func (m *MockCluster) RemoveMember(memberID protocol.MemberID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", memberID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember
+func (mr *MockClusterMockRecorder) RemoveMember(memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockCluster)(nil).RemoveMember), memberID)
+}
+
+// SetMemberType mocks base method
+func (m *MockCluster) SetMemberType(memberID protocol.MemberID, memberType protocol.Member_Type) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMemberType", memberID, memberType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMemberType indicates an expected call of SetMemberType
+func (mr *MockClusterMockRecorder) SetMemberType(memberID, memberType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMemberType", reflect.TypeOf((*MockCluster)(nil).SetMemberType), memberID, memberType)
+}
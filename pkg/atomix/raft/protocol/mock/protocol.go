@@ -124,6 +124,21 @@ func (mr *MockClientMockRecorder) Vote(ctx, request, member interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockClient)(nil).Vote), ctx, request, member)
 }
 
+// Digest mocks base method
+func (m *MockClient) Digest(ctx context.Context, request *protocol.DigestRequest, member protocol.MemberID) (*protocol.DigestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", ctx, request, member)
+	ret0, _ := ret[0].(*protocol.DigestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest
+func (mr *MockClientMockRecorder) Digest(ctx, request, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockClient)(nil).Digest), ctx, request, member)
+}
+
 // Transfer mocks base method
 func (m *MockClient) Transfer(ctx context.Context, request *protocol.TransferRequest, member protocol.MemberID) (*protocol.TransferResponse, error) {
 	m.ctrl.T.Helper()
@@ -313,6 +328,21 @@ func (mr *MockServerMockRecorder) Vote(ctx, request interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockServer)(nil).Vote), ctx, request)
 }
 
+// Digest mocks base method
+func (m *MockServer) Digest(ctx context.Context, request *protocol.DigestRequest) (*protocol.DigestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", ctx, request)
+	ret0, _ := ret[0].(*protocol.DigestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest
+func (mr *MockServerMockRecorder) Digest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockServer)(nil).Digest), ctx, request)
+}
+
 // Transfer mocks base method
 func (m *MockServer) Transfer(ctx context.Context, request *protocol.TransferRequest) (*protocol.TransferResponse, error) {
 	m.ctrl.T.Helper()
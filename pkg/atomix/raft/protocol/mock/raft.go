@@ -10,6 +10,7 @@ import (
 	protocol "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRaft is a mock of Raft interface
@@ -125,6 +126,21 @@ func (mr *MockRaftMockRecorder) Vote(ctx, request interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockRaft)(nil).Vote), ctx, request)
 }
 
+// Digest mocks base method
+func (m *MockRaft) Digest(ctx context.Context, request *protocol.DigestRequest) (*protocol.DigestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", ctx, request)
+	ret0, _ := ret[0].(*protocol.DigestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest
+func (mr *MockRaftMockRecorder) Digest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockRaft)(nil).Digest), ctx, request)
+}
+
 // Transfer mocks base method
 func (m *MockRaft) Transfer(ctx context.Context, request *protocol.TransferRequest) (*protocol.TransferResponse, error) {
 	m.ctrl.T.Helper()
@@ -276,6 +292,20 @@ func (mr *MockRaftMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockRaft)(nil).Config))
 }
 
+// SetElectionTimeout mocks base method
+func (m *MockRaft) SetElectionTimeout(timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetElectionTimeout", timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetElectionTimeout indicates an expected call of SetElectionTimeout
+func (mr *MockRaftMockRecorder) SetElectionTimeout(timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetElectionTimeout", reflect.TypeOf((*MockRaft)(nil).SetElectionTimeout), timeout)
+}
+
 // Member mocks base method
 func (m *MockRaft) Member() protocol.MemberID {
 	m.ctrl.T.Helper()
@@ -318,6 +348,20 @@ func (mr *MockRaftMockRecorder) GetMember(memberID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockRaft)(nil).GetMember), memberID)
 }
 
+// Configuration mocks base method
+func (m *MockRaft) Configuration() *protocol.Configuration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configuration")
+	ret0, _ := ret[0].(*protocol.Configuration)
+	return ret0
+}
+
+// Configuration indicates an expected call of Configuration
+func (mr *MockRaftMockRecorder) Configuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configuration", reflect.TypeOf((*MockRaft)(nil).Configuration))
+}
+
 // Protocol mocks base method
 func (m *MockRaft) Protocol() protocol.Client {
 	m.ctrl.T.Helper()
@@ -388,6 +432,20 @@ func (mr *MockRaftMockRecorder) SetLeader(leader interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLeader", reflect.TypeOf((*MockRaft)(nil).SetLeader), leader)
 }
 
+// LeaderTime mocks base method
+func (m *MockRaft) LeaderTime() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaderTime")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// LeaderTime indicates an expected call of LeaderTime
+func (mr *MockRaftMockRecorder) LeaderTime() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaderTime", reflect.TypeOf((*MockRaft)(nil).LeaderTime))
+}
+
 // LastVotedFor mocks base method
 func (m *MockRaft) LastVotedFor() *protocol.MemberID {
 	m.ctrl.T.Helper()
@@ -416,6 +474,202 @@ func (mr *MockRaftMockRecorder) SetLastVotedFor(memberID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastVotedFor", reflect.TypeOf((*MockRaft)(nil).SetLastVotedFor), memberID)
 }
 
+// MatchIndex mocks base method
+func (m *MockRaft) MatchIndex(memberID protocol.MemberID) protocol.Index {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchIndex", memberID)
+	ret0, _ := ret[0].(protocol.Index)
+	return ret0
+}
+
+// MatchIndex indicates an expected call of MatchIndex
+func (mr *MockRaftMockRecorder) MatchIndex(memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchIndex", reflect.TypeOf((*MockRaft)(nil).MatchIndex), memberID)
+}
+
+// SetMatchIndex mocks base method
+func (m *MockRaft) SetMatchIndex(memberID protocol.MemberID, index protocol.Index) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMatchIndex", memberID, index)
+}
+
+// SetMatchIndex indicates an expected call of SetMatchIndex
+func (mr *MockRaftMockRecorder) SetMatchIndex(memberID, index interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMatchIndex", reflect.TypeOf((*MockRaft)(nil).SetMatchIndex), memberID, index)
+}
+
+// WitnessRecord mocks base method
+func (m *MockRaft) WitnessRecord() protocol.WitnessRecord {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WitnessRecord")
+	ret0, _ := ret[0].(protocol.WitnessRecord)
+	return ret0
+}
+
+// WitnessRecord indicates an expected call of WitnessRecord
+func (mr *MockRaftMockRecorder) WitnessRecord() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WitnessRecord", reflect.TypeOf((*MockRaft)(nil).WitnessRecord))
+}
+
+// SetWitnessRecord mocks base method
+func (m *MockRaft) SetWitnessRecord(record protocol.WitnessRecord) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWitnessRecord", record)
+}
+
+// SetWitnessRecord indicates an expected call of SetWitnessRecord
+func (mr *MockRaftMockRecorder) SetWitnessRecord(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWitnessRecord", reflect.TypeOf((*MockRaft)(nil).SetWitnessRecord), record)
+}
+
+// Progress mocks base method
+func (m *MockRaft) Progress() []*protocol.MemberProgress {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Progress")
+	ret0, _ := ret[0].([]*protocol.MemberProgress)
+	return ret0
+}
+
+// Progress indicates an expected call of Progress
+func (mr *MockRaftMockRecorder) Progress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Progress", reflect.TypeOf((*MockRaft)(nil).Progress))
+}
+
+// ForceInstall mocks base method
+func (m *MockRaft) ForceInstall(member protocol.MemberID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceInstall", member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceInstall indicates an expected call of ForceInstall
+func (mr *MockRaftMockRecorder) ForceInstall(member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceInstall", reflect.TypeOf((*MockRaft)(nil).ForceInstall), member)
+}
+
+// SetFaulted mocks base method
+func (m *MockRaft) SetFaulted() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFaulted")
+}
+
+// SetFaulted indicates an expected call of SetFaulted
+func (mr *MockRaftMockRecorder) SetFaulted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFaulted", reflect.TypeOf((*MockRaft)(nil).SetFaulted))
+}
+
+// ElectionFailure mocks base method
+func (m *MockRaft) ElectionFailure() *protocol.ElectionFailure {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ElectionFailure")
+	ret0, _ := ret[0].(*protocol.ElectionFailure)
+	return ret0
+}
+
+// ElectionFailure indicates an expected call of ElectionFailure
+func (mr *MockRaftMockRecorder) ElectionFailure() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ElectionFailure", reflect.TypeOf((*MockRaft)(nil).ElectionFailure))
+}
+
+// SetElectionFailure mocks base method
+func (m *MockRaft) SetElectionFailure(failure *protocol.ElectionFailure) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetElectionFailure", failure)
+}
+
+// SetElectionFailure indicates an expected call of SetElectionFailure
+func (mr *MockRaftMockRecorder) SetElectionFailure(failure interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetElectionFailure", reflect.TypeOf((*MockRaft)(nil).SetElectionFailure), failure)
+}
+
+// CommitStalled mocks base method
+func (m *MockRaft) CommitStalled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitStalled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CommitStalled indicates an expected call of CommitStalled
+func (mr *MockRaftMockRecorder) CommitStalled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStalled", reflect.TypeOf((*MockRaft)(nil).CommitStalled))
+}
+
+// SetCommitStalled mocks base method
+func (m *MockRaft) SetCommitStalled(stalled bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCommitStalled", stalled)
+}
+
+// SetCommitStalled indicates an expected call of SetCommitStalled
+func (mr *MockRaftMockRecorder) SetCommitStalled(stalled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommitStalled", reflect.TypeOf((*MockRaft)(nil).SetCommitStalled), stalled)
+}
+
+// InMaintenance mocks base method
+func (m *MockRaft) InMaintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InMaintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// InMaintenance indicates an expected call of InMaintenance
+func (mr *MockRaftMockRecorder) InMaintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InMaintenance", reflect.TypeOf((*MockRaft)(nil).InMaintenance))
+}
+
+// SetMaintenance mocks base method
+func (m *MockRaft) SetMaintenance(until time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMaintenance", until)
+}
+
+// SetMaintenance indicates an expected call of SetMaintenance
+func (mr *MockRaftMockRecorder) SetMaintenance(until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMaintenance", reflect.TypeOf((*MockRaft)(nil).SetMaintenance), until)
+}
+
+// Handoff mocks base method
+func (m *MockRaft) Handoff() *protocol.Handoff {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handoff")
+	ret0, _ := ret[0].(*protocol.Handoff)
+	return ret0
+}
+
+// Handoff indicates an expected call of Handoff
+func (mr *MockRaftMockRecorder) Handoff() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handoff", reflect.TypeOf((*MockRaft)(nil).Handoff))
+}
+
+// SetHandoff mocks base method
+func (m *MockRaft) SetHandoff(handoff *protocol.Handoff) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetHandoff", handoff)
+}
+
+// SetHandoff indicates an expected call of SetHandoff
+func (mr *MockRaftMockRecorder) SetHandoff(handoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHandoff", reflect.TypeOf((*MockRaft)(nil).SetHandoff), handoff)
+}
+
 // CommitIndex mocks base method
 func (m *MockRaft) CommitIndex() protocol.Index {
 	m.ctrl.T.Helper()
@@ -456,6 +710,36 @@ func (mr *MockRaftMockRecorder) Commit(index interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockRaft)(nil).Commit), index)
 }
 
+// BeginRead mocks base method
+func (m *MockRaft) BeginRead(timeout time.Duration) (protocol.ReadTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRead", timeout)
+	ret0, _ := ret[0].(protocol.ReadTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRead indicates an expected call of BeginRead
+func (mr *MockRaftMockRecorder) BeginRead(timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRead", reflect.TypeOf((*MockRaft)(nil).BeginRead), timeout)
+}
+
+// Timestamp mocks base method
+func (m *MockRaft) Timestamp() (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timestamp")
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timestamp indicates an expected call of Timestamp
+func (mr *MockRaftMockRecorder) Timestamp() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timestamp", reflect.TypeOf((*MockRaft)(nil).Timestamp))
+}
+
 // WriteLock mocks base method
 func (m *MockRaft) WriteLock() {
 	m.ctrl.T.Helper()
@@ -643,6 +927,21 @@ func (mr *MockRoleMockRecorder) Vote(ctx, request interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Vote", reflect.TypeOf((*MockRole)(nil).Vote), ctx, request)
 }
 
+// Digest mocks base method
+func (m *MockRole) Digest(ctx context.Context, request *protocol.DigestRequest) (*protocol.DigestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Digest", ctx, request)
+	ret0, _ := ret[0].(*protocol.DigestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Digest indicates an expected call of Digest
+func (mr *MockRoleMockRecorder) Digest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Digest", reflect.TypeOf((*MockRole)(nil).Digest), ctx, request)
+}
+
 // Transfer mocks base method
 func (m *MockRole) Transfer(ctx context.Context, request *protocol.TransferRequest) (*protocol.TransferResponse, error) {
 	m.ctrl.T.Helper()
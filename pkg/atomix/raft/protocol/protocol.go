@@ -52,6 +52,9 @@ type Client interface {
 	// Transfer sends a leadership transfer request
 	Transfer(ctx context.Context, request *TransferRequest, member MemberID) (*TransferResponse, error)
 
+	// Digest sends a log digest request
+	Digest(ctx context.Context, request *DigestRequest, member MemberID) (*DigestResponse, error)
+
 	// Append sends an append request
 	Append(ctx context.Context, request *AppendRequest, member MemberID) (*AppendResponse, error)
 
@@ -88,6 +91,9 @@ type Server interface {
 	// Transfer handles a leadership transfer request
 	Transfer(ctx context.Context, request *TransferRequest) (*TransferResponse, error)
 
+	// Digest handles a log digest request
+	Digest(ctx context.Context, request *DigestRequest) (*DigestResponse, error)
+
 	// Append handles an append request
 	Append(ctx context.Context, request *AppendRequest) (*AppendResponse, error)
 
@@ -213,6 +219,10 @@ func (s *gRPCServer) Transfer(ctx context.Context, request *TransferRequest) (*T
 	return s.server.Transfer(ctx, request)
 }
 
+func (s *gRPCServer) Digest(ctx context.Context, request *DigestRequest) (*DigestResponse, error) {
+	return s.server.Digest(ctx, request)
+}
+
 func (s *gRPCServer) Append(ctx context.Context, request *AppendRequest) (*AppendResponse, error) {
 	return s.server.Append(ctx, request)
 }
@@ -350,6 +360,14 @@ func (p *gRPCClient) Transfer(ctx context.Context, request *TransferRequest, mem
 	return client.Transfer(ctx, request)
 }
 
+func (p *gRPCClient) Digest(ctx context.Context, request *DigestRequest, member MemberID) (*DigestResponse, error) {
+	client, err := p.cluster.GetClient(member)
+	if err != nil {
+		return nil, err
+	}
+	return client.Digest(ctx, request)
+}
+
 func (p *gRPCClient) Append(ctx context.Context, request *AppendRequest, member MemberID) (*AppendResponse, error) {
 	client, err := p.cluster.GetClient(member)
 	if err != nil {
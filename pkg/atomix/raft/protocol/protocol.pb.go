@@ -6,6 +6,7 @@ package protocol
 import (
 	bytes "bytes"
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -38,18 +39,23 @@ const (
 	ReadConsistency_SEQUENTIAL         ReadConsistency = 0
 	ReadConsistency_LINEARIZABLE_LEASE ReadConsistency = 1
 	ReadConsistency_LINEARIZABLE       ReadConsistency = 2
+	// STALE reads are served from any member's last applied state without checking for a leader.
+	// Stale reads may observe arbitrarily old state and are not guaranteed to be monotonic across members.
+	ReadConsistency_STALE ReadConsistency = 3
 )
 
 var ReadConsistency_name = map[int32]string{
 	0: "SEQUENTIAL",
 	1: "LINEARIZABLE_LEASE",
 	2: "LINEARIZABLE",
+	3: "STALE",
 }
 
 var ReadConsistency_value = map[string]int32{
 	"SEQUENTIAL":         0,
 	"LINEARIZABLE_LEASE": 1,
 	"LINEARIZABLE":       2,
+	"STALE":              3,
 }
 
 func (x ReadConsistency) String() string {
@@ -100,6 +106,14 @@ const (
 	ResponseError_PROTOCOL_ERROR       ResponseError = 9
 	ResponseError_CONFIGURATION_ERROR  ResponseError = 10
 	ResponseError_UNAVAILABLE          ResponseError = 11
+	ResponseError_OVERLOADED           ResponseError = 12
+	ResponseError_TIMEOUT              ResponseError = 13
+	ResponseError_DISK_FULL            ResponseError = 14
+	ResponseError_ENTRY_TOO_LARGE      ResponseError = 15
+	ResponseError_SHUTTING_DOWN        ResponseError = 16
+	ResponseError_LEADER_INITIALIZING  ResponseError = 18
+	ResponseError_FAULTED              ResponseError = 19
+	ResponseError_ENTRIES_UNAVAILABLE  ResponseError = 20
 )
 
 var ResponseError_name = map[int32]string{
@@ -115,6 +129,14 @@ var ResponseError_name = map[int32]string{
 	9:  "PROTOCOL_ERROR",
 	10: "CONFIGURATION_ERROR",
 	11: "UNAVAILABLE",
+	12: "OVERLOADED",
+	13: "TIMEOUT",
+	14: "DISK_FULL",
+	15: "ENTRY_TOO_LARGE",
+	16: "SHUTTING_DOWN",
+	18: "LEADER_INITIALIZING",
+	19: "FAULTED",
+	20: "ENTRIES_UNAVAILABLE",
 }
 
 var ResponseError_value = map[string]int32{
@@ -130,6 +152,14 @@ var ResponseError_value = map[string]int32{
 	"PROTOCOL_ERROR":       9,
 	"CONFIGURATION_ERROR":  10,
 	"UNAVAILABLE":          11,
+	"OVERLOADED":           12,
+	"TIMEOUT":              13,
+	"DISK_FULL":            14,
+	"ENTRY_TOO_LARGE":      15,
+	"SHUTTING_DOWN":        16,
+	"LEADER_INITIALIZING":  18,
+	"FAULTED":              19,
+	"ENTRIES_UNAVAILABLE":  20,
 }
 
 func (x ResponseError) String() string {
@@ -140,6 +170,54 @@ func (ResponseError) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_2ab16e79e6abb7aa, []int{2}
 }
 
+// RejectionReason indicates why a poll or vote request was rejected
+type RejectionReason int32
+
+const (
+	// REJECTION_UNSPECIFIED is used for accepted requests and rejections without a known reason
+	RejectionReason_REJECTION_UNSPECIFIED RejectionReason = 0
+	// TERM_BEHIND indicates the candidate's term is less than the member's term
+	RejectionReason_TERM_BEHIND RejectionReason = 1
+	// LOG_BEHIND indicates the candidate's log is not as up-to-date as the member's log
+	RejectionReason_LOG_BEHIND RejectionReason = 2
+	// ALREADY_VOTED indicates the member already voted for another candidate in the term
+	RejectionReason_ALREADY_VOTED RejectionReason = 3
+	// LEADER_EXISTS indicates the member already knows of a leader for the term
+	RejectionReason_LEADER_EXISTS RejectionReason = 4
+	// UNKNOWN_CANDIDATE indicates the candidate is not a known member of the cluster
+	RejectionReason_UNKNOWN_CANDIDATE RejectionReason = 5
+	// LEADERSHIP_PROTECTED indicates the current leader is within its minimum leadership duration
+	RejectionReason_LEADERSHIP_PROTECTED RejectionReason = 6
+)
+
+var RejectionReason_name = map[int32]string{
+	0: "REJECTION_UNSPECIFIED",
+	1: "TERM_BEHIND",
+	2: "LOG_BEHIND",
+	3: "ALREADY_VOTED",
+	4: "LEADER_EXISTS",
+	5: "UNKNOWN_CANDIDATE",
+	6: "LEADERSHIP_PROTECTED",
+}
+
+var RejectionReason_value = map[string]int32{
+	"REJECTION_UNSPECIFIED": 0,
+	"TERM_BEHIND":           1,
+	"LOG_BEHIND":            2,
+	"ALREADY_VOTED":         3,
+	"LEADER_EXISTS":         4,
+	"UNKNOWN_CANDIDATE":     5,
+	"LEADERSHIP_PROTECTED":  6,
+}
+
+func (x RejectionReason) String() string {
+	return proto.EnumName(RejectionReason_name, int32(x))
+}
+
+func (RejectionReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{3}
+}
+
 type JoinRequest struct {
 	Member *Member `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
 }
@@ -737,10 +815,13 @@ func (m *PollRequest) GetLastLogTerm() Term {
 }
 
 type PollResponse struct {
-	Status   ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
-	Error    ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
-	Term     Term           `protobuf:"varint,3,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
-	Accepted bool           `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Status    ResponseStatus  `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
+	Error     ResponseError   `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
+	Term      Term            `protobuf:"varint,3,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Accepted  bool            `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejection RejectionReason `protobuf:"varint,5,opt,name=rejection,proto3,enum=atomix.raft.protocol.RejectionReason" json:"rejection,omitempty"`
+	// leader is the leader known to the responding member, if any
+	Leader MemberID `protobuf:"bytes,6,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
 }
 
 func (m *PollResponse) Reset()         { *m = PollResponse{} }
@@ -804,11 +885,27 @@ func (m *PollResponse) GetAccepted() bool {
 	return false
 }
 
+func (m *PollResponse) GetRejection() RejectionReason {
+	if m != nil {
+		return m.Rejection
+	}
+	return RejectionReason_REJECTION_UNSPECIFIED
+}
+
+func (m *PollResponse) GetLeader() MemberID {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
 type VoteRequest struct {
 	Term         Term     `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
 	Candidate    MemberID `protobuf:"bytes,2,opt,name=candidate,proto3,casttype=MemberID" json:"candidate,omitempty"`
 	LastLogIndex Index    `protobuf:"varint,3,opt,name=last_log_index,json=lastLogIndex,proto3,casttype=Index" json:"last_log_index,omitempty"`
 	LastLogTerm  Term     `protobuf:"varint,4,opt,name=last_log_term,json=lastLogTerm,proto3,casttype=Term" json:"last_log_term,omitempty"`
+	// transfer indicates the candidate was transferred leadership by the leader of the previous term
+	Transfer bool `protobuf:"varint,5,opt,name=transfer,proto3" json:"transfer,omitempty"`
 }
 
 func (m *VoteRequest) Reset()         { *m = VoteRequest{} }
@@ -872,11 +969,19 @@ func (m *VoteRequest) GetLastLogTerm() Term {
 	return 0
 }
 
+func (m *VoteRequest) GetTransfer() bool {
+	if m != nil {
+		return m.Transfer
+	}
+	return false
+}
+
 type VoteResponse struct {
-	Status ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
-	Error  ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
-	Term   Term           `protobuf:"varint,3,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
-	Voted  bool           `protobuf:"varint,4,opt,name=voted,proto3" json:"voted,omitempty"`
+	Status    ResponseStatus  `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
+	Error     ResponseError   `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
+	Term      Term            `protobuf:"varint,3,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Voted     bool            `protobuf:"varint,4,opt,name=voted,proto3" json:"voted,omitempty"`
+	Rejection RejectionReason `protobuf:"varint,5,opt,name=rejection,proto3,enum=atomix.raft.protocol.RejectionReason" json:"rejection,omitempty"`
 }
 
 func (m *VoteResponse) Reset()         { *m = VoteResponse{} }
@@ -940,8 +1045,17 @@ func (m *VoteResponse) GetVoted() bool {
 	return false
 }
 
+func (m *VoteResponse) GetRejection() RejectionReason {
+	if m != nil {
+		return m.Rejection
+	}
+	return RejectionReason_REJECTION_UNSPECIFIED
+}
+
 type TransferRequest struct {
 	Member MemberID `protobuf:"bytes,1,opt,name=member,proto3,casttype=MemberID" json:"member,omitempty"`
+	Term   Term     `protobuf:"varint,2,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Leader MemberID `protobuf:"bytes,3,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
 }
 
 func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
@@ -984,6 +1098,20 @@ func (m *TransferRequest) GetMember() MemberID {
 	return ""
 }
 
+func (m *TransferRequest) GetTerm() Term {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *TransferRequest) GetLeader() MemberID {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
 type TransferResponse struct {
 	Status ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
 	Error  ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
@@ -1036,6 +1164,138 @@ func (m *TransferResponse) GetError() ResponseError {
 	return ResponseError_NO_LEADER
 }
 
+// DigestRequest is a request for a digest of the committed log entries from first_index through index
+type DigestRequest struct {
+	FirstIndex Index `protobuf:"varint,1,opt,name=first_index,json=firstIndex,proto3,casttype=Index" json:"first_index,omitempty"`
+	Index      Index `protobuf:"varint,2,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
+}
+
+func (m *DigestRequest) Reset()         { *m = DigestRequest{} }
+func (m *DigestRequest) String() string { return proto.CompactTextString(m) }
+func (*DigestRequest) ProtoMessage()    {}
+func (*DigestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{14}
+}
+func (m *DigestRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DigestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DigestRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DigestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DigestRequest.Merge(m, src)
+}
+func (m *DigestRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DigestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DigestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DigestRequest proto.InternalMessageInfo
+
+func (m *DigestRequest) GetFirstIndex() Index {
+	if m != nil {
+		return m.FirstIndex
+	}
+	return 0
+}
+
+func (m *DigestRequest) GetIndex() Index {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type DigestResponse struct {
+	Status ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
+	Error  ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
+	// digest is the digest of the requested entries
+	Digest []byte `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	// first_index is the first index in the member's log
+	FirstIndex Index `protobuf:"varint,4,opt,name=first_index,json=firstIndex,proto3,casttype=Index" json:"first_index,omitempty"`
+	// commit_index is the member's commit index
+	CommitIndex Index `protobuf:"varint,5,opt,name=commit_index,json=commitIndex,proto3,casttype=Index" json:"commit_index,omitempty"`
+}
+
+func (m *DigestResponse) Reset()         { *m = DigestResponse{} }
+func (m *DigestResponse) String() string { return proto.CompactTextString(m) }
+func (*DigestResponse) ProtoMessage()    {}
+func (*DigestResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{15}
+}
+func (m *DigestResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DigestResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DigestResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DigestResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DigestResponse.Merge(m, src)
+}
+func (m *DigestResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DigestResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DigestResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DigestResponse proto.InternalMessageInfo
+
+func (m *DigestResponse) GetStatus() ResponseStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ResponseStatus_OK
+}
+
+func (m *DigestResponse) GetError() ResponseError {
+	if m != nil {
+		return m.Error
+	}
+	return ResponseError_NO_LEADER
+}
+
+func (m *DigestResponse) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (m *DigestResponse) GetFirstIndex() Index {
+	if m != nil {
+		return m.FirstIndex
+	}
+	return 0
+}
+
+func (m *DigestResponse) GetCommitIndex() Index {
+	if m != nil {
+		return m.CommitIndex
+	}
+	return 0
+}
+
 type AppendRequest struct {
 	Term         Term        `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
 	Leader       MemberID    `protobuf:"bytes,2,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
@@ -1043,13 +1303,15 @@ type AppendRequest struct {
 	PrevLogTerm  Term        `protobuf:"varint,4,opt,name=prev_log_term,json=prevLogTerm,proto3,casttype=Term" json:"prev_log_term,omitempty"`
 	Entries      []*LogEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
 	CommitIndex  Index       `protobuf:"varint,6,opt,name=commit_index,json=commitIndex,proto3,casttype=Index" json:"commit_index,omitempty"`
+	// last_log_index is the index of the last entry in the leader's log when the request was built
+	LastLogIndex Index `protobuf:"varint,7,opt,name=last_log_index,json=lastLogIndex,proto3,casttype=Index" json:"last_log_index,omitempty"`
 }
 
 func (m *AppendRequest) Reset()         { *m = AppendRequest{} }
 func (m *AppendRequest) String() string { return proto.CompactTextString(m) }
 func (*AppendRequest) ProtoMessage()    {}
 func (*AppendRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{14}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{16}
 }
 func (m *AppendRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1120,19 +1382,29 @@ func (m *AppendRequest) GetCommitIndex() Index {
 	return 0
 }
 
+func (m *AppendRequest) GetLastLogIndex() Index {
+	if m != nil {
+		return m.LastLogIndex
+	}
+	return 0
+}
+
 type AppendResponse struct {
 	Status       ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
 	Error        ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
 	Term         Term           `protobuf:"varint,3,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
 	Succeeded    bool           `protobuf:"varint,4,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
 	LastLogIndex Index          `protobuf:"varint,5,opt,name=last_log_index,json=lastLogIndex,proto3,casttype=Index" json:"last_log_index,omitempty"`
+	// unrelayed is the downstream members the responding member can't relay entries to
+	// The leader replicates to these members directly.
+	Unrelayed []MemberID `protobuf:"bytes,6,rep,name=unrelayed,proto3,casttype=MemberID" json:"unrelayed,omitempty"`
 }
 
 func (m *AppendResponse) Reset()         { *m = AppendResponse{} }
 func (m *AppendResponse) String() string { return proto.CompactTextString(m) }
 func (*AppendResponse) ProtoMessage()    {}
 func (*AppendResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{15}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{17}
 }
 func (m *AppendResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1196,19 +1468,32 @@ func (m *AppendResponse) GetLastLogIndex() Index {
 	return 0
 }
 
+func (m *AppendResponse) GetUnrelayed() []MemberID {
+	if m != nil {
+		return m.Unrelayed
+	}
+	return nil
+}
+
 type InstallRequest struct {
-	Term      Term      `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
-	Leader    MemberID  `protobuf:"bytes,2,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
-	Index     Index     `protobuf:"varint,3,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
-	Timestamp time.Time `protobuf:"bytes,4,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
-	Data      []byte    `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Term         Term      `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Leader       MemberID  `protobuf:"bytes,2,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
+	Index        Index     `protobuf:"varint,3,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
+	Timestamp    time.Time `protobuf:"bytes,4,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	Data         []byte    `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	SnapshotTerm Term      `protobuf:"varint,6,opt,name=snapshot_term,json=snapshotTerm,proto3,casttype=Term" json:"snapshot_term,omitempty"`
+	// checksum is the CRC32 checksum of the snapshot data up to and including this request
+	// Leaders that predate checksums send 0, in which case the data is not verified.
+	Checksum uint32 `protobuf:"varint,7,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// metadata is the application-defined metadata of the snapshot
+	Metadata []byte `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *InstallRequest) Reset()         { *m = InstallRequest{} }
 func (m *InstallRequest) String() string { return proto.CompactTextString(m) }
 func (*InstallRequest) ProtoMessage()    {}
 func (*InstallRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{16}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{18}
 }
 func (m *InstallRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1272,6 +1557,27 @@ func (m *InstallRequest) GetData() []byte {
 	return nil
 }
 
+func (m *InstallRequest) GetSnapshotTerm() Term {
+	if m != nil {
+		return m.SnapshotTerm
+	}
+	return 0
+}
+
+func (m *InstallRequest) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *InstallRequest) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type InstallResponse struct {
 	Status ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
 	Error  ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
@@ -1281,7 +1587,7 @@ func (m *InstallResponse) Reset()         { *m = InstallResponse{} }
 func (m *InstallResponse) String() string { return proto.CompactTextString(m) }
 func (*InstallResponse) ProtoMessage()    {}
 func (*InstallResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{17}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{19}
 }
 func (m *InstallResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1332,7 +1638,7 @@ func (m *CommandRequest) Reset()         { *m = CommandRequest{} }
 func (m *CommandRequest) String() string { return proto.CompactTextString(m) }
 func (*CommandRequest) ProtoMessage()    {}
 func (*CommandRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{18}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{20}
 }
 func (m *CommandRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1376,13 +1682,15 @@ type CommandResponse struct {
 	Term    Term           `protobuf:"varint,5,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
 	Members []MemberID     `protobuf:"bytes,6,rep,name=members,proto3,casttype=MemberID" json:"members,omitempty"`
 	Output  []byte         `protobuf:"bytes,7,opt,name=output,proto3" json:"output,omitempty"`
+	// index is the index at which the command was committed
+	Index Index `protobuf:"varint,8,opt,name=index,proto3,casttype=Index" json:"index,omitempty"`
 }
 
 func (m *CommandResponse) Reset()         { *m = CommandResponse{} }
 func (m *CommandResponse) String() string { return proto.CompactTextString(m) }
 func (*CommandResponse) ProtoMessage()    {}
 func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{19}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{21}
 }
 func (m *CommandResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1460,16 +1768,29 @@ func (m *CommandResponse) GetOutput() []byte {
 	return nil
 }
 
+func (m *CommandResponse) GetIndex() Index {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
 type QueryRequest struct {
 	Value           []byte          `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
 	ReadConsistency ReadConsistency `protobuf:"varint,2,opt,name=read_consistency,json=readConsistency,proto3,enum=atomix.raft.protocol.ReadConsistency" json:"read_consistency,omitempty"`
+	// forward_depth is the number of times the query has been forwarded between members
+	ForwardDepth uint32 `protobuf:"varint,3,opt,name=forward_depth,json=forwardDepth,proto3" json:"forward_depth,omitempty"`
+	// min_index is the minimum index the serving member must have applied to serve the query
+	MinIndex Index `protobuf:"varint,4,opt,name=min_index,json=minIndex,proto3,casttype=Index" json:"min_index,omitempty"`
+	// min_index_timeout is the maximum time to wait for the serving member to reach the minimum index
+	MinIndexTimeout time.Duration `protobuf:"bytes,5,opt,name=min_index_timeout,json=minIndexTimeout,proto3,stdduration" json:"min_index_timeout"`
 }
 
 func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
 func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest) ProtoMessage()    {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{20}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{22}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1512,6 +1833,27 @@ func (m *QueryRequest) GetReadConsistency() ReadConsistency {
 	return ReadConsistency_SEQUENTIAL
 }
 
+func (m *QueryRequest) GetForwardDepth() uint32 {
+	if m != nil {
+		return m.ForwardDepth
+	}
+	return 0
+}
+
+func (m *QueryRequest) GetMinIndex() Index {
+	if m != nil {
+		return m.MinIndex
+	}
+	return 0
+}
+
+func (m *QueryRequest) GetMinIndexTimeout() time.Duration {
+	if m != nil {
+		return m.MinIndexTimeout
+	}
+	return 0
+}
+
 type QueryResponse struct {
 	Status  ResponseStatus `protobuf:"varint,1,opt,name=status,proto3,enum=atomix.raft.protocol.ResponseStatus" json:"status,omitempty"`
 	Error   ResponseError  `protobuf:"varint,2,opt,name=error,proto3,enum=atomix.raft.protocol.ResponseError" json:"error,omitempty"`
@@ -1523,7 +1865,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2ab16e79e6abb7aa, []int{21}
+	return fileDescriptor_2ab16e79e6abb7aa, []int{23}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1580,153 +1922,520 @@ func (m *QueryResponse) GetOutput() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("atomix.raft.protocol.ReadConsistency", ReadConsistency_name, ReadConsistency_value)
-	proto.RegisterEnum("atomix.raft.protocol.ResponseStatus", ResponseStatus_name, ResponseStatus_value)
-	proto.RegisterEnum("atomix.raft.protocol.ResponseError", ResponseError_name, ResponseError_value)
-	proto.RegisterType((*JoinRequest)(nil), "atomix.raft.protocol.JoinRequest")
-	proto.RegisterType((*JoinResponse)(nil), "atomix.raft.protocol.JoinResponse")
-	proto.RegisterType((*ConfigureRequest)(nil), "atomix.raft.protocol.ConfigureRequest")
-	proto.RegisterType((*ConfigureResponse)(nil), "atomix.raft.protocol.ConfigureResponse")
-	proto.RegisterType((*ReconfigureRequest)(nil), "atomix.raft.protocol.ReconfigureRequest")
-	proto.RegisterType((*ReconfigureResponse)(nil), "atomix.raft.protocol.ReconfigureResponse")
-	proto.RegisterType((*LeaveRequest)(nil), "atomix.raft.protocol.LeaveRequest")
-	proto.RegisterType((*LeaveResponse)(nil), "atomix.raft.protocol.LeaveResponse")
-	proto.RegisterType((*PollRequest)(nil), "atomix.raft.protocol.PollRequest")
-	proto.RegisterType((*PollResponse)(nil), "atomix.raft.protocol.PollResponse")
-	proto.RegisterType((*VoteRequest)(nil), "atomix.raft.protocol.VoteRequest")
-	proto.RegisterType((*VoteResponse)(nil), "atomix.raft.protocol.VoteResponse")
-	proto.RegisterType((*TransferRequest)(nil), "atomix.raft.protocol.TransferRequest")
-	proto.RegisterType((*TransferResponse)(nil), "atomix.raft.protocol.TransferResponse")
-	proto.RegisterType((*AppendRequest)(nil), "atomix.raft.protocol.AppendRequest")
-	proto.RegisterType((*AppendResponse)(nil), "atomix.raft.protocol.AppendResponse")
-	proto.RegisterType((*InstallRequest)(nil), "atomix.raft.protocol.InstallRequest")
-	proto.RegisterType((*InstallResponse)(nil), "atomix.raft.protocol.InstallResponse")
-	proto.RegisterType((*CommandRequest)(nil), "atomix.raft.protocol.CommandRequest")
-	proto.RegisterType((*CommandResponse)(nil), "atomix.raft.protocol.CommandResponse")
-	proto.RegisterType((*QueryRequest)(nil), "atomix.raft.protocol.QueryRequest")
-	proto.RegisterType((*QueryResponse)(nil), "atomix.raft.protocol.QueryResponse")
+// WatchStatusRequest is a request to watch the status of a member
+type WatchStatusRequest struct {
 }
 
-func init() {
-	proto.RegisterFile("atomix/raft/protocol/protocol.proto", fileDescriptor_2ab16e79e6abb7aa)
+func (m *WatchStatusRequest) Reset()         { *m = WatchStatusRequest{} }
+func (m *WatchStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchStatusRequest) ProtoMessage()    {}
+func (*WatchStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{24}
 }
-
-var fileDescriptor_2ab16e79e6abb7aa = []byte{
-	// 1353 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xf6, 0x38, 0xb6, 0x63, 0x1f, 0xff, 0x6d, 0xa7, 0xa1, 0x58, 0x56, 0x65, 0x97, 0x4d, 0x1a,
-	0x42, 0x54, 0x39, 0xa8, 0x20, 0x7e, 0x24, 0x6e, 0xd6, 0xce, 0xb6, 0xda, 0x76, 0xe3, 0x4d, 0xc7,
-	0x76, 0x11, 0x45, 0xc2, 0xda, 0xda, 0x13, 0xcb, 0x92, 0xbd, 0x6b, 0x76, 0xd7, 0x51, 0xfb, 0x04,
-	0x88, 0x9f, 0x8b, 0x5e, 0xf3, 0x04, 0x7d, 0x02, 0x84, 0xe0, 0x0a, 0xb8, 0x29, 0x17, 0x48, 0xbd,
-	0xe4, 0x02, 0x05, 0x48, 0x1f, 0x01, 0x09, 0xa1, 0x5e, 0xa1, 0xfd, 0xf5, 0xae, 0xd9, 0xb5, 0x4b,
-	0x8a, 0x94, 0x20, 0xf5, 0x6e, 0xe7, 0xcc, 0x77, 0x3e, 0x9f, 0xf9, 0xce, 0x99, 0x99, 0x33, 0x86,
-	0x75, 0xd9, 0x50, 0xc7, 0xc3, 0x7b, 0x3b, 0x9a, 0x7c, 0x60, 0xec, 0x4c, 0x34, 0xd5, 0x50, 0x7b,
-	0xea, 0xc8, 0xfb, 0xa8, 0x59, 0x1f, 0x78, 0xcd, 0x06, 0xd5, 0x4c, 0x50, 0xcd, 0x9d, 0x2b, 0xb3,
-	0xa1, 0xae, 0xbd, 0xd1, 0x54, 0x37, 0xa8, 0x66, 0xc3, 0xca, 0x95, 0x50, 0xcc, 0x48, 0x1d, 0x38,
-	0xf3, 0xd5, 0x81, 0xaa, 0x0e, 0x46, 0xd4, 0x9e, 0xba, 0x3b, 0x3d, 0xd8, 0x31, 0x86, 0x63, 0xaa,
-	0x1b, 0xf2, 0x78, 0xe2, 0x00, 0xd6, 0x06, 0xea, 0x40, 0xb5, 0x3e, 0x77, 0xcc, 0x2f, 0xdb, 0xca,
-	0x36, 0x20, 0x7b, 0x43, 0x1d, 0x2a, 0x84, 0x7e, 0x3c, 0xa5, 0xba, 0x81, 0xdf, 0x84, 0xd4, 0x98,
-	0x8e, 0xef, 0x52, 0xad, 0x84, 0x2e, 0xa1, 0xad, 0xec, 0xd5, 0x8b, 0xb5, 0xb0, 0x80, 0x6b, 0x7b,
-	0x16, 0x86, 0x38, 0x58, 0xf6, 0xfb, 0x38, 0xe4, 0x6c, 0x16, 0x7d, 0xa2, 0x2a, 0x3a, 0xc5, 0xef,
-	0x41, 0x4a, 0x37, 0x64, 0x63, 0xaa, 0x5b, 0x34, 0x85, 0xab, 0x1b, 0xe1, 0x34, 0x2e, 0xbe, 0x65,
-	0x61, 0x89, 0xe3, 0x83, 0xdf, 0x85, 0x24, 0xd5, 0x34, 0x55, 0x2b, 0xc5, 0x2d, 0xe7, 0xf5, 0xc5,
-	0xce, 0xbc, 0x09, 0x25, 0xb6, 0x07, 0xae, 0x42, 0x72, 0xa8, 0xf4, 0xe9, 0xbd, 0xd2, 0xca, 0x25,
-	0xb4, 0x95, 0xa8, 0x67, 0x9e, 0x1e, 0x55, 0x93, 0x82, 0x69, 0x20, 0xb6, 0x1d, 0x5f, 0x84, 0x84,
-	0x41, 0xb5, 0x71, 0x29, 0x61, 0xcd, 0xa7, 0x9f, 0x1e, 0x55, 0x13, 0x6d, 0xaa, 0x8d, 0x89, 0x65,
-	0xc5, 0x75, 0xc8, 0x78, 0xb2, 0x95, 0x92, 0x96, 0x02, 0xe5, 0x9a, 0x2d, 0x6c, 0xcd, 0x15, 0xb6,
-	0xd6, 0x76, 0x11, 0xf5, 0xf4, 0xa3, 0xa3, 0x6a, 0xec, 0xc1, 0xaf, 0x55, 0x44, 0x66, 0x6e, 0xf8,
-	0x2d, 0x58, 0xb5, 0x65, 0xd1, 0x4b, 0xa9, 0x4b, 0x2b, 0x4b, 0x35, 0x74, 0xc1, 0xec, 0x1f, 0x08,
-	0x98, 0x86, 0xaa, 0x1c, 0x0c, 0x07, 0x53, 0x8d, 0xba, 0xf9, 0x70, 0xc3, 0x45, 0xa1, 0xe1, 0x6e,
-	0x40, 0x6a, 0x44, 0xe5, 0x3e, 0xb5, 0x95, 0xca, 0xd4, 0x73, 0x4f, 0x8f, 0xaa, 0x69, 0x9b, 0x57,
-	0xd8, 0x25, 0xce, 0xdc, 0x72, 0x4d, 0x02, 0xab, 0x4e, 0x3c, 0xf7, 0xaa, 0x93, 0xff, 0x66, 0xd5,
-	0x5f, 0x20, 0x38, 0xe7, 0x5b, 0xf5, 0x29, 0xd7, 0x0f, 0xfb, 0x29, 0x02, 0x4c, 0x68, 0x6f, 0x3e,
-	0x0d, 0x27, 0xda, 0x16, 0x33, 0xe1, 0xe3, 0x4b, 0x8a, 0x71, 0x25, 0x2c, 0xbb, 0xec, 0x8f, 0x71,
-	0x38, 0x1f, 0x88, 0xe5, 0xc5, 0xe6, 0x3a, 0xf1, 0xe6, 0xda, 0x85, 0x9c, 0x48, 0xe5, 0xc3, 0xe7,
-	0x4b, 0x28, 0xfb, 0x43, 0x1c, 0xf2, 0x0e, 0xcd, 0x8b, 0x5c, 0x9c, 0x38, 0x17, 0x5f, 0x21, 0xc8,
-	0xee, 0xab, 0xa3, 0xd1, 0xb3, 0x9d, 0x71, 0xdb, 0x90, 0xe9, 0xc9, 0x4a, 0x7f, 0xd8, 0x97, 0x0d,
-	0x1a, 0x7a, 0xcc, 0xcd, 0xa6, 0xf1, 0x0e, 0x14, 0x46, 0xb2, 0x6e, 0x74, 0x47, 0xea, 0xa0, 0x1b,
-	0xa1, 0x4e, 0xce, 0x04, 0x88, 0xea, 0xc0, 0x1a, 0xe1, 0x2b, 0x90, 0xf7, 0x1c, 0x42, 0xd5, 0xca,
-	0x3a, 0x70, 0x73, 0xc0, 0x7e, 0x87, 0x20, 0x67, 0x07, 0x7e, 0xda, 0xd9, 0x5f, 0x78, 0x70, 0xe0,
-	0x32, 0xa4, 0xe5, 0x5e, 0x8f, 0x4e, 0x0c, 0xda, 0xb7, 0x16, 0x94, 0x26, 0xde, 0xd8, 0x12, 0xff,
-	0xb6, 0x6a, 0xd0, 0xff, 0x9d, 0xf8, 0xdf, 0x22, 0xc8, 0xd9, 0x81, 0x9f, 0x6d, 0xf1, 0xd7, 0x20,
-	0x79, 0xa8, 0xce, 0x94, 0xb7, 0x07, 0xec, 0xdb, 0x50, 0x6c, 0x6b, 0xb2, 0xa2, 0x1f, 0x50, 0xcd,
-	0x55, 0x7e, 0x23, 0x70, 0x04, 0xfd, 0xe3, 0xf2, 0x76, 0x8e, 0x9c, 0xcf, 0x11, 0x30, 0x33, 0xcf,
-	0xd3, 0xbe, 0x1e, 0xbf, 0x8c, 0x43, 0x9e, 0x9b, 0x4c, 0xa8, 0xd2, 0xff, 0x2f, 0x1b, 0x94, 0x1d,
-	0x28, 0x4c, 0x34, 0x7a, 0xb8, 0xb0, 0x72, 0x4c, 0x80, 0xbf, 0x72, 0x3c, 0x87, 0xf0, 0xca, 0x71,
-	0xe0, 0xe6, 0x00, 0xbf, 0x03, 0xab, 0x54, 0x31, 0xb4, 0x21, 0x75, 0x5b, 0x93, 0x4a, 0xf8, 0x8a,
-	0x45, 0x75, 0xc0, 0x2b, 0x86, 0x76, 0x9f, 0xb8, 0x70, 0x7c, 0x05, 0x72, 0x3d, 0x75, 0x3c, 0x1e,
-	0x1a, 0x4e, 0x58, 0xa9, 0xf9, 0xb0, 0xb2, 0xf6, 0xb4, 0x35, 0x60, 0xff, 0x44, 0x50, 0x70, 0xc5,
-	0x39, 0xdb, 0x35, 0x7a, 0x11, 0x32, 0xfa, 0xb4, 0xd7, 0xa3, 0xb4, 0xef, 0xd5, 0xe9, 0xcc, 0x10,
-	0xb2, 0x91, 0x93, 0x0b, 0x37, 0x32, 0xfb, 0x13, 0x82, 0x82, 0xa0, 0xe8, 0x86, 0xfc, 0xac, 0x67,
-	0xfa, 0x19, 0xea, 0x5b, 0x31, 0x24, 0xfa, 0xb2, 0x21, 0x5b, 0x4b, 0xcc, 0x11, 0xeb, 0x9b, 0xfd,
-	0x0c, 0x41, 0xd1, 0x5b, 0xcf, 0x69, 0x6f, 0xb9, 0x4d, 0x28, 0x34, 0xd4, 0xf1, 0x58, 0x9e, 0x6d,
-	0x39, 0xf3, 0x84, 0x91, 0x47, 0x53, 0x6a, 0x45, 0x92, 0x23, 0xf6, 0x80, 0x7d, 0x18, 0x87, 0xa2,
-	0x07, 0x3c, 0xed, 0xf2, 0x2b, 0x99, 0xad, 0x81, 0xae, 0xcb, 0x03, 0x6a, 0x25, 0x2f, 0x43, 0xdc,
-	0xa1, 0x2f, 0xf5, 0x89, 0x05, 0xa9, 0x77, 0xcb, 0x27, 0x19, 0x5a, 0x3e, 0x9b, 0xc1, 0xc6, 0x63,
-	0x9e, 0xc4, 0x9d, 0xc4, 0x17, 0x20, 0xa5, 0x4e, 0x8d, 0xc9, 0xd4, 0x28, 0xad, 0x5a, 0x4a, 0x39,
-	0x23, 0xf6, 0x10, 0x72, 0xb7, 0xa6, 0x54, 0xbb, 0xbf, 0x50, 0x50, 0xbc, 0x0f, 0x8c, 0x46, 0xe5,
-	0x7e, 0xb7, 0xa7, 0x2a, 0xfa, 0x50, 0x37, 0xa8, 0xd2, 0xbb, 0xef, 0x28, 0x71, 0x39, 0x4a, 0x09,
-	0xb9, 0xdf, 0x98, 0x81, 0x49, 0x51, 0x0b, 0x1a, 0xd8, 0x6f, 0x10, 0xe4, 0x9d, 0x1f, 0x3e, 0xbb,
-	0x09, 0x9a, 0x89, 0x96, 0xf0, 0x8b, 0xb6, 0x7d, 0x13, 0x8a, 0x73, 0x0b, 0xc4, 0x05, 0x80, 0x16,
-	0x7f, 0xab, 0xc3, 0x37, 0xdb, 0x02, 0x27, 0x32, 0x31, 0x7c, 0x01, 0xb0, 0x28, 0x34, 0x79, 0x8e,
-	0x08, 0x77, 0xb8, 0xba, 0xc8, 0x77, 0x45, 0x9e, 0x6b, 0xf1, 0x0c, 0xc2, 0x0c, 0xe4, 0xfc, 0x76,
-	0x26, 0xbe, 0xbd, 0x0e, 0x85, 0xe0, 0x9a, 0x70, 0x0a, 0xe2, 0xd2, 0x4d, 0x26, 0x86, 0x33, 0x90,
-	0xe4, 0x09, 0x91, 0x08, 0x83, 0xb6, 0x3f, 0x89, 0x43, 0x3e, 0x10, 0x3c, 0xce, 0x43, 0xa6, 0x29,
-	0x99, 0xb4, 0xbb, 0x3c, 0x61, 0x62, 0xf8, 0x1c, 0xe4, 0x6f, 0x75, 0x78, 0xf2, 0x41, 0xf7, 0x1a,
-	0x27, 0x88, 0x1d, 0x62, 0xfe, 0xd4, 0x79, 0x28, 0x36, 0xa4, 0xbd, 0x3d, 0xae, 0xb9, 0xeb, 0x19,
-	0xe3, 0xf8, 0x25, 0x38, 0xc7, 0xed, 0xef, 0x8b, 0x42, 0x83, 0x6b, 0x0b, 0x52, 0xb3, 0x6b, 0xf3,
-	0xaf, 0xe0, 0x12, 0xac, 0x09, 0xa2, 0xc8, 0x5f, 0xe7, 0xc4, 0xee, 0x1e, 0xbf, 0x57, 0xe7, 0x49,
-	0xb7, 0xd5, 0xe6, 0xda, 0x3c, 0x93, 0xc0, 0x18, 0x0a, 0x9d, 0xe6, 0xcd, 0xa6, 0xf4, 0x7e, 0xb3,
-	0xdb, 0x10, 0x05, 0xbe, 0xd9, 0x66, 0x92, 0x26, 0xb3, 0x6b, 0x6b, 0xf1, 0xad, 0x96, 0x20, 0x35,
-	0x99, 0x54, 0xd0, 0x48, 0x6e, 0x0b, 0x0d, 0x9e, 0x59, 0x35, 0xbd, 0x1b, 0xa2, 0xd4, 0xe2, 0x77,
-	0x3d, 0x60, 0xda, 0xb4, 0xed, 0x13, 0xa9, 0x2d, 0x35, 0x24, 0xd1, 0xf9, 0xfd, 0x0c, 0x7e, 0x19,
-	0xce, 0x37, 0xa4, 0xe6, 0x35, 0xe1, 0x7a, 0x87, 0xf8, 0x03, 0x03, 0x5c, 0x84, 0x6c, 0xa7, 0xc9,
-	0xdd, 0xe6, 0x04, 0xd1, 0x92, 0x2b, 0x7b, 0xf5, 0x97, 0x55, 0xc8, 0x12, 0xf9, 0xc0, 0x68, 0x51,
-	0xed, 0x70, 0xd8, 0xa3, 0x58, 0x82, 0xc4, 0x0d, 0x75, 0xa8, 0xe0, 0x57, 0xc2, 0x33, 0xee, 0xfb,
-	0x43, 0xa7, 0xcc, 0x2e, 0x82, 0xd8, 0xda, 0xb2, 0x31, 0x4c, 0x20, 0x69, 0xbd, 0x6b, 0x70, 0x04,
-	0xdc, 0xff, 0x76, 0x2a, 0xaf, 0x2f, 0xc4, 0x78, 0x9c, 0x1f, 0x41, 0xc6, 0x7b, 0xd8, 0xe3, 0xcd,
-	0x70, 0x9f, 0xf9, 0xff, 0x3b, 0xca, 0xaf, 0x2e, 0xc5, 0x79, 0xfc, 0x7d, 0xc8, 0xfa, 0x5e, 0xc7,
-	0x78, 0x2b, 0xaa, 0xfa, 0xe7, 0x1f, 0xf3, 0xe5, 0xd7, 0x9e, 0x01, 0xe9, 0xfd, 0x8a, 0x04, 0x09,
-	0xb3, 0xe5, 0x8f, 0x92, 0xda, 0xf7, 0x8e, 0x89, 0x92, 0xda, 0xff, 0x62, 0xb0, 0x09, 0xcd, 0x36,
-	0x36, 0x8a, 0xd0, 0xd7, 0x9b, 0x47, 0x11, 0xfa, 0xbb, 0x60, 0x36, 0x86, 0x3f, 0x84, 0xb4, 0xdb,
-	0x20, 0xe2, 0x88, 0x93, 0x69, 0xae, 0xf5, 0x2c, 0x6f, 0x2e, 0x83, 0x79, 0xe4, 0x1d, 0x48, 0xd9,
-	0x2d, 0x0d, 0x8e, 0xc8, 0x7a, 0xa0, 0x1b, 0x2c, 0x6f, 0x2c, 0x06, 0x79, 0xb4, 0x77, 0x60, 0xd5,
-	0xb9, 0x60, 0x71, 0x84, 0x4b, 0xb0, 0x9f, 0x28, 0x5f, 0x5e, 0x82, 0x72, 0x99, 0xb7, 0x90, 0xc9,
-	0xed, 0xdc, 0x83, 0x51, 0xdc, 0xc1, 0xfb, 0x34, 0x8a, 0x7b, 0xee, 0x32, 0x65, 0x63, 0xaf, 0x23,
-	0xdc, 0x86, 0xa4, 0x75, 0x80, 0x47, 0xed, 0x13, 0xff, 0xb5, 0x12, 0xb5, 0x4f, 0x02, 0x37, 0x80,
-	0xc9, 0x5a, 0xdf, 0xf8, 0xeb, 0xf7, 0x0a, 0x7a, 0x78, 0x5c, 0x41, 0x5f, 0x1f, 0x57, 0xd0, 0xa3,
-	0xe3, 0x0a, 0x7a, 0x7c, 0x5c, 0x41, 0xbf, 0x1d, 0x57, 0xd0, 0x83, 0x27, 0x95, 0xd8, 0xe3, 0x27,
-	0x95, 0xd8, 0xcf, 0x4f, 0x2a, 0xb1, 0xbb, 0x29, 0x8b, 0xe1, 0x8d, 0xbf, 0x03, 0x00, 0x00, 0xff,
-	0xff, 0xd2, 0x1c, 0x28, 0x01, 0x68, 0x16, 0x00, 0x00,
+func (m *WatchStatusRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (this *JoinRequest) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
-
-	that1, ok := that.(*JoinRequest)
-	if !ok {
-		that2, ok := that.(JoinRequest)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+func (m *WatchStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WatchStatusRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if !this.Member.Equal(that1.Member) {
-		return false
-	}
-	return true
 }
-func (this *JoinResponse) Equal(that interface{}) bool {
-	if that == nil {
+func (m *WatchStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchStatusRequest.Merge(m, src)
+}
+func (m *WatchStatusRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *WatchStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchStatusRequest proto.InternalMessageInfo
+
+// MemberProgress is the replication progress of a member as known to the leader
+// A member is degraded if its last successful append took longer than the configured slow append threshold.
+type MemberProgress struct {
+	MemberID   MemberID `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3,casttype=MemberID" json:"member_id,omitempty"`
+	MatchIndex Index    `protobuf:"varint,2,opt,name=match_index,json=matchIndex,proto3,casttype=Index" json:"match_index,omitempty"`
+	NextIndex  Index    `protobuf:"varint,3,opt,name=next_index,json=nextIndex,proto3,casttype=Index" json:"next_index,omitempty"`
+	Degraded   bool     `protobuf:"varint,4,opt,name=degraded,proto3" json:"degraded,omitempty"`
+}
+
+func (m *MemberProgress) Reset()         { *m = MemberProgress{} }
+func (m *MemberProgress) String() string { return proto.CompactTextString(m) }
+func (*MemberProgress) ProtoMessage()    {}
+func (*MemberProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{25}
+}
+func (m *MemberProgress) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MemberProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MemberProgress.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MemberProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MemberProgress.Merge(m, src)
+}
+func (m *MemberProgress) XXX_Size() int {
+	return m.Size()
+}
+func (m *MemberProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_MemberProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MemberProgress proto.InternalMessageInfo
+
+func (m *MemberProgress) GetMemberID() MemberID {
+	if m != nil {
+		return m.MemberID
+	}
+	return ""
+}
+
+func (m *MemberProgress) GetMatchIndex() Index {
+	if m != nil {
+		return m.MatchIndex
+	}
+	return 0
+}
+
+func (m *MemberProgress) GetNextIndex() Index {
+	if m != nil {
+		return m.NextIndex
+	}
+	return 0
+}
+
+func (m *MemberProgress) GetDegraded() bool {
+	if m != nil {
+		return m.Degraded
+	}
+	return false
+}
+
+// MemberStatus is the status of a member
+// The replication progress of other members is only reported by the leader.
+type MemberStatus struct {
+	MemberID        MemberID          `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3,casttype=MemberID" json:"member_id,omitempty"`
+	Term            Term              `protobuf:"varint,2,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	Role            RoleType          `protobuf:"bytes,3,opt,name=role,proto3,casttype=RoleType" json:"role,omitempty"`
+	Leader          MemberID          `protobuf:"bytes,4,opt,name=leader,proto3,casttype=MemberID" json:"leader,omitempty"`
+	CommitIndex     Index             `protobuf:"varint,5,opt,name=commit_index,json=commitIndex,proto3,casttype=Index" json:"commit_index,omitempty"`
+	LastIndex       Index             `protobuf:"varint,6,opt,name=last_index,json=lastIndex,proto3,casttype=Index" json:"last_index,omitempty"`
+	Progress        []*MemberProgress `protobuf:"bytes,7,rep,name=progress,proto3" json:"progress,omitempty"`
+	ElectionFailure *ElectionFailure  `protobuf:"bytes,8,opt,name=election_failure,json=electionFailure,proto3" json:"election_failure,omitempty"`
+	// apply_rate is the number of entries applied to the state machine per second over the configured rate window
+	ApplyRate float64 `protobuf:"fixed64,9,opt,name=apply_rate,json=applyRate,proto3" json:"apply_rate,omitempty"`
+	// commit_rate is the number of entries committed per second over the configured rate window
+	CommitRate float64 `protobuf:"fixed64,10,opt,name=commit_rate,json=commitRate,proto3" json:"commit_rate,omitempty"`
+}
+
+func (m *MemberStatus) Reset()         { *m = MemberStatus{} }
+func (m *MemberStatus) String() string { return proto.CompactTextString(m) }
+func (*MemberStatus) ProtoMessage()    {}
+func (*MemberStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{26}
+}
+func (m *MemberStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MemberStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MemberStatus.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MemberStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MemberStatus.Merge(m, src)
+}
+func (m *MemberStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *MemberStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MemberStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MemberStatus proto.InternalMessageInfo
+
+func (m *MemberStatus) GetMemberID() MemberID {
+	if m != nil {
+		return m.MemberID
+	}
+	return ""
+}
+
+func (m *MemberStatus) GetTerm() Term {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *MemberStatus) GetRole() RoleType {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *MemberStatus) GetLeader() MemberID {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *MemberStatus) GetCommitIndex() Index {
+	if m != nil {
+		return m.CommitIndex
+	}
+	return 0
+}
+
+func (m *MemberStatus) GetLastIndex() Index {
+	if m != nil {
+		return m.LastIndex
+	}
+	return 0
+}
+
+func (m *MemberStatus) GetProgress() []*MemberProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *MemberStatus) GetElectionFailure() *ElectionFailure {
+	if m != nil {
+		return m.ElectionFailure
+	}
+	return nil
+}
+
+func (m *MemberStatus) GetApplyRate() float64 {
+	if m != nil {
+		return m.ApplyRate
+	}
+	return 0
+}
+
+func (m *MemberStatus) GetCommitRate() float64 {
+	if m != nil {
+		return m.CommitRate
+	}
+	return 0
+}
+
+// ElectionFailure describes why the member's most recent poll or election did not make it the leader
+type ElectionFailure struct {
+	// term is the term for which the member campaigned
+	Term Term `protobuf:"varint,1,opt,name=term,proto3,casttype=Term" json:"term,omitempty"`
+	// reason is the reason most voters gave for rejecting the member, or REJECTION_UNSPECIFIED if a quorum of voters
+	// could not be reached
+	Reason RejectionReason `protobuf:"varint,2,opt,name=reason,proto3,enum=atomix.raft.protocol.RejectionReason" json:"reason,omitempty"`
+	// rejections is the number of voters that rejected the member
+	Rejections uint32 `protobuf:"varint,3,opt,name=rejections,proto3" json:"rejections,omitempty"`
+	// unreachable is the number of voters that failed to respond
+	Unreachable uint32 `protobuf:"varint,4,opt,name=unreachable,proto3" json:"unreachable,omitempty"`
+	// voters is the number of voters in the cluster
+	Voters uint32 `protobuf:"varint,5,opt,name=voters,proto3" json:"voters,omitempty"`
+}
+
+func (m *ElectionFailure) Reset()         { *m = ElectionFailure{} }
+func (m *ElectionFailure) String() string { return proto.CompactTextString(m) }
+func (*ElectionFailure) ProtoMessage()    {}
+func (*ElectionFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ab16e79e6abb7aa, []int{27}
+}
+func (m *ElectionFailure) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElectionFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ElectionFailure.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ElectionFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElectionFailure.Merge(m, src)
+}
+func (m *ElectionFailure) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElectionFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElectionFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ElectionFailure proto.InternalMessageInfo
+
+func (m *ElectionFailure) GetTerm() Term {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *ElectionFailure) GetReason() RejectionReason {
+	if m != nil {
+		return m.Reason
+	}
+	return RejectionReason_REJECTION_UNSPECIFIED
+}
+
+func (m *ElectionFailure) GetRejections() uint32 {
+	if m != nil {
+		return m.Rejections
+	}
+	return 0
+}
+
+func (m *ElectionFailure) GetUnreachable() uint32 {
+	if m != nil {
+		return m.Unreachable
+	}
+	return 0
+}
+
+func (m *ElectionFailure) GetVoters() uint32 {
+	if m != nil {
+		return m.Voters
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("atomix.raft.protocol.ReadConsistency", ReadConsistency_name, ReadConsistency_value)
+	proto.RegisterEnum("atomix.raft.protocol.ResponseStatus", ResponseStatus_name, ResponseStatus_value)
+	proto.RegisterEnum("atomix.raft.protocol.ResponseError", ResponseError_name, ResponseError_value)
+	proto.RegisterEnum("atomix.raft.protocol.RejectionReason", RejectionReason_name, RejectionReason_value)
+	proto.RegisterType((*JoinRequest)(nil), "atomix.raft.protocol.JoinRequest")
+	proto.RegisterType((*JoinResponse)(nil), "atomix.raft.protocol.JoinResponse")
+	proto.RegisterType((*ConfigureRequest)(nil), "atomix.raft.protocol.ConfigureRequest")
+	proto.RegisterType((*ConfigureResponse)(nil), "atomix.raft.protocol.ConfigureResponse")
+	proto.RegisterType((*ReconfigureRequest)(nil), "atomix.raft.protocol.ReconfigureRequest")
+	proto.RegisterType((*ReconfigureResponse)(nil), "atomix.raft.protocol.ReconfigureResponse")
+	proto.RegisterType((*LeaveRequest)(nil), "atomix.raft.protocol.LeaveRequest")
+	proto.RegisterType((*LeaveResponse)(nil), "atomix.raft.protocol.LeaveResponse")
+	proto.RegisterType((*PollRequest)(nil), "atomix.raft.protocol.PollRequest")
+	proto.RegisterType((*PollResponse)(nil), "atomix.raft.protocol.PollResponse")
+	proto.RegisterType((*VoteRequest)(nil), "atomix.raft.protocol.VoteRequest")
+	proto.RegisterType((*VoteResponse)(nil), "atomix.raft.protocol.VoteResponse")
+	proto.RegisterType((*TransferRequest)(nil), "atomix.raft.protocol.TransferRequest")
+	proto.RegisterType((*TransferResponse)(nil), "atomix.raft.protocol.TransferResponse")
+	proto.RegisterType((*DigestRequest)(nil), "atomix.raft.protocol.DigestRequest")
+	proto.RegisterType((*DigestResponse)(nil), "atomix.raft.protocol.DigestResponse")
+	proto.RegisterType((*AppendRequest)(nil), "atomix.raft.protocol.AppendRequest")
+	proto.RegisterType((*AppendResponse)(nil), "atomix.raft.protocol.AppendResponse")
+	proto.RegisterType((*InstallRequest)(nil), "atomix.raft.protocol.InstallRequest")
+	proto.RegisterType((*InstallResponse)(nil), "atomix.raft.protocol.InstallResponse")
+	proto.RegisterType((*CommandRequest)(nil), "atomix.raft.protocol.CommandRequest")
+	proto.RegisterType((*CommandResponse)(nil), "atomix.raft.protocol.CommandResponse")
+	proto.RegisterType((*QueryRequest)(nil), "atomix.raft.protocol.QueryRequest")
+	proto.RegisterType((*QueryResponse)(nil), "atomix.raft.protocol.QueryResponse")
+	proto.RegisterType((*WatchStatusRequest)(nil), "atomix.raft.protocol.WatchStatusRequest")
+	proto.RegisterType((*MemberProgress)(nil), "atomix.raft.protocol.MemberProgress")
+	proto.RegisterType((*MemberStatus)(nil), "atomix.raft.protocol.MemberStatus")
+	proto.RegisterType((*ElectionFailure)(nil), "atomix.raft.protocol.ElectionFailure")
+}
+
+func init() {
+	proto.RegisterFile("atomix/raft/protocol/protocol.proto", fileDescriptor_2ab16e79e6abb7aa)
+}
+
+var fileDescriptor_2ab16e79e6abb7aa = []byte{
+	// 2145 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcd, 0x73, 0x1b, 0x49,
+	0x15, 0xf7, 0xc8, 0x92, 0x2c, 0x3d, 0x7d, 0x4d, 0x3a, 0xde, 0x45, 0xab, 0x0a, 0xb2, 0x19, 0x3b,
+	0xc1, 0xb8, 0x16, 0x9b, 0x32, 0x1f, 0x05, 0x55, 0x50, 0x85, 0x2c, 0x8d, 0x9d, 0xd9, 0x8c, 0x35,
+	0x4e, 0x6b, 0xe4, 0x90, 0x40, 0x31, 0x35, 0x91, 0xda, 0xb2, 0x40, 0xd2, 0x88, 0x99, 0x51, 0x88,
+	0x8b, 0x0b, 0x57, 0x3e, 0x0e, 0x7b, 0xe0, 0x40, 0x71, 0xa4, 0x38, 0x70, 0xe0, 0x06, 0x45, 0x01,
+	0x47, 0xb8, 0x84, 0xe2, 0xb2, 0xc5, 0x05, 0x4e, 0x01, 0x9c, 0x3f, 0x81, 0x0b, 0x15, 0x2e, 0x54,
+	0x77, 0xcf, 0x8c, 0x46, 0xb2, 0x34, 0xca, 0x66, 0x53, 0x38, 0x5b, 0xb5, 0xb7, 0xe9, 0xf7, 0x7e,
+	0xfd, 0xba, 0xdf, 0x47, 0xbf, 0x7e, 0xaf, 0x07, 0x36, 0x4c, 0xd7, 0xea, 0x77, 0x1f, 0xef, 0xda,
+	0xe6, 0xa9, 0xbb, 0x3b, 0xb4, 0x2d, 0xd7, 0x6a, 0x59, 0xbd, 0xe0, 0x63, 0x87, 0x7d, 0xa0, 0x55,
+	0x0e, 0xda, 0xa1, 0xa0, 0x1d, 0x9f, 0x57, 0x92, 0x66, 0x4e, 0x6d, 0xf5, 0x46, 0x8e, 0x4b, 0x6c,
+	0x0e, 0x2b, 0x95, 0x67, 0x62, 0x7a, 0x56, 0xc7, 0xe3, 0xaf, 0x75, 0x2c, 0xab, 0xd3, 0x23, 0x9c,
+	0xf5, 0x70, 0x74, 0xba, 0xeb, 0x76, 0xfb, 0xc4, 0x71, 0xcd, 0xfe, 0xd0, 0x17, 0x30, 0x0d, 0x68,
+	0x8f, 0x6c, 0xd3, 0xed, 0x5a, 0x03, 0x8f, 0xbf, 0xda, 0xb1, 0x3a, 0x16, 0xfb, 0xdc, 0xa5, 0x5f,
+	0x9c, 0x2a, 0x55, 0x21, 0xf3, 0x8e, 0xd5, 0x1d, 0x60, 0xf2, 0x9d, 0x11, 0x71, 0x5c, 0xf4, 0x39,
+	0x48, 0xf6, 0x49, 0xff, 0x21, 0xb1, 0x8b, 0xc2, 0xba, 0xb0, 0x95, 0xd9, 0xbb, 0xb1, 0x33, 0x4b,
+	0xa1, 0x9d, 0x23, 0x86, 0xc1, 0x1e, 0x56, 0xfa, 0x63, 0x0c, 0xb2, 0x5c, 0x8a, 0x33, 0xb4, 0x06,
+	0x0e, 0x41, 0x5f, 0x86, 0xa4, 0xe3, 0x9a, 0xee, 0xc8, 0x61, 0x62, 0xf2, 0x7b, 0x9b, 0xb3, 0xc5,
+	0xf8, 0xf8, 0x06, 0xc3, 0x62, 0x6f, 0x0e, 0xfa, 0x12, 0x24, 0x88, 0x6d, 0x5b, 0x76, 0x31, 0xc6,
+	0x26, 0x6f, 0x44, 0x4f, 0x96, 0x29, 0x14, 0xf3, 0x19, 0x68, 0x0d, 0x12, 0xdd, 0x41, 0x9b, 0x3c,
+	0x2e, 0x2e, 0xaf, 0x0b, 0x5b, 0xf1, 0xfd, 0xf4, 0xf3, 0xa7, 0x6b, 0x09, 0x85, 0x12, 0x30, 0xa7,
+	0xa3, 0x1b, 0x10, 0x77, 0x89, 0xdd, 0x2f, 0xc6, 0x19, 0x3f, 0xf5, 0xfc, 0xe9, 0x5a, 0x5c, 0x27,
+	0x76, 0x1f, 0x33, 0x2a, 0xda, 0x87, 0x74, 0x60, 0xd6, 0x62, 0x82, 0x59, 0xa0, 0xb4, 0xc3, 0xed,
+	0xba, 0xe3, 0xdb, 0x75, 0x47, 0xf7, 0x11, 0xfb, 0xa9, 0x27, 0x4f, 0xd7, 0x96, 0xde, 0xfd, 0xc7,
+	0x9a, 0x80, 0xc7, 0xd3, 0xd0, 0x17, 0x60, 0x85, 0x9b, 0xc5, 0x29, 0x26, 0xd7, 0x97, 0x17, 0xda,
+	0xd0, 0x07, 0x4b, 0xff, 0x16, 0x40, 0xac, 0x5a, 0x83, 0xd3, 0x6e, 0x67, 0x64, 0x13, 0xdf, 0x1f,
+	0xfe, 0x76, 0x85, 0x99, 0xdb, 0xdd, 0x84, 0x64, 0x8f, 0x98, 0x6d, 0xc2, 0x2d, 0x95, 0xde, 0xcf,
+	0x3e, 0x7f, 0xba, 0x96, 0xe2, 0x72, 0x95, 0x1a, 0xf6, 0x78, 0x8b, 0x6d, 0x32, 0xa1, 0x75, 0xfc,
+	0x03, 0x6b, 0x9d, 0x78, 0x3f, 0x5a, 0xff, 0x58, 0x80, 0x6b, 0x21, 0xad, 0xaf, 0x38, 0x7e, 0xa4,
+	0x1f, 0x08, 0x80, 0x30, 0x69, 0x4d, 0xbb, 0xe1, 0xa5, 0x8e, 0xc5, 0xd8, 0xf0, 0xb1, 0x05, 0xc1,
+	0xb8, 0x3c, 0xcb, 0xbb, 0xd2, 0x9f, 0x63, 0x70, 0x7d, 0x62, 0x2f, 0x1f, 0x1d, 0xae, 0x97, 0x3e,
+	0x5c, 0x35, 0xc8, 0xaa, 0xc4, 0x7c, 0xf4, 0xc1, 0x1c, 0x2a, 0xfd, 0x29, 0x06, 0x39, 0x4f, 0xcc,
+	0x47, 0xbe, 0x78, 0x69, 0x5f, 0xfc, 0x56, 0x80, 0xcc, 0xb1, 0xd5, 0xeb, 0xbd, 0x58, 0x8e, 0xdb,
+	0x86, 0x74, 0xcb, 0x1c, 0xb4, 0xbb, 0x6d, 0xd3, 0x25, 0x33, 0xd3, 0xdc, 0x98, 0x8d, 0x76, 0x21,
+	0xdf, 0x33, 0x1d, 0xd7, 0xe8, 0x59, 0x1d, 0x63, 0x8e, 0x75, 0xb2, 0x14, 0xa0, 0x5a, 0x1d, 0x36,
+	0x42, 0x6f, 0x43, 0x2e, 0x98, 0x30, 0xd3, 0x5a, 0x19, 0x0f, 0x4e, 0x07, 0xd2, 0xaf, 0x62, 0x90,
+	0xe5, 0x1b, 0xbf, 0x6a, 0xef, 0x47, 0x26, 0x0e, 0x54, 0x82, 0x94, 0xd9, 0x6a, 0x91, 0xa1, 0x4b,
+	0xda, 0x4c, 0xa1, 0x14, 0x0e, 0xc6, 0xa8, 0x0a, 0x69, 0x9b, 0x7c, 0x8b, 0xb4, 0x68, 0x61, 0xc0,
+	0x1c, 0x9f, 0xdf, 0xbb, 0x39, 0x6f, 0x61, 0x0f, 0x86, 0x89, 0xe9, 0x58, 0x03, 0x3c, 0x9e, 0x17,
+	0xba, 0x77, 0x92, 0xf3, 0xef, 0x1d, 0xe9, 0xaf, 0x02, 0x64, 0x4e, 0x2c, 0x97, 0x7c, 0xd8, 0xfc,
+	0x4c, 0xed, 0xe7, 0xda, 0xe6, 0xc0, 0x39, 0x25, 0x36, 0x33, 0x51, 0x0a, 0x07, 0x63, 0xe9, 0xfb,
+	0x31, 0xc8, 0x72, 0xa5, 0x5e, 0xef, 0x18, 0x58, 0x85, 0xc4, 0x23, 0x6b, 0x1c, 0x00, 0x7c, 0xf0,
+	0x4a, 0xbc, 0x2f, 0x7d, 0x0f, 0x0a, 0xba, 0x67, 0x0e, 0xdf, 0xb5, 0x9b, 0x13, 0xe9, 0xf4, 0x52,
+	0x40, 0x70, 0x5e, 0xb0, 0xe3, 0xd8, 0x82, 0x62, 0x66, 0x39, 0x22, 0xa8, 0x7e, 0x24, 0x80, 0x38,
+	0x5e, 0xfd, 0xaa, 0xcb, 0x85, 0x6f, 0x40, 0xae, 0xd6, 0xed, 0x10, 0xc7, 0xf5, 0x0d, 0xb1, 0x0d,
+	0x99, 0xd3, 0xae, 0xed, 0xb8, 0x5e, 0x58, 0x0a, 0xd3, 0x61, 0x09, 0x8c, 0xcb, 0xbe, 0x17, 0x96,
+	0x07, 0xd2, 0x7f, 0x05, 0xc8, 0xfb, 0xe2, 0xaf, 0x3a, 0xda, 0xde, 0x84, 0x64, 0x9b, 0x6d, 0x85,
+	0x79, 0x27, 0x8b, 0xbd, 0xd1, 0xb4, 0xc2, 0xf1, 0x28, 0x85, 0xdf, 0x86, 0x6c, 0xcb, 0xea, 0xf7,
+	0xbb, 0x3e, 0x38, 0x31, 0x0d, 0xce, 0x70, 0x36, 0x1b, 0x48, 0x7f, 0x89, 0x41, 0xae, 0x32, 0x1c,
+	0x92, 0x41, 0xfb, 0x55, 0x16, 0xc3, 0xbb, 0x90, 0x1f, 0xda, 0xe4, 0x51, 0x64, 0xea, 0xa0, 0x80,
+	0x70, 0xea, 0x08, 0x26, 0xcc, 0x4e, 0x1d, 0x1e, 0x9c, 0x0e, 0xd0, 0x17, 0x61, 0x85, 0x0c, 0x5c,
+	0xbb, 0x4b, 0xfc, 0x32, 0xb8, 0x3c, 0xdb, 0xc6, 0xaa, 0xd5, 0x91, 0x07, 0xae, 0x7d, 0x8e, 0x7d,
+	0xf8, 0x25, 0xe3, 0x24, 0xa3, 0x8c, 0x33, 0x23, 0x03, 0xae, 0x44, 0x66, 0x40, 0xe9, 0xe7, 0x31,
+	0xc8, 0xfb, 0xd6, 0x7c, 0xbd, 0x33, 0xd7, 0x0d, 0x48, 0x3b, 0xa3, 0x56, 0x8b, 0x90, 0x76, 0x90,
+	0xbd, 0xc6, 0x84, 0x19, 0x8a, 0x27, 0xa2, 0x53, 0xff, 0x36, 0xa4, 0x47, 0x03, 0x9b, 0xf4, 0xcc,
+	0x73, 0xd2, 0x66, 0x75, 0xca, 0xa5, 0x7b, 0x25, 0x60, 0x4b, 0xbf, 0x89, 0x41, 0x5e, 0x19, 0x38,
+	0xae, 0xd9, 0xeb, 0xbd, 0xca, 0x98, 0xfb, 0xbf, 0x34, 0x60, 0x08, 0xe2, 0x6d, 0xd3, 0x35, 0x99,
+	0x39, 0xb2, 0x98, 0x7d, 0xa3, 0x4f, 0x43, 0xce, 0x19, 0x98, 0x43, 0xe7, 0xcc, 0x72, 0x79, 0xec,
+	0x26, 0xa7, 0xb4, 0xc8, 0xfa, 0x6c, 0xff, 0xde, 0x6b, 0x9d, 0x91, 0xd6, 0xb7, 0x9d, 0x51, 0x9f,
+	0x85, 0x53, 0x0e, 0x07, 0x63, 0xca, 0xeb, 0x13, 0xd7, 0x64, 0x4b, 0xa4, 0xd8, 0x12, 0xc1, 0x58,
+	0xfa, 0xa1, 0x00, 0x85, 0xc0, 0x6c, 0x57, 0x9d, 0x92, 0x6f, 0x41, 0xbe, 0x6a, 0xf5, 0xfb, 0xe6,
+	0x38, 0x6d, 0xd0, 0xab, 0xd0, 0xec, 0x8d, 0x08, 0xdb, 0x49, 0x16, 0xf3, 0x01, 0xed, 0xae, 0x0a,
+	0x01, 0xf0, 0xaa, 0x4f, 0x44, 0x91, 0x96, 0xd2, 0x8e, 0x63, 0x76, 0x08, 0xbf, 0xfc, 0xb0, 0x3f,
+	0x0c, 0x45, 0x58, 0x3c, 0x22, 0xc2, 0xfc, 0x28, 0x4d, 0xcc, 0x8c, 0xd2, 0x5b, 0x93, 0x85, 0xfa,
+	0xb4, 0x10, 0x9f, 0x49, 0x73, 0xbc, 0x35, 0x72, 0x87, 0x23, 0x97, 0x79, 0x3f, 0x8b, 0xbd, 0xd1,
+	0x38, 0x7e, 0x53, 0x73, 0x2e, 0xaa, 0x9f, 0xc4, 0x20, 0x7b, 0x77, 0x44, 0xec, 0xf3, 0x48, 0x93,
+	0xa3, 0x63, 0x10, 0x6d, 0x62, 0xb6, 0x8d, 0x96, 0x35, 0x70, 0xba, 0x8e, 0x4b, 0x06, 0xad, 0xf3,
+	0x62, 0x2c, 0xba, 0x08, 0x31, 0xdb, 0xd5, 0x31, 0x18, 0x17, 0xec, 0x49, 0x02, 0xda, 0x80, 0xdc,
+	0xa9, 0x65, 0x7f, 0xd7, 0xb4, 0xdb, 0x46, 0x9b, 0x0c, 0xdd, 0x33, 0x66, 0xbd, 0x1c, 0xce, 0x7a,
+	0xc4, 0x1a, 0xa5, 0xa1, 0x5b, 0x90, 0xee, 0x77, 0x07, 0xf3, 0x2e, 0xa8, 0x54, 0xbf, 0x3b, 0x60,
+	0x5f, 0x48, 0x83, 0x6b, 0x01, 0xce, 0xa0, 0x07, 0xcb, 0x1a, 0xb9, 0x5e, 0x6f, 0xf4, 0xd6, 0xa5,
+	0xd3, 0x58, 0xf3, 0x1e, 0xd7, 0xf8, 0x61, 0xfc, 0x29, 0x3d, 0x8c, 0x05, 0x5f, 0x92, 0xce, 0xe7,
+	0x4a, 0x7f, 0x10, 0x20, 0xe7, 0x99, 0xe5, 0xf5, 0x0d, 0xb0, 0xb1, 0xd3, 0xe3, 0x61, 0xa7, 0x4b,
+	0xab, 0x80, 0xee, 0x99, 0x6e, 0xeb, 0xcc, 0xdb, 0x03, 0x77, 0xac, 0xf4, 0x7b, 0x01, 0xf2, 0x3c,
+	0x70, 0x8e, 0x6d, 0xab, 0x63, 0x13, 0xc7, 0x41, 0x9f, 0x87, 0x34, 0x0f, 0x20, 0xa3, 0xdb, 0xf6,
+	0xca, 0xbf, 0xe2, 0x45, 0x28, 0xbe, 0x26, 0x62, 0x2d, 0xc5, 0xa1, 0x4a, 0x9b, 0x16, 0x0e, 0x7d,
+	0x2a, 0xdf, 0x98, 0x53, 0x03, 0x01, 0xe3, 0xb2, 0x6f, 0xb4, 0x05, 0x30, 0x20, 0x8f, 0xdd, 0x79,
+	0x17, 0x76, 0x9a, 0x32, 0x39, 0xb2, 0x04, 0xa9, 0x36, 0xe9, 0xd8, 0xe6, 0xf8, 0xee, 0x08, 0xc6,
+	0xd2, 0xdf, 0x96, 0x21, 0xcb, 0x37, 0xc2, 0x75, 0x7a, 0xd9, 0x9d, 0x47, 0x97, 0xb1, 0xeb, 0x10,
+	0xb7, 0xad, 0x1e, 0x09, 0x17, 0xb1, 0xd8, 0xea, 0x11, 0xfd, 0x7c, 0x48, 0x30, 0xe3, 0xbc, 0xe0,
+	0x91, 0x7e, 0x5f, 0xc5, 0x12, 0xb5, 0x10, 0xbb, 0x16, 0xe7, 0xd4, 0x0e, 0x69, 0xca, 0xe4, 0xc8,
+	0xaf, 0x42, 0x6a, 0xe8, 0xb9, 0xae, 0xb8, 0xc2, 0x4a, 0x94, 0xcd, 0xa8, 0xb6, 0xdd, 0x77, 0x33,
+	0x0e, 0x66, 0xd1, 0x63, 0x4c, 0x7a, 0xbc, 0x17, 0x30, 0x4e, 0xcd, 0x6e, 0x6f, 0x64, 0x13, 0x96,
+	0x19, 0x32, 0xf3, 0x8e, 0xb1, 0xec, 0xa1, 0x0f, 0x38, 0x18, 0x17, 0xc8, 0x24, 0x01, 0x7d, 0x1c,
+	0xc0, 0x1c, 0x0e, 0x7b, 0xe7, 0x86, 0x4d, 0x9b, 0xbf, 0xf4, 0xba, 0xb0, 0x25, 0xe0, 0x34, 0xa3,
+	0x60, 0xda, 0xee, 0xad, 0x81, 0xa7, 0x2b, 0xe7, 0x03, 0xe3, 0x03, 0x27, 0x51, 0x80, 0xf4, 0x44,
+	0x80, 0xc2, 0xd4, 0x22, 0x0b, 0x2e, 0xee, 0xaf, 0x40, 0xd2, 0x66, 0x8d, 0xcd, 0xa2, 0x04, 0x34,
+	0xd9, 0x05, 0x79, 0x93, 0x50, 0x19, 0x20, 0xe8, 0x87, 0x1c, 0x2f, 0xe9, 0x84, 0x28, 0x68, 0x1d,
+	0x32, 0xb4, 0xaa, 0x30, 0x5b, 0x67, 0xe6, 0xc3, 0x1e, 0x61, 0x7e, 0xce, 0xe1, 0x30, 0x89, 0x1e,
+	0x3b, 0xda, 0x92, 0xb1, 0xe7, 0x52, 0xca, 0xf4, 0x46, 0xdb, 0x27, 0x50, 0x98, 0xca, 0x7a, 0x28,
+	0x0f, 0xd0, 0x90, 0xef, 0x36, 0xe5, 0xba, 0xae, 0x54, 0x54, 0x71, 0x09, 0xbd, 0x09, 0x48, 0x55,
+	0xea, 0x72, 0x05, 0x2b, 0x0f, 0x2a, 0xfb, 0xaa, 0x6c, 0xa8, 0x72, 0xa5, 0x21, 0x8b, 0x02, 0x12,
+	0x21, 0x1b, 0xa6, 0x8b, 0x31, 0x94, 0x86, 0x44, 0x43, 0xaf, 0xa8, 0xb2, 0xb8, 0xbc, 0xbd, 0x01,
+	0xf9, 0xc9, 0xac, 0x82, 0x92, 0x10, 0xd3, 0xee, 0x88, 0x4b, 0x14, 0x24, 0x63, 0xac, 0x61, 0x51,
+	0xd8, 0xfe, 0xd9, 0x32, 0xe4, 0x26, 0xd2, 0x07, 0xca, 0x41, 0xba, 0xae, 0xd1, 0x15, 0x6a, 0x32,
+	0x16, 0x97, 0xd0, 0x35, 0xc8, 0xdd, 0x6d, 0xca, 0xf8, 0xbe, 0x71, 0x50, 0x51, 0xd4, 0x26, 0xa6,
+	0xab, 0x5e, 0x87, 0x42, 0x55, 0x3b, 0x3a, 0xaa, 0xd4, 0x6b, 0x01, 0x31, 0x86, 0xde, 0x80, 0x6b,
+	0x95, 0xe3, 0x63, 0x55, 0xa9, 0x56, 0x74, 0x45, 0xab, 0x1b, 0x5c, 0xfe, 0x32, 0x2a, 0xc2, 0xaa,
+	0xa2, 0xaa, 0xf2, 0x61, 0x45, 0x35, 0x8e, 0xe4, 0xa3, 0x7d, 0x19, 0x1b, 0x0d, 0xbd, 0xa2, 0xcb,
+	0x62, 0x1c, 0x21, 0xc8, 0x37, 0xeb, 0x77, 0xea, 0xda, 0xbd, 0xba, 0x51, 0x55, 0x15, 0xb9, 0xae,
+	0x8b, 0x09, 0x2a, 0xd9, 0xa7, 0x35, 0xe4, 0x46, 0x43, 0xd1, 0xea, 0x62, 0x72, 0x92, 0x88, 0x4f,
+	0x94, 0xaa, 0x2c, 0xae, 0xd0, 0xd9, 0x55, 0x55, 0x6b, 0xc8, 0xb5, 0x00, 0x98, 0xa2, 0xb4, 0x63,
+	0xac, 0xe9, 0x5a, 0x55, 0x53, 0xbd, 0xf5, 0xd3, 0xe8, 0x63, 0x70, 0xbd, 0xaa, 0xd5, 0x0f, 0x94,
+	0xc3, 0x26, 0x0e, 0x6f, 0x0c, 0x50, 0x01, 0x32, 0xcd, 0x7a, 0xe5, 0xa4, 0xa2, 0xa8, 0xcc, 0x72,
+	0x19, 0x6a, 0x73, 0xed, 0x44, 0xc6, 0xaa, 0x56, 0xa9, 0xc9, 0x35, 0x31, 0x8b, 0x32, 0xb0, 0xa2,
+	0x2b, 0x47, 0xb2, 0xd6, 0xd4, 0xc5, 0x1c, 0x35, 0x4a, 0x4d, 0x69, 0xdc, 0x31, 0x0e, 0x9a, 0xaa,
+	0x2a, 0xe6, 0xe9, 0x96, 0xe4, 0xba, 0x8e, 0xef, 0x1b, 0xba, 0xa6, 0x19, 0x6a, 0x05, 0x1f, 0xca,
+	0x62, 0x81, 0x5a, 0xaa, 0x71, 0xbb, 0xa9, 0xeb, 0x4a, 0xfd, 0xd0, 0xa8, 0x69, 0xf7, 0xea, 0xa2,
+	0x48, 0x57, 0xe7, 0x86, 0x34, 0x94, 0xba, 0x42, 0x7d, 0xa9, 0x3c, 0x50, 0xea, 0x87, 0x22, 0xa2,
+	0xc2, 0x0f, 0x2a, 0x4d, 0x55, 0x97, 0x6b, 0xe2, 0x75, 0x8a, 0xa2, 0xd2, 0x14, 0xb9, 0x61, 0x84,
+	0xb7, 0xb4, 0xba, 0xfd, 0x0b, 0x81, 0x86, 0xc6, 0x44, 0x3c, 0xa2, 0xb7, 0xe0, 0x0d, 0x2c, 0xbf,
+	0x23, 0x57, 0x99, 0x32, 0xcd, 0x7a, 0xe3, 0x58, 0xae, 0x2a, 0x07, 0x8a, 0x5c, 0x13, 0x97, 0xa8,
+	0x4a, 0xba, 0x8c, 0x8f, 0x8c, 0x7d, 0xf9, 0xb6, 0x52, 0xaf, 0x89, 0x02, 0x55, 0x49, 0xd5, 0x0e,
+	0xfd, 0x71, 0x8c, 0xee, 0xb0, 0xa2, 0x62, 0xb9, 0x52, 0xbb, 0x6f, 0x9c, 0x68, 0x74, 0xed, 0x65,
+	0x4a, 0xf2, 0x76, 0x28, 0x7f, 0x4d, 0x69, 0xe8, 0x0d, 0x31, 0x4e, 0x3d, 0x19, 0x38, 0xa6, 0x52,
+	0xaf, 0x29, 0x35, 0xea, 0xaf, 0x04, 0xf5, 0x24, 0x47, 0x36, 0x6e, 0x2b, 0xc7, 0x06, 0x35, 0xb4,
+	0x5c, 0xa5, 0x32, 0x92, 0x7b, 0xbf, 0x4e, 0x41, 0x06, 0x9b, 0xa7, 0x6e, 0x83, 0xd8, 0x8f, 0xba,
+	0x2d, 0x82, 0x34, 0x88, 0xd3, 0x5f, 0x43, 0xe8, 0x13, 0xb3, 0x4f, 0x58, 0xe8, 0xe7, 0x53, 0x49,
+	0x8a, 0x82, 0xf0, 0xa8, 0x94, 0x96, 0x10, 0x86, 0x04, 0x7b, 0x83, 0x45, 0x73, 0xe0, 0xe1, 0x77,
+	0xde, 0xd2, 0x46, 0x24, 0x26, 0x90, 0xf9, 0x4d, 0x48, 0x07, 0x3f, 0x21, 0xd0, 0xad, 0xd9, 0x73,
+	0xa6, 0xff, 0xcd, 0x94, 0x3e, 0xb9, 0x10, 0x17, 0xc8, 0x6f, 0x43, 0x26, 0xf4, 0x92, 0x8f, 0xb6,
+	0xe6, 0x65, 0x9b, 0xe9, 0x1f, 0x0f, 0xa5, 0x4f, 0xbd, 0x00, 0x32, 0x58, 0x45, 0x83, 0x38, 0x7d,
+	0x9e, 0x9c, 0x67, 0xea, 0xd0, 0x9b, 0x6b, 0x49, 0x8a, 0x82, 0x84, 0x05, 0xd2, 0xb7, 0xae, 0x79,
+	0x02, 0x43, 0x8f, 0x7b, 0x25, 0x29, 0x0a, 0x12, 0x08, 0xfc, 0x3a, 0xa4, 0xfc, 0xc7, 0x1b, 0x34,
+	0x27, 0xe5, 0x4e, 0x3d, 0x2d, 0x95, 0x6e, 0x2d, 0x82, 0x05, 0xc2, 0x9b, 0x90, 0xe4, 0xaf, 0x25,
+	0x68, 0x8e, 0xd7, 0x27, 0x9e, 0x6a, 0x4a, 0x9b, 0xd1, 0xa0, 0xb0, 0x58, 0xde, 0x38, 0xcf, 0x13,
+	0x3b, 0xf1, 0x48, 0x51, 0xda, 0x8c, 0x06, 0x05, 0x62, 0x1f, 0xc0, 0x8a, 0xd7, 0x33, 0xa1, 0x39,
+	0x53, 0x26, 0x3b, 0xd1, 0xd2, 0xcd, 0x05, 0x28, 0x5f, 0xf2, 0x96, 0x40, 0x65, 0x7b, 0xad, 0xcd,
+	0x3c, 0xd9, 0x93, 0x2d, 0x52, 0xe9, 0xe6, 0x02, 0x94, 0x2f, 0xfb, 0x33, 0x02, 0xd2, 0x21, 0xc1,
+	0x6a, 0xda, 0x79, 0xc7, 0x2f, 0xdc, 0x07, 0x94, 0x36, 0x22, 0x31, 0x63, 0xa9, 0x7b, 0x2e, 0x5c,
+	0x63, 0x49, 0x83, 0x5d, 0x4d, 0x7e, 0xea, 0x30, 0x20, 0x13, 0x2a, 0x41, 0xe7, 0x9d, 0x9a, 0xcb,
+	0x55, 0x6a, 0x49, 0x8a, 0xaa, 0x68, 0x38, 0x94, 0xae, 0xba, 0xbf, 0xf9, 0x9f, 0x7f, 0x95, 0x85,
+	0x5f, 0x5e, 0x94, 0x85, 0xdf, 0x5d, 0x94, 0x85, 0x27, 0x17, 0x65, 0xe1, 0xbd, 0x8b, 0xb2, 0xf0,
+	0xcf, 0x8b, 0xb2, 0xf0, 0xee, 0xb3, 0xf2, 0xd2, 0x7b, 0xcf, 0xca, 0x4b, 0x7f, 0x7f, 0x56, 0x5e,
+	0x7a, 0x98, 0x64, 0x02, 0x3e, 0xfb, 0xbf, 0x01, 0x00, 0x97, 0x89, 0xb7, 0x7f, 0x01, 0x20, 0x00,
+	0x00,
+}
+
+func (this *JoinRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*JoinRequest)
+	if !ok {
+		that2, ok := that.(JoinRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Member.Equal(that1.Member) {
+		return false
+	}
+	return true
+}
+func (this *JoinResponse) Equal(that interface{}) bool {
+	if that == nil {
 		return this == nil
 	}
 
@@ -2043,6 +2752,12 @@ func (this *PollResponse) Equal(that interface{}) bool {
 	if this.Accepted != that1.Accepted {
 		return false
 	}
+	if this.Rejection != that1.Rejection {
+		return false
+	}
+	if this.Leader != that1.Leader {
+		return false
+	}
 	return true
 }
 func (this *VoteRequest) Equal(that interface{}) bool {
@@ -2076,6 +2791,9 @@ func (this *VoteRequest) Equal(that interface{}) bool {
 	if this.LastLogTerm != that1.LastLogTerm {
 		return false
 	}
+	if this.Transfer != that1.Transfer {
+		return false
+	}
 	return true
 }
 func (this *VoteResponse) Equal(that interface{}) bool {
@@ -2109,6 +2827,9 @@ func (this *VoteResponse) Equal(that interface{}) bool {
 	if this.Voted != that1.Voted {
 		return false
 	}
+	if this.Rejection != that1.Rejection {
+		return false
+	}
 	return true
 }
 func (this *TransferRequest) Equal(that interface{}) bool {
@@ -2133,6 +2854,12 @@ func (this *TransferRequest) Equal(that interface{}) bool {
 	if this.Member != that1.Member {
 		return false
 	}
+	if this.Term != that1.Term {
+		return false
+	}
+	if this.Leader != that1.Leader {
+		return false
+	}
 	return true
 }
 func (this *TransferResponse) Equal(that interface{}) bool {
@@ -2162,14 +2889,14 @@ func (this *TransferResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *AppendRequest) Equal(that interface{}) bool {
+func (this *DigestRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*AppendRequest)
+	that1, ok := that.(*DigestRequest)
 	if !ok {
-		that2, ok := that.(AppendRequest)
+		that2, ok := that.(DigestRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2181,22 +2908,85 @@ func (this *AppendRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Term != that1.Term {
-		return false
-	}
-	if this.Leader != that1.Leader {
+	if this.FirstIndex != that1.FirstIndex {
 		return false
 	}
-	if this.PrevLogIndex != that1.PrevLogIndex {
-		return false
-	}
-	if this.PrevLogTerm != that1.PrevLogTerm {
+	if this.Index != that1.Index {
 		return false
 	}
-	if len(this.Entries) != len(that1.Entries) {
-		return false
+	return true
+}
+func (this *DigestResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	for i := range this.Entries {
+
+	that1, ok := that.(*DigestResponse)
+	if !ok {
+		that2, ok := that.(DigestResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Status != that1.Status {
+		return false
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	if !bytes.Equal(this.Digest, that1.Digest) {
+		return false
+	}
+	if this.FirstIndex != that1.FirstIndex {
+		return false
+	}
+	if this.CommitIndex != that1.CommitIndex {
+		return false
+	}
+	return true
+}
+func (this *AppendRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AppendRequest)
+	if !ok {
+		that2, ok := that.(AppendRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Term != that1.Term {
+		return false
+	}
+	if this.Leader != that1.Leader {
+		return false
+	}
+	if this.PrevLogIndex != that1.PrevLogIndex {
+		return false
+	}
+	if this.PrevLogTerm != that1.PrevLogTerm {
+		return false
+	}
+	if len(this.Entries) != len(that1.Entries) {
+		return false
+	}
+	for i := range this.Entries {
 		if !this.Entries[i].Equal(that1.Entries[i]) {
 			return false
 		}
@@ -2204,6 +2994,9 @@ func (this *AppendRequest) Equal(that interface{}) bool {
 	if this.CommitIndex != that1.CommitIndex {
 		return false
 	}
+	if this.LastLogIndex != that1.LastLogIndex {
+		return false
+	}
 	return true
 }
 func (this *AppendResponse) Equal(that interface{}) bool {
@@ -2240,6 +3033,14 @@ func (this *AppendResponse) Equal(that interface{}) bool {
 	if this.LastLogIndex != that1.LastLogIndex {
 		return false
 	}
+	if len(this.Unrelayed) != len(that1.Unrelayed) {
+		return false
+	}
+	for i := range this.Unrelayed {
+		if this.Unrelayed[i] != that1.Unrelayed[i] {
+			return false
+		}
+	}
 	return true
 }
 func (this *InstallRequest) Equal(that interface{}) bool {
@@ -2276,6 +3077,15 @@ func (this *InstallRequest) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Data, that1.Data) {
 		return false
 	}
+	if this.SnapshotTerm != that1.SnapshotTerm {
+		return false
+	}
+	if this.Checksum != that1.Checksum {
+		return false
+	}
+	if !bytes.Equal(this.Metadata, that1.Metadata) {
+		return false
+	}
 	return true
 }
 func (this *InstallResponse) Equal(that interface{}) bool {
@@ -2374,6 +3184,9 @@ func (this *CommandResponse) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Output, that1.Output) {
 		return false
 	}
+	if this.Index != that1.Index {
+		return false
+	}
 	return true
 }
 func (this *QueryRequest) Equal(that interface{}) bool {
@@ -2401,6 +3214,15 @@ func (this *QueryRequest) Equal(that interface{}) bool {
 	if this.ReadConsistency != that1.ReadConsistency {
 		return false
 	}
+	if this.ForwardDepth != that1.ForwardDepth {
+		return false
+	}
+	if this.MinIndex != that1.MinIndex {
+		return false
+	}
+	if this.MinIndexTimeout != that1.MinIndexTimeout {
+		return false
+	}
 	return true
 }
 func (this *QueryResponse) Equal(that interface{}) bool {
@@ -2436,6 +3258,152 @@ func (this *QueryResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *WatchStatusRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*WatchStatusRequest)
+	if !ok {
+		that2, ok := that.(WatchStatusRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *MemberProgress) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MemberProgress)
+	if !ok {
+		that2, ok := that.(MemberProgress)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MemberID != that1.MemberID {
+		return false
+	}
+	if this.MatchIndex != that1.MatchIndex {
+		return false
+	}
+	if this.NextIndex != that1.NextIndex {
+		return false
+	}
+	if this.Degraded != that1.Degraded {
+		return false
+	}
+	return true
+}
+func (this *MemberStatus) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MemberStatus)
+	if !ok {
+		that2, ok := that.(MemberStatus)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MemberID != that1.MemberID {
+		return false
+	}
+	if this.Term != that1.Term {
+		return false
+	}
+	if this.Role != that1.Role {
+		return false
+	}
+	if this.Leader != that1.Leader {
+		return false
+	}
+	if this.CommitIndex != that1.CommitIndex {
+		return false
+	}
+	if this.LastIndex != that1.LastIndex {
+		return false
+	}
+	if len(this.Progress) != len(that1.Progress) {
+		return false
+	}
+	for i := range this.Progress {
+		if !this.Progress[i].Equal(that1.Progress[i]) {
+			return false
+		}
+	}
+	if !this.ElectionFailure.Equal(that1.ElectionFailure) {
+		return false
+	}
+	if this.ApplyRate != that1.ApplyRate {
+		return false
+	}
+	if this.CommitRate != that1.CommitRate {
+		return false
+	}
+	return true
+}
+func (this *ElectionFailure) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ElectionFailure)
+	if !ok {
+		that2, ok := that.(ElectionFailure)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Term != that1.Term {
+		return false
+	}
+	if this.Reason != that1.Reason {
+		return false
+	}
+	if this.Rejections != that1.Rejections {
+		return false
+	}
+	if this.Unreachable != that1.Unreachable {
+		return false
+	}
+	if this.Voters != that1.Voters {
+		return false
+	}
+	return true
+}
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
@@ -2456,6 +3424,7 @@ type RaftServiceClient interface {
 	Poll(ctx context.Context, in *PollRequest, opts ...grpc.CallOption) (*PollResponse, error)
 	Vote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteResponse, error)
 	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	Digest(ctx context.Context, in *DigestRequest, opts ...grpc.CallOption) (*DigestResponse, error)
 	Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error)
 	Install(ctx context.Context, opts ...grpc.CallOption) (RaftService_InstallClient, error)
 	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (RaftService_CommandClient, error)
@@ -2533,6 +3502,15 @@ func (c *raftServiceClient) Transfer(ctx context.Context, in *TransferRequest, o
 	return out, nil
 }
 
+func (c *raftServiceClient) Digest(ctx context.Context, in *DigestRequest, opts ...grpc.CallOption) (*DigestResponse, error) {
+	out := new(DigestResponse)
+	err := c.cc.Invoke(ctx, "/atomix.raft.protocol.RaftService/Digest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *raftServiceClient) Append(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error) {
 	out := new(AppendResponse)
 	err := c.cc.Invoke(ctx, "/atomix.raft.protocol.RaftService/Append", in, out, opts...)
@@ -2649,6 +3627,7 @@ type RaftServiceServer interface {
 	Poll(context.Context, *PollRequest) (*PollResponse, error)
 	Vote(context.Context, *VoteRequest) (*VoteResponse, error)
 	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	Digest(context.Context, *DigestRequest) (*DigestResponse, error)
 	Append(context.Context, *AppendRequest) (*AppendResponse, error)
 	Install(RaftService_InstallServer) error
 	Command(*CommandRequest, RaftService_CommandServer) error
@@ -2680,6 +3659,9 @@ func (*UnimplementedRaftServiceServer) Vote(ctx context.Context, req *VoteReques
 func (*UnimplementedRaftServiceServer) Transfer(ctx context.Context, req *TransferRequest) (*TransferResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
 }
+func (*UnimplementedRaftServiceServer) Digest(ctx context.Context, req *DigestRequest) (*DigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Digest not implemented")
+}
 func (*UnimplementedRaftServiceServer) Append(ctx context.Context, req *AppendRequest) (*AppendResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Append not implemented")
 }
@@ -2823,6 +3805,24 @@ func _RaftService_Transfer_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RaftService_Digest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftServiceServer).Digest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/atomix.raft.protocol.RaftService/Digest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftServiceServer).Digest(ctx, req.(*DigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RaftService_Append_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AppendRequest)
 	if err := dec(in); err != nil {
@@ -2941,6 +3941,10 @@ var _RaftService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Transfer",
 			Handler:    _RaftService_Transfer_Handler,
 		},
+		{
+			MethodName: "Digest",
+			Handler:    _RaftService_Digest_Handler,
+		},
 		{
 			MethodName: "Append",
 			Handler:    _RaftService_Append_Handler,
@@ -2966,27 +3970,126 @@ var _RaftService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "atomix/raft/protocol/protocol.proto",
 }
 
-func (m *JoinRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+// RaftStatusServiceClient is the client API for RaftStatusService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RaftStatusServiceClient interface {
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (RaftStatusService_WatchStatusClient, error)
+}
+
+type raftStatusServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRaftStatusServiceClient(cc *grpc.ClientConn) RaftStatusServiceClient {
+	return &raftStatusServiceClient{cc}
+}
+
+func (c *raftStatusServiceClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (RaftStatusService_WatchStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_RaftStatusService_serviceDesc.Streams[0], "/atomix.raft.protocol.RaftStatusService/WatchStatus", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	x := &raftStatusServiceWatchStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (m *JoinRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type RaftStatusService_WatchStatusClient interface {
+	Recv() (*MemberStatus, error)
+	grpc.ClientStream
 }
 
-func (m *JoinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Member != nil {
+type raftStatusServiceWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *raftStatusServiceWatchStatusClient) Recv() (*MemberStatus, error) {
+	m := new(MemberStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RaftStatusServiceServer is the server API for RaftStatusService service.
+type RaftStatusServiceServer interface {
+	WatchStatus(*WatchStatusRequest, RaftStatusService_WatchStatusServer) error
+}
+
+// UnimplementedRaftStatusServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedRaftStatusServiceServer struct {
+}
+
+func (*UnimplementedRaftStatusServiceServer) WatchStatus(req *WatchStatusRequest, srv RaftStatusService_WatchStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+
+func RegisterRaftStatusServiceServer(s *grpc.Server, srv RaftStatusServiceServer) {
+	s.RegisterService(&_RaftStatusService_serviceDesc, srv)
+}
+
+func _RaftStatusService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RaftStatusServiceServer).WatchStatus(m, &raftStatusServiceWatchStatusServer{stream})
+}
+
+type RaftStatusService_WatchStatusServer interface {
+	Send(*MemberStatus) error
+	grpc.ServerStream
+}
+
+type raftStatusServiceWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *raftStatusServiceWatchStatusServer) Send(m *MemberStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _RaftStatusService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "atomix.raft.protocol.RaftStatusService",
+	HandlerType: (*RaftStatusServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _RaftStatusService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "atomix/raft/protocol/protocol.proto",
+}
+
+func (m *JoinRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *JoinRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *JoinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Member != nil {
 		{
 			size, err := m.Member.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
@@ -3436,6 +4539,18 @@ func (m *PollResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Leader) > 0 {
+		i -= len(m.Leader)
+		copy(dAtA[i:], m.Leader)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Leader)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Rejection != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Rejection))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.Accepted {
 		i--
 		if m.Accepted {
@@ -3484,6 +4599,16 @@ func (m *VoteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Transfer {
+		i--
+		if m.Transfer {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.LastLogTerm != 0 {
 		i = encodeVarintProtocol(dAtA, i, uint64(m.LastLogTerm))
 		i--
@@ -3529,6 +4654,11 @@ func (m *VoteResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Rejection != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Rejection))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.Voted {
 		i--
 		if m.Voted {
@@ -3577,6 +4707,18 @@ func (m *TransferRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Leader) > 0 {
+		i -= len(m.Leader)
+		copy(dAtA[i:], m.Leader)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Leader)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Term != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Term))
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.Member) > 0 {
 		i -= len(m.Member)
 		copy(dAtA[i:], m.Member)
@@ -3620,6 +4762,89 @@ func (m *TransferResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *DigestRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DigestRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DigestRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Index != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.FirstIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.FirstIndex))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DigestResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DigestResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DigestResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CommitIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.CommitIndex))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FirstIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.FirstIndex))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Error != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Error))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Status != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *AppendRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -3640,6 +4865,11 @@ func (m *AppendRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.LastLogIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.LastLogIndex))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.CommitIndex != 0 {
 		i = encodeVarintProtocol(dAtA, i, uint64(m.CommitIndex))
 		i--
@@ -3704,6 +4934,15 @@ func (m *AppendResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Unrelayed) > 0 {
+		for iNdEx := len(m.Unrelayed) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Unrelayed[iNdEx])
+			copy(dAtA[i:], m.Unrelayed[iNdEx])
+			i = encodeVarintProtocol(dAtA, i, uint64(len(m.Unrelayed[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
 	if m.LastLogIndex != 0 {
 		i = encodeVarintProtocol(dAtA, i, uint64(m.LastLogIndex))
 		i--
@@ -3757,6 +4996,23 @@ func (m *InstallRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Metadata) > 0 {
+		i -= len(m.Metadata)
+		copy(dAtA[i:], m.Metadata)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Metadata)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.SnapshotTerm != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.SnapshotTerm))
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Data) > 0 {
 		i -= len(m.Data)
 		copy(dAtA[i:], m.Data)
@@ -3875,6 +5131,11 @@ func (m *CommandResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Index != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x40
+	}
 	if len(m.Output) > 0 {
 		i -= len(m.Output)
 		copy(dAtA[i:], m.Output)
@@ -3943,6 +5204,24 @@ func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	n9, err9 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.MinIndexTimeout, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.MinIndexTimeout):])
+	if err9 != nil {
+		return 0, err9
+	}
+	i -= n9
+	i = encodeVarintProtocol(dAtA, i, uint64(n9))
+	i--
+	dAtA[i] = 0x2a
+	if m.MinIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.MinIndex))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ForwardDepth != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.ForwardDepth))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.ReadConsistency != 0 {
 		i = encodeVarintProtocol(dAtA, i, uint64(m.ReadConsistency))
 		i--
@@ -4005,70 +5284,288 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintProtocol(dAtA []byte, offset int, v uint64) int {
-	offset -= sovProtocol(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func NewPopulatedJoinRequest(r randyProtocol, easy bool) *JoinRequest {
-	this := &JoinRequest{}
-	if r.Intn(5) != 0 {
-		this.Member = NewPopulatedMember(r, easy)
-	}
-	if !easy && r.Intn(10) != 0 {
+func (m *WatchStatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return this
+	return dAtA[:n], nil
 }
 
-func NewPopulatedJoinResponse(r randyProtocol, easy bool) *JoinResponse {
-	this := &JoinResponse{}
-	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
-	this.Index = Index(uint64(r.Uint32()))
-	this.Term = Term(uint64(r.Uint32()))
-	v1 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
-	this.Timestamp = *v1
-	if r.Intn(5) != 0 {
-		v2 := r.Intn(5)
-		this.Members = make([]*Member, v2)
-		for i := 0; i < v2; i++ {
-			this.Members[i] = NewPopulatedMember(r, easy)
-		}
-	}
-	if !easy && r.Intn(10) != 0 {
-	}
-	return this
+func (m *WatchStatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func NewPopulatedConfigureRequest(r randyProtocol, easy bool) *ConfigureRequest {
-	this := &ConfigureRequest{}
-	this.Term = Term(uint64(r.Uint32()))
-	this.Leader = MemberID(randStringProtocol(r))
-	this.Index = Index(uint64(r.Uint32()))
-	v3 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
-	this.Timestamp = *v3
-	if r.Intn(5) != 0 {
-		v4 := r.Intn(5)
-		this.Members = make([]*Member, v4)
-		for i := 0; i < v4; i++ {
-			this.Members[i] = NewPopulatedMember(r, easy)
-		}
-	}
-	if !easy && r.Intn(10) != 0 {
-	}
-	return this
+func (m *WatchStatusRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
 }
 
-func NewPopulatedConfigureResponse(r randyProtocol, easy bool) *ConfigureResponse {
-	this := &ConfigureResponse{}
+func (m *MemberProgress) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MemberProgress) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MemberProgress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Degraded {
+		i--
+		if m.Degraded {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.NextIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.NextIndex))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MatchIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.MatchIndex))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.MemberID) > 0 {
+		i -= len(m.MemberID)
+		copy(dAtA[i:], m.MemberID)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.MemberID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MemberStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MemberStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MemberStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CommitRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.CommitRate))))
+		i--
+		dAtA[i] = 0x51
+	}
+	if m.ApplyRate != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ApplyRate))))
+		i--
+		dAtA[i] = 0x49
+	}
+	if m.ElectionFailure != nil {
+		{
+			size, err := m.ElectionFailure.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintProtocol(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Progress) > 0 {
+		for iNdEx := len(m.Progress) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Progress[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintProtocol(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.LastIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.LastIndex))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.CommitIndex != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.CommitIndex))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Leader) > 0 {
+		i -= len(m.Leader)
+		copy(dAtA[i:], m.Leader)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Leader)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Term != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Term))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.MemberID) > 0 {
+		i -= len(m.MemberID)
+		copy(dAtA[i:], m.MemberID)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.MemberID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ElectionFailure) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ElectionFailure) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ElectionFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Voters != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Voters))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Unreachable != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Unreachable))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Rejections != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Rejections))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Reason != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Reason))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Term != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Term))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintProtocol(dAtA []byte, offset int, v uint64) int {
+	offset -= sovProtocol(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func NewPopulatedJoinRequest(r randyProtocol, easy bool) *JoinRequest {
+	this := &JoinRequest{}
+	if r.Intn(5) != 0 {
+		this.Member = NewPopulatedMember(r, easy)
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedJoinResponse(r randyProtocol, easy bool) *JoinResponse {
+	this := &JoinResponse{}
+	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
+	this.Index = Index(uint64(r.Uint32()))
+	this.Term = Term(uint64(r.Uint32()))
+	v1 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
+	this.Timestamp = *v1
+	if r.Intn(5) != 0 {
+		v2 := r.Intn(5)
+		this.Members = make([]*Member, v2)
+		for i := 0; i < v2; i++ {
+			this.Members[i] = NewPopulatedMember(r, easy)
+		}
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedConfigureRequest(r randyProtocol, easy bool) *ConfigureRequest {
+	this := &ConfigureRequest{}
+	this.Term = Term(uint64(r.Uint32()))
+	this.Leader = MemberID(randStringProtocol(r))
+	this.Index = Index(uint64(r.Uint32()))
+	v3 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
+	this.Timestamp = *v3
+	if r.Intn(5) != 0 {
+		v4 := r.Intn(5)
+		this.Members = make([]*Member, v4)
+		for i := 0; i < v4; i++ {
+			this.Members[i] = NewPopulatedMember(r, easy)
+		}
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedConfigureResponse(r randyProtocol, easy bool) *ConfigureResponse {
+	this := &ConfigureResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4089,7 +5586,7 @@ func NewPopulatedReconfigureRequest(r randyProtocol, easy bool) *ReconfigureRequ
 func NewPopulatedReconfigureResponse(r randyProtocol, easy bool) *ReconfigureResponse {
 	this := &ReconfigureResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Index = Index(uint64(r.Uint32()))
 	this.Term = Term(uint64(r.Uint32()))
 	v5 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
@@ -4119,7 +5616,7 @@ func NewPopulatedLeaveRequest(r randyProtocol, easy bool) *LeaveRequest {
 func NewPopulatedLeaveResponse(r randyProtocol, easy bool) *LeaveResponse {
 	this := &LeaveResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Index = Index(uint64(r.Uint32()))
 	this.Term = Term(uint64(r.Uint32()))
 	v7 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
@@ -4150,9 +5647,11 @@ func NewPopulatedPollRequest(r randyProtocol, easy bool) *PollRequest {
 func NewPopulatedPollResponse(r randyProtocol, easy bool) *PollResponse {
 	this := &PollResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Term = Term(uint64(r.Uint32()))
 	this.Accepted = bool(bool(r.Intn(2) == 0))
+	this.Rejection = RejectionReason([]int32{0, 1, 2, 3, 4, 5, 6}[r.Intn(7)])
+	this.Leader = MemberID(randStringProtocol(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4164,6 +5663,7 @@ func NewPopulatedVoteRequest(r randyProtocol, easy bool) *VoteRequest {
 	this.Candidate = MemberID(randStringProtocol(r))
 	this.LastLogIndex = Index(uint64(r.Uint32()))
 	this.LastLogTerm = Term(uint64(r.Uint32()))
+	this.Transfer = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4172,9 +5672,10 @@ func NewPopulatedVoteRequest(r randyProtocol, easy bool) *VoteRequest {
 func NewPopulatedVoteResponse(r randyProtocol, easy bool) *VoteResponse {
 	this := &VoteResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Term = Term(uint64(r.Uint32()))
 	this.Voted = bool(bool(r.Intn(2) == 0))
+	this.Rejection = RejectionReason([]int32{0, 1, 2, 3, 4, 5, 6}[r.Intn(7)])
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4183,6 +5684,8 @@ func NewPopulatedVoteResponse(r randyProtocol, easy bool) *VoteResponse {
 func NewPopulatedTransferRequest(r randyProtocol, easy bool) *TransferRequest {
 	this := &TransferRequest{}
 	this.Member = MemberID(randStringProtocol(r))
+	this.Term = Term(uint64(r.Uint32()))
+	this.Leader = MemberID(randStringProtocol(r))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4191,7 +5694,32 @@ func NewPopulatedTransferRequest(r randyProtocol, easy bool) *TransferRequest {
 func NewPopulatedTransferResponse(r randyProtocol, easy bool) *TransferResponse {
 	this := &TransferResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedDigestRequest(r randyProtocol, easy bool) *DigestRequest {
+	this := &DigestRequest{}
+	this.FirstIndex = Index(uint64(r.Uint32()))
+	this.Index = Index(uint64(r.Uint32()))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedDigestResponse(r randyProtocol, easy bool) *DigestResponse {
+	this := &DigestResponse{}
+	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
+	v9 := r.Intn(100)
+	this.Digest = make([]byte, v9)
+	for i := 0; i < v9; i++ {
+		this.Digest[i] = byte(r.Intn(256))
+	}
+	this.FirstIndex = Index(uint64(r.Uint32()))
+	this.CommitIndex = Index(uint64(r.Uint32()))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4204,13 +5732,14 @@ func NewPopulatedAppendRequest(r randyProtocol, easy bool) *AppendRequest {
 	this.PrevLogIndex = Index(uint64(r.Uint32()))
 	this.PrevLogTerm = Term(uint64(r.Uint32()))
 	if r.Intn(5) != 0 {
-		v9 := r.Intn(5)
-		this.Entries = make([]*LogEntry, v9)
-		for i := 0; i < v9; i++ {
+		v10 := r.Intn(5)
+		this.Entries = make([]*LogEntry, v10)
+		for i := 0; i < v10; i++ {
 			this.Entries[i] = NewPopulatedLogEntry(r, easy)
 		}
 	}
 	this.CommitIndex = Index(uint64(r.Uint32()))
+	this.LastLogIndex = Index(uint64(r.Uint32()))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4219,10 +5748,15 @@ func NewPopulatedAppendRequest(r randyProtocol, easy bool) *AppendRequest {
 func NewPopulatedAppendResponse(r randyProtocol, easy bool) *AppendResponse {
 	this := &AppendResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Term = Term(uint64(r.Uint32()))
 	this.Succeeded = bool(bool(r.Intn(2) == 0))
 	this.LastLogIndex = Index(uint64(r.Uint32()))
+	v11 := r.Intn(10)
+	this.Unrelayed = make([]MemberID, v11)
+	for i := 0; i < v11; i++ {
+		this.Unrelayed[i] = MemberID(randStringProtocol(r))
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4233,13 +5767,20 @@ func NewPopulatedInstallRequest(r randyProtocol, easy bool) *InstallRequest {
 	this.Term = Term(uint64(r.Uint32()))
 	this.Leader = MemberID(randStringProtocol(r))
 	this.Index = Index(uint64(r.Uint32()))
-	v10 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
-	this.Timestamp = *v10
-	v11 := r.Intn(100)
-	this.Data = make([]byte, v11)
-	for i := 0; i < v11; i++ {
+	v12 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
+	this.Timestamp = *v12
+	v13 := r.Intn(100)
+	this.Data = make([]byte, v13)
+	for i := 0; i < v13; i++ {
 		this.Data[i] = byte(r.Intn(256))
 	}
+	this.SnapshotTerm = Term(uint64(r.Uint32()))
+	this.Checksum = uint32(r.Uint32())
+	v14 := r.Intn(100)
+	this.Metadata = make([]byte, v14)
+	for i := 0; i < v14; i++ {
+		this.Metadata[i] = byte(r.Intn(256))
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4248,7 +5789,7 @@ func NewPopulatedInstallRequest(r randyProtocol, easy bool) *InstallRequest {
 func NewPopulatedInstallResponse(r randyProtocol, easy bool) *InstallResponse {
 	this := &InstallResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4256,9 +5797,9 @@ func NewPopulatedInstallResponse(r randyProtocol, easy bool) *InstallResponse {
 
 func NewPopulatedCommandRequest(r randyProtocol, easy bool) *CommandRequest {
 	this := &CommandRequest{}
-	v12 := r.Intn(100)
-	this.Value = make([]byte, v12)
-	for i := 0; i < v12; i++ {
+	v15 := r.Intn(100)
+	this.Value = make([]byte, v15)
+	for i := 0; i < v15; i++ {
 		this.Value[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -4269,20 +5810,21 @@ func NewPopulatedCommandRequest(r randyProtocol, easy bool) *CommandRequest {
 func NewPopulatedCommandResponse(r randyProtocol, easy bool) *CommandResponse {
 	this := &CommandResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Message = string(randStringProtocol(r))
 	this.Leader = MemberID(randStringProtocol(r))
 	this.Term = Term(uint64(r.Uint32()))
-	v13 := r.Intn(10)
-	this.Members = make([]MemberID, v13)
-	for i := 0; i < v13; i++ {
+	v16 := r.Intn(10)
+	this.Members = make([]MemberID, v16)
+	for i := 0; i < v16; i++ {
 		this.Members[i] = MemberID(randStringProtocol(r))
 	}
-	v14 := r.Intn(100)
-	this.Output = make([]byte, v14)
-	for i := 0; i < v14; i++ {
+	v17 := r.Intn(100)
+	this.Output = make([]byte, v17)
+	for i := 0; i < v17; i++ {
 		this.Output[i] = byte(r.Intn(256))
 	}
+	this.Index = Index(uint64(r.Uint32()))
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4290,12 +5832,16 @@ func NewPopulatedCommandResponse(r randyProtocol, easy bool) *CommandResponse {
 
 func NewPopulatedQueryRequest(r randyProtocol, easy bool) *QueryRequest {
 	this := &QueryRequest{}
-	v15 := r.Intn(100)
-	this.Value = make([]byte, v15)
-	for i := 0; i < v15; i++ {
+	v18 := r.Intn(100)
+	this.Value = make([]byte, v18)
+	for i := 0; i < v18; i++ {
 		this.Value[i] = byte(r.Intn(256))
 	}
-	this.ReadConsistency = ReadConsistency([]int32{0, 1, 2}[r.Intn(3)])
+	this.ReadConsistency = ReadConsistency([]int32{0, 1, 2, 3}[r.Intn(4)])
+	this.ForwardDepth = uint32(r.Uint32())
+	this.MinIndex = Index(uint64(r.Uint32()))
+	v19 := github_com_gogo_protobuf_types.NewPopulatedStdDuration(r, easy)
+	this.MinIndexTimeout = *v19
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -4304,11 +5850,11 @@ func NewPopulatedQueryRequest(r randyProtocol, easy bool) *QueryRequest {
 func NewPopulatedQueryResponse(r randyProtocol, easy bool) *QueryResponse {
 	this := &QueryResponse{}
 	this.Status = ResponseStatus([]int32{0, 1}[r.Intn(2)])
-	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}[r.Intn(12)])
+	this.Error = ResponseError([]int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}[r.Intn(21)])
 	this.Message = string(randStringProtocol(r))
-	v16 := r.Intn(100)
-	this.Output = make([]byte, v16)
-	for i := 0; i < v16; i++ {
+	v20 := r.Intn(100)
+	this.Output = make([]byte, v20)
+	for i := 0; i < v20; i++ {
 		this.Output[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -4316,18 +5862,79 @@ func NewPopulatedQueryResponse(r randyProtocol, easy bool) *QueryResponse {
 	return this
 }
 
-type randyProtocol interface {
-	Float32() float32
-	Float64() float64
-	Int63() int64
-	Int31() int32
-	Uint32() uint32
-	Intn(n int) int
+func NewPopulatedWatchStatusRequest(r randyProtocol, easy bool) *WatchStatusRequest {
+	this := &WatchStatusRequest{}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
 }
 
-func randUTF8RuneProtocol(r randyProtocol) rune {
-	ru := r.Intn(62)
-	if ru < 10 {
+func NewPopulatedMemberProgress(r randyProtocol, easy bool) *MemberProgress {
+	this := &MemberProgress{}
+	this.MemberID = MemberID(randStringProtocol(r))
+	this.MatchIndex = Index(uint64(r.Uint32()))
+	this.NextIndex = Index(uint64(r.Uint32()))
+	this.Degraded = bool(bool(r.Intn(2) == 0))
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedMemberStatus(r randyProtocol, easy bool) *MemberStatus {
+	this := &MemberStatus{}
+	this.MemberID = MemberID(randStringProtocol(r))
+	this.Term = Term(uint64(r.Uint32()))
+	this.Role = RoleType(randStringProtocol(r))
+	this.Leader = MemberID(randStringProtocol(r))
+	this.CommitIndex = Index(uint64(r.Uint32()))
+	this.LastIndex = Index(uint64(r.Uint32()))
+	if r.Intn(5) != 0 {
+		v21 := r.Intn(5)
+		this.Progress = make([]*MemberProgress, v21)
+		for i := 0; i < v21; i++ {
+			this.Progress[i] = NewPopulatedMemberProgress(r, easy)
+		}
+	}
+	if r.Intn(5) != 0 {
+		this.ElectionFailure = NewPopulatedElectionFailure(r, easy)
+	}
+	this.ApplyRate = float64(r.Float64())
+	if r.Intn(2) == 0 {
+		this.ApplyRate *= -1
+	}
+	this.CommitRate = float64(r.Float64())
+	if r.Intn(2) == 0 {
+		this.CommitRate *= -1
+	}
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+func NewPopulatedElectionFailure(r randyProtocol, easy bool) *ElectionFailure {
+	this := &ElectionFailure{}
+	this.Term = Term(uint64(r.Uint32()))
+	this.Reason = RejectionReason([]int32{0, 1, 2, 3, 4, 5, 6}[r.Intn(7)])
+	this.Rejections = uint32(r.Uint32())
+	this.Unreachable = uint32(r.Uint32())
+	this.Voters = uint32(r.Uint32())
+	if !easy && r.Intn(10) != 0 {
+	}
+	return this
+}
+
+type randyProtocol interface {
+	Float32() float32
+	Float64() float64
+	Int63() int64
+	Int31() int32
+	Uint32() uint32
+	Intn(n int) int
+}
+
+func randUTF8RuneProtocol(r randyProtocol) rune {
+	ru := r.Intn(62)
+	if ru < 10 {
 		return rune(ru + 48)
 	} else if ru < 36 {
 		return rune(ru + 55)
@@ -4335,9 +5942,9 @@ func randUTF8RuneProtocol(r randyProtocol) rune {
 	return rune(ru + 61)
 }
 func randStringProtocol(r randyProtocol) string {
-	v17 := r.Intn(100)
-	tmps := make([]rune, v17)
-	for i := 0; i < v17; i++ {
+	v22 := r.Intn(100)
+	tmps := make([]rune, v22)
+	for i := 0; i < v22; i++ {
 		tmps[i] = randUTF8RuneProtocol(r)
 	}
 	return string(tmps)
@@ -4359,11 +5966,11 @@ func randFieldProtocol(dAtA []byte, r randyProtocol, fieldNumber int, wire int)
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateProtocol(dAtA, uint64(key))
-		v18 := r.Int63()
+		v23 := r.Int63()
 		if r.Intn(2) == 0 {
-			v18 *= -1
+			v23 *= -1
 		}
-		dAtA = encodeVarintPopulateProtocol(dAtA, uint64(v18))
+		dAtA = encodeVarintPopulateProtocol(dAtA, uint64(v23))
 	case 1:
 		dAtA = encodeVarintPopulateProtocol(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -4602,6 +6209,13 @@ func (m *PollResponse) Size() (n int) {
 	if m.Accepted {
 		n += 2
 	}
+	if m.Rejection != 0 {
+		n += 1 + sovProtocol(uint64(m.Rejection))
+	}
+	l = len(m.Leader)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
 	return n
 }
 
@@ -4624,6 +6238,9 @@ func (m *VoteRequest) Size() (n int) {
 	if m.LastLogTerm != 0 {
 		n += 1 + sovProtocol(uint64(m.LastLogTerm))
 	}
+	if m.Transfer {
+		n += 2
+	}
 	return n
 }
 
@@ -4645,6 +6262,9 @@ func (m *VoteResponse) Size() (n int) {
 	if m.Voted {
 		n += 2
 	}
+	if m.Rejection != 0 {
+		n += 1 + sovProtocol(uint64(m.Rejection))
+	}
 	return n
 }
 
@@ -4658,6 +6278,13 @@ func (m *TransferRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovProtocol(uint64(l))
 	}
+	if m.Term != 0 {
+		n += 1 + sovProtocol(uint64(m.Term))
+	}
+	l = len(m.Leader)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
 	return n
 }
 
@@ -4676,6 +6303,46 @@ func (m *TransferResponse) Size() (n int) {
 	return n
 }
 
+func (m *DigestRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FirstIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.FirstIndex))
+	}
+	if m.Index != 0 {
+		n += 1 + sovProtocol(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *DigestResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Status != 0 {
+		n += 1 + sovProtocol(uint64(m.Status))
+	}
+	if m.Error != 0 {
+		n += 1 + sovProtocol(uint64(m.Error))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if m.FirstIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.FirstIndex))
+	}
+	if m.CommitIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.CommitIndex))
+	}
+	return n
+}
+
 func (m *AppendRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4704,6 +6371,9 @@ func (m *AppendRequest) Size() (n int) {
 	if m.CommitIndex != 0 {
 		n += 1 + sovProtocol(uint64(m.CommitIndex))
 	}
+	if m.LastLogIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.LastLogIndex))
+	}
 	return n
 }
 
@@ -4728,6 +6398,12 @@ func (m *AppendResponse) Size() (n int) {
 	if m.LastLogIndex != 0 {
 		n += 1 + sovProtocol(uint64(m.LastLogIndex))
 	}
+	if len(m.Unrelayed) > 0 {
+		for _, s := range m.Unrelayed {
+			l = len(s)
+			n += 1 + l + sovProtocol(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -4753,6 +6429,16 @@ func (m *InstallRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovProtocol(uint64(l))
 	}
+	if m.SnapshotTerm != 0 {
+		n += 1 + sovProtocol(uint64(m.SnapshotTerm))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovProtocol(uint64(m.Checksum))
+	}
+	l = len(m.Metadata)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
 	return n
 }
 
@@ -4817,6 +6503,9 @@ func (m *CommandResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovProtocol(uint64(l))
 	}
+	if m.Index != 0 {
+		n += 1 + sovProtocol(uint64(m.Index))
+	}
 	return n
 }
 
@@ -4833,6 +6522,14 @@ func (m *QueryRequest) Size() (n int) {
 	if m.ReadConsistency != 0 {
 		n += 1 + sovProtocol(uint64(m.ReadConsistency))
 	}
+	if m.ForwardDepth != 0 {
+		n += 1 + sovProtocol(uint64(m.ForwardDepth))
+	}
+	if m.MinIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.MinIndex))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.MinIndexTimeout)
+	n += 1 + l + sovProtocol(uint64(l))
 	return n
 }
 
@@ -4859,13 +6556,1288 @@ func (m *QueryResponse) Size() (n int) {
 	return n
 }
 
+func (m *WatchStatusRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *MemberProgress) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MemberID)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if m.MatchIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.MatchIndex))
+	}
+	if m.NextIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.NextIndex))
+	}
+	if m.Degraded {
+		n += 2
+	}
+	return n
+}
+
+func (m *MemberStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MemberID)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if m.Term != 0 {
+		n += 1 + sovProtocol(uint64(m.Term))
+	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	l = len(m.Leader)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if m.CommitIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.CommitIndex))
+	}
+	if m.LastIndex != 0 {
+		n += 1 + sovProtocol(uint64(m.LastIndex))
+	}
+	if len(m.Progress) > 0 {
+		for _, e := range m.Progress {
+			l = e.Size()
+			n += 1 + l + sovProtocol(uint64(l))
+		}
+	}
+	if m.ElectionFailure != nil {
+		l = m.ElectionFailure.Size()
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if m.ApplyRate != 0 {
+		n += 9
+	}
+	if m.CommitRate != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *ElectionFailure) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Term != 0 {
+		n += 1 + sovProtocol(uint64(m.Term))
+	}
+	if m.Reason != 0 {
+		n += 1 + sovProtocol(uint64(m.Reason))
+	}
+	if m.Rejections != 0 {
+		n += 1 + sovProtocol(uint64(m.Rejections))
+	}
+	if m.Unreachable != 0 {
+		n += 1 + sovProtocol(uint64(m.Unreachable))
+	}
+	if m.Voters != 0 {
+		n += 1 + sovProtocol(uint64(m.Voters))
+	}
+	return n
+}
+
 func sovProtocol(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
 func sozProtocol(x uint64) (n int) {
 	return sovProtocol(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
-func (m *JoinRequest) Unmarshal(dAtA []byte) error {
+func (m *JoinRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JoinRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JoinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &Member{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *JoinResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: JoinResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: JoinResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= ResponseStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			m.Error = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Error |= ResponseError(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, &Member{})
+			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConfigureRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConfigureRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Leader = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, &Member{})
+			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConfigureResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConfigureResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConfigureResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= ResponseStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			m.Error = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Error |= ResponseError(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReconfigureRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReconfigureRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &Member{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReconfigureResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReconfigureResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReconfigureResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= ResponseStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			m.Error = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Error |= ResponseError(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, &Member{})
+			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LeaveRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LeaveRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LeaveRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &Member{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LeaveResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LeaveResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= ResponseStatus(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			m.Error = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Error |= ResponseError(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, &Member{})
+			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PollRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4888,17 +7860,36 @@ func (m *JoinRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: JoinRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PollRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: JoinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PollRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Candidate", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -4908,28 +7899,62 @@ func (m *JoinRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Member == nil {
-				m.Member = &Member{}
+			m.Candidate = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
 			}
-			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.LastLogIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastLogIndex |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogTerm", wireType)
+			}
+			m.LastLogTerm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastLogTerm |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -4954,7 +7979,7 @@ func (m *JoinRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *JoinResponse) Unmarshal(dAtA []byte) error {
+func (m *PollResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -4977,10 +8002,10 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: JoinResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PollResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: JoinResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PollResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5023,9 +8048,9 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 			}
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.Index = 0
+			m.Term = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5035,16 +8060,16 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
+				m.Term |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Accepted", wireType)
 			}
-			m.Term = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5054,16 +8079,17 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Accepted = bool(v != 0)
 		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejection", wireType)
 			}
-			var msglen int
+			m.Rejection = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5073,30 +8099,16 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Rejection |= RejectionReason(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5106,25 +8118,23 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Members = append(m.Members, &Member{})
-			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Leader = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5150,7 +8160,7 @@ func (m *JoinResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
+func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5173,10 +8183,10 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ConfigureRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: VoteRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ConfigureRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VoteRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5200,7 +8210,7 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 			}
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Candidate", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5228,13 +8238,13 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Leader = MemberID(dAtA[iNdEx:postIndex])
+			m.Candidate = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
 			}
-			m.Index = 0
+			m.LastLogIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5244,16 +8254,16 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
+				m.LastLogIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogTerm", wireType)
 			}
-			var msglen int
+			m.LastLogTerm = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5263,30 +8273,16 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.LastLogTerm |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Transfer", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5296,26 +8292,12 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Members = append(m.Members, &Member{})
-			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			m.Transfer = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5340,7 +8322,7 @@ func (m *ConfigureRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ConfigureResponse) Unmarshal(dAtA []byte) error {
+func (m *VoteResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5363,10 +8345,10 @@ func (m *ConfigureResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ConfigureResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: VoteResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ConfigureResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VoteResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5407,6 +8389,64 @@ func (m *ConfigureResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Voted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Voted = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejection", wireType)
+			}
+			m.Rejection = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rejection |= RejectionReason(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5431,7 +8471,7 @@ func (m *ConfigureResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
+func (m *TransferRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5454,17 +8494,17 @@ func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReconfigureRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TransferRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReconfigureRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TransferRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5474,33 +8514,29 @@ func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Member == nil {
-				m.Member = &Member{}
-			}
-			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Member = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.Index = 0
+			m.Term = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5510,16 +8546,16 @@ func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
+				m.Term |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
 			}
-			m.Term = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5529,11 +8565,24 @@ func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Leader = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5558,7 +8607,7 @@ func (m *ReconfigureRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReconfigureResponse) Unmarshal(dAtA []byte) error {
+func (m *TransferResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5581,10 +8630,10 @@ func (m *ReconfigureResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReconfigureResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TransferResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReconfigureResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TransferResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5625,111 +8674,6 @@ func (m *ReconfigureResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
-			}
-			m.Index = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
-			}
-			m.Term = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Members = append(m.Members, &Member{})
-			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5754,7 +8698,7 @@ func (m *ReconfigureResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LeaveRequest) Unmarshal(dAtA []byte) error {
+func (m *DigestRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5777,17 +8721,17 @@ func (m *LeaveRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LeaveRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: DigestRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LeaveRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DigestRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstIndex", wireType)
 			}
-			var msglen int
+			m.FirstIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5797,28 +8741,30 @@ func (m *LeaveRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.FirstIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Member == nil {
-				m.Member = &Member{}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
 			}
-			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5843,7 +8789,7 @@ func (m *LeaveRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
+func (m *DigestResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5866,10 +8812,10 @@ func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LeaveResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DigestResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LeaveResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DigestResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -5911,48 +8857,10 @@ func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
 				}
 			}
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
-			}
-			m.Index = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
-			}
-			m.Term = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5962,30 +8870,31 @@ func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Digest = append(m.Digest[:0], dAtA[iNdEx:postIndex]...)
+			if m.Digest == nil {
+				m.Digest = []byte{}
 			}
 			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstIndex", wireType)
 			}
-			var msglen int
+			m.FirstIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5995,26 +8904,30 @@ func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.FirstIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitIndex", wireType)
 			}
-			m.Members = append(m.Members, &Member{})
-			if err := m.Members[len(m.Members)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.CommitIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommitIndex |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -6039,7 +8952,7 @@ func (m *LeaveResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PollRequest) Unmarshal(dAtA []byte) error {
+func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6062,10 +8975,10 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PollRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: AppendRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PollRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AppendRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6089,7 +9002,7 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 			}
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Candidate", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6117,13 +9030,13 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Candidate = MemberID(dAtA[iNdEx:postIndex])
+			m.Leader = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevLogIndex", wireType)
 			}
-			m.LastLogIndex = 0
+			m.PrevLogIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6133,16 +9046,16 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastLogIndex |= Index(b&0x7F) << shift
+				m.PrevLogIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastLogTerm", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PrevLogTerm", wireType)
 			}
-			m.LastLogTerm = 0
+			m.PrevLogTerm = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6152,7 +9065,79 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastLogTerm |= Term(b&0x7F) << shift
+				m.PrevLogTerm |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Entries = append(m.Entries, &LogEntry{})
+			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitIndex", wireType)
+			}
+			m.CommitIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommitIndex |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
+			}
+			m.LastLogIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastLogIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -6181,7 +9166,7 @@ func (m *PollRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PollResponse) Unmarshal(dAtA []byte) error {
+func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6204,10 +9189,10 @@ func (m *PollResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PollResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: AppendResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PollResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AppendResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6269,7 +9254,7 @@ func (m *PollResponse) Unmarshal(dAtA []byte) error {
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Accepted", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Succeeded", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -6286,7 +9271,58 @@ func (m *PollResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.Accepted = bool(v != 0)
+			m.Succeeded = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
+			}
+			m.LastLogIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastLogIndex |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unrelayed", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unrelayed = append(m.Unrelayed, MemberID(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -6311,7 +9347,7 @@ func (m *PollResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VoteRequest) Unmarshal(dAtA []byte) error {
+func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6334,17 +9370,87 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VoteRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: InstallRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VoteRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: InstallRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.Term = 0
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Leader = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6354,16 +9460,30 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Candidate", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6373,29 +9493,31 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Candidate = MemberID(dAtA[iNdEx:postIndex])
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
 			iNdEx = postIndex
-		case 3:
+		case 6:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SnapshotTerm", wireType)
 			}
-			m.LastLogIndex = 0
+			m.SnapshotTerm = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6405,16 +9527,16 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastLogIndex |= Index(b&0x7F) << shift
+				m.SnapshotTerm |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastLogTerm", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
 			}
-			m.LastLogTerm = 0
+			m.Checksum = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6424,11 +9546,45 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastLogTerm |= Term(b&0x7F) << shift
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata[:0], dAtA[iNdEx:postIndex]...)
+			if m.Metadata == nil {
+				m.Metadata = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -6453,7 +9609,7 @@ func (m *VoteRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VoteResponse) Unmarshal(dAtA []byte) error {
+func (m *InstallResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6476,10 +9632,10 @@ func (m *VoteResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VoteResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: InstallResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VoteResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: InstallResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6520,45 +9676,6 @@ func (m *VoteResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
-			}
-			m.Term = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Voted", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.Voted = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -6583,7 +9700,7 @@ func (m *VoteResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TransferRequest) Unmarshal(dAtA []byte) error {
+func (m *CommandRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6606,17 +9723,17 @@ func (m *TransferRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TransferRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: CommandRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TransferRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CommandRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6626,23 +9743,25 @@ func (m *TransferRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Member = MemberID(dAtA[iNdEx:postIndex])
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -6668,7 +9787,7 @@ func (m *TransferRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TransferResponse) Unmarshal(dAtA []byte) error {
+func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6691,10 +9810,10 @@ func (m *TransferResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TransferResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CommandResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TransferResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CommandResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -6735,64 +9854,11 @@ func (m *TransferResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipProtocol(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *AppendRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowProtocol
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: AppendRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AppendRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			m.Term = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6802,12 +9868,25 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
 			}
@@ -6839,11 +9918,11 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Leader = MemberID(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PrevLogIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.PrevLogIndex = 0
+			m.Term = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6853,16 +9932,16 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.PrevLogIndex |= Index(b&0x7F) << shift
+				m.Term |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PrevLogTerm", wireType)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
 			}
-			m.PrevLogTerm = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6872,16 +9951,29 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.PrevLogTerm |= Term(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, MemberID(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6891,31 +9983,31 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Entries = append(m.Entries, &LogEntry{})
-			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Output = append(m.Output[:0], dAtA[iNdEx:postIndex]...)
+			if m.Output == nil {
+				m.Output = []byte{}
 			}
 			iNdEx = postIndex
-		case 6:
+		case 8:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CommitIndex", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
 			}
-			m.CommitIndex = 0
+			m.Index = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6925,7 +10017,7 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CommitIndex |= Index(b&0x7F) << shift
+				m.Index |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -6954,7 +10046,7 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AppendResponse) Unmarshal(dAtA []byte) error {
+func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6977,17 +10069,17 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AppendResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AppendResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
-			m.Status = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -6997,16 +10089,31 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= ResponseStatus(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadConsistency", wireType)
 			}
-			m.Error = 0
+			m.ReadConsistency = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7016,16 +10123,16 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Error |= ResponseError(b&0x7F) << shift
+				m.ReadConsistency |= ReadConsistency(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ForwardDepth", wireType)
 			}
-			m.Term = 0
+			m.ForwardDepth = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7035,16 +10142,16 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				m.ForwardDepth |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Succeeded", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MinIndex", wireType)
 			}
-			var v int
+			m.MinIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7054,17 +10161,16 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				m.MinIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Succeeded = bool(v != 0)
 		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastLogIndex", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinIndexTimeout", wireType)
 			}
-			m.LastLogIndex = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7074,11 +10180,25 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastLogIndex |= Index(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.MinIndexTimeout, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -7103,7 +10223,7 @@ func (m *AppendResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *InstallRequest) Unmarshal(dAtA []byte) error {
+func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7126,17 +10246,17 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: InstallRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: InstallRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
 			}
-			m.Term = 0
+			m.Status = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7146,48 +10266,16 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				m.Status |= ResponseStatus(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Leader", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Leader = MemberID(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
-			m.Index = 0
+			m.Error = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7197,16 +10285,16 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= Index(b&0x7F) << shift
+				m.Error |= ResponseError(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7216,28 +10304,27 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -7264,9 +10351,9 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
-			if m.Data == nil {
-				m.Data = []byte{}
+			m.Output = append(m.Output[:0], dAtA[iNdEx:postIndex]...)
+			if m.Output == nil {
+				m.Output = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -7293,7 +10380,7 @@ func (m *InstallRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *InstallResponse) Unmarshal(dAtA []byte) error {
+func (m *WatchStatusRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7316,50 +10403,12 @@ func (m *InstallResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: InstallResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: WatchStatusRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: InstallResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WatchStatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
-			}
-			m.Status = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Status |= ResponseStatus(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
-			}
-			m.Error = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Error |= ResponseError(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -7384,7 +10433,7 @@ func (m *InstallResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CommandRequest) Unmarshal(dAtA []byte) error {
+func (m *MemberProgress) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7407,17 +10456,68 @@ func (m *CommandRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CommandRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: MemberProgress: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommandRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MemberProgress: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MemberID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemberID = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MatchIndex", wireType)
+			}
+			m.MatchIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MatchIndex |= Index(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			var byteLen int
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextIndex", wireType)
+			}
+			m.NextIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7427,26 +10527,31 @@ func (m *CommandRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.NextIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Degraded", wireType)
 			}
-			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
-			if m.Value == nil {
-				m.Value = []byte{}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
+			m.Degraded = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -7471,7 +10576,7 @@ func (m *CommandRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CommandResponse) Unmarshal(dAtA []byte) error {
+func (m *MemberStatus) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7494,17 +10599,17 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CommandResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: MemberStatus: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommandResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MemberStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemberID", wireType)
 			}
-			m.Status = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7514,16 +10619,29 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= ResponseStatus(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemberID = MemberID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.Error = 0
+			m.Term = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7533,14 +10651,14 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Error |= ResponseError(b&0x7F) << shift
+				m.Term |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -7568,7 +10686,7 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Message = string(dAtA[iNdEx:postIndex])
+			m.Role = RoleType(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
@@ -7604,9 +10722,9 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitIndex", wireType)
 			}
-			m.Term = 0
+			m.CommitIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7616,16 +10734,16 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Term |= Term(b&0x7F) << shift
+				m.CommitIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastIndex", wireType)
 			}
-			var stringLen uint64
+			m.LastIndex = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7635,29 +10753,16 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.LastIndex |= Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Members = append(m.Members, MemberID(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
 		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7667,84 +10772,31 @@ func (m *CommandResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Output = append(m.Output[:0], dAtA[iNdEx:postIndex]...)
-			if m.Output == nil {
-				m.Output = []byte{}
-			}
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipProtocol(dAtA[iNdEx:])
-			if err != nil {
+			m.Progress = append(m.Progress, &MemberProgress{})
+			if err := m.Progress[len(m.Progress)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *QueryRequest) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowProtocol
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: QueryRequest: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectionFailure", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7754,45 +10806,50 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
-			if m.Value == nil {
-				m.Value = []byte{}
+			if m.ElectionFailure == nil {
+				m.ElectionFailure = &ElectionFailure{}
+			}
+			if err := m.ElectionFailure.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReadConsistency", wireType)
+		case 9:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApplyRate", wireType)
 			}
-			m.ReadConsistency = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowProtocol
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.ReadConsistency |= ReadConsistency(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ApplyRate = float64(math.Float64frombits(v))
+		case 10:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitRate", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
 			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.CommitRate = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -7817,7 +10874,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryResponse) Unmarshal(dAtA []byte) error {
+func (m *ElectionFailure) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -7840,17 +10897,17 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ElectionFailure: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ElectionFailure: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
 			}
-			m.Status = 0
+			m.Term = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7860,16 +10917,16 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Status |= ResponseStatus(b&0x7F) << shift
+				m.Term |= Term(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
-			m.Error = 0
+			m.Reason = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7879,16 +10936,16 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Error |= ResponseError(b&0x7F) << shift
+				m.Reason |= RejectionReason(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rejections", wireType)
 			}
-			var stringLen uint64
+			m.Rejections = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7898,29 +10955,16 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Rejections |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Message = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unreachable", wireType)
 			}
-			var byteLen int
+			m.Unreachable = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -7930,26 +10974,30 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Unreachable |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Voters", wireType)
 			}
-			m.Output = append(m.Output[:0], dAtA[iNdEx:postIndex]...)
-			if m.Output == nil {
-				m.Output = []byte{}
+			m.Voters = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Voters |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
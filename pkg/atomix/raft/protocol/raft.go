@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Status represents the status of a Raft server
@@ -29,11 +32,23 @@ const (
 	// StatusStopped indicates the server is not running
 	StatusStopped Status = "stopped"
 
+	// StatusStopping indicates the server is shutting down and draining pending requests
+	StatusStopping Status = "stopping"
+
 	// StatusRunning indicates the server is running but has not found a leader
 	StatusRunning Status = "running"
 
 	// StatusReady indicates the server is running, has found a leader, and has applied committed entries to its state machine
 	StatusReady Status = "ready"
+
+	// StatusCatchingUp indicates the server was elected leader and is applying entries committed before its election
+	// Reads are not served by the leader until it has caught up to the commit index at the time of its election.
+	StatusCatchingUp Status = "catching up"
+
+	// StatusFaulted indicates the server's state machine failed to apply a committed entry, or the term advanced
+	// beyond the maximum term gap and the server is configured to fault on term gaps
+	// A faulted server does not serve reads and remains faulted until it's restarted.
+	StatusFaulted Status = "faulted"
 )
 
 // NewRaft returns a new Raft protocol state struct
@@ -41,18 +56,26 @@ func NewRaft(cluster Cluster, config *config.ProtocolConfig, protocol Client, ro
 	return newRaft(cluster, config, protocol, roles, newMemoryMetadataStore())
 }
 
+// NewPersistentRaft returns a new Raft protocol state struct that stores its metadata in the given store
+func NewPersistentRaft(cluster Cluster, config *config.ProtocolConfig, protocol Client, roles map[RoleType]func(Raft) Role, store MetadataStore) Raft {
+	return newRaft(cluster, config, protocol, roles, store)
+}
+
 // newRaft returns a new Raft protocol state struct
 func newRaft(cluster Cluster, config *config.ProtocolConfig, protocol Client, roles map[RoleType]func(Raft) Role, store MetadataStore) Raft {
-	return &raft{
-		log:      util.NewNodeLogger(string(cluster.Member())),
-		config:   config,
-		protocol: protocol,
-		status:   StatusStopped,
-		watchers: make([]func(Event), 0),
-		roles:    roles,
-		cluster:  cluster,
-		metadata: store,
+	raft := &raft{
+		log:        util.NewNodeLogger(string(cluster.Member())),
+		protocol:   newTermValidatingClient(protocol, cluster.Member()),
+		watchers:   make([]*eventWatcher, 0),
+		roles:      roles,
+		cluster:    cluster,
+		metadata:   store,
+		termGaps:   metrics.NewCounter("raft_term_gaps_total", string(cluster.Member())),
+		commitRate: metrics.NewRate("raft_commit_rate", string(cluster.Member()), config.GetRateWindowOrDefault()),
 	}
+	raft.status.Store(StatusStopped)
+	raft.config.Store(config)
+	return raft
 }
 
 // MemberID is the ID of a Raft cluster member
@@ -72,17 +95,26 @@ type Raft interface {
 	Init()
 
 	// Watch watches the Raft protocol state for changes
-	Watch(func(Event))
+	// The returned function removes the watcher.
+	Watch(func(Event)) func()
 
 	// Role is the current role
 	Role() RoleType
 
 	// Status returns the Raft protocol status
+	// The status can be read without a lock on the Raft state.
 	Status() Status
 
 	// Config returns the Raft protocol configuration
+	// The configuration must not be modified. Configuration changes at runtime replace the configuration, so
+	// callers should not retain the returned configuration.
 	Config() *config.ProtocolConfig
 
+	// SetElectionTimeout updates the election timeout at runtime
+	// The timeout must be at least twice the heartbeat interval. Roles implementing ElectionTimeoutListener are
+	// notified of the change so their timers use the new timeout.
+	SetElectionTimeout(timeout time.Duration) error
+
 	// Member returns the local member ID
 	Member() MemberID
 
@@ -92,6 +124,11 @@ type Raft interface {
 	// GetMember returns a RaftMember by ID
 	GetMember(memberID MemberID) *Member
 
+	// Configuration returns the committed cluster configuration
+	// Membership is fixed by the cluster configuration with which the node was started, so there is never a pending
+	// configuration change to return.
+	Configuration() *Configuration
+
 	// Client returns the Raft messaging protocol
 	Protocol() Client
 
@@ -107,12 +144,80 @@ type Raft interface {
 	// SetLeader sets the current leader
 	SetLeader(leader *MemberID) error
 
+	// LeaderTime returns the time at which the current leader was last set
+	LeaderTime() time.Time
+
 	// LastVotedFor returns the last member voted for by this node
 	LastVotedFor() *MemberID
 
 	// SetLastVotedFor sets the last member voted for by this node
 	SetLastVotedFor(memberID MemberID) error
 
+	// SyncMetadata blocks until the term and vote are durable
+	// The term and vote must be synced before they're sent to other members. Responses to Raft requests are
+	// synced before they're returned, so SyncMetadata only needs to be called before sending requests.
+	SyncMetadata() error
+
+	// MatchIndex returns the last persisted match index for the given member, or 0 if none is known
+	// The match index is only a hint used to initialize replication and must be verified with the member.
+	MatchIndex(memberID MemberID) Index
+
+	// SetMatchIndex persists the last known match index for the given member
+	// Unlike other state, match indexes may be set without holding a lock on the state.
+	SetMatchIndex(memberID MemberID, index Index)
+
+	// WitnessRecord returns the last entry in the leader's log recorded by the local member if it's a witness
+	// Witnesses store no entries, so the record takes the place of the last entry in the log.
+	WitnessRecord() WitnessRecord
+
+	// SetWitnessRecord persists the last entry in the leader's log recorded by the local member if it's a witness
+	SetWitnessRecord(record WitnessRecord)
+
+	// Progress returns the replication progress of each member if the local member is the leader, otherwise nil
+	Progress() []*MemberProgress
+
+	// ForceInstall marks the given member to be caught up by installing the leader's current snapshot
+	// If the local member is not the leader, ErrNotLeader is returned.
+	ForceInstall(member MemberID) error
+
+	// SetCatchingUp sets whether the leader is catching up to the commit index at the time of its election
+	// While the leader is catching up, the status is StatusCatchingUp.
+	SetCatchingUp(catchingUp bool)
+
+	// SetFaulted marks the server's state machine as faulted
+	// Once faulted, the status is StatusFaulted until the server is closed.
+	SetFaulted()
+
+	// ElectionFailure returns the reason the local member's most recent campaign failed, or nil if the member has not
+	// failed to become the leader since it was last elected
+	ElectionFailure() *ElectionFailure
+
+	// SetElectionFailure records the reason the local member's most recent campaign failed
+	// The failure is cleared when the member becomes the leader.
+	SetElectionFailure(failure *ElectionFailure)
+
+	// CommitStalled returns a bool indicating whether the leader's commit index has stalled with proposals pending
+	CommitStalled() bool
+
+	// SetCommitStalled sets whether the leader's commit index has stalled, notifying watchers if it changed
+	// The stall is cleared when the local member's role changes.
+	SetCommitStalled(stalled bool)
+
+	// InMaintenance returns a bool indicating whether the local member is in maintenance mode
+	// Members in maintenance mode continue to replicate entries and vote but do not stand for election.
+	InMaintenance() bool
+
+	// SetMaintenance puts the local member in maintenance mode until the given time
+	// A zero time takes the member out of maintenance mode.
+	SetMaintenance(until time.Time)
+
+	// Handoff returns the last leadership transfer to or from the local member, or nil if there is none
+	Handoff() *Handoff
+
+	// SetHandoff records a leadership transfer to or from the local member
+	// A nil handoff clears the record once the transfer has completed.
+	SetHandoff(handoff *Handoff)
+
 	// CommitIndex returns the current commit index
 	CommitIndex() Index
 
@@ -122,6 +227,15 @@ type Raft interface {
 	// Commit sets the persisted commit index
 	Commit(index Index) Index
 
+	// BeginRead begins a read transaction serving queries against the state machine at a single read index
+	// The transaction is released once it's closed or the given timeout expires. If the local member is not the
+	// leader, ErrNotLeader is returned.
+	BeginRead(timeout time.Duration) (ReadTransaction, error)
+
+	// Timestamp returns a cluster-wide timestamp that never goes backward, even across leader changes
+	// If the local member is not the leader, ErrNotLeader is returned.
+	Timestamp() (time.Time, error)
+
 	// WriteLock acquires a write lock on the state
 	WriteLock()
 
@@ -141,13 +255,27 @@ type Raft interface {
 	Close() error
 }
 
+// Handoff is a leadership transfer from a leader to another member
+// A leader that transfers leadership may continue to serve reads from its local state until its lease expires or it
+// learns of its successor, so the successor must not serve reads or accept commands until the previous leader has
+// acknowledged it or the lease has expired.
+type Handoff struct {
+	// Term is the term from which leadership was transferred
+	Term Term
+	// Leader is the member that transferred leadership
+	Leader MemberID
+	// Until is the time at which the lease expires if the local member transferred leadership
+	Until time.Time
+}
+
 // Event is a Raft protocol state change event
 type Event struct {
-	Type   EventType
-	Status Status
-	Role   RoleType
-	Term   Term
-	Leader *MemberID
+	Type          EventType
+	Status        Status
+	Role          RoleType
+	Term          Term
+	Leader        *MemberID
+	CommitStalled bool
 }
 
 // EventType is a Raft protocol state change event type
@@ -165,6 +293,9 @@ const (
 
 	// EventTypeLeader is a leader change event
 	EventTypeLeader EventType = "Leader"
+
+	// EventTypeCommitStall is an event indicating the leader's commit index has stalled or resumed advancing
+	EventTypeCommitStall EventType = "CommitStall"
 )
 
 // RoleType is the name of a role
@@ -181,6 +312,24 @@ const (
 	RoleLeader RoleType = "Leader"
 )
 
+// ProgressReporter is implemented by roles that replicate entries to other members
+type ProgressReporter interface {
+	// Progress returns the replication progress of each member
+	Progress() []*MemberProgress
+}
+
+// SnapshotInstaller is implemented by roles that can install snapshots on other members
+type SnapshotInstaller interface {
+	// ForceInstall marks the given member to be caught up by installing the current snapshot rather than appending entries
+	ForceInstall(member MemberID) error
+}
+
+// ElectionTimeoutListener is implemented by roles with timers derived from the election timeout
+type ElectionTimeoutListener interface {
+	// ElectionTimeoutChanged is called with a write lock on the Raft object when the election timeout is updated
+	ElectionTimeoutChanged(timeout time.Duration)
+}
+
 // Role is implemented by server roles to support protocol requests
 type Role interface {
 	Server
@@ -193,26 +342,61 @@ type Role interface {
 	Start() error
 
 	// Stop stops the role
-	// The Stop method will always be called with a write lock on the Raft object
+	// The Stop method will always be called with a write lock on the Raft object, except when the Raft state
+	// is closed, in which case the status is StatusStopping and no lock is held.
 	Stop() error
 }
 
+// ReadTransaction is a set of queries served against the state machine at a single index
+type ReadTransaction interface {
+	// Index returns the index at which the transaction reads
+	Index() Index
+
+	// Query applies a query at the transaction's read index
+	Query(request *QueryRequest, ch chan<- *QueryStreamResponse) error
+
+	// Close closes the transaction, resuming application of changes to the state machine
+	Close()
+}
+
+// ReadTransactor is implemented by roles that can serve read transactions
+type ReadTransactor interface {
+	// BeginRead begins a read transaction that is released once closed or once the given timeout expires
+	BeginRead(timeout time.Duration) (ReadTransaction, error)
+}
+
+// Clock is implemented by roles that can provide cluster-wide timestamps
+type Clock interface {
+	// Timestamp returns a timestamp committed to the log that never goes backward across leader changes
+	Timestamp() (time.Time, error)
+}
+
 // raft is the default implementation of the Raft protocol state
 type raft struct {
 	log              util.Logger
-	status           Status
-	config           *config.ProtocolConfig
+	status           atomic.Value
+	config           atomic.Value
 	protocol         Client
 	metadata         MetadataStore
-	watchers         []func(Event)
+	watchers         []*eventWatcher
+	watchersMu       sync.Mutex
 	roles            map[RoleType]func(Raft) Role
 	role             Role
+	closed           bool
 	term             Term
 	leader           *MemberID
+	leaderTime       time.Time
 	lastVotedFor     *MemberID
+	witnessRecord    WitnessRecord
 	firstCommitIndex *Index
 	commitIndex      Index
+	electionFailure  *ElectionFailure
+	commitStalled    bool
+	maintenanceUntil time.Time
+	handoff          *Handoff
 	cluster          Cluster
+	termGaps         *metrics.Counter
+	commitRate       *metrics.Rate
 	mu               sync.RWMutex
 }
 
@@ -222,42 +406,96 @@ func (r *raft) Init() {
 		r.term = *term
 	}
 	r.lastVotedFor = r.metadata.LoadVote()
+	if record := r.metadata.LoadWitnessRecord(); record != nil {
+		r.witnessRecord = *record
+	}
 	r.setStatus(StatusRunning)
 	r.SetRole(RoleFollower)
 }
 
-func (r *raft) Watch(watcher func(Event)) {
-	r.watchers = append(r.watchers, watcher)
+// eventWatcher is a watcher added to the Raft state
+type eventWatcher struct {
+	f func(Event)
+}
+
+func (r *raft) Watch(f func(Event)) func() {
+	watcher := &eventWatcher{f: f}
+	r.watchersMu.Lock()
+	watchers := make([]*eventWatcher, 0, len(r.watchers)+1)
+	watchers = append(watchers, r.watchers...)
+	r.watchers = append(watchers, watcher)
+	r.watchersMu.Unlock()
+	return func() {
+		r.unwatch(watcher)
+	}
+}
+
+// unwatch removes the given watcher
+func (r *raft) unwatch(watcher *eventWatcher) {
+	r.watchersMu.Lock()
+	defer r.watchersMu.Unlock()
+	watchers := make([]*eventWatcher, 0, len(r.watchers))
+	for _, w := range r.watchers {
+		if w != watcher {
+			watchers = append(watchers, w)
+		}
+	}
+	r.watchers = watchers
 }
 
 func (r *raft) notify(eventType EventType) {
 	event := Event{
-		Type:   eventType,
-		Status: r.status,
-		Role:   r.Role(),
-		Term:   r.term,
-		Leader: r.leader,
+		Type:          eventType,
+		Status:        r.Status(),
+		Role:          r.Role(),
+		Term:          r.term,
+		Leader:        r.leader,
+		CommitStalled: r.commitStalled,
 	}
-	for _, watcher := range r.watchers {
-		watcher(event)
+	r.watchersMu.Lock()
+	watchers := r.watchers
+	r.watchersMu.Unlock()
+	for _, watcher := range watchers {
+		watcher.f(event)
 	}
 }
 
 func (r *raft) Status() Status {
-	return r.status
+	return r.status.Load().(Status)
 }
 
 // setStatus sets the node's status
+// A faulted node only transitions to the stopping and stopped statuses.
 func (r *raft) setStatus(status Status) {
-	if r.status != status {
+	if r.Status() == StatusFaulted && status != StatusStopping && status != StatusStopped {
+		return
+	}
+	if r.Status() != status {
 		r.log.Info("Server is %s", status)
-		r.status = status
+		r.status.Store(status)
 		r.notify(EventTypeStatus)
 	}
 }
 
 func (r *raft) Config() *config.ProtocolConfig {
-	return r.config
+	return r.config.Load().(*config.ProtocolConfig)
+}
+
+// minElectionTimeoutFactor is the minimum ratio of the election timeout to the heartbeat interval
+const minElectionTimeoutFactor = 2
+
+func (r *raft) SetElectionTimeout(timeout time.Duration) error {
+	config := *r.Config()
+	if heartbeatInterval := config.GetHeartbeatIntervalOrDefault(); timeout < heartbeatInterval*minElectionTimeoutFactor {
+		return fmt.Errorf("election timeout %s must be at least %d times the heartbeat interval %s", timeout, minElectionTimeoutFactor, heartbeatInterval)
+	}
+	r.log.Info("Updating election timeout to %s", timeout)
+	config.ElectionTimeout = &timeout
+	r.config.Store(&config)
+	if listener, ok := r.role.(ElectionTimeoutListener); ok {
+		listener.ElectionTimeoutChanged(timeout)
+	}
+	return nil
 }
 
 func (r *raft) Protocol() Client {
@@ -276,6 +514,10 @@ func (r *raft) GetMember(memberID MemberID) *Member {
 	return r.cluster.GetMember(memberID)
 }
 
+func (r *raft) Configuration() *Configuration {
+	return r.cluster.Configuration()
+}
+
 func (r *raft) Connect(memberID MemberID) (RaftServiceClient, error) {
 	return r.cluster.GetClient(memberID)
 }
@@ -288,6 +530,7 @@ func (r *raft) SetTerm(term Term) error {
 	if term < r.term {
 		return fmt.Errorf("cannot decrease term %d to %d", r.term, term)
 	} else if term > r.term {
+		r.checkTermGap(term)
 		r.term = term
 		r.leader = nil
 		r.lastVotedFor = nil
@@ -298,6 +541,24 @@ func (r *raft) SetTerm(term Term) error {
 	return nil
 }
 
+// checkTermGap warns if the given term is further ahead of the local term than the configured maximum term gap
+// Terms advance slowly in normal operation, so a large gap likely indicates a member that was partitioned for a long
+// time or a misconfigured cluster rather than a routine election. Members that have never observed a term are not
+// checked. If configured, the member is faulted and remains faulted until an operator restarts it.
+func (r *raft) checkTermGap(term Term) {
+	maxGap := r.Config().GetMaxTermGap()
+	if maxGap == 0 || r.term == 0 || uint64(term-r.term) <= maxGap {
+		return
+	}
+	r.termGaps.Inc()
+	r.log.Warn("Term increased from %d to %d, exceeding the maximum gap of %d terms; "+
+		"a member may have been partitioned for a long time, or the cluster may be misconfigured", r.term, term, maxGap)
+	if r.Config().GetFaultOnTermGap() {
+		r.log.Error("Faulting after the term increased beyond the maximum gap; the server must be restarted to recover")
+		r.setStatus(StatusFaulted)
+	}
+}
+
 func (r *raft) Leader() *MemberID {
 	return r.leader
 }
@@ -307,6 +568,7 @@ func (r *raft) SetLeader(leader *MemberID) error {
 		// If the leader is being set for the first time, verify it's a member of the cluster configuration
 		if r.GetMember(*leader) != nil {
 			r.leader = leader
+			r.leaderTime = time.Now()
 			r.notify(EventTypeLeader)
 		} else {
 			return fmt.Errorf("unknown member %+v", leader)
@@ -320,6 +582,10 @@ func (r *raft) SetLeader(leader *MemberID) error {
 	return nil
 }
 
+func (r *raft) LeaderTime() time.Time {
+	return r.leaderTime
+}
+
 func (r *raft) LastVotedFor() *MemberID {
 	return r.lastVotedFor
 }
@@ -341,6 +607,73 @@ func (r *raft) SetLastVotedFor(memberID MemberID) error {
 	return nil
 }
 
+func (r *raft) SyncMetadata() error {
+	return r.metadata.Sync()
+}
+
+func (r *raft) MatchIndex(memberID MemberID) Index {
+	if index := r.metadata.LoadMatchIndex(memberID); index != nil {
+		return *index
+	}
+	return 0
+}
+
+func (r *raft) SetMatchIndex(memberID MemberID, index Index) {
+	r.metadata.StoreMatchIndex(memberID, index)
+}
+
+func (r *raft) WitnessRecord() WitnessRecord {
+	return r.witnessRecord
+}
+
+func (r *raft) SetWitnessRecord(record WitnessRecord) {
+	r.witnessRecord = record
+	r.metadata.StoreWitnessRecord(&record)
+}
+
+func (r *raft) Progress() []*MemberProgress {
+	if reporter, ok := r.getRole().(ProgressReporter); ok {
+		return reporter.Progress()
+	}
+	return nil
+}
+
+func (r *raft) ForceInstall(member MemberID) error {
+	if installer, ok := r.getRole().(SnapshotInstaller); ok {
+		return installer.ForceInstall(member)
+	}
+	r.ReadLock()
+	defer r.ReadUnlock()
+	if r.leader != nil {
+		return &ErrNotLeader{Leader: *r.leader}
+	}
+	return &ErrNotLeader{}
+}
+
+func (r *raft) BeginRead(timeout time.Duration) (ReadTransaction, error) {
+	if transactor, ok := r.getRole().(ReadTransactor); ok {
+		return transactor.BeginRead(timeout)
+	}
+	r.ReadLock()
+	defer r.ReadUnlock()
+	if r.leader != nil {
+		return nil, &ErrNotLeader{Leader: *r.leader}
+	}
+	return nil, &ErrNotLeader{}
+}
+
+func (r *raft) Timestamp() (time.Time, error) {
+	if clock, ok := r.getRole().(Clock); ok {
+		return clock.Timestamp()
+	}
+	r.ReadLock()
+	defer r.ReadUnlock()
+	if r.leader != nil {
+		return time.Time{}, &ErrNotLeader{Leader: *r.leader}
+	}
+	return time.Time{}, &ErrNotLeader{}
+}
+
 func (r *raft) CommitIndex() Index {
 	return r.commitIndex
 }
@@ -351,11 +684,72 @@ func (r *raft) SetCommitIndex(index Index) {
 	}
 }
 
+func (r *raft) SetCatchingUp(catchingUp bool) {
+	if catchingUp {
+		if status := r.Status(); status == StatusRunning || status == StatusReady {
+			r.setStatus(StatusCatchingUp)
+		}
+	} else if r.Status() == StatusCatchingUp {
+		if r.firstCommitIndex != nil && r.commitIndex >= *r.firstCommitIndex {
+			r.setStatus(StatusReady)
+		} else {
+			r.setStatus(StatusRunning)
+		}
+	}
+}
+
+func (r *raft) SetFaulted() {
+	r.setStatus(StatusFaulted)
+}
+
+func (r *raft) ElectionFailure() *ElectionFailure {
+	return r.electionFailure
+}
+
+func (r *raft) SetElectionFailure(failure *ElectionFailure) {
+	r.electionFailure = failure
+}
+
+func (r *raft) CommitStalled() bool {
+	return r.commitStalled
+}
+
+func (r *raft) SetCommitStalled(stalled bool) {
+	if r.commitStalled != stalled {
+		r.commitStalled = stalled
+		r.notify(EventTypeCommitStall)
+	}
+}
+
+func (r *raft) InMaintenance() bool {
+	return time.Now().Before(r.maintenanceUntil)
+}
+
+func (r *raft) SetMaintenance(until time.Time) {
+	if until.IsZero() {
+		if r.InMaintenance() {
+			r.log.Info("Exiting maintenance mode")
+		}
+	} else {
+		r.log.Info("Entering maintenance mode until %s", until)
+	}
+	r.maintenanceUntil = until
+}
+
+func (r *raft) Handoff() *Handoff {
+	return r.handoff
+}
+
+func (r *raft) SetHandoff(handoff *Handoff) {
+	r.handoff = handoff
+}
+
 func (r *raft) Commit(index Index) Index {
 	prevIndex := r.commitIndex
 	if index > prevIndex {
 		r.commitIndex = index
-		if r.firstCommitIndex != nil && index >= *r.firstCommitIndex {
+		r.commitRate.Mark(uint64(index - prevIndex))
+		if r.Status() != StatusCatchingUp && r.firstCommitIndex != nil && index >= *r.firstCommitIndex {
 			r.setStatus(StatusReady)
 		}
 	}
@@ -393,6 +787,11 @@ func (r *raft) SetRole(roleType RoleType) {
 		return
 	}
 
+	// Once the state is closed, its role has been stopped and no new role is started
+	if r.closed {
+		return
+	}
+
 	// If the role has not changed, ignore the call
 	if r.role != nil && r.role.Type() == roleType {
 		return
@@ -406,6 +805,12 @@ func (r *raft) SetRole(roleType RoleType) {
 		}
 	}
 
+	// Once the local member is elected, the reason it previously failed to become the leader no longer applies
+	if roleType == RoleLeader {
+		r.electionFailure = nil
+	}
+	r.commitStalled = false
+
 	// Create and start the new role
 	role := roleFunc(r)
 	r.role = role
@@ -423,51 +828,161 @@ func (r *raft) getRole() Role {
 	return r.role
 }
 
+// currentRole returns the role to which to route a request, or ErrShuttingDown once the state has been closed
+func (r *raft) currentRole() (Role, error) {
+	role := r.getRole()
+	if role == nil {
+		return nil, ErrShuttingDown
+	}
+	return role, nil
+}
+
+// Responses to requests that can change the term or vote are returned only once the metadata is durable. Metadata is
+// synced after the role releases its lock, allowing concurrent requests to share a sync.
 func (r *raft) Poll(ctx context.Context, request *PollRequest) (*PollResponse, error) {
-	return r.getRole().Poll(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	response, err := role.Poll(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if err := r.metadata.Sync(); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (r *raft) Vote(ctx context.Context, request *VoteRequest) (*VoteResponse, error) {
-	return r.getRole().Vote(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	response, err := role.Vote(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if err := r.metadata.Sync(); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (r *raft) Append(ctx context.Context, request *AppendRequest) (*AppendResponse, error) {
-	return r.getRole().Append(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	response, err := role.Append(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	if err := r.metadata.Sync(); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (r *raft) Install(ch <-chan *InstallStreamRequest) (*InstallResponse, error) {
-	return r.getRole().Install(ch)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	response, err := role.Install(ch)
+	if err != nil {
+		return response, err
+	}
+	if err := r.metadata.Sync(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (r *raft) Digest(ctx context.Context, request *DigestRequest) (*DigestResponse, error) {
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Digest(ctx, request)
 }
 
 func (r *raft) Command(request *CommandRequest, ch chan<- *CommandStreamResponse) error {
-	return r.getRole().Command(request, ch)
+	role, err := r.currentRole()
+	if err != nil {
+		return err
+	}
+	return role.Command(request, ch)
 }
 
 func (r *raft) Query(request *QueryRequest, ch chan<- *QueryStreamResponse) error {
-	return r.getRole().Query(request, ch)
+	role, err := r.currentRole()
+	if err != nil {
+		return err
+	}
+	return role.Query(request, ch)
 }
 
 func (r *raft) Join(ctx context.Context, request *JoinRequest) (*JoinResponse, error) {
-	return r.getRole().Join(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Join(ctx, request)
 }
 
 func (r *raft) Leave(ctx context.Context, request *LeaveRequest) (*LeaveResponse, error) {
-	return r.getRole().Leave(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Leave(ctx, request)
 }
 
 func (r *raft) Configure(ctx context.Context, request *ConfigureRequest) (*ConfigureResponse, error) {
-	return r.getRole().Configure(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Configure(ctx, request)
 }
 
 func (r *raft) Reconfigure(ctx context.Context, request *ReconfigureRequest) (*ReconfigureResponse, error) {
-	return r.getRole().Reconfigure(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Reconfigure(ctx, request)
 }
 
 func (r *raft) Transfer(ctx context.Context, request *TransferRequest) (*TransferResponse, error) {
-	return r.getRole().Transfer(ctx, request)
+	role, err := r.currentRole()
+	if err != nil {
+		return nil, err
+	}
+	return role.Transfer(ctx, request)
 }
 
 func (r *raft) Close() error {
+	// Mark the state closed so no further role transitions stop or replace the current role.
+	r.WriteLock()
+	r.closed = true
+	r.setStatus(StatusStopping)
+	role := r.role
+	r.WriteUnlock()
+
+	// Stop the current role to drain or fail its pending requests. The role is stopped without a lock on the
+	// state to allow pending requests to be committed while draining.
+	if role != nil {
+		if err := role.Stop(); err != nil {
+			r.log.Error("Failed to stop %s role", role.Type(), err)
+		}
+	}
+
+	// Requests received once the role has been stopped are failed with ErrShuttingDown.
+	r.WriteLock()
+	r.role = nil
 	r.setStatus(StatusStopped)
+	r.WriteUnlock()
 	return r.metadata.Close()
 }
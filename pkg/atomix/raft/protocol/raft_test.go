@@ -31,18 +31,18 @@ func TestRaftProtocol(t *testing.T) {
 		MemberID: "foo",
 		Members: map[string]atomix.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "foo",
-				Port: 5678,
+				ID:           "foo",
+				Host:         "foo",
+				ProtocolPort: 5678,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "bar",
-				Port: 5679,
+				ID:           "bar",
+				Host:         "bar",
+				ProtocolPort: 5679,
 			},
 			"baz": {
-				ID:   "baz",
-				Port: 5680,
+				ID:           "baz",
+				ProtocolPort: 5680,
 			},
 		},
 	}
@@ -52,7 +52,7 @@ func TestRaftProtocol(t *testing.T) {
 	roles := make(map[RoleType]func(Raft) Role)
 	raft := newRaft(NewCluster(cluster), &config.ProtocolConfig{ElectionTimeout: &electionTimeout}, &unimplementedClient{}, roles, store)
 	assert.Equal(t, StatusStopped, raft.Status())
-	statusCh := make(chan Status, 1)
+	statusCh := make(chan Status, 2)
 	raft.Watch(func(event Event) {
 		if event.Type == EventTypeStatus {
 			statusCh <- event.Status
@@ -114,6 +114,19 @@ func TestRaftProtocol(t *testing.T) {
 	assert.Nil(t, raft.Leader())
 	assert.Equal(t, &bar, raft.LastVotedFor())
 
+	// Verify that a removed watcher is no longer notified
+	leaders := 0
+	unwatch := raft.Watch(func(event Event) {
+		if event.Type == EventTypeLeader {
+			leaders++
+		}
+	})
+	assert.NoError(t, raft.SetLeader(&bar))
+	assert.Equal(t, 1, leaders)
+	unwatch()
+	assert.NoError(t, raft.SetLeader(nil))
+	assert.Equal(t, 1, leaders)
+
 	// Verify that the lastVotedFor and leader are reset when term changes
 	assert.NoError(t, raft.SetTerm(Term(5)))
 	assert.Nil(t, raft.LastVotedFor())
@@ -139,10 +152,13 @@ func TestRaftProtocol(t *testing.T) {
 	// Increment the term and vote for later tests
 	assert.NoError(t, raft.SetTerm(Term(10)))
 	assert.NoError(t, raft.SetLastVotedFor(bar))
+	assert.Equal(t, Index(0), raft.MatchIndex(bar))
+	raft.SetMatchIndex(bar, Index(5))
 
 	// Verify that the status is changed on close
 	assert.NoError(t, raft.Close())
 	assert.Equal(t, StatusStopped, raft.Status())
+	assert.Equal(t, StatusStopping, <-statusCh)
 	assert.Equal(t, StatusStopped, <-statusCh)
 
 	// Verify that the cluster state is reloaded from the metadata store when restarted
@@ -168,6 +184,8 @@ func TestRaftProtocol(t *testing.T) {
 	assert.Nil(t, raft.Leader())
 	assert.Equal(t, &bar, raft.LastVotedFor())
 	assert.Equal(t, Index(0), raft.CommitIndex())
+	assert.Equal(t, Index(5), raft.MatchIndex(bar))
+	assert.Equal(t, Index(0), raft.MatchIndex(foo))
 
 	// Test a role change
 	roleCh := make(chan RoleType, 1)
@@ -191,6 +209,62 @@ func TestRaftProtocol(t *testing.T) {
 	raft.WriteUnlock()
 }
 
+// timeoutRole is a follower role that records election timeout changes
+type timeoutRole struct {
+	*testRole
+	timeout time.Duration
+}
+
+func (r *timeoutRole) Type() RoleType {
+	return RoleFollower
+}
+
+func (r *timeoutRole) ElectionTimeoutChanged(timeout time.Duration) {
+	r.timeout = timeout
+}
+
+func TestSetElectionTimeout(t *testing.T) {
+	cluster := atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID: "foo",
+			},
+			"bar": {
+				ID: "bar",
+			},
+		},
+	}
+	role := &timeoutRole{testRole: &testRole{}}
+	roles := map[RoleType]func(Raft) Role{
+		RoleFollower: func(r Raft) Role {
+			return role
+		},
+	}
+	electionTimeout := 5 * time.Second
+	heartbeatInterval := 500 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+	raft := newRaft(NewCluster(cluster), config, &unimplementedClient{}, roles, newMemoryMetadataStore())
+	raft.WriteLock()
+	defer raft.WriteUnlock()
+	raft.Init()
+
+	// Verify the timeout is validated against the heartbeat interval
+	assert.Error(t, raft.SetElectionTimeout(900*time.Millisecond))
+	assert.Equal(t, electionTimeout, raft.Config().GetElectionTimeoutOrDefault())
+	assert.Equal(t, time.Duration(0), role.timeout)
+
+	// Verify the configuration is replaced rather than modified and the role is notified of the change
+	assert.NoError(t, raft.SetElectionTimeout(time.Second))
+	assert.Equal(t, time.Second, raft.Config().GetElectionTimeoutOrDefault())
+	assert.Equal(t, heartbeatInterval, raft.Config().GetHeartbeatIntervalOrDefault())
+	assert.Equal(t, electionTimeout, config.GetElectionTimeoutOrDefault())
+	assert.Equal(t, time.Second, role.timeout)
+}
+
 type testRole struct {
 	Role
 	appended bool
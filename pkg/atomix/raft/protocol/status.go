@@ -0,0 +1,87 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	"time"
+)
+
+// NewStatusServer returns a new RaftStatusServiceServer that reports the status of the given Raft state
+// The lastIndex function returns the index of the last entry in the local log. The apply and commit rates are read
+// from the rates reported to metrics by the member's state machine and Raft state.
+func NewStatusServer(raft Raft, lastIndex func() Index) RaftStatusServiceServer {
+	member := string(raft.Member())
+	window := raft.Config().GetRateWindowOrDefault()
+	return &statusServer{
+		raft:       raft,
+		lastIndex:  lastIndex,
+		applyRate:  metrics.NewRate("raft_apply_rate", member, window),
+		commitRate: metrics.NewRate("raft_commit_rate", member, window),
+	}
+}
+
+// statusServer is a RaftStatusServiceServer that periodically sends the status of the local member
+type statusServer struct {
+	raft       Raft
+	lastIndex  func() Index
+	applyRate  *metrics.Rate
+	commitRate *metrics.Rate
+}
+
+// WatchStatus sends the current status of the local member followed by the status at each status interval
+// To coalesce rapid changes, the status is sampled once per interval and only sent when it has changed.
+func (s *statusServer) WatchStatus(request *WatchStatusRequest, stream RaftStatusService_WatchStatusServer) error {
+	ticker := time.NewTicker(s.raft.Config().GetStatusIntervalOrDefault())
+	defer ticker.Stop()
+
+	var last *MemberStatus
+	for {
+		status := s.getStatus()
+		if last == nil || !status.Equal(last) {
+			if err := stream.Send(status); err != nil {
+				return err
+			}
+			last = status
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// getStatus returns the current status of the local member
+func (s *statusServer) getStatus() *MemberStatus {
+	s.raft.ReadLock()
+	status := &MemberStatus{
+		MemberID:        s.raft.Member(),
+		Term:            s.raft.Term(),
+		Role:            s.raft.Role(),
+		CommitIndex:     s.raft.CommitIndex(),
+		ElectionFailure: s.raft.ElectionFailure(),
+	}
+	if leader := s.raft.Leader(); leader != nil {
+		status.Leader = *leader
+	}
+	s.raft.ReadUnlock()
+	status.LastIndex = s.lastIndex()
+	status.Progress = s.raft.Progress()
+	status.ApplyRate = s.applyRate.Value()
+	status.CommitRate = s.commitRate.Value()
+	return status
+}
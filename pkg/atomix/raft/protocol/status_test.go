@@ -0,0 +1,178 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	atomix "github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// progressLeaderRole is a leader role that reports fixed replication progress
+type progressLeaderRole struct {
+	*testRole
+	progress []*MemberProgress
+}
+
+func (r *progressLeaderRole) Type() RoleType {
+	return RoleLeader
+}
+
+func (r *progressLeaderRole) Progress() []*MemberProgress {
+	return r.progress
+}
+
+// awaitStatus receives status updates from the given stream until one matches the given predicate
+func awaitStatus(t *testing.T, stream RaftStatusService_WatchStatusClient, f func(*MemberStatus) bool) *MemberStatus {
+	for {
+		status, err := stream.Recv()
+		assert.NoError(t, err)
+		if err != nil {
+			return nil
+		}
+		if f(status) {
+			return status
+		}
+	}
+}
+
+func TestWatchStatus(t *testing.T) {
+	cluster := atomix.Cluster{
+		MemberID: "foo",
+		Members: map[string]atomix.Member{
+			"foo": {
+				ID: "foo",
+			},
+			"bar": {
+				ID: "bar",
+			},
+		},
+	}
+	progress := []*MemberProgress{
+		{
+			MemberID:   "bar",
+			MatchIndex: 2,
+			NextIndex:  3,
+		},
+	}
+	roles := map[RoleType]func(Raft) Role{
+		RoleFollower: func(r Raft) Role {
+			return &followerRole{&testRole{}}
+		},
+		RoleLeader: func(r Raft) Role {
+			return &progressLeaderRole{testRole: &testRole{}, progress: progress}
+		},
+	}
+	interval := 20 * time.Millisecond
+	rateWindow := 100 * time.Millisecond
+	raft := newRaft(NewCluster(cluster), &config.ProtocolConfig{StatusInterval: &interval, RateWindow: &rateWindow}, &unimplementedClient{}, roles, newMemoryMetadataStore())
+	raft.WriteLock()
+	raft.Init()
+	raft.WriteUnlock()
+
+	var lastIndex uint64
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	RegisterRaftStatusServiceServer(server, NewStatusServer(raft, func() Index {
+		return Index(atomic.LoadUint64(&lastIndex))
+	}))
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := NewRaftStatusServiceClient(conn).WatchStatus(ctx, &WatchStatusRequest{})
+	assert.NoError(t, err)
+
+	// Verify the current status is sent immediately
+	status, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, MemberID("foo"), status.MemberID)
+	assert.Equal(t, RoleFollower, status.Role)
+	assert.Equal(t, Index(0), status.CommitIndex)
+	assert.Len(t, status.Progress, 0)
+
+	// Drive a burst of writes and verify the stream reports the latest indexes
+	for i := 1; i <= 10; i++ {
+		atomic.StoreUint64(&lastIndex, uint64(i))
+		raft.WriteLock()
+		raft.Commit(Index(i))
+		raft.WriteUnlock()
+	}
+	status = awaitStatus(t, stream, func(status *MemberStatus) bool {
+		return status.CommitIndex == 10
+	})
+	assert.Equal(t, Index(10), status.LastIndex)
+	assert.True(t, status.CommitRate > 0)
+
+	// Record a failed election and verify the stream reports the reason the member is not the leader
+	raft.WriteLock()
+	raft.SetElectionFailure(&ElectionFailure{
+		Term:       1,
+		Reason:     RejectionReason_LOG_BEHIND,
+		Rejections: 1,
+		Voters:     2,
+	})
+	raft.WriteUnlock()
+	status = awaitStatus(t, stream, func(status *MemberStatus) bool {
+		return status.ElectionFailure != nil
+	})
+	assert.Equal(t, RejectionReason_LOG_BEHIND, status.ElectionFailure.Reason)
+
+	// Change the leader and verify the stream reports the new term, role, leader, and progress once the commit rate
+	// has decayed
+	raft.WriteLock()
+	assert.NoError(t, raft.SetTerm(2))
+	leader := MemberID("foo")
+	assert.NoError(t, raft.SetLeader(&leader))
+	raft.SetRole(RoleLeader)
+	raft.WriteUnlock()
+	status = awaitStatus(t, stream, func(status *MemberStatus) bool {
+		return status.Role == RoleLeader && status.CommitRate == 0
+	})
+	assert.Equal(t, Term(2), status.Term)
+	assert.Equal(t, MemberID("foo"), status.Leader)
+	assert.Equal(t, Index(10), status.CommitIndex)
+	assert.Len(t, status.Progress, 1)
+	assert.Equal(t, MemberID("bar"), status.Progress[0].MemberID)
+	assert.Equal(t, Index(2), status.Progress[0].MatchIndex)
+	assert.Equal(t, Index(3), status.Progress[0].NextIndex)
+	assert.Nil(t, status.ElectionFailure)
+
+	// Verify unchanged status is not sent again
+	recvCh := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		recvCh <- err
+	}()
+	select {
+	case err := <-recvCh:
+		assert.Fail(t, fmt.Sprintf("unexpected status update: %v", err))
+	case <-time.After(5 * interval):
+	}
+}
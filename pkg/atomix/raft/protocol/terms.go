@@ -0,0 +1,105 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"sync"
+	"time"
+)
+
+// newTermValidatingClient returns a Client that validates the terms reported by members in responses
+func newTermValidatingClient(client Client, member MemberID) *termValidatingClient {
+	return &termValidatingClient{
+		Client: client,
+		member: member,
+		log:    util.NewNodeLogger(string(member)),
+		terms:  make(map[MemberID]*memberTerm),
+	}
+}
+
+// termValidatingClient is a Client that records the highest term reported by each member and flags members whose
+// term decreases
+// A member's term never decreases unless the member lost its persisted metadata, in which case it may vote twice in
+// the same term. Responses to concurrent requests can be received out of order, so a lower term is only flagged if
+// the request was sent after the higher term was received.
+type termValidatingClient struct {
+	Client
+	member MemberID
+	log    util.Logger
+	terms  map[MemberID]*memberTerm
+	mu     sync.Mutex
+}
+
+// memberTerm is the highest term reported by a member
+type memberTerm struct {
+	term        Term
+	received    time.Time
+	regressions *metrics.Counter
+}
+
+// observe records the term reported by the given member in response to a request sent at the given time
+// Returns false if the member's term decreased.
+func (c *termValidatingClient) observe(member MemberID, term Term, sent time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	highest, ok := c.terms[member]
+	if !ok {
+		highest = &memberTerm{
+			regressions: metrics.NewCounter("raft_member_term_regressions_total", string(member)),
+		}
+		c.terms[member] = highest
+	}
+	if term > highest.term {
+		highest.term = term
+		highest.received = time.Now()
+		return true
+	}
+	if term < highest.term && sent.After(highest.received) {
+		highest.regressions.Inc()
+		c.log.Warn("Member %s reported term %d after reporting term %d; the member may have lost its persisted metadata", member, term, highest.term)
+		return false
+	}
+	return true
+}
+
+func (c *termValidatingClient) Poll(ctx context.Context, request *PollRequest, member MemberID) (*PollResponse, error) {
+	sent := time.Now()
+	response, err := c.Client.Poll(ctx, request, member)
+	if err == nil && response.Status == ResponseStatus_OK {
+		c.observe(member, response.Term, sent)
+	}
+	return response, err
+}
+
+func (c *termValidatingClient) Vote(ctx context.Context, request *VoteRequest, member MemberID) (*VoteResponse, error) {
+	sent := time.Now()
+	response, err := c.Client.Vote(ctx, request, member)
+	if err == nil && response.Status == ResponseStatus_OK {
+		c.observe(member, response.Term, sent)
+	}
+	return response, err
+}
+
+func (c *termValidatingClient) Append(ctx context.Context, request *AppendRequest, member MemberID) (*AppendResponse, error) {
+	sent := time.Now()
+	response, err := c.Client.Append(ctx, request, member)
+	if err == nil && response.Status == ResponseStatus_OK {
+		c.observe(member, response.Term, sent)
+	}
+	return response, err
+}
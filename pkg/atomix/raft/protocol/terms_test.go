@@ -0,0 +1,72 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// appendClient is a Client that responds to append requests with the given term
+type appendClient struct {
+	Client
+	term Term
+}
+
+func (c *appendClient) Append(ctx context.Context, request *AppendRequest, member MemberID) (*AppendResponse, error) {
+	return &AppendResponse{
+		Status: ResponseStatus_OK,
+		Term:   c.term,
+	}, nil
+}
+
+func TestTermValidatingClient(t *testing.T) {
+	delegate := &appendClient{}
+	client := newTermValidatingClient(delegate, MemberID("foo"))
+	regressions := metrics.NewCounter("raft_member_term_regressions_total", "bar")
+	initialRegressions := regressions.Value()
+
+	// Verify increasing terms are recorded
+	for _, term := range []Term{1, 2, 2, 3} {
+		delegate.term = term
+		response, err := client.Append(context.Background(), &AppendRequest{}, MemberID("bar"))
+		assert.NoError(t, err)
+		assert.Equal(t, term, response.Term)
+	}
+	assert.Equal(t, initialRegressions, regressions.Value())
+
+	// Verify a lower term in response to a request sent before the higher term was received is not flagged
+	sent := time.Now()
+	time.Sleep(time.Millisecond)
+	assert.True(t, client.observe(MemberID("bar"), Term(4), time.Now()))
+	assert.True(t, client.observe(MemberID("bar"), Term(3), sent))
+	assert.Equal(t, initialRegressions, regressions.Value())
+
+	// Verify a lower term in response to a later request is flagged
+	delegate.term = 2
+	response, err := client.Append(context.Background(), &AppendRequest{}, MemberID("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, Term(2), response.Term)
+	assert.Equal(t, initialRegressions+1, regressions.Value())
+	assert.False(t, client.observe(MemberID("bar"), Term(3), time.Now()))
+	assert.Equal(t, initialRegressions+2, regressions.Value())
+
+	// Verify terms are tracked independently for each member
+	assert.True(t, client.observe(MemberID("baz"), Term(1), time.Now()))
+	assert.Equal(t, initialRegressions+2, regressions.Value())
+}
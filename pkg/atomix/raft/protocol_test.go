@@ -17,8 +17,13 @@ package raft
 import (
 	"github.com/atomix/api/proto/atomix/controller"
 	"github.com/atomix/go-framework/pkg/atomix"
+	"github.com/atomix/go-framework/pkg/atomix/cluster"
 	"github.com/atomix/go-framework/pkg/atomix/registry"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/client"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"net"
 	"testing"
 	"time"
 )
@@ -46,3 +51,59 @@ func TestProtocol(t *testing.T) {
 	time.Sleep(1 * time.Second)
 	defer node.Stop()
 }
+
+func TestProtocolReadConsistency(t *testing.T) {
+	c := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5750,
+			},
+		},
+	}
+
+	// Verify the client uses sequential reads by default
+	protocol := NewProtocol(&config.ProtocolConfig{})
+	assert.NoError(t, protocol.Start(c, registry.Registry))
+	assert.Equal(t, raft.ReadConsistency_SEQUENTIAL, protocol.Client().(*client.Client).ReadConsistency())
+	assert.NoError(t, protocol.Stop())
+
+	// Verify the client uses the configured read consistency
+	protocol = NewProtocol(&config.ProtocolConfig{ReadConsistency: config.ReadConsistency_LINEARIZABLE})
+	assert.NoError(t, protocol.Start(c, registry.Registry))
+	defer protocol.Stop()
+	assert.Equal(t, raft.ReadConsistency_LINEARIZABLE, protocol.Client().(*client.Client).ReadConsistency())
+}
+
+func TestProtocolStartupTimeout(t *testing.T) {
+	// Configure a cluster whose other members are never started
+	c := cluster.Cluster{
+		MemberID: "foo",
+		Members:  map[string]cluster.Member{},
+	}
+	for i, member := range []string{"foo", "bar", "baz"} {
+		c.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5779 + i,
+		}
+	}
+	electionTimeout := 100 * time.Millisecond
+	heartbeatInterval := 20 * time.Millisecond
+	startupTimeout := 500 * time.Millisecond
+	protocol := NewProtocol(&config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+		StartupTimeout:    &startupTimeout,
+	})
+	assert.Error(t, protocol.Start(c, registry.Registry))
+
+	// Verify the server was stopped and released its port when the protocol failed to start
+	lis, err := net.Listen("tcp", ":5779")
+	assert.NoError(t, err)
+	if lis != nil {
+		lis.Close()
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"time"
 )
 
 func newActiveRole(raft raft.Raft, state state.Manager, store store.Store, log util.Logger) *ActiveRole {
@@ -58,17 +59,87 @@ func (r *ActiveRole) Poll(ctx context.Context, request *raft.PollRequest) (*raft
 
 	// Acquire a write lock to update the leader and term.
 	r.raft.WriteLock()
+	if r.isLeadershipProtected() {
+		response := &raft.PollResponse{
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Accepted:  false,
+			Rejection: raft.RejectionReason_LEADERSHIP_PROTECTED,
+			Leader:    *r.raft.Leader(),
+		}
+		r.raft.WriteUnlock()
+		r.log.Debug("Rejected %v: the current leader is within its minimum leadership duration", request)
+		_ = r.log.Response("PollResponse", response, nil)
+		return response, nil
+	} else if leader := r.raft.Leader(); leader != nil && request.Term >= r.raft.Term() {
+		response := &raft.PollResponse{
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Accepted:  false,
+			Rejection: raft.RejectionReason_LEADER_EXISTS,
+			Leader:    *leader,
+		}
+		r.raft.WriteUnlock()
+		r.log.Debug("Rejected %v: the local member has heard from the current leader within the election timeout", request)
+		_ = r.log.Response("PollResponse", response, nil)
+		return response, nil
+	}
 	r.updateTermAndLeader(request.Term, nil)
 	r.raft.WriteUnlock()
 
 	// Acquire a read lock to vote for the follower.
+	// The known leader is reported to help non-voting members rediscover the leader.
 	r.raft.ReadLock()
 	response, err := r.handlePoll(ctx, request)
+	if leader := r.raft.Leader(); leader != nil {
+		response.Leader = *leader
+	}
 	r.raft.ReadUnlock()
 	_ = r.log.Response("PollResponse", response, err)
 	return response, err
 }
 
+// isLeadershipProtected returns a bool indicating whether the current leader was elected within the configured
+// minimum leadership duration, in which case requests to elect a new leader are ignored. The leader is only
+// protected while it's known: a heartbeat timeout clears it, so a stalled leader can still be replaced. The caller
+// must hold a lock on the Raft state.
+func (r *ActiveRole) isLeadershipProtected() bool {
+	duration := r.raft.Config().GetMinLeadershipDurationOrDefault()
+	return duration > 0 && r.raft.Leader() != nil && time.Since(r.raft.LeaderTime()) < duration
+}
+
+// protectLeadership returns a vote rejection if the current leader is within its minimum leadership duration
+// The request's term is not adopted to ensure the current leader is not disrupted. The caller must hold a
+// lock on the Raft state.
+func (r *ActiveRole) protectLeadership(request *raft.VoteRequest) *raft.VoteResponse {
+	if !r.isLeadershipProtected() {
+		return nil
+	}
+	r.log.Debug("Rejected %v: the current leader is within its minimum leadership duration", request)
+	return &raft.VoteResponse{
+		Status:    raft.ResponseStatus_OK,
+		Term:      r.raft.Term(),
+		Voted:     false,
+		Rejection: raft.RejectionReason_LEADERSHIP_PROTECTED,
+	}
+}
+
+// protectLeader returns a vote rejection if the local member has heard from the current leader within the election
+// timeout, which ensures the leader's lease expires before another leader is elected. Members the leader transferred
+// leadership to are not rejected. The caller must hold a lock on the Raft state.
+func (r *ActiveRole) protectLeader(request *raft.VoteRequest) *raft.VoteResponse {
+	if r.raft.Leader() == nil || request.Transfer || request.Term < r.raft.Term() {
+		return nil
+	}
+	r.log.Debug("Rejected %v: the local member has heard from the current leader within the election timeout", request)
+	return &raft.VoteResponse{
+		Status:    raft.ResponseStatus_OK,
+		Term:      r.raft.Term(),
+		Voted:     false,
+		Rejection: raft.RejectionReason_LEADER_EXISTS,
+	}
+}
+
 // handlePoll handles a poll request
 func (r *ActiveRole) handlePoll(ctx context.Context, request *raft.PollRequest) (*raft.PollResponse, error) {
 	// If the request term is not as great as the current context term then don't
@@ -77,9 +148,10 @@ func (r *ActiveRole) handlePoll(ctx context.Context, request *raft.PollRequest)
 	if request.Term < r.raft.Term() {
 		r.log.Debug("Rejected %v: candidate's term %d is less than the current term %d", request, request.Term, r.raft.Term())
 		return &raft.PollResponse{
-			Status:   raft.ResponseStatus_OK,
-			Term:     r.raft.Term(),
-			Accepted: false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Accepted:  false,
+			Rejection: raft.RejectionReason_TERM_BEHIND,
 		}, nil
 	} else if r.isLogUpToDate(request.LastLogIndex, request.LastLogTerm, request) {
 		return &raft.PollResponse{
@@ -89,9 +161,10 @@ func (r *ActiveRole) handlePoll(ctx context.Context, request *raft.PollRequest)
 		}, nil
 	} else {
 		return &raft.PollResponse{
-			Status:   raft.ResponseStatus_OK,
-			Term:     r.raft.Term(),
-			Accepted: false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Accepted:  false,
+			Rejection: raft.RejectionReason_LOG_BEHIND,
 		}, nil
 	}
 }
@@ -99,17 +172,17 @@ func (r *ActiveRole) handlePoll(ctx context.Context, request *raft.PollRequest)
 // isLogUpToDate returns a boolean indicating whether the log is up to date with the given index and term
 func (r *ActiveRole) isLogUpToDate(lastIndex raft.Index, lastTerm raft.Term, request interface{}) bool {
 	// Read the last entry from the log.
-	lastEntry := r.store.Writer().LastEntry()
+	localIndex, localTerm := r.lastEntry()
 
 	// If the log is empty then vote for the candidate.
-	if lastEntry == nil {
+	if localIndex == 0 {
 		r.log.Debug("Accepted %v: candidate's log is up-to-date", request)
 		return true
 	}
 
 	// If the candidate's last log term is lower than the local log's last entry term, reject the request.
-	if lastTerm < lastEntry.Entry.Term {
-		r.log.Debug("Rejected %v: candidate's last log entry (%d) is at a lower term than the local log (%d)", request, lastTerm, lastEntry.Entry.Term)
+	if lastTerm < localTerm {
+		r.log.Debug("Rejected %v: candidate's last log entry (%d) is at a lower term than the local log (%d)", request, lastTerm, localTerm)
 		return false
 	}
 
@@ -117,8 +190,8 @@ func (r *ActiveRole) isLogUpToDate(lastIndex raft.Index, lastTerm raft.Term, req
 	// candidate's last index is less than the local log's last index. If the candidate's last log term is
 	// greater than the local log's last term then it's considered up to date, and if both have the same term
 	// then the candidate's last index must be greater than the local log's last index.
-	if lastTerm == lastEntry.Entry.Term && lastIndex < lastEntry.Index {
-		r.log.Debug("Rejected %v: candidate's last log entry (%d) is at a lower index than the local log (%d)", request, lastIndex, lastEntry.Index)
+	if lastTerm == localTerm && lastIndex < localIndex {
+		r.log.Debug("Rejected %v: candidate's last log entry (%d) is at a lower index than the local log (%d)", request, lastIndex, localIndex)
 		return false
 	}
 
@@ -129,6 +202,19 @@ func (r *ActiveRole) isLogUpToDate(lastIndex raft.Index, lastTerm raft.Term, req
 	return true
 }
 
+// lastEntry returns the index and term of the last entry in the local log
+// Witnesses store no entries, so the last entry of a witness is the last entry in the leader's log it recorded.
+func (r *ActiveRole) lastEntry() (raft.Index, raft.Term) {
+	if r.isWitness() {
+		record := r.raft.WitnessRecord()
+		return record.Index, record.Term
+	}
+	if lastEntry := r.store.Writer().LastEntry(); lastEntry != nil {
+		return lastEntry.Index, lastEntry.Entry.Term
+	}
+	return 0, 0
+}
+
 // Vote handles a vote request
 func (r *ActiveRole) Vote(ctx context.Context, request *raft.VoteRequest) (*raft.VoteResponse, error) {
 	r.log.Request("VoteRequest", request)
@@ -137,6 +223,15 @@ func (r *ActiveRole) Vote(ctx context.Context, request *raft.VoteRequest) (*raft
 	r.raft.WriteLock()
 	defer r.raft.WriteUnlock()
 
+	if response := r.protectLeadership(request); response != nil {
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+	if response := r.protectLeader(request); response != nil {
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+
 	// If the request indicates a term that is greater than the current term then
 	// assign that term and leader to the current context.
 	if r.updateTermAndLeader(request.Term, nil) {
@@ -156,26 +251,29 @@ func (r *ActiveRole) handleVote(ctx context.Context, request *raft.VoteRequest)
 		// as up to date as us.
 		r.log.Debug("Rejected %+v: candidate's term is less than the current term", request)
 		return &raft.VoteResponse{
-			Status: raft.ResponseStatus_OK,
-			Term:   r.raft.Term(),
-			Voted:  false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_TERM_BEHIND,
 		}, nil
 	} else if r.raft.Leader() != nil {
 		// If a leader was already determined for this term then reject the request.
 		r.log.Debug("Rejected %+v: leader already exists", request)
 		return &raft.VoteResponse{
-			Status: raft.ResponseStatus_OK,
-			Term:   r.raft.Term(),
-			Voted:  false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_LEADER_EXISTS,
 		}, nil
 	} else if r.raft.GetMember(request.Candidate) == nil {
 		// If the requesting candidate is not a known member of the cluster (to this
 		// node) then don't vote for it. Only vote for candidates that we know about.
 		r.log.Debug("Rejected %+v: candidate is not known to the local member", request)
 		return &raft.VoteResponse{
-			Status: raft.ResponseStatus_OK,
-			Term:   r.raft.Term(),
-			Voted:  false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_UNKNOWN_CANDIDATE,
 		}, nil
 	} else if r.raft.LastVotedFor() == nil {
 		// If no vote has been cast, check the log and cast a vote if necessary.
@@ -195,9 +293,10 @@ func (r *ActiveRole) handleVote(ctx context.Context, request *raft.VoteRequest)
 			}, nil
 		}
 		return &raft.VoteResponse{
-			Status: raft.ResponseStatus_OK,
-			Term:   r.raft.Term(),
-			Voted:  false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_LOG_BEHIND,
 		}, nil
 	} else if *r.raft.LastVotedFor() == request.Candidate {
 		// If we already voted for the requesting server, respond successfully.
@@ -211,9 +310,10 @@ func (r *ActiveRole) handleVote(ctx context.Context, request *raft.VoteRequest)
 		// In this case, we've already voted for someone else.
 		r.log.Debug("Rejected %+v: already voted for %+v", request, r.raft.LastVotedFor())
 		return &raft.VoteResponse{
-			Status: raft.ResponseStatus_OK,
-			Term:   r.raft.Term(),
-			Voted:  false,
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_ALREADY_VOTED,
 		}, nil
 	}
 }
@@ -98,9 +98,25 @@ func TestActivePoll(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Accepted)
+	assert.Equal(t, raft.RejectionReason_TERM_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
+	// Test that the poll is rejected while the node has heard from a leader
+	response, err = role.Poll(context.TODO(), &raft.PollRequest{
+		Term:         3,
+		Candidate:    "baz",
+		LastLogIndex: 10,
+		LastLogTerm:  2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.False(t, response.Accepted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, response.Rejection)
+	assert.Equal(t, bar, response.Leader)
+	assert.Equal(t, raft.Term(2), role.raft.Term())
+
 	// Test that the node votes if there are no entries in its log
+	assert.NoError(t, role.raft.SetLeader(nil))
 	response, err = role.Poll(context.TODO(), &raft.PollRequest{
 		Term:         2,
 		Candidate:    "baz",
@@ -146,6 +162,7 @@ func TestActivePoll(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Accepted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
 	// Test that the poll is rejected if the candidate's last index is less than the local log's last index in the same term
@@ -158,6 +175,7 @@ func TestActivePoll(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Accepted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
 	// Test that the poll is accepted if the candidate's last index is greater than the local log's last index in an equal or greater term
@@ -209,6 +227,7 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_TERM_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
 	// Test that the node rejects the vote request if it already has a leader for the requested term
@@ -221,9 +240,26 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, response.Rejection)
+	assert.Equal(t, raft.Term(2), response.Term)
+
+	// Test that the node rejects the vote request for a greater term while it has heard from a leader
+	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         3,
+		Candidate:    "baz",
+		LastLogIndex: 10,
+		LastLogTerm:  2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
+	assert.Equal(t, raft.Term(2), role.raft.Term())
+	assert.Equal(t, bar, *role.raft.Leader())
 
 	// Test that the node rejects the vote request if it's from an unknown member
+	assert.NoError(t, role.raft.SetLeader(nil))
 	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
 		Term:         2,
 		Candidate:    "none",
@@ -233,6 +269,7 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_UNKNOWN_CANDIDATE, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
 	// Test that the node votes if there are no entries in its log
@@ -252,7 +289,6 @@ func TestActiveVote(t *testing.T) {
 
 	// Reset the server state
 	assert.NoError(t, role.raft.SetTerm(3))
-	assert.NoError(t, role.raft.SetLeader(&bar))
 
 	// Test that the request is rejected if the LastLogTerm is lower than the log's last entry term
 	role.store.Writer().Append(&raft.LogEntry{
@@ -288,6 +324,7 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(4), response.Term)
 	assert.Equal(t, raft.Term(4), role.raft.Term())
 	assert.Nil(t, role.raft.Leader())
@@ -303,6 +340,7 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(4), response.Term)
 	assert.Equal(t, raft.Term(4), role.raft.Term())
 	assert.Nil(t, role.raft.Leader())
@@ -347,6 +385,7 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_ALREADY_VOTED, response.Rejection)
 
 	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
 		Term:         5,
@@ -357,4 +396,5 @@ func TestActiveVote(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_ALREADY_VOTED, response.Rejection)
 }
@@ -17,17 +17,21 @@ package roles
 import (
 	"container/list"
 	"context"
-	"errors"
+	"fmt"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"hash/crc32"
 	"io"
 	"math"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,28 +39,67 @@ import (
 func newAppender(state raft.Raft, sm state.Manager, store store.Store, log util.Logger) *raftAppender {
 	commitCh := make(chan memberCommit)
 	failCh := make(chan time.Time)
-	members := make(map[raft.MemberID]*memberAppender)
-	for _, memberID := range state.Members() {
-		if memberID != state.Member() {
-			members[memberID] = newMemberAppender(state, sm, store, log, state.GetMember(memberID), commitCh, failCh)
-		}
+	if maxEntrySize := state.Config().GetMaxEntrySizeOrDefault(); maxEntrySize > maxBatchSize {
+		log.Warn("Maximum entry size %d exceeds the maximum append batch size %d; large entries will be replicated in oversized batches", maxEntrySize, maxBatchSize)
+	}
+	// If a replication budget is configured, installs and catch-up appends to all members share a bounded set of
+	// workers so a leader catching up many lagging members at once doesn't overwhelm the scheduler. Members that are
+	// caught up are exempt from the budget so they keep receiving heartbeats while lagging members saturate it.
+	// The budget bounds only the workers sending appends and installs to lagging members, including workers abandoned
+	// by the append watchdog until they return. It doesn't bound each member's own goroutine, the workers sending to
+	// caught-up members, or the readers building each batch. Readers aren't drawn from the budget because a budgeted
+	// append waits for its readers, which could otherwise wait for the place held by their own append.
+	var budget *workerBudget
+	if maxWorkers := state.Config().GetMaxReplicationWorkers(); maxWorkers > 0 {
+		budget = newWorkerBudget(int(maxWorkers))
 	}
 	appender := &raftAppender{
 		raft:             state,
 		sm:               sm,
 		store:            store,
 		log:              log,
-		members:          members,
+		members:          make(map[raft.MemberID]*memberAppender),
+		budget:           budget,
 		commitIndexes:    make(map[raft.MemberID]raft.Index),
 		commitTimes:      make(map[raft.MemberID]time.Time),
 		heartbeatFutures: list.New(),
-		commitChannels:   make(map[raft.Index]chan bool),
+		commitChannels:   make(map[raft.Index]chan error),
 		commitFutures:    make(map[raft.Index]func()),
 		commitCh:         commitCh,
 		failCh:           failCh,
 		lastQuorumTime:   time.Now(),
+		lease:            newLeaderLease(leaseDuration(state.Config().GetElectionTimeoutOrDefault())),
+		healthTicker:     time.NewTicker(state.Config().GetQuorumHealthIntervalOrDefault()),
+		responsive:       metrics.NewGauge("raft_responsive_members", string(state.Member())),
+		quorumAvailable:  metrics.NewGauge("raft_quorum_available", string(state.Member())),
+		stallThreshold:   state.Config().GetCommitStallThresholdOrDefault(),
+		lastCommitTime:   time.Now().UnixNano(),
+		stalls:           metrics.NewCounter("raft_commit_stalls_total", string(state.Member())),
+		stalled:          metrics.NewGauge("raft_commit_stalled", string(state.Member())),
+		heartbeatDepth:   metrics.NewGauge("raft_pending_heartbeats", string(state.Member())),
 		stopped:          make(chan bool),
 	}
+
+	// The leader replicates to the other voters, and to any non-voting members not reached through relays.
+	committed := state.Configuration()
+	for _, memberID := range committed.ReplicationTargets(state.Member(), state.Member(), int(state.Config().GetRelayFanout())) {
+		member := state.GetMember(memberID)
+		appender.members[memberID] = appender.newMember(member)
+		if member.Type == raft.Member_ACTIVE || member.Type == raft.Member_WITNESS {
+			appender.voters++
+		}
+	}
+
+	// If stall detection is enabled, check for a stalled commit index several times per threshold.
+	if appender.stallThreshold > 0 {
+		appender.stallTicker = time.NewTicker(appender.stallThreshold / stallChecksPerThreshold)
+		appender.stallCh = appender.stallTicker.C
+	}
+
+	// If an apply queue is configured, apply committed entries on a dedicated goroutine.
+	if size := state.Config().GetApplyQueueSize(); size > 0 {
+		appender.applyQueue = newApplyQueue(int(size), state.Member())
+	}
 	return appender
 }
 
@@ -67,30 +110,203 @@ type raftAppender struct {
 	store            store.Store
 	log              util.Logger
 	members          map[raft.MemberID]*memberAppender
+	budget           *workerBudget
+	voters           int
 	commitIndexes    map[raft.MemberID]raft.Index
 	commitTimes      map[raft.MemberID]time.Time
 	heartbeatFutures *list.List
-	commitChannels   map[raft.Index]chan bool
+	commitChannels   map[raft.Index]chan error
 	commitFutures    map[raft.Index]func()
+	applyQueue       *applyQueue
 	commitCh         chan memberCommit
 	failCh           chan time.Time
 	stopped          chan bool
+	closed           bool
+	stopErr          error
+	drained          chan struct{}
 	lastQuorumTime   time.Time
+	lease            *leaderLease
+	healthTicker     *time.Ticker
+	health           atomic.Value
+	responsive       *metrics.Gauge
+	quorumAvailable  *metrics.Gauge
+	stallThreshold   time.Duration
+	stallTicker      *time.Ticker
+	stallCh          <-chan time.Time
+	lastCommitTime   int64
+	stalls           *metrics.Counter
+	stalled          *metrics.Gauge
+	heartbeatDepth   *metrics.Gauge
 	mu               sync.Mutex
 }
 
+// stallChecksPerThreshold is the number of times the commit index is checked for stalls per stall threshold
+const stallChecksPerThreshold = 4
+
+// quorumHealth is the result of a periodic quorum health check
+type quorumHealth struct {
+	// responsive is the number of responsive members, including the leader
+	responsive int
+	// members is the total number of members in the cluster
+	members int
+}
+
+// available returns a bool indicating whether a quorum of the cluster is responsive
+func (h quorumHealth) available() bool {
+	return h.responsive >= h.members/2+1
+}
+
+// degraded returns a bool indicating whether any member of the cluster is unresponsive
+func (h quorumHealth) degraded() bool {
+	return h.responsive < h.members
+}
+
+// getMembers returns the appenders of the members to which the leader replicates
+// The members are read from other goroutines, e.g. when reporting progress, so they're guarded by the appender lock.
+func (a *raftAppender) getMembers() map[raft.MemberID]*memberAppender {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.members
+}
+
+// newMember returns a new appender for the given member
+func (a *raftAppender) newMember(member *raft.Member) *memberAppender {
+	appender := newMemberAppender(a.raft, a.sm, a.store, a.log, member, a.commitCh, a.failCh)
+	if a.budget != nil {
+		appender.workers = newBudgetedWorkerPool(a.raft.Config().GetMaxAppendWorkersOrDefault(), a.budget)
+	}
+	appender.fallback = a.fallback
+	appender.needed = a.quorumNeeds
+	return appender
+}
+
+// quorumNeeds returns a bool indicating whether a quorum of voters can't be formed without the given member
+// The leader and the other voters that have responded within the election timeout and aren't stabilizing after
+// flapping count toward the quorum.
+func (a *raftAppender) quorumNeeds(member *memberAppender) bool {
+	if !member.voting() {
+		return false
+	}
+	now := time.Now()
+	electionTimeout := a.raft.Config().GetElectionTimeoutOrDefault()
+	available := 1
+	for _, other := range a.getMembers() {
+		if other == member || !other.voting() {
+			continue
+		}
+		if now.Sub(other.getLastResponseTime()) < electionTimeout && !other.isStabilizing(now) {
+			available++
+		}
+	}
+	return available < (a.voters+1)/2+1
+}
+
+// fallback replicates directly to the given members, which their relay can't catch up
+// A relay only relays entries from its own log, so the leader takes over replication to downstream members that need
+// entries compacted from the relay's log, installing a snapshot if the member needs one. The members are replicated
+// to directly until the leader steps down.
+func (a *raftAppender) fallback(members []raft.MemberID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+	var next map[raft.MemberID]*memberAppender
+	for _, memberID := range members {
+		if _, ok := a.members[memberID]; ok {
+			continue
+		}
+		member := a.raft.GetMember(memberID)
+		if member == nil {
+			continue
+		}
+
+		// The members are read without holding the lock once they're returned by getMembers, so the map is
+		// copied rather than modified.
+		if next == nil {
+			next = make(map[raft.MemberID]*memberAppender, len(a.members)+1)
+			for id, appender := range a.members {
+				next[id] = appender
+			}
+		}
+		a.log.Info("Replicating to %s in place of its relay", memberID)
+		appender := a.newMember(member)
+		next[memberID] = appender
+		go appender.start()
+	}
+	if next != nil {
+		a.members = next
+	}
+}
+
 // start starts the appender
 func (a *raftAppender) start() {
-	for _, member := range a.members {
+	for _, member := range a.getMembers() {
 		go member.start()
 	}
 	a.processCommits()
 }
 
+// setElectionTimeout updates the lease duration and member heartbeat intervals derived from the election timeout
+func (a *raftAppender) setElectionTimeout(timeout time.Duration) {
+	a.lease.reset(leaseDuration(timeout))
+	for _, member := range a.getMembers() {
+		member.setElectionTimeout(timeout)
+	}
+}
+
+// leaseValid returns a bool indicating whether the leader holds a valid leadership lease
+func (a *raftAppender) leaseValid() bool {
+	return a.voters == 0 || a.lease.valid(time.Now())
+}
+
+// acknowledged returns a bool indicating whether the given member has accepted the leader for its term
+func (a *raftAppender) acknowledged(member raft.MemberID) bool {
+	appender, ok := a.getMembers()[member]
+	return ok && atomic.LoadInt32(&appender.acknowledged) == 1
+}
+
+// probe signals that the given member has been heard from, probing it immediately if replication to it has failed
+// Members that fail to respond to appends are backed off. If probing on recovery is enabled, a request received from
+// the member indicates it may have recovered, so the member is sent an append without waiting for the backoff.
+func (a *raftAppender) probe(member raft.MemberID) {
+	if !a.raft.Config().GetProbeOnRecovery() {
+		return
+	}
+	if appender, ok := a.getMembers()[member]; ok {
+		appender.probe()
+	}
+}
+
+// reconnect requests that the given member be sent an append immediately, regardless of any backoff
+// Unlike probes, reconnects are sent whether or not probing on recovery is enabled.
+func (a *raftAppender) reconnect(member raft.MemberID) {
+	if appender, ok := a.getMembers()[member]; ok {
+		appender.probe()
+	}
+}
+
+// forceInstall marks the given member to be caught up by installing the current snapshot
+// The member is sent the snapshot on its next append in place of entries, even if it could be caught up from the log.
+func (a *raftAppender) forceInstall(member raft.MemberID) error {
+	appender, ok := a.getMembers()[member]
+	if !ok {
+		return fmt.Errorf("unknown member %s", member)
+	}
+	if appender.witness() {
+		return fmt.Errorf("member %s is a witness and stores no snapshots", member)
+	}
+	atomic.StoreInt32(&appender.forcingInstall, 1)
+	appender.probe()
+	return nil
+}
+
 // heartbeat sends a heartbeat to a majority of followers
+// If the configured maximum number of heartbeats are already awaiting confirmation by a quorum, the heartbeat is
+// rejected with ErrOverloaded rather than queueing further reads behind them.
 func (a *raftAppender) heartbeat() error {
-	// If there are no members to send the entry to, immediately return.
-	if len(a.members) == 0 {
+	// If there are no voters to send the heartbeat to, immediately return.
+	if a.voters == 0 {
 		return nil
 	}
 
@@ -98,54 +314,127 @@ func (a *raftAppender) heartbeat() error {
 
 	// Acquire a lock to add the future to the heartbeat futures.
 	a.mu.Lock()
+	if maxPending := int(a.raft.Config().GetMaxPendingHeartbeats()); maxPending > 0 && a.heartbeatFutures.Len() >= maxPending {
+		a.mu.Unlock()
+		a.log.Debug("Rejected heartbeat: the maximum of %d heartbeats are awaiting a quorum", maxPending)
+		return raft.ErrOverloaded
+	}
 	a.heartbeatFutures.PushBack(future)
+	a.heartbeatDepth.Set(int64(a.heartbeatFutures.Len()))
 	a.mu.Unlock()
 
 	// Iterate through member appenders and add the future time to the heartbeat channels.
-	for _, member := range a.members {
-		member.heartbeatCh <- future.time
+	for _, member := range a.getMembers() {
+		select {
+		case member.heartbeatCh <- future.time:
+		case <-member.stopped:
+		}
 	}
 	_, ok := <-future.ch
 	if ok {
 		return nil
 	}
-	return errors.New("failed to verify quorum")
+
+	// If the appender was stopped, return the reason for stopping it.
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopErr != nil {
+		return a.stopErr
+	}
+	return raft.ErrQuorumLost
+}
+
+// checkLeadership returns an ErrNotLeader if the local member is not the leader for the term of the given entry
+// The leader may step down between appending an entry and committing it, in which case the entry must not be
+// committed by the former leader. The caller must hold a lock on the Raft state.
+func (a *raftAppender) checkLeadership(entry *log.Entry) error {
+	leader := a.raft.Leader()
+	if leader != nil && *leader == a.raft.Member() && entry.Entry.Term == a.raft.Term() {
+		return nil
+	}
+	err := &raft.ErrNotLeader{}
+	if leader != nil && *leader != a.raft.Member() {
+		err.Leader = *leader
+	}
+	a.log.Debug("Rejected commit of entry %d: the local member is not the leader for term %d", entry.Index, entry.Entry.Term)
+	return err
 }
 
 // commit replicates the given entry to followers and returns once the entry is committed
+// If the local member is not the leader in the entry's term, an ErrNotLeader is returned and the entry is not committed.
 func (a *raftAppender) commit(entry *log.Entry, f func()) error {
-	// If there are no members to send the entry to, immediately commit it.
-	if len(a.members) == 0 {
+	// If there are no voters to send the entry to, immediately commit it.
+	// The entry is applied with the appender locked so it can't be pushed to the apply queue once it's closed.
+	if a.voters == 0 {
 		a.raft.WriteLock()
+		if err := a.checkLeadership(entry); err != nil {
+			a.raft.WriteUnlock()
+			return err
+		}
+		a.mu.Lock()
+		if a.closed {
+			a.mu.Unlock()
+			a.raft.WriteUnlock()
+			return a.stopErr
+		}
 		a.raft.SetCommitIndex(entry.Index)
 		a.raft.Commit(entry.Index)
 		if f != nil {
-			f()
+			a.apply(f)
 		}
+		a.mu.Unlock()
 		a.raft.WriteUnlock()
+		a.push(entry)
 		return nil
 	}
 
+	a.raft.ReadLock()
+	if err := a.checkLeadership(entry); err != nil {
+		a.raft.ReadUnlock()
+		return err
+	}
+
 	// Acquire a write lock on the appender and add the channel to commitFutures.
 	a.mu.Lock()
-	ch := make(chan bool)
+	if a.closed {
+		a.mu.Unlock()
+		a.raft.ReadUnlock()
+		return a.stopErr
+	}
+
+	// The entry is appended to the log before it's registered here, so member appenders reading from the log may
+	// replicate and commit it first. Commits are made with a write lock on the Raft state, so the commit index can't
+	// advance while it's checked here. If the entry was already committed, complete it immediately.
+	if a.raft.CommitIndex() >= entry.Index {
+		if f != nil {
+			a.apply(f)
+		}
+		a.mu.Unlock()
+		a.raft.ReadUnlock()
+		a.log.Debug("Entry %d was committed before its commit was registered", entry.Index)
+		return nil
+	}
+	ch := make(chan error, 1)
 	a.commitChannels[entry.Index] = ch
 	if f != nil {
 		a.commitFutures[entry.Index] = f
 	}
 	a.mu.Unlock()
+	a.raft.ReadUnlock()
 
-	// Push the entry onto the channel for each member appender
-	for _, member := range a.members {
-		member.entryCh <- entry
-	}
+	// Push the entry to the member appenders and wait for the commit channel.
+	a.push(entry)
+	return <-ch
+}
 
-	// Wait for the commit channel.
-	succeeded, ok := <-ch
-	if ok && succeeded {
-		return nil
+// push pushes the given entry onto the channel for each member appender
+func (a *raftAppender) push(entry *log.Entry) {
+	for _, member := range a.getMembers() {
+		select {
+		case member.entryCh <- entry:
+		case <-member.stopped:
+		}
 	}
-	return errors.New("failed to commit entry")
 }
 
 // processCommits handles member commit events and updates the local commit index
@@ -156,26 +445,36 @@ func (a *raftAppender) processCommits() {
 			a.commitMember(commit.member, commit.index, commit.time)
 		case failTime := <-a.failCh:
 			a.failTime(failTime)
+		case checkTime := <-a.healthTicker.C:
+			a.checkQuorumHealth(checkTime)
+		case checkTime := <-a.stallCh:
+			a.checkCommitStall(checkTime)
 		case <-a.stopped:
 			return
 		}
 	}
 }
 
+// commitMember records the match index and response time of a member
+// Only voters count toward the quorum, so commits from non-voting members are ignored.
 func (a *raftAppender) commitMember(member *memberAppender, index raft.Index, time time.Time) {
-	if !member.active {
+	if atomic.LoadInt32(&member.active) == 0 || !member.voting() {
 		return
 	}
 	a.commitMemberIndex(member.member.MemberID, index)
 	a.commitMemberTime(member.member.MemberID, time)
 }
 
+// commitMemberIndex records the match index of a voter and commits the entries stored by a quorum
+// The leader stores every entry, so an entry is committed once a majority of the other voters have also stored it.
+// Voters that have never responded, e.g. members added since the leader was elected, are counted at index 0, so
+// they delay a commit only when the established voters can't form a quorum without them.
 func (a *raftAppender) commitMemberIndex(member raft.MemberID, index raft.Index) {
 	prevIndex := a.commitIndexes[member]
 	if index > prevIndex {
 		a.commitIndexes[member] = index
 
-		indexes := make([]raft.Index, len(a.members))
+		indexes := make([]raft.Index, a.voters)
 		i := 0
 		for _, index := range a.commitIndexes {
 			indexes[i] = index
@@ -185,17 +484,43 @@ func (a *raftAppender) commitMemberIndex(member raft.MemberID, index raft.Index)
 			return indexes[i] < indexes[j]
 		})
 
-		commitIndex := indexes[len(a.members)/2]
+		commitIndex := indexes[a.voters/2]
 		a.raft.ReadLock()
 		if commitIndex > a.raft.CommitIndex() {
 			a.raft.ReadUnlock()
 			a.raft.WriteLock()
 			if commitIndex > a.raft.CommitIndex() {
-				for i := a.raft.CommitIndex() + 1; i <= commitIndex; i++ {
-					a.commitIndex(i)
+				// Commit entries in batches, releasing the lock between batches so a large commit, e.g. after
+				// a partition heals, doesn't block readers until every entry has been committed.
+				batchSize := raft.Index(a.raft.Config().GetMaxCommitBatchSizeOrDefault())
+				for {
+					batchIndex := a.raft.CommitIndex() + batchSize
+					if batchIndex > commitIndex {
+						batchIndex = commitIndex
+					}
+					for i := a.raft.CommitIndex() + 1; i <= batchIndex; i++ {
+						a.commitIndex(i)
+					}
+					if a.raft.CommitIndex() >= commitIndex {
+						break
+					}
+					a.raft.WriteUnlock()
+					runtime.Gosched()
+					a.raft.WriteLock()
+
+					// If the leader was stopped while the lock was released, its pending commits have already
+					// been failed, so the remaining commit channels and futures must not be completed.
+					a.mu.Lock()
+					closed := a.closed
+					a.mu.Unlock()
+					if closed {
+						a.log.Debug("Stopped committing entries at %d: the appender was stopped", a.raft.CommitIndex())
+						break
+					}
 				}
+				committed := a.raft.CommitIndex()
 				a.raft.WriteUnlock()
-				a.log.Trace("Committed entries up to %d", commitIndex)
+				a.log.Trace("Committed entries up to %d", committed)
 			} else {
 				a.raft.WriteUnlock()
 			}
@@ -210,28 +535,108 @@ func (a *raftAppender) commitIndex(index raft.Index) {
 	a.raft.SetCommitIndex(index)
 	a.raft.Commit(index)
 
+	// Any advance of the commit index clears a stall.
+	atomic.StoreInt64(&a.lastCommitTime, time.Now().UnixNano())
+	if a.raft.CommitStalled() {
+		a.log.Info("Commit index resumed advancing at %d", index)
+		a.stalled.Set(0)
+		a.raft.SetCommitStalled(false)
+	}
+
 	// Acquire a lock on the appender and complete the commit channels and futures.
 	a.mu.Lock()
 	ch, ok := a.commitChannels[index]
 	if ok {
-		ch <- true
+		ch <- nil
 		delete(a.commitChannels, index)
 	}
 	f, ok := a.commitFutures[index]
 	if ok {
-		f()
+		a.apply(f)
 		delete(a.commitFutures, index)
 	}
+	if a.drained != nil && len(a.commitChannels) == 0 {
+		close(a.drained)
+		a.drained = nil
+	}
 	a.mu.Unlock()
 }
 
-func (a *raftAppender) commitMemberTime(member raft.MemberID, time time.Time) {
+// apply calls the given function to apply a committed entry, enqueueing it if an apply queue is configured
+func (a *raftAppender) apply(f func()) {
+	if a.applyQueue != nil {
+		a.applyQueue.push(f)
+	} else {
+		f()
+	}
+}
+
+// reserveApply blocks until the apply queue has room for another entry or the context is done
+// Proposers must reserve room for an entry before appending it to the log, and release the reservation by calling
+// releaseApply once the entry has been applied or has failed to commit. If no apply queue is configured, reserveApply
+// returns immediately. If the context is done before room is available, raft.ErrOverloaded is returned.
+func (a *raftAppender) reserveApply(ctx context.Context) error {
+	if a.applyQueue == nil || a.applyQueue.reserve(ctx) {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return raft.ErrOverloaded
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stopErr
+}
+
+// releaseApply releases a reservation made by reserveApply
+func (a *raftAppender) releaseApply() {
+	if a.applyQueue != nil {
+		a.applyQueue.release()
+	}
+}
+
+// progress returns the replication progress of each member, sorted by member ID
+func (a *raftAppender) progress() []*raft.MemberProgress {
+	members := a.getMembers()
+	progress := make([]*raft.MemberProgress, 0, len(members))
+	for _, member := range members {
+		progress = append(progress, member.getProgress())
+	}
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].MemberID < progress[j].MemberID
+	})
+	return progress
+}
+
+// drain waits up to the given timeout for pending commits to complete
+// It returns a bool indicating whether all pending commits completed.
+func (a *raftAppender) drain(timeout time.Duration) bool {
+	a.mu.Lock()
+	if len(a.commitChannels) == 0 {
+		a.mu.Unlock()
+		return true
+	}
+	if a.drained == nil {
+		a.drained = make(chan struct{})
+	}
+	drained := a.drained
+	a.mu.Unlock()
+
+	a.log.Debug("Draining pending commits")
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (a *raftAppender) commitMemberTime(member raft.MemberID, memberTime time.Time) {
 	prevTime := a.commitTimes[member]
-	nextTime := time
+	nextTime := memberTime
 	if nextTime.UnixNano() > prevTime.UnixNano() {
 		a.commitTimes[member] = nextTime
 
-		times := make([]int64, len(a.members))
+		times := make([]int64, a.voters)
 		i := 0
 		for _, t := range a.commitTimes {
 			times[i] = t.UnixNano()
@@ -241,7 +646,7 @@ func (a *raftAppender) commitMemberTime(member raft.MemberID, time time.Time) {
 			return times[i] < times[j]
 		})
 
-		commitTime := times[len(a.members)/2]
+		commitTime := times[a.voters/2]
 		a.mu.Lock()
 		for commitFuture := a.heartbeatFutures.Front(); commitFuture != nil && commitFuture.Value.(heartbeatFuture).time.UnixNano() < commitTime; commitFuture = a.heartbeatFutures.Front() {
 			ch := commitFuture.Value.(heartbeatFuture).ch
@@ -249,16 +654,22 @@ func (a *raftAppender) commitMemberTime(member raft.MemberID, time time.Time) {
 			close(ch)
 			a.heartbeatFutures.Remove(commitFuture)
 		}
+		a.heartbeatDepth.Set(int64(a.heartbeatFutures.Len()))
 		a.mu.Unlock()
 
+		// Renew the leadership lease from the time the requests acknowledged by a quorum were sent
+		a.lease.renew(time.Unix(0, commitTime))
+
 		// Update the last time a quorum of the cluster was reached
-		a.lastQuorumTime = time
+		a.lastQuorumTime = memberTime
 	}
 }
 
 func (a *raftAppender) failTime(failTime time.Time) {
 	if failTime.Sub(a.lastQuorumTime) > a.raft.Config().GetElectionTimeoutOrDefault()*2 {
 		a.log.Warn("Suspected network partition; stepping down")
+		a.lease.expire()
+		a.failPending(raft.ErrQuorumLost)
 		_ = a.raft.SetLeader(nil)
 		a.raft.WriteLock()
 		defer a.raft.WriteUnlock()
@@ -266,10 +677,113 @@ func (a *raftAppender) failTime(failTime time.Time) {
 	}
 }
 
-func (a *raftAppender) stop() {
+// checkQuorumHealth reports whether a quorum of the cluster has responded within the election timeout
+// The check relies only on the last time each member responded to an append request, so it reflects the
+// health of the cluster even when no writes are being replicated.
+func (a *raftAppender) checkQuorumHealth(checkTime time.Time) {
+	electionTimeout := a.raft.Config().GetElectionTimeoutOrDefault()
+	health := quorumHealth{
+		responsive: 1,
+		members:    a.voters + 1,
+	}
+	for _, member := range a.getMembers() {
+		if !member.voting() {
+			continue
+		}
+		if checkTime.Sub(member.getLastResponseTime()) < electionTimeout {
+			health.responsive++
+		}
+	}
+
+	a.responsive.Set(int64(health.responsive))
+	if health.available() {
+		a.quorumAvailable.Set(1)
+	} else {
+		a.quorumAvailable.Set(0)
+	}
+
+	if !health.available() {
+		a.log.Warn("Quorum unavailable: %d of %d members responsive", health.responsive, health.members)
+	} else if health.degraded() {
+		a.log.Warn("Quorum degraded: %d of %d members responsive", health.responsive, health.members)
+	} else {
+		a.log.Debug("Quorum healthy: %d of %d members responsive", health.responsive, health.members)
+	}
+
+	a.health.Store(health)
+}
+
+// checkCommitStall reports the commit index stalled if proposals have been pending without the commit index
+// advancing for the stall threshold
+// The stall duration is measured from the later of the last advance of the commit index and the last check that
+// found no proposals pending.
+func (a *raftAppender) checkCommitStall(checkTime time.Time) {
+	a.mu.Lock()
+	pending := len(a.commitChannels)
+	a.mu.Unlock()
+	if pending == 0 {
+		atomic.StoreInt64(&a.lastCommitTime, checkTime.UnixNano())
+		return
+	}
+
+	a.raft.WriteLock()
+	defer a.raft.WriteUnlock()
+	stalledFor := checkTime.Sub(time.Unix(0, atomic.LoadInt64(&a.lastCommitTime)))
+	if stalledFor >= a.stallThreshold && !a.raft.CommitStalled() {
+		a.log.Warn("Commit index %d has not advanced in %s with %d proposals pending", a.raft.CommitIndex(), stalledFor, pending)
+		a.stalls.Inc()
+		a.stalled.Set(1)
+		a.raft.SetCommitStalled(true)
+	}
+}
+
+// getQuorumHealth returns the result of the last quorum health check, or nil if no check has completed
+func (a *raftAppender) getQuorumHealth() *quorumHealth {
+	health, ok := a.health.Load().(quorumHealth)
+	if !ok {
+		return nil
+	}
+	return &health
+}
+
+// failPending completes all pending commits and heartbeats with the given error
+func (a *raftAppender) failPending(err error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	for _, member := range a.members {
+	for index, ch := range a.commitChannels {
+		ch <- err
+		delete(a.commitChannels, index)
+		delete(a.commitFutures, index)
+	}
+	for future := a.heartbeatFutures.Front(); future != nil; future = a.heartbeatFutures.Front() {
+		close(future.Value.(heartbeatFuture).ch)
+		a.heartbeatFutures.Remove(future)
+	}
+	a.heartbeatDepth.Set(0)
+}
+
+// stop stops the appender, failing pending commits and heartbeats with the given error
+func (a *raftAppender) stop(err error) {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	a.closed = true
+	a.stopErr = err
+	a.mu.Unlock()
+	a.lease.expire()
+	a.healthTicker.Stop()
+	if a.stallTicker != nil {
+		a.stallTicker.Stop()
+	}
+	a.stalled.Set(0)
+	a.failPending(err)
+	if a.applyQueue != nil {
+		a.applyQueue.close()
+	}
+
+	for _, member := range a.getMembers() {
 		member.stop()
 	}
 	a.stopped <- true
@@ -300,29 +814,81 @@ const (
 	minBackoffFailureCount = 3
 	maxHeartbeatWait       = 1 * time.Minute
 	maxBatchSize           = 1024 * 1024
+
+	// parallelReadThreshold is the minimum number of entries a member must be behind for batch reads to be parallelized
+	parallelReadThreshold = 1024
+	// maxParallelReads is the maximum number of log readers used concurrently to build a batch
+	maxParallelReads = 4
+	// parallelReadChunkSize is the number of entries read by each log reader per round
+	parallelReadChunkSize = 256
 )
 
+// tickInterval returns the interval at which a member is sent a heartbeat if idle for the given election timeout
+func tickInterval(electionTimeout time.Duration) time.Duration {
+	return electionTimeout / 2
+}
+
+// appendWatchdogSlack is the multiple of the append or install RPC deadline after which an append is considered stuck
+const appendWatchdogSlack = 2
+
+// maxInstallRestarts is the maximum number of consecutive times an install is restarted to send a newer snapshot
+const maxInstallRestarts = 2
+
+// batchEntriesBounds are the bucket bounds of the histogram of entries per append batch
+var batchEntriesBounds = metrics.ExponentialBounds(1, 4, 7)
+
+// batchBytesBounds are the bucket bounds of the histogram of bytes per append batch
+var batchBytesBounds = metrics.ExponentialBounds(64, 4, 9)
+
 func newMemberAppender(state raft.Raft, sm state.Manager, store store.Store, logger util.Logger, member *raft.Member, commitCh chan<- memberCommit, failCh chan<- time.Time) *memberAppender {
-	ticker := time.NewTicker(state.Config().GetElectionTimeoutOrDefault() / 2)
+	ticker := time.NewTicker(tickInterval(state.Config().GetElectionTimeoutOrDefault()))
 	reader := store.Log().OpenReader(0)
-	return &memberAppender{
-		raft:        state,
-		sm:          sm,
-		store:       store,
-		log:         logger,
-		member:      member,
-		nextIndex:   reader.LastIndex() + 1,
-		entryCh:     make(chan *log.Entry),
-		appendCh:    make(chan bool),
-		commitCh:    commitCh,
-		failCh:      failCh,
-		heartbeatCh: make(chan time.Time),
-		stopped:     make(chan bool),
-		reader:      reader,
-		tickTicker:  ticker,
-		tickCh:      ticker.C,
-		queue:       list.New(),
+
+	// If replication progress is persisted, start from the member's last known match index rather than the end
+	// of the log. The match index is verified by the consistency check of the first append.
+	nextIndex := reader.LastIndex() + 1
+	if state.Config().GetPersistReplicationProgress() {
+		if matchIndex := state.MatchIndex(member.MemberID); matchIndex > 0 && matchIndex < nextIndex {
+			logger.Debug("Resuming replication to %s from persisted match index %d", member.MemberID, matchIndex)
+			nextIndex = matchIndex + 1
+		}
+	}
+	appender := &memberAppender{
+		raft:          state,
+		sm:            sm,
+		store:         store,
+		log:           logger,
+		member:        member,
+		nextIndex:     nextIndex,
+		entryCh:       make(chan *log.Entry),
+		appendCh:      make(chan bool),
+		commitCh:      commitCh,
+		failCh:        failCh,
+		heartbeatCh:   make(chan time.Time),
+		probeCh:       make(chan struct{}, 1),
+		timeoutCh:     make(chan time.Duration, 1),
+		stopped:       make(chan struct{}),
+		reader:        reader,
+		parallelism:   maxParallelReads,
+		workers:       newWorkerPool(state.Config().GetMaxAppendWorkersOrDefault()),
+		readWorkers:   newWorkerPool(maxParallelReads),
+		tickTicker:    ticker,
+		tickCh:        ticker.C,
+		queue:         newEntryQueue(state.Config().GetMaxAppendQueueBytesOrDefault()),
+		resets:        metrics.NewCounter("raft_append_watchdog_resets_total", string(member.MemberID)),
+		failures:      metrics.NewCounter("raft_append_failures_total", string(member.MemberID)),
+		installFails:  metrics.NewCounter("raft_install_failures_total", string(member.MemberID)),
+		logGaps:       metrics.NewCounter("raft_append_log_gaps_total", string(member.MemberID)),
+		slowAppends:   metrics.NewCounter("raft_slow_appends_total", string(member.MemberID)),
+		flapBackoffs:  metrics.NewCounter("raft_append_flap_backoffs_total", string(member.MemberID)),
+		degradedGauge: metrics.NewGauge("raft_member_degraded", string(member.MemberID)),
+		failureLog:    newLogSampler(state.Config().GetFailureLogIntervalOrDefault()),
+		batchEntries:  metrics.NewHistogram("raft_append_batch_entries", string(member.MemberID), batchEntriesBounds),
+		batchBytes:    metrics.NewHistogram("raft_append_batch_bytes", string(member.MemberID), batchBytesBounds),
 	}
+	appender.exemptWorkers = appender.workers
+	appender.recordProgress()
+	return appender
 }
 
 // memberAppender handles replication to a member
@@ -332,30 +898,62 @@ type memberAppender struct {
 	store            store.Store
 	log              util.Logger
 	member           *raft.Member
-	active           bool
+	active           int32
 	snapshotIndex    raft.Index
 	prevTerm         raft.Term
 	nextIndex        raft.Index
 	matchIndex       raft.Index
 	appending        bool
-	failureCount     int
+	appendStartTime  time.Time
+	logGap           bool
+	installing       int32
+	forcingInstall   int32
+	installRestarts  int32
+	generation       uint64
+	lastResponseTime int64
+	acknowledged     int32
+	progress         atomic.Value
+	resets           *metrics.Counter
+	failures         *metrics.Counter
+	installFails     *metrics.Counter
+	logGaps          *metrics.Counter
+	slowAppends      *metrics.Counter
+	flapBackoffs     *metrics.Counter
+	degradedGauge    *metrics.Gauge
+	degraded         bool
+	failureLog       *logSampler
+	batchEntries     *metrics.Histogram
+	batchBytes       *metrics.Histogram
+	failureCount     int32
 	firstFailureTime time.Time
+	flapTimes        []time.Time
+	stabilizeUntil   int64
 	entryCh          chan *log.Entry
 	appendCh         chan bool
 	commitCh         chan<- memberCommit
 	failCh           chan<- time.Time
 	heartbeatCh      chan time.Time
+	probeCh          chan struct{}
+	probing          int32
+	timeoutCh        chan time.Duration
 	tickCh           <-chan time.Time
 	tickTicker       *time.Ticker
-	stopped          chan bool
+	stopped          chan struct{}
 	reader           log.Reader
-	queue            *list.List
+	parallelism      int
+	workers          *workerPool
+	exemptWorkers    *workerPool
+	appendWorkers    *workerPool
+	fallback         func([]raft.MemberID)
+	needed           func(*memberAppender) bool
+	readWorkers      *workerPool
+	queue            *entryQueue
 	mu               sync.Mutex
 }
 
 // start starts sending append requests to the member
 func (a *memberAppender) start() {
-	a.active = true
+	atomic.StoreInt32(&a.active, 1)
 	a.processEvents()
 }
 
@@ -363,110 +961,431 @@ func (a *memberAppender) processEvents() {
 	for {
 		select {
 		case entry := <-a.entryCh:
-			if a.failureCount == 0 {
+			// Witnesses are never sent entries, so entries are only cached for other members. The failure count is
+			// reset by the append goroutine as soon as the member responds, so entries arriving once the member has
+			// recovered are cached even before the response has been handled by this loop.
+			if atomic.LoadInt32(&a.failureCount) == 0 && !a.witness() {
 				a.mu.Lock()
-				a.queue.PushBack(entry)
+				a.queue.push(entry)
 				a.mu.Unlock()
 			}
 			if !a.appending {
-				a.appending = true
-				go a.append()
+				a.startAppend()
 			}
 		case hasEntries := <-a.appendCh:
 			a.appending = false
-			if hasEntries {
-				a.appending = true
-				go a.append()
+			if hasEntries || atomic.LoadInt32(&a.probing) == 1 {
+				a.startAppend()
 			}
 		case <-a.heartbeatCh:
+			// If probing on recovery is enabled, heartbeats are sent to backed off members as probes.
+			if atomic.LoadInt32(&a.failureCount) > 0 && a.raft.Config().GetProbeOnRecovery() {
+				atomic.StoreInt32(&a.probing, 1)
+			}
 			if !a.appending {
-				a.appending = true
-				go a.append()
+				a.startAppend()
+			}
+		case <-a.probeCh:
+			if atomic.LoadInt32(&a.failureCount) > 0 {
+				atomic.StoreInt32(&a.probing, 1)
+				if !a.appending {
+					a.startAppend()
+				}
 			}
 		case <-a.tickCh:
+			if a.appending && a.isStuck() {
+				a.reset()
+			}
+			// Heartbeats are not sent separately from entries. An in-progress append already serves as a heartbeat,
+			// and a new append carries any pending entries, so a request is only empty if the member is caught up.
 			if !a.appending {
-				a.appending = true
-				go a.append()
+				a.startAppend()
 			}
+		case timeout := <-a.timeoutCh:
+			a.tickTicker.Stop()
+			a.tickTicker = time.NewTicker(tickInterval(timeout))
+			a.tickCh = a.tickTicker.C
 		case <-a.stopped:
+			a.tickTicker.Stop()
 			return
 		}
 	}
 }
 
+// setElectionTimeout resets the member's tick interval for the given election timeout
+// Only the latest timeout is retained if the member has not yet applied a prior update.
+func (a *memberAppender) setElectionTimeout(timeout time.Duration) {
+	select {
+	case <-a.timeoutCh:
+	default:
+	}
+	a.timeoutCh <- timeout
+}
+
+// startAppend starts an append on the member's worker pool
+// Appends to caught-up members are started on the pool exempt from the replication budget.
+func (a *memberAppender) startAppend() {
+	a.appending = true
+	a.appendStartTime = time.Now()
+	if a.caughtUp() {
+		a.appendWorkers = a.exemptWorkers
+	} else {
+		a.appendWorkers = a.workers
+	}
+	a.appendWorkers.submit(a.append)
+}
+
+// caughtUp returns a bool indicating whether the member is healthy and its next append is a heartbeat or carries
+// only entries cached for the member since it last responded
+// caughtUp must only be called while no append is in progress.
+func (a *memberAppender) caughtUp() bool {
+	if atomic.LoadInt32(&a.failureCount) > 0 || atomic.LoadInt32(&a.installing) == 1 || atomic.LoadInt32(&a.forcingInstall) == 1 {
+		return false
+	}
+	a.mu.Lock()
+	index, ok := a.queue.front()
+	a.mu.Unlock()
+	if ok {
+		return index <= a.nextIndex
+	}
+	return a.nextIndex > a.store.Writer().LastIndex()
+}
+
+// isStuck returns a bool indicating whether the current append has exceeded the append deadline plus slack
+// Snapshot installs are subject to the longer install deadline.
+func (a *memberAppender) isStuck() bool {
+	deadline := a.raft.Config().GetElectionTimeoutOrDefault()
+	if atomic.LoadInt32(&a.installing) == 1 {
+		deadline = a.raft.Config().GetInstallTimeoutOrDefault()
+	}
+	return time.Since(a.appendStartTime) > deadline*appendWatchdogSlack
+}
+
+// reset abandons a stuck append and resets the appender to a clean state
+// The abandoned append goroutine discards its response once it returns, if it ever does. Its worker is detached from
+// the pool so repeatedly stuck appends can't occupy every worker and starve the member of further appends.
+func (a *memberAppender) reset() {
+	a.log.Warn("Append to %s did not complete within %s; resetting appender", a.member.MemberID, time.Since(a.appendStartTime))
+	a.resets.Inc()
+	atomic.AddUint64(&a.generation, 1)
+	a.appendWorkers.detach()
+	a.appending = false
+	a.mu.Lock()
+	a.queue.clear()
+	a.prevTerm = 0
+	a.mu.Unlock()
+}
+
+// isAbandoned returns a bool indicating whether the append started in the given generation was abandoned
+func (a *memberAppender) isAbandoned(generation uint64) bool {
+	if atomic.LoadUint64(&a.generation) != generation {
+		a.log.Debug("Discarding response from abandoned append to %s", a.member.MemberID)
+		return true
+	}
+	return false
+}
+
+// probe requests that the member be sent an append immediately, regardless of any backoff
+func (a *memberAppender) probe() {
+	select {
+	case a.probeCh <- struct{}{}:
+	default:
+	}
+}
+
 func (a *memberAppender) append() {
-	if a.failureCount > minBackoffFailureCount {
+	a.logGap = false
+
+	// Probes bypass the backoff. If the probe succeeds, the failure count is reset and replication resumes at once.
+	probing := atomic.SwapInt32(&a.probing, 0) == 1
+	if failureCount := int(atomic.LoadInt32(&a.failureCount)); failureCount > minBackoffFailureCount && !probing {
 		timeSinceFailure := float64(time.Since(a.firstFailureTime))
 		electionTimeout := a.raft.Config().GetElectionTimeoutOrDefault()
-		failureCount := a.failureCount - minBackoffFailureCount
+		failureCount -= minBackoffFailureCount
 		heartbeatWaitTime := math.Min(float64(failureCount*failureCount)*float64(electionTimeout.Nanoseconds()), float64(maxHeartbeatWait))
 		if timeSinceFailure > heartbeatWaitTime {
 			a.sendAppendRequest(a.nextAppendRequest())
 		} else {
 			a.pause()
 		}
+	} else if a.stabilizing() {
+		// Flapping members are sent heartbeats to maintain leadership but no entries until they've stabilized.
+		a.raft.ReadLock()
+		request := a.emptyAppendRequest()
+		a.raft.ReadUnlock()
+		a.sendAppendRequest(request)
+	} else if a.witness() {
+		// Witnesses store no entries, so they're never sent snapshots.
+		a.sendAppendRequest(a.nextAppendRequest())
 	} else {
 		// TODO: The snapshot store needs concurrency control when accessing the snapshots for replication.
 		snapshot := a.store.Snapshot().CurrentSnapshot()
-		if snapshot != nil && a.snapshotIndex < snapshot.Index() && snapshot.Index() >= a.nextIndex {
+		if a.installForced(snapshot) {
+			a.log.Info("Replicating snapshot %d to %s in place of entries %d-%d", snapshot.Index(), a.member.MemberID, a.nextIndex, snapshot.Index())
+			a.sendInstallRequests(snapshot)
+		} else if a.needsSnapshot(snapshot) {
 			a.log.Debug("Replicating snapshot %d to %s", snapshot.Index(), a.member.MemberID)
 			a.sendInstallRequests(snapshot)
+		} else if a.nextIndex < a.reader.FirstIndex() {
+			a.handleLogGap(snapshot)
 		} else {
 			a.sendAppendRequest(a.nextAppendRequest())
 		}
 	}
 }
 
+// handleLogGap handles a member whose next entry was compacted from the log but is not covered by a snapshot install
+// If the member was already sent the current snapshot, it must have lost it, so the snapshot is installed again.
+// Otherwise, the member can't be caught up until a snapshot covering the gap is taken. Rather than repeatedly
+// sending appends that make no progress, the gap is reported, the member is sent an empty append to maintain
+// leadership, and the member is retried on the next heartbeat.
+func (a *memberAppender) handleLogGap(snapshot snapshot.Snapshot) {
+	firstIndex := a.reader.FirstIndex()
+	if snapshot != nil && snapshot.Index()+1 >= firstIndex {
+		a.log.Warn("Member %s needs entry %d preceding the first entry %d in the log; reinstalling snapshot %d", a.member.MemberID, a.nextIndex, firstIndex, snapshot.Index())
+		a.snapshotIndex = 0
+		a.sendInstallRequests(snapshot)
+		return
+	}
+	a.recordFailure(a.logGaps, "Cannot replicate entry %d to %s: the log begins at entry %d and no snapshot covers the gap", a.nextIndex, a.member.MemberID, firstIndex)
+	a.logGap = true
+	a.raft.ReadLock()
+	request := a.emptyAppendRequest()
+	a.raft.ReadUnlock()
+	a.sendAppendRequest(request)
+}
+
+// needsSnapshot returns a bool indicating whether the member must be sent the given snapshot to catch up
+// Members whose next entry is still in the log, e.g. in the tail of entries retained when the log was compacted, are
+// caught up with appends rather than the snapshot.
+func (a *memberAppender) needsSnapshot(snapshot snapshot.Snapshot) bool {
+	if snapshot == nil || a.snapshotIndex >= snapshot.Index() || snapshot.Index() < a.nextIndex {
+		return false
+	}
+	if a.nextIndex < a.reader.FirstIndex() || a.nextIndex > a.reader.LastIndex() {
+		return true
+	}
+	if a.nextIndex == 1 {
+		return false
+	}
+	_, ok := a.store.TermAt(a.nextIndex - 1)
+	return !ok
+}
+
+// installForced returns a bool indicating whether the member was marked to be caught up by installing the given snapshot
+// The mark is cleared if the snapshot holds no entries the member needs, in which case the member is caught up from the
+// log as usual. Otherwise, the mark is cleared once the snapshot is installed.
+func (a *memberAppender) installForced(snapshot snapshot.Snapshot) bool {
+	if atomic.LoadInt32(&a.forcingInstall) == 0 {
+		return false
+	}
+	if snapshot == nil || snapshot.Index() < a.nextIndex {
+		a.log.Warn("No snapshot covers entry %d of %s; catching up from the log", a.nextIndex, a.member.MemberID)
+		atomic.StoreInt32(&a.forcingInstall, 0)
+		return false
+	}
+	return true
+}
+
+// installSuperseded returns a bool indicating whether an in-progress install of the given snapshot should be cancelled
+// An install is superseded when a newer snapshot has been taken. If snapshots are taken faster than they can be sent
+// to the member, restarting every install would never complete one, so once an install has been restarted
+// maxInstallRestarts times in a row, the current install is completed and the member is caught up from there.
+func (a *memberAppender) installSuperseded(snapshot snapshot.Snapshot) bool {
+	if atomic.LoadInt32(&a.installRestarts) >= maxInstallRestarts {
+		return false
+	}
+	current := a.store.Snapshot().CurrentSnapshot()
+	return current != nil && current.Index() > snapshot.Index()
+}
+
 // stop stops sending append requests to the member
+// Closing the stopped channel ensures responses that arrive after the member is stopped are dropped
+// rather than blocking on channels that are no longer consumed.
 func (a *memberAppender) stop() {
-	a.active = false
-	a.tickTicker.Stop()
-	a.stopped <- true
+	atomic.StoreInt32(&a.active, 0)
+	close(a.stopped)
+	a.workers.close()
+	a.exemptWorkers.close()
+	a.readWorkers.close()
+}
+
+// recordProgress records the member's match and next indexes to be reported by getProgress
+func (a *memberAppender) recordProgress() {
+	a.progress.Store(&raft.MemberProgress{
+		MemberID:   a.member.MemberID,
+		MatchIndex: a.matchIndex,
+		NextIndex:  a.nextIndex,
+		Degraded:   a.degraded,
+	})
+}
+
+// getProgress returns the member's last recorded replication progress
+func (a *memberAppender) getProgress() *raft.MemberProgress {
+	progress := *a.progress.Load().(*raft.MemberProgress)
+	return &progress
+}
+
+// voting returns a bool indicating whether the member is a voter
+func (a *memberAppender) voting() bool {
+	return a.member.Type == raft.Member_ACTIVE || a.member.Type == raft.Member_WITNESS
+}
+
+// witness returns a bool indicating whether the member is a witness, which records the last entry in the log
+// rather than storing entries
+func (a *memberAppender) witness() bool {
+	return a.member.Type == raft.Member_WITNESS
 }
 
 func (a *memberAppender) succeed() {
-	a.failureCount = 0
+	if failureCount := atomic.SwapInt32(&a.failureCount, 0); failureCount > minBackoffFailureCount {
+		a.log.Info("Member %s recovered after %d failed requests; resuming replication", a.member.MemberID, failureCount)
+	}
+	atomic.StoreInt64(&a.lastResponseTime, time.Now().UnixNano())
+}
+
+// getLastResponseTime returns the last time the member successfully responded to a request
+func (a *memberAppender) getLastResponseTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&a.lastResponseTime))
 }
 
 func (a *memberAppender) fail(time time.Time) {
-	if a.failureCount == 0 {
+	if atomic.AddInt32(&a.failureCount, 1) == 1 {
 		a.firstFailureTime = time
+		// A failure following a successful response is a flap.
+		if atomic.LoadInt64(&a.lastResponseTime) > 0 {
+			a.recordFlap(time)
+		}
+	}
+	// Failures of non-voting members don't indicate a loss of quorum.
+	if !a.voting() {
+		return
+	}
+	select {
+	case a.failCh <- time:
+	case <-a.stopped:
+	}
+}
+
+// recordFlap records a failure of the member after it had recovered
+// Members that repeatedly recover and fail again would otherwise oscillate between full replication and the failure
+// backoff. If the member flaps more than the configured threshold within the flap window, entries are withheld for
+// the flap backoff to give the member time to stabilize. Heartbeats continue to be sent in the meantime.
+func (a *memberAppender) recordFlap(flapTime time.Time) {
+	threshold := a.raft.Config().GetFlapThresholdOrDefault()
+	if threshold == 0 {
+		return
+	}
+	window := a.raft.Config().GetFlapWindowOrDefault()
+	flapTimes := a.flapTimes[:0]
+	for _, t := range a.flapTimes {
+		if flapTime.Sub(t) < window {
+			flapTimes = append(flapTimes, t)
+		}
+	}
+	a.flapTimes = append(flapTimes, flapTime)
+	if len(a.flapTimes) > threshold {
+		backoff := a.raft.Config().GetFlapBackoffOrDefault()
+		a.log.Warn("Member %s failed %d times within %s after recovering; withholding entries for %s", a.member.MemberID, len(a.flapTimes), window, backoff)
+		a.flapBackoffs.Inc()
+		atomic.StoreInt64(&a.stabilizeUntil, flapTime.Add(backoff).UnixNano())
+		a.flapTimes = nil
+	}
+}
+
+// stabilizing returns a bool indicating whether entries are being withheld from a flapping member
+// Entries are not withheld from a member the leader needs to form a quorum, since withholding them would stall commits
+// until the flap backoff elapsed.
+func (a *memberAppender) stabilizing() bool {
+	until := atomic.LoadInt64(&a.stabilizeUntil)
+	if until == 0 {
+		return false
+	}
+	if time.Now().UnixNano() >= until {
+		if atomic.CompareAndSwapInt64(&a.stabilizeUntil, until, 0) {
+			a.log.Info("Member %s stabilized; resuming replication", a.member.MemberID)
+		}
+		return false
+	}
+	return a.needed == nil || !a.needed(a)
+}
+
+// isStabilizing returns a bool indicating whether the member's flap backoff extends past the given time
+func (a *memberAppender) isStabilizing(now time.Time) bool {
+	return now.UnixNano() < atomic.LoadInt64(&a.stabilizeUntil)
+}
+
+// recordFailure counts a failed request to the member and logs the failure
+// Requests are retried continuously while the member is unreachable, so repeated failures are sampled to avoid
+// flooding the logs. Every failure is counted by the given counter.
+func (a *memberAppender) recordFailure(counter *metrics.Counter, message string, args ...interface{}) {
+	counter.Inc()
+	if ok, suppressed := a.failureLog.sample(); ok {
+		if suppressed > 0 {
+			message = fmt.Sprintf("%s (%d similar failures not logged)", message, suppressed)
+		}
+		a.log.Warn(message, args...)
 	}
-	a.failureCount++
-	a.failCh <- time
 }
 
 func (a *memberAppender) requeue() {
+	// Entries that can't be replicated due to a gap in the log or withheld from a flapping member are retried on
+	// the next heartbeat.
 	a.raft.ReadLock()
-	hasEntries := a.reader.LastIndex() >= a.nextIndex
+	hasEntries := a.reader.LastIndex() >= a.nextIndex && !a.logGap && !a.stabilizing()
 	a.raft.ReadUnlock()
-	a.appendCh <- hasEntries
+	select {
+	case a.appendCh <- hasEntries:
+	case <-a.stopped:
+	}
 }
 
 func (a *memberAppender) pause() {
-	a.appendCh <- false
+	select {
+	case a.appendCh <- false:
+	case <-a.stopped:
+	}
 }
 
-func (a *memberAppender) newInstallRequest(snapshot snapshot.Snapshot, bytes []byte) *raft.InstallRequest {
+func (a *memberAppender) newInstallRequest(snapshot snapshot.Snapshot, bytes []byte, checksum uint32) *raft.InstallRequest {
 	a.raft.ReadLock()
 	defer a.raft.ReadUnlock()
 	return &raft.InstallRequest{
-		Term:      a.raft.Term(),
-		Leader:    a.raft.Member(),
-		Index:     snapshot.Index(),
-		Timestamp: snapshot.Timestamp(),
-		Data:      bytes,
+		Term:         a.raft.Term(),
+		Leader:       a.raft.Member(),
+		Index:        snapshot.Index(),
+		Timestamp:    snapshot.Timestamp(),
+		Data:         bytes,
+		SnapshotTerm: snapshot.Term(),
+		Checksum:     checksum,
+		Metadata:     snapshot.Metadata(),
 	}
 }
 
 func (a *memberAppender) sendInstallRequests(snapshot snapshot.Snapshot) {
+	// Entries cached for the member are obsolete once the member is caught up by a snapshot. Clear the cache and the
+	// term of the member's last entry so that once the install completes, appends resume from the entry following the
+	// snapshot, reading entries from the log.
+	a.mu.Lock()
+	a.queue.clear()
+	a.prevTerm = 0
+	a.mu.Unlock()
+
 	// Start the append to the member.
 	startTime := time.Now()
+	generation := atomic.LoadUint64(&a.generation)
+	atomic.StoreInt32(&a.installing, 1)
+	defer atomic.StoreInt32(&a.installing, 0)
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.raft.Config().GetElectionTimeoutOrDefault())
+	// Snapshots may take much longer to replicate than append requests, so installs use a separate timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), a.raft.Config().GetInstallTimeoutOrDefault())
 	defer cancel()
 
+	// Failures to connect to the member are returned as errors and retried with backoff like any failed request.
 	stream, future, err := a.raft.Protocol().Install(ctx, a.member.MemberID)
+	if a.isAbandoned(generation) {
+		return
+	}
 	if err != nil {
 		a.log.ErrorFrom("InstallRequest", err, a.member.MemberID)
 		a.handleInstallError(snapshot, err, startTime)
@@ -477,7 +1396,10 @@ func (a *memberAppender) sendInstallRequests(snapshot snapshot.Snapshot) {
 	defer func() {
 		_ = reader.Close()
 	}()
+	// Each request carries the checksum of the data sent so far, allowing the member to verify the snapshot as it's
+	// received.
 	bytes := make([]byte, maxBatchSize)
+	var checksum uint32
 	for {
 		n, err := reader.Read(bytes)
 		if err == io.EOF {
@@ -488,16 +1410,38 @@ func (a *memberAppender) sendInstallRequests(snapshot snapshot.Snapshot) {
 			return
 		}
 
-		request := a.newInstallRequest(snapshot, bytes[:n])
+		// If a newer snapshot was taken while the snapshot was being sent, cancel the stream rather than completing
+		// the install. Cancelling the stream aborts the partially written snapshot on the member, and the requeued
+		// append installs the newer snapshot.
+		if a.installSuperseded(snapshot) {
+			a.log.Debug("Abandoning install of snapshot %d on %s", snapshot.Index(), a.member.MemberID)
+			atomic.AddInt32(&a.installRestarts, 1)
+			cancel()
+			close(stream)
+			<-future
+			if !a.isAbandoned(generation) {
+				a.requeue()
+			}
+			return
+		}
+
+		// The stream is sent asynchronously, so each request gets its own copy of the chunk before the buffer is
+		// reused for the next read.
+		data := append([]byte(nil), bytes[:n]...)
+		checksum = crc32.Update(checksum, crc32.IEEETable, data)
+		request := a.newInstallRequest(snapshot, data, checksum)
 		a.log.SendTo("InstallRequest", request, a.member.MemberID)
 		stream <- request
 	}
 	close(stream)
 
 	response := <-future
+	if a.isAbandoned(generation) {
+		return
+	}
 	if response.Failed() {
 		a.log.ErrorFrom("InstallRequest", response.Error, a.member.MemberID)
-		a.handleInstallError(snapshot, err, startTime)
+		a.handleInstallError(snapshot, response.Error, startTime)
 	} else {
 		a.log.ReceiveFrom("InstallResponse", response, a.member.MemberID)
 		if response.Response.Status == raft.ResponseStatus_OK {
@@ -512,8 +1456,18 @@ func (a *memberAppender) handleInstallResponse(snapshot snapshot.Snapshot, respo
 	// Reset the member failure count to allow entries to be sent to the member.
 	a.succeed()
 
-	// Update the snapshot index
+	// Update the snapshot index and resume appending entries following the snapshot
+	atomic.StoreInt32(&a.forcingInstall, 0)
+	atomic.StoreInt32(&a.installRestarts, 0)
 	a.snapshotIndex = snapshot.Index()
+	if a.matchIndex < snapshot.Index() {
+		a.matchIndex = snapshot.Index()
+	}
+	a.nextIndex = snapshot.Index() + 1
+	a.mu.Lock()
+	a.prevTerm = 0
+	a.mu.Unlock()
+	a.recordProgress()
 
 	// Send a commit event to the parent appender.
 	a.commit(startTime)
@@ -528,7 +1482,7 @@ func (a *memberAppender) handleInstallFailure(snapshot snapshot.Snapshot, respon
 }
 
 func (a *memberAppender) handleInstallError(snapshot snapshot.Snapshot, err error, startTime time.Time) {
-	a.log.Debug("Failed to install %s: %s", a.member.MemberID, err)
+	a.recordFailure(a.installFails, "Failed to install snapshot %d on %s: %v", snapshot.Index(), a.member.MemberID, err)
 	a.fail(startTime)
 	a.requeue()
 }
@@ -541,39 +1495,70 @@ func (a *memberAppender) nextAppendRequest() *raft.AppendRequest {
 	// helps avoid doing expensive work until we can ascertain the member is back up.
 	a.raft.ReadLock()
 	defer a.raft.ReadUnlock()
-	if a.failureCount > 0 || a.nextIndex > a.reader.LastIndex() {
+	if a.witness() {
+		return a.witnessAppendRequest()
+	}
+	if atomic.LoadInt32(&a.failureCount) > 0 || a.nextIndex > a.reader.LastIndex() {
 		return a.emptyAppendRequest()
 	}
 	return a.entriesAppendRequest()
 }
 
-func (a *memberAppender) emptyAppendRequest() *raft.AppendRequest {
-	prevIndex := a.nextIndex - 1
-	if a.prevTerm == 0 && prevIndex >= a.reader.FirstIndex() {
-		a.reader.Reset(prevIndex)
-		a.prevTerm = a.reader.NextEntry().Entry.Term
+// witnessAppendRequest returns a request for a witness to record the last entry in the log
+// Rather than sending entries, the last entry in the log is sent as the previous entry. Once the witness has
+// recorded the entry, its response acknowledges the log up to the entry.
+func (a *memberAppender) witnessAppendRequest() *raft.AppendRequest {
+	lastIndex := a.reader.LastIndex()
+	lastTerm, _ := a.store.TermAt(lastIndex)
+	return &raft.AppendRequest{
+		Term:         a.raft.Term(),
+		Leader:       a.raft.Member(),
+		PrevLogIndex: lastIndex,
+		PrevLogTerm:  lastTerm,
+		CommitIndex:  a.raft.CommitIndex(),
+		LastLogIndex: lastIndex,
+	}
+}
+
+// prevLogTerm returns the term of the entry preceding the next index, reading it from the store if it was reset
+// The term is guarded by the same lock as the queue since both are reset when the member is caught up by a snapshot.
+func (a *memberAppender) prevLogTerm() raft.Term {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.prevTerm == 0 {
+		if term, ok := a.store.TermAt(a.nextIndex - 1); ok {
+			a.prevTerm = term
+		}
 	}
+	return a.prevTerm
+}
+
+func (a *memberAppender) emptyAppendRequest() *raft.AppendRequest {
 	return &raft.AppendRequest{
 		Term:         a.raft.Term(),
 		Leader:       a.raft.Member(),
 		PrevLogIndex: a.nextIndex - 1,
-		PrevLogTerm:  a.prevTerm,
+		PrevLogTerm:  a.prevLogTerm(),
 		CommitIndex:  a.raft.CommitIndex(),
+		LastLogIndex: a.reader.LastIndex(),
 	}
 }
 
 func (a *memberAppender) entriesAppendRequest() *raft.AppendRequest {
-	prevIndex := a.nextIndex - 1
-	if a.prevTerm == 0 && prevIndex >= a.reader.FirstIndex() {
-		a.reader.Reset(prevIndex)
-		a.prevTerm = a.reader.NextEntry().Entry.Term
-	}
 	request := &raft.AppendRequest{
 		Term:         a.raft.Term(),
 		Leader:       a.raft.Member(),
 		PrevLogIndex: a.nextIndex - 1,
-		PrevLogTerm:  a.prevTerm,
+		PrevLogTerm:  a.prevLogTerm(),
 		CommitIndex:  a.raft.CommitIndex(),
+		LastLogIndex: a.reader.LastIndex(),
+	}
+
+	// If the member is far behind and the next entry is not cached, prefetch the batch from the log in parallel.
+	if lastIndex := a.reader.LastIndex(); a.parallelism > 1 && lastIndex-a.nextIndex+1 >= parallelReadThreshold && !a.cached(a.nextIndex) {
+		request.Entries = a.readEntries(a.nextIndex, lastIndex)
+		a.observeBatch(request.Entries)
+		return request
 	}
 
 	entriesList := list.New()
@@ -584,31 +1569,29 @@ func (a *memberAppender) entriesAppendRequest() *raft.AppendRequest {
 	for nextIndex <= a.reader.LastIndex() {
 		// First, try to get the entry from the cache.
 		a.mu.Lock()
-		entry := a.queue.Front()
-		if entry != nil {
-			indexed := entry.Value.(*log.Entry)
-			if indexed.Index == nextIndex {
-				entriesList.PushBack(indexed.Entry)
-				a.queue.Remove(entry)
-				size += indexed.Entry.XXX_Size()
-				nextIndex++
-				if size >= maxBatchSize {
-					break
-				}
-				a.mu.Unlock()
-				continue
-			} else if indexed.Index < nextIndex {
-				a.queue.Remove(entry)
-				a.mu.Unlock()
-				continue
+		index, ok := a.queue.front()
+		if ok && index == nextIndex {
+			indexed := a.queue.pop()
+			a.mu.Unlock()
+			entriesList.PushBack(indexed.Entry)
+			size += indexed.Entry.XXX_Size()
+			nextIndex++
+			if size >= maxBatchSize {
+				break
 			}
+			continue
+		} else if ok && index < nextIndex {
+			a.queue.pop()
+			a.mu.Unlock()
+			continue
 		}
 		a.mu.Unlock()
 
 		// If the entry was not in the cache, read it from the log reader.
+		// If the log was compacted beyond the next index, the entry is only available via a snapshot.
 		a.reader.Reset(nextIndex)
 		indexed := a.reader.NextEntry()
-		if indexed != nil {
+		if indexed != nil && indexed.Index == nextIndex {
 			entriesList.PushBack(indexed.Entry)
 			size += indexed.Entry.XXX_Size()
 			nextIndex++
@@ -630,18 +1613,106 @@ func (a *memberAppender) entriesAppendRequest() *raft.AppendRequest {
 
 	// Add the entries to the request builder and return the request.
 	request.Entries = entries
+	a.observeBatch(entries)
 	return request
 }
 
+// cached returns a bool indicating whether the entry at the given index is in the member's entry queue
+func (a *memberAppender) cached(index raft.Index) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.queue.contains(index)
+}
+
+// observeBatch records the number of entries and bytes in an append batch
+func (a *memberAppender) observeBatch(entries []*raft.LogEntry) {
+	size := 0
+	for _, entry := range entries {
+		size += entry.XXX_Size()
+	}
+	a.batchEntries.Observe(int64(len(entries)))
+	a.batchBytes.Observe(int64(size))
+	a.log.Trace("Built append batch of %d entries (%d bytes) for %s", len(entries), size, a.member.MemberID)
+}
+
+// readEntries reads a batch of entries from nextIndex up to lastIndex using a bounded number of concurrent log readers
+// Entries are returned in index order and the batch is limited to maxBatchSize bytes. The readers are closed once the
+// batch has been read.
+func (a *memberAppender) readEntries(nextIndex raft.Index, lastIndex raft.Index) []*raft.LogEntry {
+	readers := make([]log.Reader, a.parallelism)
+	for i := range readers {
+		reader := a.store.Log().OpenReader(0)
+		defer reader.Close()
+		readers[i] = reader
+	}
+
+	entries := make([]*raft.LogEntry, 0, parallelReadChunkSize*len(readers))
+	size := 0
+	for nextIndex <= lastIndex {
+		count := int(lastIndex - nextIndex + 1)
+		if count > parallelReadChunkSize*len(readers) {
+			count = parallelReadChunkSize * len(readers)
+		}
+
+		// Each reader reads a contiguous chunk of the round into its own range of the slice.
+		chunk := make([]*raft.LogEntry, count)
+		wg := &sync.WaitGroup{}
+		for i := 0; i*parallelReadChunkSize < count; i++ {
+			start := i * parallelReadChunkSize
+			end := start + parallelReadChunkSize
+			if end > count {
+				end = count
+			}
+			wg.Add(1)
+			reader := readers[i]
+			sent := a.readWorkers.submit(func() {
+				defer wg.Done()
+				reader.Reset(nextIndex + raft.Index(start))
+				for j := start; j < end; j++ {
+					indexed := reader.NextEntry()
+					if indexed == nil || indexed.Index != nextIndex+raft.Index(j) {
+						return
+					}
+					chunk[j] = indexed.Entry
+				}
+			})
+
+			// If the member was stopped, the chunk is left unread and the batch ends before it.
+			if !sent {
+				wg.Done()
+			}
+		}
+		wg.Wait()
+
+		// Assemble the chunks in order, stopping at the first gap or once the batch is full.
+		for _, entry := range chunk {
+			if entry == nil {
+				return entries
+			}
+			entries = append(entries, entry)
+			size += entry.XXX_Size()
+			nextIndex++
+			if size >= maxBatchSize {
+				return entries
+			}
+		}
+	}
+	return entries
+}
+
 func (a *memberAppender) sendAppendRequest(request *raft.AppendRequest) {
 	// Start the append to the member.
 	startTime := time.Now()
+	generation := atomic.LoadUint64(&a.generation)
 
 	ctx, cancel := context.WithTimeout(context.Background(), a.raft.Config().GetElectionTimeoutOrDefault())
 	defer cancel()
 
 	a.log.SendTo("AppendRequest", request, a.member.MemberID)
 	response, err := a.raft.Protocol().Append(ctx, request, a.member.MemberID)
+	if a.isAbandoned(generation) {
+		return
+	}
 	if err == nil {
 		a.log.ReceiveFrom("AppendResponse", response, a.member.MemberID)
 		if response.Status == raft.ResponseStatus_OK {
@@ -657,74 +1728,144 @@ func (a *memberAppender) sendAppendRequest(request *raft.AppendRequest) {
 
 func (a *memberAppender) commit(time time.Time) {
 	// Send a commit event to the parent appender.
-	a.commitCh <- memberCommit{
+	select {
+	case a.commitCh <- memberCommit{
 		member: a,
 		index:  a.matchIndex,
 		time:   time,
+	}:
+	case <-a.stopped:
 	}
 }
 
 func (a *memberAppender) handleAppendResponse(request *raft.AppendRequest, response *raft.AppendResponse, startTime time.Time) {
 	// Reset the member failure count to avoid empty heartbeats.
 	a.succeed()
+	a.checkSlowAppend(startTime)
+
+	// A response for the leader's term indicates the member has accepted the leader.
+	if response.Term == request.Term {
+		atomic.StoreInt32(&a.acknowledged, 1)
+	}
+
+	// If the member can't relay entries to some of its downstream members, replicate to them directly.
+	if len(response.Unrelayed) > 0 && a.fallback != nil {
+		a.fallback(response.Unrelayed)
+	}
 
 	// If replication succeeded then trigger commit futures.
 	if response.Succeeded {
 		// If the replica returned a valid match index then update the existing match index.
+		if response.LastLogIndex != a.matchIndex && a.raft.Config().GetPersistReplicationProgress() {
+			a.raft.SetMatchIndex(a.member.MemberID, response.LastLogIndex)
+		}
 		a.matchIndex = response.LastLogIndex
 		a.nextIndex = a.matchIndex + 1
 
 		// If entries were sent to the follower, update the previous entry term to the term of the
 		// last entry in the follower's log.
 		if len(request.Entries) > 0 {
+			a.mu.Lock()
 			a.prevTerm = request.Entries[response.LastLogIndex-request.PrevLogIndex-1].Term
+			a.mu.Unlock()
 		}
 
 		// Send a commit event to the parent appender.
 		a.commit(startTime)
 	} else {
-		// If the request was rejected, use a double checked lock to compare the response term to the
-		// server's term. If the term is greater than the local server's term, transition back to follower.
-		a.raft.ReadLock()
-		if response.Term > a.raft.Term() {
-			a.raft.ReadUnlock()
-			a.raft.WriteLock()
-			defer a.raft.WriteUnlock()
-			if response.Term > a.raft.Term() {
-				// If we've received a greater term, update the term and transition back to follower.
-				_ = a.raft.SetTerm(response.Term)
-				_ = a.raft.SetLeader(nil)
-				a.raft.SetRole(raft.RoleFollower)
-			}
+		// If the request was rejected because the member has seen a greater term, this leader is stale.
+		if a.stepDownIfStale(response.Term) {
 			return
 		}
 
-		a.raft.ReadUnlock()
-
 		// If the request was rejected, the follower should have provided the correct last index in their log.
 		// This helps us converge on the matchIndex faster than by simply decrementing nextIndex one index at a time.
-		// Reset the matchIndex and nextIndex according to the response.
-		if response.LastLogIndex < a.matchIndex {
-			a.matchIndex = response.LastLogIndex
+		// Reset the matchIndex and nextIndex according to the response. If the follower's log is ahead of the
+		// leader's, resume from the end of the leader's log to truncate the follower's uncommitted entries.
+		lastLogIndex := response.LastLogIndex
+		a.raft.ReadLock()
+		if lastIndex := a.reader.LastIndex(); lastLogIndex > lastIndex {
+			lastLogIndex = lastIndex
+		}
+		a.raft.ReadUnlock()
+		if lastLogIndex < a.matchIndex {
+			a.matchIndex = lastLogIndex
 			a.log.Trace("Reset match index for %s to %d", a.member.MemberID, a.matchIndex)
 		}
-		if response.LastLogIndex+1 != a.nextIndex {
-			a.nextIndex = response.LastLogIndex + 1
+		if lastLogIndex+1 != a.nextIndex {
+			a.nextIndex = lastLogIndex + 1
 			a.log.Trace("Reset next index for %s to %d", a.member.MemberID, a.nextIndex)
+			a.mu.Lock()
 			a.prevTerm = 0
+			a.mu.Unlock()
 		}
 	}
+	a.recordProgress()
 
 	// Notify the appender that the next index can be appended.
 	a.requeue()
 }
 
+// checkSlowAppend marks the member degraded if a successful append started at the given time exceeded the slow
+// append threshold, and clears the mark once the member responds within the threshold again. A slow append is
+// not a failure: only appends that hit the RPC deadline count toward the member's failures.
+func (a *memberAppender) checkSlowAppend(startTime time.Time) {
+	threshold := a.raft.Config().GetSlowAppendThresholdOrDefault()
+	if threshold == 0 {
+		return
+	}
+	elapsed := time.Since(startTime)
+	degraded := elapsed > threshold
+	if degraded {
+		a.slowAppends.Inc()
+	}
+	if degraded != a.degraded {
+		a.degraded = degraded
+		if degraded {
+			a.degradedGauge.Set(1)
+			a.log.Warn("Append to %s took %s, exceeding the slow append threshold of %s", a.member.MemberID, elapsed, threshold)
+		} else {
+			a.degradedGauge.Set(0)
+			a.log.Info("Append to %s completed within the slow append threshold of %s", a.member.MemberID, threshold)
+		}
+	}
+}
+
+// stepDownIfStale transitions the leader back to follower if the given response term is greater than the local term
+// A leader that was partitioned from the cluster learns of the term in which a new leader was elected from the
+// members' responses to its first heartbeat. Returns a bool indicating whether the leader stepped down.
+func (a *memberAppender) stepDownIfStale(term raft.Term) bool {
+	// Use a double checked lock to compare the response term to the server's term.
+	a.raft.ReadLock()
+	if term <= a.raft.Term() {
+		a.raft.ReadUnlock()
+		return false
+	}
+	a.raft.ReadUnlock()
+
+	a.raft.WriteLock()
+	defer a.raft.WriteUnlock()
+	if term > a.raft.Term() {
+		a.log.Info("Received greater term %d from %s; stepping down", term, a.member.MemberID)
+		_ = a.raft.SetTerm(term)
+		_ = a.raft.SetLeader(nil)
+		a.raft.SetRole(raft.RoleFollower)
+	}
+	return true
+}
+
 func (a *memberAppender) handleAppendFailure(request *raft.AppendRequest, response *raft.AppendResponse, startTime time.Time) {
+	// Members report their current term in error responses as well, so a stale leader steps down even if the
+	// member could not handle the request.
+	if a.stepDownIfStale(response.Term) {
+		return
+	}
 	a.fail(startTime)
 	a.requeue()
 }
 
 func (a *memberAppender) handleAppendError(request *raft.AppendRequest, err error, startTime time.Time) {
+	a.recordFailure(a.failures, "Failed to append entries to %s: %v", a.member.MemberID, err)
 	a.fail(startTime)
 	a.requeue()
 }
@@ -0,0 +1,2086 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/atomix/go-framework/pkg/atomix/node"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestMemberAppender(t testing.TB, entries int, parallelism int) *memberAppender {
+	ctrl := gomock.NewController(t)
+	protocol, sm, store := newTestState(mock.NewMockClient(ctrl))
+	for i := 1; i <= entries; i++ {
+		store.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(i/100 + 1),
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{
+					Value: []byte(fmt.Sprintf("command-%d", i)),
+				},
+			},
+		})
+	}
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), nil, nil)
+	appender.tickTicker.Stop()
+	appender.parallelism = parallelism
+	appender.nextIndex = 1
+	return appender
+}
+
+func TestAppenderParallelRead(t *testing.T) {
+	serial := newTestMemberAppender(t, 5000, 1)
+	parallel := newTestMemberAppender(t, 5000, maxParallelReads)
+
+	serialRequest := serial.entriesAppendRequest()
+	parallelRequest := parallel.entriesAppendRequest()
+	assert.Len(t, serialRequest.Entries, 5000)
+	assert.Len(t, parallelRequest.Entries, 5000)
+	for i, entry := range parallelRequest.Entries {
+		assert.Equal(t, serialRequest.Entries[i].Term, entry.Term)
+		assert.Equal(t, fmt.Sprintf("command-%d", i+1), string(entry.GetCommand().Value))
+	}
+
+	// Verify that reads begin at the next index and stop at the end of the log
+	parallel.nextIndex = 3001
+	parallel.prevTerm = 0
+	request := parallel.entriesAppendRequest()
+	assert.Equal(t, raft.Index(3000), request.PrevLogIndex)
+	assert.Equal(t, raft.Term(31), request.PrevLogTerm)
+	assert.Len(t, request.Entries, 2000)
+	assert.Equal(t, "command-3001", string(request.Entries[0].GetCommand().Value))
+
+	// Verify that batches are limited to the maximum batch size
+	entries := parallel.readEntries(raft.Index(1), raft.Index(5000))
+	assert.Len(t, entries, 5000)
+	large := newTestMemberAppender(t, 0, maxParallelReads)
+	for i := 0; i < 2048; i++ {
+		large.store.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(1),
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{
+					Value: make([]byte, 1024),
+				},
+			},
+		})
+	}
+	entries = large.readEntries(raft.Index(1), raft.Index(2048))
+	assert.True(t, len(entries) < 2048)
+	size := 0
+	for _, entry := range entries {
+		size += entry.XXX_Size()
+	}
+	assert.True(t, size >= maxBatchSize)
+	assert.True(t, size-entries[len(entries)-1].XXX_Size() < maxBatchSize)
+
+	// Verify that entries in the member's queue are served from the queue rather than the log
+	cached := newTestMemberAppender(t, 2000, maxParallelReads)
+	for i := 1; i <= 2000; i++ {
+		cached.queue.push(&log.Entry{
+			Index: raft.Index(i),
+			Entry: &raft.LogEntry{
+				Term: 1,
+				Entry: &raft.LogEntry_Command{
+					Command: &raft.CommandEntry{
+						Value: []byte(fmt.Sprintf("cached-%d", i)),
+					},
+				},
+			},
+		})
+	}
+	assert.True(t, cached.cached(1))
+	assert.False(t, cached.cached(2001))
+	request = cached.entriesAppendRequest()
+	assert.Equal(t, "cached-1", string(request.Entries[0].GetCommand().Value))
+}
+
+func TestAppenderBatchMetrics(t *testing.T) {
+	appender := newTestMemberAppender(t, 100, 1)
+	count, sum := appender.batchEntries.Count(), appender.batchEntries.Sum()
+	small, large := appender.batchEntries.Bucket(64), appender.batchEntries.Bucket(256)
+	bytes, smallBytes := appender.batchBytes.Sum(), appender.batchBytes.Bucket(1024)
+
+	// Verify a batch of 100 entries is recorded in the expected buckets
+	request := appender.entriesAppendRequest()
+	assert.Len(t, request.Entries, 100)
+	size := 0
+	for _, entry := range request.Entries {
+		size += entry.XXX_Size()
+	}
+	assert.True(t, size > 1024)
+	assert.Equal(t, count+1, appender.batchEntries.Count())
+	assert.Equal(t, sum+100, appender.batchEntries.Sum())
+	assert.Equal(t, small, appender.batchEntries.Bucket(64))
+	assert.Equal(t, large+1, appender.batchEntries.Bucket(256))
+	assert.Equal(t, bytes+int64(size), appender.batchBytes.Sum())
+	assert.Equal(t, smallBytes, appender.batchBytes.Bucket(1024))
+
+	// Verify empty batches are recorded once the member is caught up
+	appender.nextIndex = 101
+	request = appender.entriesAppendRequest()
+	assert.Len(t, request.Entries, 0)
+	assert.Equal(t, count+2, appender.batchEntries.Count())
+	assert.Equal(t, sum+100, appender.batchEntries.Sum())
+}
+
+func benchmarkAppenderBatch(b *testing.B, parallelism int) {
+	appender := newTestMemberAppender(b, 50000, parallelism)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		appender.nextIndex = 1
+		appender.entriesAppendRequest()
+	}
+}
+
+func BenchmarkAppenderSerialBatch(b *testing.B) {
+	benchmarkAppenderBatch(b, 1)
+}
+
+func BenchmarkAppenderParallelBatch(b *testing.B) {
+	benchmarkAppenderBatch(b, maxParallelReads)
+}
+
+// warnLogger is a Logger that records warnings
+type warnLogger struct {
+	util.Logger
+	warnings []string
+	mu       sync.Mutex
+}
+
+func (l *warnLogger) Warn(message string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf(message, args...))
+}
+
+func (l *warnLogger) getWarnings() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.warnings...)
+}
+
+func TestAppenderFailureLogSampling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := 1 * time.Second
+	failureLogInterval := 200 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:    &electionTimeout,
+		FailureLogInterval: &failureLogInterval,
+	}
+	protocol, sm, store := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewMemoryStore(), config)
+	logger := &warnLogger{Logger: util.NewNodeLogger(string(protocol.Member()))}
+	appender := newMemberAppender(protocol, sm, store, logger, protocol.GetMember(raft.MemberID("baz")), nil, make(chan time.Time, 1))
+	appender.tickTicker.Stop()
+	close(appender.stopped)
+
+	failures := metrics.NewCounter("raft_append_failures_total", "baz")
+	initialFailures := failures.Value()
+
+	// Verify only the first of many consecutive failures is logged, but every failure is counted
+	request := &raft.AppendRequest{Term: raft.Term(1), Leader: protocol.Member()}
+	for i := 0; i < 1000; i++ {
+		appender.handleAppendError(request, errors.New("connection refused"), time.Now())
+	}
+	assert.Equal(t, initialFailures+1000, failures.Value())
+	warnings := logger.getWarnings()
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "Failed to append entries to baz: connection refused", warnings[0])
+
+	// Verify the next failure after the interval is logged with the number of failures that were not logged
+	time.Sleep(failureLogInterval)
+	appender.handleAppendError(request, errors.New("connection refused"), time.Now())
+	assert.Equal(t, initialFailures+1001, failures.Value())
+	warnings = logger.getWarnings()
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, "Failed to append entries to baz: connection refused (999 similar failures not logged)", warnings[1])
+}
+
+func TestAppenderWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+
+	// Block the first two appends to bar indefinitely to simulate stuck RPCs occupying all of the member's workers
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			<-release
+			return nil, errors.New("AppendRequest failed")
+		}).Times(2)
+
+	recovered := make(chan struct{}, 1)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			select {
+			case recovered <- struct{}{}:
+			default:
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	resets := metrics.NewCounter("raft_append_watchdog_resets_total", "bar")
+	initialResets := resets.Value()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	// Verify the stuck appends are abandoned and appends to the member resume
+	select {
+	case <-recovered:
+	case <-time.After(10 * time.Second):
+		t.Fatal("appender was not recovered")
+	}
+	assert.True(t, resets.Value() >= initialResets+2)
+
+	// Verify the responses from the abandoned appends are discarded once they return
+	close(release)
+	time.Sleep(100 * time.Millisecond)
+	role.raft.ReadLock()
+	assert.Equal(t, role.raft.Member(), *role.raft.Leader())
+	role.raft.ReadUnlock()
+}
+
+func TestAppenderSlowAppend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+
+	// Respond to appends to bar successfully, but only after the slow append threshold has passed
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			time.Sleep(100 * time.Millisecond)
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	failures := metrics.NewCounter("raft_append_failures_total", "bar")
+	initialFailures := failures.Value()
+	slowAppends := metrics.NewCounter("raft_slow_appends_total", "bar")
+	initialSlowAppends := slowAppends.Value()
+
+	electionTimeout := 1 * time.Second
+	slowAppendThreshold := 50 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:     &electionTimeout,
+		SlowAppendThreshold: &slowAppendThreshold,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newLeaderRole(protocol, sm, store).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	// Verify the slow member is reported degraded once its append completes
+	var progress map[raft.MemberID]*raft.MemberProgress
+	for i := 0; i < 50; i++ {
+		progress = make(map[raft.MemberID]*raft.MemberProgress)
+		for _, member := range role.Progress() {
+			progress[member.MemberID] = member
+		}
+		if progress[raft.MemberID("bar")].MatchIndex == 1 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, raft.Index(1), progress[raft.MemberID("bar")].MatchIndex)
+	assert.True(t, progress[raft.MemberID("bar")].Degraded)
+	assert.False(t, progress[raft.MemberID("baz")].Degraded)
+	assert.True(t, slowAppends.Value() > initialSlowAppends)
+
+	// Verify the slow member is not failed and the leader keeps its leadership
+	assert.Equal(t, initialFailures, failures.Value())
+	role.raft.ReadLock()
+	assert.Equal(t, role.raft.Member(), *role.raft.Leader())
+	role.raft.ReadUnlock()
+}
+
+func TestAppenderCommitStall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Block appends to all followers until released once blocking is enabled
+	var blocking int32
+	release := make(chan struct{})
+	blockAppends(client, &blocking, release).AnyTimes()
+
+	stalls := metrics.NewCounter("raft_commit_stalls_total", "foo")
+	initialStalls := stalls.Value()
+
+	electionTimeout := 1 * time.Second
+	commitStallThreshold := 200 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:      &electionTimeout,
+		CommitStallThreshold: &commitStallThreshold,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	events := make(chan raft.Event, 10)
+	protocol.Watch(func(event raft.Event) {
+		if event.Type == raft.EventTypeCommitStall {
+			events <- event
+		}
+	})
+	role := newLeaderRole(protocol, sm, store).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	// Block commits and propose a command
+	atomic.StoreInt32(&blocking, 1)
+	proposeTime := time.Now()
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		_ = role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch)
+	}()
+
+	// Verify the stall event fires once the threshold has passed
+	select {
+	case event := <-events:
+		assert.True(t, event.CommitStalled)
+		assert.True(t, time.Since(proposeTime) >= commitStallThreshold)
+	case <-time.After(electionTimeout):
+		assert.Fail(t, "commit stall not detected")
+	}
+	assert.True(t, stalls.Value() > initialStalls)
+	role.raft.ReadLock()
+	assert.True(t, role.raft.CommitStalled())
+	role.raft.ReadUnlock()
+
+	// Resume commits and verify the stall is cleared
+	atomic.StoreInt32(&blocking, 0)
+	close(release)
+	select {
+	case event := <-events:
+		assert.False(t, event.CommitStalled)
+	case <-time.After(electionTimeout):
+		assert.Fail(t, "commit stall not cleared")
+	}
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	role.raft.ReadLock()
+	assert.False(t, role.raft.CommitStalled())
+	role.raft.ReadUnlock()
+}
+
+func TestAppenderStopWhileAppending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	protocol, sm, store := newTestState(client)
+
+	// Use channels with no consumers to verify a stopped member never blocks sending events
+	commitCh := make(chan memberCommit)
+	failCh := make(chan time.Time)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+
+	// Stop the member while the append request is in flight
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			appender.stop()
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			return nil, errors.New("AppendRequest failed")
+		})
+
+	// Verify the late responses are dropped without panicking or blocking
+	for i := 0; i < 2; i++ {
+		done := make(chan struct{})
+		go func() {
+			appender.append()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("append blocked after the member was stopped")
+		}
+	}
+}
+
+func TestAppenderInstallTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	defer quietLogs()()
+
+	electionTimeout := 100 * time.Millisecond
+	installTimeout := 2 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		InstallTimeout:  &installTimeout,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config)
+
+	// Write a snapshot large enough to be sent in several chunks
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write(make([]byte, maxBatchSize*4))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Install the snapshot slowly enough that it takes several election timeouts to complete
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				for range requestCh {
+					time.Sleep(electionTimeout * 2)
+				}
+				if ctx.Err() != nil {
+					responseCh <- raft.NewInstallStreamResponse(nil, ctx.Err())
+				} else {
+					responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+						Status: raft.ResponseStatus_OK,
+					}, nil)
+				}
+			}()
+			return requestCh, responseCh, nil
+		})
+
+	resets := metrics.NewCounter("raft_append_watchdog_resets_total", "bar")
+	initialResets := resets.Value()
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	go appender.start()
+	defer appender.stop()
+
+	// Verify the install completes rather than being cancelled or reset by the watchdog
+	select {
+	case commit := <-commitCh:
+		assert.Equal(t, raft.Index(10), commit.index)
+	case <-failCh:
+		t.Fatal("snapshot install failed")
+	case <-time.After(10 * time.Second):
+		t.Fatal("snapshot install did not complete")
+	}
+	assert.Equal(t, initialResets, resets.Value())
+}
+
+func TestAppenderInstallMetadata(t *testing.T) {
+	defer quietLogs()()
+
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+	protocol, sm, store := newTestState(client)
+
+	// Take a snapshot with application-defined metadata on the leader
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), []byte("schema-v2"))
+	writer := snapshot.Writer()
+	_, err := writer.Write(make([]byte, maxBatchSize*2))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Install the snapshot on a follower by relaying the leader's install requests to the follower's role
+	followerProtocol, followerSM, followerStore := newTestState(mock.NewMockClient(ctrl))
+	follower := newPassiveRole(followerProtocol, followerSM, followerStore, util.NewNodeLogger("bar"))
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				streamCh := make(chan *raft.InstallStreamRequest)
+				go func() {
+					defer close(streamCh)
+					for request := range requestCh {
+						streamCh <- raft.NewInstallStreamRequest(request, nil)
+					}
+				}()
+				response, err := follower.Install(streamCh)
+				responseCh <- raft.NewInstallStreamResponse(response, err)
+			}()
+			return requestCh, responseCh, nil
+		})
+
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	go appender.start()
+	defer appender.stop()
+
+	select {
+	case commit := <-commitCh:
+		assert.Equal(t, raft.Index(10), commit.index)
+	case <-failCh:
+		t.Fatal("snapshot install failed")
+	case <-time.After(10 * time.Second):
+		t.Fatal("snapshot install did not complete")
+	}
+
+	// Verify the follower retained the snapshot's metadata
+	installed := followerStore.Snapshot().CurrentSnapshot()
+	if assert.NotNil(t, installed) {
+		assert.Equal(t, raft.Index(10), installed.Index())
+		assert.Equal(t, []byte("schema-v2"), installed.Metadata())
+	}
+}
+
+func TestAppenderInstallSuperseded(t *testing.T) {
+	defer quietLogs()()
+
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config)
+
+	// Write a snapshot large enough to be sent in several chunks
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write(make([]byte, maxBatchSize*4))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Simulate a member that stores a snapshot only once the stream is completed without being cancelled,
+	// pausing the first install after its first chunk is received
+	type install struct {
+		index raft.Index
+		data  []byte
+	}
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	installed := make(chan install, 10)
+	var installs int32
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			first := atomic.AddInt32(&installs, 1) == 1
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				var index raft.Index
+				var data []byte
+				for request := range requestCh {
+					index = request.Index
+					data = append(data, request.Data...)
+					if first && len(data) == len(request.Data) {
+						close(started)
+						<-proceed
+					}
+				}
+				if ctx.Err() != nil {
+					responseCh <- raft.NewInstallStreamResponse(nil, ctx.Err())
+				} else {
+					installed <- install{index: index, data: data}
+					responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+						Status: raft.ResponseStatus_OK,
+					}, nil)
+				}
+			}()
+			return requestCh, responseCh, nil
+		}).
+		AnyTimes()
+
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	go appender.start()
+	defer appender.stop()
+
+	// Take a newer snapshot while the first install is in progress
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot install did not start")
+	}
+	snapshot = store.Snapshot().NewSnapshot(raft.Index(20), raft.Term(1), time.Now(), nil)
+	writer = snapshot.Writer()
+	_, err = writer.Write([]byte("bar"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	close(proceed)
+
+	// Verify the first install is cancelled and the member ends up with the newer snapshot
+	select {
+	case install := <-installed:
+		assert.Equal(t, raft.Index(20), install.index)
+		assert.Equal(t, []byte("bar"), install.data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("newer snapshot was not installed")
+	}
+	select {
+	case commit := <-commitCh:
+		assert.Equal(t, raft.Index(20), commit.index)
+	case <-failCh:
+		t.Fatal("snapshot install failed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot install did not complete")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&installs))
+}
+
+func TestAppenderInstallRestartLimit(t *testing.T) {
+	appender := newTestMemberAppender(t, 0, 1)
+	older := appender.store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), nil)
+	assert.NoError(t, older.Writer().Close())
+	newer := appender.store.Snapshot().NewSnapshot(raft.Index(20), raft.Term(1), time.Now(), nil)
+	assert.NoError(t, newer.Writer().Close())
+
+	// Verify an install is superseded by a newer snapshot until it has been restarted the maximum number of times
+	assert.True(t, appender.installSuperseded(older))
+	assert.False(t, appender.installSuperseded(newer))
+	atomic.StoreInt32(&appender.installRestarts, maxInstallRestarts)
+	assert.False(t, appender.installSuperseded(older))
+}
+
+func TestAppenderCommitBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:    &electionTimeout,
+		MaxCommitBatchSize: 10,
+	}
+	protocol, sm, store := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewMemoryStore(), config)
+	for i := 0; i < 10000; i++ {
+		appendTestEntry(protocol, store, raft.Term(1))
+	}
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+
+	// Read the commit index concurrently with a large commit
+	started := make(chan struct{})
+	stop := make(chan struct{})
+	done := make(chan []raft.Index)
+	go func() {
+		var indexes []raft.Index
+		close(started)
+		for {
+			select {
+			case <-stop:
+				done <- indexes
+				return
+			default:
+			}
+			protocol.ReadLock()
+			index := protocol.CommitIndex()
+			protocol.ReadUnlock()
+			if index > 0 && index < 10000 && (len(indexes) == 0 || indexes[len(indexes)-1] != index) {
+				indexes = append(indexes, index)
+			}
+			runtime.Gosched()
+		}
+	}()
+	<-started
+
+	appender.commitMemberIndex(raft.MemberID("bar"), raft.Index(10000))
+	close(stop)
+
+	// Verify the lock was released between batches, allowing reads to observe the commit in progress
+	indexes := <-done
+	for _, index := range indexes {
+		assert.Equal(t, raft.Index(0), index%10)
+	}
+	assert.NotEmpty(t, indexes)
+	assert.Equal(t, raft.Index(10000), protocol.CommitIndex())
+}
+
+func TestAppenderRetainedTail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	protocol, sm, store := newTestState(client)
+	for i := 0; i < 10; i++ {
+		appendTestEntry(protocol, store, raft.Term(1))
+	}
+
+	// Take a snapshot at index 8 and compact the log, retaining a tail of entries from index 6
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(8), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	store.Writer().Compact(raft.Index(6))
+
+	// Verify a member lagging within the retained tail is caught up with appends
+	appends := make(chan *raft.AppendRequest, 10)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			appends <- request
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	bar := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	bar.nextIndex = 7
+	go bar.start()
+	defer bar.stop()
+	request := <-appends
+	assert.Equal(t, raft.Index(6), request.PrevLogIndex)
+	assert.Equal(t, raft.Term(1), request.PrevLogTerm)
+	assert.Len(t, request.Entries, 4)
+	assert.Equal(t, raft.Index(10), (<-commitCh).index)
+
+	// Verify a member lagging beyond the retained tail is sent the snapshot
+	installs := make(chan []byte, 1)
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("baz")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				var data []byte
+				for request := range requestCh {
+					data = append(data, request.Data...)
+				}
+				installs <- data
+				responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+					Status: raft.ResponseStatus_OK,
+				}, nil)
+			}()
+			return requestCh, responseCh, nil
+		})
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+	baz := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("baz")), commitCh, failCh)
+	baz.nextIndex = 3
+	go baz.start()
+	defer baz.stop()
+	select {
+	case data := <-installs:
+		assert.Equal(t, "foo", string(data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot was not installed")
+	}
+}
+
+func TestAppenderLogGap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	electionTimeout := 200 * time.Millisecond
+	newCompactedState := func() (raft.Raft, state.Manager, store.Store) {
+		protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{ElectionTimeout: &electionTimeout})
+		for i := 0; i < 10; i++ {
+			appendTestEntry(protocol, store, raft.Term(1))
+		}
+		store.Writer().Compact(raft.Index(6))
+		return protocol, sm, store
+	}
+
+	// Compact the log without taking a snapshot that covers the compacted entries
+	protocol, sm, store := newCompactedState()
+
+	// Verify a member needing a compacted entry is reported rather than sent empty appends in a loop
+	var appends int32
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			atomic.AddInt32(&appends, 1)
+			assert.Len(t, request.Entries, 0)
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    false,
+				LastLogIndex: raft.Index(2),
+			}, nil
+		}).AnyTimes()
+	logGaps := metrics.NewCounter("raft_append_log_gaps_total", "bar")
+	initialLogGaps := logGaps.Value()
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	bar := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	bar.nextIndex = 3
+	go bar.start()
+	time.Sleep(500 * time.Millisecond)
+	bar.stop()
+	assert.True(t, logGaps.Value() > initialLogGaps)
+	assert.True(t, atomic.LoadInt32(&appends) > 0)
+	assert.True(t, atomic.LoadInt32(&appends) <= 10)
+
+	// Compact the log and take a snapshot covering the compacted entries
+	protocol, sm, store = newCompactedState()
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(8), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Verify a member that lost the snapshot it was already sent is sent the snapshot again
+	installs := make(chan []byte, 1)
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("baz")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				var data []byte
+				for request := range requestCh {
+					data = append(data, request.Data...)
+				}
+				installs <- data
+				responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+					Status: raft.ResponseStatus_OK,
+				}, nil)
+			}()
+			return requestCh, responseCh, nil
+		})
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+	baz := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("baz")), commitCh, failCh)
+	baz.snapshotIndex = 8
+	baz.nextIndex = 3
+	go baz.start()
+	defer baz.stop()
+	select {
+	case data := <-installs:
+		assert.Equal(t, "foo", string(data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot was not installed")
+	}
+}
+
+// awaitMemberCommit drains commits from the given channel until the given index is committed
+func awaitMemberCommit(commitCh <-chan memberCommit, index raft.Index, timeout time.Duration) bool {
+	timer := time.After(timeout)
+	for {
+		select {
+		case commit := <-commitCh:
+			if commit.index >= index {
+				return true
+			}
+		case <-timer:
+			return false
+		}
+	}
+}
+
+func TestAppenderFlapping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	electionTimeout := 100 * time.Millisecond
+	flapBackoff := time.Second
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		FlapThreshold:   2,
+		FlapBackoff:     &flapBackoff,
+	})
+	for i := 0; i < 5; i++ {
+		appendTestEntry(protocol, store, raft.Term(1))
+	}
+
+	// Fail every other append to a flapping member, acknowledging no entries in between
+	var flapping int32 = 1
+	var appends int32
+	entriesCh := make(chan time.Time, 1)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			if atomic.LoadInt32(&flapping) == 1 {
+				if atomic.AddInt32(&appends, 1)%2 == 0 {
+					return nil, errors.New("unavailable")
+				}
+				return &raft.AppendResponse{
+					Status:       raft.ResponseStatus_OK,
+					Term:         request.Term,
+					Succeeded:    false,
+					LastLogIndex: request.PrevLogIndex,
+				}, nil
+			}
+			if len(request.Entries) > 0 {
+				select {
+				case entriesCh <- time.Now():
+				default:
+				}
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+	barBackoffs := metrics.NewCounter("raft_append_flap_backoffs_total", "bar")
+	initialBarBackoffs := barBackoffs.Value()
+	barCommitCh := make(chan memberCommit, 100)
+	failCh := make(chan time.Time, 100)
+	bar := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), barCommitCh, failCh)
+	bar.nextIndex = 1
+	go bar.start()
+	defer bar.stop()
+
+	// Verify the flapping member triggers the stabilization backoff
+	deadline := time.Now().Add(5 * time.Second)
+	for barBackoffs.Value() == initialBarBackoffs && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, barBackoffs.Value() > initialBarBackoffs)
+	stabilizeTime := time.Now()
+	atomic.StoreInt32(&flapping, 0)
+
+	// Verify the member is not sent entries until the backoff has elapsed even though it has recovered
+	select {
+	case entriesTime := <-entriesCh:
+		assert.True(t, entriesTime.Sub(stabilizeTime) > flapBackoff/2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("entries were not replicated after the backoff")
+	}
+	assert.True(t, awaitMemberCommit(barCommitCh, raft.Index(5), 5*time.Second))
+
+	// Verify a healthy member is replicated to immediately without a backoff
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+	bazBackoffs := metrics.NewCounter("raft_append_flap_backoffs_total", "baz")
+	initialBazBackoffs := bazBackoffs.Value()
+	bazCommitCh := make(chan memberCommit, 100)
+	baz := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("baz")), bazCommitCh, failCh)
+	baz.nextIndex = 1
+	go baz.start()
+	defer baz.stop()
+	assert.True(t, awaitMemberCommit(bazCommitCh, raft.Index(5), flapBackoff/2))
+	assert.Equal(t, initialBazBackoffs, bazBackoffs.Value())
+}
+
+func TestAppenderFlappingQuorum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := time.Second
+	protocol, sm, store := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewMemoryStore(), &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		FlapThreshold:   1,
+	})
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	bar := appender.members[raft.MemberID("bar")]
+	baz := appender.members[raft.MemberID("baz")]
+	bar.succeed()
+	baz.succeed()
+	bar.stabilizeUntil = time.Now().Add(time.Minute).UnixNano()
+
+	// Verify entries are withheld from a flapping member while the other voters can form a quorum
+	assert.False(t, appender.quorumNeeds(bar))
+	assert.True(t, bar.stabilizing())
+
+	// Verify entries are not withheld from a flapping member the leader needs to form a quorum
+	atomic.StoreInt64(&baz.lastResponseTime, time.Now().Add(-2*electionTimeout).UnixNano())
+	assert.True(t, appender.quorumNeeds(bar))
+	assert.False(t, bar.stabilizing())
+
+	// Verify a stabilizing member doesn't count toward the quorum
+	baz.succeed()
+	baz.stabilizeUntil = bar.stabilizeUntil
+	assert.True(t, appender.quorumNeeds(bar))
+	assert.False(t, bar.stabilizing())
+}
+
+func TestAppenderHeartbeatWithEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	electionTimeout := 100 * time.Millisecond
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{ElectionTimeout: &electionTimeout})
+	for i := 0; i < 20; i++ {
+		appendTestEntry(protocol, store, raft.Term(1))
+	}
+
+	// Acknowledge a single entry per request, slowly enough that several heartbeat ticks fire while entries are pending
+	var heartbeats int32
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			lastLogIndex := request.PrevLogIndex
+			if len(request.Entries) > 0 {
+				lastLogIndex++
+			} else if request.PrevLogIndex < raft.Index(20) {
+				atomic.AddInt32(&heartbeats, 1)
+			}
+			time.Sleep(10 * time.Millisecond)
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: lastLogIndex,
+			}, nil
+		}).AnyTimes()
+	commitCh := make(chan memberCommit, 100)
+	failCh := make(chan time.Time, 100)
+	bar := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	bar.nextIndex = 1
+	go bar.start()
+	defer bar.stop()
+
+	// Verify the entries serve as heartbeats: no empty request is sent while entries are pending
+	assert.True(t, awaitMemberCommit(commitCh, raft.Index(20), 5*time.Second))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&heartbeats))
+}
+
+func TestAppenderCommitCommittedEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol, sm, store := newTestState(mock.NewMockClient(ctrl))
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+
+	// Commit an entry before its commit is registered, as when it's replicated from the log by a member appender
+	entry := appendTestEntry(protocol, store, raft.Term(1))
+	appender.commitMemberIndex(raft.MemberID("bar"), entry.Index)
+	assert.Equal(t, entry.Index, protocol.CommitIndex())
+
+	// Verify the commit completes immediately and the entry is applied
+	applied := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- appender.commit(entry, func() {
+			applied <- struct{}{}
+		})
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "commit of a committed entry did not complete")
+	}
+	select {
+	case <-applied:
+	default:
+		assert.Fail(t, "committed entry was not applied")
+	}
+}
+
+func TestAppenderMaxPendingHeartbeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			<-release
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 200 * time.Millisecond
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{
+		ElectionTimeout:      &electionTimeout,
+		MaxPendingHeartbeats: 2,
+	})
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	go appender.start()
+	defer appender.stop(nil)
+
+	// Saturate the pending heartbeats while the followers are unresponsive
+	depth := metrics.NewGauge("raft_pending_heartbeats", string(protocol.Member()))
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- appender.heartbeat()
+		}()
+	}
+	for depth.Value() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Verify further heartbeats are rejected until the pending heartbeats drain
+	assert.Equal(t, raft.ErrOverloaded, appender.heartbeat())
+	close(release)
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("pending heartbeats were not confirmed")
+		}
+	}
+	assert.Equal(t, int64(0), depth.Value())
+	assert.NoError(t, appender.heartbeat())
+}
+
+func TestAppenderElectionTimeoutChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	var appends int32
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			atomic.AddInt32(&appends, 1)
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	heartbeatInterval := 10 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	// Verify idle members are not sent heartbeats within half the initial election timeout
+	time.Sleep(100 * time.Millisecond)
+	idle := atomic.LoadInt32(&appends)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, idle, atomic.LoadInt32(&appends))
+
+	// Verify idle members are sent heartbeats at the interval derived from the updated timeout
+	role.raft.WriteLock()
+	role.ElectionTimeoutChanged(100 * time.Millisecond)
+	role.raft.WriteUnlock()
+	assert.Equal(t, int64(leaseDuration(100*time.Millisecond)), atomic.LoadInt64(&role.appender.lease.duration))
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, atomic.LoadInt32(&appends)-idle >= 6)
+}
+
+func TestAppenderCommitBatchesStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:    &electionTimeout,
+		MaxCommitBatchSize: 10,
+	}
+	protocol, sm, store := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewMemoryStore(), config)
+	for i := 0; i < 10000; i++ {
+		appendTestEntry(protocol, store, raft.Term(1))
+	}
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	completed := false
+	appender.commitFutures[raft.Index(10000)] = func() {
+		completed = true
+	}
+
+	// Stop the appender between batches of a large commit
+	started := make(chan struct{})
+	done := make(chan raft.Index)
+	go func() {
+		close(started)
+		for {
+			protocol.WriteLock()
+			index := protocol.CommitIndex()
+			if index == 10000 {
+				protocol.WriteUnlock()
+				done <- index
+				return
+			} else if index > 0 {
+				appender.mu.Lock()
+				appender.closed = true
+				appender.mu.Unlock()
+				protocol.WriteUnlock()
+				done <- index
+				return
+			}
+			protocol.WriteUnlock()
+			runtime.Gosched()
+		}
+	}()
+	<-started
+
+	appender.commitMemberIndex(raft.MemberID("bar"), raft.Index(10000))
+
+	// Verify no further entries were committed once the appender was stopped
+	index := <-done
+	assert.Equal(t, index, protocol.CommitIndex())
+	assert.False(t, completed)
+}
+
+// testRaftServiceClient is a RaftServiceClient that accepts all append requests
+type testRaftServiceClient struct {
+	raft.RaftServiceClient
+}
+
+func (c *testRaftServiceClient) Append(ctx context.Context, request *raft.AppendRequest, opts ...grpc.CallOption) (*raft.AppendResponse, error) {
+	return &raft.AppendResponse{
+		Status:       raft.ResponseStatus_OK,
+		Term:         request.Term,
+		Succeeded:    true,
+		LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+	}, nil
+}
+
+func TestAppenderClientUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Fail to create a client for the member until it becomes available
+	failures := 5
+	cluster := mock.NewMockCluster(ctrl)
+	cluster.EXPECT().
+		GetClient(raft.MemberID("bar")).
+		Return(nil, errors.New("connection refused")).
+		Times(failures)
+	cluster.EXPECT().
+		GetClient(raft.MemberID("bar")).
+		Return(&testRaftServiceClient{}, nil).
+		AnyTimes()
+
+	electionTimeout := 100 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, store := newTestStateWithStore(raft.NewClient(cluster), store.NewMemoryStore(), config)
+	appendTestEntry(protocol, store, raft.Term(1))
+
+	appendFailures := metrics.NewCounter("raft_append_failures_total", "bar")
+	initialFailures := appendFailures.Value()
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	go appender.start()
+	defer appender.stop()
+
+	// Verify each client creation failure is counted as a failed append
+	for i := 0; i < failures; i++ {
+		select {
+		case <-failCh:
+		case <-time.After(10 * time.Second):
+			t.Fatal("client failure was not reported")
+		}
+	}
+	assert.Equal(t, initialFailures+uint64(failures), appendFailures.Value())
+
+	// Verify the member recovers and catches up once the client becomes available
+	for {
+		select {
+		case commit := <-commitCh:
+			if commit.index == raft.Index(1) {
+				assert.Equal(t, raft.Index(1), appender.getProgress().MatchIndex)
+				return
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("member did not recover")
+		}
+	}
+}
+
+// newSingleNodeTestState returns the state of a single node cluster
+func newSingleNodeTestState(client raft.Client) (raft.Raft, state.Manager, store.Store) {
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	members := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5000,
+			},
+		},
+	}
+	store := store.NewMemoryStore()
+	cluster := raft.NewCluster(members)
+	state := state.NewManager(cluster.Member(), store, node.GetRegistry(), config)
+	return raft.NewRaft(cluster, config, client, newRoleFuncs()), state, store
+}
+
+// appendTestEntry appends an entry in the given term to the log
+func appendTestEntry(protocol raft.Raft, store store.Store, term raft.Term) *log.Entry {
+	protocol.WriteLock()
+	defer protocol.WriteUnlock()
+	return store.Writer().Append(&raft.LogEntry{
+		Term:      term,
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Barrier{
+			Barrier: &raft.BarrierEntry{},
+		},
+	})
+}
+
+func TestAppenderProbeOnRecovery(t *testing.T) {
+	tests := []struct {
+		name            string
+		probeOnRecovery bool
+		signal          func(*memberAppender)
+		resumed         bool
+	}{
+		{
+			name:            "heartbeat",
+			probeOnRecovery: true,
+			signal: func(appender *memberAppender) {
+				appender.heartbeatCh <- time.Now()
+			},
+			resumed: true,
+		},
+		{
+			name:            "probe",
+			probeOnRecovery: true,
+			signal: func(appender *memberAppender) {
+				appender.probe()
+			},
+			resumed: true,
+		},
+		{
+			name:            "disabled",
+			probeOnRecovery: false,
+			signal: func(appender *memberAppender) {
+				appender.heartbeatCh <- time.Now()
+			},
+			resumed: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mock.NewMockClient(ctrl)
+			requests := make(chan *raft.AppendRequest, 10)
+			client.EXPECT().
+				Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+				DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+					requests <- request
+					return &raft.AppendResponse{
+						Status:       raft.ResponseStatus_OK,
+						Term:         request.Term,
+						Succeeded:    true,
+						LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+					}, nil
+				}).AnyTimes()
+
+			electionTimeout := 1 * time.Second
+			config := &config.ProtocolConfig{
+				ElectionTimeout: &electionTimeout,
+				ProbeOnRecovery: test.probeOnRecovery,
+			}
+			protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config)
+			assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+
+			// Simulate a member that has recovered after being backed off following repeated failures
+			commitCh := make(chan memberCommit, 10)
+			failCh := make(chan time.Time, 10)
+			appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+			appender.failureCount = minBackoffFailureCount * 3
+			appender.firstFailureTime = time.Now()
+			go appender.start()
+			defer appender.stop()
+			for i := 0; i < 3; i++ {
+				appendTestEntry(protocol, store, raft.Term(1))
+			}
+			test.signal(appender)
+
+			if !test.resumed {
+				// Verify the member is not sent any requests until the backoff has elapsed
+				select {
+				case request := <-requests:
+					assert.Fail(t, "unexpected append during backoff", "%v", request)
+				case <-time.After(electionTimeout):
+				}
+				return
+			}
+
+			// Verify the member is probed with a heartbeat, and replication resumes once the heartbeat succeeds
+			select {
+			case request := <-requests:
+				assert.Len(t, request.Entries, 0)
+			case <-time.After(5 * time.Second):
+				t.Fatal("member was not probed")
+			}
+			select {
+			case request := <-requests:
+				assert.Len(t, request.Entries, 3)
+			case <-time.After(5 * time.Second):
+				t.Fatal("replication did not resume")
+			}
+			for {
+				select {
+				case commit := <-commitCh:
+					if commit.index == raft.Index(3) {
+						return
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("entries were not committed")
+				}
+			}
+		})
+	}
+}
+
+// queuedIndexes returns the indexes of the entries cached by the given appender
+func queuedIndexes(appender *memberAppender) []raft.Index {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	indexes := make([]raft.Index, 0, appender.queue.len())
+	for element := appender.queue.entries.Front(); element != nil; element = element.Next() {
+		indexes = append(indexes, element.Value.(*queuedEntry).index)
+	}
+	return indexes
+}
+
+func TestAppenderRecoveryCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{})
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+
+	// Fail the first append, then block the retry until the member recovers and block further appends once it has
+	var appends int32
+	failed := make(chan struct{})
+	recovering := make(chan struct{})
+	recovered := make(chan struct{})
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			switch atomic.AddInt32(&appends, 1) {
+			case 1:
+				return nil, errors.New("unavailable")
+			case 2:
+				close(failed)
+				<-recovering
+			case 3:
+				close(recovered)
+				<-release
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	appender := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	go appender.start()
+	defer appender.stop()
+	defer close(release)
+	appender.entryCh <- appendTestEntry(protocol, store, raft.Term(1))
+
+	// Verify entries arriving while the member is failing are not cached
+	<-failed
+	appender.entryCh <- appendTestEntry(protocol, store, raft.Term(1))
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, queuedIndexes(appender), 0)
+
+	// Verify entries arriving once the member has recovered are cached
+	close(recovering)
+	<-recovered
+	entry := appendTestEntry(protocol, store, raft.Term(1))
+	appender.entryCh <- entry
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []raft.Index{entry.Index}, queuedIndexes(appender))
+}
+
+func TestAppenderCommitNotLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	// Verify a single node leader commits entries while it's the leader
+	protocol, sm, store := newSingleNodeTestState(client)
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+	entry := appendTestEntry(protocol, store, raft.Term(1))
+	assert.NoError(t, appender.commit(entry, nil))
+	assert.Equal(t, entry.Index, protocol.CommitIndex())
+
+	// Verify entries appended before the leader stepped down are not committed
+	entry = appendTestEntry(protocol, store, raft.Term(1))
+	assert.NoError(t, protocol.SetTerm(raft.Term(2)))
+	assert.NoError(t, protocol.SetLeader(nil))
+	err := appender.commit(entry, func() {
+		t.Fatal("entry committed after the leader stepped down")
+	})
+	notLeader, ok := err.(*raft.ErrNotLeader)
+	assert.True(t, ok)
+	assert.Equal(t, raft.MemberID(""), notLeader.Leader)
+	assert.Equal(t, entry.Index-1, protocol.CommitIndex())
+
+	// Verify entries from a prior term are not committed after the member is re-elected
+	assert.NoError(t, protocol.SetLeader(&foo))
+	assert.IsType(t, &raft.ErrNotLeader{}, appender.commit(entry, nil))
+	assert.Equal(t, entry.Index-1, protocol.CommitIndex())
+
+	// Verify a leader that stepped down rejects entries with the new leader
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	<-role.Ready()
+	entry = appendTestEntry(role.raft, role.store, raft.Term(1))
+	bar := raft.MemberID("bar")
+	role.raft.WriteLock()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	assert.NoError(t, role.raft.SetLeader(&bar))
+	role.raft.WriteUnlock()
+	err = role.appender.commit(entry, nil)
+	notLeader, ok = err.(*raft.ErrNotLeader)
+	assert.True(t, ok)
+	assert.Equal(t, bar, notLeader.Leader)
+	assert.True(t, role.raft.CommitIndex() < entry.Index)
+}
+
+func newQuorumHealthTestLeader(ctrl *gomock.Controller, client *mock.MockClient) *LeaderRole {
+	electionTimeout := 1 * time.Second
+	healthInterval := 100 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:      &electionTimeout,
+		QuorumHealthInterval: &healthInterval,
+	}
+	return newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+}
+
+func awaitQuorumHealth(appender *raftAppender, responsive int) *quorumHealth {
+	for i := 0; i < 50; i++ {
+		health := appender.getQuorumHealth()
+		if health != nil && health.responsive == responsive {
+			return health
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return appender.getQuorumHealth()
+}
+
+func TestAppenderQuorumHealthy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	role := newQuorumHealthTestLeader(ctrl, client)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	health := awaitQuorumHealth(role.appender, 3)
+	assert.NotNil(t, health)
+	assert.Equal(t, 3, health.responsive)
+	assert.Equal(t, 3, health.members)
+	assert.True(t, health.available())
+	assert.False(t, health.degraded())
+}
+
+func TestAppenderQuorumDegraded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppendTo(client, raft.MemberID("baz")).AnyTimes()
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		Return(nil, errors.New("AppendRequest failed")).
+		AnyTimes()
+
+	role := newQuorumHealthTestLeader(ctrl, client)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	health := awaitQuorumHealth(role.appender, 2)
+	assert.NotNil(t, health)
+	assert.Equal(t, 2, health.responsive)
+	assert.Equal(t, 3, health.members)
+	assert.True(t, health.available())
+	assert.True(t, health.degraded())
+}
+
+// testFollowerLogs tracks the last index of each follower's log and the number of rejected appends
+type testFollowerLogs struct {
+	lastIndexes map[raft.MemberID]raft.Index
+	rejections  int
+	mu          sync.Mutex
+}
+
+func (l *testFollowerLogs) append(request *raft.AppendRequest, member raft.MemberID) *raft.AppendResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lastIndex := l.lastIndexes[member]
+	if request.PrevLogIndex > lastIndex {
+		l.rejections++
+		return &raft.AppendResponse{
+			Status:       raft.ResponseStatus_OK,
+			Term:         request.Term,
+			Succeeded:    false,
+			LastLogIndex: lastIndex,
+		}
+	}
+	lastIndex = request.PrevLogIndex + raft.Index(len(request.Entries))
+	if len(request.Entries) > 0 {
+		l.lastIndexes[member] = lastIndex
+	}
+	return &raft.AppendResponse{
+		Status:       raft.ResponseStatus_OK,
+		Term:         request.Term,
+		Succeeded:    true,
+		LastLogIndex: lastIndex,
+	}
+}
+
+func (l *testFollowerLogs) await(t *testing.T, index raft.Index) int {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.mu.Lock()
+		caughtUp := true
+		for _, lastIndex := range l.lastIndexes {
+			if lastIndex < index {
+				caughtUp = false
+			}
+		}
+		rejections := l.rejections
+		l.mu.Unlock()
+		if caughtUp {
+			return rejections
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("followers did not catch up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// restartLeaderRejections restarts a leader with unreplicated entries and returns the number of rejected appends
+func restartLeaderRejections(t *testing.T, persist bool) int {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logs := &testFollowerLogs{
+		lastIndexes: map[raft.MemberID]raft.Index{
+			raft.MemberID("bar"): 0,
+			raft.MemberID("baz"): 0,
+		},
+	}
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			return logs.append(request, member), nil
+		}).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:            &electionTimeout,
+		PersistReplicationProgress: persist,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+
+	// Replicate the first leader's initialization entry to all followers
+	leader := newLeaderRole(protocol, sm, store).(*LeaderRole)
+	assert.NoError(t, leader.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, leader.Start())
+	logs.await(t, raft.Index(1))
+	assert.NoError(t, leader.Stop())
+
+	// Append entries that were not replicated before the leader restarted
+	for i := 0; i < 10; i++ {
+		store.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(1),
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{
+					Value: newOpenSessionRequest(),
+				},
+			},
+		})
+	}
+	rejections := logs.await(t, raft.Index(1))
+
+	// Restart the leader and verify it converges on the followers' logs
+	leader = newLeaderRole(protocol, sm, store).(*LeaderRole)
+	assert.NoError(t, leader.raft.SetTerm(raft.Term(2)))
+	assert.NoError(t, leader.Start())
+	defer leader.Stop()
+	return logs.await(t, raft.Index(12)) - rejections
+}
+
+func TestAppenderPersistReplicationProgress(t *testing.T) {
+	// Verify each follower rejects the first append when the leader restarts from the end of its log
+	assert.True(t, restartLeaderRejections(t, false) >= 2)
+
+	// Verify no appends are rejected when the leader restarts from the persisted match indexes
+	assert.Equal(t, 0, restartLeaderRejections(t, true))
+}
+
+func TestAppenderForceInstall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	protocol, sm, store := newTestState(client)
+
+	// Write entries that remain in the log after a snapshot is taken of them
+	for i := 1; i <= 20; i++ {
+		store.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(1),
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{
+					Value: []byte(fmt.Sprintf("command-%d", i)),
+				},
+			},
+		})
+	}
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(20), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Record any entries appended to the member and any snapshots installed on it
+	var appended int32
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			atomic.AddInt32(&appended, int32(len(request.Entries)))
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).
+		AnyTimes()
+	installed := make(chan raft.Index, 10)
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				var index raft.Index
+				for request := range requestCh {
+					index = request.Index
+				}
+				installed <- index
+				responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+					Status: raft.ResponseStatus_OK,
+				}, nil)
+			}()
+			return requestCh, responseCh, nil
+		}).
+		AnyTimes()
+
+	// A member lagging behind entries still in the log is normally caught up with appends
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	member := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	member.nextIndex = 2
+	assert.False(t, member.needsSnapshot(snapshot))
+
+	appender := &raftAppender{
+		members: map[raft.MemberID]*memberAppender{
+			"bar": member,
+		},
+	}
+	assert.Error(t, appender.forceInstall(raft.MemberID("baz")))
+	assert.NoError(t, appender.forceInstall(raft.MemberID("bar")))
+	go member.start()
+	defer member.stop()
+
+	// Verify the marked member is sent the snapshot rather than the entries it's missing
+	select {
+	case index := <-installed:
+		assert.Equal(t, raft.Index(20), index)
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot was not installed")
+	}
+	select {
+	case commit := <-commitCh:
+		assert.Equal(t, raft.Index(20), commit.index)
+	case <-failCh:
+		t.Fatal("snapshot install failed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot install did not complete")
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&appended))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&member.forcingInstall))
+}
+
+func TestAppenderCommitWithoutNewMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+
+	// Add a fresh member peer-3 to the cluster of foo, peer-1, and peer-2
+	protocol, sm, store := newLargeTestState(mock.NewMockClient(ctrl), config, 3)
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	assert.Equal(t, 3, appender.voters)
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+	appendTestEntry(protocol, store, raft.Term(1))
+	entry := appendTestEntry(protocol, store, raft.Term(1))
+
+	// A single established member and the leader are not a quorum of the four voters
+	appender.commitMemberIndex(raft.MemberID("peer-1"), entry.Index)
+	assert.Equal(t, raft.Index(0), protocol.CommitIndex())
+
+	// Verify the original members commit the entry before the new member has ever responded
+	appender.commitMemberIndex(raft.MemberID("peer-2"), entry.Index)
+	assert.Equal(t, entry.Index, protocol.CommitIndex())
+
+	// Verify the new member advances the commit index once it catches up with a lagging original member
+	next := appendTestEntry(protocol, store, raft.Term(1))
+	appender.commitMemberIndex(raft.MemberID("peer-1"), next.Index)
+	assert.Equal(t, entry.Index, protocol.CommitIndex())
+	appender.commitMemberIndex(raft.MemberID("peer-3"), next.Index)
+	assert.Equal(t, next.Index, protocol.CommitIndex())
+}
+
+func TestAppenderInstallClearsQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	protocol, sm, store := newTestState(client)
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	commitCh := make(chan memberCommit, 10)
+	failCh := make(chan time.Time, 10)
+	bar := newMemberAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())), protocol.GetMember(raft.MemberID("bar")), commitCh, failCh)
+	bar.nextIndex = 3
+
+	// Cache entries for a member lagging behind, then take a snapshot and compact the entries it needs from the log
+	for i := 0; i < 10; i++ {
+		entry := appendTestEntry(protocol, store, raft.Term(1))
+		if entry.Index >= bar.nextIndex {
+			bar.queue.push(entry)
+		}
+	}
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(8), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	store.Writer().Compact(raft.Index(8))
+
+	// Record the entries cached when the install starts and the appends sent to the member
+	queued := make(chan []raft.Index, 10)
+	client.EXPECT().
+		Install(gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, member raft.MemberID) (chan<- *raft.InstallRequest, <-chan *raft.InstallStreamResponse, error) {
+			queued <- queuedIndexes(bar)
+			requestCh := make(chan *raft.InstallRequest)
+			responseCh := make(chan *raft.InstallStreamResponse, 1)
+			go func() {
+				for range requestCh {
+				}
+				responseCh <- raft.NewInstallStreamResponse(&raft.InstallResponse{
+					Status: raft.ResponseStatus_OK,
+				}, nil)
+			}()
+			return requestCh, responseCh, nil
+		}).
+		AnyTimes()
+	appends := make(chan *raft.AppendRequest, 100)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			appends <- request
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).
+		AnyTimes()
+	go bar.start()
+	defer bar.stop()
+
+	// Verify the cached entries were dropped when the member switched to installing the snapshot
+	select {
+	case indexes := <-queued:
+		assert.Empty(t, indexes)
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot was not installed")
+	}
+	assert.True(t, awaitMemberCommit(commitCh, raft.Index(10), 5*time.Second))
+
+	// Verify no entries preceding the snapshot were appended once it was installed
+	entries := 0
+	for len(appends) > 0 {
+		request := <-appends
+		if len(request.Entries) > 0 {
+			assert.Equal(t, raft.Index(8), request.PrevLogIndex)
+			entries += len(request.Entries)
+		}
+	}
+	assert.Equal(t, 2, entries)
+}
+
+func TestAppenderBudgetHeartbeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Fail the first append to bar, then block appends to bar to hold the only place in the budget
+	var barAppends int32
+	var bazAppends int32
+	release := make(chan struct{})
+	defer close(release)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			if member == raft.MemberID("bar") {
+				if atomic.AddInt32(&barAppends, 1) == 1 {
+					return nil, errors.New("unavailable")
+				}
+				<-release
+			} else {
+				atomic.AddInt32(&bazAppends, 1)
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 200 * time.Millisecond
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{
+		ElectionTimeout:       &electionTimeout,
+		MaxReplicationWorkers: 1,
+	})
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	go appender.start()
+	defer appender.stop(nil)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&barAppends) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("budget was not saturated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Verify the healthy member keeps its heartbeat cadence while the lagging member holds the budget
+	start := atomic.LoadInt32(&bazAppends)
+	time.Sleep(5 * electionTimeout)
+	assert.True(t, atomic.LoadInt32(&bazAppends)-start >= 3)
+}
+
+func TestAppenderBudgetBound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Fail the first few appends to each member so both lag, tracking the appends sent concurrently once they do
+	var mu sync.Mutex
+	appends := make(map[raft.MemberID]int)
+	var inflight, maxInflight int
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			mu.Lock()
+			appends[member]++
+			count := appends[member]
+			if count == 1 {
+				mu.Unlock()
+				return nil, errors.New("unavailable")
+			}
+			lagging := count <= 4
+			if lagging {
+				inflight++
+				if inflight > maxInflight {
+					maxInflight = inflight
+				}
+			}
+			mu.Unlock()
+			if lagging {
+				time.Sleep(50 * time.Millisecond)
+				mu.Lock()
+				inflight--
+				mu.Unlock()
+				if count < 4 {
+					return nil, errors.New("unavailable")
+				}
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 200 * time.Millisecond
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), &config.ProtocolConfig{
+		ElectionTimeout:       &electionTimeout,
+		MaxReplicationWorkers: 1,
+	})
+	foo := raft.MemberID("foo")
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	assert.NoError(t, protocol.SetLeader(&foo))
+	appender := newAppender(protocol, sm, store, util.NewNodeLogger(string(protocol.Member())))
+	go appender.start()
+	defer appender.stop(nil)
+
+	// Verify both lagging members are caught up through the shared budget
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		mu.Lock()
+		done := appends[raft.MemberID("bar")] > 4 && appends[raft.MemberID("baz")] > 4
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("lagging members were not caught up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Verify appends to the lagging members were never sent concurrently
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, maxInflight)
+}
@@ -0,0 +1,105 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"context"
+	"github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"sync"
+)
+
+// newApplyQueue returns a new queue of at most the given number of entries to apply to the state machine
+func newApplyQueue(size int, member raft.MemberID) *applyQueue {
+	return &applyQueue{
+		worker: newWorkerPool(1),
+		slots:  make(chan struct{}, size),
+		closed: make(chan struct{}),
+		depth:  metrics.NewGauge("raft_apply_queue_depth", string(member)),
+	}
+}
+
+// applyQueue applies committed entries to the state machine on a dedicated goroutine
+// Entries are applied in the order in which they're pushed, so a slow state machine does not block the advancement
+// of the commit index. Proposers reserve a slot in the queue before appending an entry to the log and release it
+// once the entry has been applied, bounding the number of entries the commit index can advance ahead of the state
+// machine.
+type applyQueue struct {
+	worker *workerPool
+	slots  chan struct{}
+	closed chan struct{}
+	depth  *metrics.Gauge
+}
+
+// reserve blocks until a slot is available in the queue
+// It returns a bool indicating whether a slot was reserved, which is false if the queue was closed or the context
+// was done before a slot became available.
+func (q *applyQueue) reserve(ctx context.Context) bool {
+	select {
+	case q.slots <- struct{}{}:
+		return true
+	case <-q.closed:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release releases a reserved slot
+func (q *applyQueue) release() {
+	<-q.slots
+}
+
+// push enqueues the given function to apply a committed entry
+// Entries pushed once the queue is closed are dropped.
+func (q *applyQueue) push(f func()) {
+	q.depth.Add(1)
+	submitted := q.worker.submit(func() {
+		defer q.depth.Add(-1)
+		f()
+	})
+	if !submitted {
+		q.depth.Add(-1)
+	}
+}
+
+// close closes the queue
+// Entries already pushed to the queue are still applied.
+func (q *applyQueue) close() {
+	close(q.closed)
+	q.worker.close()
+}
+
+// newAppliedStream returns a stream that calls the given function once the entry writing to it has been applied
+func newAppliedStream(writer stream.WriteStream, applied func()) stream.WriteStream {
+	return &appliedStream{
+		WriteStream: writer,
+		applied:     applied,
+	}
+}
+
+// appliedStream is a stream that calls a function once the state manager has applied the entry writing to it
+// Entries may produce no output, or stream output after they're applied, so the stream's output is not used to
+// determine when the entry has been applied.
+type appliedStream struct {
+	stream.WriteStream
+	applied func()
+	once    sync.Once
+}
+
+func (s *appliedStream) Applied() {
+	s.once.Do(s.applied)
+}
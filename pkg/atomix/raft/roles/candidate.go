@@ -21,23 +21,28 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"math"
-	"math/rand"
 	"time"
 )
 
 // newCandidateRole returns a new candidate role
-func newCandidateRole(protocol raft.Raft, state state.Manager, store store.Store) raft.Role {
+func newCandidateRole(protocol raft.Raft, state state.Manager, store store.Store, random Random) raft.Role {
 	log := util.NewRoleLogger(string(protocol.Member()), string(raft.RoleCandidate))
 	return &CandidateRole{
 		ActiveRole: newActiveRole(protocol, state, store, log),
+		random:     random,
+		workers:    newWorkerPool(protocol.Config().GetMaxElectionWorkersOrDefault()),
 	}
 }
 
 // CandidateRole implements a Raft candidate
 type CandidateRole struct {
 	*ActiveRole
+	random          Random
+	tally           *electionTally
 	electionTimer   *time.Timer
 	electionExpired chan bool
+	// workers sends vote requests, bounding the number of requests in flight across elections
+	workers *workerPool
 }
 
 // Type is the role type
@@ -47,8 +52,8 @@ func (r *CandidateRole) Type() raft.RoleType {
 
 // Start starts the candidate
 func (r *CandidateRole) Start() error {
-	// If there are no other members in the cluster, immediately transition to leader.
-	if len(r.raft.Members()) == 1 {
+	// If there are no other voters in the cluster, immediately transition to leader.
+	if len(r.voters()) == 1 {
 		r.log.Debug("Single node cluster; skipping election")
 		r.raft.SetRole(raft.RoleLeader)
 		return nil
@@ -63,6 +68,7 @@ func (r *CandidateRole) Stop() error {
 	if r.electionTimer != nil && r.electionTimer.Stop() {
 		r.electionExpired <- true
 	}
+	r.workers.close()
 	return r.ActiveRole.Stop()
 }
 
@@ -92,11 +98,16 @@ func (r *CandidateRole) Vote(ctx context.Context, request *raft.VoteRequest) (*r
 		return response, nil
 	}
 
-	// Otherwise, reject it.
+	// Otherwise, reject it. Candidates have already voted for themselves in the current term.
+	rejection := raft.RejectionReason_ALREADY_VOTED
+	if request.Term < r.raft.Term() {
+		rejection = raft.RejectionReason_TERM_BEHIND
+	}
 	response := &raft.VoteResponse{
-		Status: raft.ResponseStatus_OK,
-		Term:   r.raft.Term(),
-		Voted:  false,
+		Status:    raft.ResponseStatus_OK,
+		Term:      r.raft.Term(),
+		Voted:     false,
+		Rejection: rejection,
 	}
 	_ = r.log.Response("VoteResponse", response, nil)
 	return response, nil
@@ -112,7 +123,7 @@ func (r *CandidateRole) resetElectionTimeout() {
 
 	// Set the election timeout in a semi-random fashion with the random range
 	// being election timeout and 2 * election timeout.
-	timeout := r.raft.Config().GetElectionTimeoutOrDefault() + time.Duration(rand.Int63n(int64(r.raft.Config().GetElectionTimeoutOrDefault())))
+	timeout := r.raft.Config().GetElectionTimeoutOrDefault() + time.Duration(r.random.Int63n(int64(r.raft.Config().GetElectionTimeoutOrDefault())))
 	r.electionTimer = time.NewTimer(timeout)
 	electionCh := r.electionTimer.C
 	r.electionExpired = make(chan bool, 1)
@@ -120,14 +131,17 @@ func (r *CandidateRole) resetElectionTimeout() {
 	go func() {
 		select {
 		case <-electionCh:
-			r.raft.ReadLock()
+			r.raft.WriteLock()
 			if r.active {
 				// When the election times out, clear the previous majority vote
 				// check and restart the election.
 				r.log.Debug("Election round for term %d expired: not enough votes received within the election timeout; restarting election", r.raft.Term())
+				if failure := r.tally.expire(); failure != nil {
+					r.raft.SetElectionFailure(failure)
+				}
 				go r.sendVoteRequests()
 			}
-			r.raft.ReadUnlock()
+			r.raft.WriteUnlock()
 		case <-expiredCh:
 			return
 		}
@@ -164,10 +178,19 @@ func (r *CandidateRole) sendVoteRequests() {
 		return
 	}
 	term := r.raft.Term()
+	handoff := r.raft.Handoff()
+	transfer := handoff != nil && handoff.Leader != member && handoff.Term+1 == term
+	votingMembers := r.voters()
+	tally := newElectionTally(term, len(votingMembers))
+	r.tally = tally
 	r.raft.WriteUnlock()
 
-	// Create a quorum that will track the number of nodes that have responded to the poll request.
-	votingMembers := r.raft.Members()
+	// Ensure the term and vote are durable before requesting votes. If the metadata cannot be synced, the
+	// election will be restarted once the election timer expires.
+	if err := r.raft.SyncMetadata(); err != nil {
+		r.log.Error("Failed to sync metadata", err)
+		return
+	}
 
 	// Compute the quorum and create a goroutine to count votes
 	votes := make(chan bool, len(votingMembers))
@@ -196,6 +219,9 @@ func (r *CandidateRole) sendVoteRequests() {
 				rejectCount++
 				if rejectCount == quorum {
 					r.log.Debug("Lost election with %d/%d votes rejected; transitioning back to follower", rejectCount, len(votingMembers))
+					if failure := tally.lose(); failure != nil {
+						r.raft.SetElectionFailure(failure)
+					}
 					r.raft.SetRole(raft.RoleFollower)
 					r.raft.WriteUnlock()
 					return
@@ -224,25 +250,34 @@ func (r *CandidateRole) sendVoteRequests() {
 
 	// Once we got the last log term, iterate through each current member
 	// of the cluster and request a vote from each.
+	// Requests are sent by a bounded pool of workers to avoid a spike of goroutines in large clusters, and each
+	// request is given a deadline so unresponsive members can't delay the requests queued behind them.
+	timeout := electionRequestTimeout(r.raft.Config(), len(votingMembers)-1)
 	for _, member := range votingMembers {
 		// Vote for yourself!
 		if member == r.raft.Member() {
+			tally.accept()
 			votes <- true
 			continue
 		}
 
-		go func(member raft.MemberID) {
+		member := member
+		sent := r.workers.submit(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
 			r.log.Debug("Requesting vote from %s for term %d", member, term)
 			request := &raft.VoteRequest{
 				Term:         term,
 				Candidate:    r.raft.Member(),
 				LastLogIndex: lastIndex,
 				LastLogTerm:  lastTerm,
+				Transfer:     transfer,
 			}
 
 			r.log.Send("VoteRequest", request)
-			response, err := r.raft.Protocol().Vote(context.Background(), request, member)
+			response, err := r.raft.Protocol().Vote(ctx, request, member)
 			if err != nil {
+				tally.fail()
 				votes <- false
 				r.log.Warn("Failed to request vote from %s", member, err)
 			} else {
@@ -250,23 +285,36 @@ func (r *CandidateRole) sendVoteRequests() {
 				r.raft.WriteLock()
 				if response.Term > request.Term {
 					r.log.Debug("Received greater term from %s; transitioning back to follower", member)
+					tally.reject(raft.RejectionReason_TERM_BEHIND)
+					if failure := tally.lose(); failure != nil {
+						r.raft.SetElectionFailure(failure)
+					}
 					_ = r.raft.SetTerm(response.Term)
 					r.raft.SetRole(raft.RoleFollower)
 					r.raft.WriteUnlock()
-					close(votes)
+					votes <- false
 					return
 				} else if !response.Voted {
-					r.log.Debug("Received rejected vote from %s", member)
+					r.log.Debug("Received rejected vote from %s: %s", member, response.Rejection)
+					tally.reject(response.Rejection)
 					votes <- false
 				} else if response.Term != r.raft.Term() {
 					r.log.Debug("Received successful vote for a different term from %s", member)
+					tally.reject(raft.RejectionReason_TERM_BEHIND)
 					votes <- false
 				} else {
 					r.log.Debug("Received successful vote from %s", member)
+					tally.accept()
 					votes <- true
 				}
 				r.raft.WriteUnlock()
 			}
-		}(member)
+		})
+
+		// Requests aren't sent once the role is stopped, so they're counted as failed to release the vote counter.
+		if !sent {
+			tally.fail()
+			votes <- false
+		}
 	}
 }
@@ -16,20 +16,28 @@ package roles
 
 import (
 	"context"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 )
 
+// newTestCandidateRole returns a new candidate role with randomized election timeouts
+func newTestCandidateRole(protocol raft.Raft, state state.Manager, store store.Store) raft.Role {
+	return newCandidateRole(protocol, state, store, NewRandom())
+}
+
 func TestCandidateVote(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
 	delayFailVote(client, 5*time.Second).AnyTimes()
 
-	role := newTestRole(client, newCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
+	role := newTestRole(client, newTestCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
 	assert.NoError(t, role.raft.SetTerm(1))
 	assert.NoError(t, role.Start())
 	awaitTerm(role.raft, raft.Term(2))
@@ -42,9 +50,20 @@ func TestCandidateVote(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_TERM_BEHIND, response.Rejection)
 	assert.Equal(t, raft.Term(2), response.Term)
 
-	role = newTestRole(client, newCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
+	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         raft.Term(2),
+		Candidate:    raft.MemberID("bar"),
+		LastLogIndex: 1,
+		LastLogTerm:  1,
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_ALREADY_VOTED, response.Rejection)
+
+	role = newTestRole(client, newTestCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
 	assert.NoError(t, role.raft.SetTerm(1))
 	assert.NoError(t, role.Start())
 	awaitTerm(role.raft, raft.Term(2))
@@ -60,7 +79,7 @@ func TestCandidateVote(t *testing.T) {
 	assert.Equal(t, raft.RoleFollower, awaitRole(role.raft, raft.RoleFollower))
 	assert.Equal(t, raft.Term(3), awaitTerm(role.raft, raft.Term(3)))
 
-	role = newTestRole(client, newCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
+	role = newTestRole(client, newTestCandidateRole, mockFollower(ctrl), mockLeader(ctrl)).(*CandidateRole)
 	assert.NoError(t, role.raft.SetTerm(1))
 	assert.NoError(t, role.Start())
 	awaitTerm(role.raft, raft.Term(2))
@@ -84,7 +103,7 @@ func TestCandidateVoteQuorum(t *testing.T) {
 	rejectVote(client).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newCandidateRole(protocol, sm, stores).(*CandidateRole)
+	role := newCandidateRole(protocol, sm, stores, NewRandom()).(*CandidateRole)
 	assert.NoError(t, role.Start())
 	assert.Equal(t, raft.RoleLeader, awaitRole(role.raft, raft.RoleLeader))
 }
@@ -95,7 +114,7 @@ func TestCandidateVoteFail(t *testing.T) {
 	rejectVote(client).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newCandidateRole(protocol, sm, stores).(*CandidateRole)
+	role := newCandidateRole(protocol, sm, stores, NewRandom()).(*CandidateRole)
 	assert.NoError(t, role.Start())
 	assert.Equal(t, raft.RoleFollower, awaitRole(role.raft, raft.RoleFollower))
 }
@@ -106,7 +125,7 @@ func TestCandidateVoteTimeout(t *testing.T) {
 	delayFailVote(client, 5*time.Second).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newCandidateRole(protocol, sm, stores).(*CandidateRole)
+	role := newCandidateRole(protocol, sm, stores, NewRandom()).(*CandidateRole)
 	assert.NoError(t, role.Start())
 	assert.Equal(t, raft.Term(1), awaitTerm(role.raft, raft.Term(1)))
 
@@ -124,3 +143,32 @@ func TestCandidateVoteTimeout(t *testing.T) {
 	assert.Nil(t, role.raft.Leader())
 	assert.Equal(t, role.raft.Member(), *role.raft.LastVotedFor())
 }
+
+func TestCandidateUnresponsiveVoter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Hang vote requests to one member until their deadline expires
+	client.EXPECT().
+		Vote(gomock.Any(), gomock.Any(), gomock.Eq(raft.MemberID("bar"))).
+		DoAndReturn(func(ctx context.Context, request *raft.VoteRequest, member raft.MemberID) (*raft.VoteResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).AnyTimes()
+	acceptVote(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:    &electionTimeout,
+		MaxElectionWorkers: 1,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newCandidateRole(protocol, sm, stores, NewRandom()).(*CandidateRole)
+
+	// Verify the unresponsive member doesn't prevent the vote from the other member within the election timeout
+	startTime := time.Now()
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	assert.Equal(t, raft.RoleLeader, awaitRole(role.raft, raft.RoleLeader))
+	assert.True(t, time.Since(startTime) < electionTimeout)
+}
@@ -0,0 +1,62 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"time"
+)
+
+// Timestamp returns a cluster-wide timestamp that never goes backward, even across leader changes
+// The timestamp is the later of the leader's clock and the timestamp of the last entry in the log. It's committed
+// to the log in a barrier entry before it's returned, so entries written by any future leader are no earlier.
+func (r *LeaderRole) Timestamp() (time.Time, error) {
+	r.raft.WriteLock()
+	entry := &raft.LogEntry{
+		Term:      r.raft.Term(),
+		Timestamp: r.nextTimestamp(),
+		Entry: &raft.LogEntry_Barrier{
+			Barrier: &raft.BarrierEntry{},
+		},
+	}
+	indexed := r.store.Writer().Append(entry)
+	r.raft.WriteUnlock()
+
+	err := r.appender.commit(indexed, func() {
+		r.state.ApplyEntry(indexed, nil)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return entry.Timestamp, nil
+}
+
+// nextTimestamp returns the timestamp for a new entry in the leader's log
+// The leader's log contains all committed entries, so using the later of the local clock and the timestamp of the
+// last entry in the log ensures committed timestamps never go backward when a leader with a slower clock is
+// elected. The caller must hold the write lock.
+func (r *LeaderRole) nextTimestamp() time.Time {
+	now := time.Now()
+	if entry := r.store.Writer().LastEntry(); entry != nil {
+		if entry.Entry.Timestamp.After(now) {
+			return entry.Entry.Timestamp
+		}
+		return now
+	}
+	if snapshot := r.store.Snapshot().CurrentSnapshot(); snapshot != nil && snapshot.Timestamp().After(now) {
+		return snapshot.Timestamp()
+	}
+	return now
+}
@@ -0,0 +1,119 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"sync"
+	"time"
+)
+
+// electionRequestTimeout returns the deadline for each poll or vote request sent to the given number of peers
+// Requests are sent by a bounded pool of workers, so a request to an unresponsive peer delays the requests queued
+// behind it. Dividing the election timeout among the waves of requests the workers send ensures every peer is
+// contacted within the election timeout.
+func electionRequestTimeout(config *config.ProtocolConfig, peers int) time.Duration {
+	workers := config.GetMaxElectionWorkersOrDefault()
+	waves := (peers + workers - 1) / workers
+	if waves < 1 {
+		waves = 1
+	}
+	return config.GetElectionTimeoutOrDefault() / time.Duration(waves)
+}
+
+// newElectionTally returns a new tally of the responses to a poll or vote for the given term
+func newElectionTally(term raft.Term, voters int) *electionTally {
+	return &electionTally{
+		term:   term,
+		voters: voters,
+	}
+}
+
+// electionTally aggregates the responses to a poll or vote to explain why the local member did not become the leader
+type electionTally struct {
+	term        raft.Term
+	voters      int
+	accepted    int
+	unreachable int
+	rejections  []raft.RejectionReason
+	concluded   bool
+	mu          sync.Mutex
+}
+
+// accept records a voter that accepted the local member
+func (t *electionTally) accept() {
+	t.mu.Lock()
+	t.accepted++
+	t.mu.Unlock()
+}
+
+// reject records a voter that rejected the local member for the given reason
+func (t *electionTally) reject(reason raft.RejectionReason) {
+	t.mu.Lock()
+	t.rejections = append(t.rejections, reason)
+	t.mu.Unlock()
+}
+
+// fail records a voter that failed to respond
+func (t *electionTally) fail() {
+	t.mu.Lock()
+	t.unreachable++
+	t.mu.Unlock()
+}
+
+// lose concludes the tally once the local member has lost, returning the reason it lost
+// If the tally was already concluded, nil is returned.
+func (t *electionTally) lose() *raft.ElectionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conclude()
+}
+
+// expire concludes the tally once the election timeout has expired, returning the reason the local member did not
+// win. Voters that have not responded are counted as unreachable. If the tally was already concluded, nil is returned.
+func (t *electionTally) expire() *raft.ElectionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.concluded {
+		return nil
+	}
+	t.unreachable = t.voters - t.accepted - len(t.rejections)
+	return t.conclude()
+}
+
+// conclude concludes the tally, returning nil if it was already concluded
+// The reason for the failure is the reason given by the most voters, with ties going to the most recently given.
+func (t *electionTally) conclude() *raft.ElectionFailure {
+	if t.concluded {
+		return nil
+	}
+	t.concluded = true
+	reason := raft.RejectionReason_REJECTION_UNSPECIFIED
+	counts := make(map[raft.RejectionReason]int)
+	for _, rejection := range t.rejections {
+		counts[rejection]++
+		if counts[rejection] >= counts[reason] {
+			reason = rejection
+		}
+	}
+	return &raft.ElectionFailure{
+		Term:        t.term,
+		Reason:      reason,
+		Rejections:  uint32(len(t.rejections)),
+		Unreachable: uint32(t.unreachable),
+		Voters:      uint32(t.voters),
+	}
+}
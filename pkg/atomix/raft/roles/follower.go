@@ -21,23 +21,28 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"math"
-	"math/rand"
 	"time"
 )
 
 // newFollowerRole returns a new follower role
-func newFollowerRole(protocol raft.Raft, state state.Manager, store store.Store) raft.Role {
+func newFollowerRole(protocol raft.Raft, state state.Manager, store store.Store, random Random) raft.Role {
 	log := util.NewRoleLogger(string(protocol.Member()), string(raft.RoleFollower))
 	return &FollowerRole{
 		ActiveRole: newActiveRole(protocol, state, store, log),
+		random:     random,
+		workers:    newWorkerPool(protocol.Config().GetMaxElectionWorkersOrDefault()),
 	}
 }
 
 // FollowerRole implements a Raft follower
 type FollowerRole struct {
 	*ActiveRole
+	random         Random
 	heartbeatTimer *time.Timer
 	heartbeatStop  chan bool
+	relay          *relay
+	// workers sends poll requests, bounding the number of requests in flight across polls
+	workers *workerPool
 }
 
 // Type is the role type
@@ -47,14 +52,20 @@ func (r *FollowerRole) Type() raft.RoleType {
 
 // Start starts the follower
 func (r *FollowerRole) Start() error {
-	// If there are no other members in the cluster, immediately transition to candidate to increment the term.
-	if len(r.raft.Members()) == 1 {
+	// If there are no other voters in the cluster, immediately transition to candidate to increment the term.
+	if r.isVoter() && !r.isWitness() && len(r.voters()) == 1 {
 		r.log.Debug("Single node cluster; starting election")
 		r.raft.SetRole(raft.RoleCandidate)
 		return nil
 	}
 	_ = r.ActiveRole.Start()
 	go r.resetHeartbeatTimeout()
+
+	// If relaying is enabled, relay committed entries to the follower's downstream members.
+	if r.raft.Config().GetRelayFanout() > 0 {
+		r.relay = newRelay(r.raft, r.store, r.log)
+		go r.relay.start()
+	}
 	return nil
 }
 
@@ -63,6 +74,10 @@ func (r *FollowerRole) Stop() error {
 	if r.heartbeatTimer != nil && r.heartbeatTimer.Stop() {
 		r.heartbeatStop <- true
 	}
+	if r.relay != nil {
+		r.relay.stop()
+	}
+	r.workers.close()
 	return r.ActiveRole.Stop()
 }
 
@@ -70,7 +85,19 @@ func (r *FollowerRole) Stop() error {
 func (r *FollowerRole) resetHeartbeatTimeout() {
 	r.raft.WriteLock()
 	defer r.raft.WriteUnlock()
+	r.resetHeartbeatTimer()
+}
 
+// ElectionTimeoutChanged resets the follower's heartbeat timeout using the updated election timeout
+func (r *FollowerRole) ElectionTimeoutChanged(timeout time.Duration) {
+	if r.active {
+		r.resetHeartbeatTimer()
+	}
+}
+
+// resetHeartbeatTimer resets the follower's heartbeat timer
+// The timer must be reset with a write lock on the Raft state.
+func (r *FollowerRole) resetHeartbeatTimer() {
 	// If a timer is already set, cancel the timer.
 	if r.heartbeatTimer != nil && r.heartbeatTimer.Stop() {
 		r.heartbeatStop <- true
@@ -78,7 +105,7 @@ func (r *FollowerRole) resetHeartbeatTimeout() {
 
 	// Set the election timeout in a semi-random fashion with the random range
 	// being election timeout and 2 * election timeout.
-	timeout := r.raft.Config().GetElectionTimeoutOrDefault() + time.Duration(rand.Int63n(int64(r.raft.Config().GetElectionTimeoutOrDefault())))
+	timeout := r.raft.Config().GetElectionTimeoutOrDefault() + time.Duration(r.random.Int63n(int64(r.raft.Config().GetElectionTimeoutOrDefault())))
 	r.heartbeatTimer = time.NewTimer(timeout)
 	heartbeatStop := make(chan bool, 1)
 	r.heartbeatStop = heartbeatStop
@@ -87,7 +114,36 @@ func (r *FollowerRole) resetHeartbeatTimeout() {
 		select {
 		case <-heartbeatCh:
 			r.raft.WriteLock()
-			if r.active {
+			if r.active && r.raft.Status() == raft.StatusFaulted {
+				// A member with a faulted state machine must not be elected leader
+				r.log.Debug("Heartbeat timed out in %d milliseconds; state machine is faulted", timeout/time.Millisecond)
+				if err := r.raft.SetLeader(nil); err != nil {
+					r.log.Error("Failed to update leader", err)
+				}
+				go r.resetHeartbeatTimeout()
+			} else if r.active && r.raft.InMaintenance() {
+				// Members in maintenance mode don't stand for election, so they can be taken down without disruption
+				r.log.Debug("Heartbeat timed out in %d milliseconds; local member is in maintenance mode", timeout/time.Millisecond)
+				if err := r.raft.SetLeader(nil); err != nil {
+					r.log.Error("Failed to update leader", err)
+				}
+				go r.resetHeartbeatTimeout()
+			} else if r.active && r.isWitness() {
+				// Witnesses store no entries, so they vote but never stand for election
+				r.log.Debug("Heartbeat timed out in %d milliseconds; local member is a witness", timeout/time.Millisecond)
+				if err := r.raft.SetLeader(nil); err != nil {
+					r.log.Error("Failed to update leader", err)
+				}
+				go r.resetHeartbeatTimeout()
+			} else if r.active && !r.isVoter() {
+				// Non-voting members never stand for election
+				r.log.Debug("Heartbeat timed out in %d milliseconds; local member is not a voter", timeout/time.Millisecond)
+				if err := r.raft.SetLeader(nil); err != nil {
+					r.log.Error("Failed to update leader", err)
+				}
+				go r.discoverLeader()
+				go r.resetHeartbeatTimeout()
+			} else if r.active {
 				if err := r.raft.SetLeader(nil); err != nil {
 					r.log.Error("Failed to update leader", err)
 				}
@@ -101,27 +157,78 @@ func (r *FollowerRole) resetHeartbeatTimeout() {
 	}()
 }
 
+// discoverLeader polls the voting members of the cluster for the current leader
+// Non-voting members never stand for election, so rather than waiting for the leader to resume replicating to them
+// after losing contact, they poll the voters to learn the leader's identity. Polling the leader causes it to resume
+// replicating to the member at once.
+func (r *FollowerRole) discoverLeader() {
+	r.raft.ReadLock()
+	request := &raft.PollRequest{
+		Term:      r.raft.Term(),
+		Candidate: r.raft.Member(),
+	}
+	if lastEntry := r.store.Writer().LastEntry(); lastEntry != nil {
+		request.LastLogIndex = lastEntry.Index
+		request.LastLogTerm = lastEntry.Entry.Term
+	}
+	r.raft.ReadUnlock()
+
+	votingMembers := r.voters()
+	r.log.Debug("Polling members %v for the leader", votingMembers)
+	timeout := electionRequestTimeout(r.raft.Config(), len(votingMembers))
+	for _, member := range votingMembers {
+		member := member
+		r.workers.submit(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			r.log.Send("PollRequest", request)
+			response, err := r.raft.Protocol().Poll(ctx, request, member)
+			if err != nil {
+				r.log.Debug("Poll request to %s failed", member, err)
+				return
+			}
+			r.log.Receive("PollResponse", response)
+			if response.Leader == "" {
+				return
+			}
+			r.raft.WriteLock()
+			defer r.raft.WriteUnlock()
+			if r.active && r.updateTermAndLeader(response.Term, &response.Leader) {
+				r.log.Info("Discovered leader %s in term %d", response.Leader, response.Term)
+			}
+		})
+	}
+}
+
 // sendPollRequests sends PollRequests to all members of the cluster
 func (r *FollowerRole) sendPollRequests() {
+	// Create a tally of the responses to explain why the poll failed if it does.
+	votingMembers := r.voters()
+	r.raft.ReadLock()
+	tally := newElectionTally(r.raft.Term()+1, len(votingMembers))
+	r.raft.ReadUnlock()
+
 	// Set a new timer within which other nodes must respond in order for this node to transition to candidate.
 	timeoutTimer := time.NewTimer(r.raft.Config().GetElectionTimeoutOrDefault())
 	timeoutExpired := make(chan bool, 1)
 	go func() {
 		select {
 		case <-timeoutTimer.C:
-			r.raft.ReadLock()
+			r.raft.WriteLock()
 			if r.active {
 				r.log.Debug("Failed to poll a majority of the cluster in %d", r.raft.Config().GetElectionTimeoutOrDefault())
+				if failure := tally.expire(); failure != nil {
+					r.raft.SetElectionFailure(failure)
+				}
 				go r.resetHeartbeatTimeout()
 			}
-			r.raft.ReadUnlock()
+			r.raft.WriteUnlock()
 		case <-timeoutExpired:
 			return
 		}
 	}()
 
 	// Create a quorum that will track the number of nodes that have responded to the poll request.
-	votingMembers := r.raft.Members()
 	votes := make(chan bool, len(votingMembers))
 	quorum := int(math.Floor(float64(len(votingMembers))/2.0) + 1)
 	go func() {
@@ -147,6 +254,9 @@ func (r *FollowerRole) sendPollRequests() {
 				rejectCount++
 				if rejectCount == quorum {
 					r.log.Debug("Received %d/%d rejected pre-votes; resetting heartbeat timeout", rejectCount, len(votingMembers))
+					if failure := tally.lose(); failure != nil {
+						r.raft.SetElectionFailure(failure)
+					}
 					r.raft.WriteUnlock()
 					go r.resetHeartbeatTimeout()
 					return
@@ -178,14 +288,21 @@ func (r *FollowerRole) sendPollRequests() {
 
 	// Once we got the last log term, iterate through each current member
 	// of the cluster and vote each member for a vote.
+	// Requests are sent by a bounded pool of workers to avoid a spike of goroutines in large clusters, and each
+	// request is given a deadline so unresponsive members can't delay the requests queued behind them.
+	timeout := electionRequestTimeout(r.raft.Config(), len(votingMembers)-1)
 	for _, member := range votingMembers {
 		// Vote for yourself!
 		if member == r.raft.Member() {
+			tally.accept()
 			votes <- true
 			continue
 		}
 
-		go func(member raft.MemberID) {
+		member := member
+		sent := r.workers.submit(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
 			r.raft.ReadLock()
 			term := r.raft.Term()
 			r.raft.ReadUnlock()
@@ -198,8 +315,9 @@ func (r *FollowerRole) sendPollRequests() {
 			}
 
 			r.log.Send("PollRequest", request)
-			response, err := r.raft.Protocol().Poll(context.Background(), request, member)
+			response, err := r.raft.Protocol().Poll(ctx, request, member)
 			if err != nil {
+				tally.fail()
 				votes <- false
 				r.log.Warn("Poll request failed", err)
 			} else {
@@ -216,17 +334,26 @@ func (r *FollowerRole) sendPollRequests() {
 				}
 
 				if !response.Accepted {
-					r.log.Debug("Received rejected poll from %s", member)
+					r.log.Debug("Received rejected poll from %s: %s", member, response.Rejection)
+					tally.reject(response.Rejection)
 					votes <- false
 				} else if response.Term != request.Term {
 					r.log.Debug("Received accepted poll for a different term from %s", member)
+					tally.reject(raft.RejectionReason_TERM_BEHIND)
 					votes <- false
 				} else {
 					r.log.Debug("Received accepted poll from %s", member)
+					tally.accept()
 					votes <- true
 				}
 			}
-		}(member)
+		})
+
+		// Requests aren't sent once the role is stopped, so they're counted as failed to release the vote counter.
+		if !sent {
+			tally.fail()
+			votes <- false
+		}
 	}
 }
 
@@ -247,10 +374,63 @@ func (r *FollowerRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.Inst
 // Append handles an append request
 func (r *FollowerRole) Append(ctx context.Context, request *raft.AppendRequest) (*raft.AppendResponse, error) {
 	response, err := r.PassiveRole.Append(ctx, request)
-	r.resetHeartbeatTimeout()
+
+	// Only requests from the leader of the current term reset the heartbeat timeout. Heartbeats from a stale
+	// leader rejoining the cluster are rejected with the current term and must not delay the detection of a
+	// failure of the current leader.
+	if response != nil && response.Term == request.Term {
+		r.resetHeartbeatTimeout()
+
+		// Report downstream members the relay can't catch up so the leader replicates to them directly.
+		if r.relay != nil {
+			response.Unrelayed = r.relay.unrelayedMembers()
+		}
+	}
 	return response, err
 }
 
+// Transfer handles a leadership transfer request from the leader
+// The leader sends the request once the member is caught up with its log, so the member starts an election at once
+// without polling the cluster. The member's vote requests carry a greater term than the leader's, causing the leader
+// to step down and vote for the member. Requests that don't come from the leader for the current term are rejected.
+func (r *FollowerRole) Transfer(ctx context.Context, request *raft.TransferRequest) (*raft.TransferResponse, error) {
+	r.log.Request("TransferRequest", request)
+	r.raft.WriteLock()
+	defer r.raft.WriteUnlock()
+	if leader := r.raft.Leader(); request.Term != r.raft.Term() || leader == nil || *leader != request.Leader {
+		r.log.Warn("Rejecting leadership transfer from %s for term %d: the member is not the current leader", request.Leader, request.Term)
+		response := &raft.TransferResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+		}
+		_ = r.log.Response("TransferResponse", response, nil)
+		return response, nil
+	}
+	if request.Member != r.raft.Member() || !r.isVoter() || r.isWitness() || r.raft.InMaintenance() || r.raft.Status() == raft.StatusFaulted {
+		response := &raft.TransferResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+		}
+		_ = r.log.Response("TransferResponse", response, nil)
+		return response, nil
+	}
+
+	r.log.Debug("Leadership transferred to the local member; starting election")
+	r.raft.SetHandoff(&raft.Handoff{
+		Term:   request.Term,
+		Leader: request.Leader,
+	})
+	if err := r.raft.SetLeader(nil); err != nil {
+		r.log.Error("Failed to update leader", err)
+	}
+	r.raft.SetRole(raft.RoleCandidate)
+	response := &raft.TransferResponse{
+		Status: raft.ResponseStatus_OK,
+	}
+	_ = r.log.Response("TransferResponse", response, nil)
+	return response, nil
+}
+
 // Vote handles a vote request
 func (r *FollowerRole) Vote(ctx context.Context, request *raft.VoteRequest) (*raft.VoteResponse, error) {
 	r.log.Request("VoteRequest", request)
@@ -258,6 +438,17 @@ func (r *FollowerRole) Vote(ctx context.Context, request *raft.VoteRequest) (*ra
 	// Vote requests can modify the server's vote record, so we need to hold a write lock while handling the request.
 	r.raft.WriteLock()
 
+	if response := r.protectLeadership(request); response != nil {
+		r.raft.WriteUnlock()
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+	if response := r.protectLeader(request); response != nil {
+		r.raft.WriteUnlock()
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+
 	// If the request indicates a term that is greater than the current term then
 	// assign that term and leader to the current context.
 	if r.updateTermAndLeader(request.Term, nil) {
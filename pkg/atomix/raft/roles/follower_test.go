@@ -15,10 +15,15 @@
 package roles
 
 import (
+	"context"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -32,7 +37,7 @@ func TestFollowerPollQuorum(t *testing.T) {
 	failAppend(client).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newFollowerRole(protocol, sm, stores).(*FollowerRole)
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
 	assert.NoError(t, role.Start())
 	role.raft.ReadLock()
 	assert.Equal(t, raft.Term(0), role.raft.Term())
@@ -49,7 +54,7 @@ func TestFollowerPollFail(t *testing.T) {
 	failAppend(client).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newFollowerRole(protocol, sm, stores).(*FollowerRole)
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
 	assert.NoError(t, role.Start())
 	role.raft.ReadLock()
 	assert.Equal(t, raft.Term(0), role.raft.Term())
@@ -60,6 +65,102 @@ func TestFollowerPollFail(t *testing.T) {
 	assert.Equal(t, raft.RoleType(""), role.raft.Role())
 }
 
+func TestFollowerElectionFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	failAppend(client).AnyTimes()
+
+	// Reject polls from members whose logs are shorter than the other members' logs
+	client.EXPECT().
+		Poll(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.PollRequest, member raft.MemberID) (*raft.PollResponse, error) {
+			if request.LastLogIndex < 10 {
+				return &raft.PollResponse{
+					Status:    raft.ResponseStatus_OK,
+					Term:      request.Term,
+					Accepted:  false,
+					Rejection: raft.RejectionReason_LOG_BEHIND,
+				}, nil
+			}
+			return &raft.PollResponse{
+				Status:   raft.ResponseStatus_OK,
+				Term:     request.Term,
+				Accepted: true,
+			}, nil
+		}).
+		AnyTimes()
+
+	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	appendTestEntry(protocol, stores, raft.Term(1))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify the member reports its short log as the reason it cannot become the leader
+	var failure *raft.ElectionFailure
+	deadline := time.Now().Add(10 * time.Second)
+	for failure == nil && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+		role.raft.ReadLock()
+		failure = role.raft.ElectionFailure()
+		role.raft.ReadUnlock()
+	}
+	assert.NotNil(t, failure)
+	if failure != nil {
+		assert.Equal(t, raft.Term(1), failure.Term)
+		assert.Equal(t, raft.RejectionReason_LOG_BEHIND, failure.Reason)
+		assert.Equal(t, uint32(2), failure.Rejections)
+		assert.Equal(t, uint32(0), failure.Unreachable)
+		assert.Equal(t, uint32(3), failure.Voters)
+	}
+}
+
+func TestFollowerPollConcurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Track the number of poll requests in flight at once
+	var inflight, maxInflight, polls int32
+	client.EXPECT().
+		Poll(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.PollRequest, member raft.MemberID) (*raft.PollResponse, error) {
+			count := atomic.AddInt32(&inflight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInflight)
+				if count <= max || atomic.CompareAndSwapInt32(&maxInflight, max, count) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inflight, -1)
+			atomic.AddInt32(&polls, 1)
+			return &raft.PollResponse{
+				Status:   raft.ResponseStatus_OK,
+				Term:     request.Term,
+				Accepted: false,
+			}, nil
+		}).
+		AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:    &electionTimeout,
+		MaxElectionWorkers: 4,
+	}
+	protocol, sm, stores := newLargeTestState(client, config, 100, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify every member is polled without exceeding the maximum number of concurrent requests
+	deadline := time.Now().Add(10 * time.Second)
+	for atomic.LoadInt32(&polls) < 100 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.True(t, atomic.LoadInt32(&polls) >= 100)
+	assert.True(t, atomic.LoadInt32(&maxInflight) <= 4, "%d concurrent polls", atomic.LoadInt32(&maxInflight))
+}
+
 func TestFollowerPollRestart(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
@@ -67,7 +168,7 @@ func TestFollowerPollRestart(t *testing.T) {
 	acceptPoll(client).AnyTimes()
 
 	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
-	role := newFollowerRole(protocol, sm, stores).(*FollowerRole)
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
 	assert.NoError(t, role.Start())
 	role.raft.ReadLock()
 	assert.Equal(t, raft.Term(0), role.raft.Term())
@@ -76,3 +177,498 @@ func TestFollowerPollRestart(t *testing.T) {
 
 	assert.Equal(t, raft.RoleCandidate, awaitRole(role.raft, raft.RoleCandidate))
 }
+
+func TestFollowerStaleQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+
+	// Apply a session and a write to the local state without a leader
+	stores.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newOpenSessionRequest(),
+			},
+		},
+	})
+	stores.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newSetRequest("Set", 1, 1),
+			},
+		},
+	})
+	sm.ApplyIndex(raft.Index(2))
+
+	// Verify the partitioned follower serves stale reads from its local state
+	query := func(consistency raft.ReadConsistency) *raft.QueryResponse {
+		ch := make(chan *raft.QueryStreamResponse, 1)
+		assert.NoError(t, role.Query(&raft.QueryRequest{
+			Value:           newGetRequest("Get", 1, 1),
+			ReadConsistency: consistency,
+		}, ch))
+		response := <-ch
+		assert.True(t, response.Succeeded())
+		return response.Response
+	}
+
+	var response *raft.QueryResponse
+	for i := 0; i < 100; i++ {
+		response = query(raft.ReadConsistency_STALE)
+		if getQueryValue(response.Output) != "" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(response.Output))
+
+	// Verify reads requiring a leader are rejected
+	response = query(raft.ReadConsistency_SEQUENTIAL)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_NO_LEADER, response.Error)
+	response = query(raft.ReadConsistency_LINEARIZABLE)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_NO_LEADER, response.Error)
+}
+
+func TestFollowerForwardQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	leaderClient := mock.NewMockClient(ctrl)
+	succeedAppend(leaderClient).AnyTimes()
+
+	// Write a value through the leader that the follower has not received
+	leader := newLeaderRole(newTestState(leaderClient, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, leader.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, leader.Start())
+	sessionID := openTestSession(t, leader)
+	setTestValue(t, leader, sessionID, 1)
+
+	// Relay queries forwarded by the follower to the leader
+	followerClient := mock.NewMockClient(ctrl)
+	depths := make(chan uint32, 1)
+	followerClient.EXPECT().
+		Query(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.QueryRequest, member raft.MemberID) (<-chan *raft.QueryStreamResponse, error) {
+			depths <- request.ForwardDepth
+			ch := make(chan *raft.QueryStreamResponse, 1)
+			go func() {
+				_ = leader.Query(request, ch)
+			}()
+			return ch, nil
+		})
+
+	protocol, sm, stores := newTestState(followerClient, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	follower := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	leaderID := raft.MemberID("bar")
+	follower.raft.WriteLock()
+	assert.NoError(t, follower.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, follower.raft.SetLeader(&leaderID))
+	follower.raft.WriteUnlock()
+
+	// Verify a linearizable read sent to the follower returns the leader's up-to-date value
+	ch := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, follower.Query(&raft.QueryRequest{
+		Value:           newGetRequest("Get", sessionID, 1),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+	}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(response.Response.Output))
+	assert.Equal(t, uint32(1), <-depths)
+
+	// Verify a query that has been forwarded too many times is rejected rather than forwarded again
+	ch = make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, follower.Query(&raft.QueryRequest{
+		Value:           newGetRequest("Get", sessionID, 1),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+		ForwardDepth:    maxQueryForwardDepth,
+	}, ch))
+	response = <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_NO_LEADER, response.Response.Error)
+}
+
+func TestFollowerMinIndexQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	protocol, sm, stores := newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	leaderID := raft.MemberID("bar")
+	role.raft.WriteLock()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.raft.SetLeader(&leaderID))
+	role.raft.WriteUnlock()
+
+	// Replicate a session and a write to the follower, but only commit the session
+	stores.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newOpenSessionRequest(),
+			},
+		},
+	})
+	stores.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newSetRequest("Set", 1, 1),
+			},
+		},
+	})
+	commit := func(index raft.Index) {
+		role.raft.WriteLock()
+		role.raft.SetCommitIndex(index)
+		role.raft.Commit(index)
+		sm.ApplyIndex(index)
+		role.raft.WriteUnlock()
+	}
+	commit(raft.Index(1))
+
+	query := func(minIndex raft.Index, sequenceNumber uint64, timeout time.Duration) <-chan *raft.QueryStreamResponse {
+		ch := make(chan *raft.QueryStreamResponse, 1)
+		go func() {
+			assert.NoError(t, role.Query(&raft.QueryRequest{
+				Value:           newGetRequest("Get", 1, sequenceNumber),
+				ReadConsistency: raft.ReadConsistency_SEQUENTIAL,
+				MinIndex:        minIndex,
+				MinIndexTimeout: timeout,
+			}, ch))
+		}()
+		return ch
+	}
+	awaitResponse := func(ch <-chan *raft.QueryStreamResponse) *raft.QueryResponse {
+		select {
+		case response := <-ch:
+			assert.True(t, response.Succeeded())
+			return response.Response
+		case <-time.After(5 * time.Second):
+			t.Fatal("query did not complete")
+			return nil
+		}
+	}
+
+	// Verify a read requiring an index the lagging follower has reached is served locally
+	response := awaitResponse(query(raft.Index(1), 0, 0))
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.Equal(t, "", getQueryValue(response.Output))
+
+	// Verify a read requiring an index the follower has not reached is rejected with a retryable error
+	startTime := time.Now()
+	response = awaitResponse(query(raft.Index(2), 0, 50*time.Millisecond))
+	assert.True(t, time.Since(startTime) >= 50*time.Millisecond)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_UNAVAILABLE, response.Error)
+
+	// Verify a read waiting for the index is held until the follower applies it and is then served
+	ch := query(raft.Index(2), 1, 5*time.Second)
+	select {
+	case <-ch:
+		t.Fatal("query completed before its minimum index was applied")
+	case <-time.After(100 * time.Millisecond):
+	}
+	commit(raft.Index(2))
+	response = awaitResponse(ch)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(response.Output))
+}
+
+func TestFollowerMinLeadershipDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Signal when the follower times out the leader and polls the cluster
+	polled := make(chan struct{}, 1)
+	client.EXPECT().
+		Poll(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.PollRequest, member raft.MemberID) (*raft.PollResponse, error) {
+			select {
+			case polled <- struct{}{}:
+			default:
+			}
+			return &raft.PollResponse{
+				Status:   raft.ResponseStatus_OK,
+				Term:     request.Term,
+				Accepted: false,
+			}, nil
+		}).
+		AnyTimes()
+
+	electionTimeout := 200 * time.Millisecond
+	minLeadershipDuration := time.Minute
+	config := &config.ProtocolConfig{
+		ElectionTimeout:       &electionTimeout,
+		MinLeadershipDuration: &minLeadershipDuration,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	leader := raft.MemberID("bar")
+	role.raft.WriteLock()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.raft.SetLeader(&leader))
+	role.raft.WriteUnlock()
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Simulate a member repeatedly attempting to disrupt the new leader
+	for i := 0; i < 5; i++ {
+		pollResponse, err := role.Poll(context.TODO(), &raft.PollRequest{
+			Term:      raft.Term(i + 2),
+			Candidate: raft.MemberID("baz"),
+		})
+		assert.NoError(t, err)
+		assert.False(t, pollResponse.Accepted)
+
+		voteResponse, err := role.Vote(context.TODO(), &raft.VoteRequest{
+			Term:      raft.Term(i + 2),
+			Candidate: raft.MemberID("baz"),
+		})
+		assert.NoError(t, err)
+		assert.False(t, voteResponse.Voted)
+	}
+
+	// Verify the follower did not adopt the new terms
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Term(1), role.raft.Term())
+	role.raft.ReadUnlock()
+
+	// Verify a leader that stops sending heartbeats is timed out within its minimum leadership duration
+	select {
+	case <-polled:
+	case <-time.After(10 * time.Second):
+		t.Fatal("follower did not time out the leader")
+	}
+	role.raft.ReadLock()
+	assert.Nil(t, role.raft.Leader())
+	role.raft.ReadUnlock()
+
+	// Once the leader has been timed out, verify the follower votes for the candidate
+	voteResponse, err := role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:      raft.Term(2),
+		Candidate: raft.MemberID("baz"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, voteResponse.Voted)
+	assert.Equal(t, raft.Term(2), awaitTerm(role.raft, raft.Term(2)))
+}
+
+func TestFollowerVoteLeaderExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	rejectPoll(client).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	leader := raft.MemberID("bar")
+	role.raft.WriteLock()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.raft.SetLeader(&leader))
+	role.raft.WriteUnlock()
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify the follower rejects votes while it's hearing from the leader, without a minimum leadership duration
+	voteResponse, err := role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:      raft.Term(2),
+		Candidate: raft.MemberID("baz"),
+	})
+	assert.NoError(t, err)
+	assert.False(t, voteResponse.Voted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, voteResponse.Rejection)
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Term(1), role.raft.Term())
+	assert.Equal(t, leader, *role.raft.Leader())
+	role.raft.ReadUnlock()
+
+	// Verify the follower votes for a member the leader transferred leadership to
+	voteResponse, err = role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:      raft.Term(2),
+		Candidate: raft.MemberID("baz"),
+		Transfer:  true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, voteResponse.Voted)
+	assert.Equal(t, raft.Term(2), awaitTerm(role.raft, raft.Term(2)))
+}
+
+func TestFollowerElectionTimeoutChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	acceptPoll(client).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	heartbeatInterval := 10 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify a timeout shorter than twice the heartbeat interval is rejected
+	role.raft.WriteLock()
+	assert.Error(t, role.raft.SetElectionTimeout(15*time.Millisecond))
+	role.raft.WriteUnlock()
+	assert.Equal(t, electionTimeout, role.raft.Config().GetElectionTimeoutOrDefault())
+
+	// Verify the heartbeat timer is re-armed with the updated timeout
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, raft.RoleType(""), role.raft.Role())
+	startTime := time.Now()
+	role.raft.WriteLock()
+	assert.NoError(t, role.raft.SetElectionTimeout(100*time.Millisecond))
+	role.ElectionTimeoutChanged(100 * time.Millisecond)
+	role.raft.WriteUnlock()
+	assert.Equal(t, 100*time.Millisecond, role.raft.Config().GetElectionTimeoutOrDefault())
+	assert.Equal(t, raft.RoleCandidate, awaitRole(role.raft, raft.RoleCandidate))
+	assert.True(t, time.Since(startTime) < electionTimeout)
+}
+
+func TestFollowerDiscoverLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	client.EXPECT().
+		Poll(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.PollRequest, member raft.MemberID) (*raft.PollResponse, error) {
+			assert.Equal(t, raft.MemberID("foo"), request.Candidate)
+			return &raft.PollResponse{
+				Status:    raft.ResponseStatus_OK,
+				Term:      raft.Term(2),
+				Accepted:  false,
+				Rejection: raft.RejectionReason_LEADER_EXISTS,
+				Leader:    raft.MemberID("baz"),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 100 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		Observers:       []string{"foo"},
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify the observer learns the leader from the voters once its heartbeat times out
+	leader := raft.MemberID("baz")
+	assert.Equal(t, leader, *awaitLeader(role.raft, &leader))
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Term(2), role.raft.Term())
+	role.raft.ReadUnlock()
+}
+
+func TestFollowerTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	rejectPoll(client).AnyTimes()
+	rejectVote(client).AnyTimes()
+	failAppend(client).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	leader := raft.MemberID("bar")
+	assert.NoError(t, role.raft.SetLeader(&leader))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify transfer requests that don't come from the leader for the current term are rejected
+	response, err := role.Transfer(context.TODO(), &raft.TransferRequest{Member: "foo", Term: raft.Term(1), Leader: leader})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_ILLEGAL_MEMBER_STATE, response.Error)
+
+	response, err = role.Transfer(context.TODO(), &raft.TransferRequest{Member: "foo", Term: raft.Term(2), Leader: "baz"})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_ILLEGAL_MEMBER_STATE, response.Error)
+	assert.Equal(t, raft.RoleType(""), role.raft.Role())
+
+	// Verify the member starts an election when the leader transfers leadership to it
+	response, err = role.Transfer(context.TODO(), &raft.TransferRequest{Member: "foo", Term: raft.Term(2), Leader: leader})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.Equal(t, raft.RoleCandidate, awaitRole(role.raft, raft.RoleCandidate))
+	role.raft.ReadLock()
+	assert.Equal(t, &raft.Handoff{Term: raft.Term(2), Leader: leader}, role.raft.Handoff())
+	role.raft.ReadUnlock()
+}
+
+func TestFollowerRelayFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Reject appends to the downstream member as if its log were empty
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("baz")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			return &raft.AppendResponse{
+				Status:    raft.ResponseStatus_OK,
+				Term:      request.Term,
+				Succeeded: false,
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		RelayFanout:     1,
+		Observers:       []string{"baz"},
+	}
+	protocol, _, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl))
+	for i := 0; i < 5; i++ {
+		stores.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(1),
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{},
+			},
+		})
+	}
+	stores.Writer().Compact(raft.Index(4))
+	assert.NoError(t, protocol.SetTerm(raft.Term(1)))
+	leader := raft.MemberID("bar")
+	assert.NoError(t, protocol.SetLeader(&leader))
+	protocol.WriteLock()
+	protocol.SetCommitIndex(raft.Index(5))
+	protocol.WriteUnlock()
+
+	// Verify the relay falls back to the leader once the member needs entries compacted from the local log
+	relay := newRelay(protocol, stores, util.NewNodeLogger("foo"))
+	relay.tick()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(relay.unrelayedMembers()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []raft.MemberID{"baz"}, relay.unrelayedMembers())
+
+	// Verify the relay stops relaying to the member
+	relay.tick()
+	relay.mu.Lock()
+	assert.Len(t, relay.members, 0)
+	relay.mu.Unlock()
+	relay.stop()
+}
@@ -16,21 +16,32 @@ package roles
 
 import (
 	"context"
+	"fmt"
 	"github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"sync"
 	"time"
 )
 
+// transferPollInterval is the interval at which the leader checks whether a leadership transfer target is caught up
+const transferPollInterval = 10 * time.Millisecond
+
 // newLeaderRole returns a new leader role
 func newLeaderRole(protocol raft.Raft, state state.Manager, store store.Store) raft.Role {
 	log := util.NewRoleLogger(string(protocol.Member()), string(raft.RoleLeader))
 	return &LeaderRole{
 		ActiveRole: newActiveRole(protocol, state, store, log),
 		appender:   newAppender(protocol, state, store, log),
+		ready:      make(chan struct{}),
+		caughtUp:   make(chan struct{}),
+		stopped:    make(chan struct{}),
+		handedOff:  make(chan struct{}),
+		conflicts:  metrics.NewCounter("raft_leader_conflicts_total", string(protocol.Member())),
 	}
 }
 
@@ -39,6 +50,17 @@ type LeaderRole struct {
 	*ActiveRole
 	appender  *raftAppender
 	initIndex raft.Index
+	ready     chan struct{}
+	caughtUp  chan struct{}
+	stopped   chan struct{}
+	stopOnce  sync.Once
+	conflicts *metrics.Counter
+	// transferee is the member to which leadership is being transferred until the transfer deadline, guarded by the
+	// write lock
+	transferee       raft.MemberID
+	transferDeadline time.Time
+	// handedOff is closed once a leader that transferred leadership to this member has stopped serving reads
+	handedOff chan struct{}
 }
 
 // Type is the role type
@@ -49,11 +71,131 @@ func (r *LeaderRole) Type() raft.RoleType {
 // Start starts the leader
 func (r *LeaderRole) Start() error {
 	r.setLeadership()
+	r.startCatchUp()
 	go r.startAppender()
 	go r.commitInitializeEntry()
+	go r.completeHandoff(r.raft.Handoff())
+	if interval := r.raft.Config().GetIdleNoopIntervalOrDefault(); interval > 0 {
+		go r.commitIdleEntries(interval)
+	}
 	return r.ActiveRole.Start()
 }
 
+// Progress returns the replication progress of each member
+func (r *LeaderRole) Progress() []*raft.MemberProgress {
+	return r.appender.progress()
+}
+
+// ForceInstall marks the given member to be caught up by installing the current snapshot rather than appending entries
+func (r *LeaderRole) ForceInstall(member raft.MemberID) error {
+	return r.appender.forceInstall(member)
+}
+
+// ElectionTimeoutChanged updates the leadership lease and heartbeat intervals for the updated election timeout
+func (r *LeaderRole) ElectionTimeoutChanged(timeout time.Duration) {
+	r.appender.setElectionTimeout(timeout)
+}
+
+// Ready returns a channel that is closed once the leader's no-op entry has been committed
+// Until the leader has committed an entry from its own term, it cannot know which entries from prior
+// terms are committed, so linearizable reads must not be served before the channel is closed.
+func (r *LeaderRole) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// CaughtUp returns a channel that is closed once the leader has applied the entries committed before its election
+// A newly elected leader's state machine may lag behind the commit index, so reads must not be served by the
+// leader before the channel is closed.
+func (r *LeaderRole) CaughtUp() <-chan struct{} {
+	return r.caughtUp
+}
+
+// startCatchUp sets the leader's status to catching up until the entries committed before its election are applied
+func (r *LeaderRole) startCatchUp() {
+	term := r.raft.Term()
+	index := r.raft.CommitIndex()
+	r.raft.SetCatchingUp(true)
+	go func() {
+		<-r.state.WaitApplied(index)
+		r.raft.WriteLock()
+		if r.raft.Role() == raft.RoleLeader && r.raft.Term() == term {
+			r.log.Debug("Caught up to commit index %d", index)
+			r.raft.SetCatchingUp(false)
+		}
+		r.raft.WriteUnlock()
+		close(r.caughtUp)
+	}()
+}
+
+// awaitReady waits for the leader's no-op entry to be committed, returning false if it was not
+// committed within an election timeout
+func (r *LeaderRole) awaitReady() bool {
+	return r.await(r.ready)
+}
+
+// awaitCaughtUp waits for the leader to apply the entries committed before its election, returning false if
+// they were not applied within an election timeout
+func (r *LeaderRole) awaitCaughtUp() bool {
+	return r.await(r.caughtUp)
+}
+
+// await waits for the given channel to be closed, returning false if it was not closed within an election timeout
+func (r *LeaderRole) await(ch <-chan struct{}) bool {
+	return r.awaitTimeout(ch, r.raft.Config().GetElectionTimeoutOrDefault())
+}
+
+// awaitTimeout waits for the given channel to be closed, returning false if it was not closed within the timeout
+func (r *LeaderRole) awaitTimeout(ch <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ch:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// completeHandoff waits for a leader that transferred leadership to this member to stop serving reads
+// The previous leader serves reads from its local state until it learns of its successor or its lease expires. Its
+// lease can only have been renewed before this member was elected, so the leader waits for the previous leader to
+// accept it for up to an election timeout before serving linearizable reads or accepting commands.
+func (r *LeaderRole) completeHandoff(handoff *raft.Handoff) {
+	defer close(r.handedOff)
+	if handoff == nil || handoff.Leader == r.raft.Member() {
+		return
+	}
+
+	r.log.Debug("Waiting for %s to hand off leadership", handoff.Leader)
+	timeout := time.NewTimer(r.raft.Config().GetElectionTimeoutOrDefault())
+	defer timeout.Stop()
+	ticker := time.NewTicker(transferPollInterval)
+	defer ticker.Stop()
+	for !r.appender.acknowledged(handoff.Leader) {
+		select {
+		case <-ticker.C:
+			continue
+		case <-timeout.C:
+			r.log.Debug("%s did not acknowledge the handoff before its lease expired", handoff.Leader)
+		case <-r.stopped:
+			return
+		}
+		break
+	}
+
+	r.raft.WriteLock()
+	if r.raft.Handoff() == handoff {
+		r.raft.SetHandoff(nil)
+	}
+	r.raft.WriteUnlock()
+}
+
 // setLeadership sets the leader as the current leader
 func (r *LeaderRole) setLeadership() {
 	member := r.raft.Member()
@@ -74,7 +216,7 @@ func (r *LeaderRole) commitInitializeEntry() {
 	// Create and append an InitializeEntry.
 	entry := &raft.LogEntry{
 		Term:      r.raft.Term(),
-		Timestamp: time.Now(),
+		Timestamp: r.nextTimestamp(),
 		Entry: &raft.LogEntry_Initialize{
 			Initialize: &raft.InitializeEntry{},
 		},
@@ -88,7 +230,11 @@ func (r *LeaderRole) commitInitializeEntry() {
 	// at least one entry from their current term has been stored on a majority of servers. Thus,
 	// we force entries to be appended up to the leader's no-op entry. The LeaderAppender will ensure
 	// that the commitIndex is not increased until the no-op entry is committed.
-	err := r.appender.commit(indexed, nil)
+	// Once the no-op entry is committed, apply it and open the gate for linearizable reads.
+	err := r.appender.commit(indexed, func() {
+		r.state.ApplyEntry(indexed, nil)
+		close(r.ready)
+	})
 	if err != nil {
 		r.log.Debug("Failed to commit entry from leader's term; transitioning to follower")
 		r.raft.WriteLock()
@@ -97,20 +243,70 @@ func (r *LeaderRole) commitInitializeEntry() {
 			r.log.Error("Failed to unset leader", err)
 		}
 		r.raft.SetRole(raft.RoleFollower)
-	} else {
-		r.state.ApplyEntry(indexed, nil)
+	}
+}
+
+// commitIdleEntries commits a no-op entry at the given interval while no other entries are appended to the log and
+// the log contains uncommitted entries
+// The commit index only advances when an entry from the leader's term is committed, so committing no-op entries
+// while the leader is idle bounds how far the commit index can lag behind the log. Once the log is committed, no
+// further no-op entries are appended until new entries are appended and left uncommitted.
+func (r *LeaderRole) commitIdleEntries(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	r.raft.ReadLock()
+	lastIndex := r.store.Writer().LastIndex()
+	r.raft.ReadUnlock()
+	for {
+		select {
+		case <-ticker.C:
+			r.raft.WriteLock()
+			if index := r.store.Writer().LastIndex(); index != lastIndex || r.raft.CommitIndex() >= index {
+				lastIndex = index
+				r.raft.WriteUnlock()
+				continue
+			}
+			indexed := r.store.Writer().Append(&raft.LogEntry{
+				Term:      r.raft.Term(),
+				Timestamp: r.nextTimestamp(),
+				Entry: &raft.LogEntry_Initialize{
+					Initialize: &raft.InitializeEntry{},
+				},
+			})
+			lastIndex = indexed.Index
+			r.raft.WriteUnlock()
+
+			r.log.Debug("Committing no-op entry %d while idle", indexed.Index)
+			err := r.appender.commit(indexed, func() {
+				r.state.ApplyEntry(indexed, nil)
+			})
+			if err != nil {
+				r.log.Debug("Failed to commit no-op entry %d: %v", indexed.Index, err)
+			}
+		case <-r.stopped:
+			return
+		}
 	}
 }
 
 // Poll handles a poll request
 func (r *LeaderRole) Poll(ctx context.Context, request *raft.PollRequest) (*raft.PollResponse, error) {
 	r.log.Request("PollRequest", request)
+	// Non-voting members poll the leader to rediscover it after losing contact, so replication to them resumes at once.
+	if member := r.raft.GetMember(request.Candidate); member != nil && member.Type == raft.Member_PASSIVE {
+		r.appender.reconnect(request.Candidate)
+	} else {
+		r.appender.probe(request.Candidate)
+	}
 	r.raft.ReadLock()
 	defer r.raft.ReadUnlock()
 	response := &raft.PollResponse{
-		Status:   raft.ResponseStatus_OK,
-		Term:     r.raft.Term(),
-		Accepted: false,
+		Status:    raft.ResponseStatus_OK,
+		Term:      r.raft.Term(),
+		Accepted:  false,
+		Rejection: raft.RejectionReason_LEADER_EXISTS,
+		Leader:    r.raft.Member(),
 	}
 	_ = r.log.Response("PollResponse", response, nil)
 	return response, nil
@@ -119,10 +315,42 @@ func (r *LeaderRole) Poll(ctx context.Context, request *raft.PollRequest) (*raft
 // Vote handles a vote request
 func (r *LeaderRole) Vote(ctx context.Context, request *raft.VoteRequest) (*raft.VoteResponse, error) {
 	r.log.Request("VoteRequest", request)
+	r.appender.probe(request.Candidate)
 	r.raft.WriteLock()
 	defer r.raft.WriteUnlock()
+	if response := r.protectLeadership(request); response != nil {
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+
+	// The leader must not vote for another member while its lease is valid, or reads could be served from the
+	// lease after another leader has been elected. The member leadership is being transferred to doesn't serve
+	// requests until this member has stepped down or the lease has expired.
+	transferring := r.isTransferee(request.Candidate)
+	if !transferring && request.Term >= r.raft.Term() && r.appender.leaseValid() {
+		response := &raft.VoteResponse{
+			Status:    raft.ResponseStatus_OK,
+			Term:      r.raft.Term(),
+			Voted:     false,
+			Rejection: raft.RejectionReason_LEADER_EXISTS,
+		}
+		r.log.Debug("Rejected %v: the leader's lease is valid", request)
+		_ = r.log.Response("VoteResponse", response, nil)
+		return response, nil
+	}
+	term := r.raft.Term()
 	if r.updateTermAndLeader(request.Term, nil) {
 		r.log.Debug("Received greater term")
+
+		// If the vote was requested by the member leadership is being transferred to, record the handoff to
+		// continue serving reads from the local state until the lease expires or the new leader is learned.
+		if transferring {
+			r.raft.SetHandoff(&raft.Handoff{
+				Term:   term,
+				Leader: r.raft.Member(),
+				Until:  r.appender.lease.until(),
+			})
+		}
 		defer r.raft.SetRole(raft.RoleFollower)
 		response, err := r.ActiveRole.handleVote(ctx, request)
 		_ = r.log.Response("VoteResponse", response, err)
@@ -130,9 +358,10 @@ func (r *LeaderRole) Vote(ctx context.Context, request *raft.VoteRequest) (*raft
 	}
 
 	response := &raft.VoteResponse{
-		Status: raft.ResponseStatus_OK,
-		Term:   r.raft.Term(),
-		Voted:  false,
+		Status:    raft.ResponseStatus_OK,
+		Term:      r.raft.Term(),
+		Voted:     false,
+		Rejection: raft.RejectionReason_LEADER_EXISTS,
 	}
 	_ = r.log.Response("VoteResponse", response, nil)
 	return response, nil
@@ -158,6 +387,20 @@ func (r *LeaderRole) Append(ctx context.Context, request *raft.AppendRequest) (*
 		}
 		_ = r.log.Response("AppendResponse", response, nil)
 		return response, nil
+	} else if request.Leader != r.raft.Member() {
+		// Raft never elects two leaders in the same term, so another leader in this term indicates the cluster
+		// has diverged, e.g. due to duplicate member IDs. Step down without appending the other leader's entries.
+		r.log.Error("Received append from conflicting leader %s in term %d; the cluster may have diverged, stepping down", request.Leader, request.Term)
+		r.conflicts.Inc()
+		defer r.raft.SetRole(raft.RoleFollower)
+		response := &raft.AppendResponse{
+			Status:       raft.ResponseStatus_OK,
+			Term:         r.raft.Term(),
+			Succeeded:    false,
+			LastLogIndex: r.store.Writer().LastIndex(),
+		}
+		_ = r.log.Response("AppendResponse", response, nil)
+		return response, nil
 	}
 
 	response, err := r.ActiveRole.handleAppend(ctx, request)
@@ -165,23 +408,205 @@ func (r *LeaderRole) Append(ctx context.Context, request *raft.AppendRequest) (*
 	return response, err
 }
 
+// Transfer transfers leadership to the requested member, or to the most up-to-date voter if no member is requested
+// The leader waits up to an election timeout for the member to catch up with its log, then requests that the member
+// start an election. The leader steps down once it receives the member's vote request for the next term.
+func (r *LeaderRole) Transfer(ctx context.Context, request *raft.TransferRequest) (*raft.TransferResponse, error) {
+	r.log.Request("TransferRequest", request)
+	member := request.Member
+	if member == "" {
+		member = r.transferTarget()
+	}
+	if target := r.raft.GetMember(member); target == nil || target.Type != raft.Member_ACTIVE || member == r.raft.Member() {
+		r.log.Warn("Cannot transfer leadership to %q: the member is not an active voter", member)
+		response := &raft.TransferResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+		}
+		_ = r.log.Response("TransferResponse", response, nil)
+		return response, nil
+	}
+
+	if !r.awaitTransferTarget(ctx, member) {
+		r.log.Warn("Cannot transfer leadership to %s: the member did not catch up with the log", member)
+		response := &raft.TransferResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_TIMEOUT,
+		}
+		_ = r.log.Response("TransferResponse", response, nil)
+		return response, nil
+	}
+
+	r.log.Info("Transferring leadership to %s", member)
+
+	// The lease is held through the transfer: the target doesn't serve reads or accept commands until this member
+	// has acknowledged it or the lease has expired.
+	r.raft.WriteLock()
+	r.transferee = member
+	r.transferDeadline = time.Now().Add(r.raft.Config().GetElectionTimeoutOrDefault())
+	transferRequest := &raft.TransferRequest{
+		Member: member,
+		Term:   r.raft.Term(),
+		Leader: r.raft.Member(),
+	}
+	r.raft.WriteUnlock()
+	r.log.Send("TransferRequest", transferRequest)
+	response, err := r.raft.Protocol().Transfer(ctx, transferRequest, member)
+	if err != nil {
+		r.log.Warn("Failed to transfer leadership to %s", member, err)
+		response = &raft.TransferResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_UNAVAILABLE,
+		}
+	} else {
+		r.log.Receive("TransferResponse", response)
+	}
+	if response.Status != raft.ResponseStatus_OK {
+		r.raft.WriteLock()
+		r.transferee = ""
+		r.raft.WriteUnlock()
+	}
+	_ = r.log.Response("TransferResponse", response, nil)
+	return response, nil
+}
+
+// isTransferee returns a bool indicating whether leadership is being transferred to the given member
+// A target that doesn't request votes within an election timeout of the transfer request isn't going to take over,
+// so the transfer is abandoned rather than letting the target bypass the lease in later elections. isTransferee must
+// be called with the write lock held.
+func (r *LeaderRole) isTransferee(member raft.MemberID) bool {
+	if r.transferee == "" {
+		return false
+	}
+	if time.Now().After(r.transferDeadline) {
+		r.log.Warn("Abandoning leadership transfer to %s: the member did not start an election", r.transferee)
+		r.transferee = ""
+		return false
+	}
+	return member == r.transferee
+}
+
+// transferTarget returns the healthy active voter with the greatest match index, or an empty ID if there is none
+func (r *LeaderRole) transferTarget() raft.MemberID {
+	var target raft.MemberID
+	var matchIndex raft.Index
+	for _, progress := range r.appender.progress() {
+		member := r.raft.GetMember(progress.MemberID)
+		if member == nil || member.Type != raft.Member_ACTIVE || progress.Degraded {
+			continue
+		}
+		if target == "" || progress.MatchIndex > matchIndex {
+			target = progress.MemberID
+			matchIndex = progress.MatchIndex
+		}
+	}
+	return target
+}
+
+// awaitTransferTarget waits for the given member to replicate the last entry in the leader's log
+// It returns a bool indicating whether the member caught up within an election timeout.
+func (r *LeaderRole) awaitTransferTarget(ctx context.Context, member raft.MemberID) bool {
+	timeout := time.NewTimer(r.raft.Config().GetElectionTimeoutOrDefault())
+	defer timeout.Stop()
+	ticker := time.NewTicker(transferPollInterval)
+	defer ticker.Stop()
+	for {
+		r.raft.ReadLock()
+		lastIndex := r.store.Writer().LastIndex()
+		r.raft.ReadUnlock()
+		for _, progress := range r.appender.progress() {
+			if progress.MemberID == member && progress.MatchIndex >= lastIndex {
+				return true
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			return false
+		case <-ctx.Done():
+			return false
+		case <-r.stopped:
+			return false
+		}
+	}
+}
+
 // Command handles a command request
 func (r *LeaderRole) Command(request *raft.CommandRequest, responseCh chan<- *raft.CommandStreamResponse) error {
 	r.log.Request("CommandRequest", request)
 	defer close(responseCh)
 
+	// Reject new commands once the server is shutting down or the state machine has faulted.
+	switch r.raft.Status() {
+	case raft.StatusStopping:
+		r.rejectCommand(raft.ErrShuttingDown, raft.ErrShuttingDown.Error(), responseCh)
+		return nil
+	case raft.StatusFaulted:
+		r.rejectCommand(raft.ErrFaulted, raft.ErrFaulted.Error(), responseCh)
+		return nil
+	}
+
+	// If a warm-up is configured, wait for a new leader to establish contact with a quorum before accepting the
+	// command. Commands that could not be committed are rejected rather than left to time out.
+	if warmup := r.raft.Config().GetLeaderWarmupOrDefault(); warmup > 0 && !r.awaitTimeout(r.ready, warmup) {
+		r.rejectCommand(raft.ErrLeaderInitializing, raft.ErrLeaderInitializing.Error(), responseCh)
+		return nil
+	}
+
+	// A leader elected by a leadership transfer doesn't accept commands until the previous leader has stopped
+	// serving reads, so the previous leader can't serve a read that misses a write this leader has acknowledged.
+	if !r.await(r.handedOff) {
+		r.rejectCommand(raft.ErrLeaderInitializing, raft.ErrLeaderInitializing.Error(), responseCh)
+		return nil
+	}
+
+	// Reject the command if the store is too low on space to safely append to the log.
+	if err := r.store.CheckDiskSpace(); err != nil {
+		r.rejectCommand(err, err.Error(), responseCh)
+		return nil
+	}
+
+	// Wait for room in the apply queue to bound the number of committed entries waiting to be applied. The command
+	// is rejected with a retryable error if the queue remains full for an election timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), r.raft.Config().GetElectionTimeoutOrDefault())
+	err := r.appender.reserveApply(ctx)
+	cancel()
+	if err != nil {
+		r.rejectCommand(err, err.Error(), responseCh)
+		return nil
+	}
+
 	// Acquire the write lock to write the entry to the log.
 	r.raft.WriteLock()
 
 	entry := &raft.LogEntry{
 		Term:      r.raft.Term(),
-		Timestamp: time.Now(),
+		Timestamp: r.nextTimestamp(),
 		Entry: &raft.LogEntry_Command{
 			Command: &raft.CommandEntry{
 				Value: request.Value,
 			},
 		},
 	}
+
+	// Reject entries that are too large to be replicated before they're appended to the log.
+	if size, maxSize := entry.Size(), r.raft.Config().GetMaxEntrySizeOrDefault(); size > maxSize {
+		r.raft.WriteUnlock()
+		r.appender.releaseApply()
+		r.rejectCommand(raft.ErrEntryTooLarge, fmt.Sprintf("entry size %d exceeds the maximum entry size %d", size, maxSize), responseCh)
+		return nil
+	}
+
+	// Bound the number of uncommitted entries to limit the growth of the log while the leader cannot commit.
+	// The command can be retried once commits catch up.
+	if maxUncommitted := raft.Index(r.raft.Config().GetMaxUncommittedEntries()); maxUncommitted > 0 {
+		if uncommitted := r.store.Writer().LastIndex() - r.raft.CommitIndex(); uncommitted >= maxUncommitted {
+			r.raft.WriteUnlock()
+			r.appender.releaseApply()
+			r.rejectCommand(raft.ErrOverloaded, fmt.Sprintf("the log has %d uncommitted entries; the maximum is %d", uncommitted, maxUncommitted), responseCh)
+			return nil
+		}
+	}
 	indexed := r.store.Writer().Append(entry)
 
 	// Release the write lock immediately after appending the entry to ensure the appenders
@@ -193,15 +618,25 @@ func (r *LeaderRole) Command(request *raft.CommandRequest, responseCh chan<- *ra
 	// are committed by the appender.
 	outputCh := make(chan stream.Result)
 	f := func() {
-		r.state.ApplyEntry(indexed, stream.NewChannelStream(outputCh))
+		r.state.ApplyEntry(indexed, newAppliedStream(stream.NewChannelStream(outputCh), r.appender.releaseApply))
 	}
 
 	// Pass the apply function to the appender to be called when the change is committed.
 	if err := r.appender.commit(indexed, f); err != nil {
+		r.appender.releaseApply()
+		r.raft.ReadLock()
+		leader := raft.MemberID("")
+		if r.raft.Leader() != nil {
+			leader = *r.raft.Leader()
+		}
 		response := &raft.CommandResponse{
-			Status: raft.ResponseStatus_ERROR,
-			Error:  raft.ResponseError_PROTOCOL_ERROR,
+			Status:  raft.ResponseStatus_ERROR,
+			Error:   raft.GetResponseError(err),
+			Message: err.Error(),
+			Leader:  leader,
+			Term:    r.raft.Term(),
 		}
+		r.raft.ReadUnlock()
 		_ = r.log.Response("CommandResponse", response, nil)
 		responseCh <- raft.NewCommandStreamResponse(response, nil)
 		return nil
@@ -228,6 +663,7 @@ func (r *LeaderRole) Command(request *raft.CommandRequest, responseCh chan<- *ra
 			Term:    r.raft.Term(),
 			Members: r.raft.Members(),
 			Output:  output.Value.([]byte),
+			Index:   indexed.Index,
 		}
 		r.raft.ReadUnlock()
 		_ = r.log.Response("CommandResponse", response, nil)
@@ -236,11 +672,59 @@ func (r *LeaderRole) Command(request *raft.CommandRequest, responseCh chan<- *ra
 	return nil
 }
 
+// rejectCommand responds to a command that was rejected before being appended to the log
+func (r *LeaderRole) rejectCommand(err error, message string, responseCh chan<- *raft.CommandStreamResponse) {
+	r.raft.ReadLock()
+	response := &raft.CommandResponse{
+		Status:  raft.ResponseStatus_ERROR,
+		Error:   raft.GetResponseError(err),
+		Message: message,
+		Leader:  r.raft.Member(),
+		Term:    r.raft.Term(),
+	}
+	r.raft.ReadUnlock()
+	_ = r.log.Response("CommandResponse", response, nil)
+	responseCh <- raft.NewCommandStreamResponse(response, nil)
+}
+
 // Query handles a query request
 func (r *LeaderRole) Query(request *raft.QueryRequest, responseCh chan<- *raft.QueryStreamResponse) error {
 	r.log.Request("QueryRequest", request)
 	defer close(responseCh)
 
+	// A faulted state machine can't serve reads.
+	if r.raft.Status() == raft.StatusFaulted {
+		response := &raft.QueryResponse{
+			Status:  raft.ResponseStatus_ERROR,
+			Error:   raft.ResponseError_FAULTED,
+			Message: raft.ErrFaulted.Error(),
+		}
+		_ = r.log.Response("QueryResponse", response, nil)
+		responseCh <- raft.NewQueryStreamResponse(response, nil)
+		return nil
+	}
+
+	// Reads other than stale reads must wait for the leader to apply the entries committed before its election,
+	// and linearizable reads must also wait for the leader to commit an entry from its term and for any previous
+	// leader that transferred leadership to it to stop serving reads.
+	stale := request.ReadConsistency == raft.ReadConsistency_STALE
+	linearizable := request.ReadConsistency != raft.ReadConsistency_SEQUENTIAL && !stale
+	if (!stale && !r.awaitCaughtUp()) || (linearizable && (!r.awaitReady() || !r.await(r.handedOff))) {
+		response := &raft.QueryResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+		}
+		_ = r.log.Response("QueryResponse", response, nil)
+		responseCh <- raft.NewQueryStreamResponse(response, nil)
+		return nil
+	}
+
+	// A newly elected leader may not yet have committed the query's minimum index.
+	if !r.awaitMinIndex(request) {
+		r.rejectMinIndex(request, responseCh)
+		return nil
+	}
+
 	// Acquire a read lock before creating the entry.
 	r.raft.ReadLock()
 
@@ -266,7 +750,7 @@ func (r *LeaderRole) Query(request *raft.QueryRequest, responseCh chan<- *raft.Q
 		return r.queryLinearizable(entry, responseCh)
 	case raft.ReadConsistency_LINEARIZABLE_LEASE:
 		return r.queryLinearizableLease(entry, responseCh)
-	case raft.ReadConsistency_SEQUENTIAL:
+	case raft.ReadConsistency_SEQUENTIAL, raft.ReadConsistency_STALE:
 		return r.querySequential(entry, responseCh)
 	default:
 		return r.queryLinearizable(entry, responseCh)
@@ -285,7 +769,14 @@ func (r *LeaderRole) queryLinearizable(entry *log.Entry, responseCh chan<- *raft
 	for result := range ch {
 		// Send a heartbeat to a majority of the cluster to verify leadership.
 		if err := r.appender.heartbeat(); err != nil {
-			return r.log.Response("QueryResponse", nil, err)
+			response := &raft.QueryResponse{
+				Status:  raft.ResponseStatus_ERROR,
+				Error:   raft.GetResponseError(err),
+				Message: err.Error(),
+			}
+			_ = r.log.Response("QueryResponse", response, nil)
+			responseCh <- raft.NewQueryStreamResponse(response, nil)
+			continue
 		}
 		if result.Succeeded() {
 			response := &raft.QueryResponse{
@@ -307,8 +798,14 @@ func (r *LeaderRole) queryLinearizable(entry *log.Entry, responseCh chan<- *raft
 }
 
 // queryLinearizableLease performs a lease query
+// The query is served locally while the leader holds a leadership lease. Once the lease has lapsed, the query
+// falls back to verifying leadership with a quorum as a linearizable query.
 func (r *LeaderRole) queryLinearizableLease(entry *log.Entry, responseCh chan<- *raft.QueryStreamResponse) error {
-	return r.applyQuery(entry, responseCh)
+	if r.appender.leaseValid() {
+		return r.applyQuery(entry, responseCh)
+	}
+	r.log.Debug("Leadership lease expired; verifying leadership for query")
+	return r.queryLinearizable(entry, responseCh)
 }
 
 // querySequential performs a sequential query
@@ -326,8 +823,29 @@ func (r *LeaderRole) stepDown() {
 }
 
 // Stop stops the leader
+// If the server is shutting down, pending writes are given up to an election timeout to commit before the
+// remaining requests are failed with ErrShuttingDown. Stopping a stopped leader has no effect.
 func (r *LeaderRole) Stop() error {
-	r.appender.stop()
-	r.stepDown()
+	r.stopOnce.Do(func() {
+		shuttingDown := r.raft.Status() == raft.StatusStopping
+		if shuttingDown {
+			if !r.appender.drain(r.raft.Config().GetElectionTimeoutOrDefault()) {
+				r.log.Warn("Failed to drain pending commits before shutting down")
+			}
+			r.appender.stop(raft.ErrShuttingDown)
+		} else {
+			r.appender.stop(&raft.ErrNotLeader{})
+		}
+		close(r.stopped)
+
+		// Role transitions stop the leader with a lock on the state, but the state is closed without a lock to
+		// allow pending commits to drain, so the lock must be acquired before updating the state.
+		if shuttingDown {
+			r.raft.WriteLock()
+			defer r.raft.WriteUnlock()
+		}
+		r.raft.SetCatchingUp(false)
+		r.stepDown()
+	})
 	return nil
 }
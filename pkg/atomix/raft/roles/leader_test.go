@@ -16,12 +16,24 @@ package roles
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/atomix/go-framework/pkg/atomix/service"
+	"github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -89,6 +101,8 @@ func TestLeaderPoll(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.False(t, response.Accepted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, response.Rejection)
+	assert.Equal(t, role.raft.Member(), response.Leader)
 }
 
 func TestLeaderVote(t *testing.T) {
@@ -101,7 +115,22 @@ func TestLeaderVote(t *testing.T) {
 	assert.NoError(t, role.Start())
 	awaitIndex(role.raft, role.store.Log(), raft.Index(1))
 
+	// Verify the leader rejects votes while its lease is valid
+	role.appender.lease.renew(time.Now())
 	response, err := role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         raft.Term(2),
+		Candidate:    role.raft.Members()[1],
+		LastLogIndex: 1,
+		LastLogTerm:  1,
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, response.Rejection)
+	assert.Equal(t, raft.Term(1), response.Term)
+
+	// Verify the leader steps down once its lease has expired
+	role.appender.lease.reset(0)
+	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
 		Term:         raft.Term(2),
 		Candidate:    role.raft.Members()[1],
 		LastLogIndex: 0,
@@ -109,6 +138,7 @@ func TestLeaderVote(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
 	assert.Equal(t, raft.RoleFollower, awaitRole(role.raft, raft.RoleFollower))
 	assert.Equal(t, raft.Term(2), awaitTerm(role.raft, raft.Term(2)))
 
@@ -116,6 +146,7 @@ func TestLeaderVote(t *testing.T) {
 	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
 	assert.NoError(t, role.Start())
 	awaitIndex(role.raft, role.store.Log(), raft.Index(1))
+	role.appender.lease.reset(0)
 
 	response, err = role.Vote(context.TODO(), &raft.VoteRequest{
 		Term:         raft.Term(2),
@@ -129,6 +160,38 @@ func TestLeaderVote(t *testing.T) {
 	assert.Equal(t, raft.Term(2), awaitTerm(role.raft, raft.Term(2)))
 }
 
+func TestLeaderMinLeadershipDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	minLeadershipDuration := 1 * time.Minute
+	config := &config.ProtocolConfig{
+		ElectionTimeout:       &electionTimeout,
+		MinLeadershipDuration: &minLeadershipDuration,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	awaitIndex(role.raft, role.store.Log(), raft.Index(1))
+
+	// Verify the leader does not step down for a candidate within its minimum leadership duration
+	response, err := role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         raft.Term(2),
+		Candidate:    role.raft.Members()[1],
+		LastLogIndex: 1,
+		LastLogTerm:  1,
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Voted)
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Term(1), role.raft.Term())
+	assert.Equal(t, role.raft.Member(), *role.raft.Leader())
+	role.raft.ReadUnlock()
+}
+
 func TestLeaderAppendPriorTerm(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
@@ -178,6 +241,64 @@ func TestLeaderAppendGreaterTerm(t *testing.T) {
 	assert.Equal(t, &leader, awaitLeader(role.raft, &leader))
 }
 
+func TestLeaderStaleHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Simulate members that elected a new leader in term 2 while the old leader was partitioned
+	electionTimeout := 10 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	newLeader := raft.MemberID("baz")
+	followers := make(map[raft.MemberID]*FollowerRole)
+	for _, member := range []raft.MemberID{"bar", "baz"} {
+		followerClient := mock.NewMockClient(ctrl)
+		rejectPoll(followerClient).AnyTimes()
+		protocol, sm, stores := newTestStateWithStore(followerClient, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+		follower := newFollowerRole(protocol, sm, stores, NewRandom()).(*FollowerRole)
+		follower.raft.WriteLock()
+		assert.NoError(t, follower.raft.SetTerm(raft.Term(2)))
+		assert.NoError(t, follower.raft.SetLeader(&newLeader))
+		follower.raft.WriteUnlock()
+		assert.NoError(t, follower.Start())
+		defer follower.Stop()
+		followers[member] = follower
+	}
+
+	// Route the old leader's appends to the members, counting the appends received by each member
+	var appends sync.Map
+	client := mock.NewMockClient(ctrl)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			count, _ := appends.LoadOrStore(member, new(int32))
+			atomic.AddInt32(count.(*int32), 1)
+			return followers[member].Append(ctx, request)
+		}).AnyTimes()
+
+	// Rejoin the old leader in term 1 and verify it steps down on its first heartbeat to each member
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	assert.Equal(t, raft.RoleFollower, awaitRole(role.raft, raft.RoleFollower))
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Term(2), role.raft.Term())
+	assert.Nil(t, role.raft.Leader())
+	role.raft.ReadUnlock()
+	appends.Range(func(member, count interface{}) bool {
+		assert.Equal(t, int32(1), atomic.LoadInt32(count.(*int32)), "member %s", member)
+		return true
+	})
+
+	// Verify the members rejected the stale heartbeats and kept the new leader
+	for _, follower := range followers {
+		follower.raft.ReadLock()
+		assert.Equal(t, raft.Term(2), follower.raft.Term())
+		assert.Equal(t, newLeader, *follower.raft.Leader())
+		follower.raft.ReadUnlock()
+	}
+}
+
 func TestLeaderSendSnapshot(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
@@ -210,7 +331,7 @@ func TestLeaderSendSnapshot(t *testing.T) {
 	})
 
 	// Add a snapshot to the log at index 100
-	snapshot := role.store.Snapshot().NewSnapshot(raft.Index(100), time.Now())
+	snapshot := role.store.Snapshot().NewSnapshot(raft.Index(100), raft.Term(1), time.Now(), nil)
 	writer := snapshot.Writer()
 	_, _ = writer.Write([]byte("abc"))
 	writer.Close()
@@ -228,6 +349,7 @@ func TestLeaderSendSnapshot(t *testing.T) {
 	role.raft.ReadUnlock()
 
 	assert.Equal(t, raft.Index(102), awaitCommit(role.raft, raft.Index(102)))
+	assert.NoError(t, role.Stop())
 }
 
 func TestLeaderCommand(t *testing.T) {
@@ -274,7 +396,8 @@ func TestLeaderCommand(t *testing.T) {
 	request = &raft.CommandRequest{
 		Value: newSetRequest("SetStream", sessionID, 2),
 	}
-	ch = make(chan *raft.CommandStreamResponse, 3)
+	// Stream operations are framed by the session's open and close responses.
+	ch = make(chan *raft.CommandStreamResponse, 5)
 	err = role.Command(request, ch)
 	assert.NoError(t, err)
 	response = <-ch
@@ -286,6 +409,12 @@ func TestLeaderCommand(t *testing.T) {
 	response = <-ch
 	assert.True(t, response.Succeeded())
 	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	response = <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	response = <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
 
 	role.raft.ReadLock()
 	assert.Equal(t, raft.Index(4), role.raft.CommitIndex())
@@ -312,6 +441,511 @@ func TestLeaderCommand(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestLeaderCommandQuorumLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client)
+	succeedAppend(client)
+	failAppend(client).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_UNAVAILABLE, response.Response.Error)
+	assert.Equal(t, raft.ErrQuorumLost, raft.NewCommandError(response.Response))
+}
+
+func TestLeaderCommandStepDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client)
+	succeedAppend(client)
+
+	// Block appends so the command cannot be committed before the leader steps down
+	release := make(chan struct{})
+	defer close(release)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			<-release
+			return nil, errors.New("AppendRequest failed")
+		}).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	}()
+	awaitIndex(role.raft, role.store.Log(), raft.Index(2))
+	role.raft.WriteLock()
+	assert.NoError(t, role.Stop())
+	role.raft.WriteUnlock()
+
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_ILLEGAL_MEMBER_STATE, response.Response.Error)
+	_, ok := raft.NewCommandError(response.Response).(*raft.ErrNotLeader)
+	assert.True(t, ok)
+}
+
+func TestLeaderCommandDiskFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	fs := &lowSpaceFileSystem{free: 1024 * 1024}
+	electionTimeout := 1 * time.Second
+	diskCheckInterval := time.Duration(0)
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		Storage: &config.StorageConfig{
+			Directory:         "/data",
+			MinFreeDiskSpace:  1024,
+			DiskCheckInterval: &diskCheckInterval,
+		},
+	}
+	store := store.NewDiskMonitoredStore(store.NewMemoryStore(), fs, config, raft.MemberID("foo"))
+	role := newLeaderRole(newTestStateWithStore(client, store, config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	openTestSession(t, role)
+	lastIndex := role.store.Writer().LastIndex()
+
+	// Verify commands are rejected without being appended while the disk is low on space
+	fs.free = 512
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_DISK_FULL, response.Response.Error)
+	assert.Equal(t, raft.ErrDiskFull, raft.NewCommandError(response.Response))
+	assert.Equal(t, lastIndex, role.store.Writer().LastIndex())
+
+	// Verify commands are accepted once space is freed
+	fs.free = 1024 * 1024
+	openTestSession(t, role)
+	assert.Equal(t, lastIndex+1, role.store.Writer().LastIndex())
+}
+
+func TestLeaderCommandTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		Storage: &config.StorageConfig{
+			MaxEntrySize: 1024,
+		},
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	openTestSession(t, role)
+	lastIndex := role.store.Writer().LastIndex()
+
+	// Verify commands larger than the maximum entry size are rejected without being appended
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: make([]byte, 2048)}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_ENTRY_TOO_LARGE, response.Response.Error)
+	assert.Equal(t, raft.ErrEntryTooLarge, raft.NewCommandError(response.Response))
+	assert.Equal(t, lastIndex, role.store.Writer().LastIndex())
+
+	// Verify commands within the limit are still accepted
+	openTestSession(t, role)
+	assert.Equal(t, lastIndex+1, role.store.Writer().LastIndex())
+}
+
+func TestLeaderCommandMaxUncommitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	var blocked int32
+	release := make(chan struct{})
+	blockAppends(client, &blocked, release).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout:       &electionTimeout,
+		MaxUncommittedEntries: 3,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	openTestSession(t, role)
+	lastIndex := role.store.Writer().LastIndex()
+
+	// Partition the followers and fill the log with uncommitted commands
+	atomic.StoreInt32(&blocked, 1)
+	pending := make([]chan *raft.CommandStreamResponse, 3)
+	for i := range pending {
+		ch := make(chan *raft.CommandStreamResponse, 1)
+		pending[i] = ch
+		go func() {
+			assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+		}()
+	}
+	for role.store.Writer().LastIndex() < lastIndex+3 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Verify proposals are rejected with a retryable error once the limit is reached
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_OVERLOADED, response.Response.Error)
+	assert.Equal(t, raft.ErrOverloaded, raft.NewCommandError(response.Response))
+	assert.Equal(t, lastIndex+3, role.store.Writer().LastIndex())
+
+	// Verify proposals are accepted once commits catch up
+	atomic.StoreInt32(&blocked, 0)
+	close(release)
+	for _, ch := range pending {
+		response := <-ch
+		assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	}
+	openTestSession(t, role)
+	assert.Equal(t, lastIndex+4, role.store.Writer().LastIndex())
+}
+
+// stalledStateManager is a state manager that stalls applying commands until released
+type stalledStateManager struct {
+	state.Manager
+	stalled int32
+	release chan struct{}
+	applied raft.Index
+}
+
+func (m *stalledStateManager) ApplyEntry(entry *log.Entry, stream stream.WriteStream) {
+	if _, ok := entry.Entry.Entry.(*raft.LogEntry_Command); ok && atomic.LoadInt32(&m.stalled) == 1 {
+		<-m.release
+	}
+	m.Manager.ApplyEntry(entry, stream)
+	atomic.StoreUint64((*uint64)(&m.applied), uint64(entry.Index))
+}
+
+func TestLeaderApplyQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		ApplyQueueSize:  3,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	stalled := &stalledStateManager{
+		Manager: sm,
+		release: make(chan struct{}),
+	}
+	role := newLeaderRole(protocol, stalled, store).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	openTestSession(t, role)
+	lastIndex := role.store.Writer().LastIndex()
+	depth := metrics.NewGauge("raft_apply_queue_depth", string(role.raft.Member()))
+	assert.Equal(t, int64(0), depth.Value())
+
+	// Stall the state machine and propose more commands than fit in the apply queue
+	atomic.StoreInt32(&stalled.stalled, 1)
+	pending := make([]chan *raft.CommandStreamResponse, 5)
+	for i := range pending {
+		ch := make(chan *raft.CommandStreamResponse, 1)
+		pending[i] = ch
+		go func() {
+			assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+		}()
+	}
+
+	// Verify the commit index advances ahead of the applied index up to the queue bound
+	for {
+		role.raft.ReadLock()
+		commitIndex := role.raft.CommitIndex()
+		role.raft.ReadUnlock()
+		if commitIndex == lastIndex+3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	role.raft.ReadLock()
+	assert.Equal(t, lastIndex+3, role.raft.CommitIndex())
+	role.raft.ReadUnlock()
+	assert.Equal(t, lastIndex, raft.Index(atomic.LoadUint64((*uint64)(&stalled.applied))))
+	assert.Equal(t, lastIndex+3, role.store.Writer().LastIndex())
+	assert.Equal(t, int64(3), depth.Value())
+
+	// Verify the remaining commands are proposed and all commands are applied once the state machine resumes
+	close(stalled.release)
+	for _, ch := range pending {
+		response := <-ch
+		assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	}
+	assert.Equal(t, lastIndex+5, role.store.Writer().LastIndex())
+	assert.Equal(t, lastIndex+5, raft.Index(atomic.LoadUint64((*uint64)(&stalled.applied))))
+	assert.Equal(t, int64(0), depth.Value())
+}
+
+// silentStateManager is a state manager that applies commands without writing their output
+type silentStateManager struct {
+	state.Manager
+}
+
+func (m *silentStateManager) ApplyEntry(entry *log.Entry, stream stream.WriteStream) {
+	if _, ok := entry.Entry.Entry.(*raft.LogEntry_Command); ok {
+		stream = &silentStream{stream.(state.AppliedStream)}
+	}
+	m.Manager.ApplyEntry(entry, stream)
+}
+
+// silentStream is a stream that discards output
+type silentStream struct {
+	state.AppliedStream
+}
+
+func (s *silentStream) Send(result stream.Result) {}
+
+func (s *silentStream) Result(value interface{}, err error) {}
+
+func (s *silentStream) Value(value interface{}) {}
+
+func (s *silentStream) Error(err error) {}
+
+func (s *silentStream) Close() {}
+
+func TestLeaderApplyQueueNoOutput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		ApplyQueueSize:  1,
+	}
+	protocol, sm, store := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newLeaderRole(protocol, &silentStateManager{Manager: sm}, store).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	for role.store.Writer().LastIndex() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	lastIndex := role.store.Writer().LastIndex()
+
+	// Verify slots are released once commands are applied even though the commands produce no output
+	for i := 0; i < 3; i++ {
+		go func() {
+			_ = role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, make(chan *raft.CommandStreamResponse, 1))
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for role.store.Writer().LastIndex() < lastIndex+3 {
+		if time.Now().After(deadline) {
+			t.Fatal("commands were not proposed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newClosableTestLeader returns a leader that is stopped when its Raft state is closed
+func newClosableTestLeader(ctrl *gomock.Controller, client raft.Client) *LeaderRole {
+	var role *LeaderRole
+	leader := mock.NewMockRole(ctrl)
+	leader.EXPECT().Type().Return(raft.RoleLeader).AnyTimes()
+	leader.EXPECT().Start().Return(nil).AnyTimes()
+	leader.EXPECT().Stop().DoAndReturn(func() error {
+		return role.Stop()
+	}).AnyTimes()
+	role = newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), leader)).(*LeaderRole)
+	role.raft.WriteLock()
+	role.raft.SetRole(raft.RoleLeader)
+	role.raft.WriteUnlock()
+	return role
+}
+
+// blockAppends blocks append requests until released while blocked is set
+func blockAppends(client *mock.MockClient, blocked *int32, release <-chan struct{}) *gomock.Call {
+	return client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			if atomic.LoadInt32(blocked) == 1 {
+				<-release
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		})
+}
+
+func TestLeaderCommandWarmup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	blocked := int32(1)
+	release := make(chan struct{})
+	blockAppends(client, &blocked, release).AnyTimes()
+
+	electionTimeout := 5 * time.Second
+	leaderWarmup := 200 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		LeaderWarmup:    &leaderWarmup,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+
+	// Verify a command issued before the leader has contacted a quorum is rejected once the warm-up expires
+	start := time.Now()
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	assert.Equal(t, raft.ResponseError_LEADER_INITIALIZING, response.Response.Error)
+	assert.Equal(t, raft.ErrLeaderInitializing, raft.NewCommandError(response.Response))
+	assert.True(t, time.Since(start) >= leaderWarmup)
+	assert.True(t, time.Since(start) < electionTimeout)
+
+	// Verify a command issued during the warm-up succeeds once the leader contacts a quorum
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreInt32(&blocked, 0)
+		close(release)
+	}()
+	ch = make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response = <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+}
+
+func TestLeaderCommandShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	var blocked int32
+	release := make(chan struct{})
+	defer close(release)
+	blockAppends(client, &blocked, release).AnyTimes()
+
+	role := newClosableTestLeader(ctrl, client)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	openTestSession(t, role)
+
+	// Issue a command that cannot be committed
+	atomic.StoreInt32(&blocked, 1)
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Shut down the server and verify the pending command fails with a shutdown error once draining times out
+	go func() {
+		assert.NoError(t, role.raft.Close())
+	}()
+	select {
+	case response := <-ch:
+		assert.True(t, response.Succeeded())
+		assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+		assert.Equal(t, raft.ResponseError_SHUTTING_DOWN, response.Response.Error)
+		assert.Equal(t, raft.ErrShuttingDown, raft.NewCommandError(response.Response))
+	case <-time.After(5 * time.Second):
+		t.Fatal("command did not complete")
+	}
+}
+
+func TestLeaderCommandDrain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	var blocked int32
+	release := make(chan struct{})
+	blockAppends(client, &blocked, release).AnyTimes()
+
+	role := newClosableTestLeader(ctrl, client)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	openTestSession(t, role)
+
+	// Issue a command that cannot be committed until appends are released
+	atomic.StoreInt32(&blocked, 1)
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Begin shutting down the server and verify new commands are rejected while draining
+	stopped := make(chan struct{})
+	go func() {
+		assert.NoError(t, role.raft.Close())
+		close(stopped)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	rejectCh := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, rejectCh))
+	response := <-rejectCh
+	assert.Equal(t, raft.ResponseError_SHUTTING_DOWN, response.Response.Error)
+
+	// Verify the pending command completes if it commits while the leader is draining
+	atomic.StoreInt32(&blocked, 0)
+	close(release)
+	select {
+	case response = <-ch:
+		assert.True(t, response.Succeeded())
+		assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("command did not complete")
+	}
+	<-stopped
+}
+
+func TestLeaderCloseTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	role := newClosableTestLeader(ctrl, client)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	assert.NoError(t, role.raft.Close())
+
+	// Verify a role transition racing with the close neither stops the leader again nor starts a new role
+	role.raft.WriteLock()
+	role.raft.SetRole(raft.RoleFollower)
+	assert.Equal(t, raft.RoleType(""), role.raft.Role())
+	role.raft.WriteUnlock()
+	assert.NoError(t, role.Stop())
+
+	// Verify requests received once the state is closed are failed
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.Equal(t, raft.ErrShuttingDown, role.raft.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+}
+
 func TestLeaderQuery(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
@@ -396,7 +1030,8 @@ func TestLeaderQuery(t *testing.T) {
 	query = &raft.QueryRequest{
 		Value: newGetRequest("GetStream", sessionID, 0),
 	}
-	queryCh = make(chan *raft.QueryStreamResponse, 3)
+	// Stream operations are framed by the session's open and close responses.
+	queryCh = make(chan *raft.QueryStreamResponse, 5)
 	err = role.Query(query, queryCh)
 	assert.NoError(t, err)
 
@@ -412,6 +1047,14 @@ func TestLeaderQuery(t *testing.T) {
 	assert.True(t, queryResponse.Succeeded())
 	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
 
+	queryResponse = <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+
+	queryResponse = <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+
 	role.raft.ReadLock()
 	assert.Equal(t, raft.Index(2), role.raft.CommitIndex())
 	role.raft.ReadUnlock()
@@ -438,21 +1081,825 @@ func TestLeaderQuery(t *testing.T) {
 	assert.False(t, ok)
 }
 
-func newOpenSessionRequest() []byte {
-	timeout := 30 * time.Second
-	bytes, _ := proto.Marshal(&service.SessionRequest{
-		Request: &service.SessionRequest_OpenSession{
-			OpenSession: &service.OpenSessionRequest{
-				Timeout: &timeout,
-			},
-		},
-	})
-	return newCommandRequest(bytes)
-}
+func TestLeaderQueryLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
 
-func getSessionID(bytes []byte) uint64 {
-	return getOpenSessionResponse(bytes).SessionID
-}
+	// Block appends while blocked is set to prevent the lease from being renewed
+	var blocked int32
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			if atomic.LoadInt32(&blocked) == 1 {
+				<-release
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	sessionID := openTestSession(t, role)
+	assert.True(t, role.appender.leaseValid())
+
+	// Verify lease queries are served without a round trip to followers while the lease holds
+	atomic.StoreInt32(&blocked, 1)
+	query := &raft.QueryRequest{
+		Value:           newGetRequest("Get", sessionID, 0),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE_LEASE,
+	}
+	queryCh := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, role.Query(query, queryCh))
+	queryResponse := <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+
+	// Wait for the lease to lapse
+	deadline := time.Now().Add(5 * time.Second)
+	for role.appender.leaseValid() {
+		if time.Now().After(deadline) {
+			t.Fatal("lease was not expired")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Verify lease queries wait to verify leadership with a quorum once the lease has lapsed
+	queryCh = make(chan *raft.QueryStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Query(query, queryCh))
+	}()
+	select {
+	case <-queryCh:
+		t.Fatal("query completed without verifying leadership")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	atomic.StoreInt32(&blocked, 0)
+	close(release)
+	select {
+	case queryResponse = <-queryCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("query did not complete")
+	}
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+	assert.True(t, role.appender.leaseValid())
+}
+
+func TestLeaderTransferLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	var blocked int32
+	release := make(chan struct{})
+	defer close(release)
+	blockAppends(client, &blocked, release).AnyTimes()
+	client.EXPECT().
+		Transfer(gomock.Any(), gomock.Any(), gomock.Eq(raft.MemberID("bar"))).
+		DoAndReturn(func(ctx context.Context, request *raft.TransferRequest, member raft.MemberID) (*raft.TransferResponse, error) {
+			assert.Equal(t, raft.Term(1), request.Term)
+			assert.Equal(t, raft.MemberID("foo"), request.Leader)
+			return &raft.TransferResponse{
+				Status: raft.ResponseStatus_OK,
+			}, nil
+		})
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	sessionID := openTestSession(t, role)
+	setTestValue(t, role, sessionID, 1)
+	assert.True(t, role.appender.leaseValid())
+
+	// Verify the lease is held through the transfer
+	response, err := role.Transfer(context.TODO(), &raft.TransferRequest{Member: "bar"})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.True(t, role.appender.leaseValid())
+
+	// Verify lease queries are served locally without verifying leadership while the transfer is in progress
+	atomic.StoreInt32(&blocked, 1)
+	query := func(role raft.Role) *raft.QueryResponse {
+		ch := make(chan *raft.QueryStreamResponse, 1)
+		assert.NoError(t, role.Query(&raft.QueryRequest{
+			Value:           newGetRequest("Get", sessionID, 1),
+			ReadConsistency: raft.ReadConsistency_LINEARIZABLE_LEASE,
+		}, ch))
+		response := <-ch
+		assert.True(t, response.Succeeded())
+		return response.Response
+	}
+	queryResponse := query(role)
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(queryResponse.Output))
+
+	// Verify the handoff is recorded when the target requests votes for the next term
+	role.raft.ReadLock()
+	lastIndex := role.store.Writer().LastIndex()
+	role.raft.ReadUnlock()
+	_, err = role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         raft.Term(2),
+		Candidate:    "bar",
+		LastLogIndex: lastIndex,
+		LastLogTerm:  raft.Term(1),
+	})
+	assert.NoError(t, err)
+	role.raft.ReadLock()
+	assert.Equal(t, raft.RoleFollower, role.raft.Role())
+	handoff := role.raft.Handoff()
+	role.raft.ReadUnlock()
+	if !assert.NotNil(t, handoff) {
+		return
+	}
+	assert.Equal(t, raft.Term(1), handoff.Term)
+	assert.Equal(t, raft.MemberID("foo"), handoff.Leader)
+	assert.True(t, handoff.Until.After(time.Now()))
+
+	// Measure the availability of reads on the former leader until it learns of the new leader
+	follower := newFollowerRole(role.raft, role.state, role.store, NewRandom())
+	available := func() int {
+		succeeded := 0
+		for i := 0; i < 10; i++ {
+			if response := query(follower); response.Status == raft.ResponseStatus_OK {
+				assert.Equal(t, "Hello world!", getQueryValue(response.Output))
+				succeeded++
+			}
+		}
+		return succeeded
+	}
+	handedOff := available()
+
+	// Verify reads are unavailable after a hard step-down without a handoff
+	role.raft.WriteLock()
+	role.raft.SetHandoff(nil)
+	role.raft.WriteUnlock()
+	steppedDown := available()
+	assert.Equal(t, 10, handedOff)
+	assert.Equal(t, 0, steppedDown)
+
+	// Verify reads are forwarded once the new leader is learned
+	role.raft.WriteLock()
+	role.raft.SetHandoff(handoff)
+	leader := raft.MemberID("bar")
+	assert.NoError(t, role.raft.SetLeader(&leader))
+	role.raft.WriteUnlock()
+	expectQuery(client).Times(1)
+	assert.Equal(t, raft.ResponseStatus_OK, query(follower).Status)
+}
+
+func TestLeaderTransferAbandoned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+	client.EXPECT().
+		Transfer(gomock.Any(), gomock.Any(), gomock.Eq(raft.MemberID("bar"))).
+		Return(&raft.TransferResponse{
+			Status: raft.ResponseStatus_OK,
+		}, nil)
+
+	electionTimeout := 100 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	awaitIndex(role.raft, role.store.Log(), raft.Index(1))
+
+	// Transfer leadership to a member that accepts the transfer but never starts an election
+	response, err := role.Transfer(context.TODO(), &raft.TransferRequest{Member: "bar"})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	time.Sleep(electionTimeout * 2)
+
+	// Verify the abandoned transfer target no longer bypasses the lease
+	role.appender.lease.renew(time.Now())
+	voteResponse, err := role.Vote(context.TODO(), &raft.VoteRequest{
+		Term:         raft.Term(2),
+		Candidate:    "bar",
+		LastLogIndex: 1,
+		LastLogTerm:  1,
+	})
+	assert.NoError(t, err)
+	assert.False(t, voteResponse.Voted)
+	assert.Equal(t, raft.RejectionReason_LEADER_EXISTS, voteResponse.Rejection)
+	assert.Equal(t, raft.Term(1), voteResponse.Term)
+}
+
+func TestLeaderHandoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Block appends to the previous leader until released
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Eq(raft.MemberID("bar"))).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			<-release
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 10 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))
+	role := newLeaderRole(protocol, sm, stores).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	handoff := &raft.Handoff{
+		Term:   raft.Term(1),
+		Leader: "bar",
+	}
+	role.raft.SetHandoff(handoff)
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+	assert.Equal(t, raft.Index(1), awaitCommit(role.raft, raft.Index(1)))
+
+	// Verify commands are not accepted until the previous leader acknowledges the new leader
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		_ = role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch)
+	}()
+	select {
+	case <-ch:
+		t.Fatal("command was accepted before the handoff completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case response := <-ch:
+		assert.True(t, response.Succeeded())
+		assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("command was not accepted once the handoff completed")
+	}
+	<-role.handedOff
+	role.raft.ReadLock()
+	assert.Nil(t, role.raft.Handoff())
+	role.raft.ReadUnlock()
+}
+
+func TestLeaderQueryBeforeReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// Block appends until released to delay the commit of the leader's no-op entry
+	release := make(chan struct{})
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			<-release
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+
+	// Populate the log with a session and a write from the prior term
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newOpenSessionRequest(),
+			},
+		},
+	})
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newSetRequest("Set", 1, 1),
+			},
+		},
+	})
+
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	assert.NoError(t, role.Start())
+
+	query := &raft.QueryRequest{
+		Value:           newGetRequest("Get", 1, 1),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+	}
+	queryCh := make(chan *raft.QueryStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Query(query, queryCh))
+	}()
+
+	// Verify the query blocks until the leader's no-op entry is committed
+	select {
+	case <-queryCh:
+		t.Fatal("query completed before the leader was ready")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-role.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("leader was not ready")
+	}
+
+	queryResponse := <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(queryResponse.Response.Output))
+
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Index(3), role.raft.CommitIndex())
+	role.raft.ReadUnlock()
+}
+
+func TestLeaderIdleNoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	blocked := int32(1)
+	release := make(chan struct{})
+	blockAppends(client, &blocked, release).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	idleNoopInterval := 50 * time.Millisecond
+	config := &config.ProtocolConfig{
+		ElectionTimeout:  &electionTimeout,
+		IdleNoopInterval: &idleNoopInterval,
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	defer role.Stop()
+
+	// Populate the log with a session and a write from the prior term
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newOpenSessionRequest(),
+			},
+		},
+	})
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newSetRequest("Set", 1, 1),
+			},
+		},
+	})
+
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	assert.NoError(t, role.Start())
+
+	// Verify the idle leader appends a no-op entry from its term while its initialize entry is uncommitted
+	entry := awaitEntry(role.raft, role.store.Log(), raft.Index(4))
+	assert.Equal(t, raft.Index(4), entry.Index)
+	assert.Equal(t, raft.Term(2), entry.Entry.Term)
+	assert.NotNil(t, entry.Entry.GetInitialize())
+
+	// Once appends succeed, verify the entries are committed and a linearizable read is served without a client write
+	role.raft.ReadLock()
+	lastIndex := role.store.Writer().LastIndex()
+	role.raft.ReadUnlock()
+	atomic.StoreInt32(&blocked, 0)
+	close(release)
+	assert.Equal(t, lastIndex, awaitCommit(role.raft, lastIndex))
+
+	queryCh := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, role.Query(&raft.QueryRequest{
+		Value:           newGetRequest("Get", 1, 1),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+	}, queryCh))
+	queryResponse := <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(queryResponse.Response.Output))
+
+	// Verify no further no-op entries are appended once the log is committed
+	time.Sleep(idleNoopInterval * 5)
+	role.raft.ReadLock()
+	lastIndex = role.store.Writer().LastIndex()
+	role.raft.ReadUnlock()
+	time.Sleep(idleNoopInterval * 5)
+	role.raft.ReadLock()
+	assert.Equal(t, lastIndex, role.store.Writer().LastIndex())
+	role.raft.ReadUnlock()
+}
+
+func TestLeaderReadTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	sessionID := openTestSession(t, role)
+
+	transaction, err := role.BeginRead(10 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.Index(2), transaction.Index())
+	assert.Equal(t, "", queryTransaction(t, transaction, sessionID))
+
+	// Commit a write while the transaction is open
+	commandCh := make(chan *raft.CommandStreamResponse, 1)
+	go func() {
+		assert.NoError(t, role.Command(&raft.CommandRequest{Value: newSetRequest("Set", sessionID, 1)}, commandCh))
+	}()
+	assert.Equal(t, raft.Index(3), awaitCommit(role.raft, raft.Index(3)))
+
+	// Verify the transaction continues to observe the state at its read index
+	assert.Equal(t, "", queryTransaction(t, transaction, sessionID))
+
+	// Once the transaction is closed, verify the write is applied and visible to subsequent reads
+	transaction.Close()
+	commandResponse := <-commandCh
+	assert.True(t, commandResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, commandResponse.Response.Status)
+
+	queryCh := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, role.Query(&raft.QueryRequest{
+		Value:           newGetRequest("Get", sessionID, 1),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+	}, queryCh))
+	queryResponse := <-queryCh
+	assert.Equal(t, "Hello world!", getQueryValue(queryResponse.Response.Output))
+}
+
+func queryTransaction(t *testing.T, transaction raft.ReadTransaction, sessionID uint64) string {
+	ch := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, transaction.Query(&raft.QueryRequest{Value: newGetRequest("Get", sessionID, 0)}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	return getQueryValue(response.Response.Output)
+}
+
+func TestLeaderCatchUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	failAppend(client).AnyTimes()
+
+	role := newTestRole(client, newLeaderRole, mockFollower(ctrl), mockCandidate(ctrl)).(*LeaderRole)
+	statuses := make(chan raft.Status, 10)
+	role.raft.Watch(func(event raft.Event) {
+		if event.Type == raft.EventTypeStatus {
+			statuses <- event.Status
+		}
+	})
+
+	// Append a session and a write committed by a prior leader that have not been applied
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newOpenSessionRequest(),
+			},
+		},
+	})
+	role.store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: newSetRequest("Set", 1, 1),
+			},
+		},
+	})
+
+	// Promote the member to leader. Appends fail, so the leader cannot commit entries from its own term.
+	role.raft.WriteLock()
+	role.raft.Init()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	role.raft.Commit(raft.Index(2))
+	role.raft.SetRole(raft.RoleLeader)
+	assert.Equal(t, raft.StatusCatchingUp, role.raft.Status())
+	role.raft.WriteUnlock()
+
+	// Verify a sequential read immediately after promotion observes the write committed by the prior leader
+	ch := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, role.Query(&raft.QueryRequest{
+		Value:           newGetRequest("Get", 1, 1),
+		ReadConsistency: raft.ReadConsistency_SEQUENTIAL,
+	}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	assert.Equal(t, "Hello world!", getQueryValue(response.Response.Output))
+
+	// Verify the status reflects the gate
+	<-role.CaughtUp()
+	assert.Equal(t, raft.StatusRunning, <-statuses)
+	assert.Equal(t, raft.StatusCatchingUp, <-statuses)
+	assert.Equal(t, raft.StatusRunning, <-statuses)
+	role.raft.ReadLock()
+	assert.Equal(t, raft.StatusRunning, role.raft.Status())
+	role.raft.ReadUnlock()
+}
+
+// errorLogger records the errors logged by a role
+type errorLogger struct {
+	util.Logger
+	errors chan string
+}
+
+func (l *errorLogger) Error(message string, args ...interface{}) {
+	l.Logger.Error(message, args...)
+	select {
+	case l.errors <- fmt.Sprintf(message, args...):
+	default:
+	}
+}
+
+func TestLeaderConflictingLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	failAppend(client).AnyTimes()
+
+	role := newTestRole(client, newLeaderRole, mockFollower(ctrl), mockCandidate(ctrl)).(*LeaderRole)
+	logger := &errorLogger{
+		Logger: role.log,
+		errors: make(chan string, 10),
+	}
+	role.log = logger
+	conflicts := role.conflicts.Value()
+
+	role.raft.WriteLock()
+	role.raft.Init()
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	role.raft.SetRole(raft.RoleLeader)
+	role.raft.WriteUnlock()
+
+	// Simulate an append from another leader claiming the same term
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:   raft.Term(1),
+		Leader: raft.MemberID("bar"),
+		Entries: []*raft.LogEntry{
+			{
+				Term:      raft.Term(1),
+				Timestamp: time.Now(),
+				Entry: &raft.LogEntry_Command{
+					Command: &raft.CommandEntry{
+						Value: []byte("conflict"),
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Succeeded)
+	assert.Equal(t, raft.Term(1), response.Term)
+
+	// Verify the anomaly was logged and counted and the leader stepped down without appending the entries
+	assert.Contains(t, <-logger.errors, "conflicting leader bar in term 1")
+	assert.Equal(t, conflicts+1, role.conflicts.Value())
+	assert.Equal(t, raft.RoleFollower, awaitRole(role.raft, raft.RoleFollower))
+	role.raft.ReadLock()
+	assert.Nil(t, role.raft.Leader())
+	assert.Equal(t, raft.Term(1), role.raft.Term())
+	role.raft.ReadUnlock()
+	reader := role.store.Log().OpenReader(0)
+	for entry := reader.NextEntry(); entry != nil; entry = reader.NextEntry() {
+		assert.NotEqual(t, "conflict", string(entry.Entry.GetCommand().GetValue()))
+	}
+}
+
+func TestLeaderTimestamp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	store := store.NewMemoryStore()
+	role := newLeaderRole(newTestStateWithStore(client, store, config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+
+	var timestamps []time.Time
+	for i := 0; i < 3; i++ {
+		timestamp, err := role.Timestamp()
+		assert.NoError(t, err)
+		timestamps = append(timestamps, timestamp)
+	}
+
+	// Simulate an entry written by a leader whose clock is ahead of the next leader's clock
+	future := time.Now().Add(time.Hour)
+	role.raft.WriteLock()
+	store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: future,
+		Entry: &raft.LogEntry_Barrier{
+			Barrier: &raft.BarrierEntry{},
+		},
+	})
+	role.raft.WriteUnlock()
+	timestamp, err := role.Timestamp()
+	assert.NoError(t, err)
+	timestamps = append(timestamps, timestamp)
+	assert.NoError(t, role.Stop())
+
+	// Elect a new leader with the same log and verify its timestamps do not go backward
+	role = newLeaderRole(newTestStateWithStore(client, store, config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(2)))
+	assert.NoError(t, role.Start())
+	for i := 0; i < 3; i++ {
+		timestamp, err := role.Timestamp()
+		assert.NoError(t, err)
+		timestamps = append(timestamps, timestamp)
+	}
+
+	for i := 1; i < len(timestamps); i++ {
+		assert.False(t, timestamps[i].Before(timestamps[i-1]), "timestamp %d went backward", i)
+	}
+	assert.False(t, timestamps[len(timestamps)-1].Before(future))
+}
+
+func TestLeaderSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+	succeedInstallTo(client, raft.MemberID("bar")).AnyTimes()
+	succeedInstallTo(client, raft.MemberID("baz")).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+		},
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+
+	sessionID := openTestSession(t, role)
+	for i := 1; i <= 3; i++ {
+		setTestValue(t, role, sessionID, uint64(i))
+	}
+
+	snapshot := awaitSnapshot(role.store, raft.Index(4))
+	assert.NotNil(t, snapshot)
+	assert.True(t, snapshot.Index() >= raft.Index(4))
+	assert.True(t, role.store.Log().OpenReader(0).FirstIndex() > snapshot.Index())
+}
+
+func TestLeaderSnapshotFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 1,
+		},
+	}
+	failures := metrics.NewCounter("raft_snapshot_failures_total", "foo")
+	initialFailures := failures.Value()
+
+	store := &failingSnapshotStore{Store: store.NewMemoryStore()}
+	role := newLeaderRole(newTestStateWithStore(client, store, config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+
+	// Verify that commands continue to be committed even though snapshots are failing
+	sessionID := openTestSession(t, role)
+	for i := 1; i <= 5; i++ {
+		setTestValue(t, role, sessionID, uint64(i))
+	}
+
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Index(7), role.raft.CommitIndex())
+	role.raft.ReadUnlock()
+
+	// Verify that queries can still be read from the state machine
+	query := &raft.QueryRequest{
+		Value:           newGetRequest("Get", sessionID, 5),
+		ReadConsistency: raft.ReadConsistency_LINEARIZABLE,
+	}
+	queryCh := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, role.Query(query, queryCh))
+	queryResponse := <-queryCh
+	assert.True(t, queryResponse.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, queryResponse.Response.Status)
+
+	// Verify the failures were reported and the log was not compacted
+	assert.True(t, failures.Value() > initialFailures)
+	assert.Nil(t, store.Snapshot().CurrentSnapshot())
+	assert.Equal(t, raft.Index(1), role.store.Log().OpenReader(0).FirstIndex())
+}
+
+func openTestSession(t *testing.T, role *LeaderRole) uint64 {
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+	return getSessionID(response.Response.Output)
+}
+
+func setTestValue(t *testing.T, role *LeaderRole, sessionID uint64, commandID uint64) {
+	ch := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newSetRequest("Set", sessionID, commandID)}, ch))
+	response := <-ch
+	assert.True(t, response.Succeeded())
+	assert.Equal(t, raft.ResponseStatus_OK, response.Response.Status)
+}
+
+func awaitSnapshot(store store.Store, index raft.Index) snapshot.Snapshot {
+	for i := 0; i < 100; i++ {
+		if snapshot := store.Snapshot().CurrentSnapshot(); snapshot != nil && snapshot.Index() >= index {
+			return snapshot
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// lowSpaceFileSystem is a file system reporting a configurable amount of free space
+type lowSpaceFileSystem struct {
+	free uint64
+}
+
+func (f *lowSpaceFileSystem) FreeSpace(path string) (uint64, error) {
+	return f.free, nil
+}
+
+// failingSnapshotStore is a store whose snapshot store always fails to persist snapshots
+type failingSnapshotStore struct {
+	store.Store
+}
+
+func (s *failingSnapshotStore) Snapshot() snapshot.Store {
+	return &failingSnapshots{Store: s.Store.Snapshot()}
+}
+
+type failingSnapshots struct {
+	snapshot.Store
+}
+
+func (s *failingSnapshots) NewSnapshot(index raft.Index, term raft.Term, timestamp time.Time, metadata []byte) snapshot.Snapshot {
+	return &failingSnapshot{Snapshot: s.Store.NewSnapshot(index, term, timestamp, metadata)}
+}
+
+type failingSnapshot struct {
+	snapshot.Snapshot
+}
+
+func (s *failingSnapshot) Writer() snapshot.Writer {
+	return &failingSnapshotWriter{}
+}
+
+type failingSnapshotWriter struct{}
+
+func (w *failingSnapshotWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func (w *failingSnapshotWriter) Close() error {
+	return errors.New("no space left on device")
+}
+
+func (w *failingSnapshotWriter) Abort() error {
+	return nil
+}
+
+func newOpenSessionRequest() []byte {
+	timeout := 30 * time.Second
+	bytes, _ := proto.Marshal(&service.SessionRequest{
+		Request: &service.SessionRequest_OpenSession{
+			OpenSession: &service.OpenSessionRequest{
+				Timeout: &timeout,
+			},
+		},
+	})
+	return newCommandRequest(bytes)
+}
+
+func getSessionID(bytes []byte) uint64 {
+	return getOpenSessionResponse(bytes).SessionID
+}
 
 func getOpenSessionResponse(bytes []byte) *service.OpenSessionResponse {
 	serviceResponse := &service.ServiceResponse{}
@@ -512,6 +1959,19 @@ func newGetRequest(getType string, sessionID uint64, commandID uint64) []byte {
 	return newQueryRequest(bytes)
 }
 
+func getQueryValue(bytes []byte) string {
+	serviceResponse := &service.ServiceResponse{}
+	_ = proto.Unmarshal(bytes, serviceResponse)
+	sessionResponse := &service.SessionResponse{}
+	_ = proto.Unmarshal(serviceResponse.GetQuery(), sessionResponse)
+	if sessionResponse.GetQuery() == nil {
+		return ""
+	}
+	getResponse := &GetResponse{}
+	_ = proto.Unmarshal(sessionResponse.GetQuery().Output, getResponse)
+	return getResponse.Value
+}
+
 func newQueryRequest(bytes []byte) []byte {
 	bytes, _ = proto.Marshal(&service.ServiceRequest{
 		Id: &service.ServiceId{
@@ -525,3 +1985,99 @@ func newQueryRequest(bytes []byte) []byte {
 	})
 	return bytes
 }
+
+func TestLeaderQueryFaulted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	succeedAppend(client).AnyTimes()
+
+	role := newLeaderRole(newTestState(client, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	sessionID := openTestSession(t, role)
+
+	role.raft.WriteLock()
+	role.raft.SetFaulted()
+	role.raft.WriteUnlock()
+	assert.Equal(t, raft.StatusFaulted, role.raft.Status())
+
+	// Verify reads of every consistency level are rejected once the state machine has faulted
+	for _, consistency := range []raft.ReadConsistency{raft.ReadConsistency_LINEARIZABLE, raft.ReadConsistency_SEQUENTIAL, raft.ReadConsistency_STALE} {
+		queryCh := make(chan *raft.QueryStreamResponse, 1)
+		assert.NoError(t, role.Query(&raft.QueryRequest{
+			Value:           newGetRequest("Get", sessionID, 0),
+			ReadConsistency: consistency,
+		}, queryCh))
+		queryResponse := <-queryCh
+		assert.Equal(t, raft.ResponseStatus_ERROR, queryResponse.Response.Status)
+		assert.Equal(t, raft.ResponseError_FAULTED, queryResponse.Response.Error)
+	}
+
+	// Verify commands are rejected once the state machine has faulted
+	commandCh := make(chan *raft.CommandStreamResponse, 1)
+	assert.NoError(t, role.Command(&raft.CommandRequest{Value: newOpenSessionRequest()}, commandCh))
+	commandResponse := <-commandCh
+	assert.Equal(t, raft.ResponseStatus_ERROR, commandResponse.Response.Status)
+	assert.Equal(t, raft.ResponseError_FAULTED, commandResponse.Response.Error)
+
+	// Verify the faulted status is not cleared by later commits
+	appendTestEntry(role.raft, role.store, raft.Term(1))
+	role.raft.WriteLock()
+	role.raft.Commit(role.store.Writer().LastIndex())
+	role.raft.WriteUnlock()
+	assert.Equal(t, raft.StatusFaulted, role.raft.Status())
+}
+
+func TestLeaderRelayFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	// The relay reports it can't catch up the downstream member in its append responses
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("bar")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+				Unrelayed:    []raft.MemberID{"baz"},
+			}, nil
+		}).AnyTimes()
+	fallbackCh := make(chan struct{}, 1)
+	client.EXPECT().
+		Append(gomock.Any(), gomock.Any(), raft.MemberID("baz")).
+		DoAndReturn(func(ctx context.Context, request *raft.AppendRequest, member raft.MemberID) (*raft.AppendResponse, error) {
+			select {
+			case fallbackCh <- struct{}{}:
+			default:
+			}
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_OK,
+				Term:         request.Term,
+				Succeeded:    true,
+				LastLogIndex: request.PrevLogIndex + raft.Index(len(request.Entries)),
+			}, nil
+		}).AnyTimes()
+
+	electionTimeout := time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		RelayFanout:     1,
+		Observers:       []string{"baz"},
+	}
+	role := newLeaderRole(newTestStateWithStore(client, store.NewMemoryStore(), config, mockFollower(ctrl), mockCandidate(ctrl), mockLeader(ctrl))).(*LeaderRole)
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	assert.NoError(t, role.Start())
+	defer role.Stop()
+
+	// Verify the leader replicates to the member in place of its relay
+	select {
+	case <-fallbackCh:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "leader did not replicate to the unrelayed member")
+	}
+	progress := role.appender.progress()
+	assert.Len(t, progress, 2)
+	assert.Equal(t, raft.MemberID("baz"), progress[1].MemberID)
+}
@@ -0,0 +1,79 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// leaseSafetyFactor is the fraction of the election timeout for which a leadership lease is held
+// The lease is shorter than the election timeout to tolerate bounded clock drift between members.
+const leaseSafetyFactor = 0.9
+
+// leaseDuration returns the duration for which a leadership lease is held for the given election timeout
+func leaseDuration(electionTimeout time.Duration) time.Duration {
+	return time.Duration(float64(electionTimeout) * leaseSafetyFactor)
+}
+
+// newLeaderLease returns a new expired lease that is held for the given duration each time it's renewed
+func newLeaderLease(duration time.Duration) *leaderLease {
+	return &leaderLease{
+		duration: int64(duration),
+	}
+}
+
+// leaderLease is a leadership lease renewed each time a quorum of the cluster acknowledges the leader
+// Followers reject polls and votes while they know of a leader and only forget the leader once the election timeout
+// has elapsed since they last heard from it, and the leader rejects votes while its lease is valid, so the lease
+// expires before another leader can be elected. Members the leader transferred leadership to are voted for, but they
+// don't serve requests until the leader has stepped down or its lease has expired.
+type leaderLease struct {
+	duration   int64
+	expiration int64
+}
+
+// renew extends the lease from the given time at which requests acknowledged by a quorum were sent
+func (l *leaderLease) renew(start time.Time) {
+	expiration := start.Add(time.Duration(atomic.LoadInt64(&l.duration))).UnixNano()
+	for {
+		prev := atomic.LoadInt64(&l.expiration)
+		if expiration <= prev || atomic.CompareAndSwapInt64(&l.expiration, prev, expiration) {
+			return
+		}
+	}
+}
+
+// reset revokes the lease and sets the duration for which the lease is held when it's renewed
+// The lease is revoked because it may have been renewed for longer than the new duration allows.
+func (l *leaderLease) reset(duration time.Duration) {
+	atomic.StoreInt64(&l.duration, int64(duration))
+	l.expire()
+}
+
+// expire revokes the lease
+func (l *leaderLease) expire() {
+	atomic.StoreInt64(&l.expiration, 0)
+}
+
+// until returns the time at which the lease expires
+func (l *leaderLease) until() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&l.expiration))
+}
+
+// valid returns a bool indicating whether the lease is held at the given time
+func (l *leaderLease) valid(now time.Time) bool {
+	return now.UnixNano() < atomic.LoadInt64(&l.expiration)
+}
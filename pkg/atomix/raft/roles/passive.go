@@ -23,12 +23,16 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
-	"io"
+	"hash/crc32"
 	"math"
 	"time"
 )
 
+// maxQueryForwardDepth is the maximum number of times a query can be forwarded between members
+const maxQueryForwardDepth = 2
+
 func newPassiveRole(raft raft.Raft, state state.Manager, store store.Store, log util.Logger) *PassiveRole {
 	return &PassiveRole{
 		raftRole: newRaftRole(raft, state, store, log),
@@ -73,12 +77,61 @@ func (r *PassiveRole) handleAppend(ctx context.Context, request *raft.AppendRequ
 		return response, nil
 	}
 
+	// Witnesses store no entries. Instead, they record the last entry in the leader's log.
+	if r.isWitness() {
+		return r.recordAppend(request), nil
+	}
+
 	if response := r.checkPreviousEntry(request); response != nil {
 		return response, nil
 	}
+
+	// Refuse to append entries if the store is too low on space to safely write them.
+	if len(request.Entries) > 0 {
+		if err := r.store.CheckDiskSpace(); err != nil {
+			r.log.Debug("Rejected %v: %v", request, err)
+			return &raft.AppendResponse{
+				Status:       raft.ResponseStatus_ERROR,
+				Error:        raft.GetResponseError(err),
+				Term:         r.raft.Term(),
+				LastLogIndex: r.store.Writer().LastIndex(),
+			}, nil
+		}
+	}
 	return r.appendEntries(request)
 }
 
+// recordAppend records the last entry in the leader's log on a witness
+// The leader sends witnesses the last entry in its log as the request's previous entry. The witness durably records
+// the entry in place of the log, acknowledging the leader's log up to the entry, and refuses to vote for candidates
+// whose logs are behind the record. The commit index is advanced but there are no entries to apply.
+func (r *PassiveRole) recordAppend(request *raft.AppendRequest) *raft.AppendResponse {
+	record := r.raft.WitnessRecord()
+	if request.PrevLogTerm > record.Term || (request.PrevLogTerm == record.Term && request.PrevLogIndex > record.Index) {
+		r.raft.SetWitnessRecord(raft.WitnessRecord{
+			Index: request.PrevLogIndex,
+			Term:  request.PrevLogTerm,
+		})
+
+		// The record stands in for the log, so it must be durable before the entry is acknowledged. If the sync
+		// fails, the previous record is restored so the entry is recorded again when the leader retries.
+		if err := r.raft.SyncMetadata(); err != nil {
+			r.log.Error("Failed to sync metadata", err)
+			r.raft.SetWitnessRecord(record)
+			return r.failAppend(record.Index)
+		}
+		r.log.Trace("Recorded entry %d at term %d", request.PrevLogIndex, request.PrevLogTerm)
+	}
+
+	commitIndex := request.CommitIndex
+	if commitIndex > request.PrevLogIndex {
+		commitIndex = request.PrevLogIndex
+	}
+	r.raft.SetCommitIndex(request.CommitIndex)
+	r.raft.Commit(commitIndex)
+	return r.succeedAppend(request.PrevLogIndex)
+}
+
 // checkTerm compares the given request to the current term
 func (r *PassiveRole) checkTerm(request *raft.AppendRequest) *raft.AppendResponse {
 	if request.Term < r.raft.Term() {
@@ -118,7 +171,11 @@ func (r *PassiveRole) checkPreviousEntry(request *raft.AppendRequest) *raft.Appe
 
 				// The previous entry should exist in the log if we've gotten this far.
 				previousEntry := reader.NextEntry()
-				if previousEntry == nil {
+				if err := reader.Err(); err != nil {
+					r.log.Debug("Rejected %v: Previous entry is corrupt", request)
+					r.recoverCorruptEntry(request.PrevLogIndex, err)
+					return r.failAppend(request.PrevLogIndex - 1)
+				} else if previousEntry == nil {
 					r.log.Debug("Rejected %v: Previous entry does not exist in the local log", request)
 					return r.failAppend(lastEntry.Index)
 				}
@@ -184,7 +241,14 @@ func (r *PassiveRole) appendEntries(request *raft.AppendRequest) (*raft.AppendRe
 
 					// If the reader does not have any next entry, that indicates an inconsistency between the reader and writer.
 					existingEntry := reader.NextEntry()
-					if existingEntry == nil {
+					if err := reader.Err(); err != nil {
+						if !r.recoverCorruptEntry(index, err) {
+							return r.failAppend(index - 1), nil
+						}
+						indexed := writer.Append(entry)
+						r.log.Trace("Appended %v", indexed)
+						continue
+					} else if existingEntry == nil {
 						return nil, errors.New("log reader inconsistent with writer")
 					}
 
@@ -223,6 +287,11 @@ func (r *PassiveRole) appendEntries(request *raft.AppendRequest) (*raft.AppendRe
 		}
 	}
 
+	// If the request reaches the end of the leader's log, remove any entries that follow it from prior terms.
+	if request.LastLogIndex > 0 && index == request.LastLogIndex {
+		r.truncateTail(request, index)
+	}
+
 	// Update the context commit and global indices.
 	r.raft.SetCommitIndex(request.CommitIndex)
 	prevCommitIndex := r.raft.Commit(commitIndex)
@@ -230,9 +299,10 @@ func (r *PassiveRole) appendEntries(request *raft.AppendRequest) (*raft.AppendRe
 		r.log.Trace("Committed entries up to index %d", commitIndex)
 
 		// If the commitIndex was for entries that were replicated in a prior request, ensure they're applied
-		// to the state machine.
+		// to the state machine. Entries beyond the commit index, e.g. an uncommitted tail recovered when the
+		// member restarted, are not applied until the leader commits them.
 		if commitIndex <= request.PrevLogIndex {
-			r.state.ApplyIndex(request.PrevLogIndex)
+			r.state.ApplyIndex(commitIndex)
 		}
 
 		// Iterate through entries in the request and apply committed entries to the state machine.
@@ -248,6 +318,39 @@ func (r *PassiveRole) appendEntries(request *raft.AppendRequest) (*raft.AppendRe
 	return r.succeedAppend(index), nil
 }
 
+// recoverCorruptEntry recovers from a corrupt entry at the given index in the log
+// Uncommitted entries are truncated from the log to be replicated again by the leader. Committed entries can't be
+// truncated without risking the loss of committed state, so the member is faulted instead. It returns a bool
+// indicating whether the log was truncated.
+func (r *PassiveRole) recoverCorruptEntry(index raft.Index, err error) bool {
+	if index > r.raft.CommitIndex() {
+		r.log.Warn("Truncating the log prior to corrupt entry %d: %v", index, err)
+		r.store.Writer().Truncate(index - 1)
+		return true
+	}
+	r.log.Error("Committed entry %d is corrupt: %v", index, err)
+	r.raft.SetFaulted()
+	return false
+}
+
+// truncateTail truncates uncommitted entries beyond the end of the leader's log
+// Entries from prior terms that follow the end of the leader's log are not in the leader's log and so cannot have
+// been committed. Entries from the leader's term were written by the leader and are never truncated, which protects
+// against delayed requests from the leader.
+func (r *PassiveRole) truncateTail(request *raft.AppendRequest, index raft.Index) {
+	writer := r.store.Writer()
+	lastEntry := writer.LastEntry()
+	if lastEntry == nil || lastEntry.Index <= index || lastEntry.Entry.Term >= request.Term {
+		return
+	}
+	if index < r.raft.CommitIndex() {
+		r.log.Warn("Refusing to truncate entries %d-%d beyond the leader's log: the commit index is %d", index+1, lastEntry.Index, r.raft.CommitIndex())
+		return
+	}
+	r.log.Debug("Truncating uncommitted entries %d-%d beyond the leader's last index", index+1, lastEntry.Index)
+	writer.Truncate(index)
+}
+
 // failAppend returns a failed AppendResponse
 func (r *PassiveRole) failAppend(lastIndex raft.Index) *raft.AppendResponse {
 	return r.completeAppend(false, lastIndex)
@@ -269,11 +372,17 @@ func (r *PassiveRole) completeAppend(succeeded bool, lastIndex raft.Index) *raft
 }
 
 // Install handles an install request
+// The snapshot is written to the snapshot store as it's received and is only committed once the stream completes
+// and the data matches the checksum sent by the leader. If the stream fails or the data does not match, the partial
+// snapshot is discarded. Leaders that predate checksums send a checksum of 0, so their data is not verified.
 func (r *PassiveRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.InstallResponse, error) {
-	var writer io.WriteCloser
+	var writer snapshot.Writer
+	var checksum uint32
 	for message := range ch {
 		if message.Failed() {
-			writer.Close()
+			if writer != nil {
+				_ = writer.Abort()
+			}
 			_ = r.log.Response("InstallResponse", nil, message.Error)
 			return nil, message.Error
 		}
@@ -289,6 +398,10 @@ func (r *PassiveRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.Insta
 
 		// If the request is for a lesser term, reject the request.
 		if request.Term < r.raft.Term() {
+			r.raft.WriteUnlock()
+			if writer != nil {
+				_ = writer.Abort()
+			}
 			response := &raft.InstallResponse{
 				Status: raft.ResponseStatus_ERROR,
 				Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
@@ -298,13 +411,22 @@ func (r *PassiveRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.Insta
 		}
 
 		if writer == nil {
-			snapshot := r.store.Snapshot().NewSnapshot(request.Index, request.Timestamp)
+			snapshot := r.store.Snapshot().NewSnapshot(request.Index, request.SnapshotTerm, request.Timestamp, request.Metadata)
 			writer = snapshot.Writer()
 		}
 
-		_, err := writer.Write(request.Data)
+		// Verify the data received so far matches the leader's checksum before writing the request's data.
+		checksum = crc32.Update(checksum, crc32.IEEETable, request.Data)
+		var err error
+		if request.Checksum != 0 && checksum != request.Checksum {
+			err = fmt.Errorf("checksum mismatch for snapshot %d", request.Index)
+		} else {
+			_, err = writer.Write(request.Data)
+		}
 		r.raft.WriteUnlock()
 		if err != nil {
+			r.log.Warn("Failed to install snapshot %d: %v", request.Index, err)
+			_ = writer.Abort()
 			response := &raft.InstallResponse{
 				Status: raft.ResponseStatus_ERROR,
 				Error:  raft.ResponseError_PROTOCOL_ERROR,
@@ -314,7 +436,24 @@ func (r *PassiveRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.Insta
 		}
 	}
 
-	writer.Close()
+	if writer == nil {
+		r.log.Warn("Install stream closed before any snapshot data was received")
+		response := &raft.InstallResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_PROTOCOL_ERROR,
+		}
+		_ = r.log.Response("InstallResponse", response, nil)
+		return response, nil
+	}
+	if err := writer.Close(); err != nil {
+		r.log.Warn("Failed to commit installed snapshot: %v", err)
+		response := &raft.InstallResponse{
+			Status: raft.ResponseStatus_ERROR,
+			Error:  raft.ResponseError_PROTOCOL_ERROR,
+		}
+		_ = r.log.Response("InstallResponse", response, nil)
+		return response, nil
+	}
 	response := &raft.InstallResponse{
 		Status: raft.ResponseStatus_OK,
 	}
@@ -322,6 +461,32 @@ func (r *PassiveRole) Install(ch <-chan *raft.InstallStreamRequest) (*raft.Insta
 	return response, nil
 }
 
+// Digest handles a log digest request
+// Only committed entries are included in digests, since uncommitted entries may legitimately differ between members.
+func (r *PassiveRole) Digest(ctx context.Context, request *raft.DigestRequest) (*raft.DigestResponse, error) {
+	r.log.Request("DigestRequest", request)
+	r.raft.ReadLock()
+	commitIndex := r.raft.CommitIndex()
+	r.raft.ReadUnlock()
+
+	response := &raft.DigestResponse{
+		Status:      raft.ResponseStatus_OK,
+		FirstIndex:  r.store.Reader().FirstIndex(),
+		CommitIndex: commitIndex,
+	}
+	if request.Index > commitIndex {
+		response.Status = raft.ResponseStatus_ERROR
+		response.Error = raft.ResponseError_ENTRIES_UNAVAILABLE
+	} else if digest, err := log.Digest(r.store.Log(), request.FirstIndex, request.Index); err != nil {
+		response.Status = raft.ResponseStatus_ERROR
+		response.Error = raft.ResponseError_ENTRIES_UNAVAILABLE
+	} else {
+		response.Digest = digest
+	}
+	_ = r.log.Response("DigestResponse", response, nil)
+	return response, nil
+}
+
 // Command handles a command request
 func (r *PassiveRole) Command(request *raft.CommandRequest, ch chan<- *raft.CommandStreamResponse) error {
 	defer close(ch)
@@ -350,9 +515,54 @@ func (r *PassiveRole) Query(request *raft.QueryRequest, ch chan<- *raft.QueryStr
 	defer close(ch)
 
 	r.log.Request("QueryRequest", request)
+
+	// If the member has not reached the query's minimum index, reject the query so the client can retry it on
+	// another member.
+	if !r.awaitMinIndex(request) {
+		r.rejectMinIndex(request, ch)
+		return nil
+	}
+
 	r.raft.ReadLock()
 	leader := r.raft.Leader()
 
+	// A faulted state machine can't serve reads.
+	if r.raft.Status() == raft.StatusFaulted {
+		r.raft.ReadUnlock()
+		response := &raft.QueryResponse{
+			Status:  raft.ResponseStatus_ERROR,
+			Error:   raft.ResponseError_FAULTED,
+			Message: raft.ErrFaulted.Error(),
+		}
+		_ = r.log.Response("QueryResponse", response, nil)
+		ch <- raft.NewQueryStreamResponse(response, nil)
+		return nil
+	}
+
+	// Witnesses have no state to serve the query from, so the query is forwarded to the leader.
+	if r.isWitness() {
+		r.raft.ReadUnlock()
+		return r.forwardQuery(request, leader, ch)
+	}
+
+	// If the query's consistency level is STALE, serve the query from the local state without any checks.
+	if request.ReadConsistency == raft.ReadConsistency_STALE {
+		entry := &log.Entry{
+			Index: r.store.Writer().LastIndex(),
+			Entry: &raft.LogEntry{
+				Term:      r.raft.Term(),
+				Timestamp: time.Now(),
+				Entry: &raft.LogEntry_Query{
+					Query: &raft.QueryEntry{
+						Value: request.Value,
+					},
+				},
+			},
+		}
+		r.raft.ReadUnlock()
+		return r.applyQuery(entry, ch)
+	}
+
 	// If this server has not yet applied entries up to the client's session ID, forward the
 	// query to the leader. This ensures that a follower does not tell the client its session
 	// doesn't exist if the follower hasn't had a chance to see the session's registration entry.
@@ -390,10 +600,68 @@ func (r *PassiveRole) Query(request *raft.QueryRequest, ch chan<- *raft.QueryStr
 
 		return r.applyQuery(entry, ch)
 	}
+
+	// If this member transferred leadership and hasn't yet learned of its successor, serve linearizable queries
+	// from the local state until its lease expires. The successor doesn't serve reads or accept commands until
+	// this member has acknowledged it or the lease has expired.
+	if leader == nil && r.holdsHandoffLease() {
+		entry := &log.Entry{
+			Index: r.raft.CommitIndex(),
+			Entry: &raft.LogEntry{
+				Term:      r.raft.Term(),
+				Timestamp: time.Now(),
+				Entry: &raft.LogEntry_Query{
+					Query: &raft.QueryEntry{
+						Value: request.Value,
+					},
+				},
+			},
+		}
+		r.raft.ReadUnlock()
+		return r.applyQuery(entry, ch)
+	}
 	r.raft.ReadUnlock()
 	return r.forwardQuery(request, leader, ch)
 }
 
+// holdsHandoffLease returns a bool indicating whether the member transferred leadership and its lease has not expired
+func (r *PassiveRole) holdsHandoffLease() bool {
+	handoff := r.raft.Handoff()
+	return handoff != nil && handoff.Leader == r.raft.Member() && r.raft.Term() > handoff.Term && time.Now().Before(handoff.Until)
+}
+
+// awaitMinIndex waits up to the query's minimum index timeout for the state machine to apply its minimum index
+// Committed entries are enqueued to the state machine while the write lock is held, so once the commit index has
+// reached the minimum index, a query applied to the state machine observes the minimum index without waiting.
+func (r *PassiveRole) awaitMinIndex(request *raft.QueryRequest) bool {
+	if request.MinIndex == 0 {
+		return true
+	}
+	r.raft.ReadLock()
+	commitIndex := r.raft.CommitIndex()
+	r.raft.ReadUnlock()
+	if commitIndex >= request.MinIndex {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), request.MinIndexTimeout)
+	defer cancel()
+	return r.state.AwaitIndex(ctx, request.MinIndex) == nil
+}
+
+// rejectMinIndex responds to a query whose minimum index has not been reached with a retryable error
+func (r *PassiveRole) rejectMinIndex(request *raft.QueryRequest, ch chan<- *raft.QueryStreamResponse) {
+	r.raft.ReadLock()
+	commitIndex := r.raft.CommitIndex()
+	r.raft.ReadUnlock()
+	response := &raft.QueryResponse{
+		Status:  raft.ResponseStatus_ERROR,
+		Error:   raft.ResponseError_UNAVAILABLE,
+		Message: fmt.Sprintf("member has committed up to index %d, but the query requires index %d", commitIndex, request.MinIndex),
+	}
+	_ = r.log.Response("QueryResponse", response, nil)
+	ch <- raft.NewQueryStreamResponse(response, nil)
+}
+
 // applyQuery applies a query to the state machine
 func (r *PassiveRole) applyQuery(entry *log.Entry, responseCh chan<- *raft.QueryStreamResponse) error {
 	// Create a result channel
@@ -401,8 +669,11 @@ func (r *PassiveRole) applyQuery(entry *log.Entry, responseCh chan<- *raft.Query
 
 	// Apply the entry to the state machine
 	r.state.ApplyEntry(entry, stream.NewChannelStream(outputCh))
+	return r.sendQueryResults(outputCh, responseCh)
+}
 
-	// Iterate through results and translate them into QueryResponses.
+// sendQueryResults translates query results into QueryResponses
+func (r *PassiveRole) sendQueryResults(outputCh <-chan stream.Result, responseCh chan<- *raft.QueryStreamResponse) error {
 	for result := range outputCh {
 		if result.Succeeded() {
 			response := &raft.QueryResponse{
@@ -435,8 +706,28 @@ func (r *PassiveRole) forwardQuery(request *raft.QueryRequest, leader *raft.Memb
 		return nil
 	}
 
-	r.log.Trace("Forwarding %v", request)
-	stream, err := r.raft.Protocol().Query(context.Background(), request, *leader)
+	// Limit the number of times a query can be forwarded to prevent forwarding loops while members disagree
+	// about the leader
+	if request.ForwardDepth >= maxQueryForwardDepth {
+		response := &raft.QueryResponse{
+			Status:  raft.ResponseStatus_ERROR,
+			Error:   raft.ResponseError_NO_LEADER,
+			Message: fmt.Sprintf("query was forwarded %d times without reaching the leader", request.ForwardDepth),
+		}
+		_ = r.log.Response("QueryResponse", response, nil)
+		ch <- raft.NewQueryStreamResponse(response, nil)
+		return nil
+	}
+
+	forward := &raft.QueryRequest{
+		Value:           request.Value,
+		ReadConsistency: request.ReadConsistency,
+		ForwardDepth:    request.ForwardDepth + 1,
+		MinIndex:        request.MinIndex,
+		MinIndexTimeout: request.MinIndexTimeout,
+	}
+	r.log.Trace("Forwarding %v", forward)
+	stream, err := r.raft.Protocol().Query(context.Background(), forward, *leader)
 	if err != nil {
 		return err
 	}
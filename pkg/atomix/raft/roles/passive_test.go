@@ -16,13 +16,26 @@ package roles
 
 import (
 	"context"
+	"errors"
+	"github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/atomix/go-framework/pkg/atomix/node"
 	"github.com/atomix/go-framework/pkg/atomix/service"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/protocol/mock"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -208,6 +221,380 @@ func TestPassiveAppend(t *testing.T) {
 	assert.Equal(t, raft.Index(3), response.LastLogIndex)
 }
 
+// unsyncedMetadataStore is a metadata store that fails to sync
+type unsyncedMetadataStore struct {
+	raft.MetadataStore
+}
+
+func (s *unsyncedMetadataStore) Sync() error {
+	return errors.New("sync failed")
+}
+
+func TestPassiveWitnessAppendSyncFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	dir, err := ioutil.TempDir("", "raft-witness")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	metadata := raft.NewFileMetadataStore("foo", 0)
+	assert.NoError(t, metadata.Open(dir))
+	defer metadata.Close()
+
+	members := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {ID: "foo", Host: "localhost", ProtocolPort: 5000},
+			"bar": {ID: "bar", Host: "localhost", ProtocolPort: 5001},
+			"baz": {ID: "baz", Host: "localhost", ProtocolPort: 5002},
+		},
+	}
+	config := &config.ProtocolConfig{}
+	witnessCluster := raft.NewClusterWithMemberTypes(members, map[raft.MemberID]raft.Member_Type{"foo": raft.Member_WITNESS})
+	stores := store.NewMemoryStore()
+	sm := state.NewManager(witnessCluster.Member(), stores, node.GetRegistry(), config)
+	protocol := raft.NewPersistentRaft(witnessCluster, config, mock.NewMockClient(ctrl), newRoleFuncs(), &unsyncedMetadataStore{metadata})
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+
+	// Verify the witness doesn't acknowledge entries it failed to durably record, including when the leader retries
+	request := &raft.AppendRequest{
+		Term:         1,
+		Leader:       "bar",
+		PrevLogIndex: 5,
+		PrevLogTerm:  1,
+		CommitIndex:  5,
+	}
+	for i := 0; i < 2; i++ {
+		response, err := role.Append(context.TODO(), request)
+		assert.NoError(t, err)
+		assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+		assert.False(t, response.Succeeded)
+		assert.Equal(t, raft.Index(0), response.LastLogIndex)
+		assert.Equal(t, raft.WitnessRecord{}, role.raft.WitnessRecord())
+	}
+}
+
+func TestPassiveAppendTermGap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+		MaxTermGap:      100,
+	}
+	protocol, sm, stores := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewMemoryStore(), config)
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	gaps := metrics.NewCounter("raft_term_gaps_total", string(protocol.Member()))
+	initialGaps := gaps.Value()
+
+	// Verify a term within the maximum gap is accepted silently
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:   2,
+		Leader: "bar",
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, initialGaps, gaps.Value())
+
+	// Verify a term far ahead of the local term is flagged but still handled per the protocol
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:   10000,
+		Leader: "baz",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Term(10000), response.Term)
+	assert.Equal(t, raft.Term(10000), role.raft.Term())
+	assert.Equal(t, raft.MemberID("baz"), *role.raft.Leader())
+	assert.Equal(t, initialGaps+1, gaps.Value())
+	assert.NotEqual(t, raft.StatusFaulted, role.raft.Status())
+
+	// Verify the member is faulted if configured to require manual intervention
+	config.FaultOnTermGap = true
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:   20000,
+		Leader: "bar",
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Term(20000), role.raft.Term())
+	assert.Equal(t, initialGaps+2, gaps.Value())
+	assert.Equal(t, raft.StatusFaulted, role.raft.Status())
+}
+
+func TestPassiveAppendTruncateTail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	protocol, sm, stores := newTestState(mock.NewMockClient(ctrl))
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+
+	// Populate the log with two committed entries and an uncommitted entry from a prior leader
+	newEntry := func(term raft.Term) *raft.LogEntry {
+		return &raft.LogEntry{
+			Term:      term,
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Initialize{
+				Initialize: &raft.InitializeEntry{},
+			},
+		}
+	}
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:        2,
+		Leader:      "bar",
+		Entries:     []*raft.LogEntry{newEntry(1), newEntry(2), newEntry(2)},
+		CommitIndex: 2,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), role.store.Writer().LastIndex())
+	assert.Equal(t, raft.Index(2), role.raft.CommitIndex())
+
+	// Verify the uncommitted entry is truncated once a new leader's log is known to end before it
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         3,
+		Leader:       "baz",
+		PrevLogIndex: 2,
+		PrevLogTerm:  2,
+		CommitIndex:  2,
+		LastLogIndex: 2,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(2), response.LastLogIndex)
+	assert.Equal(t, raft.Index(2), role.store.Writer().LastIndex())
+	assert.Equal(t, raft.Term(2), role.store.Writer().LastEntry().Entry.Term)
+
+	// Verify entries from the leader's term are not truncated by a delayed request
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         3,
+		Leader:       "baz",
+		PrevLogIndex: 2,
+		PrevLogTerm:  2,
+		Entries:      []*raft.LogEntry{newEntry(3)},
+		CommitIndex:  2,
+		LastLogIndex: 3,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), role.store.Writer().LastIndex())
+
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         3,
+		Leader:       "baz",
+		PrevLogIndex: 2,
+		PrevLogTerm:  2,
+		CommitIndex:  2,
+		LastLogIndex: 2,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), role.store.Writer().LastIndex())
+	assert.Equal(t, raft.Term(3), role.store.Writer().LastEntry().Entry.Term)
+}
+
+// awaitApplied receives applied indexes from the given channel until none are received within the given timeout
+func awaitApplied(applied <-chan raft.Index, timeout time.Duration) []raft.Index {
+	var indexes []raft.Index
+	for {
+		select {
+		case index := <-applied:
+			indexes = append(indexes, index)
+		case <-time.After(timeout):
+			return indexes
+		}
+	}
+}
+
+func TestPassiveAppendUncommittedTail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	newEntry := func(term raft.Term) *raft.LogEntry {
+		return &raft.LogEntry{
+			Term:      term,
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Initialize{
+				Initialize: &raft.InitializeEntry{},
+			},
+		}
+	}
+
+	// Restart a member whose log ends in entries that were not known to be committed when it stopped
+	stores := store.NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		stores.Writer().Append(newEntry(1))
+	}
+	protocol, sm, stores := newTestStateWithStore(mock.NewMockClient(ctrl), stores, &config.ProtocolConfig{})
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	applied := make(chan raft.Index, 10)
+	sm.AddApplyListener(func(event state.ApplyEvent) {
+		applied <- event.Index
+	}, 10, state.OverflowBlock)
+
+	// Verify only entries up to the leader's commit index are applied, even though the member's log matches the leader's
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         2,
+		Leader:       "bar",
+		PrevLogIndex: 5,
+		PrevLogTerm:  1,
+		CommitIndex:  3,
+		LastLogIndex: 5,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), role.raft.CommitIndex())
+	assert.Equal(t, []raft.Index{1, 2, 3}, awaitApplied(applied, 100*time.Millisecond))
+
+	// Verify the tail is applied once the leader commits it
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         2,
+		Leader:       "bar",
+		PrevLogIndex: 5,
+		PrevLogTerm:  1,
+		Entries:      []*raft.LogEntry{newEntry(2)},
+		CommitIndex:  6,
+		LastLogIndex: 6,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, []raft.Index{4, 5, 6}, awaitApplied(applied, 100*time.Millisecond))
+}
+
+// corruptLog is a log whose readers report the entry at the given index as corrupt
+type corruptLog struct {
+	log.Log
+	index raft.Index
+}
+
+func (l *corruptLog) OpenReader(index raft.Index) log.Reader {
+	return &corruptReader{
+		Reader: l.Log.OpenReader(index),
+		index:  l.index,
+	}
+}
+
+type corruptReader struct {
+	log.Reader
+	index raft.Index
+	err   error
+}
+
+func (r *corruptReader) NextEntry() *log.Entry {
+	r.err = nil
+	if r.NextIndex() == r.index && r.LastIndex() >= r.index {
+		r.err = &log.CorruptEntryError{Index: r.index}
+		return nil
+	}
+	return r.Reader.NextEntry()
+}
+
+func (r *corruptReader) Err() error {
+	return r.err
+}
+
+func TestPassiveAppendCorruptEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	newEntry := func(term raft.Term) *raft.LogEntry {
+		return &raft.LogEntry{
+			Term:      term,
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Initialize{
+				Initialize: &raft.InitializeEntry{},
+			},
+		}
+	}
+	newStore := func() store.Store {
+		stores := store.NewStore(&corruptLog{Log: log.NewMemoryLog(), index: 2}, snapshot.NewMemoryStore())
+		for i := 0; i < 3; i++ {
+			stores.Writer().Append(newEntry(1))
+		}
+		return stores
+	}
+
+	// Verify a corrupt uncommitted entry is replaced with the leader's entry
+	protocol, sm, stores := newTestStateWithStore(mock.NewMockClient(ctrl), newStore(), &config.ProtocolConfig{})
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         1,
+		Leader:       "bar",
+		PrevLogIndex: 1,
+		PrevLogTerm:  1,
+		Entries:      []*raft.LogEntry{newEntry(1), newEntry(1)},
+		CommitIndex:  1,
+	})
+	assert.NoError(t, err)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), response.LastLogIndex)
+	assert.Equal(t, raft.Index(3), stores.Writer().LastIndex())
+	assert.NotEqual(t, raft.StatusFaulted, role.raft.Status())
+
+	// Verify a corrupt committed entry is never truncated and faults the member
+	protocol, sm, stores = newTestStateWithStore(mock.NewMockClient(ctrl), newStore(), &config.ProtocolConfig{})
+	role = newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	role.raft.Commit(raft.Index(3))
+	response, err = role.Append(context.TODO(), &raft.AppendRequest{
+		Term:         1,
+		Leader:       "bar",
+		PrevLogIndex: 2,
+		PrevLogTerm:  1,
+		Entries:      []*raft.LogEntry{newEntry(1)},
+		CommitIndex:  3,
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Succeeded)
+	assert.Equal(t, raft.Index(3), stores.Writer().LastIndex())
+	assert.Equal(t, raft.StatusFaulted, role.raft.Status())
+}
+
+func TestPassiveAppendDiskFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	fs := &lowSpaceFileSystem{free: 512}
+	diskCheckInterval := time.Duration(0)
+	config := &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			Directory:         "/data",
+			MinFreeDiskSpace:  1024,
+			DiskCheckInterval: &diskCheckInterval,
+		},
+	}
+	protocol, sm, stores := newTestStateWithStore(mock.NewMockClient(ctrl), store.NewDiskMonitoredStore(store.NewMemoryStore(), fs, config, raft.MemberID("foo")), config)
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+
+	// Verify heartbeats are accepted while the disk is low on space
+	response, err := role.Append(context.TODO(), &raft.AppendRequest{
+		Term:   1,
+		Leader: "bar",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.True(t, response.Succeeded)
+
+	// Verify entries are refused without being written to the log
+	request := &raft.AppendRequest{
+		Term:   1,
+		Leader: "bar",
+		Entries: []*raft.LogEntry{
+			{
+				Term:      1,
+				Timestamp: time.Now(),
+				Entry: &raft.LogEntry_Initialize{
+					Initialize: &raft.InitializeEntry{},
+				},
+			},
+		},
+	}
+	response, err = role.Append(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_DISK_FULL, response.Error)
+	assert.False(t, response.Succeeded)
+	assert.Equal(t, raft.Index(0), role.store.Writer().LastIndex())
+
+	// Verify entries are appended once space is freed
+	fs.free = 4096
+	response, err = role.Append(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.True(t, response.Succeeded)
+	assert.Equal(t, raft.Index(1), role.store.Writer().LastIndex())
+}
+
 func TestPassiveCommand(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	protocol, sm, stores := newTestState(mock.NewMockClient(ctrl))
@@ -321,6 +708,7 @@ func TestPassiveInstall(t *testing.T) {
 		Index:     raft.Index(10),
 		Timestamp: timestamp,
 		Data:      []byte("a"),
+		Checksum:  crc32.ChecksumIEEE([]byte("a")),
 	}, nil)
 	ch <- raft.NewInstallStreamRequest(&raft.InstallRequest{
 		Term:      raft.Term(1),
@@ -328,6 +716,7 @@ func TestPassiveInstall(t *testing.T) {
 		Index:     raft.Index(10),
 		Timestamp: timestamp,
 		Data:      []byte("b"),
+		Checksum:  crc32.ChecksumIEEE([]byte("ab")),
 	}, nil)
 	ch <- raft.NewInstallStreamRequest(&raft.InstallRequest{
 		Term:      raft.Term(1),
@@ -335,6 +724,7 @@ func TestPassiveInstall(t *testing.T) {
 		Index:     raft.Index(10),
 		Timestamp: timestamp,
 		Data:      []byte("c"),
+		Checksum:  crc32.ChecksumIEEE([]byte("abc")),
 	}, nil)
 	close(ch)
 
@@ -352,3 +742,139 @@ func TestPassiveInstall(t *testing.T) {
 	assert.Equal(t, "abc", string(bytes))
 	role.raft.ReadUnlock()
 }
+
+// newInstallRequests returns install requests for the given chunks of snapshot data with running checksums
+func newInstallRequests(leader raft.MemberID, index raft.Index, timestamp time.Time, chunks ...string) []*raft.InstallStreamRequest {
+	requests := make([]*raft.InstallStreamRequest, len(chunks))
+	var checksum uint32
+	for i, chunk := range chunks {
+		checksum = crc32.Update(checksum, crc32.IEEETable, []byte(chunk))
+		requests[i] = raft.NewInstallStreamRequest(&raft.InstallRequest{
+			Term:         raft.Term(1),
+			Leader:       leader,
+			Index:        index,
+			Timestamp:    timestamp,
+			Data:         []byte(chunk),
+			SnapshotTerm: raft.Term(1),
+			Checksum:     checksum,
+		}, nil)
+	}
+	return requests
+}
+
+func TestPassiveInstallWithoutChecksum(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	expectQuery(client).AnyTimes()
+	protocol, sm, stores := newTestState(client)
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	leader := raft.MemberID("bar")
+	assert.NoError(t, role.raft.SetLeader(&leader))
+
+	// Verify a snapshot from a leader that doesn't send checksums is installed without being verified
+	requests := newInstallRequests(leader, raft.Index(10), time.Now(), "a", "b", "c")
+	ch := make(chan *raft.InstallStreamRequest, len(requests))
+	for _, request := range requests {
+		request.Request.Checksum = 0
+		ch <- request
+	}
+	close(ch)
+
+	response, err := role.Install(ch)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	role.raft.ReadLock()
+	assert.Equal(t, raft.Index(10), role.store.Snapshot().CurrentSnapshot().Index())
+	role.raft.ReadUnlock()
+}
+
+func newFilePassiveRole(t *testing.T, client raft.Client, dir string) (*PassiveRole, *snapshot.FileStore) {
+	snapshots := snapshot.NewFileStore()
+	assert.NoError(t, snapshots.Open(dir))
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	protocol, sm, stores := newTestStateWithStore(client, store.NewMemoryLogStore(snapshots), config)
+	role := newPassiveRole(protocol, sm, stores, util.NewNodeLogger(string(protocol.Member())))
+	assert.NoError(t, role.raft.SetTerm(raft.Term(1)))
+	leader := raft.MemberID("bar")
+	assert.NoError(t, role.raft.SetLeader(&leader))
+	return role, snapshots
+}
+
+func TestPassiveInstallCrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	expectQuery(client).AnyTimes()
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Begin installing a snapshot and crash once part of the snapshot has been written
+	role, _ := newFilePassiveRole(t, client, dir)
+	timestamp := time.Now()
+	ch := make(chan *raft.InstallStreamRequest, 3)
+	defer close(ch)
+	requests := newInstallRequests(*role.raft.Leader(), raft.Index(10), timestamp, "a", "b", "c")
+	ch <- requests[0]
+	ch <- requests[1]
+	go func() {
+		_, _ = role.Install(ch)
+	}()
+	partial := filepath.Join(dir, "snapshot-00000000000000000010-00000000000000000001.data.*.tmp")
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if paths, _ := filepath.Glob(partial); len(paths) == 1 {
+			if bytes, err := ioutil.ReadFile(paths[0]); err == nil && string(bytes) == "ab" {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for partial snapshot")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Verify the partial snapshot is discarded on restart
+	role, snapshots := newFilePassiveRole(t, client, dir)
+	assert.Nil(t, snapshots.CurrentSnapshot())
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+
+	// Verify a snapshot with data that does not match the leader's checksum is rejected
+	corrupt := newInstallRequests(*role.raft.Leader(), raft.Index(10), timestamp, "a", "b", "c")
+	corrupt[1].Request.Data = []byte("x")
+	retry := make(chan *raft.InstallStreamRequest, len(corrupt))
+	for _, request := range corrupt {
+		retry <- request
+	}
+	close(retry)
+	response, err := role.Install(retry)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Status)
+	assert.Equal(t, raft.ResponseError_PROTOCOL_ERROR, response.Error)
+	assert.Nil(t, snapshots.CurrentSnapshot())
+
+	// Re-install the snapshot and verify it's committed
+	retry = make(chan *raft.InstallStreamRequest, len(requests))
+	for _, request := range newInstallRequests(*role.raft.Leader(), raft.Index(10), timestamp, "a", "b", "c") {
+		retry <- request
+	}
+	close(retry)
+	response, err = role.Install(retry)
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_OK, response.Status)
+	assert.Equal(t, raft.Index(10), snapshots.CurrentSnapshot().Index())
+
+	// Verify the installed snapshot is recovered on restart
+	_, snapshots = newFilePassiveRole(t, client, dir)
+	current := snapshots.CurrentSnapshot()
+	assert.Equal(t, raft.Index(10), current.Index())
+	reader := current.Reader()
+	bytes, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, "abc", string(bytes))
+}
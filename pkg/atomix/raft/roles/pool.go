@@ -0,0 +1,202 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"container/list"
+	"sync"
+)
+
+// newWorkerPool returns a new pool that runs tasks on at most the given number of goroutines
+func newWorkerPool(size int) *workerPool {
+	return newBudgetedWorkerPool(size, nil)
+}
+
+// newBudgetedWorkerPool returns a new pool that runs tasks on at most the given number of goroutines, drawing its
+// workers from the given budget shared with other pools
+func newBudgetedWorkerPool(size int, budget *workerBudget) *workerPool {
+	pool := &workerPool{
+		size:   size,
+		tasks:  list.New(),
+		budget: budget,
+	}
+	pool.cond = sync.NewCond(&pool.mu)
+	return pool
+}
+
+// workerPool runs tasks on a bounded set of reused goroutines
+// Workers are started on demand up to the pool size and wait for further tasks once started. Tasks submitted while
+// all workers are busy are queued, so submitting a task never blocks. Tasks queued before the pool is closed are
+// always run, so callers may wait for their completion, but tasks submitted once the pool is closed are rejected.
+// If the pool draws its workers from a budget, a worker is only started once the budget has room for it, and workers
+// exit rather than waiting once the pool's queue is empty so their place in the budget can be taken by another pool.
+type workerPool struct {
+	size     int
+	workers  int
+	idle     int
+	detached int
+	tasks    *list.List
+	budget   *workerBudget
+	waiting  bool
+	closed   bool
+	cond     *sync.Cond
+	mu       sync.Mutex
+}
+
+// submit submits a task to be run by the pool
+// It returns a bool indicating whether the task was accepted, which is false if the pool is closed. Rejected tasks
+// are never run, so callers must fail any work waiting on them.
+func (p *workerPool) submit(task func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	p.tasks.PushBack(task)
+	p.schedule()
+	return true
+}
+
+// schedule wakes an idle worker or starts a new one to run a queued task
+// The pool's lock must be held by the caller.
+func (p *workerPool) schedule() {
+	if p.idle > 0 {
+		p.idle--
+		p.cond.Signal()
+	} else if p.workers < p.size && (p.budget == nil || p.budget.acquire(p)) {
+		p.workers++
+		go p.run()
+	}
+}
+
+// detach gives up the place of a worker running an abandoned task so another worker can be started in its place
+// The pool can't tell which worker is running the abandoned task, so the next worker to finish a task exits in its
+// place. This ensures tasks that never return can't starve the tasks queued behind them.
+func (p *workerPool) detach() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.workers == p.idle {
+		return
+	}
+	p.workers--
+	p.detached++
+	if p.tasks.Len() > 0 {
+		p.schedule()
+	}
+}
+
+// grant starts a worker using a place in the budget released by another pool
+// If the pool no longer needs another worker, the place is released again.
+func (p *workerPool) grant() {
+	p.mu.Lock()
+	p.waiting = false
+	if p.tasks.Len() > 0 && p.workers < p.size {
+		p.workers++
+		p.mu.Unlock()
+		go p.run()
+		return
+	}
+	p.mu.Unlock()
+	p.budget.release()
+}
+
+// run runs tasks until the pool is closed and no tasks remain
+func (p *workerPool) run() {
+	p.mu.Lock()
+	for {
+		for p.tasks.Len() == 0 && !p.closed && p.budget == nil {
+			p.idle++
+			p.cond.Wait()
+		}
+		if p.tasks.Len() == 0 {
+			p.workers--
+			p.mu.Unlock()
+			if p.budget != nil {
+				p.budget.release()
+			}
+			return
+		}
+		task := p.tasks.Remove(p.tasks.Front()).(func())
+		p.mu.Unlock()
+		task()
+		p.mu.Lock()
+
+		// If a worker was detached, exit in its place.
+		if p.detached > 0 {
+			p.detached--
+			p.mu.Unlock()
+			if p.budget != nil {
+				p.budget.release()
+			}
+			return
+		}
+	}
+}
+
+// close closes the pool
+// Workers exit once all queued tasks have been run.
+func (p *workerPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	p.cond.Broadcast()
+}
+
+// newWorkerBudget returns a new budget allowing at most the given number of workers across all pools drawing from it
+func newWorkerBudget(size int) *workerBudget {
+	return &workerBudget{
+		size:    size,
+		waiting: list.New(),
+	}
+}
+
+// workerBudget bounds the total number of workers run by a set of pools
+// Pools that can't start a worker when the budget is exhausted wait in order to be granted the next released place.
+type workerBudget struct {
+	size    int
+	workers int
+	waiting *list.List
+	mu      sync.Mutex
+}
+
+// acquire attempts to reserve a place in the budget for a worker of the given pool
+// If the budget is exhausted, the pool is queued to be granted a place once one is released. The pool's lock must
+// be held by the caller.
+func (b *workerBudget) acquire(pool *workerPool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.workers < b.size {
+		b.workers++
+		return true
+	}
+	if !pool.waiting {
+		pool.waiting = true
+		b.waiting.PushBack(pool)
+	}
+	return false
+}
+
+// release releases a worker's place in the budget, granting it to the next waiting pool if any
+func (b *workerBudget) release() {
+	b.mu.Lock()
+	if b.waiting.Len() == 0 {
+		b.workers--
+		b.mu.Unlock()
+		return
+	}
+	pool := b.waiting.Remove(b.waiting.Front()).(*workerPool)
+	b.mu.Unlock()
+	pool.grant()
+}
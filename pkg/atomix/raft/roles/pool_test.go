@@ -0,0 +1,260 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	pool := newWorkerPool(2)
+	release := make(chan struct{})
+	var running, completed int32
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		pool.submit(func() {
+			defer wg.Done()
+			atomic.AddInt32(&running, 1)
+			<-release
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&completed, 1)
+		})
+	}
+
+	// Verify no more than the pool size of tasks run concurrently
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&running))
+	pool.mu.Lock()
+	assert.Equal(t, 2, pool.workers)
+	assert.Equal(t, 8, pool.tasks.Len())
+	pool.mu.Unlock()
+
+	// Verify queued tasks are run by the existing workers
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(10), atomic.LoadInt32(&completed))
+	pool.mu.Lock()
+	assert.Equal(t, 2, pool.workers)
+	pool.mu.Unlock()
+
+	// Verify tasks submitted after the pool is closed are rejected and the workers exit
+	pool.close()
+	assert.False(t, pool.submit(func() {
+		t.Error("task submitted after the pool was closed was run")
+	}))
+	for {
+		pool.mu.Lock()
+		workers := pool.workers
+		pool.mu.Unlock()
+		if workers == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPoolDetach(t *testing.T) {
+	pool := newWorkerPool(2)
+	defer pool.close()
+
+	// Occupy both workers with tasks that don't return until released
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		assert.True(t, pool.submit(func() {
+			started <- struct{}{}
+			<-release
+		}))
+	}
+	<-started
+	<-started
+
+	// Verify tasks queued behind the stuck tasks are run as the workers running them are detached
+	for i := 0; i < 2; i++ {
+		done := make(chan struct{})
+		assert.True(t, pool.submit(func() {
+			close(done)
+		}))
+		select {
+		case <-done:
+			t.Fatal("task ran while all workers were busy")
+		case <-time.After(10 * time.Millisecond):
+		}
+		pool.detach()
+		<-done
+	}
+
+	// Verify the workers exit in place of the detached workers, leaving the pool at its size once the tasks return
+	close(release)
+	for {
+		pool.mu.Lock()
+		workers, idle, detached := pool.workers, pool.idle, pool.detached
+		pool.mu.Unlock()
+		if detached == 0 && workers == idle {
+			assert.Equal(t, 2, workers)
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerBudget(t *testing.T) {
+	// Simulate a leader catching up many lagging members, each with its own pool drawing from a shared budget
+	const members = 32
+	const appends = 8
+	const size = 4
+	budget := newWorkerBudget(size)
+	pools := make([]*workerPool, members)
+	for i := range pools {
+		pools[i] = newBudgetedWorkerPool(2, budget)
+	}
+
+	var running, peak, completed int32
+	wg := &sync.WaitGroup{}
+	for i := 0; i < appends; i++ {
+		for _, pool := range pools {
+			wg.Add(1)
+			pool.submit(func() {
+				defer wg.Done()
+				count := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&peak)
+					if count <= max || atomic.CompareAndSwapInt32(&peak, max, count) {
+						break
+					}
+				}
+				budget.mu.Lock()
+				assert.True(t, budget.workers <= size)
+				budget.mu.Unlock()
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&completed, 1)
+			})
+		}
+	}
+
+	// Verify all tasks are run without the number of concurrent workers ever exceeding the budget
+	wg.Wait()
+	assert.Equal(t, int32(members*appends), atomic.LoadInt32(&completed))
+	assert.Equal(t, int32(size), atomic.LoadInt32(&peak))
+
+	// Verify the workers exit and release their places in the budget once the pools are idle
+	for {
+		budget.mu.Lock()
+		workers := budget.workers
+		budget.mu.Unlock()
+		if workers == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	for _, pool := range pools {
+		pool.mu.Lock()
+		assert.Equal(t, 0, pool.workers)
+		assert.False(t, pool.waiting)
+		pool.mu.Unlock()
+		pool.close()
+	}
+}
+
+// benchmarkCatchUp simulates appends to many lagging members, each of which reads its batch from the log in parallel
+// chunks before sending the batch, using the given function to run appends and chunk reads
+func benchmarkCatchUp(b *testing.B, run func(member int, read bool, task func())) {
+	const members = 32
+	const appends = 16
+	var peak int64
+	sampling := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			select {
+			case <-sampling:
+				return
+			default:
+			}
+			if count := int64(runtime.NumGoroutine()); count > atomic.LoadInt64(&peak) {
+				atomic.StoreInt64(&peak, count)
+			}
+			runtime.Gosched()
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg := &sync.WaitGroup{}
+		for member := 0; member < members; member++ {
+			wg.Add(appends)
+			for j := 0; j < appends; j++ {
+				member := member
+				run(member, false, func() {
+					defer wg.Done()
+					reads := &sync.WaitGroup{}
+					reads.Add(maxParallelReads)
+					for k := 0; k < maxParallelReads; k++ {
+						run(member, true, reads.Done)
+					}
+					reads.Wait()
+					time.Sleep(10 * time.Microsecond)
+				})
+			}
+		}
+		wg.Wait()
+	}
+	b.StopTimer()
+	close(sampling)
+	<-sampled
+	b.Logf("Peak of %d goroutines replicating to %d members", atomic.LoadInt64(&peak), members)
+}
+
+func BenchmarkCatchUpGoroutines(b *testing.B) {
+	var started int64
+	benchmarkCatchUp(b, func(member int, read bool, task func()) {
+		atomic.AddInt64(&started, 1)
+		go task()
+	})
+	b.Logf("Started %d goroutines", started)
+}
+
+func BenchmarkCatchUpWorkerPool(b *testing.B) {
+	appendWorkers := (&config.ProtocolConfig{}).GetMaxAppendWorkersOrDefault()
+	workers := make([]*workerPool, 32)
+	readWorkers := make([]*workerPool, 32)
+	for i := range workers {
+		workers[i] = newWorkerPool(appendWorkers)
+		readWorkers[i] = newWorkerPool(maxParallelReads)
+	}
+	benchmarkCatchUp(b, func(member int, read bool, task func()) {
+		if read {
+			readWorkers[member].submit(task)
+		} else {
+			workers[member].submit(task)
+		}
+	})
+	started := 0
+	for i := range workers {
+		started += workers[i].workers + readWorkers[i].workers
+		workers[i].close()
+		readWorkers[i].close()
+	}
+	b.Logf("Started %d goroutines", started)
+}
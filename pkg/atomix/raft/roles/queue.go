@@ -0,0 +1,104 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"container/list"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+)
+
+// newEntryQueue returns a new queue that caches at most the given number of bytes of entries
+func newEntryQueue(maxBytes int) *entryQueue {
+	return &entryQueue{
+		entries:  list.New(),
+		maxBytes: maxBytes,
+	}
+}
+
+// entryQueue is a queue of entries cached for replication to a member
+// When a member falls behind, the queue grows until the entries are drained into append requests. Once the size of
+// the queue reaches the maximum, entries are no longer queued until the queue has been drained, so the queued entries
+// are always contiguous. Entries that were not queued are read from the log when they're appended to the member.
+type entryQueue struct {
+	entries  *list.List
+	size     int
+	maxBytes int
+	full     bool
+}
+
+// queuedEntry is an entry in the queue
+type queuedEntry struct {
+	index raft.Index
+	entry *log.Entry
+	size  int
+}
+
+// push adds the given entry to the back of the queue if the queue has room for it
+func (q *entryQueue) push(entry *log.Entry) {
+	if q.entries.Len() == 0 {
+		q.full = false
+	}
+	size := entry.Entry.XXX_Size()
+	if q.full || q.size+size > q.maxBytes {
+		q.full = true
+		return
+	}
+	q.entries.PushBack(&queuedEntry{
+		index: entry.Index,
+		entry: entry,
+		size:  size,
+	})
+	q.size += size
+}
+
+// front returns the index of the entry at the front of the queue and a bool indicating whether the queue is non-empty
+func (q *entryQueue) front() (raft.Index, bool) {
+	element := q.entries.Front()
+	if element == nil {
+		return 0, false
+	}
+	return element.Value.(*queuedEntry).index, true
+}
+
+// contains returns a bool indicating whether the entry at the given index is in the queue
+func (q *entryQueue) contains(index raft.Index) bool {
+	front, back := q.entries.Front(), q.entries.Back()
+	return front != nil && front.Value.(*queuedEntry).index <= index && back.Value.(*queuedEntry).index >= index
+}
+
+// pop removes the entry at the front of the queue and returns it
+func (q *entryQueue) pop() *log.Entry {
+	element := q.entries.Front()
+	if element == nil {
+		return nil
+	}
+	q.entries.Remove(element)
+	queued := element.Value.(*queuedEntry)
+	q.size -= queued.size
+	return queued.entry
+}
+
+// clear removes all entries from the queue
+func (q *entryQueue) clear() {
+	q.entries.Init()
+	q.size = 0
+	q.full = false
+}
+
+// len returns the number of entries in the queue
+func (q *entryQueue) len() int {
+	return q.entries.Len()
+}
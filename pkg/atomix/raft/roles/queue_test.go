@@ -0,0 +1,58 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAppenderQueueBound(t *testing.T) {
+	appender := newTestMemberAppender(t, 100, 1)
+	appender.queue = newEntryQueue(512)
+
+	// Queue the entries as they would be queued when appended by the leader
+	reader := appender.store.Log().OpenReader(1)
+	expected := make([]*raft.LogEntry, 0, 100)
+	for i := 0; i < 100; i++ {
+		entry := reader.NextEntry()
+		expected = append(expected, entry.Entry)
+		appender.queue.push(entry)
+	}
+
+	// Verify entries are only queued up to the maximum size, and the queued entries are contiguous
+	indexes := queuedIndexes(appender)
+	assert.True(t, len(indexes) > 0)
+	assert.True(t, len(indexes) < 100)
+	assert.True(t, appender.queue.size <= 512)
+	for i, index := range indexes {
+		assert.Equal(t, raft.Index(i+1), index)
+	}
+
+	// Verify the entries that were not queued are read from the log when the queue is drained into an append request
+	request := appender.entriesAppendRequest()
+	assert.Len(t, request.Entries, 100)
+	for i, entry := range request.Entries {
+		assert.True(t, expected[i].Equal(entry), "entry %d does not match", i+1)
+	}
+	assert.Equal(t, 0, appender.queue.len())
+	assert.Equal(t, 0, appender.queue.size)
+
+	// Verify entries are queued again once the queue has been drained
+	entry := appender.store.Writer().Append(expected[0])
+	appender.queue.push(entry)
+	assert.Equal(t, []raft.Index{entry.Index}, queuedIndexes(appender))
+}
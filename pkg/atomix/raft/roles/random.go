@@ -0,0 +1,38 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"math/rand"
+)
+
+// Random is a source of randomness for election timeouts
+// Tests can inject a Random to control the order in which members time out and start elections.
+type Random interface {
+	// Int63n returns a non-negative random number in [0,n)
+	Int63n(n int64) int64
+}
+
+// NewRandom returns a Random backed by the default source of the math/rand package
+func NewRandom() Random {
+	return defaultRandom{}
+}
+
+// defaultRandom is a Random backed by the default source of the math/rand package
+type defaultRandom struct{}
+
+func (defaultRandom) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
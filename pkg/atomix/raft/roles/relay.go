@@ -0,0 +1,276 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"context"
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"sort"
+	"sync"
+	"time"
+)
+
+// newRelay returns a new relay for the local member
+func newRelay(state raft.Raft, store store.Store, log util.Logger) *relay {
+	return &relay{
+		raft:      state,
+		store:     store,
+		log:       log,
+		members:   make(map[raft.MemberID]*relayMember),
+		unrelayed: make(map[raft.MemberID]bool),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// relay relays committed entries from the local member to its downstream members in the replication tree
+// Only committed entries are relayed, and only from the local log. A downstream member that has fallen behind the
+// local log's first index can't be caught up by the relay, so the relay stops relaying to the member and reports it
+// to the leader in its append responses, and the leader replicates to the member directly, installing a snapshot
+// if the member needs one.
+type relay struct {
+	raft      raft.Raft
+	store     store.Store
+	log       util.Logger
+	members   map[raft.MemberID]*relayMember
+	unrelayed map[raft.MemberID]bool
+	stopped   chan struct{}
+	closed    bool
+	mu        sync.Mutex
+}
+
+// relayMember is the replication state of a downstream member
+type relayMember struct {
+	memberID  raft.MemberID
+	reader    log.Reader
+	nextIndex raft.Index
+	sending   bool
+	removed   bool
+}
+
+// start starts relaying entries once per heartbeat interval until the relay is stopped
+func (r *relay) start() {
+	ticker := time.NewTicker(r.raft.Config().GetHeartbeatIntervalOrDefault())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.tick()
+		case <-r.stopped:
+			return
+		}
+	}
+}
+
+// stop stops the relay
+func (r *relay) stop() {
+	close(r.stopped)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for _, member := range r.members {
+		r.remove(member)
+	}
+	r.members = nil
+}
+
+// remove removes the given downstream member, closing its reader once the member is no longer being replicated to
+// The caller must hold the relay lock.
+func (r *relay) remove(member *relayMember) {
+	if member.sending {
+		member.removed = true
+	} else {
+		member.reader.Close()
+	}
+}
+
+// unrelayedMembers returns the downstream members the relay can't catch up, sorted by member ID
+func (r *relay) unrelayedMembers() []raft.MemberID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.unrelayed) == 0 {
+		return nil
+	}
+	members := make([]raft.MemberID, 0, len(r.unrelayed))
+	for member := range r.unrelayed {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i] < members[j]
+	})
+	return members
+}
+
+// fallback stops relaying to the given member and reports it to the leader to be replicated to directly
+func (r *relay) fallback(member *relayMember, reason string) {
+	r.log.Info("Cannot relay entries to %s: %s; falling back to the leader", member.memberID, reason)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unrelayed[member.memberID] = true
+}
+
+// tick updates the set of downstream members for the current leader and starts replicating to idle members
+func (r *relay) tick() {
+	r.raft.ReadLock()
+	var targets []raft.MemberID
+	if leader := r.raft.Leader(); leader != nil {
+		committed := r.raft.Configuration()
+		targets = committed.ReplicationTargets(*leader, r.raft.Member(), int(r.raft.Config().GetRelayFanout()))
+	}
+	commitIndex := r.raft.CommitIndex()
+	r.raft.ReadUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	members := make(map[raft.MemberID]*relayMember)
+	for _, memberID := range targets {
+		if r.unrelayed[memberID] {
+			continue
+		}
+		member, ok := r.members[memberID]
+		if !ok {
+			member = &relayMember{
+				memberID:  memberID,
+				reader:    r.store.Log().OpenReader(0),
+				nextIndex: commitIndex + 1,
+			}
+		}
+		members[memberID] = member
+		if !member.sending {
+			member.sending = true
+			go r.replicate(member)
+		}
+	}
+	for memberID, member := range r.members {
+		if _, ok := members[memberID]; !ok {
+			r.remove(member)
+		}
+	}
+	r.members = members
+}
+
+// replicate sends committed entries to the given member until it has caught up with the local commit index
+// If the member has no committed entries to catch up on, a single empty request is sent to propagate the commit index.
+func (r *relay) replicate(member *relayMember) {
+	defer func() {
+		r.mu.Lock()
+		member.sending = false
+		if member.removed {
+			member.reader.Close()
+		}
+		r.mu.Unlock()
+	}()
+
+	for {
+		request := r.nextRequest(member)
+		if request == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.raft.Config().GetElectionTimeoutOrDefault())
+		r.log.SendTo("AppendRequest", request, member.memberID)
+		response, err := r.raft.Protocol().Append(ctx, request, member.memberID)
+		cancel()
+		if err != nil {
+			r.log.ErrorFrom("AppendRequest", err, member.memberID)
+			return
+		}
+		r.log.ReceiveFrom("AppendResponse", response, member.memberID)
+		if response.Status != raft.ResponseStatus_OK {
+			return
+		}
+
+		if response.Succeeded {
+			lastIndex := request.PrevLogIndex + raft.Index(len(request.Entries))
+			member.nextIndex = lastIndex + 1
+			if lastIndex >= request.CommitIndex {
+				return
+			}
+		} else {
+			// If the member's log is inconsistent with the local log, back up to the member's last index.
+			nextIndex := member.nextIndex - 1
+			if response.LastLogIndex < nextIndex {
+				nextIndex = response.LastLogIndex + 1
+			}
+			if nextIndex < 1 || nextIndex == member.nextIndex {
+				return
+			}
+			member.nextIndex = nextIndex
+		}
+
+		select {
+		case <-r.stopped:
+			return
+		default:
+		}
+	}
+}
+
+// nextRequest returns the next append request for the given member, or nil if no request can be sent
+func (r *relay) nextRequest(member *relayMember) *raft.AppendRequest {
+	r.raft.ReadLock()
+	defer r.raft.ReadUnlock()
+	leader := r.raft.Leader()
+	if leader == nil {
+		return nil
+	}
+
+	commitIndex := r.raft.CommitIndex()
+	if member.nextIndex > commitIndex+1 {
+		member.nextIndex = commitIndex + 1
+	}
+	prevIndex := member.nextIndex - 1
+	if firstIndex := member.reader.FirstIndex(); member.nextIndex < firstIndex {
+		r.fallback(member, fmt.Sprintf("entry %d precedes the first entry %d in the local log", member.nextIndex, firstIndex))
+		return nil
+	}
+	var prevTerm raft.Term
+	if prevIndex > 0 {
+		term, ok := r.store.TermAt(prevIndex)
+		if !ok {
+			r.fallback(member, fmt.Sprintf("entry %d is not in the local log", prevIndex))
+			return nil
+		}
+		prevTerm = term
+	}
+
+	request := &raft.AppendRequest{
+		Term:         r.raft.Term(),
+		Leader:       *leader,
+		PrevLogIndex: prevIndex,
+		PrevLogTerm:  prevTerm,
+		CommitIndex:  commitIndex,
+		LastLogIndex: commitIndex,
+	}
+
+	// Read committed entries from the next index, bounded by the maximum batch size.
+	size := 0
+	member.reader.Reset(member.nextIndex)
+	for index := member.nextIndex; index <= commitIndex && size < maxBatchSize; index++ {
+		entry := member.reader.NextEntry()
+		if entry == nil || entry.Index != index {
+			break
+		}
+		request.Entries = append(request.Entries, entry.Entry)
+		size += entry.Entry.XXX_Size()
+	}
+	return request
+}
@@ -24,12 +24,17 @@ import (
 
 // GetRoles returns a mapping of role types to role factories
 func GetRoles(state state.Manager, store store.Store) map[raft.RoleType]func(raft.Raft) raft.Role {
+	return GetRolesWithRandom(state, store, NewRandom())
+}
+
+// GetRolesWithRandom returns a map of roles that randomize election timeouts using the given source
+func GetRolesWithRandom(state state.Manager, store store.Store, random Random) map[raft.RoleType]func(raft.Raft) raft.Role {
 	return map[raft.RoleType]func(raft.Raft) raft.Role{
 		raft.RoleFollower: func(raft raft.Raft) raft.Role {
-			return newFollowerRole(raft, state, store)
+			return newFollowerRole(raft, state, store, random)
 		},
 		raft.RoleCandidate: func(raft raft.Raft) raft.Role {
-			return newCandidateRole(raft, state, store)
+			return newCandidateRole(raft, state, store, random)
 		},
 		raft.RoleLeader: func(raft raft.Raft) raft.Role {
 			return newLeaderRole(raft, state, store)
@@ -56,6 +61,24 @@ type raftRole struct {
 	active bool
 }
 
+// voters returns the IDs of the voting members of the committed configuration
+func (r *raftRole) voters() []raft.MemberID {
+	committed := r.raft.Configuration()
+	return committed.Voters()
+}
+
+// isVoter returns a bool indicating whether the local member is a voter
+func (r *raftRole) isVoter() bool {
+	member := r.raft.GetMember(r.raft.Member())
+	return member != nil && (member.Type == raft.Member_ACTIVE || member.Type == raft.Member_WITNESS)
+}
+
+// isWitness returns a bool indicating whether the local member is a witness, which votes but stores no entries
+func (r *raftRole) isWitness() bool {
+	member := r.raft.GetMember(r.raft.Member())
+	return member != nil && member.Type == raft.Member_WITNESS
+}
+
 // Start starts the role
 func (r *raftRole) Start() error {
 	return nil
@@ -144,6 +167,17 @@ func (r *raftRole) Transfer(ctx context.Context, request *raft.TransferRequest)
 	return response, nil
 }
 
+// Digest handles a log digest request
+func (r *raftRole) Digest(ctx context.Context, request *raft.DigestRequest) (*raft.DigestResponse, error) {
+	r.log.Request("DigestRequest", request)
+	response := &raft.DigestResponse{
+		Status: raft.ResponseStatus_ERROR,
+		Error:  raft.ResponseError_ILLEGAL_MEMBER_STATE,
+	}
+	_ = r.log.Response("DigestResponse", response, nil)
+	return response, nil
+}
+
 // Append handles a append request
 func (r *raftRole) Append(ctx context.Context, request *raft.AppendRequest) (*raft.AppendResponse, error) {
 	r.log.Request("AppendRequest", request)
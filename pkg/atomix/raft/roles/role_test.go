@@ -17,6 +17,7 @@ package roles
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
 	"github.com/atomix/go-framework/pkg/atomix/node"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
@@ -46,34 +47,69 @@ func newRoleFuncs(roles ...raft.Role) map[raft.RoleType]func(raft.Raft) raft.Rol
 }
 
 func newTestState(client raft.Client, roles ...raft.Role) (raft.Raft, state.Manager, store.Store) {
+	electionTimeout := 1 * time.Second
+	config := &config.ProtocolConfig{
+		ElectionTimeout: &electionTimeout,
+	}
+	return newTestStateWithStore(client, store.NewMemoryStore(), config, roles...)
+}
+
+func newTestStateWithStore(client raft.Client, store store.Store, config *config.ProtocolConfig, roles ...raft.Role) (raft.Raft, state.Manager, store.Store) {
 	members := cluster.Cluster{
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5000,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5000,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "bar",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 			"baz": {
-				ID:   "baz",
-				Host: "localhost",
-				Port: 5002,
+				ID:           "baz",
+				Host:         "localhost",
+				ProtocolPort: 5002,
+			},
+		},
+	}
+
+	memberTypes := make(map[raft.MemberID]raft.Member_Type)
+	for _, observer := range config.GetObservers() {
+		memberTypes[raft.MemberID(observer)] = raft.Member_PASSIVE
+	}
+	cluster := raft.NewClusterWithMemberTypes(members, memberTypes)
+	state := state.NewManager(cluster.Member(), store, node.GetRegistry(), config)
+	raft := raft.NewRaft(cluster, config, client, newRoleFuncs(roles...))
+	return raft, state, store
+}
+
+// newLargeTestState returns a new test state for a cluster of the local member "foo" and the given number of peers
+func newLargeTestState(client raft.Client, config *config.ProtocolConfig, peers int, roles ...raft.Role) (raft.Raft, state.Manager, store.Store) {
+	members := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5000,
 			},
 		},
 	}
+	for i := 1; i <= peers; i++ {
+		id := fmt.Sprintf("peer-%d", i)
+		members.Members[id] = cluster.Member{
+			ID:           id,
+			Host:         "localhost",
+			ProtocolPort: 5000 + i,
+		}
+	}
 
 	cluster := raft.NewCluster(members)
 	store := store.NewMemoryStore()
-	state := state.NewManager(cluster.Member(), store, node.GetRegistry())
-	electionTimeout := 1 * time.Second
-	config := &config.ProtocolConfig{
-		ElectionTimeout: &electionTimeout,
-	}
+	state := state.NewManager(cluster.Member(), store, node.GetRegistry(), config)
 	raft := raft.NewRaft(cluster, config, client, newRoleFuncs(roles...))
 	return raft, state, store
 }
@@ -83,30 +119,30 @@ func newTestRole(client raft.Client, f func(raft.Raft, state.Manager, store.Stor
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5000,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5000,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "bar",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 			"baz": {
-				ID:   "baz",
-				Host: "localhost",
-				Port: 5002,
+				ID:           "baz",
+				Host:         "localhost",
+				ProtocolPort: 5002,
 			},
 		},
 	}
 
 	cluster := raft.NewCluster(members)
 	store := store.NewMemoryStore()
-	state := state.NewManager(cluster.Member(), store, node.GetRegistry())
 	electionTimeout := 1 * time.Second
 	config := &config.ProtocolConfig{
 		ElectionTimeout: &electionTimeout,
 	}
+	state := state.NewManager(cluster.Member(), store, node.GetRegistry(), config)
 	roleFuncs := newRoleFuncs(roles...)
 	r := raft.NewRaft(cluster, config, client, roleFuncs)
 	role := f(r, state, store)
@@ -149,6 +185,10 @@ func TestRole(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_ERROR, transferResponse.Status)
 
+	digestResponse, err := role.Digest(context.TODO(), &raft.DigestRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ResponseStatus_ERROR, digestResponse.Status)
+
 	appendResponse, err := role.Append(context.TODO(), &raft.AppendRequest{})
 	assert.NoError(t, err)
 	assert.Equal(t, raft.ResponseStatus_ERROR, appendResponse.Status)
@@ -395,6 +435,15 @@ func failAppend(client *mock.MockClient) *gomock.Call {
 		Return(nil, errors.New("AppendRequest failed"))
 }
 
+// quietLogs disables tracing for tests replicating large snapshots and returns a function restoring it
+// Tracing each megabyte chunk of a snapshot is slow enough under the race detector to outlast install timeouts.
+func quietLogs() func() {
+	logrus.SetLevel(logrus.InfoLevel)
+	return func() {
+		logrus.SetLevel(logrus.TraceLevel)
+	}
+}
+
 func init() {
 	logrus.SetLevel(logrus.TraceLevel)
 }
@@ -0,0 +1,54 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"sync"
+	"time"
+)
+
+// newLogSampler returns a new sampler that allows a message to be logged at most once per the given interval
+// An interval of 0 allows every message to be logged.
+func newLogSampler(interval time.Duration) *logSampler {
+	return &logSampler{
+		interval: interval,
+	}
+}
+
+// logSampler limits how often a repetitive message is logged
+// The first occurrence is always logged. Subsequent occurrences are logged at most once per interval, and the
+// number of occurrences suppressed since the last logged occurrence is reported with the next logged occurrence.
+type logSampler struct {
+	interval   time.Duration
+	last       time.Time
+	suppressed int
+	mu         sync.Mutex
+}
+
+// sample returns a bool indicating whether an occurrence should be logged along with the number of occurrences
+// suppressed since the last logged occurrence
+func (s *logSampler) sample() (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if !s.last.IsZero() && now.Sub(s.last) < s.interval {
+		s.suppressed++
+		return false, 0
+	}
+	suppressed := s.suppressed
+	s.last = now
+	s.suppressed = 0
+	return true, suppressed
+}
@@ -0,0 +1,83 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roles
+
+import (
+	"github.com/atomix/go-framework/pkg/atomix/stream"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"time"
+)
+
+// BeginRead begins a read transaction
+// The transaction pins the state machine at a read index no less than the commit index at the time the transaction
+// begins, and all queries in the transaction observe the state at that index. Changes committed while the
+// transaction is open are applied once the transaction is closed or the timeout expires.
+func (r *LeaderRole) BeginRead(timeout time.Duration) (raft.ReadTransaction, error) {
+	// The read index is only known once the leader has committed an entry from its term.
+	if !r.awaitReady() {
+		return nil, &raft.ErrNotLeader{}
+	}
+
+	// All entries up to the commit index have been passed to the state manager, so the pinned index is
+	// at least the current commit index.
+	pin := r.state.PinRead(timeout)
+
+	// Verify the leader is still the leader to ensure the read index is current.
+	if err := r.appender.heartbeat(); err != nil {
+		pin.Release()
+		return nil, err
+	}
+	return &readTransaction{
+		role: r,
+		pin:  pin,
+	}, nil
+}
+
+// readTransaction is a ReadTransaction served by the leader
+type readTransaction struct {
+	role *LeaderRole
+	pin  state.ReadPin
+}
+
+func (t *readTransaction) Index() raft.Index {
+	return t.pin.Index()
+}
+
+func (t *readTransaction) Query(request *raft.QueryRequest, responseCh chan<- *raft.QueryStreamResponse) error {
+	t.role.log.Request("QueryRequest", request)
+	defer close(responseCh)
+
+	entry := &log.Entry{
+		Index: t.pin.Index(),
+		Entry: &raft.LogEntry{
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Query{
+				Query: &raft.QueryEntry{
+					Value: request.Value,
+				},
+			},
+		},
+	}
+
+	outputCh := make(chan stream.Result)
+	t.pin.ApplyQuery(entry, stream.NewChannelStream(outputCh))
+	return t.role.sendQueryResults(outputCh, responseCh)
+}
+
+func (t *readTransaction) Close() {
+	t.pin.Release()
+}
@@ -15,6 +15,8 @@
 package raft
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
@@ -24,48 +26,149 @@ import (
 	"github.com/atomix/raft-replica/pkg/atomix/raft/roles"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
 	"google.golang.org/grpc"
+	"io"
 	"net"
+	"sort"
 	"sync"
+	"time"
 )
 
 // NewServer returns a new Raft consensus protocol server
-func NewServer(clusterConfig cluster.Cluster, registry *node.Registry, protocolConfig *config.ProtocolConfig) *Server {
-	member, ok := clusterConfig.Members[clusterConfig.MemberID]
-	if !ok {
-		panic("Local member is not present in cluster configuration!")
+// An error is returned if the cluster configuration is invalid.
+func NewServer(clusterConfig cluster.Cluster, registry *node.Registry, protocolConfig *config.ProtocolConfig) (*Server, error) {
+	return newServer(clusterConfig, protocolConfig, log.NewMemoryLog(), newPrimitiveStateMachine(registry), roles.NewRandom())
+}
+
+// NewServerWithLog returns a new Raft consensus protocol server that stores its log in the given backend
+// An error is returned if the cluster configuration is invalid.
+func NewServerWithLog(clusterConfig cluster.Cluster, registry *node.Registry, protocolConfig *config.ProtocolConfig, log log.Log) (*Server, error) {
+	return newServer(clusterConfig, protocolConfig, log, newPrimitiveStateMachine(registry), roles.NewRandom())
+}
+
+// NewServerWithStateMachine returns a new Raft consensus protocol server that stores its log in the given backend and
+// applies entries to the state machine returned by the given factory rather than the primitive state machine
+// An error is returned if the cluster configuration is invalid.
+func NewServerWithStateMachine(clusterConfig cluster.Cluster, protocolConfig *config.ProtocolConfig, log log.Log, factory state.StateMachineFactory) (*Server, error) {
+	return newServer(clusterConfig, protocolConfig, log, factory, roles.NewRandom())
+}
+
+// newPrimitiveStateMachine returns a factory for the primitive state machine of the given registry
+func newPrimitiveStateMachine(registry *node.Registry) state.StateMachineFactory {
+	return func(context node.Context) node.StateMachine {
+		return node.NewPrimitiveStateMachine(registry, context)
 	}
+}
 
-	cluster := raft.NewCluster(clusterConfig)
+// newServer returns a new Raft consensus protocol server that stores its log in the given backend, applies entries to
+// the state machine returned by the given factory, and randomizes election timeouts using the given source
+func newServer(clusterConfig cluster.Cluster, protocolConfig *config.ProtocolConfig, log log.Log, factory state.StateMachineFactory, random roles.Random) (*Server, error) {
+	if err := validateClusterConfig(clusterConfig); err != nil {
+		return nil, err
+	}
+	member := clusterConfig.Members[clusterConfig.MemberID]
+
+	memberTypes := make(map[raft.MemberID]raft.Member_Type)
+	for _, observer := range protocolConfig.GetObservers() {
+		memberTypes[raft.MemberID(observer)] = raft.Member_PASSIVE
+	}
+	for _, witness := range protocolConfig.GetWitnesses() {
+		memberTypes[raft.MemberID(witness)] = raft.Member_WITNESS
+	}
+	cluster := raft.NewClusterWithMemberTypes(clusterConfig, memberTypes)
 	protocol := raft.NewClient(cluster)
-	store := store.NewMemoryStore()
-	state := state.NewManager(cluster.Member(), store, registry)
-	roles := roles.GetRoles(state, store)
-	raft := raft.NewRaft(cluster, protocolConfig, protocol, roles)
+
+	// If a data directory is configured, persist snapshots and metadata in the directory
+	var snapshots *snapshot.FileStore
+	var metadata *raft.FileMetadataStore
+	var base store.Store
+	if protocolConfig.GetStorage().GetDataDir() != "" {
+		snapshots = snapshot.NewFileStore()
+		metadata = raft.NewFileMetadataStore(cluster.Member(), protocolConfig.GetMetadataSyncWindowOrDefault())
+		base = store.NewStore(log, snapshots)
+	} else {
+		base = store.NewStore(log, snapshot.NewMemoryStore())
+	}
+	store := store.NewDiskMonitoredStore(base, store.NewFileSystem(), protocolConfig, cluster.Member())
+	state := newStateManager(cluster.Member(), store, protocolConfig, metadata, factory)
+	roles := roles.GetRolesWithRandom(state, store, random)
 	server := &Server{
-		raft:  raft,
-		state: state,
-		store: store,
-		port:  member.ProtocolPort,
-		mu:    sync.Mutex{},
+		cluster:   cluster,
+		metadata:  metadata,
+		state:     state,
+		store:     store,
+		snapshots: snapshots,
+		port:      member.ProtocolPort,
+		mu:        sync.Mutex{},
+	}
+
+	// If a data directory is configured, persist the term and vote in the directory
+	if metadata != nil {
+		server.raft = raft.NewPersistentRaft(cluster, protocolConfig, protocol, roles, metadata)
+	} else {
+		server.raft = raft.NewRaft(cluster, protocolConfig, protocol, roles)
+	}
+
+	// The fault is reported on the apply goroutine, which must not block on the Raft state lock.
+	state.WatchFault(func(err error) {
+		go server.fault()
+	})
+	return server, nil
+}
+
+// validateClusterConfig verifies the local member is present in the given cluster configuration
+// A local member missing from the configuration, e.g. due to a typo in its ID, would otherwise replicate to and count
+// votes from every configured member as if it were a peer, breaking quorum.
+func validateClusterConfig(clusterConfig cluster.Cluster) error {
+	if clusterConfig.MemberID == "" {
+		return errors.New("no local member configured")
+	}
+	if _, ok := clusterConfig.Members[clusterConfig.MemberID]; !ok {
+		members := make([]string, 0, len(clusterConfig.Members))
+		for id := range clusterConfig.Members {
+			members = append(members, id)
+		}
+		sort.Strings(members)
+		return fmt.Errorf("local member %s is not a member of the cluster %v", clusterConfig.MemberID, members)
+	}
+	return nil
+}
+
+// newStateManager returns a new state manager, persisting the last applied index in the given metadata store if any
+func newStateManager(member raft.MemberID, store store.Store, config *config.ProtocolConfig, metadata *raft.FileMetadataStore, factory state.StateMachineFactory) state.Manager {
+	if metadata == nil {
+		return state.NewManagerWithStateMachine(member, store, config, nil, factory)
 	}
-	return server
+	return state.NewManagerWithStateMachine(member, store, config, metadata, factory)
 }
 
 // Server implements the Raft consensus protocol server
 type Server struct {
-	raft   raft.Raft
-	state  state.Manager
-	store  store.Store
-	server *grpc.Server
-	port   int
-	mu     sync.Mutex
+	cluster   raft.Cluster
+	raft      raft.Raft
+	metadata  *raft.FileMetadataStore
+	state     state.Manager
+	store     store.Store
+	snapshots *snapshot.FileStore
+	server    *grpc.Server
+	port      int
+	opened    bool
+	mu        sync.Mutex
 }
 
 // Start starts the Raft server
 func (s *Server) Start() error {
 	s.mu.Lock()
 
+	// Verify the data directory belongs to this member before initializing the Raft state
+	if err := s.open(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
 	// Initialize the Raft state
 	s.raft.WriteLock()
 	s.raft.Init()
@@ -73,40 +176,545 @@ func (s *Server) Start() error {
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
 	if err != nil {
+		s.mu.Unlock()
 		return err
 	}
 
 	s.server = grpc.NewServer()
 	raft.RegisterRaftServiceServer(s.server, raft.NewServer(s.raft))
+	raft.RegisterRaftStatusServiceServer(s.server, raft.NewStatusServer(s.raft, s.store.Writer().LastIndex))
 	s.mu.Unlock()
 	return s.server.Serve(lis)
 }
 
+// fault marks the server as faulted once its state machine has failed to apply an entry
+// If configured, a faulted leader steps down to allow a healthy member to be elected.
+func (s *Server) fault() {
+	s.raft.WriteLock()
+	defer s.raft.WriteUnlock()
+	s.raft.SetFaulted()
+	if s.raft.Role() == raft.RoleLeader && s.raft.Config().GetStepDownOnFault() {
+		_ = s.raft.SetLeader(nil)
+		s.raft.SetRole(raft.RoleFollower)
+	}
+}
+
+// open opens the data directory and persistent metadata and snapshot stores if a data directory is configured
+func (s *Server) open() error {
+	if s.opened || s.raft.Config().GetStorage().GetDataDir() == "" {
+		return nil
+	}
+	dir, err := store.OpenDataDir(s.raft.Config(), s.cluster.Member())
+	if err != nil {
+		return err
+	}
+	if err := s.metadata.Open(dir.MetaDir()); err != nil {
+		return err
+	}
+	if err := s.snapshots.Open(dir.SnapshotDir()); err != nil {
+		return err
+	}
+
+	// The persisted membership supersedes the membership the node was started with
+	if err := s.recoverConfiguration(); err != nil {
+		return err
+	}
+
+	// The log is not persisted, so it resumes from the committed snapshot if one was recovered
+	if current := s.snapshots.CurrentSnapshot(); current != nil {
+		s.store.Writer().Reset(current.Index() + 1)
+	}
+	s.opened = true
+	return nil
+}
+
+// storeConfiguration persists the local view of the cluster configuration if a data directory is configured
+// The view is changed locally rather than committed to the log, so it's stored without an index or term. The
+// timestamp records when it was changed. The configuration must be stored with a write lock on the Raft state.
+func (s *Server) storeConfiguration() error {
+	if s.metadata == nil {
+		return nil
+	}
+	committed := s.cluster.Configuration()
+	timestamp := time.Now()
+	committed.Timestamp = &timestamp
+	s.metadata.StoreConfiguration(committed)
+	return s.metadata.Sync()
+}
+
+// recoverConfiguration restores the cluster membership and member types from the persisted configuration, if any
+// Members the node was started with that are not in the persisted configuration were removed before the node was
+// restarted and are removed again. Members in the persisted configuration must be present in the membership the
+// node was started with, since the configuration does not record members' addresses.
+func (s *Server) recoverConfiguration() error {
+	configuration := s.metadata.LoadConfiguration()
+	if configuration == nil {
+		return nil
+	}
+	log := util.NewNodeLogger(string(s.cluster.Member()))
+	members := make(map[raft.MemberID]bool)
+	for _, member := range configuration.Members {
+		current := s.cluster.GetMember(member.MemberID)
+		if current == nil {
+			return fmt.Errorf("persisted configuration member %s is not a member of the cluster", member.MemberID)
+		}
+		if current.Type != member.Type {
+			log.Info("Restoring member %s type %s from the persisted configuration", member.MemberID, member.Type)
+			if err := s.cluster.SetMemberType(member.MemberID, member.Type); err != nil {
+				return err
+			}
+		}
+		members[member.MemberID] = true
+	}
+	for _, member := range s.cluster.Members() {
+		if !members[member] {
+			log.Info("Removing member %s not in the persisted configuration", member)
+			if err := s.cluster.RemoveMember(member); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Backup writes the server's term, vote, commit index, current snapshot, and log to the given writer
+// The bundle is captured while holding the Raft read lock to ensure the metadata is consistent with the log, and
+// is written to the writer once the lock has been released.
+func (s *Server) Backup(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	s.raft.ReadLock()
+	metadata := store.BackupMetadata{
+		Term:        s.raft.Term(),
+		Vote:        s.raft.LastVotedFor(),
+		CommitIndex: s.raft.CommitIndex(),
+	}
+	err := store.WriteBackup(s.store, metadata, buf)
+	s.raft.ReadUnlock()
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Restore restores a bundle written by Backup to the server
+// Restore must be called before the server is started, and the server's store must be empty. A bundle from a
+// term prior to the server's persisted term is rejected, as is a bundle that would change the server's vote in
+// its persisted term. Once restored, the committed entries are applied to the state machine.
+func (s *Server) Restore(r io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server != nil {
+		return errors.New("cannot restore a started server")
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	backup, err := store.OpenBackup(r)
+	if err != nil {
+		return err
+	}
+	metadata := backup.Metadata()
+
+	s.raft.WriteLock()
+	term, vote := s.raft.Term(), s.raft.LastVotedFor()
+	if s.metadata != nil {
+		if stored := s.metadata.LoadTerm(); stored != nil && *stored > term {
+			term = *stored
+		}
+		vote = s.metadata.LoadVote()
+	}
+	if metadata.Term < term {
+		s.raft.WriteUnlock()
+		return fmt.Errorf("cannot restore backup from term %d to a member in term %d", metadata.Term, term)
+	}
+	if metadata.Term == term && vote != nil && (metadata.Vote == nil || *metadata.Vote != *vote) {
+		s.raft.WriteUnlock()
+		return fmt.Errorf("cannot restore backup to a member that voted for %s in term %d", *vote, term)
+	}
+
+	if err := backup.Restore(s.store); err != nil {
+		s.raft.WriteUnlock()
+		return err
+	}
+	if err := s.raft.SetTerm(metadata.Term); err != nil {
+		s.raft.WriteUnlock()
+		return err
+	}
+	if metadata.Vote != nil {
+		if err := s.raft.SetLastVotedFor(*metadata.Vote); err != nil {
+			s.raft.WriteUnlock()
+			return err
+		}
+	}
+	if err := s.raft.SyncMetadata(); err != nil {
+		s.raft.WriteUnlock()
+		return err
+	}
+	s.raft.Commit(metadata.CommitIndex)
+	s.raft.WriteUnlock()
+
+	if metadata.CommitIndex > 0 {
+		<-s.state.WaitApplied(metadata.CommitIndex)
+	}
+	return nil
+}
+
 // WaitForReady blocks the current goroutine until the server is ready
 func (s *Server) WaitForReady() error {
+	return s.WaitForReadyContext(context.Background())
+}
+
+// WaitForReadyContext blocks the current goroutine until the server is ready or the given context is done
+// If the context is done first, e.g. because the cluster's peers are unreachable and the server can't catch up,
+// an error is returned describing how long the server waited.
+func (s *Server) WaitForReadyContext(ctx context.Context) error {
+	startTime := time.Now()
 	ch := make(chan struct{})
-	s.raft.Watch(func(event raft.Event) {
+	once := &sync.Once{}
+	unwatch := s.raft.Watch(func(event raft.Event) {
 		if event.Type == raft.EventTypeStatus && event.Status == raft.StatusReady {
-			ch <- struct{}{}
-			close(ch)
+			once.Do(func() {
+				close(ch)
+			})
 		}
 	})
-	_, ok := <-ch
-	if ok {
+	defer unwatch()
+
+	// The server may have become ready before the watcher was added.
+	s.raft.ReadLock()
+	ready := s.raft.Status() == raft.StatusReady
+	s.raft.ReadUnlock()
+	if ready {
+		return nil
+	}
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("server was not ready after %s: %v", time.Since(startTime), ctx.Err())
+	}
+}
+
+// UpdateMemberAddress updates the network address of the given member without changing cluster membership
+func (s *Server) UpdateMemberAddress(member raft.MemberID, host string, port int) error {
+	return s.cluster.UpdateMemberAddress(member, host, port)
+}
+
+// SetElectionTimeout updates the election timeout of this node without restarting it
+// The timeout must be at least twice the heartbeat interval. A leader's lease is bounded by the election timeout of
+// its followers, so when reducing the timeout across a cluster, the timeout should be reduced on the leader first.
+func (s *Server) SetElectionTimeout(timeout time.Duration) error {
+	s.raft.WriteLock()
+	defer s.raft.WriteUnlock()
+	return s.raft.SetElectionTimeout(timeout)
+}
+
+// EnterMaintenance prepares the server to be taken down for planned maintenance without disrupting the cluster
+// The server is put in maintenance mode, in which it continues to replicate entries and vote but does not stand for
+// election until ExitMaintenance is called or the configured maintenance window elapses. If the server is the leader,
+// leadership is transferred to the most up-to-date voter, and EnterMaintenance blocks until another member has been
+// elected. If the transfer fails, the server is taken out of maintenance mode and an error is returned.
+func (s *Server) EnterMaintenance(ctx context.Context) error {
+	startTime := time.Now()
+	member := s.cluster.Member()
+	elected := make(chan struct{})
+	once := &sync.Once{}
+	unwatch := s.raft.Watch(func(event raft.Event) {
+		if event.Type == raft.EventTypeLeader && event.Leader != nil && *event.Leader != member {
+			once.Do(func() {
+				close(elected)
+			})
+		}
+	})
+	defer unwatch()
+
+	s.raft.WriteLock()
+	s.raft.SetMaintenance(startTime.Add(s.raft.Config().GetMaintenanceWindowOrDefault()))
+	leader := s.raft.Role() == raft.RoleLeader
+	s.raft.WriteUnlock()
+	if !leader {
+		return nil
+	}
+
+	response, err := s.raft.Transfer(ctx, &raft.TransferRequest{})
+	if err == nil && response.Status != raft.ResponseStatus_OK {
+		err = fmt.Errorf("failed to transfer leadership: %s", response.Error)
+	}
+	if err != nil {
+		s.ExitMaintenance()
+		return err
+	}
+
+	select {
+	case <-elected:
 		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("no leader was elected after %s: %v", time.Since(startTime), ctx.Err())
+	}
+}
+
+// ExitMaintenance takes the server out of maintenance mode, allowing it to stand for election again
+func (s *Server) ExitMaintenance() {
+	s.raft.WriteLock()
+	defer s.raft.WriteUnlock()
+	s.raft.SetMaintenance(time.Time{})
+}
+
+// Configuration returns the committed cluster membership as seen by this node
+// Membership is fixed by the cluster configuration with which the server was started, so there is never a
+// pending membership change.
+func (s *Server) Configuration() *raft.Configuration {
+	return s.cluster.Configuration()
+}
+
+// ErrLogDiverged indicates a member's committed log does not match the leader's log
+type ErrLogDiverged struct {
+	// Member is the member whose log diverged
+	Member raft.MemberID
+	// FirstIndex is the first index of the range of entries that differ
+	FirstIndex raft.Index
+	// Index is the last index of the range of entries that differ
+	Index raft.Index
+}
+
+func (e *ErrLogDiverged) Error() string {
+	return fmt.Sprintf("log of member %s diverges from the leader's log between indexes %d and %d", e.Member, e.FirstIndex, e.Index)
+}
+
+// VerifyLog verifies the committed log of the given member matches the leader's log through the given index
+// This is an auditing tool for detecting bugs or corruption: committed entries are never expected to differ between
+// members. The member's digest of the entries in both logs is compared against a digest of the local log, and
+// ErrLogDiverged is returned if they differ. Entries compacted from either log are not verified. Only the leader can
+// verify logs, and only through its commit index.
+func (s *Server) VerifyLog(ctx context.Context, member raft.MemberID, index raft.Index) error {
+	s.raft.ReadLock()
+	isLeader := s.raft.Role() == raft.RoleLeader
+	commitIndex := s.raft.CommitIndex()
+	s.raft.ReadUnlock()
+	if !isLeader {
+		return &raft.ErrNotLeader{}
+	}
+	if index > commitIndex {
+		return raft.ErrEntriesUnavailable
+	}
+
+	// If the member has compacted entries the leader still retains, retry with the member's first index.
+	request := &raft.DigestRequest{
+		FirstIndex: s.store.Reader().FirstIndex(),
+		Index:      index,
+	}
+	response, err := s.raft.Protocol().Digest(ctx, request, member)
+	if err != nil {
+		return err
+	}
+	if response.Status == raft.ResponseStatus_ERROR && response.Error == raft.ResponseError_ENTRIES_UNAVAILABLE &&
+		response.FirstIndex > request.FirstIndex && response.FirstIndex <= index {
+		request.FirstIndex = response.FirstIndex
+		if response, err = s.raft.Protocol().Digest(ctx, request, member); err != nil {
+			return err
+		}
+	}
+	if response.Status == raft.ResponseStatus_ERROR {
+		return raft.NewError(response.Error, "", "")
+	}
+
+	digest, err := log.Digest(s.store.Log(), request.FirstIndex, request.Index)
+	if err != nil {
+		return raft.ErrEntriesUnavailable
+	}
+	if !bytes.Equal(digest, response.Digest) {
+		err := &ErrLogDiverged{
+			Member:     member,
+			FirstIndex: request.FirstIndex,
+			Index:      request.Index,
+		}
+		util.NewNodeLogger(string(s.cluster.Member())).Error("Log verification failed", err)
+		return err
+	}
+	return nil
+}
+
+// ForceInstall catches up the given member by installing a snapshot rather than replicating entries from the log
+// Catching up a member that lags far behind can be cheaper for the leader with a single install than by reading and
+// sending each missing entry. A snapshot is first taken at the commit index, then the member is sent the snapshot on
+// its next append in place of entries, even if the entries it's missing are still in the log. Once the snapshot is
+// installed, the member is caught up from the log as usual. This server must be the leader.
+func (s *Server) ForceInstall(ctx context.Context, member raft.MemberID) error {
+	s.raft.ReadLock()
+	isLeader := s.raft.Role() == raft.RoleLeader
+	commitIndex := s.raft.CommitIndex()
+	s.raft.ReadUnlock()
+	if !isLeader {
+		return &raft.ErrNotLeader{}
+	}
+
+	if err := s.state.Snapshot(ctx, commitIndex); err != nil {
+		return err
+	}
+	return s.raft.ForceInstall(member)
+}
+
+// ForceRemoveServer forcibly removes a permanently lost voter from this node's view of the cluster
+// This is a disaster recovery operation for restoring a quorum after a member is lost for good, and must be performed
+// on each surviving node. Unlike a membership change, the removal is neither committed to the log nor agreed with
+// the other members: removing a member that's still running, or removing different members on different nodes, can
+// elect two leaders and lose committed writes. To guard against mistakes, the member must be unreachable from this
+// node, and no more than a bare quorum of voters may be reachable, i.e. the cluster must be unable to commit if
+// another voter fails. If a data directory is configured, the resulting membership is persisted and recovered when
+// the node is restarted. Otherwise, the member must also be removed from each node's cluster configuration before
+// the node is restarted. If this node is the leader, it steps down so a leader is elected among the remaining voters.
+func (s *Server) ForceRemoveServer(member raft.MemberID) error {
+	log := util.NewNodeLogger(string(s.cluster.Member()))
+	if member == s.cluster.Member() {
+		return fmt.Errorf("cannot remove the local member %s", member)
+	}
+
+	s.raft.ReadLock()
+	committed := s.raft.Configuration()
+	request := &raft.PollRequest{
+		Term:      s.raft.Term(),
+		Candidate: s.raft.Member(),
+	}
+	if lastEntry := s.store.Writer().LastEntry(); lastEntry != nil {
+		request.LastLogIndex = lastEntry.Index
+		request.LastLogTerm = lastEntry.Entry.Term
+	}
+	timeout := s.raft.Config().GetElectionTimeoutOrDefault()
+	s.raft.ReadUnlock()
+
+	voters := committed.Voters()
+	isVoter := false
+	for _, voter := range voters {
+		if voter == member {
+			isVoter = true
+		}
+	}
+	if !isVoter {
+		return fmt.Errorf("%s is not a voter", member)
+	}
+
+	// Probe the other voters to determine which are reachable from this node.
+	reachable := make(chan raft.MemberID, len(voters))
+	wg := &sync.WaitGroup{}
+	for _, voter := range voters {
+		if voter == s.cluster.Member() {
+			continue
+		}
+		wg.Add(1)
+		go func(voter raft.MemberID) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if _, err := s.raft.Protocol().Poll(ctx, request, voter); err == nil {
+				reachable <- voter
+			}
+		}(voter)
+	}
+	wg.Wait()
+	close(reachable)
+
+	reachableCount := 1
+	for voter := range reachable {
+		if voter == member {
+			return fmt.Errorf("cannot remove %s: the member is reachable", member)
+		}
+		reachableCount++
+	}
+	if quorum := len(voters)/2 + 1; reachableCount > quorum {
+		return fmt.Errorf("cannot remove %s: %d of %d voters are reachable, and the cluster can tolerate the loss of another voter", member, reachableCount, len(voters))
+	}
+
+	log.Warn("Forcibly removing member %s from the cluster with %d of %d voters reachable; "+
+		"if %s is still running, committed writes may be lost", member, reachableCount, len(voters), member)
+	if s.metadata == nil {
+		log.Warn("Member %s must be removed from the cluster configuration of every node before restarting", member)
+	}
+
+	s.raft.WriteLock()
+	defer s.raft.WriteUnlock()
+	if err := s.cluster.RemoveMember(member); err != nil {
+		return err
+	}
+	if err := s.storeConfiguration(); err != nil {
+		return err
+	}
+	if s.raft.Role() == raft.RoleLeader {
+		_ = s.raft.SetLeader(nil)
+		s.raft.SetRole(raft.RoleFollower)
+	}
+	return nil
+}
+
+// snapshotOnStop takes a snapshot at the current commit index, waiting at most the snapshot-on-stop timeout
+// Failing to take the snapshot doesn't prevent the server from stopping. The server replays the log from the prior
+// snapshot on restart instead. A snapshot that has already started when the timeout expires completes before the
+// store is closed, since the state manager is closed first.
+func (s *Server) snapshotOnStop() {
+	s.raft.ReadLock()
+	commitIndex := s.raft.CommitIndex()
+	s.raft.ReadUnlock()
+	log := util.NewNodeLogger(string(s.cluster.Member()))
+	log.Info("Taking snapshot at index %d before stopping", commitIndex)
+	timeout := s.raft.Config().GetSnapshotOnStopTimeoutOrDefault()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.state.Snapshot(ctx, commitIndex); err == context.DeadlineExceeded {
+		log.Warn("Snapshot was not taken within %s; stopping without a snapshot", timeout)
+	} else if err != nil {
+		log.Warn("Failed to take snapshot before stopping: %v", err)
 	}
-	return errors.New("server stopped")
 }
 
 // Stop shuts down the Raft server
 func (s *Server) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.server != nil {
-		s.server.Stop()
+
+	// If configured, snapshot the state machine before stopping so the server restarts from a recent snapshot
+	if s.server != nil && s.raft.Config().GetCompaction().GetSnapshotOnStop() {
+		s.snapshotOnStop()
 	}
+
+	// Close the Raft state first to drain pending requests, then wait for their responses to be sent
 	s.raft.Close()
+	if s.server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(s.raft.Config().GetElectionTimeoutOrDefault()):
+			s.server.Stop()
+		}
+	}
 	s.state.Close()
 	s.store.Close()
 	return nil
 }
+
+// BeginRead begins a read transaction serving queries against the state machine at a single read index
+// Changes committed while the transaction is open are applied once it's closed, so the transaction is released
+// automatically once the configured read transaction timeout expires. Only the leader can serve read transactions.
+func (s *Server) BeginRead() (raft.ReadTransaction, error) {
+	return s.raft.BeginRead(s.raft.Config().GetReadTransactionTimeoutOrDefault())
+}
+
+// Timestamp returns a cluster-wide timestamp that never goes backward, even across leader changes
+// The timestamp is the later of the leader's clock and the timestamp of the last entry in the log, and is committed
+// to the log before it's returned. Only the leader can provide timestamps.
+func (s *Server) Timestamp() (time.Time, error) {
+	return s.raft.Timestamp()
+}
+
+// AddApplyListener registers a listener to be called with each entry applied to the server's state machine
+// Listeners are called in log order on a separate goroutine. Events are buffered up to the given buffer size, and
+// the policy determines whether events are dropped or the apply loop blocks once the buffer is full. The returned
+// function removes the listener. Listeners are removed when the server is stopped.
+func (s *Server) AddApplyListener(listener state.ApplyListener, bufferSize int, policy state.OverflowPolicy) func() {
+	return s.state.AddApplyListener(listener, bufferSize, policy)
+}
@@ -0,0 +1,1288 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"bytes"
+	"context"
+	"github.com/atomix/go-framework/pkg/atomix/cluster"
+	"github.com/atomix/go-framework/pkg/atomix/node"
+	"github.com/atomix/go-framework/pkg/atomix/registry"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/client"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/roles"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/state"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newBackupTestServer(t *testing.T, path string) *Server {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5680,
+			},
+		},
+	}
+	server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{
+		ClusterId: "test",
+		Storage: &config.StorageConfig{
+			DataDir: path,
+		},
+	})
+	assert.NoError(t, err)
+	return server
+}
+
+// startBackupTestServer starts the given server and waits for it to be elected leader
+func startBackupTestServer(t *testing.T, server *Server) {
+	ready := make(chan error)
+	go func() {
+		ready <- server.WaitForReady()
+	}()
+	go server.Start()
+	assert.NoError(t, <-ready)
+}
+
+func TestServerBackupRestore(t *testing.T) {
+	root, err := ioutil.TempDir("", "raft-backup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	// Start a node in a later term and back it up once it has been elected and committed its initial entries
+	// Single node clusters are elected without incrementing the term, so the term is persisted before starting.
+	source := newBackupTestServer(t, filepath.Join(root, "source"))
+	assert.NoError(t, source.open())
+	source.metadata.StoreTerm(raft.Term(2))
+	startBackupTestServer(t, source)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, source.Backup(buf))
+	source.raft.ReadLock()
+	term := source.raft.Term()
+	commitIndex := source.raft.CommitIndex()
+	source.raft.ReadUnlock()
+	lastIndex := source.store.Writer().LastIndex()
+	assert.Equal(t, raft.Term(2), term)
+	assert.True(t, commitIndex > 0)
+	assert.NoError(t, source.Stop())
+
+	// Verify the backup cannot be restored to a member in a later term
+	ahead := newBackupTestServer(t, filepath.Join(root, "ahead"))
+	assert.NoError(t, ahead.open())
+	ahead.metadata.StoreTerm(term + 1)
+	assert.NoError(t, ahead.metadata.Sync())
+	assert.Error(t, ahead.Restore(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, raft.Index(0), ahead.store.Writer().LastIndex())
+	assert.NoError(t, ahead.Stop())
+
+	// Restore the backup to a new node and verify the metadata and log are restored
+	target := newBackupTestServer(t, filepath.Join(root, "target"))
+	assert.NoError(t, target.Restore(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, term, *target.metadata.LoadTerm())
+	assert.Equal(t, lastIndex, target.store.Writer().LastIndex())
+	for i := raft.Index(1); i <= lastIndex; i++ {
+		expected, _ := source.store.TermAt(i)
+		actual, ok := target.store.TermAt(i)
+		assert.True(t, ok)
+		assert.Equal(t, expected, actual)
+	}
+
+	// Start the restored node and verify it is elected in the restored term and appends to the restored log
+	startBackupTestServer(t, target)
+	defer target.Stop()
+	target.raft.ReadLock()
+	assert.Equal(t, term, target.raft.Term())
+	assert.True(t, target.raft.CommitIndex() > lastIndex)
+	target.raft.ReadUnlock()
+	for i := raft.Index(1); i <= lastIndex; i++ {
+		expected, _ := source.store.TermAt(i)
+		actual, ok := target.store.TermAt(i)
+		assert.True(t, ok)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+// awaitServers polls the given servers until the given predicate holds for all of them or the timeout expires
+func awaitServers(servers []*Server, timeout time.Duration, f func(*Server) bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		ok := true
+		for _, server := range servers {
+			server.raft.ReadLock()
+			if !f(server) {
+				ok = false
+			}
+			server.raft.ReadUnlock()
+		}
+		if ok {
+			return true
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return false
+}
+
+func TestServerRelay(t *testing.T) {
+	voterIDs := []string{"a", "b", "c"}
+	observerIDs := []string{"d", "e", "f", "g", "h"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range append(voterIDs, observerIDs...) {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5710 + i,
+		}
+	}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+		RelayFanout:       2,
+		Observers:         observerIDs,
+	}
+
+	// Start a cluster of three voters and five observers
+	var voters, observers []*Server
+	for _, member := range append(voterIDs, observerIDs...) {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		if clusterConfig.Members[member].ProtocolPort < 5710+len(voterIDs) {
+			voters = append(voters, server)
+		} else {
+			observers = append(observers, server)
+		}
+		go server.Start()
+		defer server.Stop()
+	}
+
+	// Wait for a voter to be elected and commit its initial entry
+	var leader *Server
+	deadline := time.Now().Add(10 * time.Second)
+	for leader == nil && time.Now().Before(deadline) {
+		for _, server := range voters {
+			server.raft.ReadLock()
+			if server.raft.Role() == raft.RoleLeader && server.raft.CommitIndex() > 0 {
+				leader = server
+			}
+			server.raft.ReadUnlock()
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	leader.raft.ReadLock()
+	leaderID := leader.raft.Member()
+	commitIndex := leader.raft.CommitIndex()
+	progress := leader.raft.Progress()
+	leader.raft.ReadUnlock()
+
+	// Verify the leader replicates only to the other voters
+	assert.Len(t, progress, 2)
+	for _, member := range progress {
+		assert.NotEqual(t, leaderID, member.MemberID)
+		assert.Equal(t, raft.Member_ACTIVE, leader.cluster.GetMember(member.MemberID).Type)
+	}
+
+	// Verify the observers receive the committed entries via the relays
+	assert.True(t, awaitServers(observers, 10*time.Second, func(server *Server) bool {
+		leader := server.raft.Leader()
+		return leader != nil && *leader == leaderID &&
+			server.raft.CommitIndex() >= commitIndex &&
+			server.store.Writer().LastIndex() >= commitIndex
+	}))
+	for _, observer := range observers {
+		observer.raft.ReadLock()
+		assert.Equal(t, raft.RoleFollower, observer.raft.Role())
+		observer.raft.ReadUnlock()
+		for i := raft.Index(1); i <= commitIndex; i++ {
+			expected, _ := leader.store.TermAt(i)
+			actual, ok := observer.store.TermAt(i)
+			assert.True(t, ok)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+// awaitLeader polls the given servers until one of them is the leader and has committed an entry in its term
+func awaitLeader(servers []*Server, timeout time.Duration) *Server {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		for _, server := range servers {
+			server.raft.ReadLock()
+			leader := server.raft.Role() == raft.RoleLeader && server.raft.CommitIndex() > 0
+			server.raft.ReadUnlock()
+			if leader {
+				return server
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestServerForceRemove(t *testing.T) {
+	memberIDs := []string{"foo", "bar", "baz"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range memberIDs {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5720 + i,
+		}
+	}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+
+	servers := make([]*Server, 0, len(memberIDs))
+	for _, member := range memberIDs {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+		go server.Start()
+	}
+
+	// Permanently lose the leader and wait for a leader to be elected among the two remaining members
+	lost := awaitLeader(servers, 10*time.Second)
+	if !assert.NotNil(t, lost) {
+		return
+	}
+	lostID := lost.cluster.Member()
+	assert.NoError(t, lost.Stop())
+	survivors := make([]*Server, 0, 2)
+	for _, server := range servers {
+		if server != lost {
+			survivors = append(survivors, server)
+			defer server.Stop()
+		}
+	}
+	leader := awaitLeader(survivors, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+
+	// Verify the local member, reachable members, and unknown members cannot be removed
+	assert.Error(t, survivors[0].ForceRemoveServer(survivors[0].cluster.Member()))
+	assert.Error(t, survivors[0].ForceRemoveServer(survivors[1].cluster.Member()))
+	assert.Error(t, survivors[0].ForceRemoveServer(raft.MemberID("unknown")))
+
+	// Remove the lost member from both survivors
+	leader.raft.ReadLock()
+	term := leader.raft.Term()
+	commitIndex := leader.raft.CommitIndex()
+	leader.raft.ReadUnlock()
+	for _, server := range survivors {
+		assert.NoError(t, server.ForceRemoveServer(lostID))
+		committed := server.Configuration()
+		assert.Len(t, committed.Voters(), 2)
+		assert.Nil(t, server.cluster.GetMember(lostID))
+	}
+	assert.Error(t, survivors[0].ForceRemoveServer(lostID))
+
+	// Verify the two remaining members elect a leader that commits with a quorum of the new configuration
+	leader = awaitLeader(survivors, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	assert.True(t, awaitServers([]*Server{leader}, 10*time.Second, func(server *Server) bool {
+		return server.raft.Term() > term && server.raft.CommitIndex() > commitIndex
+	}))
+	leader.raft.ReadLock()
+	progress := leader.raft.Progress()
+	leader.raft.ReadUnlock()
+	assert.Len(t, progress, 1)
+}
+
+func TestServerVerifyLog(t *testing.T) {
+	memberIDs := []string{"foo", "bar", "baz"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range memberIDs {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5740 + i,
+		}
+	}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+
+	servers := make([]*Server, 0, len(memberIDs))
+	for _, member := range memberIDs {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+		go server.Start()
+		defer server.Stop()
+	}
+
+	leader := awaitLeader(servers, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	leader.raft.ReadLock()
+	commitIndex := leader.raft.CommitIndex()
+	leader.raft.ReadUnlock()
+	assert.True(t, awaitServers(servers, 10*time.Second, func(server *Server) bool {
+		return server.raft.CommitIndex() >= commitIndex
+	}))
+	var follower *Server
+	for _, server := range servers {
+		if server != leader {
+			follower = server
+		}
+	}
+	followerID := follower.cluster.Member()
+
+	// Verify the logs match and only the leader can verify logs
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, leader.VerifyLog(ctx, followerID, commitIndex))
+	assert.Error(t, follower.VerifyLog(ctx, leader.cluster.Member(), commitIndex))
+	assert.Equal(t, raft.ErrEntriesUnavailable, leader.VerifyLog(ctx, followerID, commitIndex+100))
+
+	// Corrupt the last committed entry in the follower's log and verify the divergence is detected
+	follower.raft.WriteLock()
+	entry := follower.store.Writer().LastEntry()
+	follower.store.Writer().Truncate(commitIndex - 1)
+	follower.store.Writer().Append(&raft.LogEntry{
+		Term:      entry.Entry.Term,
+		Timestamp: entry.Entry.Timestamp,
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("corrupt"),
+			},
+		},
+	})
+	follower.raft.WriteUnlock()
+	err := leader.VerifyLog(ctx, followerID, commitIndex)
+	if assert.IsType(t, &ErrLogDiverged{}, err) {
+		assert.Equal(t, followerID, err.(*ErrLogDiverged).Member)
+		assert.Equal(t, commitIndex, err.(*ErrLogDiverged).Index)
+	}
+	if commitIndex > 1 {
+		assert.NoError(t, leader.VerifyLog(ctx, followerID, commitIndex-1))
+	}
+}
+
+func TestServerScriptedElection(t *testing.T) {
+	memberIDs := []string{"foo", "bar", "baz"}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+
+	// Force each member in turn to time out first and verify it wins the election
+	for i, winner := range memberIDs {
+		clusterConfig := cluster.Cluster{
+			Members: map[string]cluster.Member{},
+		}
+		for j, member := range memberIDs {
+			clusterConfig.Members[member] = cluster.Member{
+				ID:           member,
+				Host:         "localhost",
+				ProtocolPort: 5730 + i*len(memberIDs) + j,
+			}
+		}
+
+		servers := make([]*Server, 0, len(memberIDs))
+		for _, member := range memberIDs {
+			clusterConfig.MemberID = member
+			random := newScriptedRandom(int64(electionTimeout))
+			if member == winner {
+				random = newScriptedRandom(0)
+			}
+			server, err := newServer(clusterConfig, protocolConfig, log.NewMemoryLog(), newPrimitiveStateMachine(registry.Registry), random)
+			assert.NoError(t, err)
+			servers = append(servers, server)
+			go server.Start()
+		}
+
+		leader := awaitLeader(servers, 10*time.Second)
+		if assert.NotNil(t, leader) {
+			assert.Equal(t, raft.MemberID(winner), leader.cluster.Member())
+		}
+		for _, server := range servers {
+			assert.NoError(t, server.Stop())
+		}
+	}
+}
+
+func TestServerWaitForReadyTimeout(t *testing.T) {
+	// Configure a cluster whose other members are never started
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members:  map[string]cluster.Member{},
+	}
+	for i, member := range []string{"foo", "bar", "baz"} {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5751 + i,
+		}
+	}
+	electionTimeout := 100 * time.Millisecond
+	heartbeatInterval := 20 * time.Millisecond
+	server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	})
+	assert.NoError(t, err)
+	go server.Start()
+	defer server.Stop()
+
+	// Verify waiting for the server to become ready fails once the deadline expires
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	err = server.WaitForReadyContext(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.True(t, time.Since(startTime) >= 500*time.Millisecond)
+	assert.True(t, time.Since(startTime) < 5*time.Second)
+}
+
+func TestServerRecoverConfiguration(t *testing.T) {
+	root, err := ioutil.TempDir("", "raft-configuration")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members:  map[string]cluster.Member{},
+	}
+	for i, member := range []string{"foo", "bar", "baz"} {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5755 + i,
+		}
+	}
+	electionTimeout := 500 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ClusterId:       "test",
+		ElectionTimeout: &electionTimeout,
+		Storage: &config.StorageConfig{
+			DataDir: filepath.Join(root, "foo"),
+		},
+	}
+
+	// Remove a lost member from a cluster with a witness
+	protocolConfig.Witnesses = []string{"bar"}
+	server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, server.open())
+	assert.NoError(t, server.ForceRemoveServer(raft.MemberID("baz")))
+	committed := server.Configuration()
+	assert.Len(t, committed.Voters(), 2)
+	assert.NoError(t, server.Stop())
+
+	// Restart the node without the witness configured and verify the updated membership and member types are recovered
+	protocolConfig.Witnesses = nil
+	server, err = NewServer(clusterConfig, registry.Registry, protocolConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, server.open())
+	defer server.Stop()
+	committed = server.Configuration()
+	assert.Equal(t, []raft.MemberID{"bar", "foo"}, committed.Voters())
+	assert.Equal(t, []raft.MemberID{"bar"}, committed.Witnesses())
+	assert.Nil(t, server.cluster.GetMember(raft.MemberID("baz")))
+	assert.Len(t, server.cluster.Members(), 2)
+
+	// Verify the locally changed configuration isn't labeled with a log index or term
+	persisted := server.metadata.LoadConfiguration()
+	assert.Equal(t, raft.Index(0), persisted.Index)
+	assert.Equal(t, raft.Term(0), persisted.Term)
+	assert.NotNil(t, persisted.Timestamp)
+}
+
+func TestServerForceRemoveRestart(t *testing.T) {
+	root, err := ioutil.TempDir("", "raft-configuration")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	memberIDs := []string{"foo", "bar", "baz"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range memberIDs {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5790 + i,
+		}
+	}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	newServer := func(member string) *Server {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{
+			ClusterId:         "test",
+			ElectionTimeout:   &electionTimeout,
+			HeartbeatInterval: &heartbeatInterval,
+			Storage: &config.StorageConfig{
+				DataDir: filepath.Join(root, member),
+			},
+		})
+		assert.NoError(t, err)
+		go server.Start()
+		return server
+	}
+
+	servers := make([]*Server, 0, len(memberIDs))
+	for _, member := range memberIDs {
+		servers = append(servers, newServer(member))
+	}
+
+	// Permanently lose the leader and forcibly remove it from the two remaining members
+	lost := awaitLeader(servers, 10*time.Second)
+	if !assert.NotNil(t, lost) {
+		return
+	}
+	lostID := lost.cluster.Member()
+	assert.NoError(t, lost.Stop())
+	survivorIDs := make([]string, 0, 2)
+	for _, server := range servers {
+		if server != lost {
+			survivorIDs = append(survivorIDs, string(server.cluster.Member()))
+			assert.NoError(t, server.ForceRemoveServer(lostID))
+		}
+	}
+	for _, server := range servers {
+		if server != lost {
+			assert.NoError(t, server.Stop())
+		}
+	}
+
+	// Restart the survivors with the lost member still in their cluster configuration
+	survivors := make([]*Server, 0, len(survivorIDs))
+	for _, member := range survivorIDs {
+		server := newServer(member)
+		defer server.Stop()
+		survivors = append(survivors, server)
+	}
+
+	// Verify the removal is recovered and the survivors elect a leader that commits with a quorum of the two
+	leader := awaitLeader(survivors, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	for _, server := range survivors {
+		committed := server.Configuration()
+		assert.Len(t, committed.Voters(), 2)
+		assert.Nil(t, server.cluster.GetMember(lostID))
+		persisted := server.metadata.LoadConfiguration()
+		assert.Len(t, persisted.Members, 2)
+	}
+	leader.raft.ReadLock()
+	progress := leader.raft.Progress()
+	leader.raft.ReadUnlock()
+	assert.Len(t, progress, 1)
+}
+
+func TestServerReadTransaction(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5774,
+			},
+		},
+	}
+	timeout := 500 * time.Millisecond
+	server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{
+		ReadTransactionTimeout: &timeout,
+	})
+	assert.NoError(t, err)
+	go server.Start()
+	defer server.Stop()
+
+	// Verify the transaction reads at the commit index once the server has been elected leader
+	var transaction raft.ReadTransaction
+	for i := 0; i < 100; i++ {
+		if transaction, err = server.BeginRead(); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+	server.raft.ReadLock()
+	commitIndex := server.raft.CommitIndex()
+	server.raft.ReadUnlock()
+	assert.Equal(t, commitIndex, transaction.Index())
+
+	// Verify the transaction is released once the read transaction timeout expires
+	time.Sleep(timeout * 2)
+	ch := make(chan *raft.QueryStreamResponse, 1)
+	assert.NoError(t, transaction.Query(&raft.QueryRequest{}, ch))
+	response := <-ch
+	assert.Equal(t, raft.ResponseStatus_ERROR, response.Response.Status)
+	transaction.Close()
+}
+
+func TestServerStateMachine(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5775,
+			},
+		},
+	}
+	server, err := NewServerWithStateMachine(clusterConfig, &config.ProtocolConfig{ClusterId: "test"}, log.NewMemoryLog(), func(node.Context) node.StateMachine {
+		return &testSerializingStateMachine{}
+	})
+	assert.NoError(t, err)
+	startBackupTestServer(t, server)
+	defer server.Stop()
+
+	c := client.NewClient(clusterConfig, raft.ReadConsistency_LINEARIZABLE)
+	defer c.Close()
+
+	// Verify command output serialized by the state machine round-trips through the client unchanged
+	ch := make(chan streams.Result, 1)
+	assert.NoError(t, c.Write(context.Background(), []byte("foo"), streams.NewChannelStream(ch)))
+	result := <-ch
+	assert.NoError(t, result.Error)
+	assert.Equal(t, "value:foo", string(result.Value.([]byte)))
+
+	// Verify query output serialized by the state machine round-trips through the client unchanged
+	ch = make(chan streams.Result, 1)
+	assert.NoError(t, c.Read(context.Background(), []byte{}, streams.NewChannelStream(ch)))
+	result = <-ch
+	assert.NoError(t, result.Error)
+	assert.Equal(t, "value:foo", string(result.Value.([]byte)))
+}
+
+func TestServerSnapshotMetadata(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5776,
+			},
+		},
+	}
+	protocolConfig := &config.ProtocolConfig{
+		ClusterId: "test",
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 1,
+		},
+	}
+	server, err := NewServerWithStateMachine(clusterConfig, protocolConfig, log.NewMemoryLog(), func(node.Context) node.StateMachine {
+		return &testMetadataStateMachine{testStateMachine: &testStateMachine{}}
+	})
+	assert.NoError(t, err)
+	startBackupTestServer(t, server)
+	defer server.Stop()
+
+	c := client.NewClient(clusterConfig, raft.ReadConsistency_SEQUENTIAL)
+	defer c.Close()
+	ch := make(chan streams.Result, 1)
+	assert.NoError(t, c.Write(context.Background(), []byte("foo"), streams.NewChannelStream(ch)))
+	assert.NoError(t, (<-ch).Error)
+
+	// Verify the metadata provided by the application's state machine is stored with the server's snapshots
+	assert.True(t, awaitServers([]*Server{server}, 5*time.Second, func(server *Server) bool {
+		snapshot := server.store.Snapshot().CurrentSnapshot()
+		return snapshot != nil && string(snapshot.Metadata()) == "value=foo"
+	}))
+}
+
+func TestServerApplyListener(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5777,
+			},
+		},
+	}
+	server, err := NewServerWithStateMachine(clusterConfig, &config.ProtocolConfig{}, log.NewMemoryLog(), func(node.Context) node.StateMachine {
+		return &testStateMachine{}
+	})
+	assert.NoError(t, err)
+	go server.Start()
+	defer server.Stop()
+	assert.NoError(t, server.WaitForReady())
+
+	events := make(chan state.ApplyEvent, 10)
+	remove := server.AddApplyListener(func(event state.ApplyEvent) {
+		if event.Type == state.EntryTypeCommand {
+			events <- event
+		}
+	}, 10, state.OverflowDrop)
+
+	c := client.NewClient(clusterConfig, raft.ReadConsistency_SEQUENTIAL)
+	defer c.Close()
+	write := func(value string) {
+		ch := make(chan streams.Result, 1)
+		assert.NoError(t, c.Write(context.Background(), []byte(value), streams.NewChannelStream(ch)))
+		assert.NoError(t, (<-ch).Error)
+	}
+
+	// Verify the listener observes commands applied to the server's state machine
+	write("foo")
+	select {
+	case event := <-events:
+		assert.NotEqual(t, raft.Index(0), event.Index)
+		assert.Equal(t, []byte("foo"), event.Output[0].Value)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "apply event was not received")
+	}
+
+	// Verify the listener is not called once removed
+	remove()
+	write("bar")
+	select {
+	case event := <-events:
+		assert.Fail(t, "unexpected apply event", "index %d", event.Index)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServerTimestamp(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5778,
+			},
+		},
+	}
+	server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{})
+	assert.NoError(t, err)
+	go server.Start()
+	defer server.Stop()
+
+	// Verify timestamps are committed to the log and never go backward once the server has been elected leader
+	var timestamp time.Time
+	for i := 0; i < 100; i++ {
+		if timestamp, err = server.Timestamp(); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		next, err := server.Timestamp()
+		assert.NoError(t, err)
+		assert.False(t, next.Before(timestamp))
+		timestamp = next
+	}
+	server.raft.ReadLock()
+	entry := server.store.Writer().LastEntry()
+	commitIndex := server.raft.CommitIndex()
+	server.raft.ReadUnlock()
+	assert.Equal(t, commitIndex, entry.Index)
+	assert.NotNil(t, entry.Entry.GetBarrier())
+	assert.True(t, entry.Entry.Timestamp.Equal(timestamp))
+}
+
+// testSerializingStateMachine is a state machine that stores the last command value and serializes its own outputs
+type testSerializingStateMachine struct {
+	value string
+	mu    sync.RWMutex
+}
+
+func (s *testSerializingStateMachine) Snapshot(writer io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, err := writer.Write([]byte(s.value))
+	return err
+}
+
+func (s *testSerializingStateMachine) Install(reader io.Reader) error {
+	bytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testSerializingStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	if stream != nil {
+		stream.Value(string(bytes))
+		stream.Close()
+	}
+}
+
+func (s *testSerializingStateMachine) Query(bytes []byte, stream streams.WriteStream) {
+	if stream != nil {
+		s.mu.RLock()
+		stream.Value(s.value)
+		s.mu.RUnlock()
+		stream.Close()
+	}
+}
+
+func (s *testSerializingStateMachine) SerializeOutput(value interface{}) ([]byte, error) {
+	return []byte("value:" + value.(string)), nil
+}
+
+func (s *testSerializingStateMachine) CanDelete(index uint64) bool {
+	return true
+}
+
+// newScriptedRandom returns a Random that returns the given values in order
+// Once the values are exhausted, the last value is repeated. Values are truncated to the requested range.
+func newScriptedRandom(values ...int64) roles.Random {
+	return &scriptedRandom{
+		values: values,
+	}
+}
+
+// scriptedRandom is a Random that returns a scripted sequence of values
+type scriptedRandom struct {
+	values []int64
+	next   int
+	mu     sync.Mutex
+}
+
+func (r *scriptedRandom) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.values) == 0 {
+		return 0
+	}
+	value := r.values[r.next]
+	if r.next < len(r.values)-1 {
+		r.next++
+	}
+	if value >= n {
+		return n - 1
+	}
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+func TestServerWitness(t *testing.T) {
+	memberIDs := []string{"a", "b", "w"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range memberIDs {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5760 + i,
+		}
+	}
+	electionTimeout := time.Second
+	heartbeatInterval := 100 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+		Witnesses:         []string{"w"},
+	}
+
+	servers := make(map[string]*Server)
+	for _, member := range memberIDs {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		servers[member] = server
+	}
+	committed := servers["a"].Configuration()
+	assert.Equal(t, []raft.MemberID{"a", "b", "w"}, committed.Voters())
+	assert.Equal(t, []raft.MemberID{"w"}, committed.Witnesses())
+
+	// Start one of the two data members with the witness. With the data members' votes split, the witness's
+	// vote decides the election, and its acknowledgement commits the leader's initial entry.
+	for _, member := range []string{"a", "w"} {
+		go servers[member].Start()
+		defer servers[member].Stop()
+	}
+	leader := awaitLeader([]*Server{servers["a"], servers["w"]}, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	assert.Equal(t, raft.MemberID("a"), leader.cluster.Member())
+	leader.raft.ReadLock()
+	commitIndex := leader.raft.CommitIndex()
+	leader.raft.ReadUnlock()
+
+	// Verify the witness recorded the committed entries without storing them
+	witness := servers["w"]
+	assert.True(t, awaitServers([]*Server{witness}, 10*time.Second, func(server *Server) bool {
+		return server.raft.WitnessRecord().Index >= commitIndex && server.raft.CommitIndex() >= commitIndex
+	}))
+	witness.raft.ReadLock()
+	assert.Equal(t, raft.RoleFollower, witness.raft.Role())
+	witness.raft.ReadUnlock()
+	assert.Equal(t, raft.Index(0), witness.store.Writer().LastIndex())
+
+	// Verify the other data member is caught up with entries once it's started
+	go servers["b"].Start()
+	defer servers["b"].Stop()
+	assert.True(t, awaitServers([]*Server{servers["b"]}, 10*time.Second, func(server *Server) bool {
+		return server.store.Writer().LastIndex() >= commitIndex && server.raft.CommitIndex() >= commitIndex
+	}))
+	assert.Equal(t, raft.Index(0), witness.store.Writer().LastIndex())
+
+	// Verify the witness refuses to elect a candidate whose log is behind its record, even if the candidate was
+	// transferred leadership while the witness is hearing from the leader
+	witness.raft.ReadLock()
+	term := witness.raft.Term()
+	record := witness.raft.WitnessRecord()
+	witness.raft.ReadUnlock()
+	response, err := witness.raft.Vote(context.Background(), &raft.VoteRequest{
+		Term:         term + 1,
+		Candidate:    "b",
+		LastLogIndex: record.Index - 1,
+		LastLogTerm:  record.Term,
+		Transfer:     true,
+	})
+	assert.NoError(t, err)
+	assert.False(t, response.Voted)
+	assert.Equal(t, raft.RejectionReason_LOG_BEHIND, response.Rejection)
+}
+
+func TestServerMaintenance(t *testing.T) {
+	memberIDs := []string{"a", "b", "c"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range memberIDs {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5763 + i,
+		}
+	}
+	electionTimeout := 500 * time.Millisecond
+	heartbeatInterval := 50 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+	}
+
+	servers := make([]*Server, 0, len(memberIDs))
+	for _, member := range memberIDs {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+		go server.Start()
+	}
+	maintained := awaitLeader(servers, 10*time.Second)
+	if !assert.NotNil(t, maintained) {
+		for _, server := range servers {
+			server.Stop()
+		}
+		return
+	}
+	defer maintained.Stop()
+
+	// Verify entering maintenance moves leadership to another member
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, maintained.EnterMaintenance(ctx))
+	others := make([]*Server, 0, 2)
+	for _, server := range servers {
+		if server != maintained {
+			others = append(others, server)
+		}
+	}
+	leader := awaitLeader(others, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		for _, server := range others {
+			server.Stop()
+		}
+		return
+	}
+	maintained.raft.ReadLock()
+	assert.Equal(t, raft.RoleFollower, maintained.raft.Role())
+	assert.True(t, maintained.raft.InMaintenance())
+	maintained.raft.ReadUnlock()
+
+	// Lose the new leader and verify the member in maintenance does not campaign while the other member is elected
+	var campaigned int32
+	maintained.raft.Watch(func(event raft.Event) {
+		if event.Type == raft.EventTypeRole && event.Role != raft.RoleFollower {
+			atomic.StoreInt32(&campaigned, 1)
+		}
+	})
+	assert.NoError(t, leader.Stop())
+	var survivor *Server
+	for _, server := range others {
+		if server != leader {
+			survivor = server
+		}
+	}
+	assert.NotNil(t, awaitLeader([]*Server{survivor}, 10*time.Second))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&campaigned))
+	maintained.raft.ReadLock()
+	assert.Nil(t, maintained.raft.ElectionFailure())
+	maintained.raft.ReadUnlock()
+
+	// Verify the member stands for election again once it exits maintenance and loses the leader
+	maintained.ExitMaintenance()
+	assert.NoError(t, survivor.Stop())
+	assert.True(t, awaitServers([]*Server{maintained}, 10*time.Second, func(server *Server) bool {
+		return server.raft.ElectionFailure() != nil
+	}))
+}
+
+func TestServerObserverRediscovery(t *testing.T) {
+	voterIDs := []string{"a", "b", "c"}
+	observerIDs := []string{"o"}
+	clusterConfig := cluster.Cluster{
+		Members: map[string]cluster.Member{},
+	}
+	for i, member := range append(voterIDs, observerIDs...) {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5766 + i,
+		}
+	}
+	electionTimeout := 500 * time.Millisecond
+	heartbeatInterval := 50 * time.Millisecond
+	protocolConfig := &config.ProtocolConfig{
+		ElectionTimeout:   &electionTimeout,
+		HeartbeatInterval: &heartbeatInterval,
+		Observers:         observerIDs,
+	}
+
+	var voters []*Server
+	var observer *Server
+	for _, member := range append(voterIDs, observerIDs...) {
+		clusterConfig.MemberID = member
+		server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+		assert.NoError(t, err)
+		if member == observerIDs[0] {
+			observer = server
+			defer server.Stop()
+		} else {
+			voters = append(voters, server)
+		}
+		go server.Start()
+	}
+
+	// Lose the leader and wait for a new leader to be elected among the remaining voters
+	lost := awaitLeader(voters, 10*time.Second)
+	if !assert.NotNil(t, lost) {
+		return
+	}
+	assert.NoError(t, lost.Stop())
+	survivors := make([]*Server, 0, 2)
+	for _, server := range voters {
+		if server != lost {
+			survivors = append(survivors, server)
+			defer server.Stop()
+		}
+	}
+	leader := awaitLeader(survivors, 10*time.Second)
+	if !assert.NotNil(t, leader) {
+		return
+	}
+	leader.raft.ReadLock()
+	leaderID := leader.raft.Member()
+	commitIndex := leader.raft.CommitIndex()
+	leader.raft.ReadUnlock()
+
+	// Verify the observer follows the new leader and resumes receiving entries promptly
+	assert.True(t, awaitServers([]*Server{observer}, 5*time.Second, func(server *Server) bool {
+		leader := server.raft.Leader()
+		return leader != nil && *leader == leaderID &&
+			server.raft.CommitIndex() >= commitIndex &&
+			server.store.Writer().LastIndex() >= commitIndex
+	}))
+}
+
+func TestServerUnknownMember(t *testing.T) {
+	clusterConfig := cluster.Cluster{
+		MemberID: "fo",
+		Members:  map[string]cluster.Member{},
+	}
+	for i, member := range []string{"foo", "bar", "baz"} {
+		clusterConfig.Members[member] = cluster.Member{
+			ID:           member,
+			Host:         "localhost",
+			ProtocolPort: 5770 + i,
+		}
+	}
+
+	// Verify a server cannot be created for a member missing from the cluster configuration
+	server, err := NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{})
+	assert.Nil(t, server)
+	assert.EqualError(t, err, "local member fo is not a member of the cluster [bar baz foo]")
+
+	clusterConfig.MemberID = ""
+	_, err = NewServer(clusterConfig, registry.Registry, &config.ProtocolConfig{})
+	assert.EqualError(t, err, "no local member configured")
+}
+
+func TestServerSnapshotOnStop(t *testing.T) {
+	root, err := ioutil.TempDir("", "raft-snapshot-on-stop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	clusterConfig := cluster.Cluster{
+		MemberID: "foo",
+		Members: map[string]cluster.Member{
+			"foo": {
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5773,
+			},
+		},
+	}
+	protocolConfig := &config.ProtocolConfig{
+		ClusterId: "test",
+		Storage: &config.StorageConfig{
+			DataDir: filepath.Join(root, "foo"),
+		},
+		Compaction: &config.CompactionConfig{
+			SnapshotOnStop: true,
+		},
+	}
+
+	// Start a node and stop it once it has been elected and committed its initial entries
+	server, err := NewServer(clusterConfig, registry.Registry, protocolConfig)
+	assert.NoError(t, err)
+	startBackupTestServer(t, server)
+	assert.Nil(t, server.snapshots.CurrentSnapshot())
+	server.raft.ReadLock()
+	commitIndex := server.raft.CommitIndex()
+	server.raft.ReadUnlock()
+	assert.True(t, commitIndex > 0)
+	assert.NoError(t, server.Stop())
+
+	// Verify the node restarts from a snapshot taken at the commit index when it was stopped
+	server, err = NewServer(clusterConfig, registry.Registry, protocolConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, server.open())
+	current := server.snapshots.CurrentSnapshot()
+	if !assert.NotNil(t, current) {
+		return
+	}
+	assert.Equal(t, commitIndex, current.Index())
+	assert.Equal(t, commitIndex, server.store.Writer().LastIndex())
+	startBackupTestServer(t, server)
+	defer server.Stop()
+	server.raft.ReadLock()
+	assert.True(t, server.raft.CommitIndex() > commitIndex)
+	server.raft.ReadUnlock()
+}
+
+// testStateMachine is a state machine that stores the last command value
+type testStateMachine struct {
+	value string
+	mu    sync.RWMutex
+}
+
+func (s *testStateMachine) Snapshot(writer io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, err := writer.Write([]byte(s.value))
+	return err
+}
+
+func (s *testStateMachine) Install(reader io.Reader) error {
+	bytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	if stream != nil {
+		stream.Value(bytes)
+		stream.Close()
+	}
+}
+
+func (s *testStateMachine) Query(bytes []byte, stream streams.WriteStream) {
+	if stream != nil {
+		s.mu.RLock()
+		stream.Value([]byte(s.value))
+		s.mu.RUnlock()
+		stream.Close()
+	}
+}
+
+func (s *testStateMachine) CanDelete(index uint64) bool {
+	return true
+}
+
+// testMetadataStateMachine is a state machine that attaches its current value as metadata to its snapshots
+type testMetadataStateMachine struct {
+	*testStateMachine
+}
+
+func (s *testMetadataStateMachine) SnapshotMetadata() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return []byte("value=" + s.value)
+}
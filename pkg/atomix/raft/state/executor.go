@@ -0,0 +1,127 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"github.com/atomix/go-framework/pkg/atomix/node"
+	"github.com/atomix/go-framework/pkg/atomix/service"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// KeyedStateMachine is implemented by state machines that can apply commands for independent keys concurrently
+// CommandKey is called on the apply goroutine and returns the key of the given command along with a bool indicating
+// whether the command is keyed. Commands with the same key are applied in log order, while commands with different
+// keys may be applied concurrently when the configured apply parallelism is greater than 1. Commands that are not
+// keyed, and all other entries, are applied only once all preceding commands have been applied.
+// Keyed commands are applied by KeyedCommand on separate goroutines with a context carrying the index and time of
+// the command, so they must use the given context rather than the state machine context and must not share mutable
+// state across keys.
+type KeyedStateMachine interface {
+	CommandKey(bytes []byte) (string, bool)
+	KeyedCommand(context node.Context, bytes []byte, stream streams.WriteStream)
+}
+
+// commandContext is the context of a single keyed command
+type commandContext struct {
+	node      string
+	index     uint64
+	timestamp time.Time
+}
+
+func (c *commandContext) Node() string {
+	return c.node
+}
+
+func (c *commandContext) Index() uint64 {
+	return c.index
+}
+
+func (c *commandContext) Timestamp() time.Time {
+	return c.timestamp
+}
+
+func (c *commandContext) OperationType() service.OperationType {
+	return service.OpTypeCommand
+}
+
+// applyWorkerBufferSize is the number of commands that can be queued for each apply worker
+const applyWorkerBufferSize = 256
+
+// newApplyExecutor returns a new executor that applies commands on the given number of workers
+func newApplyExecutor(parallelism int, log util.Logger) *applyExecutor {
+	executor := &applyExecutor{
+		workers: make([]chan func(), parallelism),
+		log:     log,
+	}
+	for i := range executor.workers {
+		worker := make(chan func(), applyWorkerBufferSize)
+		executor.workers[i] = worker
+		go executor.run(worker)
+	}
+	return executor
+}
+
+// applyExecutor applies commands for independent keys concurrently while preserving the order of each key
+// Each key is assigned to a single worker, so commands with the same key are applied serially in the order
+// in which they were executed.
+type applyExecutor struct {
+	workers []chan func()
+	log     util.Logger
+	wg      sync.WaitGroup
+}
+
+// run applies the commands for a single worker
+func (e *applyExecutor) run(worker chan func()) {
+	for f := range worker {
+		e.apply(f)
+	}
+}
+
+// apply applies a single command, recovering from panics in the state machine
+func (e *applyExecutor) apply(f func()) {
+	defer e.wg.Done()
+	defer func() {
+		err := recover()
+		if err != nil {
+			e.log.Error("Recovered from panic %v", err)
+		}
+	}()
+	f()
+}
+
+// execute enqueues the given function to be applied after all preceding functions for the same key
+func (e *applyExecutor) execute(key string, f func()) {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	e.wg.Add(1)
+	e.workers[hash.Sum32()%uint32(len(e.workers))] <- f
+}
+
+// await waits for all enqueued functions to be applied
+func (e *applyExecutor) await() {
+	e.wg.Wait()
+}
+
+// stop stops the workers once all enqueued functions have been applied
+func (e *applyExecutor) stop() {
+	e.wg.Wait()
+	for _, worker := range e.workers {
+		close(worker)
+	}
+}
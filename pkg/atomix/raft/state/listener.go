@@ -0,0 +1,218 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"sync"
+)
+
+// EntryType is the type of an entry applied to the state machine
+type EntryType int
+
+const (
+	// EntryTypeInitialize is an entry committed by a new leader at the start of its term
+	EntryTypeInitialize EntryType = iota
+	// EntryTypeCommand is a command entry
+	EntryTypeCommand
+	// EntryTypeConfiguration is a cluster configuration entry
+	EntryTypeConfiguration
+	// EntryTypeBarrier is a barrier entry
+	EntryTypeBarrier
+)
+
+func (t EntryType) String() string {
+	switch t {
+	case EntryTypeInitialize:
+		return "initialize"
+	case EntryTypeCommand:
+		return "command"
+	case EntryTypeConfiguration:
+		return "configuration"
+	case EntryTypeBarrier:
+		return "barrier"
+	}
+	return "unknown"
+}
+
+// getEntryType returns the EntryType of the given log entry
+func getEntryType(entry *raft.LogEntry) (EntryType, bool) {
+	switch entry.Entry.(type) {
+	case *raft.LogEntry_Initialize:
+		return EntryTypeInitialize, true
+	case *raft.LogEntry_Command:
+		return EntryTypeCommand, true
+	case *raft.LogEntry_Configuration:
+		return EntryTypeConfiguration, true
+	case *raft.LogEntry_Barrier:
+		return EntryTypeBarrier, true
+	}
+	return 0, false
+}
+
+// ApplyEvent describes an entry applied to the state machine
+type ApplyEvent struct {
+	// Index is the index of the applied entry
+	Index raft.Index
+	// Type is the type of the applied entry
+	Type EntryType
+	// Output is the output written by the state machine while applying the entry
+	// Output written to a stream after the entry has been applied, e.g. session events, is not included.
+	Output []streams.Result
+}
+
+// ApplyListener is called with each entry applied to the state machine
+type ApplyListener func(ApplyEvent)
+
+// OverflowPolicy determines how an apply listener's events are handled when its buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowDrop drops events that do not fit in the listener's buffer and logs a warning
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock blocks the apply loop until the listener's buffer has room for the event
+	OverflowBlock
+)
+
+// applyListener delivers apply events to an ApplyListener on its own goroutine
+type applyListener struct {
+	listener ApplyListener
+	policy   OverflowPolicy
+	ch       chan ApplyEvent
+	stopped  chan struct{}
+	once     sync.Once
+	log      util.Logger
+	dropped  uint64
+}
+
+// newApplyListener returns a new applyListener with the given buffer size and overflow policy
+func newApplyListener(listener ApplyListener, bufferSize int, policy OverflowPolicy, log util.Logger) *applyListener {
+	l := &applyListener{
+		listener: listener,
+		policy:   policy,
+		ch:       make(chan ApplyEvent, bufferSize),
+		stopped:  make(chan struct{}),
+		log:      log,
+	}
+	go l.run()
+	return l
+}
+
+// run calls the listener with each buffered event until the listener is stopped
+func (l *applyListener) run() {
+	for {
+		select {
+		case event := <-l.ch:
+			l.listener(event)
+		case <-l.stopped:
+			return
+		}
+	}
+}
+
+// stop stops delivering events to the listener
+// Buffered events are discarded, and a blocked apply loop is released.
+func (l *applyListener) stop() {
+	l.once.Do(func() {
+		close(l.stopped)
+	})
+}
+
+// notify enqueues the given event according to the listener's overflow policy
+// notify is only called on the apply goroutine.
+func (l *applyListener) notify(event ApplyEvent) {
+	if l.policy == OverflowBlock {
+		select {
+		case l.ch <- event:
+		case <-l.stopped:
+		}
+		return
+	}
+
+	select {
+	case <-l.stopped:
+		return
+	default:
+	}
+	select {
+	case l.ch <- event:
+		if l.dropped > 0 {
+			l.log.Info("Apply listener recovered after dropping %d events", l.dropped)
+			l.dropped = 0
+		}
+	default:
+		if l.dropped == 0 {
+			l.log.Warn("Apply listener buffer is full; dropping event for entry %d", event.Index)
+		}
+		l.dropped++
+	}
+}
+
+// newCaptureStream returns a stream that records the output written to the given stream while an entry is applied
+func newCaptureStream(stream streams.WriteStream) *captureStream {
+	return &captureStream{
+		stream: stream,
+	}
+}
+
+// captureStream is a WriteStream that records output until the entry has been applied
+type captureStream struct {
+	stream   streams.WriteStream
+	output   []streams.Result
+	complete bool
+	mu       sync.Mutex
+}
+
+// finish stops capturing output and returns the output written to the stream
+func (s *captureStream) finish() []streams.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complete = true
+	return s.output
+}
+
+func (s *captureStream) Send(result streams.Result) {
+	s.mu.Lock()
+	if !s.complete {
+		s.output = append(s.output, result)
+	}
+	s.mu.Unlock()
+	if s.stream != nil {
+		s.stream.Send(result)
+	}
+}
+
+func (s *captureStream) Result(value interface{}, err error) {
+	s.Send(streams.Result{
+		Value: value,
+		Error: err,
+	})
+}
+
+func (s *captureStream) Value(value interface{}) {
+	s.Result(value, nil)
+}
+
+func (s *captureStream) Error(err error) {
+	s.Result(nil, err)
+}
+
+func (s *captureStream) Close() {
+	if s.stream != nil {
+		s.stream.Close()
+	}
+}
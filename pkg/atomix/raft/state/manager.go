@@ -15,29 +15,129 @@
 package state
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/atomix/go-framework/pkg/atomix/node"
 	"github.com/atomix/go-framework/pkg/atomix/service"
 	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"io"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // NewManager returns a new Raft state manager
-func NewManager(member raft.MemberID, store store.Store, registry *node.Registry) Manager {
+func NewManager(member raft.MemberID, store store.Store, registry *node.Registry, config *config.ProtocolConfig) Manager {
+	return NewManagerWithMetadata(member, store, registry, config, nil)
+}
+
+// NewManagerWithMetadata returns a new Raft state manager that persists the last applied index in the given metadata store
+// The last applied index is only persisted for state machines implementing PersistentStateMachine.
+func NewManagerWithMetadata(member raft.MemberID, store store.Store, registry *node.Registry, config *config.ProtocolConfig, metadata raft.MetadataStore) Manager {
+	return NewManagerWithStateMachine(member, store, config, metadata, func(context node.Context) node.StateMachine {
+		return node.NewPrimitiveStateMachine(registry, context)
+	})
+}
+
+// StateMachineFactory returns the state machine to which a state manager applies entries
+// The given context is the state manager, which exposes the index and timestamp of the entry being applied.
+type StateMachineFactory func(context node.Context) node.StateMachine
+
+// NewManagerWithStateMachine returns a new Raft state manager that applies entries to the state machine returned by
+// the given factory rather than the primitive state machine
+// The state machine may implement any of the optional interfaces in this package, e.g. OutputSerializer to serialize
+// its own outputs or SnapshotMetadataProvider to attach metadata to snapshots. The metadata store may be nil.
+func NewManagerWithStateMachine(member raft.MemberID, store store.Store, config *config.ProtocolConfig, metadata raft.MetadataStore, factory StateMachineFactory) Manager {
+	snapshotThreshold := raft.Index(config.GetSnapshotThresholdOrDefault())
 	sm := &manager{
-		member: member,
-		log:    util.NewNodeLogger(string(member)),
-		reader: store.Log().OpenReader(0),
-		ch:     make(chan *change, stateBufferSize),
+		member:            member,
+		log:               util.NewNodeLogger(string(member)),
+		store:             store,
+		reader:            store.Log().OpenReader(0),
+		ch:                make(chan *change, stateBufferSize),
+		closed:            make(chan struct{}),
+		snapshotThreshold: snapshotThreshold,
+		nextSnapshotIndex: snapshotThreshold,
+		asyncSnapshots:    config.GetCompaction().GetAsyncSnapshots(),
+		retainedEntries:   raft.Index(config.GetCompaction().GetRetainedEntries()),
+		retainedBytes:     int(config.GetCompaction().GetRetainedBytes()),
+		maxSnapshotSize:   int64(config.GetCompaction().GetMaxSnapshotSize()),
+		snapshotFailures:  metrics.NewCounter("raft_snapshot_failures_total", string(member)),
+		oversized:         metrics.NewCounter("raft_snapshots_oversized_total", string(member)),
+		restoreBufferSize: config.GetRestoreBufferSizeOrDefault(),
+		restoreBytes:      metrics.NewGauge("raft_snapshot_restore_bytes", string(member)),
+		applyRate:         metrics.NewRate("raft_apply_rate", string(member), config.GetRateWindowOrDefault()),
+		pinDuration:       metrics.NewHistogram("raft_read_pin_milliseconds", string(member), pinDurationBounds),
+	}
+	sm.state = factory(sm)
+
+	// If the state machine persists its own state, persist the last applied index to skip applied entries on restart.
+	if persistent, ok := sm.state.(PersistentStateMachine); ok && metadata != nil {
+		sm.persistent = persistent
+		sm.metadata = metadata
+		sm.lastAppliedSyncInterval = config.GetLastAppliedSyncIntervalOrDefault()
+	}
+
+	// If the state machine declares which commands are independent, apply independent commands concurrently.
+	if keyed, ok := sm.state.(KeyedStateMachine); ok && config.GetApplyParallelismOrDefault() > 1 {
+		sm.keyed = keyed
+		sm.executor = newApplyExecutor(config.GetApplyParallelismOrDefault(), sm.log)
+	}
+
+	// If the state machine serializes its own outputs, serialize output values before they're sent to clients.
+	if serializer, ok := sm.state.(OutputSerializer); ok {
+		sm.serializer = serializer
 	}
-	sm.state = node.NewPrimitiveStateMachine(registry, sm)
 	go sm.start()
 	return sm
 }
 
+// SnapshotCapturer is implemented by state machines that support asynchronous snapshots
+// CaptureSnapshot is called on the apply goroutine and must return a function that serializes a consistent
+// point-in-time view of the state machine. The returned function is called on a background goroutine while
+// entries continue to be applied, so it must not share mutable state with the state machine.
+type SnapshotCapturer interface {
+	CaptureSnapshot() (func(io.Writer) error, error)
+}
+
+// SnapshotMetadataProvider is implemented by state machines that attach metadata to their snapshots
+// SnapshotMetadata is called on the apply goroutine when a snapshot is taken. The returned metadata is opaque to the
+// protocol: it's stored with the snapshot, carried with the snapshot when it's installed on other members, and can be
+// read from the snapshot store without reading the snapshot. Applications provide a state machine implementing
+// SnapshotMetadataProvider through NewManagerWithStateMachine or the server's NewServerWithStateMachine.
+type SnapshotMetadataProvider interface {
+	SnapshotMetadata() []byte
+}
+
+// PersistentStateMachine is implemented by state machines that durably persist their own state
+// The index of the last entry applied to a persistent state machine is periodically persisted once the state machine
+// has been synced. On restart, entries up to the persisted index are skipped rather than installing the snapshot and
+// applying the entries following it.
+type PersistentStateMachine interface {
+	// Sync blocks until the changes applied to the state machine are durable
+	Sync() error
+}
+
+// AppliedStream is implemented by streams that are notified once the entry writing to them has been applied
+// Applied is called once the entry has been applied whether or not it produced output, and whether or not the
+// stream remains open to stream further output.
+type AppliedStream interface {
+	streams.WriteStream
+
+	// Applied is called once the entry writing to the stream has been applied
+	Applied()
+}
+
 // Manager provides a state machine to which to apply Raft log entries
 type Manager interface {
 	// ApplyIndex reads and applies the given index to the state machine
@@ -46,6 +146,41 @@ type Manager interface {
 	// Apply applies a committed entry to the state machine
 	ApplyEntry(entry *log.Entry, stream streams.WriteStream)
 
+	// WatchConfiguration registers a function to be called with each configuration entry that is applied
+	// Watchers are called on the apply goroutine in log order and must not block on the Raft state lock.
+	WatchConfiguration(watcher func(raft.Index, *raft.ConfigurationEntry))
+
+	// AddApplyListener registers a listener to be called with each entry applied to the state machine
+	// Listeners are called in log order on a separate goroutine. Events are buffered up to the given buffer size,
+	// and the policy determines whether events are dropped or the apply loop blocks once the buffer is full.
+	// The returned function removes the listener. Listeners are removed when the manager is closed.
+	AddApplyListener(listener ApplyListener, bufferSize int, policy OverflowPolicy) func()
+
+	// WaitApplied applies entries up to the given index and returns a channel that is closed once they've been applied
+	WaitApplied(index raft.Index) <-chan struct{}
+
+	// AwaitIndex blocks until entries up to the given index have been applied or the context is done
+	// Unlike WaitApplied, AwaitIndex doesn't apply any entries. It waits for entries up to the index to be committed
+	// and applied in the normal course, so it's safe to call with an index that has not yet been committed.
+	AwaitIndex(ctx context.Context, index raft.Index) error
+
+	// Snapshot applies entries up to the given index and takes a snapshot of the state machine
+	// Snapshot blocks until the snapshot has been written or the context is done. No snapshot is taken if the context
+	// is done before the snapshot is started, or if the current snapshot is already at the last applied index.
+	Snapshot(ctx context.Context, index raft.Index) error
+
+	// PinRead pins the state machine at the last applied index until the pin is released or the timeout expires
+	PinRead(timeout time.Duration) ReadPin
+
+	// Fault returns the error with which the state machine faulted, or nil if it has not faulted
+	// The state machine faults if it panics while applying a committed entry; a query that panics fails only its own
+	// request. Once faulted, no further entries are applied and changes are failed with raft.ErrFaulted.
+	Fault() error
+
+	// WatchFault registers a function to be called once if the state machine faults
+	// Watchers are called on the goroutine that was applying the faulted entry and must not block.
+	WatchFault(watcher func(error))
+
 	// Close closes the state manager
 	Close() error
 }
@@ -57,15 +192,53 @@ const (
 
 // manager manages the Raft state machine
 type manager struct {
-	member       raft.MemberID
-	state        node.StateMachine
-	log          util.Logger
-	currentIndex raft.Index
-	currentTime  time.Time
-	lastApplied  raft.Index
-	reader       log.Reader
-	operation    service.OperationType
-	ch           chan *change
+	member                  raft.MemberID
+	state                   node.StateMachine
+	log                     util.Logger
+	store                   store.Store
+	currentIndex            raft.Index
+	currentTime             time.Time
+	lastApplied             raft.Index
+	lastDispatched          raft.Index
+	reader                  log.Reader
+	operation               service.OperationType
+	ch                      chan *change
+	closed                  chan struct{}
+	closeMu                 sync.RWMutex
+	snapshotThreshold       raft.Index
+	nextSnapshotIndex       raft.Index
+	asyncSnapshots          bool
+	retainedEntries         raft.Index
+	retainedBytes           int
+	appliedSizes            []int
+	appliedSizesIndex       raft.Index
+	snapshotDone            chan struct{}
+	waiters                 []*indexWaiter
+	maxSnapshotSize         int64
+	snapshotFailures        *metrics.Counter
+	oversized               *metrics.Counter
+	applyRate               *metrics.Rate
+	restoreBufferSize       int
+	restoreBytes            *metrics.Gauge
+	persistent              PersistentStateMachine
+	metadata                raft.MetadataStore
+	resumed                 bool
+	lastAppliedSyncInterval time.Duration
+	lastAppliedSyncTime     time.Time
+	lastAppliedSynced       raft.Index
+	pinned                  *readPin
+	pinDuration             *metrics.Histogram
+	deferred                []*change
+	configWatchers          []func(raft.Index, *raft.ConfigurationEntry)
+	applyListeners          []*applyListener
+	keyed                   KeyedStateMachine
+	serializer              OutputSerializer
+	executor                *applyExecutor
+	applying                raft.Index
+	fault                   atomic.Value
+	faultOnce               sync.Once
+	faultWatchers           []func(error)
+	watchersMu              sync.RWMutex
 }
 
 // Node returns the local node identifier
@@ -75,21 +248,173 @@ func (m *manager) Node() string {
 
 // applyIndex applies entries up to the given index
 func (m *manager) ApplyIndex(index raft.Index) {
-	m.ch <- &change{
+	_ = m.enqueue(context.Background(), &change{
 		entry: &log.Entry{
 			Index: index,
 		},
+		stream: streams.NewNilStream(),
+	})
+}
+
+func (m *manager) AwaitIndex(ctx context.Context, index raft.Index) error {
+	waiter := &indexWaiter{
+		index: index,
+		ch:    make(chan struct{}),
+		done:  ctx.Done(),
+	}
+	if err := m.enqueue(ctx, &change{waiter: waiter}); err != nil {
+		return err
+	}
+	select {
+	case <-waiter.ch:
+		return nil
+	case <-m.closed:
+		return raft.ErrShuttingDown
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
 // ApplyEntry enqueues the given entry to be applied to the state machine, returning output on the given channel
 func (m *manager) ApplyEntry(entry *log.Entry, stream streams.WriteStream) {
-	m.ch <- &change{
+	_ = m.enqueue(context.Background(), &change{
 		entry:  entry,
 		stream: stream,
+	})
+}
+
+func (m *manager) WaitApplied(index raft.Index) <-chan struct{} {
+	ch := make(chan struct{})
+	_ = m.enqueue(context.Background(), &change{
+		entry: &log.Entry{
+			Index: index,
+		},
+		stream:  streams.NewNilStream(),
+		applied: ch,
+	})
+	return ch
+}
+
+func (m *manager) Snapshot(ctx context.Context, index raft.Index) error {
+	ch := make(chan error, 1)
+	err := m.enqueue(ctx, &change{
+		entry: &log.Entry{
+			Index: index,
+		},
+		stream:   streams.NewNilStream(),
+		snapshot: ch,
+		ctx:      ctx,
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *manager) WatchConfiguration(watcher func(raft.Index, *raft.ConfigurationEntry)) {
+	m.watchersMu.Lock()
+	m.configWatchers = append(m.configWatchers, watcher)
+	m.watchersMu.Unlock()
+}
+
+func (m *manager) AddApplyListener(listener ApplyListener, bufferSize int, policy OverflowPolicy) func() {
+	l := newApplyListener(listener, bufferSize, policy, m.log)
+	m.watchersMu.Lock()
+	m.applyListeners = append(m.applyListeners, l)
+	m.watchersMu.Unlock()
+	return func() {
+		m.removeApplyListener(l)
+	}
+}
+
+// removeApplyListener removes and stops the given listener
+// The list of listeners is replaced rather than modified, since it's read by the apply goroutine without the lock.
+func (m *manager) removeApplyListener(l *applyListener) {
+	m.watchersMu.Lock()
+	listeners := make([]*applyListener, 0, len(m.applyListeners))
+	for _, listener := range m.applyListeners {
+		if listener != l {
+			listeners = append(listeners, listener)
+		}
+	}
+	m.applyListeners = listeners
+	m.watchersMu.Unlock()
+	l.stop()
+}
+
+func (m *manager) Fault() error {
+	if err, ok := m.fault.Load().(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *manager) WatchFault(watcher func(error)) {
+	m.watchersMu.Lock()
+	m.faultWatchers = append(m.faultWatchers, watcher)
+	m.watchersMu.Unlock()
+}
+
+// recoverFault recovers from a panic in the state machine, faulting the state machine at the given index
+// recoverFault must be deferred by the function applying the entry. The entry's stream is failed so the request
+// waiting for its output is completed.
+func (m *manager) recoverFault(index raft.Index, stream streams.WriteStream) {
+	if err := recover(); err != nil {
+		m.setFault(index, err, debug.Stack())
+		m.failPanicked(stream, raft.ErrFaulted)
 	}
 }
 
+// recoverQuery recovers from a panic in the state machine while applying a query, failing only the query's stream
+// Queries don't change the state machine, so a panicking query can't cause replicas to diverge and doesn't fault
+// the state machine.
+func (m *manager) recoverQuery(index raft.Index, stream streams.WriteStream) {
+	if value := recover(); value != nil {
+		err := fmt.Errorf("state machine panicked applying query at index %d: %v", index, value)
+		m.log.Error("%v\n%s", err, debug.Stack())
+		m.failPanicked(stream, err)
+	}
+}
+
+// failPanicked fails the stream of a change whose state machine panicked with the given error
+// The state machine may have closed the stream before panicking, in which case the stream can't be failed.
+func (m *manager) failPanicked(stream streams.WriteStream, err error) {
+	defer func() {
+		_ = recover()
+	}()
+	if stream != nil {
+		stream.Error(err)
+		stream.Close()
+	}
+}
+
+// setFault faults the state machine with the panic raised applying the entry at the given index
+// Applying further entries to a state machine that panicked could cause replicas to diverge, so the fault is
+// permanent and reported to fault watchers.
+func (m *manager) setFault(index raft.Index, value interface{}, stack []byte) {
+	err := fmt.Errorf("state machine panicked applying entry %d: %v", index, value)
+	m.log.Error("%v\n%s", err, stack)
+	m.raiseFault(err)
+}
+
+// raiseFault permanently faults the state machine with the given error and notifies fault watchers
+func (m *manager) raiseFault(err error) {
+	m.faultOnce.Do(func() {
+		m.fault.Store(err)
+		m.watchersMu.RLock()
+		watchers := m.faultWatchers
+		m.watchersMu.RUnlock()
+		for _, watcher := range watchers {
+			watcher(err)
+		}
+	})
+}
+
 func (m *manager) updateClock(index raft.Index, timestamp time.Time) {
 	m.currentIndex = index
 	if timestamp.UnixNano() > m.currentTime.UnixNano() {
@@ -97,9 +422,35 @@ func (m *manager) updateClock(index raft.Index, timestamp time.Time) {
 	}
 }
 
-// start begins applying entries to the state machine
+// enqueue sends the given change to the apply loop
+// Once the manager is closed the apply loop is no longer running, so the change is failed rather than enqueued.
+func (m *manager) enqueue(ctx context.Context, change *change) error {
+	m.closeMu.RLock()
+	defer m.closeMu.RUnlock()
+	select {
+	case <-m.closed:
+		m.failClosed(change)
+		return raft.ErrShuttingDown
+	default:
+	}
+	select {
+	case m.ch <- change:
+		return nil
+	case <-m.closed:
+		m.failClosed(change)
+		return raft.ErrShuttingDown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// start begins applying entries to the state machine until the manager is closed
 func (m *manager) start() {
 	for change := range m.ch {
+		if change.stop != nil {
+			m.stop(change.stop)
+			return
+		}
 		m.execChange(change)
 	}
 }
@@ -107,63 +458,266 @@ func (m *manager) start() {
 // execChange executes the given change on the state machine
 func (m *manager) execChange(change *change) {
 	defer func() {
-		err := recover()
-		if err != nil {
-			m.log.Error("Recovered from panic %v", err)
+		if err := recover(); err != nil {
+			m.setFault(m.applying, err, debug.Stack())
+			m.failPanicked(change.stream, raft.ErrFaulted)
 		}
 	}()
+	if change.waiter != nil {
+		m.waiters = append(m.waiters, change.waiter)
+		m.notifyWaiters()
+		return
+	}
+	defer m.notifyWaiters()
+	m.resume()
+
+	// While the state machine is pinned for reads, defer all changes not applied through the pin
+	if m.pinned != nil && (change.pin == nil || change.pinOp == pinAcquire) {
+		m.deferChange(change)
+		return
+	}
+	if stream, ok := change.stream.(AppliedStream); ok {
+		defer stream.Applied()
+	}
+	if change.applied != nil {
+		defer func() {
+			m.awaitCommands()
+			close(change.applied)
+		}()
+	}
+	if change.snapshot != nil {
+		defer func() {
+			change.snapshot <- m.takeSnapshot(change.ctx)
+		}()
+	}
+	if change.pin != nil {
+		m.execPinChange(change)
+		return
+	}
+
+	// Once the state machine has faulted, fail changes rather than applying them to the inconsistent state
+	if err := m.Fault(); err != nil {
+		m.failChange(change.stream)
+		return
+	}
+
 	if change.entry.Entry != nil {
 		// If the entry is a query, apply it without incrementing the lastApplied index
 		if query, ok := change.entry.Entry.Entry.(*raft.LogEntry_Query); ok {
 			m.execQuery(change.entry.Index, change.entry.Entry.Timestamp, query.Query, change.stream)
-		} else {
-			m.execPendingChanges(change.entry.Index - 1)
+		} else if !m.installSnapshot(change.entry.Index, change.stream) {
+			if !m.execPendingChanges(change.entry.Index - 1) {
+				m.failChange(change.stream)
+				return
+			}
 			m.execEntry(change.entry, change.stream)
-			m.lastApplied = change.entry.Index
+			m.setDispatched(change.entry.Index)
+			m.applyRate.Mark(1)
+			m.maybeSnapshot()
+		}
+	} else if change.entry.Index > m.lastDispatched && !m.installSnapshot(change.entry.Index, change.stream) {
+		if !m.execPendingChanges(change.entry.Index-1) || !m.execEntry(change.entry, change.stream) {
+			m.failChange(change.stream)
+			return
 		}
-	} else if change.entry.Index > m.lastApplied {
-		m.execPendingChanges(change.entry.Index - 1)
-		m.execEntry(change.entry, change.stream)
-		m.lastApplied = change.entry.Index
+		m.setDispatched(change.entry.Index)
+		m.applyRate.Mark(1)
+		m.maybeSnapshot()
+	}
+	m.maybeSyncLastApplied()
+}
+
+// resume skips the entries already reflected by a persistent state machine when the first change is applied
+// The metadata store is opened after the manager is created, so the persisted index is loaded lazily.
+func (m *manager) resume() {
+	if m.resumed {
+		return
+	}
+	m.resumed = true
+	if m.persistent == nil {
+		return
+	}
+	index := m.metadata.LoadLastApplied()
+	if index == nil || *index <= m.lastApplied {
+		return
+	}
+	m.log.Info("Resuming from persisted last applied index %d", *index)
+	m.lastApplied = *index
+	m.lastDispatched = *index
+	m.lastAppliedSynced = *index
+	m.nextSnapshotIndex = m.lastApplied + m.snapshotThreshold
+	m.reader.Reset(m.lastApplied + 1)
+}
+
+// maybeSyncLastApplied persists the last applied index of a persistent state machine if the sync interval has passed
+// The state machine is synced before the index is persisted to ensure the persisted index never runs ahead of
+// the durable state of the state machine.
+func (m *manager) maybeSyncLastApplied() {
+	if m.persistent == nil || m.lastDispatched == m.lastAppliedSynced || time.Since(m.lastAppliedSyncTime) < m.lastAppliedSyncInterval {
+		return
+	}
+	m.awaitCommands()
+	if err := m.persistent.Sync(); err != nil {
+		m.log.Warn("Failed to sync state machine at index %d: %v", m.lastApplied, err)
+		return
+	}
+	m.metadata.StoreLastApplied(m.lastApplied)
+	if err := m.metadata.Sync(); err != nil {
+		m.log.Warn("Failed to persist last applied index %d: %v", m.lastApplied, err)
+		return
+	}
+	m.lastAppliedSynced = m.lastApplied
+	m.lastAppliedSyncTime = time.Now()
+}
+
+// installSnapshot restores the state machine from the current snapshot if the snapshot is ahead of the state machine
+// Snapshots replace the state machine's state, including the session state used to deduplicate retried commands,
+// so entries covered by an installed snapshot are not applied. Returns a bool indicating whether the entry at the
+// given index is covered by the snapshot, in which case the given stream is closed. If the snapshot can't be
+// installed, the state machine is faulted and the stream is failed.
+func (m *manager) installSnapshot(index raft.Index, stream streams.WriteStream) bool {
+	current := m.store.Snapshot().CurrentSnapshot()
+	if current == nil || current.Index() <= m.lastDispatched {
+		return false
+	}
+
+	m.awaitCommands()
+	m.log.Debug("Installing snapshot %d", current.Index())
+	startTime := time.Now()
+	m.restoreBytes.Set(0)
+	reader := snapshot.NewRestoreReader(current, m.restoreBufferSize, m.restoreBytes.Set)
+	defer reader.Close()
+	if err := m.state.Install(reader); err != nil {
+		m.log.Error("Failed to install snapshot %d after reading %d bytes: %v", current.Index(), reader.BytesRead(), err)
+		m.raiseFault(fmt.Errorf("failed to install snapshot %d: %v", current.Index(), err))
+		m.failChange(stream)
+		return true
+	}
+	m.log.Debug("Installed snapshot %d (%d bytes) in %s", current.Index(), reader.BytesRead(), time.Since(startTime))
+	m.updateClock(current.Index(), current.Timestamp())
+	m.lastApplied = current.Index()
+	m.lastDispatched = current.Index()
+	m.nextSnapshotIndex = m.lastApplied + m.snapshotThreshold
+	m.reader.Reset(m.lastApplied + 1)
+
+	if index > m.lastApplied {
+		return false
+	}
+	if stream != nil {
+		stream.Close()
+	}
+	return true
+}
+
+// failChange fails the change with the given stream because the state machine has faulted
+func (m *manager) failChange(stream streams.WriteStream) {
+	if stream != nil {
+		stream.Error(raft.ErrFaulted)
+		stream.Close()
 	}
 }
 
 // execPendingChanges reads and executes changes up to the given index
-func (m *manager) execPendingChanges(index raft.Index) {
-	if m.lastApplied < index {
-		for m.lastApplied < index {
+// It returns false if a committed entry could not be read from the log.
+func (m *manager) execPendingChanges(index raft.Index) bool {
+	if m.lastDispatched < index {
+		for m.lastDispatched < index {
 			entry := m.reader.NextEntry()
-			if entry != nil {
-				m.execEntry(entry, nil)
-				m.lastApplied = entry.Index
+			if entry != nil && entry.Index <= m.lastDispatched {
+				// Skip entries covered by an installed snapshot
+				continue
+			} else if entry != nil {
+				m.execEntry(entry, streams.NewNilStream())
+				m.setDispatched(entry.Index)
+				m.applyRate.Mark(1)
 			} else {
-				return
+				return m.checkReader()
 			}
 		}
 	}
+	return true
+}
+
+// checkReader faults the state machine if the log reader failed to read a committed entry
+// A corrupt committed entry can't be recovered by truncating the log, and skipping it would cause the state machine
+// to diverge from other replicas, so the state machine is faulted rather than applying further entries.
+func (m *manager) checkReader() bool {
+	if err := m.reader.Err(); err != nil {
+		m.log.Error("Failed to read committed entry", err)
+		m.raiseFault(err)
+		return false
+	}
+	return true
 }
 
 // execEntry applies the given entry to the state machine and returns the result(s) on the given channel
-func (m *manager) execEntry(entry *log.Entry, stream streams.WriteStream) {
+// It returns false if the entry could not be read from the log.
+func (m *manager) execEntry(entry *log.Entry, stream streams.WriteStream) bool {
 	if entry.Entry == nil {
-		m.reader.Reset(entry.Index)
-		entry = m.reader.NextEntry()
+		index := entry.Index
+		m.reader.Reset(index)
+		if entry = m.reader.NextEntry(); entry == nil {
+			if m.checkReader() {
+				m.raiseFault(fmt.Errorf("entry %d is not in the log", index))
+			}
+			return false
+		}
+	}
+	m.applying = entry.Index
+	m.recordSize(entry)
+
+	// If any apply listeners are registered, capture the entry's output to notify the listeners once it's applied
+	m.watchersMu.RLock()
+	listeners := m.applyListeners
+	m.watchersMu.RUnlock()
+	var capture *captureStream
+	entryType, notify := getEntryType(entry.Entry)
+	if notify && len(listeners) > 0 {
+		capture = newCaptureStream(stream)
+		stream = capture
 	}
 
+	// Apply listeners observe the output of each entry in log order, so commands are only applied
+	// concurrently if no output is being captured.
 	switch e := entry.Entry.Entry.(type) {
 	case *raft.LogEntry_Query:
 		m.execQuery(entry.Index, entry.Entry.Timestamp, e.Query, stream)
 	case *raft.LogEntry_Command:
 		m.log.Trace("Applying command %d", entry.Index)
-		m.execCommand(entry.Index, entry.Entry.Timestamp, e.Command, stream)
+		m.execCommand(entry.Index, entry.Entry.Timestamp, e.Command, stream, capture == nil)
 	case *raft.LogEntry_Configuration:
 		m.execConfig(entry.Index, entry.Entry.Timestamp, e.Configuration, stream)
 	case *raft.LogEntry_Initialize:
 		m.execInit(entry.Index, entry.Entry.Timestamp, e.Initialize, stream)
+	case *raft.LogEntry_Barrier:
+		m.execBarrier(entry.Index, entry.Entry.Timestamp, e.Barrier, stream)
+	}
+
+	if capture != nil {
+		event := ApplyEvent{
+			Index:  entry.Index,
+			Type:   entryType,
+			Output: capture.finish(),
+		}
+		for _, listener := range listeners {
+			listener.notify(event)
+		}
 	}
+	return true
 }
 
 func (m *manager) execInit(index raft.Index, timestamp time.Time, init *raft.InitializeEntry, stream streams.WriteStream) {
+	m.awaitCommands()
+	m.updateClock(index, timestamp)
+	if stream != nil {
+		stream.Value(nil)
+		stream.Close()
+	}
+}
+
+// execBarrier completes a barrier entry; all preceding entries have been applied once it's reached
+func (m *manager) execBarrier(index raft.Index, timestamp time.Time, barrier *raft.BarrierEntry, stream streams.WriteStream) {
+	m.awaitCommands()
 	m.updateClock(index, timestamp)
 	if stream != nil {
 		stream.Value(nil)
@@ -172,7 +726,14 @@ func (m *manager) execInit(index raft.Index, timestamp time.Time, init *raft.Ini
 }
 
 func (m *manager) execConfig(index raft.Index, timestamp time.Time, config *raft.ConfigurationEntry, stream streams.WriteStream) {
+	m.awaitCommands()
 	m.updateClock(index, timestamp)
+	m.watchersMu.RLock()
+	watchers := m.configWatchers
+	m.watchersMu.RUnlock()
+	for _, watcher := range watchers {
+		watcher(index, config)
+	}
 	if stream != nil {
 		stream.Value(nil)
 		stream.Close()
@@ -181,19 +742,386 @@ func (m *manager) execConfig(index raft.Index, timestamp time.Time, config *raft
 
 func (m *manager) execQuery(index raft.Index, timestamp time.Time, query *raft.QueryEntry, stream streams.WriteStream) {
 	m.log.Trace("Applying query %d", index)
+	stream = m.serializeOutput(stream)
+	m.awaitCommands()
+	if err := m.Fault(); err != nil {
+		m.failChange(stream)
+		return
+	}
+	m.applying = index
 	m.operation = service.OpTypeQuery
+	defer m.recoverQuery(index, stream)
 	m.state.Query(query.Value, stream)
 }
 
-func (m *manager) execCommand(index raft.Index, timestamp time.Time, command *raft.CommandEntry, stream streams.WriteStream) {
+// execCommand applies a command to the state machine
+// If parallel is true and the state machine declares a key for the command, the command is applied concurrently
+// with commands for other keys. Otherwise, the command is applied once all preceding commands have been applied.
+func (m *manager) execCommand(index raft.Index, timestamp time.Time, command *raft.CommandEntry, stream streams.WriteStream, parallel bool) {
 	m.updateClock(index, timestamp)
 	m.operation = service.OpTypeCommand
+	stream = m.serializeOutput(stream)
+	if parallel && m.executor != nil {
+		if key, ok := m.keyed.CommandKey(command.Value); ok {
+			context := &commandContext{
+				node:      string(m.member),
+				index:     uint64(index),
+				timestamp: m.currentTime,
+			}
+			m.executor.execute(key, func() {
+				defer m.recoverFault(index, stream)
+				if m.Fault() != nil {
+					m.failChange(stream)
+					return
+				}
+				m.keyed.KeyedCommand(context, command.Value, stream)
+			})
+			return
+		}
+	}
+	m.awaitCommands()
 	m.state.Command(command.Value, stream)
 }
 
+// serializeOutput returns a stream that serializes the output written to the given stream if the state machine
+// serializes its own outputs
+func (m *manager) serializeOutput(stream streams.WriteStream) streams.WriteStream {
+	if m.serializer == nil || stream == nil {
+		return stream
+	}
+	return newSerializingStream(stream, m.serializer)
+}
+
+// stop waits for commands being applied concurrently and any asynchronous snapshot to complete and stops the executor
+// Waiting for the snapshot ensures it isn't written to the store after the store is closed.
+func (m *manager) stop(stopped chan struct{}) {
+	if m.executor != nil {
+		m.awaitCommands()
+		m.executor.stop()
+		m.executor = nil
+	}
+	m.awaitSnapshot()
+	m.failPending()
+	close(stopped)
+}
+
+// failPending closes the manager and fails the changes that will never be applied
+// Closing the manager releases senders blocked on a full change channel. Once they've returned, no further changes
+// can be enqueued, so the changes deferred by a read pin and those remaining in the channel are the last to fail.
+func (m *manager) failPending() {
+	close(m.closed)
+	m.closeMu.Lock()
+	m.closeMu.Unlock()
+
+	deferred := m.deferred
+	m.pinned = nil
+	m.deferred = nil
+	for _, change := range deferred {
+		m.failClosed(change)
+	}
+	for {
+		select {
+		case change := <-m.ch:
+			m.failClosed(change)
+		default:
+			return
+		}
+	}
+}
+
+// failClosed fails a change that can't be applied because the manager is closed
+func (m *manager) failClosed(change *change) {
+	if change.stream != nil {
+		change.stream.Error(raft.ErrShuttingDown)
+		change.stream.Close()
+		if stream, ok := change.stream.(AppliedStream); ok {
+			stream.Applied()
+		}
+	}
+	if change.applied != nil {
+		close(change.applied)
+	}
+	if change.snapshot != nil {
+		change.snapshot <- raft.ErrShuttingDown
+	}
+	if change.pin != nil && change.pinOp == pinAcquire {
+		close(change.pin.ready)
+	}
+	if change.stop != nil {
+		close(change.stop)
+	}
+}
+
+// notifyWaiters releases the callers of AwaitIndex whose index has been reached and drops those that gave up
+// Commands applied concurrently are visible to subsequent queries once dispatched, since queries await them.
+func (m *manager) notifyWaiters() {
+	if len(m.waiters) == 0 {
+		return
+	}
+	waiters := m.waiters[:0]
+	for _, waiter := range m.waiters {
+		if waiter.index <= m.lastDispatched {
+			close(waiter.ch)
+			continue
+		}
+		select {
+		case <-waiter.done:
+			continue
+		default:
+		}
+		waiters = append(waiters, waiter)
+	}
+	m.waiters = waiters
+}
+
+// setDispatched records the given index as dispatched to the state machine
+// Commands may still be being applied concurrently once dispatched, so the last applied index is only advanced
+// once they complete.
+func (m *manager) setDispatched(index raft.Index) {
+	m.lastDispatched = index
+	if m.executor == nil {
+		m.lastApplied = index
+	}
+}
+
+// awaitCommands waits for commands being applied concurrently to complete
+func (m *manager) awaitCommands() {
+	if m.executor != nil {
+		m.executor.await()
+	}
+	m.lastApplied = m.lastDispatched
+}
+
+// awaitSnapshot waits for the asynchronous snapshot being written, if any, to complete
+func (m *manager) awaitSnapshot() {
+	if m.snapshotDone != nil {
+		<-m.snapshotDone
+	}
+}
+
+// maybeSnapshot takes a snapshot of the state machine and compacts the log once the snapshot threshold is reached
+func (m *manager) maybeSnapshot() {
+	if m.snapshotThreshold == 0 || m.lastDispatched < m.nextSnapshotIndex {
+		return
+	}
+
+	// If an asynchronous snapshot is still being written, try again after the next entry is applied.
+	if m.snapshotDone != nil {
+		select {
+		case <-m.snapshotDone:
+		default:
+			return
+		}
+	}
+
+	m.awaitCommands()
+	m.nextSnapshotIndex = m.lastApplied + m.snapshotThreshold
+	index := m.lastApplied
+	timestamp := m.currentTime
+	metadata := m.snapshotMetadata()
+	compactIndex := m.compactIndex(index)
+	m.trimSizes(compactIndex)
+
+	// If the state machine supports capturing its state, serialize the snapshot in the background
+	// while entries continue to be applied. Otherwise, fall back to a synchronous snapshot.
+	if capturer, ok := m.state.(SnapshotCapturer); ok && m.asyncSnapshots {
+		serialize, err := capturer.CaptureSnapshot()
+		if err != nil {
+			m.snapshotFailed(index, err)
+			return
+		}
+		done := make(chan struct{})
+		m.snapshotDone = done
+		go func() {
+			defer close(done)
+			_ = m.snapshot(index, compactIndex, timestamp, metadata, serialize)
+		}()
+	} else {
+		_ = m.snapshot(index, compactIndex, timestamp, metadata, m.state.Snapshot)
+	}
+}
+
+// takeSnapshot synchronously takes a snapshot at the last applied index, regardless of the snapshot threshold
+// Any asynchronous snapshot still being written is allowed to complete first. If the context is done by then, e.g.
+// because the caller timed out while the change was queued, no snapshot is taken.
+func (m *manager) takeSnapshot(ctx context.Context) error {
+	if err := m.Fault(); err != nil {
+		return err
+	}
+	m.awaitCommands()
+	m.awaitSnapshot()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if current := m.store.Snapshot().CurrentSnapshot(); m.lastApplied == 0 || (current != nil && current.Index() >= m.lastApplied) {
+		return nil
+	}
+	m.nextSnapshotIndex = m.lastApplied + m.snapshotThreshold
+	compactIndex := m.compactIndex(m.lastApplied)
+	m.trimSizes(compactIndex)
+	return m.snapshot(m.lastApplied, compactIndex, m.currentTime, m.snapshotMetadata(), m.state.Snapshot)
+}
+
+// snapshotMetadata returns the metadata to be stored with a snapshot of the state machine, if any
+func (m *manager) snapshotMetadata() []byte {
+	if provider, ok := m.state.(SnapshotMetadataProvider); ok {
+		return provider.SnapshotMetadata()
+	}
+	return nil
+}
+
+// snapshot takes a snapshot at the given index and compacts the log up to the given compact index
+// Snapshot failures must not affect availability. If the snapshot cannot be written, skip
+// compaction of the log and try again once another snapshotThreshold entries have been applied.
+func (m *manager) snapshot(index raft.Index, compactIndex raft.Index, timestamp time.Time, metadata []byte, serialize func(io.Writer) error) error {
+	if err := m.writeSnapshot(index, timestamp, metadata, serialize); err != nil {
+		m.snapshotFailed(index, err)
+		return err
+	}
+	m.log.Debug("Compacting log up to index %d for snapshot index %d", compactIndex, index)
+	m.store.Writer().Compact(compactIndex)
+	return nil
+}
+
+// recordSize records the cumulative size of the applied entries through the given entry
+// The sizes are only recorded if a number of bytes is retained when the log is compacted, and they're recorded from
+// the first entry applied after a gap, e.g. following a snapshot install.
+func (m *manager) recordSize(entry *log.Entry) {
+	if m.retainedBytes == 0 {
+		return
+	}
+	if _, ok := entry.Entry.Entry.(*raft.LogEntry_Query); ok {
+		return
+	}
+	if len(m.appliedSizes) == 0 || m.appliedSizesIndex+raft.Index(len(m.appliedSizes))-1 != entry.Index {
+		m.appliedSizes = append(m.appliedSizes[:0], 0)
+		m.appliedSizesIndex = entry.Index
+	}
+	m.appliedSizes = append(m.appliedSizes, m.appliedSizes[len(m.appliedSizes)-1]+entry.Entry.XXX_Size())
+}
+
+// trimSizes discards the recorded sizes of the entries preceding the given index
+func (m *manager) trimSizes(index raft.Index) {
+	if index <= m.appliedSizesIndex {
+		return
+	}
+	if n := int(index - m.appliedSizesIndex); n < len(m.appliedSizes) {
+		m.appliedSizes = m.appliedSizes[n:]
+		m.appliedSizesIndex = index
+	} else {
+		m.appliedSizes = m.appliedSizes[:0]
+	}
+}
+
+// compactIndex returns the index before which the log is compacted following a snapshot at the given index
+// A tail of entries preceding the snapshot index may be retained to allow members that are slightly behind to catch
+// up from the log rather than installing the snapshot. If both the number of entries and the number of bytes to
+// retain are configured, the longer of the two tails is retained.
+// compactIndex must be called on the apply goroutine, which records the sizes of the entries as they're applied.
+func (m *manager) compactIndex(index raft.Index) raft.Index {
+	compactIndex := index + 1
+	if m.retainedEntries > 0 {
+		if m.retainedEntries < index {
+			compactIndex = index + 1 - m.retainedEntries
+		} else {
+			compactIndex = 1
+		}
+	}
+	if m.retainedBytes == 0 || index < m.appliedSizesIndex || int(index-m.appliedSizesIndex) >= len(m.appliedSizes)-1 {
+		return compactIndex
+	}
+
+	// Find the first entry from which the cumulative size of the entries through the snapshot index fits in the
+	// retained bytes. Entries from that index onward are retained.
+	n := int(index-m.appliedSizesIndex) + 1
+	total := m.appliedSizes[n]
+	i := sort.Search(n, func(i int) bool {
+		return total-m.appliedSizes[i] <= m.retainedBytes
+	})
+	if bytesIndex := m.appliedSizesIndex + raft.Index(i); bytesIndex < compactIndex {
+		return bytesIndex
+	}
+	return compactIndex
+}
+
+// snapshotFailed records a failure to take a snapshot at the given index
+func (m *manager) snapshotFailed(index raft.Index, err error) {
+	m.snapshotFailures.Inc()
+	m.log.Warn("Failed to take snapshot at index %d: %v", index, err)
+}
+
+// writeSnapshot writes a snapshot of the state machine at the given index with the given metadata to the snapshot store
+func (m *manager) writeSnapshot(index raft.Index, timestamp time.Time, metadata []byte, serialize func(io.Writer) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("snapshot store panicked: %v", r)
+		}
+	}()
+
+	m.log.Debug("Taking snapshot at index %d", index)
+	term, _ := m.store.TermAt(index)
+	writer := m.store.Snapshot().NewSnapshot(index, term, timestamp, metadata).Writer()
+
+	// If the snapshot size is limited, abort the snapshot once it exceeds the limit, even if the state machine
+	// ignores the write error. The log is not compacted, so it continues to grow until a snapshot fits the limit.
+	var out io.Writer = writer
+	var limited *limitedWriter
+	if m.maxSnapshotSize > 0 {
+		limited = &limitedWriter{writer: writer, limit: m.maxSnapshotSize}
+		out = limited
+	}
+	err = serialize(out)
+	if limited != nil && limited.exceeded {
+		_ = writer.Abort()
+		m.oversized.Inc()
+		return fmt.Errorf("snapshot exceeds the maximum snapshot size of %d bytes", m.maxSnapshotSize)
+	}
+	if err != nil {
+		// Abort the writer rather than closing it to avoid committing an incomplete snapshot
+		_ = writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// errSnapshotTooLarge is returned to a state machine writing a snapshot that exceeds the maximum snapshot size
+var errSnapshotTooLarge = errors.New("snapshot exceeds the maximum snapshot size")
+
+// limitedWriter is a writer that fails writes exceeding a limit on the total number of bytes written
+type limitedWriter struct {
+	writer   io.Writer
+	limit    int64
+	written  int64
+	exceeded bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.exceeded || w.written+int64(len(p)) > w.limit {
+		w.exceeded = true
+		return 0, errSnapshotTooLarge
+	}
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
 type change struct {
-	entry  *log.Entry
-	stream streams.WriteStream
+	entry    *log.Entry
+	stream   streams.WriteStream
+	pin      *readPin
+	pinOp    pinOp
+	applied  chan struct{}
+	snapshot chan error
+	ctx      context.Context
+	waiter   *indexWaiter
+	stop     chan struct{}
+}
+
+// indexWaiter is a caller of AwaitIndex waiting for entries up to an index to be applied
+type indexWaiter struct {
+	index raft.Index
+	ch    chan struct{}
+	done  <-chan struct{}
 }
 
 func (m *manager) Index() uint64 {
@@ -209,5 +1137,18 @@ func (m *manager) OperationType() service.OperationType {
 }
 
 func (m *manager) Close() error {
+	// Stop the apply listeners first to release an apply loop blocked on a listener's buffer.
+	m.watchersMu.Lock()
+	for _, listener := range m.applyListeners {
+		listener.stop()
+	}
+	m.applyListeners = nil
+	m.watchersMu.Unlock()
+
+	stopped := make(chan struct{})
+	_ = m.enqueue(context.Background(), &change{
+		stop: stopped,
+	})
+	<-stopped
 	return nil
 }
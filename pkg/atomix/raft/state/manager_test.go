@@ -0,0 +1,1422 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/atomix/go-framework/pkg/atomix/node"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestManager(store store.Store, config *config.ProtocolConfig, state node.StateMachine) Manager {
+	return NewManagerWithStateMachine(raft.MemberID("foo"), store, config, nil, func(node.Context) node.StateMachine {
+		return state
+	})
+}
+
+func applyCommand(manager Manager, store store.Store, value string) raft.Index {
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte(value),
+			},
+		},
+	})
+	manager.ApplyIndex(entry.Index)
+	return entry.Index
+}
+
+func awaitValue(state *testStateMachine, value string) string {
+	for i := 0; i < 100; i++ {
+		if current := state.get(); current == value {
+			return current
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return state.get()
+}
+
+func awaitSnapshot(store store.Store, index raft.Index) snapshot.Snapshot {
+	for i := 0; i < 100; i++ {
+		if snapshot := store.Snapshot().CurrentSnapshot(); snapshot != nil && snapshot.Index() >= index {
+			return snapshot
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+func readSnapshot(snapshot snapshot.Snapshot) string {
+	reader := snapshot.Reader()
+	defer reader.Close()
+	bytes, _ := ioutil.ReadAll(reader)
+	return string(bytes)
+}
+
+func TestSyncSnapshot(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+			AsyncSnapshots:    true,
+		},
+	}
+
+	// State machines that do not support capturing their state fall back to synchronous snapshots
+	state := &testStateMachine{}
+	manager := newTestManager(store, config, state)
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "b")
+
+	snapshot := awaitSnapshot(store, index)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, index, snapshot.Index())
+	assert.Equal(t, "b", readSnapshot(snapshot))
+	assert.Equal(t, index+1, store.Log().OpenReader(0).FirstIndex())
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "b")
+
+	// Verify a snapshot is taken on demand at the given index even though periodic snapshots are disabled
+	assert.NoError(t, manager.Snapshot(context.Background(), index))
+	snapshot := store.Snapshot().CurrentSnapshot()
+	if !assert.NotNil(t, snapshot) {
+		return
+	}
+	assert.Equal(t, index, snapshot.Index())
+	assert.Equal(t, "b", readSnapshot(snapshot))
+	assert.Equal(t, index+1, store.Log().OpenReader(0).FirstIndex())
+
+	// Verify no snapshot is taken if the current snapshot is already at the last applied index
+	assert.NoError(t, manager.Snapshot(context.Background(), index))
+	assert.Equal(t, snapshot, store.Snapshot().CurrentSnapshot())
+
+	// Verify no snapshot is taken if the context is done before the snapshot is started
+	index = applyCommand(manager, store, "c")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, manager.Snapshot(ctx, index))
+	<-manager.WaitApplied(index)
+	assert.Equal(t, snapshot, store.Snapshot().CurrentSnapshot())
+}
+
+func TestSnapshotMetadata(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+		},
+	}
+
+	// Verify the metadata provided by the state machine when the snapshot is taken is stored with the snapshot
+	state := &testMetadataStateMachine{testStateMachine: &testStateMachine{}}
+	manager := newTestManager(store, config, state)
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "b")
+
+	snapshot := awaitSnapshot(store, index)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, []byte("value=b"), snapshot.Metadata())
+	assert.Equal(t, "b", readSnapshot(snapshot))
+}
+
+func TestSnapshotRetainedTail(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 4,
+			RetainedEntries:   2,
+		},
+	}
+
+	// Verify the log is compacted up to the retained tail preceding the snapshot index
+	manager := newTestManager(store, config, &testStateMachine{})
+	for _, value := range []string{"a", "b", "c"} {
+		applyCommand(manager, store, value)
+	}
+	index := applyCommand(manager, store, "d")
+	assert.NotNil(t, awaitSnapshot(store, index))
+	reader := store.Log().OpenReader(0)
+	defer reader.Close()
+	for i := 0; i < 100 && reader.FirstIndex() == 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, index-1, reader.FirstIndex())
+}
+
+func TestCompactIndex(t *testing.T) {
+	store := store.NewMemoryStore()
+	manager := newTestManager(store, &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			RetainedBytes: 1024,
+		},
+	}, &testStateMachine{}).(*manager)
+
+	// Apply the entries so their sizes are recorded
+	for i := 0; i < 10; i++ {
+		applyCommand(manager, store, "foo")
+	}
+	<-manager.WaitApplied(10)
+	sizes := make(map[raft.Index]int)
+	reader := store.Log().OpenReader(0)
+	defer reader.Close()
+	for entry := reader.NextEntry(); entry != nil; entry = reader.NextEntry() {
+		sizes[entry.Index] = entry.Entry.XXX_Size()
+	}
+
+	// Verify the log is compacted up to the snapshot index by default
+	manager.retainedBytes = 0
+	assert.Equal(t, raft.Index(9), manager.compactIndex(8))
+
+	// Verify a tail of entries is retained
+	manager.retainedEntries = 3
+	assert.Equal(t, raft.Index(6), manager.compactIndex(8))
+	manager.retainedEntries = 20
+	assert.Equal(t, raft.Index(1), manager.compactIndex(8))
+
+	// Verify a tail of bytes is retained
+	manager.retainedEntries = 0
+	manager.retainedBytes = sizes[8] + sizes[7]
+	assert.Equal(t, raft.Index(7), manager.compactIndex(8))
+	manager.retainedBytes = sizes[8] + sizes[7] + sizes[6] - 1
+	assert.Equal(t, raft.Index(7), manager.compactIndex(8))
+
+	// Verify the longer of the two tails is retained
+	manager.retainedEntries = 3
+	assert.Equal(t, raft.Index(6), manager.compactIndex(8))
+	manager.retainedEntries = 1
+	assert.Equal(t, raft.Index(7), manager.compactIndex(8))
+
+	// Verify entries whose sizes were discarded once the log was compacted are not retained
+	manager.retainedEntries = 0
+	manager.retainedBytes = 1024
+	assert.Equal(t, raft.Index(1), manager.compactIndex(8))
+	manager.trimSizes(7)
+	assert.Equal(t, raft.Index(7), manager.compactIndex(8))
+}
+
+func TestOversizedSnapshot(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+			MaxSnapshotSize:   4,
+		},
+	}
+	oversized := metrics.NewCounter("raft_snapshots_oversized_total", "foo")
+	initialOversized := oversized.Value()
+
+	// Verify a snapshot exceeding the maximum snapshot size is aborted and the log is not compacted
+	manager := newTestManager(store, config, &testStateMachine{})
+	applyCommand(manager, store, "a")
+	applyCommand(manager, store, "foobar")
+	for i := 0; i < 100 && oversized.Value() == initialOversized; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, initialOversized+1, oversized.Value())
+	assert.Nil(t, store.Snapshot().CurrentSnapshot())
+	assert.Equal(t, raft.Index(1), store.Log().OpenReader(0).FirstIndex())
+
+	// Verify a snapshot is taken once the state fits within the maximum snapshot size
+	applyCommand(manager, store, "c")
+	index := applyCommand(manager, store, "d")
+	snapshot := awaitSnapshot(store, index)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, "d", readSnapshot(snapshot))
+	assert.Equal(t, initialOversized+1, oversized.Value())
+}
+
+func TestAsyncSnapshot(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+			AsyncSnapshots:    true,
+		},
+	}
+
+	state := &testCapturingStateMachine{
+		testStateMachine: &testStateMachine{},
+		release:          make(chan struct{}),
+	}
+	manager := newTestManager(store, config, state)
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "b")
+
+	// Verify that commands continue to be applied while the snapshot is blocked
+	applyCommand(manager, store, "c")
+	applyCommand(manager, store, "d")
+	assert.Equal(t, "d", awaitValue(state.testStateMachine, "d"))
+	assert.Nil(t, store.Snapshot().CurrentSnapshot())
+
+	// Once the snapshot is released, verify it reflects the state at its index
+	close(state.release)
+	snapshot := awaitSnapshot(store, index)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, index, snapshot.Index())
+	assert.Equal(t, "b", readSnapshot(snapshot))
+}
+
+func TestAsyncSnapshotClose(t *testing.T) {
+	store := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+			AsyncSnapshots:    true,
+		},
+	}
+
+	state := &testCapturingStateMachine{
+		testStateMachine: &testStateMachine{},
+		release:          make(chan struct{}),
+	}
+	manager := newTestManager(store, config, state)
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "b")
+
+	// Verify closing the manager waits for the asynchronous snapshot to be written
+	closed := make(chan struct{})
+	go func() {
+		_ = manager.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		assert.Fail(t, "manager closed while the snapshot was being written")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(state.release)
+	<-closed
+	snapshot := store.Snapshot().CurrentSnapshot()
+	if !assert.NotNil(t, snapshot) {
+		return
+	}
+	assert.Equal(t, index, snapshot.Index())
+}
+
+func TestInstallSessionSnapshot(t *testing.T) {
+	store1 := store.NewMemoryStore()
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+		},
+	}
+
+	// Take a snapshot of a state machine with an active session
+	state1 := newTestSessionStateMachine()
+	manager1 := newTestManager(store1, config, state1)
+	applyCommand(manager1, store1, "1:1:a")
+	index := applyCommand(manager1, store1, "1:2:b")
+	snapshot1 := awaitSnapshot(store1, index)
+	assert.NotNil(t, snapshot1)
+
+	// Install the snapshot on a fresh node as it would be replicated by the leader
+	store2 := store.NewMemoryStore()
+	snapshot2 := store2.Snapshot().NewSnapshot(snapshot1.Index(), snapshot1.Term(), snapshot1.Timestamp(), snapshot1.Metadata())
+	writer := snapshot2.Writer()
+	_, err := writer.Write([]byte(readSnapshot(snapshot1)))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	store2.Writer().Reset(index + 1)
+
+	// Verify a retried command is deduplicated using the session state restored from the snapshot
+	state2 := newTestSessionStateMachine()
+	manager2 := newTestManager(store2, config, state2)
+	applyCommand(manager2, store2, "1:2:b")
+	applyCommand(manager2, store2, "1:3:c")
+	assert.Equal(t, "c", awaitValue(state2.testStateMachine, "c"))
+	assert.Equal(t, []string{"c"}, state2.getApplied())
+}
+
+func TestInstallBufferedSnapshot(t *testing.T) {
+	value := strings.Repeat("abcdefgh", 16*1024)
+
+	// Install a snapshot through a buffer much smaller than the snapshot
+	store := store.NewMemoryStore()
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte(value))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	store.Writer().Reset(raft.Index(11))
+
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			RestoreBufferSize: 64,
+		},
+	}
+	state := &testStateMachine{}
+	manager := newTestManager(store, config, state)
+	manager.ApplyIndex(raft.Index(10))
+
+	// Verify the restored state matches the snapshot and the restored bytes are reported
+	assert.Equal(t, value, awaitValue(state, value))
+	assert.Equal(t, int64(len(value)), metrics.NewGauge("raft_snapshot_restore_bytes", "foo").Value())
+}
+
+func TestInstallSnapshotFailure(t *testing.T) {
+	store := store.NewMemoryStore()
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(10), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	store.Writer().Reset(raft.Index(11))
+
+	state := &testFailingInstallStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	faults := make(chan error, 1)
+	manager.WatchFault(func(err error) {
+		faults <- err
+	})
+
+	// Verify the state machine is faulted rather than applying entries on top of a partially installed snapshot
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("bar"),
+			},
+		},
+	})
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	result := <-ch
+	assert.Equal(t, raft.ErrFaulted, result.Error)
+	assert.Error(t, <-faults)
+	assert.Equal(t, "", state.get())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&state.installs))
+}
+
+func TestResumeLastApplied(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-metadata")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := store.NewMemoryStore()
+	syncInterval := time.Duration(0)
+	config := &config.ProtocolConfig{
+		Compaction: &config.CompactionConfig{
+			SnapshotThreshold: 2,
+		},
+		LastAppliedSyncInterval: &syncInterval,
+	}
+
+	// Apply entries past the snapshot to a persistent state machine
+	metadata := raft.NewFileMetadataStore("foo", 0)
+	assert.NoError(t, metadata.Open(dir))
+	state := newTestPersistentStateMachine()
+	manager := NewManagerWithStateMachine(raft.MemberID("foo"), store, config, metadata, func(node.Context) node.StateMachine {
+		return state
+	})
+	applyCommand(manager, store, "a")
+	assert.NotNil(t, awaitSnapshot(store, applyCommand(manager, store, "b")))
+	index := applyCommand(manager, store, "c")
+	<-manager.WaitApplied(index)
+	assert.NoError(t, metadata.Close())
+	assert.Equal(t, index, *metadata.LoadLastApplied())
+	assert.True(t, state.getSyncs() > 0)
+
+	// Restart the node with the state machine's persisted state and verify it resumes applying from the persisted
+	// last applied index rather than installing the snapshot and reapplying the entries following it
+	metadata = raft.NewFileMetadataStore("foo", 0)
+	assert.NoError(t, metadata.Open(dir))
+	state.reset()
+	manager = NewManagerWithStateMachine(raft.MemberID("foo"), store, config, metadata, func(node.Context) node.StateMachine {
+		return state
+	})
+	applyCommand(manager, store, "d")
+	assert.Equal(t, "d", awaitValue(state.testStateMachine, "d"))
+	assert.Equal(t, []string{"d"}, state.getApplied())
+	assert.Equal(t, 0, state.getInstalls())
+}
+
+func TestReadPin(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	index := applyCommand(manager, store, "a")
+	assert.Equal(t, "a", awaitValue(state, "a"))
+
+	pinned := metrics.NewHistogram("raft_read_pin_milliseconds", "foo", nil)
+	count := pinned.Count()
+	pin := manager.PinRead(10 * time.Second)
+	assert.Equal(t, index, pin.Index())
+	assert.Equal(t, "a", queryPin(pin))
+
+	// Verify changes committed while the pin is held are not visible through the pin
+	applyCommand(manager, store, "b")
+	assert.Equal(t, "a", queryPin(pin))
+	assert.Equal(t, "a", state.get())
+
+	// Once the pin is released, verify deferred changes are applied and the pinned time is recorded
+	pin.Release()
+	assert.Equal(t, "b", awaitValue(state, "b"))
+	assert.Equal(t, count+1, pinned.Count())
+
+	ch := make(chan streams.Result, 1)
+	pin.ApplyQuery(newQueryEntry(pin.Index()), streams.NewChannelStream(ch))
+	result := <-ch
+	assert.True(t, result.Failed())
+}
+
+func TestReadPinTimeout(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+
+	pin := manager.PinRead(100 * time.Millisecond)
+	applyCommand(manager, store, "b")
+	assert.Equal(t, "a", queryPin(pin))
+
+	// Verify the pin is released once the timeout expires
+	assert.Equal(t, "b", awaitValue(state, "b"))
+	ch := make(chan streams.Result, 1)
+	pin.ApplyQuery(newQueryEntry(pin.Index()), streams.NewChannelStream(ch))
+	result := <-ch
+	assert.Equal(t, raft.ErrTimeout, result.Error)
+
+	// Verify a pin that expires at once is released
+	manager.PinRead(0)
+	applyCommand(manager, store, "c")
+	assert.Equal(t, "c", awaitValue(state, "c"))
+}
+
+func TestReadPinDeferredLimit(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+
+	// Verify the pin is released once the maximum number of changes have been deferred
+	pin := manager.PinRead(10 * time.Second)
+	for i := 0; i < maxDeferredChanges; i++ {
+		applyCommand(manager, store, strconv.Itoa(i))
+	}
+	assert.Equal(t, strconv.Itoa(maxDeferredChanges-1), awaitValue(state, strconv.Itoa(maxDeferredChanges-1)))
+	ch := make(chan streams.Result, 1)
+	pin.ApplyQuery(newQueryEntry(pin.Index()), streams.NewChannelStream(ch))
+	result := <-ch
+	assert.Equal(t, raft.ErrTimeout, result.Error)
+	pin.Release()
+}
+
+func TestApplyEntryTypes(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+
+	var configIndexes []raft.Index
+	var configMembers [][]raft.MemberID
+	var commands []string
+	manager.WatchConfiguration(func(index raft.Index, config *raft.ConfigurationEntry) {
+		members := make([]raft.MemberID, 0, len(config.Members))
+		for _, member := range config.Members {
+			members = append(members, member.MemberID)
+		}
+		configIndexes = append(configIndexes, index)
+		configMembers = append(configMembers, members)
+		commands = append(commands, state.get())
+	})
+	events := make(chan ApplyEvent, 10)
+	manager.AddApplyListener(func(event ApplyEvent) {
+		events <- event
+	}, 10, OverflowBlock)
+
+	// Write a log containing a mix of entry types
+	entries := []*raft.LogEntry{
+		{Entry: &raft.LogEntry_Initialize{Initialize: &raft.InitializeEntry{}}},
+		{Entry: &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte("a")}}},
+		{Entry: &raft.LogEntry_Configuration{Configuration: &raft.ConfigurationEntry{Members: []*raft.Member{{MemberID: "foo"}}}}},
+		{Entry: &raft.LogEntry_Barrier{Barrier: &raft.BarrierEntry{}}},
+		{Entry: &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte("b")}}},
+		{Entry: &raft.LogEntry_Configuration{Configuration: &raft.ConfigurationEntry{Members: []*raft.Member{{MemberID: "foo"}, {MemberID: "bar"}}}}},
+		{Entry: &raft.LogEntry_Barrier{Barrier: &raft.BarrierEntry{}}},
+	}
+	for _, entry := range entries {
+		entry.Term = raft.Term(1)
+		entry.Timestamp = time.Now()
+		store.Writer().Append(entry)
+	}
+
+	// Apply the final barrier and verify it completes once all preceding entries have been applied
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(&log.Entry{Index: raft.Index(len(entries))}, streams.NewChannelStream(ch))
+	result, ok := <-ch
+	assert.True(t, ok)
+	assert.True(t, result.Succeeded())
+
+	// Verify commands were applied to the state machine and configurations to the watcher in log order
+	assert.Equal(t, "b", state.get())
+	assert.Equal(t, []raft.Index{3, 6}, configIndexes)
+	assert.Equal(t, [][]raft.MemberID{{"foo"}, {"foo", "bar"}}, configMembers)
+	assert.Equal(t, []string{"a", "b"}, commands)
+
+	// Verify each entry was applied in log order
+	types := []EntryType{
+		EntryTypeInitialize,
+		EntryTypeCommand,
+		EntryTypeConfiguration,
+		EntryTypeBarrier,
+		EntryTypeCommand,
+		EntryTypeConfiguration,
+		EntryTypeBarrier,
+	}
+	for i, entryType := range types {
+		event := <-events
+		assert.Equal(t, raft.Index(i+1), event.Index)
+		assert.Equal(t, entryType, event.Type)
+	}
+}
+
+func TestAwaitIndex(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+
+	// Verify awaiting an index that has not been applied times out without applying the entry
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("b"),
+			},
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	assert.Equal(t, context.DeadlineExceeded, manager.AwaitIndex(ctx, entry.Index))
+	cancel()
+	<-manager.WaitApplied(entry.Index - 1)
+	assert.Equal(t, "a", state.get())
+
+	// Verify the wait completes once the index is applied
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.AwaitIndex(context.Background(), entry.Index)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	manager.ApplyIndex(entry.Index)
+	assert.NoError(t, <-done)
+	assert.Equal(t, "b", state.get())
+
+	// Verify an index that has already been applied is reached immediately
+	assert.NoError(t, manager.AwaitIndex(context.Background(), entry.Index-1))
+}
+
+func TestApplyListener(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+
+	// Register a slow listener with a small buffer that applies backpressure to the apply loop
+	eventCh := make(chan ApplyEvent, 100)
+	manager.AddApplyListener(func(event ApplyEvent) {
+		time.Sleep(time.Millisecond)
+		eventCh <- event
+	}, 1, OverflowBlock)
+
+	// Register a listener that never returns to verify dropped events do not block the apply loop
+	blocked := make(chan struct{})
+	defer close(blocked)
+	manager.AddApplyListener(func(event ApplyEvent) {
+		<-blocked
+	}, 1, OverflowDrop)
+
+	store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry:     &raft.LogEntry_Initialize{Initialize: &raft.InitializeEntry{}},
+	})
+	for i := 0; i < 10; i++ {
+		store.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(1),
+			Timestamp: time.Now(),
+			Entry:     &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte(strconv.Itoa(i))}},
+		})
+	}
+	index := applyCommand(manager, store, "foo")
+	assert.Equal(t, "foo", awaitValue(state, "foo"))
+
+	// Verify the listener observed every applied entry in log order along with its output
+	for i := raft.Index(1); i <= index; i++ {
+		event := <-eventCh
+		assert.Equal(t, i, event.Index)
+		if i == 1 {
+			assert.Equal(t, EntryTypeInitialize, event.Type)
+			continue
+		}
+		assert.Equal(t, EntryTypeCommand, event.Type)
+		assert.Len(t, event.Output, 1)
+		if i == index {
+			assert.Equal(t, []byte("foo"), event.Output[0].Value)
+		} else {
+			assert.Equal(t, []byte(strconv.Itoa(int(i)-2)), event.Output[0].Value)
+		}
+	}
+
+	// Verify queries are not observed by listeners
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(newQueryEntry(index), streams.NewChannelStream(ch))
+	<-ch
+	select {
+	case event := <-eventCh:
+		assert.Fail(t, "unexpected apply event", "index %d", event.Index)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRemoveApplyListener(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+
+	eventCh := make(chan ApplyEvent, 100)
+	remove := manager.AddApplyListener(func(event ApplyEvent) {
+		eventCh <- event
+	}, 10, OverflowDrop)
+	index := applyCommand(manager, store, "foo")
+	assert.Equal(t, index, (<-eventCh).Index)
+
+	// Verify a removed listener is no longer called
+	remove()
+	applyCommand(manager, store, "bar")
+	assert.Equal(t, "bar", awaitValue(state, "bar"))
+	select {
+	case event := <-eventCh:
+		assert.Fail(t, "unexpected apply event", "index %d", event.Index)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Verify closing the manager releases a listener blocking the apply loop
+	blocked := make(chan struct{})
+	defer close(blocked)
+	manager.AddApplyListener(func(event ApplyEvent) {
+		<-blocked
+	}, 1, OverflowBlock)
+	for i := 0; i < 3; i++ {
+		applyCommand(manager, store, strconv.Itoa(i))
+	}
+	closed := make(chan error, 1)
+	go func() {
+		closed <- manager.Close()
+	}()
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "manager was not closed")
+	}
+}
+
+func TestParallelApply(t *testing.T) {
+	// Verify entries are applied serially by default
+	serial := newTestManager(store.NewMemoryStore(), &config.ProtocolConfig{}, newTestKeyedStateMachine())
+	assert.Nil(t, serial.(*manager).executor)
+
+	store := store.NewMemoryStore()
+	state := newTestKeyedStateMachine()
+	manager := newTestManager(store, &config.ProtocolConfig{ApplyParallelism: 4}, state)
+
+	// The command "a:block" blocks until a command for key "b" is applied, so the commands only complete
+	// if independent keys are applied concurrently
+	indexes := make(map[string][]uint64)
+	for _, command := range []string{"a:1", "a:block", "a:2", "b:1", "a:3", "b:2", "b:3"} {
+		index := applyCommand(manager, store, command)
+		indexes[command[:1]] = append(indexes[command[:1]], uint64(index))
+	}
+
+	// Queries are applied once all preceding commands have been applied
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(newQueryEntry(store.Writer().LastIndex()), streams.NewChannelStream(ch))
+	<-ch
+
+	// Verify independent keys were applied concurrently while preserving the order of each key
+	assert.False(t, state.blocked)
+	assert.Equal(t, []string{"1", "block", "2", "3"}, state.applied["a"])
+	assert.Equal(t, []string{"1", "2", "3"}, state.applied["b"])
+
+	// Verify each command was applied with the index of its own entry
+	assert.Equal(t, indexes, state.indexes)
+}
+
+func TestParallelApplyClose(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := newTestKeyedStateMachine()
+	parallel := newTestManager(store, &config.ProtocolConfig{ApplyParallelism: 4}, state)
+	for _, command := range []string{"a:1", "b:1", "a:2", "c:1"} {
+		applyCommand(parallel, store, command)
+	}
+
+	// Closing the manager waits for the commands being applied and stops the executor
+	assert.NoError(t, parallel.Close())
+	assert.Nil(t, parallel.(*manager).executor)
+	state.mu.Lock()
+	assert.Equal(t, []string{"1", "2"}, state.applied["a"])
+	assert.Equal(t, []string{"1"}, state.applied["b"])
+	assert.Equal(t, []string{"1"}, state.applied["c"])
+	state.mu.Unlock()
+}
+
+func TestClose(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+	assert.Equal(t, "a", awaitValue(state, "a"))
+
+	// Defer a change behind a read pin that's still held when the manager is closed
+	pin := manager.PinRead(10 * time.Second)
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("b"),
+			},
+		},
+	})
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	assert.NoError(t, manager.Close())
+
+	// Verify the deferred change was failed rather than abandoned
+	result := <-ch
+	assert.Equal(t, raft.ErrShuttingDown, result.Error)
+	assert.Equal(t, "a", state.get())
+
+	// Verify changes submitted once the manager is closed fail rather than block
+	ch = make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	result = <-ch
+	assert.Equal(t, raft.ErrShuttingDown, result.Error)
+	assert.Equal(t, raft.ErrShuttingDown, manager.AwaitIndex(context.Background(), entry.Index))
+	assert.Equal(t, raft.ErrShuttingDown, manager.Snapshot(context.Background(), entry.Index))
+	pin.Release()
+	assert.NoError(t, manager.Close())
+}
+
+func TestApplyRate(t *testing.T) {
+	rateWindow := 500 * time.Millisecond
+	store := store.NewMemoryStore()
+	manager := newTestManager(store, &config.ProtocolConfig{RateWindow: &rateWindow}, &testStateMachine{})
+	defer manager.Close()
+
+	// Apply commands at a steady rate for longer than the rate window
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	startTime := time.Now()
+	applied := 0
+	for time.Since(startTime) < time.Second {
+		<-ticker.C
+		applyCommand(manager, store, strconv.Itoa(applied))
+		applied++
+	}
+	<-manager.WaitApplied(store.Writer().LastIndex())
+
+	// Verify the reported apply rate is within a tolerance of the actual rate
+	actual := float64(applied) / time.Since(startTime).Seconds()
+	reported := metrics.NewRate("raft_apply_rate", "foo", rateWindow).Value()
+	assert.InDelta(t, actual, reported, actual*0.25)
+}
+
+func TestApplyFault(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testPanickingStateMachine{testStateMachine: &testStateMachine{}}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	faults := make(chan error, 1)
+	manager.WatchFault(func(err error) {
+		faults <- err
+	})
+
+	applyCommand(manager, store, "a")
+	index := applyCommand(manager, store, "panic")
+	applyCommand(manager, store, "b")
+
+	// Verify the state machine faults at the panicking entry rather than hanging or applying later entries
+	select {
+	case <-manager.WaitApplied(store.Writer().LastIndex()):
+	case <-time.After(5 * time.Second):
+		t.Fatal("apply did not complete")
+	}
+	select {
+	case err := <-faults:
+		assert.Equal(t, err, manager.Fault())
+		assert.Contains(t, err.Error(), fmt.Sprintf("entry %d", index))
+	case <-time.After(5 * time.Second):
+		t.Fatal("fault was not reported")
+	}
+	assert.Equal(t, "a", state.get())
+
+	// Verify queries fail once the state machine has faulted
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(newQueryEntry(store.Writer().LastIndex()), streams.NewChannelStream(ch))
+	result := <-ch
+	assert.Equal(t, raft.ErrFaulted, result.Error)
+}
+
+func TestApplyFaultStream(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testPanickingStateMachine{testStateMachine: &testStateMachine{}}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+
+	// Verify the stream of the command that panicked is failed and closed rather than left open
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("panic"),
+			},
+		},
+	})
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	select {
+	case result := <-ch:
+		assert.Equal(t, raft.ErrFaulted, result.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not failed")
+	}
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestApplyQueryPanic(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testPanickingStateMachine{testStateMachine: &testStateMachine{}}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	applyCommand(manager, store, "a")
+	assert.Equal(t, "a", awaitValue(state.testStateMachine, "a"))
+
+	// Verify only the stream of the query that panicked is failed
+	entry := newQueryEntry(store.Writer().LastIndex())
+	entry.Entry.GetQuery().Value = []byte("panic")
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	select {
+	case result := <-ch:
+		assert.Error(t, result.Error)
+		assert.NotEqual(t, raft.ErrFaulted, result.Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not failed")
+	}
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	// Verify the state machine is not faulted and continues to apply commands and queries
+	assert.NoError(t, manager.Fault())
+	applyCommand(manager, store, "b")
+	assert.Equal(t, "b", awaitValue(state.testStateMachine, "b"))
+	ch = make(chan streams.Result, 1)
+	manager.ApplyEntry(newQueryEntry(store.Writer().LastIndex()), streams.NewChannelStream(ch))
+	result := <-ch
+	assert.NoError(t, result.Error)
+	assert.Equal(t, []byte("b"), result.Value)
+}
+
+// corruptLog is a log whose readers report the entry at the given index as corrupt
+type corruptLog struct {
+	log.Log
+	index raft.Index
+}
+
+func (l *corruptLog) OpenReader(index raft.Index) log.Reader {
+	return &corruptReader{
+		Reader: l.Log.OpenReader(index),
+		index:  l.index,
+	}
+}
+
+type corruptReader struct {
+	log.Reader
+	index raft.Index
+	err   error
+}
+
+func (r *corruptReader) NextEntry() *log.Entry {
+	r.err = nil
+	if r.NextIndex() == r.index && r.LastIndex() >= r.index {
+		r.err = &log.CorruptEntryError{Index: r.index}
+		return nil
+	}
+	return r.Reader.NextEntry()
+}
+
+func (r *corruptReader) Err() error {
+	return r.err
+}
+
+func TestApplyCorruptEntry(t *testing.T) {
+	store := store.NewStore(&corruptLog{Log: log.NewMemoryLog(), index: 2}, snapshot.NewMemoryStore())
+	state := &testStateMachine{}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+	faults := make(chan error, 1)
+	manager.WatchFault(func(err error) {
+		faults <- err
+	})
+
+	applyCommand(manager, store, "a")
+	assert.Equal(t, "a", awaitValue(state, "a"))
+	store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("b"),
+			},
+		},
+	})
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("c"),
+			},
+		},
+	})
+
+	// Verify the state machine faults at the corrupt entry rather than skipping it
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	select {
+	case err := <-faults:
+		assert.Equal(t, &log.CorruptEntryError{Index: 2}, err)
+		assert.Equal(t, err, manager.Fault())
+	case <-time.After(5 * time.Second):
+		t.Fatal("fault was not reported")
+	}
+	result := <-ch
+	assert.Equal(t, raft.ErrFaulted, result.Error)
+	assert.Equal(t, "a", state.get())
+}
+
+func TestOutputSerializer(t *testing.T) {
+	store := store.NewMemoryStore()
+	state := &testSerializingStateMachine{testStateMachine: &testStateMachine{}}
+	manager := newTestManager(store, &config.ProtocolConfig{}, state)
+
+	// Verify command output is serialized by the state machine rather than written as bytes
+	entry := store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("foo"),
+			},
+		},
+	})
+	ch := make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	result := <-ch
+	assert.NoError(t, result.Error)
+	output := testOutput{}
+	assert.NoError(t, json.Unmarshal(result.Value.([]byte), &output))
+	assert.Equal(t, testOutput{Value: "foo", Length: 3}, output)
+
+	// Verify query output is serialized by the state machine
+	ch = make(chan streams.Result, 1)
+	manager.ApplyEntry(newQueryEntry(entry.Index), streams.NewChannelStream(ch))
+	result = <-ch
+	assert.NoError(t, result.Error)
+	output = testOutput{}
+	assert.NoError(t, json.Unmarshal(result.Value.([]byte), &output))
+	assert.Equal(t, testOutput{Value: "foo", Length: 3}, output)
+
+	// Verify output that can't be serialized is returned as an error
+	entry = store.Writer().Append(&raft.LogEntry{
+		Term:      raft.Term(1),
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{},
+		},
+	})
+	ch = make(chan streams.Result, 1)
+	manager.ApplyEntry(entry, streams.NewChannelStream(ch))
+	result = <-ch
+	assert.Error(t, result.Error)
+}
+
+func newQueryEntry(index raft.Index) *log.Entry {
+	return &log.Entry{
+		Index: index,
+		Entry: &raft.LogEntry{
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Query{
+				Query: &raft.QueryEntry{},
+			},
+		},
+	}
+}
+
+func queryPin(pin ReadPin) string {
+	ch := make(chan streams.Result, 1)
+	pin.ApplyQuery(newQueryEntry(pin.Index()), streams.NewChannelStream(ch))
+	result := <-ch
+	if result.Failed() {
+		return ""
+	}
+	return string(result.Value.([]byte))
+}
+
+// testStateMachine is a state machine that stores the last command value
+type testStateMachine struct {
+	value string
+	mu    sync.RWMutex
+}
+
+func (s *testStateMachine) get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+func (s *testStateMachine) Snapshot(writer io.Writer) error {
+	_, err := writer.Write([]byte(s.get()))
+	return err
+}
+
+func (s *testStateMachine) Install(reader io.Reader) error {
+	bytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	s.mu.Lock()
+	s.value = string(bytes)
+	s.mu.Unlock()
+	if stream != nil {
+		stream.Value(bytes)
+		stream.Close()
+	}
+}
+
+func (s *testStateMachine) Query(bytes []byte, stream streams.WriteStream) {
+	if stream != nil {
+		stream.Value([]byte(s.get()))
+		stream.Close()
+	}
+}
+
+func (s *testStateMachine) CanDelete(index uint64) bool {
+	return true
+}
+
+// testCapturingStateMachine is a state machine supporting asynchronous snapshots that blocks serialization until released
+type testCapturingStateMachine struct {
+	*testStateMachine
+	release chan struct{}
+}
+
+func (s *testCapturingStateMachine) CaptureSnapshot() (func(io.Writer) error, error) {
+	value := s.get()
+	return func(writer io.Writer) error {
+		<-s.release
+		_, err := io.Copy(writer, bytes.NewReader([]byte(value)))
+		return err
+	}, nil
+}
+
+// testMetadataStateMachine is a state machine that attaches its current value as metadata to its snapshots
+type testMetadataStateMachine struct {
+	*testStateMachine
+}
+
+func (s *testMetadataStateMachine) SnapshotMetadata() []byte {
+	return []byte("value=" + s.get())
+}
+
+// testSessionStateMachine is a state machine that deduplicates commands of the form "session:sequence:value"
+type testSessionStateMachine struct {
+	*testStateMachine
+	sessions map[string]int
+	applied  []string
+}
+
+func newTestSessionStateMachine() *testSessionStateMachine {
+	return &testSessionStateMachine{
+		testStateMachine: &testStateMachine{},
+		sessions:         make(map[string]int),
+	}
+}
+
+// testSessionSnapshot is the snapshot format of a testSessionStateMachine
+type testSessionSnapshot struct {
+	Value    string
+	Sessions map[string]int
+}
+
+func (s *testSessionStateMachine) getApplied() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.applied
+}
+
+func (s *testSessionStateMachine) Snapshot(writer io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.NewEncoder(writer).Encode(&testSessionSnapshot{
+		Value:    s.value,
+		Sessions: s.sessions,
+	})
+}
+
+func (s *testSessionStateMachine) Install(reader io.Reader) error {
+	snapshot := &testSessionSnapshot{}
+	if err := json.NewDecoder(reader).Decode(snapshot); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.value = snapshot.Value
+	s.sessions = snapshot.Sessions
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testSessionStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	parts := strings.SplitN(string(bytes), ":", 3)
+	sequence, _ := strconv.Atoi(parts[1])
+	s.mu.Lock()
+	if sequence > s.sessions[parts[0]] {
+		s.sessions[parts[0]] = sequence
+		s.value = parts[2]
+		s.applied = append(s.applied, parts[2])
+	}
+	s.mu.Unlock()
+	if stream != nil {
+		stream.Value([]byte(parts[2]))
+		stream.Close()
+	}
+}
+
+// testPersistentStateMachine is a persistent state machine that records the changes applied to it
+type testPersistentStateMachine struct {
+	*testStateMachine
+	applied  []string
+	installs int
+	syncs    int
+}
+
+func newTestPersistentStateMachine() *testPersistentStateMachine {
+	return &testPersistentStateMachine{
+		testStateMachine: &testStateMachine{},
+	}
+}
+
+// reset resets the changes recorded by the state machine, retaining its state
+func (s *testPersistentStateMachine) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applied = nil
+	s.installs = 0
+	s.syncs = 0
+}
+
+func (s *testPersistentStateMachine) getApplied() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.applied
+}
+
+func (s *testPersistentStateMachine) getInstalls() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.installs
+}
+
+func (s *testPersistentStateMachine) getSyncs() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.syncs
+}
+
+func (s *testPersistentStateMachine) Install(reader io.Reader) error {
+	s.mu.Lock()
+	s.installs++
+	s.mu.Unlock()
+	return s.testStateMachine.Install(reader)
+}
+
+func (s *testPersistentStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	s.mu.Lock()
+	s.applied = append(s.applied, string(bytes))
+	s.mu.Unlock()
+	s.testStateMachine.Command(bytes, stream)
+}
+
+func (s *testPersistentStateMachine) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncs++
+	return nil
+}
+
+// testKeyedStateMachine is a state machine that records commands of the form "key:value" for each key
+type testKeyedStateMachine struct {
+	*testStateMachine
+	applied map[string][]string
+	indexes map[string][]uint64
+	blocked bool
+	unblock chan struct{}
+	once    sync.Once
+}
+
+func newTestKeyedStateMachine() *testKeyedStateMachine {
+	return &testKeyedStateMachine{
+		testStateMachine: &testStateMachine{},
+		applied:          make(map[string][]string),
+		indexes:          make(map[string][]uint64),
+		unblock:          make(chan struct{}),
+	}
+}
+
+func (s *testKeyedStateMachine) CommandKey(bytes []byte) (string, bool) {
+	parts := strings.SplitN(string(bytes), ":", 2)
+	return parts[0], len(parts) == 2
+}
+
+func (s *testKeyedStateMachine) KeyedCommand(context node.Context, bytes []byte, stream streams.WriteStream) {
+	key, _ := s.CommandKey(bytes)
+	s.mu.Lock()
+	s.indexes[key] = append(s.indexes[key], context.Index())
+	s.mu.Unlock()
+	s.Command(bytes, stream)
+}
+
+func (s *testKeyedStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	parts := strings.SplitN(string(bytes), ":", 2)
+	if parts[1] == "block" {
+		select {
+		case <-s.unblock:
+		case <-time.After(5 * time.Second):
+			s.mu.Lock()
+			s.blocked = true
+			s.mu.Unlock()
+		}
+	} else if parts[0] == "b" {
+		s.once.Do(func() {
+			close(s.unblock)
+		})
+	}
+	s.mu.Lock()
+	s.applied[parts[0]] = append(s.applied[parts[0]], parts[1])
+	s.mu.Unlock()
+	if stream != nil {
+		stream.Value(bytes)
+		stream.Close()
+	}
+}
+
+// testPanickingStateMachine is a state machine that panics when applying the command or query "panic"
+type testPanickingStateMachine struct {
+	*testStateMachine
+}
+
+func (s *testPanickingStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	if string(bytes) == "panic" {
+		panic("invalid command")
+	}
+	s.testStateMachine.Command(bytes, stream)
+}
+
+func (s *testPanickingStateMachine) Query(bytes []byte, stream streams.WriteStream) {
+	if string(bytes) == "panic" {
+		panic("invalid query")
+	}
+	s.testStateMachine.Query(bytes, stream)
+}
+
+// testOutput is the output of a testSerializingStateMachine
+type testOutput struct {
+	Value  string
+	Length int
+}
+
+// testSerializingStateMachine is a state machine that writes its outputs as values serialized with its own codec
+type testSerializingStateMachine struct {
+	*testStateMachine
+}
+
+func (s *testSerializingStateMachine) Command(bytes []byte, stream streams.WriteStream) {
+	s.testStateMachine.Command(bytes, streams.NewNilStream())
+	if len(bytes) == 0 {
+		stream.Value(nil)
+	} else {
+		stream.Value(testOutput{Value: string(bytes), Length: len(bytes)})
+	}
+	stream.Close()
+}
+
+func (s *testSerializingStateMachine) Query(bytes []byte, stream streams.WriteStream) {
+	value := s.get()
+	stream.Value(testOutput{Value: value, Length: len(value)})
+	stream.Close()
+}
+
+func (s *testSerializingStateMachine) SerializeOutput(value interface{}) ([]byte, error) {
+	output, ok := value.(testOutput)
+	if !ok {
+		return nil, fmt.Errorf("unexpected output %v", value)
+	}
+	return json.Marshal(output)
+}
+
+// testFailingInstallStateMachine is a state machine that fails to install snapshots
+type testFailingInstallStateMachine struct {
+	testStateMachine
+	installs int32
+}
+
+func (s *testFailingInstallStateMachine) Install(reader io.Reader) error {
+	atomic.AddInt32(&s.installs, 1)
+	return errors.New("install failed")
+}
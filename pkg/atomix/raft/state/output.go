@@ -0,0 +1,74 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+)
+
+// OutputSerializer is implemented by state machines that control how their outputs are serialized into responses
+// SerializeOutput is called with each value the state machine writes to a command or query output stream, and the
+// returned bytes are sent to the client as the response output. Independent commands may be applied concurrently, so
+// SerializeOutput must be safe to call from multiple goroutines. State machines that don't implement OutputSerializer
+// must write their outputs as bytes, e.g. encoded protobuf messages, which are sent to the client as written.
+type OutputSerializer interface {
+	SerializeOutput(value interface{}) ([]byte, error)
+}
+
+// newSerializingStream returns a stream that serializes the values written to the given stream
+func newSerializingStream(stream streams.WriteStream, serializer OutputSerializer) *serializingStream {
+	return &serializingStream{
+		stream:     stream,
+		serializer: serializer,
+	}
+}
+
+// serializingStream is a WriteStream that serializes output values with the state machine's serializer
+// A value that fails to serialize is sent to the client as an error.
+type serializingStream struct {
+	stream     streams.WriteStream
+	serializer OutputSerializer
+}
+
+func (s *serializingStream) Send(result streams.Result) {
+	if result.Failed() {
+		s.stream.Send(result)
+		return
+	}
+	bytes, err := s.serializer.SerializeOutput(result.Value)
+	s.stream.Send(streams.Result{
+		Value: bytes,
+		Error: err,
+	})
+}
+
+func (s *serializingStream) Result(value interface{}, err error) {
+	s.Send(streams.Result{
+		Value: value,
+		Error: err,
+	})
+}
+
+func (s *serializingStream) Value(value interface{}) {
+	s.Result(value, nil)
+}
+
+func (s *serializingStream) Error(err error) {
+	s.Result(nil, err)
+}
+
+func (s *serializingStream) Close() {
+	s.stream.Close()
+}
@@ -0,0 +1,173 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"context"
+	"errors"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/metrics"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errReadReleased is returned for queries applied to a released read pin
+var errReadReleased = errors.New("read pin released")
+
+// ReadPin pins the state machine at a single applied index
+// While a pin is held, all changes other than queries applied through the pin are deferred, so every query
+// applied through the pin observes the same state. The pin must be released to resume applying changes.
+type ReadPin interface {
+	// Index returns the index at which the state machine is pinned
+	Index() raft.Index
+
+	// ApplyQuery applies a query entry to the pinned state, returning output on the given stream
+	ApplyQuery(entry *log.Entry, stream streams.WriteStream)
+
+	// Release releases the pin and resumes applying deferred changes
+	Release()
+}
+
+// maxDeferredChanges is the maximum number of changes deferred while a read pin is held
+// Once the limit is reached, the pin is released as if its timeout had expired.
+const maxDeferredChanges = stateBufferSize
+
+// pinDurationBounds are the bucket bounds in milliseconds of the histogram of times the state machine is pinned
+var pinDurationBounds = metrics.ExponentialBounds(1, 4, 8)
+
+// pinOp is an operation on a read pin
+type pinOp int
+
+const (
+	pinAcquire pinOp = iota
+	pinQuery
+	pinRelease
+)
+
+// PinRead pins the state machine at the last applied index
+// The pin is released automatically if it's not released before the given timeout.
+func (m *manager) PinRead(timeout time.Duration) ReadPin {
+	pin := &readPin{
+		manager: m,
+		ready:   make(chan struct{}),
+	}
+	_ = m.enqueue(context.Background(), &change{
+		pin:   pin,
+		pinOp: pinAcquire,
+	})
+	<-pin.ready
+
+	// The timer is assigned with the pin locked, so a timer that fires at once can't release the pin before then.
+	pin.mu.Lock()
+	pin.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&pin.expired, 1)
+		pin.Release()
+	})
+	pin.mu.Unlock()
+	return pin
+}
+
+// execPinChange executes a read pin operation on the state machine
+func (m *manager) execPinChange(change *change) {
+	pin := change.pin
+	switch change.pinOp {
+	case pinAcquire:
+		m.awaitCommands()
+		pin.index = m.lastApplied
+		pin.pinned = time.Now()
+		m.pinned = pin
+		close(pin.ready)
+	case pinQuery:
+		if m.pinned != pin {
+			if atomic.LoadInt32(&pin.expired) == 1 {
+				change.stream.Error(raft.ErrTimeout)
+			} else {
+				change.stream.Error(errReadReleased)
+			}
+			change.stream.Close()
+			return
+		}
+		query := change.entry.Entry.GetQuery()
+		m.execQuery(pin.index, change.entry.Entry.Timestamp, query, change.stream)
+	case pinRelease:
+		if m.pinned != pin {
+			return
+		}
+		m.unpin()
+	}
+}
+
+// deferChange defers the given change until the current pin is released
+func (m *manager) deferChange(change *change) {
+	m.deferred = append(m.deferred, change)
+	if len(m.deferred) >= maxDeferredChanges {
+		m.log.Warn("Releasing read pin at index %d after deferring %d changes", m.pinned.index, len(m.deferred))
+		atomic.StoreInt32(&m.pinned.expired, 1)
+		m.unpin()
+	}
+}
+
+// unpin releases the current pin and applies the changes deferred while it was held
+func (m *manager) unpin() {
+	m.pinDuration.Observe(int64(time.Since(m.pinned.pinned) / time.Millisecond))
+	m.pinned = nil
+	deferred := m.deferred
+	m.deferred = nil
+	for _, change := range deferred {
+		m.execChange(change)
+	}
+}
+
+// readPin is a ReadPin implementation
+type readPin struct {
+	manager *manager
+	index   raft.Index
+	pinned  time.Time
+	ready   chan struct{}
+	timer   *time.Timer
+	mu      sync.Mutex
+	expired int32
+	once    sync.Once
+}
+
+func (p *readPin) Index() raft.Index {
+	return p.index
+}
+
+func (p *readPin) ApplyQuery(entry *log.Entry, stream streams.WriteStream) {
+	_ = p.manager.enqueue(context.Background(), &change{
+		entry:  entry,
+		stream: stream,
+		pin:    p,
+		pinOp:  pinQuery,
+	})
+}
+
+func (p *readPin) Release() {
+	p.once.Do(func() {
+		p.mu.Lock()
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+		p.mu.Unlock()
+		_ = p.manager.enqueue(context.Background(), &change{
+			pin:   p,
+			pinOp: pinRelease,
+		})
+	})
+}
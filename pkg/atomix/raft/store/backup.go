@@ -0,0 +1,186 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+const (
+	// backupFormat identifies Raft backups
+	backupFormat = "raft-backup"
+	// backupVersion is the version of the backup format
+	backupVersion = 1
+)
+
+// BackupMetadata is the Raft metadata captured in a backup
+type BackupMetadata struct {
+	Term        raft.Term      `json:"term"`
+	Vote        *raft.MemberID `json:"vote,omitempty"`
+	CommitIndex raft.Index     `json:"commitIndex"`
+}
+
+// backupHeader is the first record of a backup
+type backupHeader struct {
+	Format   string          `json:"format"`
+	Version  int             `json:"version"`
+	Metadata BackupMetadata  `json:"metadata"`
+	Snapshot *backupSnapshot `json:"snapshot,omitempty"`
+	LogIndex raft.Index      `json:"logIndex"`
+	LogEnd   raft.Index      `json:"logEnd"`
+}
+
+// backupSnapshot describes the snapshot included in a backup
+type backupSnapshot struct {
+	Index     raft.Index `json:"index"`
+	Term      raft.Term  `json:"term"`
+	Timestamp time.Time  `json:"timestamp"`
+	Metadata  []byte     `json:"metadata,omitempty"`
+}
+
+// backupData is the snapshot data record of a backup
+type backupData struct {
+	Data []byte `json:"data"`
+}
+
+// WriteBackup writes the given metadata, the store's current snapshot, and the log following the snapshot to the
+// given writer as a single bundle
+// The caller must ensure the metadata and store are not modified while the backup is written. The log is written
+// in the format used by log.ExportLog.
+func WriteBackup(store Store, metadata BackupMetadata, w io.Writer) error {
+	header := &backupHeader{
+		Format:   backupFormat,
+		Version:  backupVersion,
+		Metadata: metadata,
+	}
+
+	reader := store.Log().OpenReader(0)
+	header.LogIndex = reader.FirstIndex()
+	header.LogEnd = reader.LastIndex()
+	reader.Close()
+
+	snapshot := store.Snapshot().CurrentSnapshot()
+	if snapshot != nil {
+		header.Snapshot = &backupSnapshot{
+			Index:     snapshot.Index(),
+			Term:      snapshot.Term(),
+			Timestamp: snapshot.Timestamp(),
+			Metadata:  snapshot.Metadata(),
+		}
+		if header.LogIndex <= snapshot.Index() {
+			header.LogIndex = snapshot.Index() + 1
+		} else if header.LogIndex > snapshot.Index()+1 {
+			return fmt.Errorf("log begins at index %d, after snapshot %d", header.LogIndex, snapshot.Index())
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	if snapshot != nil {
+		reader := snapshot.Reader()
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(&backupData{Data: data}); err != nil {
+			return err
+		}
+	}
+	if header.LogEnd >= header.LogIndex {
+		return log.ExportLog(store.Log(), w, header.LogIndex, header.LogEnd)
+	}
+	return nil
+}
+
+// OpenBackup reads the header of a backup written by WriteBackup from the given reader
+func OpenBackup(r io.Reader) (*Backup, error) {
+	decoder := json.NewDecoder(r)
+	header := &backupHeader{}
+	if err := decoder.Decode(header); err != nil {
+		return nil, err
+	}
+	if header.Format != backupFormat || header.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup format %s version %d", header.Format, header.Version)
+	}
+	return &Backup{
+		header:  header,
+		decoder: decoder,
+		reader:  r,
+	}, nil
+}
+
+// Backup is a backup opened for restoring
+type Backup struct {
+	header  *backupHeader
+	decoder *json.Decoder
+	reader  io.Reader
+}
+
+// Metadata returns the Raft metadata captured in the backup
+func (b *Backup) Metadata() BackupMetadata {
+	return b.header.Metadata
+}
+
+// Restore restores the snapshot and log in the backup to the given store
+// The store must be empty. The snapshot is committed to the snapshot store and the log is reset to begin at the
+// first index following the snapshot.
+func (b *Backup) Restore(store Store) error {
+	reader := store.Log().OpenReader(0)
+	empty := reader.LastIndex() < reader.FirstIndex()
+	reader.Close()
+	if !empty || store.Snapshot().CurrentSnapshot() != nil {
+		return errors.New("cannot restore a backup to a non-empty store")
+	}
+
+	if b.header.Snapshot != nil {
+		data := &backupData{}
+		if err := b.decoder.Decode(data); err != nil {
+			return err
+		}
+		snapshot := store.Snapshot().NewSnapshot(b.header.Snapshot.Index, b.header.Snapshot.Term, b.header.Snapshot.Timestamp, b.header.Snapshot.Metadata)
+		writer := snapshot.Writer()
+		if _, err := writer.Write(data.Data); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+	}
+
+	store.Writer().Reset(b.header.LogIndex)
+	if b.header.LogEnd < b.header.LogIndex {
+		return nil
+	}
+
+	// The decoder may have buffered the beginning of the log, so read the log from the buffer first
+	count, err := log.ImportLog(store.Log(), io.MultiReader(b.decoder.Buffered(), b.reader))
+	if err != nil {
+		return err
+	}
+	if expected := int(b.header.LogEnd - b.header.LogIndex + 1); count != expected {
+		return fmt.Errorf("restored %d entries, expected %d", count, expected)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"bytes"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestBackup(t *testing.T) {
+	source := NewMemoryStore()
+	for i := 1; i <= 6; i++ {
+		source.Writer().Append(&raft.LogEntry{
+			Term:      raft.Term(i/3 + 1),
+			Timestamp: time.Now(),
+			Entry:     &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte{byte(i)}}},
+		})
+	}
+	snapshot := source.Snapshot().NewSnapshot(raft.Index(3), raft.Term(2), time.Now(), []byte("v1"))
+	writer := snapshot.Writer()
+	_, err := writer.Write([]byte("Hello world!"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	vote := raft.MemberID("foo")
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteBackup(source, BackupMetadata{Term: 3, Vote: &vote, CommitIndex: 5}, buf))
+	data := buf.Bytes()
+
+	// Restore the backup to an empty store and verify the metadata, snapshot, and log following the snapshot
+	backup, err := OpenBackup(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, raft.Term(3), backup.Metadata().Term)
+	assert.Equal(t, vote, *backup.Metadata().Vote)
+	assert.Equal(t, raft.Index(5), backup.Metadata().CommitIndex)
+
+	target := NewMemoryStore()
+	assert.NoError(t, backup.Restore(target))
+	restored := target.Snapshot().CurrentSnapshot()
+	assert.Equal(t, raft.Index(3), restored.Index())
+	assert.Equal(t, raft.Term(2), restored.Term())
+	assert.Equal(t, []byte("v1"), restored.Metadata())
+	reader := restored.Reader()
+	value, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello world!", string(value))
+
+	assert.Equal(t, raft.Index(4), target.Reader().FirstIndex())
+	assert.Equal(t, raft.Index(6), target.Reader().LastIndex())
+	for i := raft.Index(4); i <= 6; i++ {
+		term, ok := target.TermAt(i)
+		assert.True(t, ok)
+		expected, _ := source.TermAt(i)
+		assert.Equal(t, expected, term)
+	}
+	term, ok := target.TermAt(3)
+	assert.True(t, ok)
+	assert.Equal(t, raft.Term(2), term)
+
+	// Verify the backup cannot be restored to a non-empty store
+	backup, err = OpenBackup(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Error(t, backup.Restore(target))
+}
@@ -0,0 +1,146 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// identityFile is the name of the file identifying the owner of a data directory
+	identityFile = "identity.json"
+	// identityVersion is the version of the data directory layout
+	identityVersion = 1
+	// metaDir is the name of the metadata subdirectory
+	metaDir = "meta"
+	// logDir is the name of the log subdirectory
+	logDir = "log"
+	// snapshotDir is the name of the snapshot subdirectory
+	snapshotDir = "snapshots"
+)
+
+// dataDirIdentity is the contents of the identity file
+type dataDirIdentity struct {
+	Version   int    `json:"version"`
+	ClusterID string `json:"clusterId"`
+	MemberID  string `json:"memberId"`
+}
+
+// DataDir is a data directory containing the metadata, log, and snapshots of a single member
+type DataDir struct {
+	path string
+}
+
+// OpenDataDir opens the configured data directory for the given member
+// If the directory is empty or does not exist, it's initialized with the metadata, log, and snapshot
+// subdirectories and an identity file recording the cluster and member. If the directory was already initialized,
+// the identity file is checked to ensure the directory belongs to the same cluster and member. A directory that
+// contains other data or belongs to another member is rejected.
+func OpenDataDir(config *config.ProtocolConfig, member raft.MemberID) (*DataDir, error) {
+	path := config.GetStorage().GetDataDir()
+	if path == "" {
+		return nil, errors.New("no data directory configured")
+	}
+	expected := dataDirIdentity{
+		Version:   identityVersion,
+		ClusterID: config.GetClusterId(),
+		MemberID:  string(member),
+	}
+
+	bytes, err := ioutil.ReadFile(filepath.Join(path, identityFile))
+	if os.IsNotExist(err) {
+		if err := initDataDir(path, expected); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		identity := dataDirIdentity{}
+		if err := json.Unmarshal(bytes, &identity); err != nil {
+			return nil, fmt.Errorf("invalid identity file in data directory %s: %v", path, err)
+		}
+		if identity != expected {
+			return nil, fmt.Errorf("data directory %s belongs to member %s of cluster %q (version %d), not member %s of cluster %q",
+				path, identity.MemberID, identity.ClusterID, identity.Version, expected.MemberID, expected.ClusterID)
+		}
+	}
+
+	dir := &DataDir{path: path}
+	for _, subdir := range []string{dir.MetaDir(), dir.LogDir(), dir.SnapshotDir()} {
+		if err := os.MkdirAll(subdir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+// initDataDir initializes a new data directory with the given identity
+// The identity file is written last, so a partially initialized directory is initialized again on restart.
+func initDataDir(path string, identity dataDirIdentity) error {
+	files, err := ioutil.ReadDir(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, file := range files {
+		switch file.Name() {
+		case metaDir, logDir, snapshotDir, identityFile + ".tmp":
+		default:
+			return fmt.Errorf("data directory %s is not empty and has no identity file", path)
+		}
+	}
+
+	for _, subdir := range []string{metaDir, logDir, snapshotDir} {
+		if err := os.MkdirAll(filepath.Join(path, subdir), 0755); err != nil {
+			return err
+		}
+	}
+
+	bytes, err := json.Marshal(&identity)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(path, identityFile+".tmp")
+	if err := ioutil.WriteFile(tmp, bytes, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(path, identityFile))
+}
+
+// Path returns the root path of the data directory
+func (d *DataDir) Path() string {
+	return d.path
+}
+
+// MetaDir returns the path of the metadata directory
+func (d *DataDir) MetaDir() string {
+	return filepath.Join(d.path, metaDir)
+}
+
+// LogDir returns the path of the log directory
+func (d *DataDir) LogDir() string {
+	return filepath.Join(d.path, logDir)
+}
+
+// SnapshotDir returns the path of the snapshot directory
+func (d *DataDir) SnapshotDir() string {
+	return filepath.Join(d.path, snapshotDir)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newDataDirConfig(path string, clusterID string) *config.ProtocolConfig {
+	return &config.ProtocolConfig{
+		ClusterId: clusterID,
+		Storage: &config.StorageConfig{
+			DataDir: path,
+		},
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "raft-data")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	path := filepath.Join(root, "foo")
+
+	// Verify a fresh data directory is initialized with the expected layout
+	dir, err := OpenDataDir(newDataDirConfig(path, "test"), raft.MemberID("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, path, dir.Path())
+	for _, subdir := range []string{dir.MetaDir(), dir.LogDir(), dir.SnapshotDir()} {
+		info, err := os.Stat(subdir)
+		assert.NoError(t, err)
+		assert.True(t, info.IsDir())
+	}
+	_, err = os.Stat(filepath.Join(path, identityFile))
+	assert.NoError(t, err)
+
+	// Verify the directory can be reopened by the same member and existing data is preserved
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir.LogDir(), "segment-1"), []byte("foo"), 0644))
+	dir, err = OpenDataDir(newDataDirConfig(path, "test"), raft.MemberID("foo"))
+	assert.NoError(t, err)
+	bytes, err := ioutil.ReadFile(filepath.Join(dir.LogDir(), "segment-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(bytes))
+
+	// Verify the directory is rejected by another member or cluster
+	_, err = OpenDataDir(newDataDirConfig(path, "test"), raft.MemberID("bar"))
+	assert.Error(t, err)
+	_, err = OpenDataDir(newDataDirConfig(path, "other"), raft.MemberID("foo"))
+	assert.Error(t, err)
+
+	// Verify a non-empty directory without an identity is rejected
+	foreign := filepath.Join(root, "foreign")
+	assert.NoError(t, os.MkdirAll(foreign, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(foreign, "data"), []byte("bar"), 0644))
+	_, err = OpenDataDir(newDataDirConfig(foreign, "test"), raft.MemberID("foo"))
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(foreign, identityFile))
+	assert.True(t, os.IsNotExist(err))
+
+	// Verify a data directory must be configured
+	_, err = OpenDataDir(&config.ProtocolConfig{}, raft.MemberID("foo"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/util"
+	"sync"
+	"time"
+)
+
+// FileSystem provides information about the file system in which the store resides
+type FileSystem interface {
+	// FreeSpace returns the number of bytes available in the file system containing the given path
+	FreeSpace(path string) (uint64, error)
+}
+
+// NewFileSystem returns the local file system
+func NewFileSystem() FileSystem {
+	return &localFileSystem{}
+}
+
+// NewDiskMonitoredStore returns a store that rejects writes when the storage directory is low on space
+// Writes are rejected once the free space in the configured storage directory, or the data directory if no storage
+// directory is configured, drops below the configured minimum and resume once space is freed, e.g. by compaction.
+// If no directory or minimum is configured, the returned store never rejects writes. The free space is read at most
+// once per configured disk check interval, so writes within the interval share a reading.
+func NewDiskMonitoredStore(store Store, fs FileSystem, config *config.ProtocolConfig, member raft.MemberID) Store {
+	directory := config.GetStorage().GetDirectory()
+	if directory == "" {
+		directory = config.GetStorage().GetDataDir()
+	}
+	return &diskMonitoredStore{
+		Store:     store,
+		fs:        fs,
+		directory: directory,
+		minFree:   config.GetStorage().GetMinFreeDiskSpace(),
+		interval:  config.GetDiskCheckIntervalOrDefault(),
+		log:       util.NewNodeLogger(string(member)),
+	}
+}
+
+// diskMonitoredStore is a store that checks the free space in the storage directory
+type diskMonitoredStore struct {
+	Store
+	fs        FileSystem
+	directory string
+	minFree   uint64
+	interval  time.Duration
+	log       util.Logger
+	full      bool
+	checked   time.Time
+	mu        sync.Mutex
+}
+
+func (s *diskMonitoredStore) CheckDiskSpace() error {
+	if s.directory == "" || s.minFree == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Reuse the last reading of the free space until the check interval has elapsed.
+	if s.interval > 0 && !s.checked.IsZero() && time.Since(s.checked) < s.interval {
+		if s.full {
+			return raft.ErrDiskFull
+		}
+		return nil
+	}
+
+	free, err := s.fs.FreeSpace(s.directory)
+	if err != nil {
+		// If the free space cannot be determined, do not block writes
+		s.log.Debug("Failed to determine free space in %s: %v", s.directory, err)
+		return nil
+	}
+	s.checked = time.Now()
+
+	if free < s.minFree {
+		if !s.full {
+			s.log.Warn("Free space in %s (%d bytes) is below the minimum (%d bytes); rejecting writes", s.directory, free, s.minFree)
+			s.full = true
+		}
+		return raft.ErrDiskFull
+	}
+	if s.full {
+		s.log.Info("Free space in %s (%d bytes) has been restored; resuming writes", s.directory, free)
+		s.full = false
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"errors"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiskMonitoredStore(t *testing.T) {
+	fs := &testFileSystem{free: 2048}
+	diskCheckInterval := time.Duration(0)
+	store := NewDiskMonitoredStore(NewMemoryStore(), fs, &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			Directory:         "/data",
+			MinFreeDiskSpace:  1024,
+			DiskCheckInterval: &diskCheckInterval,
+		},
+	}, raft.MemberID("foo"))
+	assert.NoError(t, store.CheckDiskSpace())
+
+	// Verify writes are rejected once free space drops below the minimum
+	fs.setFree(512)
+	assert.Equal(t, raft.ErrDiskFull, store.CheckDiskSpace())
+	assert.Equal(t, raft.ErrDiskFull, store.CheckDiskSpace())
+
+	// Verify writes resume once space is freed
+	fs.setFree(4096)
+	assert.NoError(t, store.CheckDiskSpace())
+
+	// Verify writes are not blocked if the free space cannot be determined
+	fs.setFree(512)
+	fs.err = errors.New("statfs failed")
+	assert.NoError(t, store.CheckDiskSpace())
+}
+
+func TestDiskMonitoredStoreCheckInterval(t *testing.T) {
+	fs := &testFileSystem{free: 2048}
+	diskCheckInterval := 100 * time.Millisecond
+	store := NewDiskMonitoredStore(NewMemoryStore(), fs, &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			Directory:         "/data",
+			MinFreeDiskSpace:  1024,
+			DiskCheckInterval: &diskCheckInterval,
+		},
+	}, raft.MemberID("foo"))
+	assert.NoError(t, store.CheckDiskSpace())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fs.reads))
+
+	// Verify the free space is not read again until the check interval has elapsed
+	fs.setFree(512)
+	assert.NoError(t, store.CheckDiskSpace())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fs.reads))
+	time.Sleep(diskCheckInterval)
+	assert.Equal(t, raft.ErrDiskFull, store.CheckDiskSpace())
+	assert.Equal(t, raft.ErrDiskFull, store.CheckDiskSpace())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fs.reads))
+}
+
+func TestDiskMonitoredDataDir(t *testing.T) {
+	fs := &testFileSystem{free: 512}
+	store := NewDiskMonitoredStore(NewMemoryStore(), fs, &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			DataDir:          "/data",
+			MinFreeDiskSpace: 1024,
+		},
+	}, raft.MemberID("foo"))
+	assert.Equal(t, raft.ErrDiskFull, store.CheckDiskSpace())
+}
+
+func TestDiskMonitoredStoreDisabled(t *testing.T) {
+	fs := &testFileSystem{free: 0}
+	store := NewDiskMonitoredStore(NewMemoryStore(), fs, &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			Directory: "/data",
+		},
+	}, raft.MemberID("foo"))
+	assert.NoError(t, store.CheckDiskSpace())
+
+	store = NewDiskMonitoredStore(NewMemoryStore(), fs, &config.ProtocolConfig{
+		Storage: &config.StorageConfig{
+			MinFreeDiskSpace: 1024,
+		},
+	}, raft.MemberID("foo"))
+	assert.NoError(t, store.CheckDiskSpace())
+}
+
+func TestLocalFileSystem(t *testing.T) {
+	free, err := NewFileSystem().FreeSpace(".")
+	assert.NoError(t, err)
+	assert.True(t, free > 0)
+}
+
+// testFileSystem is a FileSystem reporting a configurable amount of free space
+type testFileSystem struct {
+	free  uint64
+	err   error
+	reads int32
+}
+
+func (f *testFileSystem) setFree(free uint64) {
+	atomic.StoreUint64(&f.free, free)
+}
+
+func (f *testFileSystem) FreeSpace(path string) (uint64, error) {
+	atomic.AddInt32(&f.reads, 1)
+	if f.err != nil {
+		return 0, f.err
+	}
+	return atomic.LoadUint64(&f.free), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+)
+
+// Digest returns a digest of the entries in the log from the first through the last index
+// The digest is a SHA-256 hash of the index and contents of each entry in order, so two logs produce the same digest
+// for a range only if they contain identical entries in the range. An error is returned if any entry in the range
+// is not in the log.
+func Digest(log Log, first, last raft.Index) ([]byte, error) {
+	if first == 0 || first > last {
+		return nil, fmt.Errorf("invalid range %d-%d", first, last)
+	}
+	reader := log.OpenReader(first)
+	defer reader.Close()
+	if first < reader.FirstIndex() || last > reader.LastIndex() {
+		return nil, fmt.Errorf("range %d-%d is not in the log %d-%d", first, last, reader.FirstIndex(), reader.LastIndex())
+	}
+	reader.Reset(first)
+
+	hash := sha256.New()
+	index := make([]byte, 8)
+	for i := first; i <= last; i++ {
+		entry := reader.NextEntry()
+		if err := reader.Err(); err != nil {
+			return nil, err
+		} else if entry == nil || entry.Index != i {
+			return nil, fmt.Errorf("entry %d is not in the log", i)
+		}
+		bytes, err := entry.Entry.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(index, uint64(i))
+		hash.Write(index)
+		hash.Write(bytes)
+	}
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDigest(t *testing.T) {
+	source := newExportTestLog()
+	target := NewMemoryLog()
+	reader := source.OpenReader(0)
+	for entry := reader.NextEntry(); entry != nil; entry = reader.NextEntry() {
+		target.Writer().Append(entry.Entry)
+	}
+
+	// Verify logs with identical entries produce identical digests
+	expected, err := Digest(source, 1, 10)
+	assert.NoError(t, err)
+	actual, err := Digest(target, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	partial, err := Digest(source, 1, 9)
+	assert.NoError(t, err)
+	assert.NotEqual(t, expected, partial)
+
+	// Replace the last entry with a different entry and verify only digests including the entry differ
+	target.Writer().Truncate(9)
+	target.Writer().Append(&raft.LogEntry{
+		Term:      3,
+		Timestamp: reader.CurrentEntry().Entry.Timestamp,
+		Entry:     &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte("diverged")}},
+	})
+	actual, err = Digest(target, 1, 10)
+	assert.NoError(t, err)
+	assert.NotEqual(t, expected, actual)
+	actual, err = Digest(target, 1, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, partial, actual)
+
+	// Verify ranges that are not in the log are rejected
+	_, err = Digest(source, 1, 11)
+	assert.Error(t, err)
+	source.Writer().Compact(5)
+	_, err = Digest(source, 1, 10)
+	assert.Error(t, err)
+	_, err = Digest(source, 5, 10)
+	assert.NoError(t, err)
+}
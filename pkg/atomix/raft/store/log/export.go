@@ -0,0 +1,184 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+const (
+	// exportFormat identifies exported Raft logs
+	exportFormat = "raft-log"
+	// exportVersion is the version of the export format
+	exportVersion = 1
+)
+
+// exportHeader is the first record of an exported log
+type exportHeader struct {
+	Format     string     `json:"format"`
+	Version    int        `json:"version"`
+	FirstIndex raft.Index `json:"firstIndex"`
+	LastIndex  raft.Index `json:"lastIndex"`
+}
+
+// exportEntry is an entry record of an exported log
+// The payload is the serialized raft.LogEntry, and the checksum is the CRC32 checksum of the payload.
+type exportEntry struct {
+	Index     raft.Index `json:"index"`
+	Term      raft.Term  `json:"term"`
+	Type      string     `json:"type"`
+	Timestamp time.Time  `json:"timestamp"`
+	Checksum  uint32     `json:"checksum"`
+	Payload   []byte     `json:"payload"`
+}
+
+// entryType returns the name of the type of the given entry
+func entryType(entry *raft.LogEntry) string {
+	switch entry.Entry.(type) {
+	case *raft.LogEntry_Initialize:
+		return "initialize"
+	case *raft.LogEntry_Configuration:
+		return "configuration"
+	case *raft.LogEntry_Command:
+		return "command"
+	case *raft.LogEntry_Query:
+		return "query"
+	case *raft.LogEntry_Barrier:
+		return "barrier"
+	}
+	return "unknown"
+}
+
+// ExportLog writes the entries in the given range of the log to the given writer in a portable format
+// The export is a stream of JSON records: a header describing the exported range followed by one record per entry
+// with the entry's index, term, type, timestamp, serialized payload, and the payload's checksum. The range is
+// limited to the entries currently in the log.
+func ExportLog(log Log, w io.Writer, from, to raft.Index) error {
+	reader := log.OpenReader(0)
+	defer reader.Close()
+	if first := reader.FirstIndex(); from < first {
+		from = first
+	}
+	if last := reader.LastIndex(); to > last {
+		to = last
+	}
+	if from > to {
+		return fmt.Errorf("no entries in range %d-%d", from, to)
+	}
+
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(&exportHeader{
+		Format:     exportFormat,
+		Version:    exportVersion,
+		FirstIndex: from,
+		LastIndex:  to,
+	})
+	if err != nil {
+		return err
+	}
+
+	reader.Reset(from)
+	for index := from; index <= to; index++ {
+		entry := reader.NextEntry()
+		if err := reader.Err(); err != nil {
+			return err
+		} else if entry == nil || entry.Index != index {
+			return fmt.Errorf("failed to read entry %d", index)
+		}
+		payload, err := entry.Entry.Marshal()
+		if err != nil {
+			return err
+		}
+		err = encoder.Encode(&exportEntry{
+			Index:     entry.Index,
+			Term:      entry.Entry.Term,
+			Type:      entryType(entry.Entry),
+			Timestamp: entry.Entry.Timestamp,
+			Checksum:  crc32.ChecksumIEEE(payload),
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportLog appends the entries exported by ExportLog from the given reader to the log
+// Each entry is validated against its checksum and term, and the entries must be contiguous with each other and
+// with the end of the log. If the log is empty, it's reset to begin at the first exported index. The number of
+// entries imported is returned; entries preceding an invalid entry are imported.
+func ImportLog(log Log, r io.Reader) (int, error) {
+	decoder := json.NewDecoder(r)
+	header := &exportHeader{}
+	if err := decoder.Decode(header); err != nil {
+		return 0, err
+	}
+	if header.Format != exportFormat || header.Version != exportVersion {
+		return 0, fmt.Errorf("unsupported log format %s version %d", header.Format, header.Version)
+	}
+
+	writer := log.Writer()
+	reader := log.OpenReader(0)
+	defer reader.Close()
+	if reader.LastIndex() < reader.FirstIndex() {
+		writer.Reset(header.FirstIndex)
+	} else if next := writer.LastIndex() + 1; header.FirstIndex != next {
+		return 0, fmt.Errorf("exported entries begin at index %d, but the next index in the log is %d", header.FirstIndex, next)
+	}
+
+	var lastTerm raft.Term
+	if last := writer.LastEntry(); last != nil {
+		lastTerm = last.Entry.Term
+	}
+
+	count := 0
+	for index := header.FirstIndex; index <= header.LastIndex; index++ {
+		record := &exportEntry{}
+		if err := decoder.Decode(record); err == io.EOF {
+			return count, fmt.Errorf("export ended at index %d, expected entries through index %d", index-1, header.LastIndex)
+		} else if err != nil {
+			return count, err
+		}
+		if record.Index != index {
+			return count, fmt.Errorf("expected entry %d, found entry %d", index, record.Index)
+		}
+		if crc32.ChecksumIEEE(record.Payload) != record.Checksum {
+			return count, fmt.Errorf("checksum mismatch for entry %d", index)
+		}
+		entry := &raft.LogEntry{}
+		if err := entry.Unmarshal(record.Payload); err != nil {
+			return count, err
+		}
+		if entry.Term != record.Term {
+			return count, fmt.Errorf("term mismatch for entry %d", index)
+		}
+		if entry.Term < lastTerm {
+			return count, fmt.Errorf("entry %d term %d precedes the prior entry's term %d", index, entry.Term, lastTerm)
+		}
+		if indexed := writer.Append(entry); indexed.Index != index {
+			return count, errors.New("log was modified during import")
+		}
+		lastTerm = entry.Term
+		count++
+	}
+	return count, nil
+}
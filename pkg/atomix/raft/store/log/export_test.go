@@ -0,0 +1,106 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExportTestLog() Log {
+	log := NewMemoryLog()
+	writer := log.Writer()
+	writer.Append(&raft.LogEntry{
+		Term:      1,
+		Timestamp: time.Now(),
+		Entry:     &raft.LogEntry_Initialize{Initialize: &raft.InitializeEntry{}},
+	})
+	for i := 2; i <= 10; i++ {
+		writer.Append(&raft.LogEntry{
+			Term:      raft.Term(i/5 + 1),
+			Timestamp: time.Now(),
+			Entry:     &raft.LogEntry_Command{Command: &raft.CommandEntry{Value: []byte(fmt.Sprintf("command-%d", i))}},
+		})
+	}
+	return log
+}
+
+func TestExportImportLog(t *testing.T) {
+	source := newExportTestLog()
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ExportLog(source, buf, 4, 8))
+	assert.Contains(t, buf.String(), `"type":"command"`)
+
+	// Import the exported range into an empty log and verify the entries match the source
+	target := NewMemoryLog()
+	count, err := ImportLog(target, bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	sourceReader := source.OpenReader(4)
+	targetReader := target.OpenReader(0)
+	assert.Equal(t, raft.Index(4), targetReader.FirstIndex())
+	assert.Equal(t, raft.Index(8), targetReader.LastIndex())
+	for i := raft.Index(4); i <= 8; i++ {
+		expected := sourceReader.NextEntry()
+		actual := targetReader.NextEntry()
+		assert.Equal(t, expected.Index, actual.Index)
+		assert.Equal(t, expected.Entry.Term, actual.Entry.Term)
+		assert.Equal(t, expected.Entry.GetCommand().Value, actual.Entry.GetCommand().Value)
+		assert.True(t, expected.Entry.Timestamp.Equal(actual.Entry.Timestamp))
+	}
+
+	// Verify a contiguous range can be appended to the imported log
+	buf.Reset()
+	assert.NoError(t, ExportLog(source, buf, 9, 100))
+	count, err = ImportLog(target, bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, raft.Index(10), target.Writer().LastIndex())
+
+	// Verify a range that is not contiguous with the end of the log is rejected
+	buf.Reset()
+	assert.NoError(t, ExportLog(source, buf, 2, 5))
+	count, err = ImportLog(target, bytes.NewReader(buf.Bytes()))
+	assert.Error(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, raft.Index(10), target.Writer().LastIndex())
+}
+
+func TestImportLogCorruption(t *testing.T) {
+	source := newExportTestLog()
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ExportLog(source, buf, 1, 10))
+
+	// Corrupt the checksum of the fourth entry and verify only the preceding entries are imported
+	lines := strings.Split(buf.String(), "\n")
+	lines[4] = strings.Replace(lines[4], `"checksum":`, `"checksum":1`, 1)
+	target := NewMemoryLog()
+	count, err := ImportLog(target, strings.NewReader(strings.Join(lines, "\n")))
+	assert.Error(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, raft.Index(3), target.Writer().LastIndex())
+
+	// Verify a truncated export is rejected
+	target = NewMemoryLog()
+	count, err = ImportLog(target, strings.NewReader(strings.Join(lines[:3], "\n")))
+	assert.Error(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -15,8 +15,11 @@
 package log
 
 import (
+	"fmt"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"hash/crc32"
 	"io"
+	"sync"
 )
 
 // NewMemoryLog creates a new in-memory Log
@@ -33,6 +36,9 @@ func NewMemoryLog() Log {
 }
 
 // Log provides for reading and writing entries in the Raft log
+// The consensus protocol accesses the log only through this interface, so an alternative storage backend can be used
+// by creating a store with it. NewMemoryLog returns the default backend, and the logtest package provides conformance
+// tests for implementations.
 type Log interface {
 	io.Closer
 
@@ -61,6 +67,9 @@ type Writer interface {
 
 	// Truncate truncates the tail of the log to the given index
 	Truncate(index raft.Index)
+
+	// Compact compacts the head of the log, discarding entries prior to the given index
+	Compact(index raft.Index)
 }
 
 // Reader supports reading of entries from the Raft log
@@ -83,16 +92,64 @@ type Reader interface {
 	NextIndex() raft.Index
 
 	// NextEntry advances the log index and returns the next entry in the log
+	// Each entry is verified against the checksum computed when it was written. If the next entry is corrupt,
+	// the reader is not advanced and nil is returned, and Err returns a *CorruptEntryError identifying the entry.
 	NextEntry() *Entry
 
+	// Err returns the error that prevented the last call to NextEntry from returning an entry, or nil
+	Err() error
+
 	// Reset resets the log reader to the given index
 	Reset(index raft.Index)
 }
 
+// CorruptEntryError is returned by Reader.Err when an entry does not match the checksum computed when it was written
+type CorruptEntryError struct {
+	Index raft.Index
+}
+
+func (e *CorruptEntryError) Error() string {
+	return fmt.Sprintf("log entry %d is corrupt", e.Index)
+}
+
 // Entry is an indexed Raft log entry
 type Entry struct {
 	Index raft.Index
 	Entry *raft.LogEntry
+	// record is the serialized entry as it was written to the log
+	record []byte
+	// checksum is the CRC32 checksum of the record
+	checksum uint32
+}
+
+// newEntry returns a new Entry, serializing the entry once and computing the checksum of the serialized bytes
+func newEntry(index raft.Index, entry *raft.LogEntry) *Entry {
+	record, _ := entry.Marshal()
+	return &Entry{
+		Index:    index,
+		Entry:    entry,
+		record:   record,
+		checksum: crc32.ChecksumIEEE(record),
+	}
+}
+
+// verify verifies the entry's record against the checksum computed when it was written
+// The returned entry is decoded from the verified record, so readers never observe entry data that was not checked.
+// If the record is corrupt, nil is returned.
+func (e *Entry) verify() *Entry {
+	if crc32.ChecksumIEEE(e.record) != e.checksum {
+		return nil
+	}
+	entry := &raft.LogEntry{}
+	if err := entry.Unmarshal(e.record); err != nil {
+		return nil
+	}
+	return &Entry{
+		Index:    e.Index,
+		Entry:    entry,
+		record:   e.record,
+		checksum: e.checksum,
+	}
 }
 
 type memoryLog struct {
@@ -100,6 +157,7 @@ type memoryLog struct {
 	firstIndex raft.Index
 	writer     *memoryWriter
 	readers    []*memoryReader
+	mu         sync.RWMutex
 }
 
 func (l *memoryLog) Writer() Writer {
@@ -107,6 +165,8 @@ func (l *memoryLog) Writer() Writer {
 }
 
 func (l *memoryLog) OpenReader(index raft.Index) Reader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	readerIndex := -1
 	for i := 0; i < len(l.entries); i++ {
 		if l.entries[i].Index == index {
@@ -131,13 +191,21 @@ type memoryWriter struct {
 }
 
 func (w *memoryWriter) LastIndex() raft.Index {
-	if entry := w.LastEntry(); entry != nil {
+	w.log.mu.RLock()
+	defer w.log.mu.RUnlock()
+	if entry := w.lastEntry(); entry != nil {
 		return entry.Index
 	}
 	return w.log.firstIndex - 1
 }
 
 func (w *memoryWriter) LastEntry() *Entry {
+	w.log.mu.RLock()
+	defer w.log.mu.RUnlock()
+	return w.lastEntry()
+}
+
+func (w *memoryWriter) lastEntry() *Entry {
 	if len(w.log.entries) == 0 {
 		return nil
 	}
@@ -152,15 +220,16 @@ func (w *memoryWriter) nextIndex() raft.Index {
 }
 
 func (w *memoryWriter) Append(entry *raft.LogEntry) *Entry {
-	indexed := &Entry{
-		Index: w.nextIndex(),
-		Entry: entry,
-	}
+	w.log.mu.Lock()
+	defer w.log.mu.Unlock()
+	indexed := newEntry(w.nextIndex(), entry)
 	w.log.entries = append(w.log.entries, indexed)
 	return indexed
 }
 
 func (w *memoryWriter) Reset(index raft.Index) {
+	w.log.mu.Lock()
+	defer w.log.mu.Unlock()
 	w.log.entries = w.log.entries[:0]
 	w.log.firstIndex = index
 	for _, reader := range w.log.readers {
@@ -169,6 +238,8 @@ func (w *memoryWriter) Reset(index raft.Index) {
 }
 
 func (w *memoryWriter) Truncate(index raft.Index) {
+	w.log.mu.Lock()
+	defer w.log.mu.Unlock()
 	for i := 0; i < len(w.log.entries); i++ {
 		if w.log.entries[i].Index > index {
 			w.log.entries = w.log.entries[:i]
@@ -180,20 +251,50 @@ func (w *memoryWriter) Truncate(index raft.Index) {
 	}
 }
 
+func (w *memoryWriter) Compact(index raft.Index) {
+	w.log.mu.Lock()
+	defer w.log.mu.Unlock()
+	if index <= w.log.firstIndex {
+		return
+	}
+
+	// Never compact beyond the end of the log
+	if lastIndex := w.nextIndex(); index > lastIndex {
+		index = lastIndex
+	}
+
+	count := 0
+	for count < len(w.log.entries) && w.log.entries[count].Index < index {
+		count++
+	}
+	w.log.entries = w.log.entries[count:]
+	w.log.firstIndex = index
+	for _, reader := range w.log.readers {
+		reader.compact(count)
+	}
+}
+
 func (w *memoryWriter) Close() error {
-	panic("implement me")
+	return nil
 }
 
+// memoryReader is a reader of the in-memory log
+// A reader is not safe for concurrent use, but separate readers may read the log concurrently.
 type memoryReader struct {
 	log   *memoryLog
 	index int
+	err   error
 }
 
 func (r *memoryReader) FirstIndex() raft.Index {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	return r.log.firstIndex
 }
 
 func (r *memoryReader) LastIndex() raft.Index {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	if len(r.log.entries) == 0 {
 		return r.log.firstIndex - 1
 	}
@@ -201,6 +302,8 @@ func (r *memoryReader) LastIndex() raft.Index {
 }
 
 func (r *memoryReader) CurrentIndex() raft.Index {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	if r.index == -1 || len(r.log.entries) == 0 {
 		return r.log.firstIndex - 1
 	}
@@ -208,6 +311,8 @@ func (r *memoryReader) CurrentIndex() raft.Index {
 }
 
 func (r *memoryReader) CurrentEntry() *Entry {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	if r.index == -1 || len(r.log.entries) == 0 {
 		return nil
 	}
@@ -215,6 +320,8 @@ func (r *memoryReader) CurrentEntry() *Entry {
 }
 
 func (r *memoryReader) NextIndex() raft.Index {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	if r.index == -1 || len(r.log.entries) == 0 {
 		return r.log.firstIndex
 	}
@@ -222,14 +329,29 @@ func (r *memoryReader) NextIndex() raft.Index {
 }
 
 func (r *memoryReader) NextEntry() *Entry {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
+	r.err = nil
 	if len(r.log.entries) > r.index+1 {
+		stored := r.log.entries[r.index+1]
+		entry := stored.verify()
+		if entry == nil {
+			r.err = &CorruptEntryError{Index: stored.Index}
+			return nil
+		}
 		r.index++
-		return r.log.entries[r.index]
+		return entry
 	}
 	return nil
 }
 
+func (r *memoryReader) Err() error {
+	return r.err
+}
+
 func (r *memoryReader) Reset(index raft.Index) {
+	r.log.mu.RLock()
+	defer r.log.mu.RUnlock()
 	for i := 0; i < len(r.log.entries); i++ {
 		if r.log.entries[i].Index >= index {
 			r.index = i - 1
@@ -244,6 +366,21 @@ func (r *memoryReader) maybeReset() {
 	}
 }
 
+func (r *memoryReader) compact(count int) {
+	r.index -= count
+	if r.index < -1 {
+		r.index = -1
+	}
+}
+
 func (r *memoryReader) Close() error {
+	r.log.mu.Lock()
+	defer r.log.mu.Unlock()
+	for i, reader := range r.log.readers {
+		if reader == r {
+			r.log.readers = append(r.log.readers[:i], r.log.readers[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
@@ -123,3 +123,119 @@ func TestMemoryLog(t *testing.T) {
 	assert.Equal(t, raft.Index(10), reader.NextIndex())
 	assert.Nil(t, reader.NextEntry())
 }
+
+func TestMemoryLogCompact(t *testing.T) {
+	log := NewMemoryLog()
+	writer := log.Writer()
+	reader := log.OpenReader(0)
+
+	for i := 0; i < 5; i++ {
+		writer.Append(&raft.LogEntry{
+			Term:      1,
+			Timestamp: time.Now(),
+			Entry:     &raft.LogEntry_Initialize{},
+		})
+	}
+
+	assert.Equal(t, raft.Index(1), reader.NextEntry().Index)
+	assert.Equal(t, raft.Index(2), reader.NextEntry().Index)
+	assert.Equal(t, raft.Index(3), reader.NextEntry().Index)
+
+	writer.Compact(3)
+	assert.Equal(t, raft.Index(3), reader.FirstIndex())
+	assert.Equal(t, raft.Index(5), reader.LastIndex())
+	assert.Equal(t, raft.Index(3), reader.CurrentIndex())
+	assert.Equal(t, raft.Index(4), reader.NextEntry().Index)
+
+	reader.Reset(3)
+	assert.Equal(t, raft.Index(3), reader.NextEntry().Index)
+
+	// Compacting to a prior index is a no-op
+	writer.Compact(2)
+	assert.Equal(t, raft.Index(3), reader.FirstIndex())
+
+	// Compacting beyond the end of the log leaves the log empty
+	writer.Compact(10)
+	assert.Equal(t, raft.Index(6), reader.FirstIndex())
+	assert.Equal(t, raft.Index(5), writer.LastIndex())
+	assert.Nil(t, reader.NextEntry())
+
+	entry := writer.Append(&raft.LogEntry{
+		Term:      2,
+		Timestamp: time.Now(),
+		Entry:     &raft.LogEntry_Initialize{},
+	})
+	assert.Equal(t, raft.Index(6), entry.Index)
+	assert.Equal(t, raft.Index(6), reader.NextEntry().Index)
+}
+
+func TestMemoryLogCloseReader(t *testing.T) {
+	log := NewMemoryLog()
+	reader1 := log.OpenReader(0)
+	reader2 := log.OpenReader(0)
+	assert.Len(t, log.(*memoryLog).readers, 2)
+
+	assert.NoError(t, reader1.Close())
+	assert.Len(t, log.(*memoryLog).readers, 1)
+	assert.Equal(t, reader2, log.(*memoryLog).readers[0])
+
+	assert.NoError(t, reader2.Close())
+	assert.Len(t, log.(*memoryLog).readers, 0)
+}
+
+func TestMemoryLogCorruption(t *testing.T) {
+	log := NewMemoryLog()
+	writer := log.Writer()
+	reader := log.OpenReader(0)
+
+	entries := make([]*Entry, 3)
+	for i := 0; i < 3; i++ {
+		entries[i] = writer.Append(&raft.LogEntry{
+			Term:      1,
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Command{
+				Command: &raft.CommandEntry{
+					Value: []byte("Hello world!"),
+				},
+			},
+		})
+	}
+
+	// Flip a byte in the second stored entry
+	entries[1].record[len(entries[1].record)-1] ^= 0xff
+
+	// Change the first stored entry without changing its record, and verify the entry read is decoded from the record
+	entries[0].Entry.GetCommand().Value = []byte("Goodbye world!")
+	first := reader.NextEntry()
+	assert.Equal(t, raft.Index(1), first.Index)
+	assert.Equal(t, []byte("Hello world!"), first.Entry.GetCommand().Value)
+	assert.NoError(t, reader.Err())
+
+	// Verify the reader stops at the corrupt entry rather than returning bad data, and reports the corruption
+	assert.Nil(t, reader.NextEntry())
+	assert.Equal(t, &CorruptEntryError{Index: 2}, reader.Err())
+	assert.Equal(t, raft.Index(1), reader.CurrentIndex())
+
+	// Verify the corrupt entry is left in the log
+	assert.Equal(t, raft.Index(3), writer.LastIndex())
+	assert.Nil(t, reader.NextEntry())
+	assert.Error(t, reader.Err())
+
+	// Verify the entry can be read once it's replaced
+	writer.Truncate(1)
+	entry := writer.Append(&raft.LogEntry{
+		Term:      2,
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte("Hello world!"),
+			},
+		},
+	})
+	assert.Equal(t, raft.Index(2), entry.Index)
+	entry = reader.NextEntry()
+	assert.NoError(t, reader.Err())
+	assert.NotNil(t, entry)
+	assert.Equal(t, raft.Index(2), entry.Index)
+	assert.Equal(t, "Hello world!", string(entry.Entry.GetCommand().Value))
+}
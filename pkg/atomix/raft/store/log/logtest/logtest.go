@@ -0,0 +1,271 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logtest provides conformance tests for implementations of the Raft log.
+package logtest
+
+import (
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// TestLog tests the semantics the consensus protocol depends on against a log implementation
+// The newLog function must return a new, empty log each time it's called. Each log is closed at the end of the test
+// that created it.
+func TestLog(t *testing.T, newLog func() log.Log) {
+	tests := []struct {
+		name string
+		test func(*testing.T, log.Log)
+	}{
+		{"Empty", testEmpty},
+		{"Append", testAppend},
+		{"Read", testRead},
+		{"ReaderReset", testReaderReset},
+		{"Truncate", testTruncate},
+		{"Reset", testReset},
+		{"Compact", testCompact},
+		{"ConcurrentReaders", testConcurrentReaders},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			l := newLog()
+			defer l.Close()
+			test.test(t, l)
+		})
+	}
+}
+
+// newEntry returns a new command entry in the given term
+func newEntry(term raft.Term, value string) *raft.LogEntry {
+	return &raft.LogEntry{
+		Term:      term,
+		Timestamp: time.Now(),
+		Entry: &raft.LogEntry_Command{
+			Command: &raft.CommandEntry{
+				Value: []byte(value),
+			},
+		},
+	}
+}
+
+// appendEntries appends entries with the given indexes to the log, each in a term equal to its index
+func appendEntries(l log.Log, first, last raft.Index) {
+	for i := first; i <= last; i++ {
+		l.Writer().Append(newEntry(raft.Term(i), fmt.Sprintf("entry-%d", i)))
+	}
+}
+
+// assertEntry asserts the given entry is the entry appended by appendEntries at the given index
+func assertEntry(t *testing.T, index raft.Index, entry *log.Entry) {
+	if assert.NotNil(t, entry, "expected entry %d", index) {
+		assert.Equal(t, index, entry.Index)
+		assert.Equal(t, raft.Term(index), entry.Entry.Term)
+		assert.Equal(t, fmt.Sprintf("entry-%d", index), string(entry.Entry.GetCommand().Value))
+	}
+}
+
+func testEmpty(t *testing.T, l log.Log) {
+	writer := l.Writer()
+	assert.Equal(t, raft.Index(0), writer.LastIndex())
+	assert.Nil(t, writer.LastEntry())
+
+	reader := l.OpenReader(0)
+	defer reader.Close()
+	assert.Equal(t, raft.Index(1), reader.FirstIndex())
+	assert.Equal(t, raft.Index(0), reader.LastIndex())
+	assert.Equal(t, raft.Index(0), reader.CurrentIndex())
+	assert.Nil(t, reader.CurrentEntry())
+	assert.Equal(t, raft.Index(1), reader.NextIndex())
+	assert.Nil(t, reader.NextEntry())
+}
+
+func testAppend(t *testing.T, l log.Log) {
+	writer := l.Writer()
+	for i := raft.Index(1); i <= 10; i++ {
+		entry := writer.Append(newEntry(raft.Term(i), fmt.Sprintf("entry-%d", i)))
+		assertEntry(t, i, entry)
+		assert.Equal(t, i, writer.LastIndex())
+		assertEntry(t, i, writer.LastEntry())
+	}
+}
+
+func testRead(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 5)
+
+	// Verify a reader opened at the start of the log reads every entry in order
+	reader := l.OpenReader(0)
+	defer reader.Close()
+	assert.Equal(t, raft.Index(1), reader.FirstIndex())
+	assert.Equal(t, raft.Index(5), reader.LastIndex())
+	for i := raft.Index(1); i <= 5; i++ {
+		assert.Equal(t, i, reader.NextIndex())
+		assertEntry(t, i, reader.NextEntry())
+		assert.NoError(t, reader.Err())
+		assert.Equal(t, i, reader.CurrentIndex())
+		assertEntry(t, i, reader.CurrentEntry())
+	}
+	assert.Equal(t, raft.Index(6), reader.NextIndex())
+	assert.Nil(t, reader.NextEntry())
+	assert.NoError(t, reader.Err())
+	assert.Equal(t, raft.Index(5), reader.CurrentIndex())
+
+	// Verify entries appended after the end of the log was reached are read
+	appendEntries(l, 6, 7)
+	assert.Equal(t, raft.Index(7), reader.LastIndex())
+	assertEntry(t, 6, reader.NextEntry())
+	assertEntry(t, 7, reader.NextEntry())
+	assert.Nil(t, reader.NextEntry())
+
+	// Verify a reader opened at an index reads from the index
+	offset := l.OpenReader(3)
+	defer offset.Close()
+	assert.Equal(t, raft.Index(3), offset.NextIndex())
+	assertEntry(t, 3, offset.NextEntry())
+}
+
+func testReaderReset(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 10)
+	reader := l.OpenReader(0)
+	defer reader.Close()
+
+	reader.Reset(5)
+	assert.Equal(t, raft.Index(5), reader.NextIndex())
+	assertEntry(t, 5, reader.NextEntry())
+
+	// Verify the reader can be reset backwards
+	reader.Reset(2)
+	assert.Equal(t, raft.Index(2), reader.NextIndex())
+	assertEntry(t, 2, reader.NextEntry())
+	assertEntry(t, 3, reader.NextEntry())
+}
+
+func testTruncate(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 10)
+	reader := l.OpenReader(0)
+	defer reader.Close()
+	for i := raft.Index(1); i <= 8; i++ {
+		reader.NextEntry()
+	}
+
+	// Verify truncating the tail of the log removes entries following the index and moves back readers past the index
+	writer := l.Writer()
+	writer.Truncate(5)
+	assert.Equal(t, raft.Index(5), writer.LastIndex())
+	assertEntry(t, 5, writer.LastEntry())
+	assert.Equal(t, raft.Index(5), reader.LastIndex())
+	assert.Equal(t, raft.Index(5), reader.CurrentIndex())
+	assert.Nil(t, reader.NextEntry())
+
+	// Verify entries appended to the truncated log replace the truncated entries
+	entry := writer.Append(newEntry(raft.Term(20), "replaced"))
+	assert.Equal(t, raft.Index(6), entry.Index)
+	entry = reader.NextEntry()
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, raft.Index(6), entry.Index)
+		assert.Equal(t, raft.Term(20), entry.Entry.Term)
+	}
+
+	// Verify truncating beyond the end of the log has no effect
+	writer.Truncate(10)
+	assert.Equal(t, raft.Index(6), writer.LastIndex())
+}
+
+func testReset(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 10)
+	reader := l.OpenReader(0)
+	defer reader.Close()
+	reader.NextEntry()
+
+	// Verify resetting the log discards all entries and starts the log at the index
+	writer := l.Writer()
+	writer.Reset(20)
+	assert.Equal(t, raft.Index(19), writer.LastIndex())
+	assert.Nil(t, writer.LastEntry())
+	assert.Equal(t, raft.Index(20), reader.FirstIndex())
+	assert.Equal(t, raft.Index(19), reader.LastIndex())
+	assert.Nil(t, reader.NextEntry())
+
+	// Verify entries are appended following the index
+	appendEntries(l, 20, 21)
+	assert.Equal(t, raft.Index(21), writer.LastIndex())
+	reader.Reset(20)
+	assertEntry(t, 20, reader.NextEntry())
+	assertEntry(t, 21, reader.NextEntry())
+}
+
+func testCompact(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 10)
+	reader := l.OpenReader(0)
+	defer reader.Close()
+	for i := raft.Index(1); i <= 6; i++ {
+		reader.NextEntry()
+	}
+
+	// Verify compacting the head of the log discards entries prior to the index without affecting readers
+	writer := l.Writer()
+	writer.Compact(5)
+	assert.Equal(t, raft.Index(5), reader.FirstIndex())
+	assert.Equal(t, raft.Index(10), reader.LastIndex())
+	assert.Equal(t, raft.Index(10), writer.LastIndex())
+	assert.Equal(t, raft.Index(6), reader.CurrentIndex())
+	assertEntry(t, 7, reader.NextEntry())
+
+	// Verify a reader opened after compaction starts at the first index
+	compacted := l.OpenReader(0)
+	defer compacted.Close()
+	assertEntry(t, 5, compacted.NextEntry())
+
+	// Verify compacting prior to the first index has no effect
+	writer.Compact(3)
+	assert.Equal(t, raft.Index(5), reader.FirstIndex())
+
+	// Verify compacting beyond the end of the log empties the log without changing the last index
+	writer.Compact(20)
+	assert.Equal(t, raft.Index(11), reader.FirstIndex())
+	assert.Equal(t, raft.Index(10), reader.LastIndex())
+	assert.Equal(t, raft.Index(10), writer.LastIndex())
+	entry := writer.Append(newEntry(raft.Term(11), "entry-11"))
+	assertEntry(t, 11, entry)
+}
+
+func testConcurrentReaders(t *testing.T, l log.Log) {
+	appendEntries(l, 1, 100)
+
+	// Verify readers read the log independently while it's written
+	done := make(chan raft.Index, 4)
+	for i := 0; i < cap(done); i++ {
+		go func() {
+			reader := l.OpenReader(0)
+			defer reader.Close()
+			var last raft.Index
+			for entry := reader.NextEntry(); entry != nil; entry = reader.NextEntry() {
+				if entry.Index != last+1 {
+					break
+				}
+				last = entry.Index
+			}
+			done <- last
+		}()
+	}
+	appendEntries(l, 101, 200)
+	for i := 0; i < cap(done); i++ {
+		last := <-done
+		assert.True(t, last >= 100, "reader stopped at entry %d", last)
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"fmt"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// snapshotPrefix is the prefix of the names of snapshot files
+	snapshotPrefix = "snapshot-"
+	// dataSuffix is the suffix of snapshot data files
+	dataSuffix = ".data"
+	// descriptorSuffix is the suffix of snapshot descriptor files
+	descriptorSuffix = ".meta"
+	// tmpSuffix is the suffix of files that have not been committed
+	tmpSuffix = ".tmp"
+)
+
+// NewFileStore returns a new snapshot store that persists snapshots to files
+// The store must be opened in a directory before it's used. Snapshots are written to temporary files that are only
+// promoted to a committed snapshot once the writer is closed. A snapshot is committed by durably renaming its data
+// file and then writing a descriptor recording its checksum, so a snapshot without a valid descriptor or whose data
+// does not match the descriptor's checksum was never committed and is discarded when the store is opened.
+func NewFileStore() *FileStore {
+	return &FileStore{
+		syncFile: func(file *os.File) error {
+			return file.Sync()
+		},
+	}
+}
+
+// FileStore is a Store that persists snapshots to files in a directory
+type FileStore struct {
+	dir             string
+	syncFile        func(*os.File) error
+	currentSnapshot *fileSnapshot
+	commitMu        sync.Mutex
+	mu              sync.RWMutex
+}
+
+// Open opens the store in the given directory
+// Uncommitted and corrupt snapshots are removed, and the latest committed snapshot becomes the current snapshot.
+func (s *FileStore) Open(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dir = dir
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var current *fileSnapshot
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, snapshotPrefix) {
+			continue
+		}
+		if strings.HasSuffix(name, tmpSuffix) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, descriptorSuffix) {
+			continue
+		}
+		snapshot, err := s.load(strings.TrimSuffix(name, descriptorSuffix))
+		if err != nil {
+			return err
+		}
+		if snapshot != nil && (current == nil || snapshot.Index() > current.Index()) {
+			current = snapshot
+		}
+	}
+
+	// Remove data files that were never committed along with snapshots preceding the current snapshot
+	files, err = ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, snapshotPrefix) && (current == nil || !strings.HasPrefix(name, current.name+".")) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	s.currentSnapshot = current
+	return nil
+}
+
+// load loads the committed snapshot with the given name, returning nil if the snapshot is invalid
+func (s *FileStore) load(name string) (*fileSnapshot, error) {
+	bytes, err := ioutil.ReadFile(filepath.Join(s.dir, name+descriptorSuffix))
+	if err != nil {
+		return nil, err
+	}
+	descriptor := &Descriptor{}
+	if err := descriptor.Unmarshal(bytes); err != nil {
+		return nil, nil
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, name+dataSuffix))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	checksum := crc32.NewIEEE()
+	if _, err := io.Copy(checksum, file); err != nil {
+		return nil, err
+	}
+	if checksum.Sum32() != descriptor.Checksum {
+		return nil, nil
+	}
+	return &fileSnapshot{
+		store:      s,
+		name:       name,
+		descriptor: descriptor,
+	}, nil
+}
+
+func (s *FileStore) NewSnapshot(index raft.Index, term raft.Term, timestamp time.Time, metadata []byte) Snapshot {
+	return &fileSnapshot{
+		store: s,
+		name:  fmt.Sprintf("%s%020d-%020d", snapshotPrefix, index, term),
+		descriptor: &Descriptor{
+			Index:     index,
+			Term:      term,
+			Timestamp: &timestamp,
+			Metadata:  metadata,
+		},
+	}
+}
+
+func (s *FileStore) CurrentSnapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.currentSnapshot == nil {
+		return nil
+	}
+	return s.currentSnapshot
+}
+
+// path returns the path of the given file in the store directory
+func (s *FileStore) path(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filepath.Join(s.dir, name)
+}
+
+// commit durably commits the given snapshot, whose data has been written to the given temporary file
+// Commits are serialized so concurrent writers of the same snapshot don't share the temporary descriptor file. If a
+// newer snapshot was committed while the snapshot was being written, the snapshot is superseded and removed.
+func (s *FileStore) commit(snapshot *fileSnapshot, tmp string) error {
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	data := s.path(snapshot.name + dataSuffix)
+	if err := os.Rename(tmp, data); err != nil {
+		return err
+	}
+	bytes, err := snapshot.descriptor.Marshal()
+	if err != nil {
+		return err
+	}
+	descriptor := s.path(snapshot.name + descriptorSuffix)
+	if err := s.writeFile(descriptor+tmpSuffix, bytes); err != nil {
+		return err
+	}
+	if err := os.Rename(descriptor+tmpSuffix, descriptor); err != nil {
+		return err
+	}
+	if err := s.syncDir(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.currentSnapshot == nil || snapshot.Index() >= s.currentSnapshot.Index() {
+		// Remove the previous snapshot once the new snapshot has been committed. Readers of the previous snapshot
+		// that have already opened its data file can continue reading it.
+		if previous := s.currentSnapshot; previous != nil && previous.name != snapshot.name {
+			_ = os.Remove(filepath.Join(s.dir, previous.name+descriptorSuffix))
+			_ = os.Remove(filepath.Join(s.dir, previous.name+dataSuffix))
+		}
+		s.currentSnapshot = snapshot
+	} else {
+		_ = os.Remove(descriptor)
+		_ = os.Remove(data)
+	}
+	return nil
+}
+
+// writeFile durably writes the given bytes to the file at the given path
+func (s *FileStore) writeFile(path string, bytes []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(bytes); err != nil {
+		file.Close()
+		return err
+	}
+	if err := s.syncFile(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir syncs the store directory to ensure renames are durable
+func (s *FileStore) syncDir() error {
+	dir, err := os.Open(s.path(""))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return s.syncFile(dir)
+}
+
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// fileSnapshot is a snapshot stored in a file
+type fileSnapshot struct {
+	store      *FileStore
+	name       string
+	descriptor *Descriptor
+}
+
+func (s *fileSnapshot) Index() raft.Index {
+	return s.descriptor.Index
+}
+
+func (s *fileSnapshot) Term() raft.Term {
+	return s.descriptor.Term
+}
+
+func (s *fileSnapshot) Timestamp() time.Time {
+	if s.descriptor.Timestamp == nil {
+		return time.Time{}
+	}
+	return *s.descriptor.Timestamp
+}
+
+func (s *fileSnapshot) Metadata() []byte {
+	return s.descriptor.Metadata
+}
+
+func (s *fileSnapshot) Reader() io.ReadCloser {
+	file, err := os.Open(s.store.path(s.name + dataSuffix))
+	if err != nil {
+		return &errorReader{err: err}
+	}
+	return file
+}
+
+func (s *fileSnapshot) Writer() Writer {
+	// Each writer writes to its own temporary file, so concurrent installs of the same snapshot can't interleave.
+	file, err := ioutil.TempFile(s.store.path(""), s.name+dataSuffix+".*"+tmpSuffix)
+	return &fileWriter{
+		snapshot: s,
+		file:     file,
+		err:      err,
+		checksum: crc32.NewIEEE(),
+	}
+}
+
+// fileWriter writes a snapshot to a temporary file
+type fileWriter struct {
+	snapshot *fileSnapshot
+	file     *os.File
+	err      error
+	checksum hash.Hash32
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.file.Write(p)
+	w.checksum.Write(p[:n])
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// Close durably writes the snapshot data and commits the snapshot to the store
+func (w *fileWriter) Close() error {
+	if w.err != nil {
+		_ = w.Abort()
+		return w.err
+	}
+	if err := w.snapshot.store.syncFile(w.file); err != nil {
+		_ = w.Abort()
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		_ = w.Abort()
+		return err
+	}
+	w.snapshot.descriptor.Checksum = w.checksum.Sum32()
+	return w.snapshot.store.commit(w.snapshot, w.file.Name())
+}
+
+// Abort closes and removes the temporary file
+func (w *fileWriter) Abort() error {
+	if w.file == nil {
+		return nil
+	}
+	_ = w.file.Close()
+	return os.Remove(w.file.Name())
+}
+
+// errorReader is a reader that fails with an error
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func (r *errorReader) Close() error {
+	return nil
+}
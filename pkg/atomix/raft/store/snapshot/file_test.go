@@ -0,0 +1,153 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readSnapshot(t *testing.T, snapshot Snapshot) string {
+	reader := snapshot.Reader()
+	defer reader.Close()
+	bytes, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(bytes)
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore()
+	assert.NoError(t, store.Open(dir))
+	assert.Nil(t, store.CurrentSnapshot())
+
+	// Verify a snapshot is not visible until its writer is closed
+	ts := time.Now()
+	snapshot := store.NewSnapshot(raft.Index(1), raft.Term(2), ts, nil)
+	writer := snapshot.Writer()
+	_, err = writer.Write([]byte("Hello world!"))
+	assert.NoError(t, err)
+	assert.Nil(t, store.CurrentSnapshot())
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, raft.Index(1), store.CurrentSnapshot().Index())
+	assert.Equal(t, "Hello world!", readSnapshot(t, store.CurrentSnapshot()))
+
+	// Verify an aborted snapshot is discarded
+	writer = store.NewSnapshot(raft.Index(2), raft.Term(2), ts, nil).Writer()
+	_, err = writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Abort())
+	assert.Equal(t, raft.Index(1), store.CurrentSnapshot().Index())
+
+	// Verify a newer snapshot replaces the current snapshot
+	writer = store.NewSnapshot(raft.Index(3), raft.Term(3), ts, []byte("v3")).Writer()
+	_, err = writer.Write([]byte("bar"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Verify the committed snapshot is recovered when the store is reopened
+	store = NewFileStore()
+	assert.NoError(t, store.Open(dir))
+	snapshot = store.CurrentSnapshot()
+	assert.Equal(t, raft.Index(3), snapshot.Index())
+	assert.Equal(t, raft.Term(3), snapshot.Term())
+	assert.True(t, ts.Equal(snapshot.Timestamp()))
+	assert.Equal(t, []byte("v3"), snapshot.Metadata())
+	assert.Equal(t, "bar", readSnapshot(t, snapshot))
+}
+
+func TestFileStoreRecovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore()
+	assert.NoError(t, store.Open(dir))
+	writer := store.NewSnapshot(raft.Index(1), raft.Term(1), time.Now(), nil).Writer()
+	_, err = writer.Write([]byte("foo"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Leave a partially written snapshot and a snapshot whose data does not match its checksum
+	writer = store.NewSnapshot(raft.Index(2), raft.Term(1), time.Now(), nil).Writer()
+	_, err = writer.Write([]byte("bar"))
+	assert.NoError(t, err)
+	writer = store.NewSnapshot(raft.Index(3), raft.Term(1), time.Now(), nil).Writer()
+	_, err = writer.Write([]byte("baz"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	corrupt := filepath.Join(dir, "snapshot-00000000000000000003-00000000000000000001.data")
+	assert.NoError(t, ioutil.WriteFile(corrupt, []byte("bad"), 0644))
+
+	// Verify the partial and corrupt snapshots are discarded when the store is reopened
+	store = NewFileStore()
+	assert.NoError(t, store.Open(dir))
+	assert.Nil(t, store.CurrentSnapshot())
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+}
+
+func TestFileStoreConcurrentWriters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileStore()
+	assert.NoError(t, store.Open(dir))
+
+	// Verify concurrent writers of the same snapshot don't interleave their data
+	ts := time.Now()
+	writer1 := store.NewSnapshot(raft.Index(1), raft.Term(1), ts, nil).Writer()
+	writer2 := store.NewSnapshot(raft.Index(1), raft.Term(1), ts, nil).Writer()
+	_, err = writer1.Write([]byte("foo"))
+	assert.NoError(t, err)
+	_, err = writer2.Write([]byte("foo"))
+	assert.NoError(t, err)
+	_, err = writer1.Write([]byte("bar"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer1.Close())
+	assert.Equal(t, "foobar", readSnapshot(t, store.CurrentSnapshot()))
+	_, err = writer2.Write([]byte("bar"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer2.Close())
+	assert.Equal(t, "foobar", readSnapshot(t, store.CurrentSnapshot()))
+
+	// Verify a snapshot superseded by a newer snapshot while it was being written is removed when it's committed
+	writer1 = store.NewSnapshot(raft.Index(2), raft.Term(1), ts, nil).Writer()
+	_, err = writer1.Write([]byte("baz"))
+	assert.NoError(t, err)
+	writer2 = store.NewSnapshot(raft.Index(3), raft.Term(1), ts, nil).Writer()
+	_, err = writer2.Write([]byte("qux"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer2.Close())
+	assert.NoError(t, writer1.Close())
+	assert.Equal(t, raft.Index(3), store.CurrentSnapshot().Index())
+	assert.Equal(t, "qux", readSnapshot(t, store.CurrentSnapshot()))
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bufio"
+	"io"
+)
+
+// NewRestoreReader returns a reader that reads the given snapshot in chunks of the given buffer size
+// State machines typically deserialize snapshots in many small reads. Buffering the snapshot reader serves
+// those reads from memory and reads the underlying snapshot in large chunks. If a progress function is provided,
+// it's called with the total number of bytes read from the snapshot after each chunk is read.
+func NewRestoreReader(snapshot Snapshot, bufferSize int, progress func(int64)) *RestoreReader {
+	source := &progressReader{
+		reader:   snapshot.Reader(),
+		progress: progress,
+	}
+	return &RestoreReader{
+		source: source,
+		reader: bufio.NewReaderSize(source, bufferSize),
+	}
+}
+
+// RestoreReader is a buffered snapshot reader used to restore a state machine
+type RestoreReader struct {
+	source *progressReader
+	reader *bufio.Reader
+}
+
+func (r *RestoreReader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
+
+// BytesRead returns the number of bytes read from the snapshot
+// Bytes are read from the snapshot a chunk at a time, so the count may include buffered bytes that have not yet
+// been returned by Read.
+func (r *RestoreReader) BytesRead() int64 {
+	return r.source.read
+}
+
+// Close closes the underlying snapshot reader
+func (r *RestoreReader) Close() error {
+	return r.source.reader.Close()
+}
+
+// progressReader counts the bytes read from a snapshot reader and reports them to a progress function
+type progressReader struct {
+	reader   io.ReadCloser
+	progress func(int64)
+	read     int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		if r.progress != nil {
+			r.progress(r.read)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,108 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"encoding/binary"
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+)
+
+// writeTestSnapshot writes a snapshot containing the given data to the given store
+func writeTestSnapshot(t testing.TB, store Store, data []byte) Snapshot {
+	snapshot := store.NewSnapshot(raft.Index(1), raft.Term(1), time.Now(), nil)
+	writer := snapshot.Writer()
+	_, err := writer.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return store.CurrentSnapshot()
+}
+
+func TestRestoreReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fileStore := NewFileStore()
+	assert.NoError(t, fileStore.Open(dir))
+
+	data := make([]byte, 100*1024+17)
+	rand.New(rand.NewSource(1)).Read(data)
+	for _, store := range []Store{NewMemoryStore(), fileStore} {
+		snapshot := writeTestSnapshot(t, store, data)
+		for _, bufferSize := range []int{16, 4096, 1024 * 1024} {
+			// Verify the restored bytes match the snapshot regardless of the buffer size
+			var progress []int64
+			reader := NewRestoreReader(snapshot, bufferSize, func(read int64) {
+				progress = append(progress, read)
+			})
+			bytes, err := ioutil.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, data, bytes)
+			assert.NoError(t, reader.Close())
+
+			// Verify progress is reported as the snapshot is read
+			assert.Equal(t, int64(len(data)), reader.BytesRead())
+			assert.True(t, len(progress) > 0)
+			assert.Equal(t, int64(len(data)), progress[len(progress)-1])
+			for i := 1; i < len(progress); i++ {
+				assert.True(t, progress[i] > progress[i-1])
+			}
+		}
+	}
+}
+
+// restoreRecordSize is the size of the records decoded by benchmarkRestore
+const restoreRecordSize = 8
+
+// benchmarkRestore benchmarks restoring a file snapshot of fixed size records with the given read buffer size
+func benchmarkRestore(b *testing.B, bufferSize int) {
+	dir, err := ioutil.TempDir("", "raft-snapshots")
+	assert.NoError(b, err)
+	defer os.RemoveAll(dir)
+	store := NewFileStore()
+	assert.NoError(b, store.Open(dir))
+	snapshot := writeTestSnapshot(b, store, make([]byte, 4*1024*1024))
+
+	b.SetBytes(4 * 1024 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader := NewRestoreReader(snapshot, bufferSize, nil)
+		record := make([]byte, restoreRecordSize)
+		var sum uint64
+		for {
+			if _, err := io.ReadFull(reader, record); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+			sum += binary.BigEndian.Uint64(record)
+		}
+		_ = reader.Close()
+	}
+}
+
+func BenchmarkRestoreSmallBuffer(b *testing.B) {
+	benchmarkRestore(b, 16)
+}
+
+func BenchmarkRestoreLargeBuffer(b *testing.B) {
+	benchmarkRestore(b, 1024*1024)
+}
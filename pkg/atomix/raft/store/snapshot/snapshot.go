@@ -18,6 +18,7 @@ import (
 	"bytes"
 	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"io"
+	"sync"
 	"time"
 )
 
@@ -30,8 +31,9 @@ func NewMemoryStore() Store {
 
 // Store is an interface for managing snapshots
 type Store interface {
-	// NewSnapshot creates a new snapshot
-	NewSnapshot(index raft.Index, timestamp time.Time) Snapshot
+	// NewSnapshot creates a new snapshot with the given application-defined metadata
+	// The snapshot becomes the current snapshot once its writer has been closed.
+	NewSnapshot(index raft.Index, term raft.Term, timestamp time.Time, metadata []byte) Snapshot
 
 	// CurrentSnapshot returns the current snapshot
 	CurrentSnapshot() Snapshot
@@ -45,44 +47,76 @@ type Snapshot interface {
 	// Index is the index at which the snapshot was taken
 	Index() raft.Index
 
+	// Term is the term of the entry at the snapshot index
+	Term() raft.Term
+
 	// Timestamp is the time at which the snapshot was taken
 	Timestamp() time.Time
 
+	// Metadata is the opaque application-defined metadata set when the snapshot was created
+	// The metadata can be read without reading the snapshot.
+	Metadata() []byte
+
 	// Reader returns a new snapshot reader
 	Reader() io.ReadCloser
 
 	// Writer returns a new snapshot writer
-	Writer() io.WriteCloser
+	Writer() Writer
+}
+
+// Writer is a snapshot writer
+// Closing the writer commits the snapshot to the store, and aborting the writer discards the snapshot.
+type Writer interface {
+	io.WriteCloser
+
+	// Abort discards the snapshot without committing it to the store
+	Abort() error
 }
 
 // memorySnapshotStore is an in-memory Store
 type memorySnapshotStore struct {
 	snapshots       map[raft.Index]Snapshot
 	currentSnapshot Snapshot
+	mu              sync.RWMutex
 }
 
-func (s *memorySnapshotStore) NewSnapshot(index raft.Index, timestamp time.Time) Snapshot {
-	snapshot := &memorySnapshot{
+func (s *memorySnapshotStore) NewSnapshot(index raft.Index, term raft.Term, timestamp time.Time, metadata []byte) Snapshot {
+	return &memorySnapshot{
+		store:     s,
 		index:     index,
+		term:      term,
 		timestamp: timestamp,
+		metadata:  metadata,
 		bytes:     make([]byte, 0, 1024*1024),
 	}
-	s.snapshots[index] = snapshot
-	s.currentSnapshot = snapshot
-	return snapshot
 }
 
 func (s *memorySnapshotStore) CurrentSnapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.currentSnapshot
 }
 
+// commit commits the given snapshot to the store
+func (s *memorySnapshotStore) commit(snapshot *memorySnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[snapshot.index] = snapshot
+	if s.currentSnapshot == nil || snapshot.index >= s.currentSnapshot.Index() {
+		s.currentSnapshot = snapshot
+	}
+}
+
 func (s *memorySnapshotStore) Close() error {
 	return nil
 }
 
 type memorySnapshot struct {
+	store     *memorySnapshotStore
 	index     raft.Index
+	term      raft.Term
 	timestamp time.Time
+	metadata  []byte
 	bytes     []byte
 }
 
@@ -90,17 +124,25 @@ func (s *memorySnapshot) Index() raft.Index {
 	return s.index
 }
 
+func (s *memorySnapshot) Term() raft.Term {
+	return s.term
+}
+
 func (s *memorySnapshot) Timestamp() time.Time {
 	return s.timestamp
 }
 
+func (s *memorySnapshot) Metadata() []byte {
+	return s.metadata
+}
+
 func (s *memorySnapshot) Reader() io.ReadCloser {
 	return &memoryReader{
 		reader: bytes.NewReader(s.bytes),
 	}
 }
 
-func (s *memorySnapshot) Writer() io.WriteCloser {
+func (s *memorySnapshot) Writer() Writer {
 	return &memoryWriter{
 		snapshot: s,
 		buf:      bytes.NewBuffer(s.bytes),
@@ -130,5 +172,11 @@ func (w *memoryWriter) Write(p []byte) (n int, err error) {
 
 func (w *memoryWriter) Close() error {
 	w.snapshot.bytes = w.buf.Bytes()
+	w.snapshot.store.commit(w.snapshot)
+	return nil
+}
+
+func (w *memoryWriter) Abort() error {
+	w.buf = nil
 	return nil
 }
@@ -4,8 +4,9 @@
 package snapshot
 
 import (
+	bytes "bytes"
 	fmt "fmt"
-	github_com_atomix_atomix_raft_node_pkg_atomix_raft_protocol "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	github_com_atomix_raft_replica_pkg_atomix_raft_protocol "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
 	_ "github.com/gogo/protobuf/types"
@@ -30,8 +31,12 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 // Snapshot descriptor
 type Descriptor struct {
-	Index     github_com_atomix_atomix_raft_node_pkg_atomix_raft_protocol.Index `protobuf:"varint,1,opt,name=index,proto3,casttype=github.com/atomix/raft-replica/pkg/atomix/raft/protocol.Index" json:"index,omitempty"`
-	Timestamp *time.Time                                                        `protobuf:"bytes,2,opt,name=timestamp,proto3,stdtime" json:"timestamp,omitempty"`
+	Index     github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Index `protobuf:"varint,1,opt,name=index,proto3,casttype=github.com/atomix/raft-replica/pkg/atomix/raft/protocol.Index" json:"index,omitempty"`
+	Timestamp *time.Time                                                    `protobuf:"bytes,2,opt,name=timestamp,proto3,stdtime" json:"timestamp,omitempty"`
+	Term      github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Term  `protobuf:"varint,3,opt,name=term,proto3,casttype=github.com/atomix/raft-replica/pkg/atomix/raft/protocol.Term" json:"term,omitempty"`
+	Checksum  uint32                                                        `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// metadata is opaque application-defined metadata set when the snapshot was taken
+	Metadata []byte `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *Descriptor) Reset()         { *m = Descriptor{} }
@@ -67,7 +72,7 @@ func (m *Descriptor) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Descriptor proto.InternalMessageInfo
 
-func (m *Descriptor) GetIndex() github_com_atomix_atomix_raft_node_pkg_atomix_raft_protocol.Index {
+func (m *Descriptor) GetIndex() github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Index {
 	if m != nil {
 		return m.Index
 	}
@@ -81,6 +86,27 @@ func (m *Descriptor) GetTimestamp() *time.Time {
 	return nil
 }
 
+func (m *Descriptor) GetTerm() github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Term {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *Descriptor) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *Descriptor) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Descriptor)(nil), "atomix.raft.Descriptor")
 }
@@ -90,24 +116,27 @@ func init() {
 }
 
 var fileDescriptor_c4596120fca830b6 = []byte{
-	// 264 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x4c, 0x2c, 0xc9, 0xcf,
-	0xcd, 0xac, 0xd0, 0x2f, 0x4a, 0x4c, 0x2b, 0xd1, 0x2f, 0x2e, 0xc9, 0x2f, 0x4a, 0xd5, 0x2f, 0xce,
-	0x4b, 0x2c, 0x28, 0xce, 0xc8, 0x2f, 0x81, 0x33, 0xf4, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85, 0xb8,
-	0x21, 0x4a, 0xf5, 0x40, 0x4a, 0xa5, 0xe4, 0xd3, 0xf3, 0xf3, 0xd3, 0x73, 0x52, 0xf5, 0xc1, 0x52,
-	0x49, 0xa5, 0x69, 0xfa, 0x25, 0x99, 0xb9, 0xa9, 0xc5, 0x25, 0x89, 0xb9, 0x05, 0x10, 0xd5, 0x52,
-	0x22, 0xe9, 0xf9, 0xe9, 0xf9, 0x60, 0xa6, 0x3e, 0x88, 0x05, 0x11, 0x55, 0x5a, 0xc9, 0xc8, 0xc5,
-	0xe5, 0x92, 0x5a, 0x9c, 0x5c, 0x94, 0x59, 0x50, 0x92, 0x5f, 0x24, 0x14, 0xcd, 0xc5, 0x9a, 0x99,
-	0x97, 0x92, 0x5a, 0x21, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0xe2, 0xe4, 0xfa, 0xeb, 0x9e, 0xbc, 0x63,
-	0x7a, 0x66, 0x49, 0x46, 0x69, 0x92, 0x5e, 0x72, 0x7e, 0xae, 0x3e, 0xd4, 0x6d, 0x10, 0x4a, 0x17,
-	0x64, 0xaf, 0x6e, 0x5e, 0x7e, 0x4a, 0xaa, 0x7e, 0x41, 0x76, 0xba, 0x3e, 0xb2, 0xbb, 0xc1, 0x66,
-	0x27, 0xe7, 0xe7, 0xe8, 0x79, 0x82, 0x0c, 0x0b, 0x82, 0x98, 0x29, 0x64, 0xc7, 0xc5, 0x09, 0x77,
-	0x94, 0x04, 0x93, 0x02, 0xa3, 0x06, 0xb7, 0x91, 0x94, 0x1e, 0xc4, 0xd9, 0x7a, 0x30, 0x67, 0xeb,
-	0x85, 0xc0, 0x54, 0x38, 0xb1, 0x4c, 0xb8, 0x2f, 0xcf, 0x18, 0x84, 0xd0, 0xe2, 0xa4, 0xf2, 0xe3,
-	0xa1, 0x1c, 0xe3, 0x8a, 0x47, 0x72, 0x8c, 0x3b, 0x1e, 0xc9, 0x31, 0x9e, 0x78, 0x24, 0xc7, 0x78,
-	0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7,
-	0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x12, 0x1b, 0xd8, 0x28, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0xb8, 0x75, 0xdb, 0x2e, 0x49, 0x01, 0x00, 0x00,
+	// 316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x8e, 0xb1, 0x4e, 0xeb, 0x30,
+	0x14, 0x86, 0xeb, 0xde, 0xf4, 0x0a, 0x5c, 0x58, 0x22, 0x86, 0x28, 0x83, 0x13, 0x21, 0x86, 0x30,
+	0x60, 0x4b, 0xb0, 0x02, 0x82, 0x8a, 0x85, 0x35, 0xaa, 0xc4, 0xec, 0xa6, 0x6e, 0x1a, 0xb5, 0xee,
+	0x89, 0x9c, 0x53, 0xa9, 0x8f, 0xd1, 0xc7, 0x60, 0x67, 0xe1, 0x11, 0x18, 0x3b, 0x32, 0x15, 0x48,
+	0x5f, 0x02, 0x75, 0x42, 0x89, 0xd5, 0xd0, 0x99, 0xed, 0x3f, 0xe7, 0x7c, 0xfe, 0xfd, 0xd1, 0x73,
+	0x89, 0xa0, 0xb3, 0x85, 0x30, 0x72, 0x84, 0xa2, 0x40, 0x30, 0x4a, 0x14, 0x33, 0x99, 0x17, 0x63,
+	0xc0, 0x26, 0xf0, 0xdc, 0x00, 0x82, 0xdb, 0xb5, 0x28, 0xaf, 0x50, 0x3f, 0x48, 0x01, 0xd2, 0xa9,
+	0x12, 0xf5, 0x69, 0x30, 0x1f, 0x09, 0xcc, 0xb4, 0x2a, 0x50, 0xea, 0xdc, 0xd2, 0xfe, 0x49, 0x0a,
+	0x29, 0xd4, 0x51, 0x54, 0xc9, 0x6e, 0x4f, 0x5f, 0xda, 0x94, 0x3e, 0xa8, 0x22, 0x31, 0x59, 0x8e,
+	0x60, 0xdc, 0x27, 0xda, 0xc9, 0x66, 0x43, 0xb5, 0xf0, 0x48, 0x48, 0x22, 0xa7, 0x77, 0xbf, 0x5d,
+	0x07, 0x37, 0x69, 0x86, 0xe3, 0xf9, 0x80, 0x27, 0xa0, 0xc5, 0x9e, 0xdb, 0x85, 0x51, 0xf9, 0x34,
+	0x4b, 0xa4, 0xc8, 0x27, 0xe9, 0xfe, 0xde, 0x0a, 0x24, 0x30, 0xe5, 0x8f, 0x55, 0x51, 0x6c, 0xfb,
+	0xdc, 0x5b, 0x7a, 0xd8, 0x08, 0x79, 0xed, 0x90, 0x44, 0xdd, 0x4b, 0x9f, 0x5b, 0x65, 0xbe, 0x53,
+	0xe6, 0xfd, 0x1d, 0xd1, 0x73, 0x96, 0x1f, 0x01, 0x89, 0x7f, 0x9f, 0xb8, 0x7d, 0xea, 0xa0, 0x32,
+	0xda, 0xfb, 0x57, 0x7b, 0xdd, 0x6d, 0xd7, 0xc1, 0xf5, 0x5f, 0xbd, 0xfa, 0xca, 0xe8, 0xb8, 0x6e,
+	0x73, 0x7d, 0x7a, 0x90, 0x8c, 0x55, 0x32, 0x29, 0xe6, 0xda, 0x73, 0x42, 0x12, 0x1d, 0xc7, 0xcd,
+	0x5c, 0xdd, 0xb4, 0x42, 0x39, 0x94, 0x28, 0xbd, 0x4e, 0x48, 0xa2, 0xa3, 0xb8, 0x99, 0x7b, 0x67,
+	0xdf, 0x5f, 0x8c, 0x3c, 0x97, 0x8c, 0xbc, 0x96, 0x8c, 0xbc, 0x95, 0x8c, 0xac, 0x4a, 0x46, 0x3e,
+	0x4b, 0x46, 0x96, 0x1b, 0xd6, 0x5a, 0x6d, 0x58, 0xeb, 0x7d, 0xc3, 0x5a, 0x83, 0xff, 0xf5, 0x97,
+	0x57, 0x3f, 0x03, 0x00, 0x45, 0x39, 0x3b, 0x7b, 0xd3, 0x01, 0x00, 0x00,
 }
 
 func (this *Descriptor) Equal(that interface{}) bool {
@@ -139,6 +168,15 @@ func (this *Descriptor) Equal(that interface{}) bool {
 	} else if !this.Timestamp.Equal(*that1.Timestamp) {
 		return false
 	}
+	if this.Term != that1.Term {
+		return false
+	}
+	if this.Checksum != that1.Checksum {
+		return false
+	}
+	if !bytes.Equal(this.Metadata, that1.Metadata) {
+		return false
+	}
 	return true
 }
 func (m *Descriptor) Marshal() (dAtA []byte, err error) {
@@ -161,6 +199,23 @@ func (m *Descriptor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Metadata) > 0 {
+		i -= len(m.Metadata)
+		copy(dAtA[i:], m.Metadata)
+		i = encodeVarintSnapshot(dAtA, i, uint64(len(m.Metadata)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintSnapshot(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Term != 0 {
+		i = encodeVarintSnapshot(dAtA, i, uint64(m.Term))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Timestamp != nil {
 		n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Timestamp):])
 		if err1 != nil {
@@ -192,10 +247,17 @@ func encodeVarintSnapshot(dAtA []byte, offset int, v uint64) int {
 }
 func NewPopulatedDescriptor(r randySnapshot, easy bool) *Descriptor {
 	this := &Descriptor{}
-	this.Index = github_com_atomix_atomix_raft_node_pkg_atomix_raft_protocol.Index(uint64(r.Uint32()))
+	this.Index = github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Index(uint64(r.Uint32()))
 	if r.Intn(5) != 0 {
 		this.Timestamp = github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
 	}
+	this.Term = github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Term(uint64(r.Uint32()))
+	this.Checksum = uint32(r.Uint32())
+	v1 := r.Intn(100)
+	this.Metadata = make([]byte, v1)
+	for i := 0; i < v1; i++ {
+		this.Metadata[i] = byte(r.Intn(256))
+	}
 	if !easy && r.Intn(10) != 0 {
 	}
 	return this
@@ -220,9 +282,9 @@ func randUTF8RuneSnapshot(r randySnapshot) rune {
 	return rune(ru + 61)
 }
 func randStringSnapshot(r randySnapshot) string {
-	v1 := r.Intn(100)
-	tmps := make([]rune, v1)
-	for i := 0; i < v1; i++ {
+	v2 := r.Intn(100)
+	tmps := make([]rune, v2)
+	for i := 0; i < v2; i++ {
 		tmps[i] = randUTF8RuneSnapshot(r)
 	}
 	return string(tmps)
@@ -244,11 +306,11 @@ func randFieldSnapshot(dAtA []byte, r randySnapshot, fieldNumber int, wire int)
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateSnapshot(dAtA, uint64(key))
-		v2 := r.Int63()
+		v3 := r.Int63()
 		if r.Intn(2) == 0 {
-			v2 *= -1
+			v3 *= -1
 		}
-		dAtA = encodeVarintPopulateSnapshot(dAtA, uint64(v2))
+		dAtA = encodeVarintPopulateSnapshot(dAtA, uint64(v3))
 	case 1:
 		dAtA = encodeVarintPopulateSnapshot(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -286,6 +348,16 @@ func (m *Descriptor) Size() (n int) {
 		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Timestamp)
 		n += 1 + l + sovSnapshot(uint64(l))
 	}
+	if m.Term != 0 {
+		n += 1 + sovSnapshot(uint64(m.Term))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovSnapshot(uint64(m.Checksum))
+	}
+	l = len(m.Metadata)
+	if l > 0 {
+		n += 1 + l + sovSnapshot(uint64(l))
+	}
 	return n
 }
 
@@ -338,7 +410,7 @@ func (m *Descriptor) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= github_com_atomix_atomix_raft_node_pkg_atomix_raft_protocol.Index(b&0x7F) << shift
+				m.Index |= github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Index(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -379,6 +451,78 @@ func (m *Descriptor) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			m.Term = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Term |= github_com_atomix_raft_replica_pkg_atomix_raft_protocol.Term(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metadata = append(m.Metadata[:0], dAtA[iNdEx:postIndex]...)
+			if m.Metadata == nil {
+				m.Metadata = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSnapshot(dAtA[iNdEx:])
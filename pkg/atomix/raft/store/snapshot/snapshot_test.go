@@ -26,8 +26,9 @@ func TestSnapshot(t *testing.T) {
 	assert.Nil(t, store.CurrentSnapshot())
 
 	ts := time.Now()
-	snapshot := store.NewSnapshot(raft.Index(1), ts)
+	snapshot := store.NewSnapshot(raft.Index(1), raft.Term(2), ts, nil)
 	assert.Equal(t, raft.Index(1), snapshot.Index())
+	assert.Equal(t, raft.Term(2), snapshot.Term())
 	assert.Equal(t, ts, snapshot.Timestamp())
 
 	writer := snapshot.Writer()
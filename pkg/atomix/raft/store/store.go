@@ -15,18 +15,31 @@
 package store
 
 import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/log"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/store/snapshot"
+	"sync"
 )
 
 // NewMemoryStore returns a new in-memory store
 func NewMemoryStore() Store {
-	log := log.NewMemoryLog()
+	return NewMemoryLogStore(snapshot.NewMemoryStore())
+}
+
+// NewMemoryLogStore returns a new store with an in-memory log and the given snapshot store
+func NewMemoryLogStore(snapshots snapshot.Store) Store {
+	return NewStore(log.NewMemoryLog(), snapshots)
+}
+
+// NewStore returns a new store with the given log and snapshot stores
+// The log may be any implementation of log.Log, allowing entries to be stored by alternative backends.
+func NewStore(log log.Log, snapshots snapshot.Store) Store {
 	return &store{
 		log:      log,
 		reader:   log.OpenReader(0),
 		writer:   log.Writer(),
-		snapshot: snapshot.NewMemoryStore(),
+		snapshot: snapshots,
+		terms:    log.OpenReader(0),
 	}
 }
 
@@ -44,6 +57,13 @@ type Store interface {
 	// Snapshot returns the snapshot store
 	Snapshot() snapshot.Store
 
+	// TermAt returns the term of the entry at the given index
+	// If the index is neither in the log nor the index of the current snapshot, the returned bool is false.
+	TermAt(index raft.Index) (raft.Term, bool)
+
+	// CheckDiskSpace returns ErrDiskFull if the store is too low on disk space to accept writes
+	CheckDiskSpace() error
+
 	// Close closes the store
 	Close() error
 }
@@ -54,6 +74,8 @@ type store struct {
 	reader   log.Reader
 	writer   log.Writer
 	snapshot snapshot.Store
+	terms    log.Reader
+	mu       sync.Mutex
 }
 
 func (s *store) Log() log.Log {
@@ -72,6 +94,27 @@ func (s *store) Snapshot() snapshot.Store {
 	return s.snapshot
 }
 
+func (s *store) TermAt(index raft.Index) (raft.Term, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index > 0 && index >= s.terms.FirstIndex() && index <= s.terms.LastIndex() {
+		s.terms.Reset(index)
+		if entry := s.terms.NextEntry(); entry != nil && entry.Index == index {
+			return entry.Entry.Term, true
+		}
+	}
+
+	// If the index has been compacted from the log, fall back to the snapshot metadata
+	if snapshot := s.snapshot.CurrentSnapshot(); snapshot != nil && snapshot.Index() == index {
+		return snapshot.Term(), true
+	}
+	return 0, false
+}
+
+func (s *store) CheckDiskSpace() error {
+	return nil
+}
+
 func (s *store) Close() error {
 	s.log.Close()
 	s.snapshot.Close()
@@ -0,0 +1,74 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	raft "github.com/atomix/raft-replica/pkg/atomix/raft/protocol"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTermAt(t *testing.T) {
+	store := NewMemoryStore()
+	terms := []raft.Term{1, 1, 2, 3, 3, 3, 5}
+	for _, term := range terms {
+		store.Writer().Append(&raft.LogEntry{
+			Term:      term,
+			Timestamp: time.Now(),
+			Entry: &raft.LogEntry_Initialize{
+				Initialize: &raft.InitializeEntry{},
+			},
+		})
+	}
+
+	for i, term := range terms {
+		actual, ok := store.TermAt(raft.Index(i + 1))
+		assert.True(t, ok)
+		assert.Equal(t, term, actual)
+	}
+
+	_, ok := store.TermAt(raft.Index(0))
+	assert.False(t, ok)
+	_, ok = store.TermAt(raft.Index(8))
+	assert.False(t, ok)
+
+	// Take a snapshot at index 4 and compact the log
+	snapshot := store.Snapshot().NewSnapshot(raft.Index(4), raft.Term(3), time.Now(), nil)
+	assert.NoError(t, snapshot.Writer().Close())
+	store.Writer().Compact(raft.Index(5))
+
+	_, ok = store.TermAt(raft.Index(1))
+	assert.False(t, ok)
+	_, ok = store.TermAt(raft.Index(3))
+	assert.False(t, ok)
+
+	term, ok := store.TermAt(raft.Index(4))
+	assert.True(t, ok)
+	assert.Equal(t, raft.Term(3), term)
+
+	term, ok = store.TermAt(raft.Index(5))
+	assert.True(t, ok)
+	assert.Equal(t, raft.Term(3), term)
+
+	term, ok = store.TermAt(raft.Index(7))
+	assert.True(t, ok)
+	assert.Equal(t, raft.Term(5), term)
+
+	// Truncating the tail of the log removes the terms of truncated entries
+	store.Writer().Truncate(raft.Index(6))
+	_, ok = store.TermAt(raft.Index(7))
+	assert.False(t, ok)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/atomix/go-framework/pkg/atomix/cluster"
 	"github.com/atomix/go-framework/pkg/atomix/node"
 	"github.com/atomix/go-framework/pkg/atomix/service"
+	streams "github.com/atomix/go-framework/pkg/atomix/stream"
 	"github.com/atomix/raft-replica/pkg/atomix/raft"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/client"
 	"github.com/atomix/raft-replica/pkg/atomix/raft/config"
@@ -37,47 +38,47 @@ func TestRaftNode(t *testing.T) {
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 		},
 	}
 
-	server := newServer("foo", cluster)
+	server := newServer(t, "foo", cluster)
 	go server.Start()
 	defer server.Stop()
 	_ = server.WaitForReady()
 
 	client := client.NewClient(cluster, protocol.ReadConsistency_SEQUENTIAL)
 
-	ch := make(chan node.Output)
-	assert.NoError(t, client.Write(context.Background(), newOpenSessionRequest(), ch))
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), newOpenSessionRequest(), streams.NewChannelStream(ch)))
 	out := <-ch
 	assert.True(t, out.Succeeded())
-	openSessionResponse := getOpenSessionResponse(out.Value)
+	openSessionResponse := getOpenSessionResponse(out.Value.([]byte))
 	assert.NotEqual(t, 0, openSessionResponse.SessionID)
 	sessionID := openSessionResponse.SessionID
 
-	ch = make(chan node.Output)
+	ch = make(chan streams.Result)
 	bytes, err := proto.Marshal(&SetRequest{
 		Value: "Hello world!",
 	})
 	assert.NoError(t, err)
-	assert.NoError(t, client.Write(context.Background(), newCommandRequest(sessionID, 1, "set", bytes), ch))
+	assert.NoError(t, client.Write(context.Background(), newCommandRequest(sessionID, 1, "set", bytes), streams.NewChannelStream(ch)))
 	out = <-ch
 	assert.True(t, out.Succeeded())
-	commandResponse := getCommandResponse(out.Value)
+	commandResponse := getCommandResponse(out.Value.([]byte))
 	setResponse := &SetResponse{}
 	assert.NoError(t, proto.Unmarshal(commandResponse.Output, setResponse))
 
-	ch = make(chan node.Output)
+	ch = make(chan streams.Result)
 	bytes, err = proto.Marshal(&GetRequest{})
 	assert.NoError(t, err)
-	assert.NoError(t, client.Read(context.Background(), newQueryRequest(sessionID, commandResponse.Context.Index, 1, "get", bytes), ch))
+	assert.NoError(t, client.Read(context.Background(), newQueryRequest(sessionID, commandResponse.Context.Index, 1, "get", bytes), streams.NewChannelStream(ch)))
 	out = <-ch
 	assert.True(t, out.Succeeded())
-	queryResponse := getQueryResponse(out.Value)
+	queryResponse := getQueryResponse(out.Value.([]byte))
 	getResponse := &GetResponse{}
 	assert.NoError(t, proto.Unmarshal(queryResponse.Output, getResponse))
 	assert.Equal(t, "Hello world!", getResponse.Value)
@@ -88,26 +89,26 @@ func TestRaftCluster(t *testing.T) {
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "localhost",
-				Port: 5002,
+				ID:           "bar",
+				Host:         "localhost",
+				ProtocolPort: 5002,
 			},
 			"baz": {
-				ID:   "baz",
-				Host: "localhost",
-				Port: 5003,
+				ID:           "baz",
+				Host:         "localhost",
+				ProtocolPort: 5003,
 			},
 		},
 	}
 
-	serverFoo := newServer("foo", cluster)
-	serverBar := newServer("bar", cluster)
-	serverBaz := newServer("baz", cluster)
+	serverFoo := newServer(t, "foo", cluster)
+	serverBar := newServer(t, "bar", cluster)
+	serverBaz := newServer(t, "baz", cluster)
 
 	wg := &sync.WaitGroup{}
 	wg.Add(3)
@@ -118,33 +119,33 @@ func TestRaftCluster(t *testing.T) {
 
 	client := client.NewClient(cluster, protocol.ReadConsistency_SEQUENTIAL)
 
-	ch := make(chan node.Output)
-	assert.NoError(t, client.Write(context.Background(), newOpenSessionRequest(), ch))
+	ch := make(chan streams.Result)
+	assert.NoError(t, client.Write(context.Background(), newOpenSessionRequest(), streams.NewChannelStream(ch)))
 	out := <-ch
 	assert.True(t, out.Succeeded())
-	openSessionResponse := getOpenSessionResponse(out.Value)
+	openSessionResponse := getOpenSessionResponse(out.Value.([]byte))
 	assert.NotEqual(t, 0, openSessionResponse.SessionID)
 	sessionID := openSessionResponse.SessionID
 
-	ch = make(chan node.Output)
+	ch = make(chan streams.Result)
 	bytes, err := proto.Marshal(&SetRequest{
 		Value: "Hello world!",
 	})
 	assert.NoError(t, err)
-	assert.NoError(t, client.Write(context.Background(), newCommandRequest(sessionID, 1, "set", bytes), ch))
+	assert.NoError(t, client.Write(context.Background(), newCommandRequest(sessionID, 1, "set", bytes), streams.NewChannelStream(ch)))
 	out = <-ch
 	assert.True(t, out.Succeeded())
-	commandResponse := getCommandResponse(out.Value)
+	commandResponse := getCommandResponse(out.Value.([]byte))
 	setResponse := &SetResponse{}
 	assert.NoError(t, proto.Unmarshal(commandResponse.Output, setResponse))
 
-	ch = make(chan node.Output)
+	ch = make(chan streams.Result)
 	bytes, err = proto.Marshal(&GetRequest{})
 	assert.NoError(t, err)
-	assert.NoError(t, client.Read(context.Background(), newQueryRequest(sessionID, commandResponse.Context.Index, 1, "get", bytes), ch))
+	assert.NoError(t, client.Read(context.Background(), newQueryRequest(sessionID, commandResponse.Context.Index, 1, "get", bytes), streams.NewChannelStream(ch)))
 	out = <-ch
 	assert.True(t, out.Succeeded())
-	queryResponse := getQueryResponse(out.Value)
+	queryResponse := getQueryResponse(out.Value.([]byte))
 	getResponse := &GetResponse{}
 	assert.NoError(t, proto.Unmarshal(queryResponse.Output, getResponse))
 	assert.Equal(t, "Hello world!", getResponse.Value)
@@ -161,26 +162,26 @@ func BenchmarkRaftCluster(b *testing.B) {
 		MemberID: "foo",
 		Members: map[string]cluster.Member{
 			"foo": {
-				ID:   "foo",
-				Host: "localhost",
-				Port: 5001,
+				ID:           "foo",
+				Host:         "localhost",
+				ProtocolPort: 5001,
 			},
 			"bar": {
-				ID:   "bar",
-				Host: "localhost",
-				Port: 5002,
+				ID:           "bar",
+				Host:         "localhost",
+				ProtocolPort: 5002,
 			},
 			"baz": {
-				ID:   "baz",
-				Host: "localhost",
-				Port: 5003,
+				ID:           "baz",
+				Host:         "localhost",
+				ProtocolPort: 5003,
 			},
 		},
 	}
 
-	serverFoo := newServer("foo", cluster)
-	serverBar := newServer("bar", cluster)
-	serverBaz := newServer("baz", cluster)
+	serverFoo := newServer(b, "foo", cluster)
+	serverBar := newServer(b, "bar", cluster)
+	serverBaz := newServer(b, "baz", cluster)
 
 	wg := &sync.WaitGroup{}
 	wg.Add(3)
@@ -195,11 +196,11 @@ func BenchmarkRaftCluster(b *testing.B) {
 
 	client := client.NewClient(cluster, protocol.ReadConsistency_SEQUENTIAL)
 
-	ch := make(chan node.Output)
-	assert.NoError(b, client.Write(context.Background(), newOpenSessionRequest(), ch))
+	ch := make(chan streams.Result)
+	assert.NoError(b, client.Write(context.Background(), newOpenSessionRequest(), streams.NewChannelStream(ch)))
 	out := <-ch
 	assert.True(b, out.Succeeded())
-	openSessionResponse := getOpenSessionResponse(out.Value)
+	openSessionResponse := getOpenSessionResponse(out.Value.([]byte))
 	assert.NotEqual(b, 0, openSessionResponse.SessionID)
 	sessionID := openSessionResponse.SessionID
 
@@ -212,11 +213,11 @@ func BenchmarkRaftCluster(b *testing.B) {
 			wg.Add(1)
 			go func() {
 				for commandID := range ch {
-					ch := make(chan node.Output)
+					ch := make(chan streams.Result)
 					bytes, _ := proto.Marshal(&SetRequest{
 						Value: "Hello world!",
 					})
-					_ = client.Write(context.Background(), newCommandRequest(sessionID, commandID, "set", bytes), ch)
+					_ = client.Write(context.Background(), newCommandRequest(sessionID, commandID, "set", bytes), streams.NewChannelStream(ch))
 					out = <-ch
 				}
 				wg.Done()
@@ -234,12 +235,14 @@ func BenchmarkRaftCluster(b *testing.B) {
 	})
 }
 
-func newServer(memberID string, cluster cluster.Cluster) *raft.Server {
+func newServer(t testing.TB, memberID string, cluster cluster.Cluster) *raft.Server {
 	cluster.MemberID = memberID
 	timeout := 5 * time.Second
-	return raft.NewServer(cluster, node.GetRegistry(), &config.ProtocolConfig{
+	server, err := raft.NewServer(cluster, node.GetRegistry(), &config.ProtocolConfig{
 		ElectionTimeout: &timeout,
 	})
+	assert.NoError(t, err)
+	return server
 }
 
 func startServer(server *raft.Server, wg *sync.WaitGroup) {